@@ -0,0 +1,147 @@
+package artwork
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			if x < 20 {
+				img.Set(x, y, color.White)
+			} else {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+	return img
+}
+
+func Test_detect(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want Protocol
+	}{
+		{map[string]string{"TERM": "xterm-kitty"}, Kitty},
+		{map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "iTerm.app"}, ITerm2},
+		{map[string]string{"TERM": "foot"}, Sixel},
+		{map[string]string{"TERM": "xterm-kitty", "TMUX": "/tmp/tmux"}, ASCII},
+		{map[string]string{"TERM": "xterm-256color"}, ASCII},
+	}
+	for _, tt := range tests {
+		if got := detect(func(k string) string { return tt.env[k] }); got != tt.want {
+			t.Errorf("detect(%v) = %s, want %s", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	img := testImage()
+
+	got := Render(img, 4, 2, ASCII)
+	if got != "@@  \n@@  " {
+		t.Errorf("got ascii %q", got)
+	}
+
+	for _, p := range []Protocol{Kitty, ITerm2, Sixel} {
+		got := Render(img, 4, 2, p)
+		lines := strings.Split(got, "\n")
+		if len(lines) != 2 {
+			t.Fatalf("%s: got %d lines, want 2", p, len(lines))
+		}
+		if !strings.HasPrefix(lines[0], "\x1b7") || !strings.HasSuffix(lines[0], "\x1b8    ") || lines[1] != "    " {
+			t.Errorf("%s: got %q", p, got)
+		}
+	}
+	if got := Render(img, 4, 2, Sixel); !strings.Contains(got, "\x1bPq\"1;1;40;40") {
+		t.Errorf("sixel: missing raster attributes")
+	}
+}
+
+func TestFetcher_Get(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage()); err != nil {
+		t.Fatal(err)
+	}
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	f := NewFetcher(t.TempDir())
+	for i := 0; i < 2; i++ {
+		img, err := f.Get(context.Background(), srv.URL+"/cover.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if img.Bounds().Dx() != 40 {
+			t.Errorf("got width %d", img.Bounds().Dx())
+		}
+		if _, err := f.Get(context.Background(), srv.URL+"/missing"); err != ErrNoArt {
+			t.Errorf("got err=%v, want ErrNoArt", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("got requests=%d, want 2", requests)
+	}
+	if u := f.ArtUrl(srv.URL + "/cover.png"); u != "file://"+filepath.ToSlash(f.Path(srv.URL+"/cover.png")) {
+		t.Errorf("got art url %q", u)
+	}
+	if u := f.ArtUrl(srv.URL + "/missing"); u != "" {
+		t.Errorf("got art url %q for missing art", u)
+	}
+}
+
+func Test_decodeIco(t *testing.T) {
+	var small, large bytes.Buffer
+	if err := png.Encode(&small, image.NewRGBA(image.Rect(0, 0, 16, 16))); err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(&large, testImage()); err != nil {
+		t.Fatal(err)
+	}
+	ico := []byte{0, 0, 1, 0, 2, 0}
+	off := len(ico) + 2*icoEntryLen
+	for _, data := range [][]byte{small.Bytes(), large.Bytes()} {
+		entry := make([]byte, icoEntryLen)
+		binary.LittleEndian.PutUint32(entry[8:], uint32(len(data)))
+		binary.LittleEndian.PutUint32(entry[12:], uint32(off))
+		ico = append(ico, entry...)
+		off += len(data)
+	}
+	ico = append(ico, small.Bytes()...)
+	ico = append(ico, large.Bytes()...)
+
+	img, err := decode(ico)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 40 {
+		t.Errorf("got width %d, want the largest entry", img.Bounds().Dx())
+	}
+}
+
+func Test_decodeTooLarge(t *testing.T) {
+	// a gif header declaring a 65535x65535 screen, with no image
+	b := []byte("GIF89a\xff\xff\xff\xff\x00\x00\x00")
+	if _, err := decode(b); err != errArtTooLarge {
+		t.Errorf("got err=%v, want errArtTooLarge", err)
+	}
+}
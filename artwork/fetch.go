@@ -0,0 +1,170 @@
+package artwork
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	CacheSubDir = "artwork"
+
+	coverArtUrl = "https://coverartarchive.org/release/%s/front-250"
+	maxArtBytes = 2 << 20
+	// maxArtPixels bounds the size of the decoded image, as a small file can declare a huge one.
+	maxArtPixels = 4096 * 4096
+	// maxArtAge is how long a cached image is used before it is downloaded again, as station logos change.
+	maxArtAge = 30 * 24 * time.Hour
+)
+
+var (
+	ErrNoArt       = errors.New("no artwork available")
+	errArtTooLarge = errors.New("image too large")
+)
+
+// CoverArtUrl returns the Cover Art Archive front cover of a MusicBrainz release.
+func CoverArtUrl(releaseID string) string {
+	if releaseID == "" {
+		return ""
+	}
+	return fmt.Sprintf(coverArtUrl, releaseID)
+}
+
+// Fetcher downloads images and keeps them in a cache dir, so each image is downloaded only once.
+type Fetcher struct {
+	client *http.Client
+	dir    string
+}
+
+func NewFetcher(dir string) *Fetcher {
+	if dir != "" {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			slog.With("method", "artwork.NewFetcher").Error("create cache dir", "error", err)
+			dir = ""
+		}
+	}
+	return &Fetcher{
+		client: &http.Client{Timeout: config.ApiReqTimeout},
+		dir:    dir,
+	}
+}
+
+// Path returns the cache file for the url.
+func (f *Fetcher) Path(url string) string {
+	if f.dir == "" {
+		return ""
+	}
+	sum := sha1.Sum([]byte(url))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:]))
+}
+
+// Get returns the decoded image at url, downloading it if it's not cached.
+func (f *Fetcher) Get(ctx context.Context, url string) (image.Image, error) {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return nil, ErrNoArt
+	}
+	b, err := f.load(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	img, err := decode(b)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", url, err)
+	}
+	return img, nil
+}
+
+// ArtUrl returns a file url of the cached image, for clients that display the art themselves.
+func (f *Fetcher) ArtUrl(artUrl string) string {
+	fp := f.Path(strings.TrimSpace(artUrl))
+	if fp == "" {
+		return ""
+	}
+	if fi, err := os.Stat(fp); err != nil || fi.Size() == 0 {
+		return ""
+	}
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(fp)}
+	return u.String()
+}
+
+func decode(b []byte) (image.Image, error) {
+	if isIco(b) {
+		return decodeIco(b)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	if tooLarge(cfg) {
+		return nil, errArtTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(b))
+	return img, err
+}
+
+// tooLarge reports whether the image would take too much memory decoded.
+func tooLarge(cfg image.Config) bool {
+	return cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > maxArtPixels
+}
+
+func (f *Fetcher) load(ctx context.Context, url string) ([]byte, error) {
+	fp := f.Path(url)
+	if fi, err := os.Stat(fp); err == nil && time.Since(fi.ModTime()) < maxArtAge {
+		if b, err := os.ReadFile(fp); err == nil {
+			if len(b) == 0 {
+				return nil, ErrNoArt
+			}
+			return b, nil
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		// remember missing art with an empty file
+		f.store(fp, nil)
+		return nil, ErrNoArt
+	} else if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("artwork response status %s", res.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(res.Body, maxArtBytes))
+	if err != nil {
+		return nil, err
+	}
+	f.store(fp, b)
+	return b, nil
+}
+
+func (f *Fetcher) store(fp string, b []byte) {
+	if fp == "" {
+		return
+	}
+	if err := os.WriteFile(fp, b, 0o644); err != nil {
+		slog.With("method", "artwork.Fetcher.store").Error("", "error", err)
+	}
+}
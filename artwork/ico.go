@@ -0,0 +1,55 @@
+package artwork
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/png"
+)
+
+var errIcoFormat = errors.New("unsupported ico image")
+
+const icoHeaderLen, icoEntryLen = 6, 16
+
+// isIco reports whether b starts with an ICO file header, the usual format of website favicons.
+func isIco(b []byte) bool {
+	return len(b) >= icoHeaderLen && binary.LittleEndian.Uint16(b[0:]) == 0 && binary.LittleEndian.Uint16(b[2:]) == 1
+}
+
+// decodeIco decodes the largest PNG encoded image of an ICO file.
+// BMP encoded entries are not supported.
+func decodeIco(b []byte) (image.Image, error) {
+	n := int(binary.LittleEndian.Uint16(b[4:]))
+	var best image.Image
+	bestW := 0
+	for i := 0; i < n; i++ {
+		e := icoHeaderLen + i*icoEntryLen
+		if e+icoEntryLen > len(b) {
+			break
+		}
+		size := int(binary.LittleEndian.Uint32(b[e+8:]))
+		off := int(binary.LittleEndian.Uint32(b[e+12:]))
+		if off < 0 || size <= 0 || off+size > len(b) {
+			continue
+		}
+		data := b[off : off+size]
+		if !bytes.HasPrefix(data, []byte("\x89PNG")) {
+			continue
+		}
+		if cfg, err := png.DecodeConfig(bytes.NewReader(data)); err != nil || tooLarge(cfg) {
+			continue
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			continue
+		}
+		if w := img.Bounds().Dx(); w > bestW {
+			best, bestW = img, w
+		}
+	}
+	if best == nil {
+		return nil, errIcoFormat
+	}
+	return best, nil
+}
@@ -0,0 +1,52 @@
+// Package artwork fetches cover art and station logos and renders them in the terminal.
+package artwork
+
+import (
+	"os"
+	"strings"
+)
+
+// Protocol is the way an image is drawn in the terminal.
+type Protocol uint8
+
+const (
+	ASCII Protocol = iota
+	Kitty
+	ITerm2
+	Sixel
+)
+
+var protocolNames = map[Protocol]string{
+	ASCII:  "ASCII",
+	Kitty:  "kitty",
+	ITerm2: "iTerm2",
+	Sixel:  "sixel",
+}
+
+func (p Protocol) String() string {
+	return protocolNames[p]
+}
+
+// Detect guesses the graphics protocol supported by the terminal from its environment,
+// falling back to ASCII when no graphics support is known.
+func Detect() Protocol {
+	return detect(os.Getenv)
+}
+
+func detect(getenv func(string) string) Protocol {
+	term := getenv("TERM")
+	termProgram := getenv("TERM_PROGRAM")
+	switch {
+	case getenv("TMUX") != "" || strings.HasPrefix(term, "screen"):
+		// multiplexers don't pass the graphics sequences through by default
+		return ASCII
+	case getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || term == "xterm-ghostty" || termProgram == "ghostty":
+		return Kitty
+	case termProgram == "iTerm.app" || termProgram == "WezTerm" || getenv("LC_TERMINAL") == "iTerm2":
+		return ITerm2
+	case term == "foot" || strings.HasPrefix(term, "foot-") || term == "mlterm" || strings.Contains(term, "sixel") ||
+		termProgram == "contour" || getenv("WT_SESSION") != "":
+		return Sixel
+	}
+	return ASCII
+}
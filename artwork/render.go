@@ -0,0 +1,194 @@
+package artwork
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/png"
+	"strings"
+)
+
+const (
+	// cell size in pixels assumed when scaling images for the graphics protocols
+	cellWidthPx  = 10
+	cellHeightPx = 20
+
+	kittyChunkSize = 4096
+	asciiRamp      = " .:-=+*#%@"
+)
+
+// Render draws img in a block of cols x rows terminal cells.
+// Graphics protocols draw the image over a block of blank cells, restoring the cursor afterwards,
+// so that the result can be laid out like any other text block.
+func Render(img image.Image, cols, rows int, p Protocol) string {
+	if img == nil || cols <= 0 || rows <= 0 {
+		return ""
+	}
+	var seq string
+	switch p {
+	case Kitty:
+		seq = kitty(resize(img, cols*cellWidthPx, rows*cellHeightPx), cols, rows)
+	case ITerm2:
+		seq = iterm2(resize(img, cols*cellWidthPx, rows*cellHeightPx), cols, rows)
+	case Sixel:
+		seq = sixel(resize(img, cols*cellWidthPx, rows*cellHeightPx))
+	default:
+		return ascii(img, cols, rows)
+	}
+	if seq == "" {
+		return ascii(img, cols, rows)
+	}
+
+	blank := strings.Repeat(" ", cols)
+	lines := make([]string, rows)
+	for i := range lines {
+		lines[i] = blank
+	}
+	lines[0] = "\x1b7" + seq + "\x1b8" + blank
+	return strings.Join(lines, "\n")
+}
+
+// ClearKitty removes the images previously drawn with the kitty protocol.
+func ClearKitty() string {
+	return "\x1b_Ga=d,q=2\x1b\\"
+}
+
+func kitty(img image.Image, cols, rows int) string {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return ""
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+	var b strings.Builder
+	for i := 0; i < len(data); i += kittyChunkSize {
+		end := min(len(data), i+kittyChunkSize)
+		more := 0
+		if end < len(data) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,c=%d,r=%d,C=1,q=2,m=%d;%s\x1b\\", cols, rows, more, data[i:end])
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, data[i:end])
+		}
+	}
+	return b.String()
+}
+
+func iterm2(img image.Image, cols, rows int) string {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return ""
+	}
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+		buf.Len(), cols, rows, base64.StdEncoding.EncodeToString(buf.Bytes()))
+}
+
+func sixel(img image.Image) string {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	pal := image.NewPaletted(image.Rect(0, 0, w, h), palette.WebSafe)
+	draw.FloydSteinberg.Draw(pal, pal.Bounds(), img, bounds.Min)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\x1bPq\"1;1;%d;%d", w, h)
+	for i, c := range pal.Palette {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+	}
+	row := make([]byte, w)
+	for y := 0; y < h; y += 6 {
+		used := make(map[uint8]bool)
+		for dy := 0; dy < 6 && y+dy < h; dy++ {
+			for x := 0; x < w; x++ {
+				used[pal.ColorIndexAt(x, y+dy)] = true
+			}
+		}
+		first := true
+		for ci := range pal.Palette {
+			if !used[uint8(ci)] {
+				continue
+			}
+			for x := 0; x < w; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && y+dy < h; dy++ {
+					if pal.ColorIndexAt(x, y+dy) == uint8(ci) {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = 63 + bits
+			}
+			if !first {
+				b.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&b, "#%d", ci)
+			writeSixelRLE(&b, row)
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+func writeSixelRLE(b *strings.Builder, row []byte) {
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(b, "!%d%c", n, row[i])
+		} else {
+			for k := 0; k < n; k++ {
+				b.WriteByte(row[i])
+			}
+		}
+		i = j
+	}
+}
+
+// ascii draws the image with characters of increasing density; a character cell is about twice as high as wide.
+func ascii(img image.Image, cols, rows int) string {
+	small := resize(img, cols, rows)
+	var b strings.Builder
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			g := color.GrayModel.Convert(small.At(x, y)).(color.Gray)
+			b.WriteByte(asciiRamp[int(g.Y)*(len(asciiRamp)-1)/255])
+		}
+		if y < rows-1 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// resize scales img to w x h by averaging the source pixels covered by each destination pixel.
+func resize(img image.Image, w, h int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	sb := img.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+	if sw == 0 || sh == 0 {
+		return dst
+	}
+	for y := 0; y < h; y++ {
+		y0, y1 := sb.Min.Y+y*sh/h, sb.Min.Y+max((y+1)*sh/h, y*sh/h+1)
+		for x := 0; x < w; x++ {
+			x0, x1 := sb.Min.X+x*sw/w, sb.Min.X+max((x+1)*sw/w, x*sw/w+1)
+			var r, g, bl, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, bl, a, n = r+cr, g+cg, bl+cb, a+ca, n+1
+				}
+			}
+			dst.Set(x, y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(bl / n), uint16(a / n)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,221 @@
+// Package audiosink lists the Bluetooth audio sinks of PulseAudio or PipeWire and moves the playing streams to them.
+// It uses pactl, which PipeWire supports with pipewire-pulse.
+package audiosink
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	pactlBin = "pactl"
+	// bluezPrefix starts the names of the Bluetooth sinks, e.g. bluez_output.XX_XX_XX_XX_XX_XX.1 with PipeWire
+	bluezPrefix = "bluez_"
+)
+
+// Sink is an audio output device.
+type Sink struct {
+	Name        string
+	Description string
+	// ActivePort is the port of the sound card played on, e.g. analog-output-headphones
+	ActivePort string
+}
+
+// Available tells if pactl is installed.
+func Available() bool {
+	_, err := exec.LookPath(pactlBin)
+	return err == nil || errors.Is(err, exec.ErrDot)
+}
+
+// BluetoothSinks returns the sinks of the connected Bluetooth audio devices.
+func BluetoothSinks() ([]Sink, error) {
+	out, err := pactl("list", "sinks")
+	if err != nil {
+		return nil, err
+	}
+	var sinks []Sink
+	for _, s := range parseSinks(out) {
+		if strings.HasPrefix(s.Name, bluezPrefix) {
+			sinks = append(sinks, s)
+		}
+	}
+	return sinks, nil
+}
+
+// DefaultSink returns the sink that new streams play on.
+func DefaultSink() (Sink, error) {
+	out, err := pactl("info")
+	if err != nil {
+		return Sink{}, err
+	}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		if v, ok := strings.CutPrefix(sc.Text(), "Default Sink: "); ok {
+			return Sink{Name: v, Description: v}, nil
+		}
+	}
+	return Sink{}, errors.New("no default sink")
+}
+
+// MoveStreams moves the streams played by the children of the process pid to the sink,
+// and returns how many were moved.
+func MoveStreams(sink Sink, pid int) (int, error) {
+	log := slog.With("method", "audiosink.MoveStreams")
+	out, err := pactl("list", "sink-inputs")
+	if err != nil {
+		return 0, err
+	}
+	var moved int
+	for _, in := range parseSinkInputs(out) {
+		if !descendant(in.pid, pid) {
+			continue
+		}
+		if _, err := pactl("move-sink-input", strconv.Itoa(in.index), sink.Name); err != nil {
+			return moved, err
+		}
+		log.Info("moved", "sinkInput", in.index, "pid", in.pid, "sink", sink.Name)
+		moved++
+	}
+	return moved, nil
+}
+
+func pactl(args ...string) ([]byte, error) {
+	cmd := pactlCommand(context.Background(), args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("pactl %s: %v %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+func pactlCommand(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, pactlBin, args...)
+	if errors.Is(cmd.Err, exec.ErrDot) {
+		cmd.Err = nil
+	}
+	// the output is parsed, so it must not be translated
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	return cmd
+}
+
+// subscribe passes the lines of pactl subscribe, one per event of the server, to the event func until
+// ctx is done.
+func subscribe(ctx context.Context, event func(string)) error {
+	cmd := pactlCommand(ctx, "subscribe")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	sc := bufio.NewScanner(stdout)
+	for sc.Scan() {
+		event(sc.Text())
+	}
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// parseSinks reads the output of pactl list sinks.
+func parseSinks(out []byte) []Sink {
+	var sinks []Sink
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case strings.HasPrefix(line, "Sink #"):
+			sinks = append(sinks, Sink{})
+		case len(sinks) == 0:
+		case strings.HasPrefix(line, "Name: "):
+			sinks[len(sinks)-1].Name = strings.TrimPrefix(line, "Name: ")
+		case strings.HasPrefix(line, "Description: "):
+			sinks[len(sinks)-1].Description = strings.TrimPrefix(line, "Description: ")
+		case strings.HasPrefix(line, "Active Port: "):
+			sinks[len(sinks)-1].ActivePort = strings.TrimPrefix(line, "Active Port: ")
+		}
+	}
+	return sinks
+}
+
+type sinkInput struct {
+	index int
+	pid   int
+	// role is the media.role of the stream, e.g. music, event for the notifications or phone for the calls
+	role string
+}
+
+// parseSinkInputs reads the output of pactl list sink-inputs.
+func parseSinkInputs(out []byte) []sinkInput {
+	var inputs []sinkInput
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if v, ok := strings.CutPrefix(line, "Sink Input #"); ok {
+			idx, err := strconv.Atoi(v)
+			if err != nil {
+				continue
+			}
+			inputs = append(inputs, sinkInput{index: idx})
+			continue
+		}
+		if len(inputs) == 0 {
+			continue
+		}
+		if v, ok := strings.CutPrefix(line, "application.process.id = "); ok {
+			inputs[len(inputs)-1].pid, _ = strconv.Atoi(strings.Trim(v, `"`))
+		}
+		if v, ok := strings.CutPrefix(line, "media.role = "); ok {
+			inputs[len(inputs)-1].role = strings.Trim(v, `"`)
+		}
+	}
+	return inputs
+}
+
+// procDir is replaced in tests.
+var procDir = "/proc"
+
+// descendant tells if the process pid is a child of the process ancestor, or of one of its children.
+func descendant(pid, ancestor int) bool {
+	for range 32 {
+		if pid <= 1 {
+			return false
+		}
+		b, err := os.ReadFile(filepath.Join(procDir, strconv.Itoa(pid), "stat"))
+		if err != nil {
+			return false
+		}
+		// the command name in parentheses may contain spaces, the parent id is the second field after it
+		i := bytes.LastIndexByte(b, ')')
+		if i < 0 {
+			return false
+		}
+		fields := strings.Fields(string(b[i+1:]))
+		if len(fields) < 2 {
+			return false
+		}
+		ppid, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return false
+		}
+		if ppid == ancestor {
+			return true
+		}
+		pid = ppid
+	}
+	return false
+}
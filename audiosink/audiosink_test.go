@@ -0,0 +1,140 @@
+package audiosink
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const sinksOutput = `Sink #50
+	State: RUNNING
+	Name: alsa_output.pci-0000_00_1f.3.analog-stereo
+	Description: Built-in Audio Analog Stereo
+	Driver: PipeWire
+Sink #71
+	State: SUSPENDED
+	Name: bluez_output.00_1B_66_AA_BB_CC.1
+	Description: WH-1000XM4
+	Driver: PipeWire
+	Properties:
+		device.description = "WH-1000XM4"
+`
+
+const sinkInputsOutput = `Sink Input #88
+	Driver: PipeWire
+	Sink: 50
+	Properties:
+		application.name = "mpv"
+		application.process.id = "4242"
+Sink Input #90
+	Driver: PipeWire
+	Sink: 50
+	Properties:
+		application.name = "Firefox"
+		application.process.id = "1000"
+`
+
+func TestParse(t *testing.T) {
+	sinks := parseSinks([]byte(sinksOutput))
+	want := []Sink{
+		{Name: "alsa_output.pci-0000_00_1f.3.analog-stereo", Description: "Built-in Audio Analog Stereo"},
+		{Name: "bluez_output.00_1B_66_AA_BB_CC.1", Description: "WH-1000XM4"},
+	}
+	if !slices.Equal(sinks, want) {
+		t.Errorf("sinks %+v", sinks)
+	}
+
+	inputs := parseSinkInputs([]byte(sinkInputsOutput))
+	wantInputs := []sinkInput{{index: 88, pid: 4242}, {index: 90, pid: 1000}}
+	if !slices.Equal(inputs, wantInputs) {
+		t.Errorf("sink inputs %+v", inputs)
+	}
+}
+
+func TestDescendant(t *testing.T) {
+	procDir = t.TempDir()
+	defer func() { procDir = "/proc" }()
+	stat := map[string]string{
+		"4242": "4242 (mpv (x)) S 4200 4242 1",
+		"4200": "4200 (sonicradio) S 1 4200 1",
+		"1000": "1000 (firefox) S 1 1000 1",
+	}
+	for pid, s := range stat {
+		if err := os.MkdirAll(filepath.Join(procDir, pid), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(procDir, pid, "stat"), []byte(s), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !descendant(4242, 4200) {
+		t.Error("player is a child of the app")
+	}
+	if descendant(1000, 4200) {
+		t.Error("firefox is not a child of the app")
+	}
+}
+
+func TestOutput(t *testing.T) {
+	out := `Sink #50
+	Name: alsa_output.pci-0000_00_1f.3.analog-stereo
+	Description: Built-in Audio Analog Stereo
+	Active Port: analog-output-headphones
+`
+	sinks := parseSinks([]byte(out))
+	if len(sinks) != 1 || sinks[0].ActivePort != "analog-output-headphones" {
+		t.Fatalf("sinks %+v", sinks)
+	}
+	tests := map[Output]bool{
+		{Sink: sinks[0].Name, Port: sinks[0].ActivePort}:                               true,
+		{Sink: sinks[0].Name, Port: "analog-output-speaker"}:                           false,
+		{Sink: "bluez_output.00_1B_66_AA_BB_CC.1", Port: "headset-output"}:             true,
+		{Sink: "alsa_output.usb-Headset.analog-stereo", Port: "analog-output-headset"}: true,
+	}
+	for o, want := range tests {
+		if o.Headphones() != want {
+			t.Errorf("%+v headphones %v, want %v", o, !want, want)
+		}
+	}
+
+	events := map[string]bool{
+		"Event 'change' on server #4294967295": true,
+		"Event 'change' on sink #50":           true,
+		"Event 'remove' on sink #71":           true,
+		"Event 'new' on sink-input #88":        false,
+		"Event 'change' on source #51":         false,
+	}
+	for line, want := range events {
+		if outputEvent(line) != want {
+			t.Errorf("outputEvent(%q) = %v", line, !want)
+		}
+	}
+}
+
+func TestInterrupted(t *testing.T) {
+	out := `Sink Input #88
+	Properties:
+		application.name = "mpv"
+		media.role = "music"
+		application.process.id = "4242"
+Sink Input #92
+	Properties:
+		application.name = "libcanberra"
+		media.role = "event"
+`
+	inputs := parseSinkInputs([]byte(out))
+	if len(inputs) != 2 || inputs[0].role != "music" || inputs[1].role != "event" {
+		t.Fatalf("inputs %+v", inputs)
+	}
+	if !interrupted(inputs) {
+		t.Error("expected the notification to interrupt")
+	}
+	if interrupted(inputs[:1]) {
+		t.Error("expected the music alone not to interrupt")
+	}
+	if !interrupted([]sinkInput{{role: "Communication"}}) {
+		t.Error("expected the PipeWire call to interrupt")
+	}
+}
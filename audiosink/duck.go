@@ -0,0 +1,44 @@
+package audiosink
+
+import (
+	"context"
+	"strings"
+)
+
+// interruptionRoles are the media roles of the streams the radio is lowered for: the notifications,
+// as event with PulseAudio and Notification with PipeWire, and the calls.
+var interruptionRoles = []string{"event", "notification", "phone", "communication"}
+
+// WatchInterruptions passes true to the active func when another app starts playing a notification or a
+// call, false when none is left, until ctx is done. It follows the streams with pactl subscribe.
+func WatchInterruptions(ctx context.Context, active func(bool)) error {
+	var prev bool
+	check := func() {
+		out, err := pactl("list", "sink-inputs")
+		if err != nil {
+			return
+		}
+		if cur := interrupted(parseSinkInputs(out)); cur != prev {
+			active(cur)
+			prev = cur
+		}
+	}
+	check()
+	return subscribe(ctx, func(event string) {
+		if strings.Contains(event, " on sink-input #") {
+			check()
+		}
+	})
+}
+
+// interrupted tells if one of the streams is a notification or a call.
+func interrupted(inputs []sinkInput) bool {
+	for _, in := range inputs {
+		for _, r := range interruptionRoles {
+			if strings.EqualFold(in.role, r) {
+				return true
+			}
+		}
+	}
+	return false
+}
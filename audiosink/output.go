@@ -0,0 +1,65 @@
+package audiosink
+
+import (
+	"context"
+	"strings"
+)
+
+// Output is the default sink the streams play on, with its active port.
+type Output struct {
+	Sink string
+	Port string
+}
+
+// Headphones tells if the output is headphones: a Bluetooth device, or the headphones or headset port
+// of a sound card.
+func (o Output) Headphones() bool {
+	port := strings.ToLower(o.Port)
+	return strings.HasPrefix(o.Sink, bluezPrefix) || strings.Contains(port, "headphone") || strings.Contains(port, "headset")
+}
+
+// CurrentOutput returns the default sink and its active port.
+func CurrentOutput() (Output, error) {
+	def, err := DefaultSink()
+	if err != nil {
+		return Output{}, err
+	}
+	out, err := pactl("list", "sinks")
+	if err != nil {
+		return Output{}, err
+	}
+	o := Output{Sink: def.Name}
+	for _, s := range parseSinks(out) {
+		if s.Name == def.Name {
+			o.Port = s.ActivePort
+		}
+	}
+	return o, nil
+}
+
+// WatchOutput calls changed with the previous and the current output each time the default sink or its
+// active port changes, e.g. when the headphones are unplugged, until ctx is done. It follows the events
+// of pactl subscribe.
+func WatchOutput(ctx context.Context, changed func(prev, cur Output)) error {
+	prev, err := CurrentOutput()
+	if err != nil {
+		return err
+	}
+	return subscribe(ctx, func(event string) {
+		if !outputEvent(event) {
+			return
+		}
+		cur, err := CurrentOutput()
+		if err != nil || cur == prev {
+			return
+		}
+		changed(prev, cur)
+		prev = cur
+	})
+}
+
+// outputEvent tells if the line of pactl subscribe may change the output: a change of the server, like
+// its default sink, or of a sink, like its active port.
+func outputEvent(line string) bool {
+	return strings.Contains(line, " on server #") || strings.Contains(line, " on sink #")
+}
@@ -0,0 +1,138 @@
+package audiosink
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	pulseServerEnv    = "PULSE_SERVER"
+	pipewireRemoteEnv = "PIPEWIRE_REMOTE"
+	// pulseSocket is the PulseAudio socket in a runtime dir, also served by PipeWire with pipewire-pulse
+	pulseSocket = "pulse/native"
+	// pipewireSocket is the native PipeWire socket in a runtime dir
+	pipewireSocket = "pipewire-0"
+	// pulsePort is the default port of a PulseAudio server over TCP
+	pulsePort = "4713"
+)
+
+// runtimeDirs are the dirs the sockets of the host are looked up in, besides XDG_RUNTIME_DIR: a container
+// mounting the runtime dir of the host user under its own path, or the socket of a system-wide server.
+// They are replaced in tests.
+var runtimeDirs = []string{"/run/user/*", "/run"}
+
+// Server is the PulseAudio or PipeWire socket the players send their audio to.
+type Server struct {
+	// Path is the socket path, or the host:port of a PulseAudio server over TCP
+	Path     string
+	PipeWire bool
+	TCP      bool
+}
+
+func (s Server) String() string {
+	switch {
+	case s.PipeWire:
+		return "pipewire:" + s.Path
+	case s.TCP:
+		return "tcp:" + s.Path
+	}
+	return "unix:" + s.Path
+}
+
+// Setup returns the audio server of the players, the socket path configured or the one found, and
+// points the players to it through PULSE_SERVER or PIPEWIRE_REMOTE when it's not found by default, e.g.
+// the socket of the host mounted into a container. A PULSE_SERVER already set is kept.
+func Setup(path string) (Server, error) {
+	log := slog.With("method", "audiosink.Setup")
+	if path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return Server{}, fmt.Errorf("audio server: %w", err)
+		}
+		s := Server{Path: path, PipeWire: filepath.Base(path) == pipewireSocket}
+		use(s)
+		log.Info("configured", "server", s)
+		return s, nil
+	}
+	if env := os.Getenv(pulseServerEnv); env != "" {
+		return parsePulseServer(env), nil
+	}
+
+	s, ok := Detect()
+	if !ok {
+		return Server{}, errors.New("no audio server found")
+	}
+	// the players find the sockets of the runtime dir by themselves
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" || (s.Path != filepath.Join(dir, pulseSocket) && s.Path != filepath.Join(dir, pipewireSocket)) {
+		use(s)
+	}
+	log.Info("found", "server", s)
+	return s, nil
+}
+
+// Detect looks up the PulseAudio socket, then the PipeWire one, in XDG_RUNTIME_DIR and the runtimeDirs.
+func Detect() (Server, bool) {
+	var dirs []string
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	for _, pattern := range runtimeDirs {
+		matches, _ := filepath.Glob(pattern)
+		dirs = append(dirs, matches...)
+	}
+	for _, socket := range []string{pulseSocket, pipewireSocket} {
+		for _, dir := range dirs {
+			p := filepath.Join(dir, socket)
+			if fi, err := os.Stat(p); err == nil && fi.Mode().Type() == os.ModeSocket {
+				return Server{Path: p, PipeWire: socket == pipewireSocket}, true
+			}
+		}
+	}
+	return Server{}, false
+}
+
+// parsePulseServer reads the first server of PULSE_SERVER: unix:/path, /path, tcp:host:port or host.
+func parsePulseServer(env string) Server {
+	addr, _, _ := strings.Cut(strings.TrimSpace(env), " ")
+	if p, ok := strings.CutPrefix(addr, "unix:"); ok {
+		return Server{Path: p}
+	}
+	if strings.HasPrefix(addr, "/") {
+		return Server{Path: addr}
+	}
+	addr = strings.TrimPrefix(strings.TrimPrefix(addr, "tcp6:"), "tcp:")
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, pulsePort)
+	}
+	return Server{Path: addr, TCP: true}
+}
+
+func use(s Server) {
+	if s.PipeWire {
+		os.Setenv(pipewireRemoteEnv, s.Path)
+		return
+	}
+	os.Setenv(pulseServerEnv, "unix:"+s.Path)
+}
+
+// Reachable tells if the audio server accepts connections.
+func (s Server) Reachable() error {
+	if s.Path == "" {
+		return errors.New("no audio server")
+	}
+	network := "unix"
+	if s.TCP {
+		network = "tcp"
+	}
+	conn, err := net.DialTimeout(network, s.Path, time.Second)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
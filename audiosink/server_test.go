@@ -0,0 +1,81 @@
+package audiosink
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func listenSocket(t *testing.T, path string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+}
+
+func TestSetup(t *testing.T) {
+	// short, as unix socket paths are limited to about 100 bytes
+	dir, err := os.MkdirTemp("", "sra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	runtimeDirs = []string{filepath.Join(dir, "user", "*")}
+	t.Cleanup(func() { runtimeDirs = []string{"/run/user/*", "/run"} })
+	t.Setenv("XDG_RUNTIME_DIR", filepath.Join(dir, "xdg"))
+	t.Setenv(pulseServerEnv, "")
+	os.Unsetenv(pulseServerEnv)
+	t.Setenv(pipewireRemoteEnv, "")
+	os.Unsetenv(pipewireRemoteEnv)
+
+	if _, err := Setup(""); err == nil {
+		t.Error("expected no audio server found")
+	}
+
+	pipewire := filepath.Join(dir, "user", "1000", pipewireSocket)
+	listenSocket(t, pipewire)
+	s, err := Setup("")
+	if err != nil || s != (Server{Path: pipewire, PipeWire: true}) {
+		t.Fatalf("got server %+v, error %v", s, err)
+	}
+	if got := os.Getenv(pipewireRemoteEnv); got != pipewire {
+		t.Errorf("got %s=%q", pipewireRemoteEnv, got)
+	}
+
+	pulse := filepath.Join(dir, "user", "1000", pulseSocket)
+	listenSocket(t, pulse)
+	if s, _ := Detect(); s != (Server{Path: pulse}) {
+		t.Errorf("got server %+v, want the PulseAudio socket first", s)
+	}
+	if err := s.Reachable(); err != nil {
+		t.Errorf("server not reachable: %v", err)
+	}
+
+	if _, err := Setup(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected an error for a missing socket")
+	}
+	s, err = Setup(pulse)
+	if err != nil || s.String() != "unix:"+pulse || os.Getenv(pulseServerEnv) != "unix:"+pulse {
+		t.Errorf("got server %v, error %v, %s=%q", s, err, pulseServerEnv, os.Getenv(pulseServerEnv))
+	}
+}
+
+func TestParsePulseServer(t *testing.T) {
+	tests := map[string]Server{
+		"unix:/run/pulse/native":   {Path: "/run/pulse/native"},
+		"/tmp/pulse.sock":          {Path: "/tmp/pulse.sock"},
+		"tcp:10.0.0.2:4714":        {Path: "10.0.0.2:4714", TCP: true},
+		"host.docker.internal":     {Path: "host.docker.internal:4713", TCP: true},
+		"unix:/a/native tcp:other": {Path: "/a/native"},
+	}
+	for env, want := range tests {
+		if got := parsePulseServer(env); got != want {
+			t.Errorf("parsePulseServer(%q) = %+v, want %+v", env, got, want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Package banner draws text in large letters, readable from across the room: a 5x7 dot font drawn with
+// half blocks, two dots per line.
+package banner
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// Height is the number of lines of the banners
+	Height = (glyphRows + 1) / 2
+
+	glyphRows  = 7
+	glyphWidth = 5
+	// glyphGap is the blank between the letters
+	glyphGap = 1
+	// missing is drawn for the characters the font doesn't have
+	missing = '?'
+)
+
+// Blocks are the blank, the upper half, the lower half and the full blocks the dots are drawn with.
+type Blocks [4]string
+
+var (
+	HalfBlocks = Blocks{" ", "▀", "▄", "█"}
+	// ASCIIBlocks are for the terminals whose fonts lack the blocks
+	ASCIIBlocks = Blocks{" ", "\"", ",", "#"}
+)
+
+// Render returns the Height lines of the text in large letters, upper case, with the letters not fitting
+// in width cut.
+func Render(text string, width int, b Blocks) []string {
+	var glyphs [][glyphRows]string
+	for _, r := range text {
+		if (len(glyphs)+1)*(glyphWidth+glyphGap)-glyphGap > width {
+			break
+		}
+		glyphs = append(glyphs, glyph(r))
+	}
+
+	lines := make([]string, Height)
+	for i := range lines {
+		var line strings.Builder
+		for j, g := range glyphs {
+			if j > 0 {
+				line.WriteString(strings.Repeat(b[0], glyphGap))
+			}
+			top, bottom := g[2*i], strings.Repeat(".", glyphWidth)
+			if 2*i+1 < glyphRows {
+				bottom = g[2*i+1]
+			}
+			for k := range glyphWidth {
+				idx := 0
+				if top[k] == '#' {
+					idx |= 1
+				}
+				if bottom[k] == '#' {
+					idx |= 2
+				}
+				line.WriteString(b[idx])
+			}
+		}
+		lines[i] = line.String()
+	}
+	return lines
+}
+
+func glyph(r rune) [glyphRows]string {
+	r = unicode.ToUpper(r)
+	if f, ok := folded[r]; ok {
+		r = f
+	}
+	if unicode.IsSpace(r) {
+		r = ' '
+	}
+	if g, ok := font[r]; ok {
+		return g
+	}
+	return font[missing]
+}
@@ -0,0 +1,57 @@
+package banner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFont(t *testing.T) {
+	for r, g := range font {
+		for _, row := range g {
+			if len(row) != glyphWidth || strings.Trim(row, ".#") != "" {
+				t.Errorf("glyph %q: invalid row %q", r, row)
+			}
+		}
+	}
+	for from, to := range folded {
+		if _, ok := font[to]; !ok {
+			t.Errorf("%q folded to %q, missing from the font", from, to)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	got := Render("Hi!", 80, HalfBlocks)
+	want := []string{
+		"█   █  ▀█▀    █  ",
+		"█▄▄▄█   █     █  ",
+		"█   █   █        ",
+		"▀   ▀  ▀▀▀    ▀  ",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if got := Render("hi!", 80, HalfBlocks); strings.Join(got, "") != strings.Join(want, "") {
+		t.Error("expected the lower case drawn upper case")
+	}
+	if got := Render("Ḩ", 80, HalfBlocks); got[0] != Render("?", 80, HalfBlocks)[0] {
+		t.Error("expected the missing characters drawn as ?")
+	}
+	if got := Render("Ștefan", 80, ASCIIBlocks); got[0] != Render("Stefan", 80, ASCIIBlocks)[0] {
+		t.Error("expected the accented letters drawn as their base letters")
+	}
+}
+
+func TestRender_cut(t *testing.T) {
+	for _, tt := range []struct{ width, letters int }{{4, 0}, {5, 1}, {10, 1}, {11, 2}, {17, 3}} {
+		lines := Render("radio", tt.width, HalfBlocks)
+		if len(lines) != Height {
+			t.Fatalf("got %d lines, want %d", len(lines), Height)
+		}
+		want := max(0, tt.letters*(glyphWidth+glyphGap)-glyphGap)
+		if n := len([]rune(lines[0])); n != want {
+			t.Errorf("width %d: got %d cells, want %d letters", tt.width, n, tt.letters)
+		}
+	}
+}
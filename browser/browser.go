@@ -3,7 +3,6 @@ package browser
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -11,29 +10,59 @@ import (
 	"math/rand/v2"
 	"net"
 	"net/http"
+	"net/url"
+	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/perf"
 )
 
 const (
 	HOST              = "all.api.radio-browser.info"
+	apiDomain         = ".api.radio-browser.info"
 	backup_server     = "https://de1.api.radio-browser.info/json/servers"
 	serverMaxRetry    = 5
 	serverRetryMillis = 200
 	voteTimeout       = 10 * time.Minute
+	// homepage is sent in the User-Agent, for the server admins to know where the requests come from
+	homepage = "https://github.com/dancnb/sonicradio"
 )
 
-var ErrServerMsg = errors.New("Server response not available")
+var (
+	ErrServerMsg    = errors.New("Server response not available")
+	ErrLocalResults = errors.New("Server response not available, showing locally indexed stations")
+	errTooManyReq   = errors.New("too many requests")
+)
 
 func NewApi(ctx context.Context, cfg *config.Value) (*Api, error) {
 	api := Api{
+		client:        newHttpClient(),
+		limiter:       newRateLimiter(cfg.GetApiRateLimit()),
+		strict:        config.StrictApi(),
 		cfg:           cfg,
 		stationsCache: make(map[string][]Station),
 		stationVotes:  make(map[string]time.Time),
 	}
+	indexPath, httpCachePath := "", ""
+	if cacheDir, err := config.GetOrCreateCacheDir(); err == nil {
+		indexPath = filepath.Join(cacheDir, IndexFilename)
+		httpCachePath = filepath.Join(cacheDir, HttpCacheFilename)
+	} else {
+		slog.Error("browser cache dir", "error", err)
+	}
+	api.index = NewStationIndex(indexPath)
+	api.httpCache = newHttpCache(httpCachePath)
+
+	if cfg.ApiUrl != "" {
+		slog.Info("browser server: " + cfg.ApiUrl)
+		api.servers = []string{strings.TrimSuffix(cfg.ApiUrl, "/")}
+		return &api, nil
+	}
+
 	res, err := api.getServersDNSLookup(ctx, HOST)
 	if err != nil {
 		msg := fmt.Errorf("could not perform DNS lookup for %q: %w", HOST, err)
@@ -46,6 +75,9 @@ func NewApi(ctx context.Context, cfg *config.Value) (*Api, error) {
 	}
 	slog.Info("browser servers: " + strings.Join(res, "; "))
 	api.servers = append(api.servers, res...)
+	if len(api.servers) > 0 {
+		api.serverIdx.Store(int32(rand.IntN(len(api.servers))))
+	}
 
 	if len(api.servers) == 0 {
 		return nil, ErrServerMsg
@@ -53,16 +85,44 @@ func NewApi(ctx context.Context, cfg *config.Value) (*Api, error) {
 	return &api, nil
 }
 
+// NewApiWithServers returns the api sending the requests to the servers, without looking them up,
+// e.g. to a fake directory in the tests. Nothing is cached on disk.
+func NewApiWithServers(cfg *config.Value, servers ...string) *Api {
+	return &Api{
+		client:        newHttpClient(),
+		limiter:       newRateLimiter(cfg.GetApiRateLimit()),
+		strict:        config.StrictApi(),
+		cfg:           cfg,
+		servers:       servers,
+		stationsCache: make(map[string][]Station),
+		stationVotes:  make(map[string]time.Time),
+		index:         NewStationIndex(""),
+		httpCache:     newHttpCache(""),
+	}
+}
+
 type Api struct {
-	cfg       *config.Value
+	client  *http.Client
+	limiter *rateLimiter
+	// strict logs the responses not matching the expected schema
+	strict bool
+	cfg    *config.Value
+
+	// base URLs of the API servers, the one at serverIdx is used until a request to it fails
 	servers   []string
+	serverIdx atomic.Int32
+
 	countries []Country
 	langs     []Language
+	tags      []StationTag
 
 	stationsMtx   sync.Mutex
 	stationsCache map[string][]Station
 
 	stationVotes map[string]time.Time
+
+	index     *StationIndex
+	httpCache *httpCache
 }
 
 func (a *Api) GetLanguages() ([]Language, error) {
@@ -71,14 +131,14 @@ func (a *Api) GetLanguages() ([]Language, error) {
 	}
 	log := slog.With("method", "Api.GetLanguages")
 	for i := 0; i < serverMaxRetry; i++ {
-		res, err := a.doServerRequest(http.MethodGet, urlLangs, nil)
+		res, err := a.doCachedServerRequest(context.Background(), urlLangs)
 		if err != nil {
 			log.Error("", "request error", err)
 			time.Sleep(serverRetryMillis * time.Millisecond)
 			continue
 		}
 		var languages []Language
-		err = json.Unmarshal(res, &languages)
+		err = a.decode(res, &languages)
 		if err != nil {
 			log.Error("", "unmarshal error", err)
 			log.Error("", "response", string(res))
@@ -99,14 +159,14 @@ func (a *Api) GetCountries() ([]Country, error) {
 	}
 	log := slog.With("method", "Api.GetCountries")
 	for i := 0; i < serverMaxRetry; i++ {
-		res, err := a.doServerRequest(http.MethodGet, urlCountries, nil)
+		res, err := a.doCachedServerRequest(context.Background(), urlCountries)
 		if err != nil {
 			log.Error("", "request error", err)
 			time.Sleep(serverRetryMillis * time.Millisecond)
 			continue
 		}
 		var countries []Country
-		err = json.Unmarshal(res, &countries)
+		err = a.decode(res, &countries)
 		if err != nil {
 			log.Error("", "unmarshal error", err)
 			log.Error("", "response", string(res))
@@ -122,15 +182,72 @@ func (a *Api) GetCountries() ([]Country, error) {
 }
 
 func (a *Api) Search(s SearchParams) ([]Station, error) {
-	return a.stationSearch(s)
+	return a.stationSearch(context.Background(), s)
+}
+
+// SearchCtx is like Search, but stops retrying and aborts the in-flight request when ctx is done.
+func (a *Api) SearchCtx(ctx context.Context, s SearchParams) ([]Station, error) {
+	return a.stationSearch(ctx, s)
 }
 
+// TopStations uses a GET request, unlike the other searches, so that it can be answered from the http cache.
 func (a *Api) TopStations() ([]Station, error) {
+	log := slog.With("method", "Api.TopStations")
 	s := DefaultSearchParams()
-	return a.stationSearch(s)
+	path := urlStations + "?" + s.toFormData()
+	for i := 0; i < serverMaxRetry; i++ {
+		res, err := a.doCachedServerRequest(context.Background(), path)
+		if err != nil {
+			log.Error("", "request error", err)
+			time.Sleep(serverRetryMillis * time.Millisecond)
+			continue
+		}
+		var stations []Station
+		err = a.decode(res, &stations)
+		if err != nil {
+			log.Error("", "unmarshal error", err)
+			time.Sleep(serverRetryMillis * time.Millisecond)
+			continue
+		}
+		log.Info("", "length", len(stations))
+		a.index.Add(stations)
+		return stations, nil
+	}
+	log.Warn("exceeded max retries")
+	if local := a.LocalSearch(s); len(local) > 0 {
+		return local, ErrLocalResults
+	}
+	return nil, ErrServerMsg
 }
 
-func (a *Api) stationSearch(s SearchParams) ([]Station, error) {
+func (a *Api) GetTags() ([]StationTag, error) {
+	if len(a.tags) > 0 {
+		return a.tags, nil
+	}
+	log := slog.With("method", "Api.GetTags")
+	for i := 0; i < serverMaxRetry; i++ {
+		res, err := a.doCachedServerRequest(context.Background(), urlTags)
+		if err != nil {
+			log.Error("", "request error", err)
+			time.Sleep(serverRetryMillis * time.Millisecond)
+			continue
+		}
+		var tags []StationTag
+		err = a.decode(res, &tags)
+		if err != nil {
+			log.Error("", "unmarshal error", err)
+			time.Sleep(serverRetryMillis * time.Millisecond)
+			continue
+		}
+		log.Info("", "length", len(tags))
+		a.tags = tags
+		return tags, nil
+	}
+	log.Warn("exceeded max retries")
+	return nil, ErrServerMsg
+}
+
+func (a *Api) stationSearch(ctx context.Context, s SearchParams) ([]Station, error) {
 	body := s.toFormData()
 	log := slog.With("method", "Api.stationSearch")
 	log.Info("", "request", body)
@@ -146,15 +263,22 @@ func (a *Api) stationSearch(s SearchParams) ([]Station, error) {
 
 	var err error
 	for i := 0; i < serverMaxRetry; i++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		var res []byte
-		res, err = a.doServerRequest(http.MethodPost, urlStations, []byte(body))
+		res, err = a.doServerRequest(ctx, http.MethodPost, urlStations, []byte(body))
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			// cancelled by a newer search, not worth retrying
+			return nil, ctxErr
+		}
 		if err != nil {
 			log.Error("", "request error", err)
 			time.Sleep(serverRetryMillis * time.Millisecond)
 			continue
 		}
 		var stations []Station
-		err = json.Unmarshal(res, &stations)
+		err = a.decode(res, &stations)
 		if err != nil {
 			log.Error("", "unmarshal error", err)
 			log.Error("", "response", string(res))
@@ -162,6 +286,7 @@ func (a *Api) stationSearch(s SearchParams) ([]Station, error) {
 			continue
 		}
 		log.Info("", "length", len(stations))
+		a.index.Add(stations)
 		if len(stations) > 0 {
 			a.stationsMtx.Lock()
 			a.stationsCache[body] = stations
@@ -171,9 +296,23 @@ func (a *Api) stationSearch(s SearchParams) ([]Station, error) {
 		return stations, nil
 	}
 	log.Warn("exceeded max retries")
+	if local := a.LocalSearch(s); len(local) > 0 {
+		return local, ErrLocalResults
+	}
 	return nil, ErrServerMsg
 }
 
+// LocalSearch looks up the search terms in the index of previously received stations, without any request.
+func (a *Api) LocalSearch(s SearchParams) []Station {
+	query := strings.Join([]string{s.Name, strings.ReplaceAll(s.TagList, ",", " "), s.Country, s.CountryCode, s.Language}, " ")
+	return a.index.Search(query, s.Limit)
+}
+
+// SaveCache persists the local station index and the cached directory data.
+func (a *Api) SaveCache() error {
+	return errors.Join(a.index.Save(), a.httpCache.save())
+}
+
 func (a *Api) GetStations(uuids []string) ([]Station, error) {
 	if len(uuids) == 0 {
 		return nil, nil
@@ -189,14 +328,14 @@ func (a *Api) GetStations(uuids []string) ([]Station, error) {
 	}
 	x := reqBody.String()
 	for i := 0; i < serverMaxRetry; i++ {
-		res, err := a.doServerRequest(http.MethodPost, urlStationsByUUID, []byte(x))
+		res, err := a.doServerRequest(context.Background(), http.MethodPost, urlStationsByUUID, []byte(x))
 		if err != nil {
 			log.Error("", "request error", err)
 			time.Sleep(serverRetryMillis * time.Millisecond)
 			continue
 		}
 		var stations []Station
-		err = json.Unmarshal(res, &stations)
+		err = a.decode(res, &stations)
 		if err != nil {
 			log.Error("", "unmarshal error", err)
 			log.Error("", "response", string(res))
@@ -204,6 +343,7 @@ func (a *Api) GetStations(uuids []string) ([]Station, error) {
 			continue
 		}
 		log.Info("", "length", len(stations))
+		a.index.Add(stations)
 		return stations, nil
 	}
 
@@ -211,10 +351,84 @@ func (a *Api) GetStations(uuids []string) ([]Station, error) {
 	return nil, ErrServerMsg
 }
 
+// StationsByUrl returns the stations with the stream url, to find the stations saved by other players.
+func (a *Api) StationsByUrl(ctx context.Context, streamUrl string) ([]Station, error) {
+	log := slog.With("method", "Api.StationsByUrl")
+	body := "url=" + url.QueryEscape(streamUrl)
+	for i := 0; i < serverMaxRetry; i++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		res, err := a.doServerRequest(ctx, http.MethodPost, urlStationsByUrl, []byte(body))
+		if err != nil {
+			log.Error("", "request error", err)
+			time.Sleep(serverRetryMillis * time.Millisecond)
+			continue
+		}
+		var stations []Station
+		if err := a.decode(res, &stations); err != nil {
+			log.Error("", "unmarshal error", err)
+			time.Sleep(serverRetryMillis * time.Millisecond)
+			continue
+		}
+		a.index.Add(stations)
+		return stations, nil
+	}
+	log.Warn("exceeded max retries")
+	return nil, ErrServerMsg
+}
+
+// LowestBitrateVariant returns the station listed with the same name and homepage at the lowest bitrate,
+// or s if there's none lower.
+func (a *Api) LowestBitrateVariant(ctx context.Context, s Station) Station {
+	params := DefaultSearchParams()
+	params.Name = s.Name
+	params.Order = Bitrate
+	params.Reverse = false
+	candidates, err := a.stationSearch(ctx, params)
+	if err != nil && !errors.Is(err, ErrLocalResults) {
+		slog.With("method", "Api.LowestBitrateVariant").Info("", "error", err)
+		return s
+	}
+	return lowestBitrateVariant(s, candidates)
+}
+
+func lowestBitrateVariant(s Station, candidates []Station) Station {
+	res := s
+	for _, c := range candidates {
+		if !sameStation(s, c) || c.Bitrate <= 0 {
+			continue
+		}
+		if res.Bitrate <= 0 || c.Bitrate < res.Bitrate {
+			res = c
+		}
+	}
+	return res
+}
+
+// sameStation tells if the stations are streams of the same broadcaster, listed separately for each bitrate or codec.
+func sameStation(s, c Station) bool {
+	if !strings.EqualFold(strings.TrimSpace(s.Name), strings.TrimSpace(c.Name)) {
+		return false
+	}
+	if s.Homepage == "" || c.Homepage == "" {
+		return true
+	}
+	return homepageHost(s.Homepage) == homepageHost(c.Homepage)
+}
+
+func homepageHost(homepage string) string {
+	u, err := url.Parse(strings.TrimSpace(homepage))
+	if err != nil {
+		return homepage
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
 func (a *Api) StationCounter(uuid string) error {
 	log := slog.With("method", "Api.StationCounter")
 	url := urlClickCount + uuid
-	res, err := a.doServerRequest(http.MethodPost, url, nil)
+	res, err := a.doServerRequest(context.Background(), http.MethodPost, url, nil)
 	if err != nil {
 		log.Error("", "request error", err)
 		return err
@@ -239,7 +453,7 @@ func (a *Api) StationVote(uuid string) error {
 	a.stationVotes[uuid] = time.Now()
 
 	url := urlVote + uuid
-	res, err := a.doServerRequest(http.MethodPost, url, nil)
+	res, err := a.doServerRequest(context.Background(), http.MethodPost, url, nil)
 	if err != nil {
 		log.Error("", "request error", err)
 		return errVoteReq
@@ -249,7 +463,7 @@ func (a *Api) StationVote(uuid string) error {
 		Ok      bool
 		Message string
 	}
-	err = json.Unmarshal(res, &voteRes)
+	err = a.decode(res, &voteRes)
 	if err != nil {
 		return errVoteReq
 	} else if strings.Contains(voteRes.Message, "you are voting for the same station too often") {
@@ -258,13 +472,20 @@ func (a *Api) StationVote(uuid string) error {
 	return nil
 }
 
-func (a *Api) doServerRequest(method string, path string, body []byte) ([]byte, error) {
-	ix := rand.IntN(len(a.servers))
-	ip := a.servers[ix]
-	url := fmt.Sprintf("http://%s%s", ip, path)
-	return a.doRequest(method, url, body)
+func (a *Api) doServerRequest(ctx context.Context, method string, path string, body []byte) ([]byte, error) {
+	ix := int(a.serverIdx.Load())
+	url := a.servers[ix] + path
+	res, err := a.doRequest(ctx, method, url, body)
+	if err != nil && ctx.Err() == nil && len(a.servers) > 1 {
+		// keep reusing the connection to the same server, unless it stops responding
+		a.serverIdx.CompareAndSwap(int32(ix), int32((ix+1)%len(a.servers)))
+	}
+	return res, err
 }
 
+// getServersDNSLookup returns the base URLs of the servers behind host.
+// Reverse DNS gives the server names, which allow using https (and HTTP/2).
+// The plain IP with http is used for the servers without a name.
 func (a *Api) getServersDNSLookup(ctx context.Context, host string) ([]string, error) {
 	ips, err := net.DefaultResolver.LookupIP(ctx, "ip4", host)
 	if err != nil {
@@ -272,53 +493,68 @@ func (a *Api) getServersDNSLookup(ctx context.Context, host string) ([]string, e
 	}
 	var res []string
 	for _, v := range ips {
-		res = append(res, v.String())
+		names, err := net.DefaultResolver.LookupAddr(ctx, v.String())
+		if err == nil && len(names) > 0 && strings.HasSuffix(strings.TrimSuffix(names[0], "."), apiDomain) {
+			res = append(res, "https://"+strings.TrimSuffix(names[0], "."))
+			continue
+		}
+		res = append(res, "http://"+v.String())
 	}
 	return res, nil
 }
 
 func (a *Api) getServerMirrors() ([]string, error) {
-	res, err := a.doRequest(http.MethodGet, backup_server, nil)
+	res, err := a.doRequest(context.Background(), http.MethodGet, backup_server, nil)
 	if err != nil {
 		return nil, err
 	}
 	var srv []ServerMirror
-	err = json.Unmarshal(res, &srv)
+	err = a.decode(res, &srv)
 	if err != nil {
 		return nil, err
 	}
-	var ips []string
+	var servers []string
 	for _, server := range srv {
 		ipVal := net.ParseIP(server.IP)
-		if ipVal != nil && ipVal.To4() != nil {
-			ips = append(ips, server.IP)
+		if ipVal == nil || ipVal.To4() == nil {
+			continue
+		}
+		if strings.HasSuffix(server.Name, apiDomain) {
+			servers = append(servers, "https://"+server.Name)
+		} else {
+			servers = append(servers, "http://"+server.IP)
 		}
 	}
 
-	return ips, err
+	return servers, err
 }
 
-func (a *Api) doRequest(method string, url string, body []byte) ([]byte, error) {
+func (a *Api) doRequest(ctx context.Context, method string, url string, body []byte) ([]byte, error) {
 	log := slog.With("method", "Api.doRequest")
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.ApiReqTimeout)
+	ctx, cancel := context.WithTimeout(ctx, config.ApiReqTimeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	req, err := a.newRequest(ctx, method, url, body)
 	if err != nil {
 		log.Error("create browser request", slog.String("error", err.Error()))
 		return nil, err
 	}
-	ua := fmt.Sprintf("sonicradio/%s", a.cfg.Version)
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("User-Agent", ua)
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	res, err := http.DefaultClient.Do(req)
+	if err := a.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+	defer perf.Since(perf.API, time.Now())
+	res, err := a.client.Do(req)
 	if err != nil {
 		log.Error("do browser request", slog.String("error", err.Error()))
 		return nil, err
 	}
 	defer res.Body.Close()
+	if res.StatusCode == http.StatusTooManyRequests {
+		log.Warn("rate limited", "url", url, "retryAfter", res.Header.Get("Retry-After"))
+		a.limiter.pause(res)
+		return nil, errTooManyReq
+	}
 
 	b, err := io.ReadAll(res.Body)
 	if err != nil {
@@ -327,3 +563,15 @@ func (a *Api) doRequest(method string, url string, body []byte) ([]byte, error)
 	}
 	return b, nil
 }
+
+func (a *Api) newRequest(ctx context.Context, method string, url string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	ua := fmt.Sprintf("sonicradio/%s (+%s)", a.cfg.Version, homepage)
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("User-Agent", ua)
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
@@ -2,7 +2,10 @@ package browser
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -128,3 +131,49 @@ func TestApi_StationVote(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func Test_lowestBitrateVariant(t *testing.T) {
+	s := Station{Stationuuid: "1", Name: "Radio X", Homepage: "https://www.radiox.example/", Bitrate: 320}
+	candidates := []Station{
+		{Stationuuid: "2", Name: "Radio X Classics", Homepage: "https://radiox.example", Bitrate: 32},
+		{Stationuuid: "3", Name: "radio x", Homepage: "http://radiox.example/live", Bitrate: 64},
+		{Stationuuid: "4", Name: "Radio X", Homepage: "https://other.example", Bitrate: 48},
+		{Stationuuid: "5", Name: "Radio X", Bitrate: 0},
+		s,
+	}
+	if res := lowestBitrateVariant(s, candidates); res.Stationuuid != "3" {
+		t.Errorf("variant %s, want 3", res.Stationuuid)
+	}
+	if res := lowestBitrateVariant(s, nil); res.Stationuuid != "1" {
+		t.Errorf("variant %s without candidates, want 1", res.Stationuuid)
+	}
+}
+
+func TestApi_SearchCtxCancel(t *testing.T) {
+	started := make(chan struct{}, serverMaxRetry)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+	a := NewApiWithServers(&config.Value{ApiRateLimit: 1000}, srv.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	start := time.Now()
+	_, err := a.SearchCtx(ctx, DefaultSearchParams())
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got err=%v, want context.Canceled", err)
+	}
+	if d := time.Since(start); d >= serverRetryMillis*time.Millisecond {
+		t.Errorf("returned after %s, want no retry after the cancel", d)
+	}
+	if n := len(started); n != 0 {
+		t.Errorf("got %d more requests, want none after the cancel", n)
+	}
+}
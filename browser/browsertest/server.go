@@ -0,0 +1,27 @@
+// Package browsertest provides a fake radio-browser server, to drive the app in the tests without the network.
+package browsertest
+
+import (
+	"net/http/httptest"
+
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/browser/fakedir"
+	"github.com/dancnb/sonicradio/config"
+)
+
+// Server serves the fake directory listing its stations, with the clicks and the votes counted.
+type Server struct {
+	*httptest.Server
+	*fakedir.Directory
+}
+
+// NewServer starts the server listing the stations, to be closed by the caller.
+func NewServer(stations ...browser.Station) *Server {
+	dir := fakedir.New(stations...)
+	return &Server{Server: httptest.NewServer(dir), Directory: dir}
+}
+
+// Api returns the api sending its requests to the server.
+func (s *Server) Api(cfg *config.Value) *browser.Api {
+	return browser.NewApiWithServers(cfg, s.URL)
+}
@@ -0,0 +1,68 @@
+package browsertest
+
+import (
+	"testing"
+
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+)
+
+var testStations = []browser.Station{
+	{Stationuuid: "uuid-1", Name: "Jazz FM", Tags: "jazz,smooth jazz", Country: "Germany", Countrycode: "DE", Language: "german", Votes: 30},
+	{Stationuuid: "uuid-2", Name: "Rock Radio", Tags: "rock", Country: "France", Countrycode: "FR", Language: "french", Votes: 20},
+	{Stationuuid: "uuid-3", Name: "Jazz Lounge", Tags: "jazz,lounge", Country: "France", Countrycode: "FR", Language: "french", Votes: 10},
+}
+
+func TestServer(t *testing.T) {
+	srv := NewServer(testStations...)
+	defer srv.Close()
+	api := srv.Api(&config.Value{Version: "test", ApiRateLimit: 1000})
+
+	params := browser.DefaultSearchParams()
+	params.TagList = "jazz"
+	got, err := api.Search(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Stationuuid != "uuid-1" || got[1].Stationuuid != "uuid-3" {
+		t.Errorf("search by tag got %v, want uuid-1 and uuid-3 by votes", got)
+	}
+
+	params = browser.DefaultSearchParams()
+	params.CountryCode = "FR"
+	params.Offset, params.Limit = 1, 1
+	got, err = api.Search(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Stationuuid != "uuid-3" {
+		t.Errorf("second page of France got %v, want uuid-3", got)
+	}
+
+	got, err = api.GetStations([]string{"uuid-2", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "Rock Radio" {
+		t.Errorf("by uuid got %v, want Rock Radio", got)
+	}
+
+	if err := api.StationCounter("uuid-2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := api.StationVote("uuid-2"); err != nil {
+		t.Fatal(err)
+	}
+	if srv.Clicks("uuid-2") != 1 || srv.Votes("uuid-2") != 1 {
+		t.Errorf("got clicks=%d votes=%d, want 1 and 1", srv.Clicks("uuid-2"), srv.Votes("uuid-2"))
+	}
+
+	countries, err := api.GetCountries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []browser.Country{{Name: "France", ISO3166_1: "FR", Stationcount: 2}, {Name: "Germany", ISO3166_1: "DE", Stationcount: 1}}
+	if len(countries) != 2 || countries[0] != want[0] || countries[1] != want[1] {
+		t.Errorf("countries got %v, want %v", countries, want)
+	}
+}
@@ -0,0 +1,35 @@
+package browser
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	dialTimeout         = 5 * time.Second
+	keepAlive           = 30 * time.Second
+	maxIdleConns        = 16
+	maxIdleConnsPerHost = 4
+	idleConnTimeout     = 90 * time.Second
+)
+
+// newHttpClient returns a client that keeps connections to the API servers alive between requests.
+// Responses are transparently requested and decoded as gzip by the transport,
+// and HTTP/2 is negotiated for https servers.
+func newHttpClient() *http.Client {
+	t := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   dialTimeout,
+			KeepAlive: keepAlive,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          maxIdleConns,
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   dialTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+	return &http.Client{Transport: t}
+}
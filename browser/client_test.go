@@ -0,0 +1,53 @@
+package browser
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+func Test_newHttpClient(t *testing.T) {
+	const body = `[{"name":"Germany","iso_3166_1":"DE","stationcount":10}]`
+	var protos []string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		protos = append(protos, r.Proto)
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("missing gzip Accept-Encoding, got %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(body))
+		_ = gz.Close()
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	client := newHttpClient()
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	client.Transport.(*http.Transport).TLSClientConfig = &tls.Config{RootCAs: pool}
+
+	a := &Api{client: client, cfg: &config.Value{}, servers: []string{srv.URL}}
+	for i := 0; i < 2; i++ {
+		res, err := a.doServerRequest(context.Background(), http.MethodGet, urlCountries, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(res) != body {
+			t.Errorf("got body=%s, want %s", res, body)
+		}
+	}
+	for _, p := range protos {
+		if p != "HTTP/2.0" {
+			t.Errorf("got proto=%s, want HTTP/2.0", p)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package browser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxExactFloat bounds the converted integers, to the ones a float64 holds exactly.
+const maxExactFloat = 1 << 53
+
+// mismatchFunc is told about a field of a response not matching the expected schema, e.g. `Station.bitrate`.
+type mismatchFunc func(field, problem string)
+
+// decode unmarshals the API response into v, a pointer to a struct or a slice of structs.
+// The servers don't all send the same JSON types, e.g. a number as a string, so a value of another type
+// than expected is converted to the type of its field instead of failing the whole response.
+// The unknown fields are ignored. In strict mode, the mismatches with the schema are logged.
+func (a *Api) decode(data []byte, v any) error {
+	t := reflect.TypeOf(v).Elem()
+	var report mismatchFunc
+	if a.strict {
+		report = logMismatch
+	} else {
+		err := json.Unmarshal(data, v)
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			return err
+		}
+		slog.Info("converting the mismatched types", "method", "Api.decode", "error", err)
+	}
+	norm, err := normalize(data, t, "response", report)
+	if err != nil {
+		return err
+	}
+	reflect.ValueOf(v).Elem().SetZero()
+	return json.Unmarshal(norm, v)
+}
+
+// reportedMismatches keeps the mismatches logged, once per field and problem.
+var reportedMismatches sync.Map
+
+func logMismatch(field, problem string) {
+	if _, ok := reportedMismatches.LoadOrStore(field+" "+problem, true); ok {
+		return
+	}
+	slog.Warn("api schema mismatch", "method", "Api.decode", "field", field, "problem", problem)
+}
+
+// normalize converts the values of data to the JSON types of the fields of t they are decoded into.
+// The unknown fields and the converted values are reported when report is set.
+func normalize(data []byte, t reflect.Type, path string, report mismatchFunc) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || string(data) == "null" {
+		return data, nil
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return normalize(data, t.Elem(), path, report)
+
+	case reflect.Slice:
+		if data[0] != '[' {
+			break
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		for i := range items {
+			v, err := normalize(items[i], t.Elem(), path, report)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = v
+		}
+		return json.Marshal(items)
+
+	case reflect.Struct:
+		if data[0] != '{' {
+			break
+		}
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+		fields := jsonFields(t)
+		for name, raw := range values {
+			f, ok := fields[strings.ToLower(name)]
+			if !ok {
+				if report != nil {
+					report(t.Name()+"."+name, "unknown field")
+				}
+				continue
+			}
+			v, err := normalize(raw, f.Type, t.Name()+"."+name, report)
+			if err != nil {
+				return nil, err
+			}
+			values[name] = v
+		}
+		return json.Marshal(values)
+
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, ok := convertScalar(data, t.Kind())
+		if !ok && report != nil {
+			report(path, fmt.Sprintf("%s for %s", data, t.Kind()))
+		}
+		return v, nil
+
+	default:
+		return data, nil
+	}
+	if report != nil {
+		report(path, fmt.Sprintf("%.20s for %s", data, t.Kind()))
+	}
+	return []byte("null"), nil
+}
+
+// convertScalar converts the JSON value to the JSON type of kind, or to its zero value when it has no meaning
+// for kind, e.g. a word for a number. It tells if the value already had the right type.
+func convertScalar(data []byte, kind reflect.Kind) ([]byte, bool) {
+	text := string(data)
+	quoted := data[0] == '"'
+	if quoted {
+		if kind == reflect.String {
+			return data, true
+		}
+		if err := json.Unmarshal(data, &text); err != nil {
+			return []byte("null"), false
+		}
+		text = strings.TrimSpace(text)
+	}
+
+	switch kind {
+	case reflect.String:
+		b, _ := json.Marshal(text)
+		return b, false
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(text); err == nil {
+			return []byte(strconv.FormatBool(b)), !quoted && (text == "true" || text == "false")
+		}
+		return []byte(strconv.FormatBool(toNumber(text) != 0)), false
+	case reflect.Float32, reflect.Float64:
+		if _, err := strconv.ParseFloat(text, 64); err == nil && !quoted {
+			return data, true
+		}
+		return []byte(strconv.FormatFloat(toNumber(text), 'g', -1, 64)), false
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if _, err := strconv.ParseUint(text, 10, 64); err == nil {
+			return []byte(text), !quoted
+		}
+		return []byte(strconv.FormatUint(uint64(max(0, min(toNumber(text), maxExactFloat))), 10)), false
+	default:
+		if _, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return []byte(text), !quoted
+		}
+		return []byte(strconv.FormatInt(int64(max(-maxExactFloat, min(toNumber(text), maxExactFloat))), 10)), false
+	}
+}
+
+// toNumber is the number of a JSON number, boolean or string, 0 when it isn't one.
+func toNumber(text string) float64 {
+	if text == "true" {
+		return 1
+	}
+	n, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0
+	}
+	return n
+}
+
+// jsonFieldsCache has the fields of the struct types by their lower case JSON names.
+var jsonFieldsCache sync.Map
+
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	if v, ok := jsonFieldsCache.Load(t); ok {
+		return v.(map[string]reflect.StructField)
+	}
+	res := make(map[string]reflect.StructField)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		res[strings.ToLower(name)] = f
+	}
+	jsonFieldsCache.Store(t, res)
+	return res
+}
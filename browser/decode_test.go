@@ -0,0 +1,154 @@
+package browser
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func Test_decode(t *testing.T) {
+	b := readFixture(t, "stations.json")
+	var want []Station
+	if err := json.Unmarshal(b, &want); err != nil {
+		t.Fatal(err)
+	}
+	for _, strict := range []bool{false, true} {
+		var got []Station
+		if err := (&Api{strict: strict}).decode(b, &got); err != nil {
+			t.Fatalf("strict=%v: %v", strict, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("strict=%v: got %+v, want %+v", strict, got, want)
+		}
+	}
+}
+
+func Test_decodeVariant(t *testing.T) {
+	b := readFixture(t, "stations_variant.json")
+	if err := json.Unmarshal(b, &[]Station{}); err == nil {
+		t.Fatal("expected the fixture to fail the plain decoding")
+	}
+	for _, strict := range []bool{false, true} {
+		var got []Station
+		if err := (&Api{strict: strict}).decode(b, &got); err != nil {
+			t.Fatalf("strict=%v: %v", strict, err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("strict=%v: got %d stations, want 2", strict, len(got))
+		}
+		s := got[0]
+		if s.Stationuuid != "9617a958-0601-11e8-ae97-52543be04c81" || s.Name != "Radio Paradise Main Mix" {
+			t.Errorf("strict=%v: got station %s %q", strict, s.Stationuuid, s.Name)
+		}
+		if s.State != "42" || s.Language != "" {
+			t.Errorf("strict=%v: got state=%q language=%q, want 42 and empty", strict, s.State, s.Language)
+		}
+		if s.Votes != 8412 || s.Bitrate != 320 || s.HLS != 0 || s.Lastcheckok != 1 {
+			t.Errorf("strict=%v: got votes=%d bitrate=%d hls=%d lastcheckok=%d", strict, s.Votes, s.Bitrate, s.HLS, s.Lastcheckok)
+		}
+		if s.Clickcount != 1514 || s.Clicktrend != -12 || s.SSLError != 0 || !s.HasExtendedInfo {
+			t.Errorf("strict=%v: got clickcount=%d clicktrend=%d ssl_error=%d extended=%v", strict, s.Clickcount, s.Clicktrend, s.SSLError, s.HasExtendedInfo)
+		}
+		if s.GeoLat != "39.76" {
+			t.Errorf("strict=%v: got geo_lat=%v, kept as sent", strict, s.GeoLat)
+		}
+		s = got[1]
+		if s.Name != "1077" || s.Votes != 3 || s.Bitrate != 0 || !s.HasExtendedInfo {
+			t.Errorf("strict=%v: got name=%q votes=%d bitrate=%d extended=%v", strict, s.Name, s.Votes, s.Bitrate, s.HasExtendedInfo)
+		}
+	}
+}
+
+func Test_decodeCountries(t *testing.T) {
+	var got []Country
+	if err := (&Api{}).decode(readFixture(t, "countries_variant.json"), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []Country{
+		{Name: "Germany", ISO3166_1: "DE", Stationcount: 3012},
+		{Name: "France", ISO3166_1: "FR", Stationcount: 2110},
+		{Name: "Japan", ISO3166_1: "JP"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func Test_normalizeMismatches(t *testing.T) {
+	var got []string
+	report := func(field, problem string) { got = append(got, field+": "+problem) }
+
+	if _, err := normalize(readFixture(t, "stations.json"), reflect.TypeOf([]Station{}), "response", report); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) > 0 {
+		t.Errorf("got mismatches for the expected schema: %v", got)
+	}
+
+	if _, err := normalize(readFixture(t, "stations_variant.json"), reflect.TypeOf([]Station{}), "response", report); err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(got)
+	want := []string{
+		`Station.bitrate: 320.0 for int64`,
+		`Station.bitrate: {"min": 64} for int64`,
+		`Station.clickcount: " 1514 " for int64`,
+		`Station.clicktrend: "-12" for int64`,
+		`Station.geo_distance: unknown field`,
+		`Station.has_extended_info: "true" for bool`,
+		`Station.has_extended_info: 1 for bool`,
+		`Station.hls: "" for int64`,
+		`Station.is_official: unknown field`,
+		`Station.lastcheckok: true for int64`,
+		`Station.name: 1077 for string`,
+		`Station.ssl_error: "n/a" for int64`,
+		`Station.state: 42 for string`,
+		`Station.votes: "8412" for int64`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got mismatches\n%v\nwant\n%v", got, want)
+	}
+}
+
+func Test_convertScalar(t *testing.T) {
+	tests := []struct {
+		data string
+		kind reflect.Kind
+		want string
+		ok   bool
+	}{
+		{`"abc"`, reflect.String, `"abc"`, true},
+		{`12`, reflect.String, `"12"`, false},
+		{`false`, reflect.String, `"false"`, false},
+		{`7`, reflect.Int64, `7`, true},
+		{`"7"`, reflect.Int64, `7`, false},
+		{`7.9`, reflect.Int64, `7`, false},
+		{`"1e3"`, reflect.Int, `1000`, false},
+		{`"-"`, reflect.Int, `0`, false},
+		{`-3`, reflect.Uint, `0`, false},
+		{`2.5`, reflect.Float64, `2.5`, true},
+		{`"2.5"`, reflect.Float64, `2.5`, false},
+		{`true`, reflect.Bool, `true`, true},
+		{`"yes"`, reflect.Bool, `false`, false},
+		{`0`, reflect.Bool, `false`, false},
+		{`"1"`, reflect.Bool, `true`, false},
+	}
+	for _, tt := range tests {
+		got, ok := convertScalar([]byte(tt.data), tt.kind)
+		if string(got) != tt.want || ok != tt.ok {
+			t.Errorf("convertScalar(%s, %s)=%s, %v, want %s, %v", tt.data, tt.kind, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// Package fakedir serves a fake radio-browser directory, for the tests and the demo mode.
+package fakedir
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dancnb/sonicradio/browser"
+)
+
+// Directory is a fake radio-browser API listing its stations. It searches them by name, tags, country, state
+// and language, with the orders and the paging of the real one, and counts the clicks and the votes.
+type Directory struct {
+	mux *http.ServeMux
+
+	mtx      sync.Mutex
+	stations []browser.Station
+	clicks   map[string]int
+	votes    map[string]int
+	searches int
+}
+
+// New returns the directory listing the stations.
+func New(stations ...browser.Station) *Directory {
+	d := &Directory{
+		stations: slices.Clone(stations),
+		clicks:   make(map[string]int),
+		votes:    make(map[string]int),
+	}
+	d.mux = http.NewServeMux()
+	d.mux.HandleFunc("/json/stations/search", d.search)
+	d.mux.HandleFunc("/json/stations/byuuid", d.byUuid)
+	d.mux.HandleFunc("/json/stations/byurl", d.byUrl)
+	d.mux.HandleFunc("/json/url/{uuid}", d.click)
+	d.mux.HandleFunc("/json/vote/{uuid}", d.vote)
+	d.mux.HandleFunc("/json/countries", d.countries)
+	d.mux.HandleFunc("/json/languages", d.languages)
+	d.mux.HandleFunc("/json/tags", d.tags)
+	return d
+}
+
+func (d *Directory) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mux.ServeHTTP(w, r)
+}
+
+// SetStations replaces the stations listed.
+func (d *Directory) SetStations(stations ...browser.Station) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.stations = slices.Clone(stations)
+}
+
+// Clicks returns how many times the station was counted as played.
+func (d *Directory) Clicks(uuid string) int {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.clicks[uuid]
+}
+
+// Votes returns the votes received by the station.
+func (d *Directory) Votes(uuid string) int {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.votes[uuid]
+}
+
+// Searches returns how many station searches were received, the top stations included.
+func (d *Directory) Searches() int {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.searches
+}
+
+func (d *Directory) search(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f := r.Form
+	d.mtx.Lock()
+	d.searches++
+	var res []browser.Station
+	for _, st := range d.stations {
+		if matchSearch(st, f.Get("name"), f.Get("tagList"), f.Get("country"), f.Get("countrycode"), f.Get("state"), f.Get("language")) {
+			res = append(res, st)
+		}
+	}
+	d.mtx.Unlock()
+
+	sortStations(res, f.Get("order"), f.Get("reverse") == "true")
+	offset, _ := strconv.Atoi(f.Get("offset"))
+	res = res[min(max(0, offset), len(res)):]
+	if limit, err := strconv.Atoi(f.Get("limit")); err == nil && limit > 0 {
+		res = res[:min(limit, len(res))]
+	}
+	writeJSON(w, res)
+}
+
+// matchSearch matches the station like the server: the name, country, state and language contain the values,
+// case insensitively, and the station has all the tags.
+func matchSearch(st browser.Station, name, tagList, country, countryCode, state, language string) bool {
+	contains := func(s, sub string) bool {
+		return strings.Contains(strings.ToLower(s), strings.ToLower(strings.TrimSpace(sub)))
+	}
+	if !contains(st.Name, name) || !contains(st.Country, country) ||
+		!contains(st.State, state) || !contains(st.Language, language) {
+		return false
+	}
+	if countryCode != "" && !strings.EqualFold(st.Countrycode, countryCode) {
+		return false
+	}
+	tags := strings.Split(strings.ToLower(st.Tags), ",")
+	for _, t := range strings.Split(strings.ToLower(tagList), ",") {
+		if t = strings.TrimSpace(t); t != "" && !slices.Contains(tags, t) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortStations(stations []browser.Station, order string, reverse bool) {
+	var compare func(a, b browser.Station) int
+	switch browser.OrderBy(order) {
+	case browser.Name:
+		compare = func(a, b browser.Station) int { return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)) }
+	case browser.Votes:
+		compare = func(a, b browser.Station) int { return cmp.Compare(a.Votes, b.Votes) }
+	case browser.Clickcount:
+		compare = func(a, b browser.Station) int { return cmp.Compare(a.Clickcount, b.Clickcount) }
+	case browser.Bitrate:
+		compare = func(a, b browser.Station) int { return cmp.Compare(a.Bitrate, b.Bitrate) }
+	default:
+		return
+	}
+	slices.SortStableFunc(stations, func(a, b browser.Station) int {
+		if reverse {
+			return compare(b, a)
+		}
+		return compare(a, b)
+	})
+}
+
+func (d *Directory) byUuid(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	res := []browser.Station{}
+	for _, uuid := range strings.Split(r.Form.Get("uuids"), ",") {
+		if i := d.index(uuid); i >= 0 {
+			res = append(res, d.stations[i])
+		}
+	}
+	writeJSON(w, res)
+}
+
+func (d *Directory) byUrl(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	url := r.Form.Get("url")
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	res := []browser.Station{}
+	for _, st := range d.stations {
+		if st.URL == url || st.URLResolved == url {
+			res = append(res, st)
+		}
+	}
+	writeJSON(w, res)
+}
+
+func (d *Directory) click(w http.ResponseWriter, r *http.Request) {
+	uuid := r.PathValue("uuid")
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	i := d.index(uuid)
+	if i < 0 {
+		writeJSON(w, map[string]any{"ok": false, "message": "did not find station"})
+		return
+	}
+	d.clicks[uuid]++
+	d.stations[i].Clickcount++
+	st := d.stations[i]
+	writeJSON(w, map[string]any{"ok": true, "message": "retrieved station url", "stationuuid": uuid, "name": st.Name, "url": st.URL})
+}
+
+func (d *Directory) vote(w http.ResponseWriter, r *http.Request) {
+	uuid := r.PathValue("uuid")
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	i := d.index(uuid)
+	if i < 0 {
+		writeJSON(w, map[string]any{"ok": false, "message": "VoteError 'could not find station with matching id'"})
+		return
+	}
+	d.votes[uuid]++
+	d.stations[i].Votes++
+	writeJSON(w, map[string]any{"ok": true, "message": "voted for station successfully"})
+}
+
+func (d *Directory) countries(w http.ResponseWriter, r *http.Request) {
+	d.mtx.Lock()
+	counts := make(map[string]int)
+	names := make(map[string]string)
+	for _, st := range d.stations {
+		if st.Countrycode != "" {
+			counts[st.Countrycode]++
+			names[st.Countrycode] = st.Country
+		}
+	}
+	d.mtx.Unlock()
+	res := []browser.Country{}
+	for code, n := range counts {
+		res = append(res, browser.Country{Name: names[code], ISO3166_1: code, Stationcount: n})
+	}
+	slices.SortFunc(res, func(a, b browser.Country) int { return cmp.Compare(a.Name, b.Name) })
+	writeJSON(w, res)
+}
+
+func (d *Directory) languages(w http.ResponseWriter, r *http.Request) {
+	res := []browser.Language{}
+	for name, n := range d.count(func(st browser.Station) string { return st.Language }) {
+		res = append(res, browser.Language{Name: name, Stationcount: n})
+	}
+	slices.SortFunc(res, func(a, b browser.Language) int { return cmp.Compare(a.Name, b.Name) })
+	writeJSON(w, res)
+}
+
+func (d *Directory) tags(w http.ResponseWriter, r *http.Request) {
+	res := []browser.StationTag{}
+	for name, n := range d.count(func(st browser.Station) string { return st.Tags }) {
+		res = append(res, browser.StationTag{Name: name, Stationcount: n})
+	}
+	slices.SortFunc(res, func(a, b browser.StationTag) int {
+		return cmp.Or(cmp.Compare(b.Stationcount, a.Stationcount), cmp.Compare(a.Name, b.Name))
+	})
+	writeJSON(w, res)
+}
+
+// count counts the stations by the comma separated values of the field.
+func (d *Directory) count(field func(browser.Station) string) map[string]int {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	res := make(map[string]int)
+	for _, st := range d.stations {
+		for _, v := range strings.Split(field(st), ",") {
+			if v = strings.TrimSpace(strings.ToLower(v)); v != "" {
+				res[v]++
+			}
+		}
+	}
+	return res
+}
+
+func (d *Directory) index(uuid string) int {
+	return slices.IndexFunc(d.stations, func(st browser.Station) bool { return st.Stationuuid == uuid })
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,140 @@
+package browser
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// FilterFields are the fields a filter term can be scoped to, e.g. `tag:jazz`.
+var FilterFields = []string{"name", "tag", "country", "language", "state", "codec"}
+
+// FieldTerm is a value a field of the station must contain, or match if Regex is set.
+type FieldTerm struct {
+	Field string
+	Value string
+	Regex *regexp.Regexp
+}
+
+// Filter is a list filter term like `tag:jazz country:de lounge`: the stations must match all the field terms,
+// the rest of the term being Text. A value between slashes, like `name:/^radio \d+$/` or `/fm$/` for the name,
+// is a case insensitive regular expression.
+type Filter struct {
+	Fields []FieldTerm
+	Text   string
+	// TextRegex is set when the rest of the term is a regular expression, matched with the station name
+	TextRegex *regexp.Regexp
+}
+
+// ParseFilter splits the term into its field terms and text, failing on an invalid regular expression.
+func ParseFilter(term string) (Filter, error) {
+	var f Filter
+	var text []string
+	for _, word := range strings.Fields(term) {
+		field, value, ok := strings.Cut(word, ":")
+		field = strings.ToLower(field)
+		if !ok || value == "" || !slices.Contains(FilterFields, field) {
+			text = append(text, word)
+			continue
+		}
+		re, err := filterRegex(value)
+		if err != nil {
+			return Filter{}, err
+		}
+		f.Fields = append(f.Fields, FieldTerm{Field: field, Value: strings.ToLower(value), Regex: re})
+	}
+	f.Text = strings.Join(text, " ")
+	re, err := filterRegex(f.Text)
+	if err != nil {
+		return Filter{}, err
+	}
+	if re != nil {
+		f.Text, f.TextRegex = "", re
+	}
+	return f, nil
+}
+
+// filterRegex compiles the value between slashes, nil for a plain value.
+func filterRegex(value string) (*regexp.Regexp, error) {
+	if len(value) < 2 || !strings.HasPrefix(value, "/") || !strings.HasSuffix(value, "/") {
+		return nil, nil
+	}
+	return regexp.Compile("(?i)" + value[1:len(value)-1])
+}
+
+// Scoped tells if the filter has field terms or a regular expression, which the fuzzy matching of names doesn't handle.
+func (f Filter) Scoped() bool {
+	return len(f.Fields) > 0 || f.TextRegex != nil
+}
+
+// Match reports whether the station matches the field terms and the regular expression of the text.
+func (f Filter) Match(s Station) bool {
+	if f.TextRegex != nil && !f.TextRegex.MatchString(s.Name) {
+		return false
+	}
+	for _, t := range f.Fields {
+		if !t.match(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t FieldTerm) match(s Station) bool {
+	var values []string
+	switch t.Field {
+	case "name":
+		values = []string{s.Name}
+	case "tag":
+		values = strings.Split(s.Tags, ",")
+	case "country":
+		if t.Regex == nil && len(t.Value) == 2 {
+			return strings.EqualFold(s.Countrycode, t.Value)
+		}
+		values = []string{s.Country, s.Countrycode}
+	case "language":
+		values = append(strings.Split(s.Language, ","), strings.Split(s.Languagecodes, ",")...)
+	case "state":
+		values = []string{s.State}
+	case "codec":
+		values = []string{s.Codec}
+	}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if t.Regex != nil && t.Regex.MatchString(v) || t.Regex == nil && strings.Contains(strings.ToLower(v), t.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchParams translates the plain field terms to the parameters of a server search, the text being the name.
+// The regular expressions and the codec are left out, the server not supporting them.
+func (f Filter) SearchParams() SearchParams {
+	p := DefaultSearchParams()
+	p.Name = f.Text
+	var tags []string
+	for _, t := range f.Fields {
+		if t.Regex != nil {
+			continue
+		}
+		switch t.Field {
+		case "name":
+			p.Name = strings.TrimSpace(p.Name + " " + t.Value)
+		case "tag":
+			tags = append(tags, t.Value)
+		case "country":
+			if len(t.Value) == 2 {
+				p.CountryCode = strings.ToUpper(t.Value)
+			} else {
+				p.Country = t.Value
+			}
+		case "language":
+			p.Language = t.Value
+		case "state":
+			p.State = t.Value
+		}
+	}
+	p.TagList = strings.Join(tags, ",")
+	return p
+}
@@ -0,0 +1,74 @@
+package browser
+
+import "testing"
+
+func TestFilterMatch(t *testing.T) {
+	stations := []Station{
+		{Name: "Jazz FM", Tags: "jazz,smooth jazz", Countrycode: "DE", Country: "Germany", Language: "german", Codec: "MP3"},
+		{Name: "Radio 1", Tags: "pop,hits", Countrycode: "GB", Country: "The United Kingdom Of Great Britain", Language: "english", Codec: "AAC"},
+		{Name: "Lounge Radio", Tags: "lounge,jazz", Countrycode: "CH", Country: "Switzerland", Language: "german,french", Codec: "MP3"},
+	}
+	tests := []struct {
+		term string
+		want []string
+		text string
+	}{
+		{"tag:jazz", []string{"Jazz FM", "Lounge Radio"}, ""},
+		{"tag:jazz country:de", []string{"Jazz FM"}, ""},
+		{"Country:switz lounge", []string{"Lounge Radio"}, "lounge"},
+		{"language:french", []string{"Lounge Radio"}, ""},
+		{"codec:aac", []string{"Radio 1"}, ""},
+		{"tag:/^jazz$/", []string{"Jazz FM", "Lounge Radio"}, ""},
+		{"tag:/^smooth/", []string{"Jazz FM"}, ""},
+		{"/^radio \\d$/", []string{"Radio 1"}, ""},
+		{"/^radio$/", []string{}, ""},
+		{"/^radio/", []string{"Radio 1"}, ""},
+		{"unknown:x", []string{"Jazz FM", "Radio 1", "Lounge Radio"}, "unknown:x"},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.term)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.term, err)
+		}
+		if f.Text != tt.text {
+			t.Errorf("%q: text %q, want %q", tt.term, f.Text, tt.text)
+		}
+		var got []string
+		for _, s := range stations {
+			if f.Match(s) {
+				got = append(got, s.Name)
+			}
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.term, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%q: got %v, want %v", tt.term, got, tt.want)
+			}
+		}
+	}
+
+	if _, err := ParseFilter("name:/[a/"); err == nil {
+		t.Error("invalid regex parsed")
+	}
+	if f, _ := ParseFilter("jazz"); f.Scoped() {
+		t.Error("plain text is scoped")
+	}
+}
+
+func TestFilterSearchParams(t *testing.T) {
+	f, err := ParseFilter("tag:jazz tag:lounge country:de language:german name:/x/ smooth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := f.SearchParams()
+	if p.TagList != "jazz,lounge" || p.CountryCode != "DE" || p.Country != "" || p.Language != "german" || p.Name != "smooth" {
+		t.Errorf("params %+v", p)
+	}
+	f, _ = ParseFilter("country:france")
+	if p := f.SearchParams(); p.Country != "france" || p.CountryCode != "" {
+		t.Errorf("params %+v", p)
+	}
+}
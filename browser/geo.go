@@ -0,0 +1,67 @@
+package browser
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math"
+	"slices"
+)
+
+const earthRadiusKm = 6371
+
+// Location returns the coordinates of the station, ok false if it has none.
+func (s Station) Location() (lat, long float64, ok bool) {
+	lat, okLat := s.GeoLat.(float64)
+	long, okLong := s.GeoLong.(float64)
+	return lat, long, okLat && okLong
+}
+
+// DistanceKm returns the great-circle distance between two points, in km.
+func DistanceKm(lat1, long1, lat2, long2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLong := (long2 - long1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLong/2)*math.Sin(dLong/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Nearby returns the stations within radiusKm of the location, the nearest first.
+func (a *Api) Nearby(ctx context.Context, lat, long float64, radiusKm, limit int) ([]Station, error) {
+	params := DefaultSearchParams()
+	params.GeoLat, params.GeoLong = lat, long
+	params.GeoDistance = radiusKm * 1000
+	params.Limit = limit
+	stations, err := a.stationSearch(ctx, params)
+	if err != nil && !errors.Is(err, ErrLocalResults) {
+		return nil, err
+	}
+	return sortByDistance(stations, lat, long, radiusKm), err
+}
+
+// sortByDistance keeps the stations within radiusKm, the nearest first.
+func sortByDistance(stations []Station, lat, long float64, radiusKm int) []Station {
+	type near struct {
+		s    Station
+		dist float64
+	}
+	var res []near
+	for _, s := range stations {
+		sLat, sLong, ok := s.Location()
+		if !ok {
+			continue
+		}
+		if d := DistanceKm(lat, long, sLat, sLong); d <= float64(radiusKm) {
+			res = append(res, near{s, d})
+		}
+	}
+	slices.SortStableFunc(res, func(a, b near) int {
+		return cmp.Compare(a.dist, b.dist)
+	})
+	out := make([]Station, len(res))
+	for i, n := range res {
+		out[i] = n.s
+	}
+	return out
+}
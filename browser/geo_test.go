@@ -0,0 +1,29 @@
+package browser
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceKm(t *testing.T) {
+	// Paris to London
+	if d := DistanceKm(48.8566, 2.3522, 51.5074, -0.1278); math.Abs(d-344) > 2 {
+		t.Errorf("DistanceKm()=%v", d)
+	}
+	if d := DistanceKm(10, 10, 10, 10); d != 0 {
+		t.Errorf("same point DistanceKm()=%v", d)
+	}
+}
+
+func TestSortByDistance(t *testing.T) {
+	stations := []Station{
+		{Stationuuid: "far", GeoLat: 51.5074, GeoLong: -0.1278},
+		{Stationuuid: "none"},
+		{Stationuuid: "near", GeoLat: 48.86, GeoLong: 2.35},
+		{Stationuuid: "mid", GeoLat: 49.0, GeoLong: 2.5},
+	}
+	got := sortByDistance(stations, 48.8566, 2.3522, 100)
+	if len(got) != 2 || got[0].Stationuuid != "near" || got[1].Stationuuid != "mid" {
+		t.Errorf("sortByDistance()=%v", got)
+	}
+}
@@ -0,0 +1,151 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/perf"
+)
+
+const HttpCacheFilename = "http_cache.json"
+
+type cachedResponse struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+// httpCache keeps the last response and its validators for the directory data
+// that rarely changes (countries, languages, tags, top stations), so refreshes
+// are made with conditional requests and transfer nothing when unchanged.
+type httpCache struct {
+	mtx     sync.Mutex
+	path    string
+	entries map[string]cachedResponse
+	dirty   bool
+}
+
+func newHttpCache(path string) *httpCache {
+	log := slog.With("method", "browser.newHttpCache")
+	c := &httpCache{
+		path:    path,
+		entries: make(map[string]cachedResponse),
+	}
+	if path == "" {
+		return c
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Error("read http cache", "error", err)
+		}
+		return c
+	}
+	if err := json.Unmarshal(b, &c.entries); err != nil {
+		log.Error("unmarshal http cache", "error", err)
+	}
+	return c
+}
+
+func (c *httpCache) get(key string) (cachedResponse, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	v, ok := c.entries[key]
+	return v, ok
+}
+
+func (c *httpCache) set(key string, v cachedResponse) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.entries[key] = v
+	c.dirty = true
+}
+
+func (c *httpCache) save() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if !c.dirty || c.path == "" {
+		return nil
+	}
+	b, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, b, 0o644); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// doCachedServerRequest performs a GET request for path, sending the validators of the cached response, if any.
+// A 304 Not Modified response returns the cached body.
+func (a *Api) doCachedServerRequest(ctx context.Context, path string) ([]byte, error) {
+	log := slog.With("method", "Api.doCachedServerRequest")
+
+	ix := int(a.serverIdx.Load())
+	url := a.servers[ix] + path
+
+	ctx, cancel := context.WithTimeout(ctx, config.ApiReqTimeout)
+	defer cancel()
+
+	req, err := a.newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	cached, hasCached := a.httpCache.get(path)
+	if hasCached {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	if err := a.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+	defer perf.Since(perf.API, time.Now())
+	res, err := a.client.Do(req)
+	if err != nil {
+		if ctx.Err() == nil && len(a.servers) > 1 {
+			a.serverIdx.CompareAndSwap(int32(ix), int32((ix+1)%len(a.servers)))
+		}
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotModified && hasCached:
+		log.Info("not modified", "path", path)
+		return cached.Body, nil
+	case res.StatusCode == http.StatusTooManyRequests:
+		log.Warn("rate limited", "path", path, "retryAfter", res.Header.Get("Retry-After"))
+		a.limiter.pause(res)
+		return nil, errTooManyReq
+	case res.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unexpected response status %s", res.Status)
+	}
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	etag, lastModified := res.Header.Get("ETag"), res.Header.Get("Last-Modified")
+	if etag != "" || lastModified != "" {
+		a.httpCache.set(path, cachedResponse{ETag: etag, LastModified: lastModified, Body: b})
+	}
+	return b, nil
+}
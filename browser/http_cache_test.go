@@ -0,0 +1,51 @@
+package browser
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+func Test_doCachedServerRequest(t *testing.T) {
+	const body = `[{"name":"english","iso_639":"en","stationcount":10}]`
+	const etag = `"v1"`
+	var requests, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), HttpCacheFilename)
+	a := &Api{client: newHttpClient(), cfg: &config.Value{}, servers: []string{srv.URL}, httpCache: newHttpCache(path)}
+	for i := 0; i < 2; i++ {
+		res, err := a.doCachedServerRequest(context.Background(), urlLangs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(res) != body {
+			t.Errorf("got body=%s, want %s", res, body)
+		}
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("got requests=%d notModified=%d, want 2 and 1", requests, notModified)
+	}
+
+	if err := a.httpCache.save(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := newHttpCache(path)
+	if v, ok := reloaded.get(urlLangs); !ok || v.ETag != etag || string(v.Body) != body {
+		t.Errorf("got reloaded entry=%+v ok=%v", v, ok)
+	}
+}
@@ -0,0 +1,242 @@
+package browser
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sahilm/fuzzy"
+)
+
+const (
+	IndexFilename   = "stations_index.json"
+	indexMaxEntries = 20000
+)
+
+// indexedStation holds the station fields needed for local search and playback.
+type indexedStation struct {
+	Stationuuid string    `json:"uuid"`
+	Name        string    `json:"name"`
+	URL         string    `json:"url"`
+	URLResolved string    `json:"urlResolved,omitempty"`
+	Homepage    string    `json:"homepage,omitempty"`
+	Favicon     string    `json:"favicon,omitempty"`
+	Tags        string    `json:"tags,omitempty"`
+	Country     string    `json:"country,omitempty"`
+	Countrycode string    `json:"countrycode,omitempty"`
+	State       string    `json:"state,omitempty"`
+	Language    string    `json:"language,omitempty"`
+	Codec       string    `json:"codec,omitempty"`
+	Bitrate     int64     `json:"bitrate,omitempty"`
+	Votes       int64     `json:"votes,omitempty"`
+	Seen        time.Time `json:"seen"`
+}
+
+func newIndexedStation(s Station, seen time.Time) indexedStation {
+	return indexedStation{
+		Stationuuid: s.Stationuuid,
+		Name:        s.Name,
+		URL:         s.URL,
+		URLResolved: s.URLResolved,
+		Homepage:    s.Homepage,
+		Favicon:     s.Favicon,
+		Tags:        s.Tags,
+		Country:     s.Country,
+		Countrycode: s.Countrycode,
+		State:       s.State,
+		Language:    s.Language,
+		Codec:       s.Codec,
+		Bitrate:     s.Bitrate,
+		Votes:       s.Votes,
+		Seen:        seen,
+	}
+}
+
+func (s indexedStation) station() Station {
+	return Station{
+		Stationuuid: s.Stationuuid,
+		Name:        s.Name,
+		URL:         s.URL,
+		URLResolved: s.URLResolved,
+		Homepage:    s.Homepage,
+		Favicon:     s.Favicon,
+		Tags:        s.Tags,
+		Country:     s.Country,
+		Countrycode: s.Countrycode,
+		State:       s.State,
+		Language:    s.Language,
+		Codec:       s.Codec,
+		Bitrate:     s.Bitrate,
+		Votes:       s.Votes,
+	}
+}
+
+func (s indexedStation) text() string {
+	return strings.ToLower(strings.Join([]string{s.Name, s.Tags, s.Country, s.Countrycode, s.State, s.Language}, " "))
+}
+
+// StationIndex is a small on-disk index of every station received from the API,
+// used to search stations instantly and while offline.
+type StationIndex struct {
+	mtx      sync.RWMutex
+	path     string
+	stations map[string]indexedStation
+	dirty    bool
+}
+
+// NewStationIndex loads the index from path, if it exists. An empty path creates an in-memory index.
+func NewStationIndex(path string) *StationIndex {
+	log := slog.With("method", "browser.NewStationIndex")
+	x := &StationIndex{
+		path:     path,
+		stations: make(map[string]indexedStation),
+	}
+	if path == "" {
+		return x
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Error("read index", "error", err)
+		}
+		return x
+	}
+	var entries []indexedStation
+	if err := json.Unmarshal(b, &entries); err != nil {
+		log.Error("unmarshal index", "error", err)
+		return x
+	}
+	for _, e := range entries {
+		x.stations[e.Stationuuid] = e
+	}
+	log.Info("loaded", "len", len(x.stations))
+	return x
+}
+
+func (x *StationIndex) Len() int {
+	x.mtx.RLock()
+	defer x.mtx.RUnlock()
+	return len(x.stations)
+}
+
+// Add inserts or refreshes the given stations.
+func (x *StationIndex) Add(stations []Station) {
+	if len(stations) == 0 {
+		return
+	}
+	x.mtx.Lock()
+	defer x.mtx.Unlock()
+
+	now := time.Now()
+	for _, s := range stations {
+		if s.Stationuuid == "" {
+			continue
+		}
+		x.stations[s.Stationuuid] = newIndexedStation(s, now)
+	}
+	x.evict()
+	x.dirty = true
+}
+
+// evict removes the least recently seen stations above indexMaxEntries.
+func (x *StationIndex) evict() {
+	extra := len(x.stations) - indexMaxEntries
+	if extra <= 0 {
+		return
+	}
+	entries := make([]indexedStation, 0, len(x.stations))
+	for _, v := range x.stations {
+		entries = append(entries, v)
+	}
+	slices.SortFunc(entries, func(a, b indexedStation) int { return a.Seen.Compare(b.Seen) })
+	for i := 0; i < extra; i++ {
+		delete(x.stations, entries[i].Stationuuid)
+	}
+}
+
+// Search returns up to limit stations matching all the words of query in their name, tags, country or language,
+// ordered by votes. If no such station exists, a fuzzy match on the station names is attempted.
+func (x *StationIndex) Search(query string, limit int) []Station {
+	x.mtx.RLock()
+	defer x.mtx.RUnlock()
+
+	terms := strings.Fields(strings.ToLower(query))
+	var res []indexedStation
+	for _, s := range x.stations {
+		text := s.text()
+		all := true
+		for _, t := range terms {
+			if !strings.Contains(text, t) {
+				all = false
+				break
+			}
+		}
+		if all {
+			res = append(res, s)
+		}
+	}
+	slices.SortFunc(res, func(a, b indexedStation) int {
+		return cmp.Or(cmp.Compare(b.Votes, a.Votes), strings.Compare(a.Name, b.Name))
+	})
+
+	if len(res) == 0 && len(terms) > 0 {
+		res = x.fuzzySearch(strings.Join(terms, " "))
+	}
+
+	if limit > 0 && len(res) > limit {
+		res = res[:limit]
+	}
+	stations := make([]Station, len(res))
+	for i := range res {
+		stations[i] = res[i].station()
+	}
+	return stations
+}
+
+type indexSource []indexedStation
+
+func (s indexSource) String(i int) string { return s[i].Name }
+func (s indexSource) Len() int            { return len(s) }
+
+func (x *StationIndex) fuzzySearch(pattern string) []indexedStation {
+	src := make(indexSource, 0, len(x.stations))
+	for _, s := range x.stations {
+		src = append(src, s)
+	}
+	matches := fuzzy.FindFrom(pattern, src)
+	res := make([]indexedStation, len(matches))
+	for i := range matches {
+		res[i] = src[matches[i].Index]
+	}
+	return res
+}
+
+// Save writes the index to disk if it changed since it was loaded or last saved.
+func (x *StationIndex) Save() error {
+	x.mtx.Lock()
+	defer x.mtx.Unlock()
+
+	if !x.dirty || x.path == "" {
+		return nil
+	}
+	entries := make([]indexedStation, 0, len(x.stations))
+	for _, v := range x.stations {
+		entries = append(entries, v)
+	}
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(x.path, b, 0o644); err != nil {
+		return err
+	}
+	x.dirty = false
+	return nil
+}
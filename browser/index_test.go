@@ -0,0 +1,50 @@
+package browser
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStationIndex(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), IndexFilename)
+	x := NewStationIndex(fp)
+	x.Add([]Station{
+		{Stationuuid: "1", Name: "Jazz FM", Tags: "jazz,smooth", Country: "Germany", Votes: 10},
+		{Stationuuid: "2", Name: "Rock Antenne", Tags: "rock", Country: "Germany", Votes: 50},
+		{Stationuuid: "3", Name: "Radio Swiss Jazz", Tags: "jazz", Country: "Switzerland", Votes: 90},
+	})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "jazz", want: []string{"3", "1"}},
+		{query: "JAZZ germany", want: []string{"1"}},
+		{query: "germany", want: []string{"2", "1"}},
+		{query: "rckantn", want: []string{"2"}},
+		{query: "classical", want: nil},
+	}
+	for _, tt := range tests {
+		got := x.Search(tt.query, 0)
+		if len(got) != len(tt.want) {
+			t.Errorf("query=%q got %d stations, want %d", tt.query, len(got), len(tt.want))
+			continue
+		}
+		for i := range got {
+			if got[i].Stationuuid != tt.want[i] {
+				t.Errorf("query=%q ix=%d got uuid=%s, want %s", tt.query, i, got[i].Stationuuid, tt.want[i])
+			}
+		}
+	}
+
+	if err := x.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewStationIndex(fp)
+	if loaded.Len() != 3 {
+		t.Fatalf("loaded index len=%d, want 3", loaded.Len())
+	}
+	if got := loaded.Search("swiss", 1); len(got) != 1 || got[0].Name != "Radio Swiss Jazz" {
+		t.Errorf("loaded index search got %v", got)
+	}
+}
@@ -0,0 +1,66 @@
+package browser
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// transientPrefix starts the uuids of the stations made for the stream urls not listed in the directory.
+const transientPrefix = "url:"
+
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ParseStationRef returns the station uuid or the http(s) stream url of the text, e.g. pasted from a link,
+// both empty for the other texts.
+func ParseStationRef(text string) (uuid, streamUrl string) {
+	text = strings.TrimSpace(text)
+	if uuidRe.MatchString(text) {
+		return strings.ToLower(text), ""
+	}
+	u, err := url.Parse(text)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", ""
+	}
+	return "", text
+}
+
+// NewTransientStation returns a station playing the stream url, which isn't listed in the directory, named
+// after its host.
+func NewTransientStation(streamUrl string) Station {
+	name := streamUrl
+	if u, err := url.Parse(streamUrl); err == nil && u.Hostname() != "" {
+		name = u.Hostname()
+	}
+	return Station{Stationuuid: transientPrefix + streamUrl, Name: name, URL: streamUrl, URLResolved: streamUrl}
+}
+
+// Transient reports whether the station isn't listed in the directory, known only by its stream url. It
+// can't be voted, counted or saved to favorites.
+func (s Station) Transient() bool {
+	return strings.HasPrefix(s.Stationuuid, transientPrefix)
+}
+
+// TransientStationByUuid returns the transient station of the uuid, e.g. of the history, false for the
+// listed stations.
+func TransientStationByUuid(uuid string) (Station, bool) {
+	streamUrl, ok := strings.CutPrefix(uuid, transientPrefix)
+	if !ok {
+		return Station{}, false
+	}
+	return NewTransientStation(streamUrl), true
+}
+
+// Lookup returns the station of the uuid, or the stations streaming the url, a transient one when the url
+// isn't listed.
+func (a *Api) Lookup(ctx context.Context, uuid, streamUrl string) ([]Station, error) {
+	if uuid != "" {
+		return a.GetStations([]string{uuid})
+	}
+	stations, err := a.StationsByUrl(ctx, streamUrl)
+	if err != nil || len(stations) > 0 {
+		return stations, err
+	}
+	return []Station{NewTransientStation(streamUrl)}, nil
+}
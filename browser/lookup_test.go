@@ -0,0 +1,39 @@
+package browser
+
+import "testing"
+
+func TestParseStationRef(t *testing.T) {
+	tests := []struct {
+		text, uuid, url string
+	}{
+		{" 9617A958-0601-11E8-AE97-52543BE04C81 ", "9617a958-0601-11e8-ae97-52543be04c81", ""},
+		{"https://stream.example.com/jazz.mp3", "", "https://stream.example.com/jazz.mp3"},
+		{"http://10.0.0.2:8000/live", "", "http://10.0.0.2:8000/live"},
+		{"jazz", "", ""},
+		{"ftp://example.com/jazz", "", ""},
+		{"https://", "", ""},
+		{"9617a958-0601-11e8-ae97", "", ""},
+	}
+	for _, tt := range tests {
+		uuid, url := ParseStationRef(tt.text)
+		if uuid != tt.uuid || url != tt.url {
+			t.Errorf("ParseStationRef(%q)=%q,%q want %q,%q", tt.text, uuid, url, tt.uuid, tt.url)
+		}
+	}
+}
+
+func TestNewTransientStation(t *testing.T) {
+	s := NewTransientStation("https://stream.example.com:8443/jazz")
+	if !s.Transient() || s.Name != "stream.example.com" || s.URL != "https://stream.example.com:8443/jazz" {
+		t.Errorf("got %+v, want a transient station named after the host", s)
+	}
+	if got, ok := TransientStationByUuid(s.Stationuuid); !ok || got != s {
+		t.Errorf("got %+v %v, want the station of the uuid", got, ok)
+	}
+	if _, ok := TransientStationByUuid("9617a958-0601-11e8-ae97-52543be04c81"); ok {
+		t.Error("expected no transient station for a listed uuid")
+	}
+	if (Station{Stationuuid: "9617a958-0601-11e8-ae97-52543be04c81"}).Transient() {
+		t.Error("a listed station is not transient")
+	}
+}
@@ -14,7 +14,7 @@ type Language struct {
 
 type StationTag struct {
 	Name         string `json:"name"`
-	Stationcount string `json:"stationcount"`
+	Stationcount int    `json:"stationcount"`
 }
 type ClickCounterResponse struct {
 	Ok          string `json:"ok"`
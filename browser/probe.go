@@ -0,0 +1,98 @@
+package browser
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	maxPlaylistSize  = 64 << 10
+	maxPlaylistDepth = 3
+)
+
+// ProbeStream resolves the redirects and the M3U or PLS playlists of a stream url, and checks that
+// it answers, so that the player connects directly to the returned stream url.
+// HLS playlists are returned as is, the players handle them.
+func ProbeStream(ctx context.Context, streamURL string) (string, error) {
+	for range maxPlaylistDepth {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
+		if err != nil {
+			return "", err
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		finalURL := res.Request.URL.String()
+		if res.StatusCode != http.StatusOK {
+			res.Body.Close()
+			return "", fmt.Errorf("stream %s: %s", streamURL, res.Status)
+		}
+		kind := playlistKind(res.Header.Get("Content-Type"), res.Request.URL.Path)
+		if kind == "" {
+			res.Body.Close()
+			return finalURL, nil
+		}
+		b, err := io.ReadAll(io.LimitReader(res.Body, maxPlaylistSize))
+		res.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		next := firstPlaylistEntry(kind, string(b), res.Request.URL)
+		if next == "" {
+			return "", fmt.Errorf("stream %s: empty playlist", streamURL)
+		}
+		streamURL = next
+	}
+	return streamURL, nil
+}
+
+// playlistKind returns "pls" or "m3u" for the playlists to resolve, and "" for the streams, including HLS.
+func playlistKind(contentType, path string) string {
+	mt, _, _ := mime.ParseMediaType(contentType)
+	switch mt {
+	case "audio/x-scpls", "application/pls+xml":
+		return "pls"
+	case "audio/x-mpegurl", "audio/mpegurl":
+		if !strings.HasSuffix(strings.ToLower(path), ".m3u8") {
+			return "m3u"
+		}
+	case "", "text/plain", "application/octet-stream":
+		switch {
+		case strings.HasSuffix(strings.ToLower(path), ".pls"):
+			return "pls"
+		case strings.HasSuffix(strings.ToLower(path), ".m3u"):
+			return "m3u"
+		}
+	}
+	return ""
+}
+
+// firstPlaylistEntry returns the first url of the playlist, resolved against base.
+func firstPlaylistEntry(kind, playlist string, base *url.URL) string {
+	sc := bufio.NewScanner(strings.NewReader(playlist))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if kind == "pls" {
+			k, v, ok := strings.Cut(line, "=")
+			if !ok || !strings.HasPrefix(strings.ToLower(k), "file") {
+				continue
+			}
+			line = strings.TrimSpace(v)
+		} else if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		u, err := base.Parse(line)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		return u.String()
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package browser
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProbeStream(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/listen.pls", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/x-scpls")
+		w.Write([]byte("[playlist]\nNumberOfEntries=1\nFile1=/redirect\nTitle1=Radio X\n"))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/live.m3u", http.StatusFound)
+	})
+	mux.HandleFunc("/live.m3u", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("#EXTM3U\n#EXTINF:-1,Radio X\n/stream\n"))
+	})
+	mux.HandleFunc("/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write([]byte("abcd"))
+	})
+	mux.HandleFunc("/hls.m3u8", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+		w.Write([]byte("#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=128000\nlow.m3u8\n"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{srv.URL + "/listen.pls", srv.URL + "/stream"},
+		{srv.URL + "/stream", srv.URL + "/stream"},
+		{srv.URL + "/hls.m3u8", srv.URL + "/hls.m3u8"},
+	}
+	for _, tt := range tests {
+		got, err := ProbeStream(context.Background(), tt.url)
+		if err != nil || got != tt.want {
+			t.Errorf("ProbeStream(%s) = %s, %v, want %s", tt.url, got, err, tt.want)
+		}
+	}
+	if _, err := ProbeStream(context.Background(), srv.URL+"/missing"); err == nil {
+		t.Error("no error for a missing stream")
+	}
+}
@@ -0,0 +1,73 @@
+package browser
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxRetryAfter caps the pause asked by a server answering 429 Too Many Requests.
+const maxRetryAfter = time.Minute
+
+// rateLimiter spaces out the requests to the API servers, so that the features sending many of them,
+// like loading all the pages of a search, don't get the app blocked.
+type rateLimiter struct {
+	interval time.Duration
+
+	mtx sync.Mutex
+	// next is the earliest time of the next request
+	next time.Time
+}
+
+func newRateLimiter(perSec float64) *rateLimiter {
+	if perSec <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / perSec)}
+}
+
+// wait blocks until the next request can be sent, or ctx is done. A nil limiter doesn't wait.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mtx.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mtx.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return nil
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pause delays the next requests by the Retry-After of a 429 response, a second when missing.
+func (l *rateLimiter) pause(res *http.Response) {
+	if l == nil {
+		return
+	}
+	d := time.Second
+	if sec, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && sec > 0 {
+		d = min(time.Duration(sec)*time.Second, maxRetryAfter)
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if at := time.Now().Add(d); at.After(l.next) {
+		l.next = at
+	}
+}
@@ -0,0 +1,67 @@
+package browser
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+func Test_rateLimiter(t *testing.T) {
+	l := newRateLimiter(20)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(start); d < 100*time.Millisecond {
+		t.Errorf("3 requests at 20/s took %v, want at least 100ms", d)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l.pause(&http.Response{Header: http.Header{"Retry-After": {"30"}}})
+	cancel()
+	if err := l.wait(ctx); err == nil {
+		t.Error("expected the wait to stop with the context")
+	}
+
+	if newRateLimiter(0) != nil {
+		t.Error("expected no limiter without a rate")
+	}
+}
+
+func Test_rateLimited(t *testing.T) {
+	var agents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.Header.Get("User-Agent"))
+		if len(agents) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	a := &Api{client: newHttpClient(), limiter: newRateLimiter(100), cfg: &config.Value{Version: "1.0"}, servers: []string{srv.URL}}
+	if _, err := a.doServerRequest(context.Background(), http.MethodGet, urlCountries, nil); err != errTooManyReq {
+		t.Fatalf("got err=%v, want %v", err, errTooManyReq)
+	}
+	start := time.Now()
+	if _, err := a.doServerRequest(context.Background(), http.MethodGet, urlCountries, nil); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 900*time.Millisecond {
+		t.Errorf("request after Retry-After of 1s sent after %v", d)
+	}
+	for _, ua := range agents {
+		if !strings.HasPrefix(ua, "sonicradio/1.0 ") || !strings.Contains(ua, homepage) {
+			t.Errorf("got User-Agent=%q", ua)
+		}
+	}
+}
@@ -41,8 +41,11 @@ type SearchParams struct {
 	Order    OrderBy
 	Reverse  bool
 
-	Offset int
-	// CountryCode string
+	Offset      int
+	CountryCode string // ISO 3166-1 alpha-2, an exact match unlike Country
+	GeoLat      float64
+	GeoLong     float64
+	GeoDistance int // Meters around GeoLat and GeoLong, 0 for no location filter
 	// TagExact    string //always "true"
 	// HideBroken  string //always "true"
 }
@@ -60,8 +63,15 @@ func (p SearchParams) toFormData() string {
 	fname := strings.Join(strings.Fields(p.Name), "+")
 	fTags := strings.Join(strings.Fields(p.TagList), "+")
 
-	return fmt.Sprintf("name=%s&tagList=%s&country=%s&countryExact=false&state=%s&language=%s&tagExact=true&offset=%d&limit=%d&order=%s&bitrateMin=0&bitrateMax=&reverse=%s&hidebroken=true",
+	res := fmt.Sprintf("name=%s&tagList=%s&country=%s&countryExact=false&state=%s&language=%s&tagExact=true&offset=%d&limit=%d&order=%s&bitrateMin=0&bitrateMax=&reverse=%s&hidebroken=true",
 		fname, fTags, p.Country, p.State, p.Language, p.Offset, p.Limit, p.Order, boolString(p.Reverse))
+	if p.CountryCode != "" {
+		res += "&countrycode=" + p.CountryCode
+	}
+	if p.GeoDistance > 0 {
+		res += fmt.Sprintf("&has_geo_info=true&geo_lat=%f&geo_long=%f&geo_distance=%d", p.GeoLat, p.GeoLong, p.GeoDistance)
+	}
+	return res
 }
 
 func boolString(v bool) string {
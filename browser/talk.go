@@ -0,0 +1,37 @@
+package browser
+
+import (
+	"slices"
+	"strings"
+)
+
+// talkTags mark the stations where people speak more than music plays, matched anywhere in a tag:
+// "talk" also matches "talk radio" and "news talk".
+var talkTags = []string{"talk", "news", "spoken", "podcast", "information", "discussion"}
+
+// IsTalk reports whether the station has a talk or news tag.
+func (s Station) IsTalk() bool {
+	for _, tag := range strings.Split(strings.ToLower(s.Tags), ",") {
+		for _, t := range talkTags {
+			if strings.Contains(tag, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TalkFirst returns the stations with the talk and news ones first, each group keeping its order.
+func TalkFirst(stations []Station) []Station {
+	res := slices.Clone(stations)
+	slices.SortStableFunc(res, func(a, b Station) int {
+		switch {
+		case a.IsTalk() == b.IsTalk():
+			return 0
+		case a.IsTalk():
+			return -1
+		}
+		return 1
+	})
+	return res
+}
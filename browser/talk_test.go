@@ -0,0 +1,22 @@
+package browser
+
+import "testing"
+
+func TestTalkFirst(t *testing.T) {
+	stations := []Station{
+		{Name: "pop", Tags: "pop,hits"},
+		{Name: "news", Tags: "News,information"},
+		{Name: "jazz", Tags: "jazz"},
+		{Name: "talk", Tags: "talk radio"},
+	}
+	got := TalkFirst(stations)
+	want := []string{"news", "talk", "pop", "jazz"}
+	for i := range want {
+		if got[i].Name != want[i] {
+			t.Fatalf("got %v at %d, want %s", got[i].Name, i, want[i])
+		}
+	}
+	if stations[0].Name != "pop" {
+		t.Error("the stations were reordered in place")
+	}
+}
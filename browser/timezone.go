@@ -0,0 +1,135 @@
+package browser
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	// the zones of the stations, also on the systems without a time zone database
+	_ "time/tzdata"
+)
+
+// countryZones is the time zone of the countries with a single one, or the most populated one.
+var countryZones = parseCountryZones(`
+AD Europe/Andorra AE Asia/Dubai AF Asia/Kabul AG America/Antigua AI America/Anguilla AL Europe/Tirane
+AM Asia/Yerevan AO Africa/Luanda AR America/Argentina/Buenos_Aires AS Pacific/Pago_Pago AT Europe/Vienna
+AW America/Aruba AX Europe/Mariehamn AZ Asia/Baku BA Europe/Sarajevo BB America/Barbados BD Asia/Dhaka
+BE Europe/Brussels BF Africa/Ouagadougou BG Europe/Sofia BH Asia/Bahrain BI Africa/Bujumbura BJ Africa/Porto-Novo
+BM Atlantic/Bermuda BN Asia/Brunei BO America/La_Paz BQ America/Kralendijk BS America/Nassau BT Asia/Thimphu
+BW Africa/Gaborone BY Europe/Minsk BZ America/Belize CF Africa/Bangui CG Africa/Brazzaville CH Europe/Zurich
+CI Africa/Abidjan CK Pacific/Rarotonga CL America/Santiago CM Africa/Douala CN Asia/Shanghai CO America/Bogota
+CR America/Costa_Rica CU America/Havana CV Atlantic/Cape_Verde CW America/Curacao CY Asia/Nicosia CZ Europe/Prague
+DE Europe/Berlin DJ Africa/Djibouti DK Europe/Copenhagen DM America/Dominica DO America/Santo_Domingo DZ Africa/Algiers
+EC America/Guayaquil EE Europe/Tallinn EG Africa/Cairo EH Africa/El_Aaiun ER Africa/Asmara ET Africa/Addis_Ababa
+FI Europe/Helsinki FJ Pacific/Fiji FK Atlantic/Stanley FM Pacific/Pohnpei FO Atlantic/Faroe FR Europe/Paris
+GA Africa/Libreville GB Europe/London GD America/Grenada GE Asia/Tbilisi GF America/Cayenne GG Europe/Guernsey
+GH Africa/Accra GI Europe/Gibraltar GL America/Nuuk GM Africa/Banjul GN Africa/Conakry GP America/Guadeloupe
+GQ Africa/Malabo GR Europe/Athens GT America/Guatemala GU Pacific/Guam GW Africa/Bissau GY America/Guyana
+HK Asia/Hong_Kong HN America/Tegucigalpa HR Europe/Zagreb HT America/Port-au-Prince HU Europe/Budapest IE Europe/Dublin
+IL Asia/Jerusalem IM Europe/Isle_of_Man IN Asia/Kolkata IQ Asia/Baghdad IR Asia/Tehran IS Atlantic/Reykjavik
+IT Europe/Rome JE Europe/Jersey JM America/Jamaica JO Asia/Amman JP Asia/Tokyo KE Africa/Nairobi
+KG Asia/Bishkek KH Asia/Phnom_Penh KI Pacific/Tarawa KM Indian/Comoro KN America/St_Kitts KP Asia/Pyongyang
+KR Asia/Seoul KW Asia/Kuwait KY America/Cayman LA Asia/Vientiane LB Asia/Beirut LC America/St_Lucia
+LI Europe/Vaduz LK Asia/Colombo LR Africa/Monrovia LS Africa/Maseru LT Europe/Vilnius LU Europe/Luxembourg
+LV Europe/Riga LY Africa/Tripoli MA Africa/Casablanca MC Europe/Monaco MD Europe/Chisinau ME Europe/Podgorica
+MG Indian/Antananarivo MH Pacific/Majuro MK Europe/Skopje ML Africa/Bamako MM Asia/Yangon MN Asia/Ulaanbaatar
+MO Asia/Macau MP Pacific/Saipan MQ America/Martinique MR Africa/Nouakchott MS America/Montserrat MT Europe/Malta
+MU Indian/Mauritius MV Indian/Maldives MW Africa/Blantyre MY Asia/Kuala_Lumpur MZ Africa/Maputo NA Africa/Windhoek
+NC Pacific/Noumea NE Africa/Niamey NG Africa/Lagos NI America/Managua NL Europe/Amsterdam NO Europe/Oslo
+NP Asia/Kathmandu NZ Pacific/Auckland OM Asia/Muscat PA America/Panama PE America/Lima PF Pacific/Tahiti
+PG Pacific/Port_Moresby PH Asia/Manila PK Asia/Karachi PL Europe/Warsaw PR America/Puerto_Rico PS Asia/Gaza
+PW Pacific/Palau PY America/Asuncion QA Asia/Qatar RE Indian/Reunion RO Europe/Bucharest RS Europe/Belgrade
+RW Africa/Kigali SA Asia/Riyadh SB Pacific/Guadalcanal SC Indian/Mahe SD Africa/Khartoum SE Europe/Stockholm
+SG Asia/Singapore SI Europe/Ljubljana SK Europe/Bratislava SL Africa/Freetown SM Europe/San_Marino SN Africa/Dakar
+SO Africa/Mogadishu SR America/Paramaribo SS Africa/Juba ST Africa/Sao_Tome SV America/El_Salvador SX America/Lower_Princes
+SY Asia/Damascus SZ Africa/Mbabane TC America/Grand_Turk TD Africa/Ndjamena TG Africa/Lome TH Asia/Bangkok
+TJ Asia/Dushanbe TL Asia/Dili TM Asia/Ashgabat TN Africa/Tunis TO Pacific/Tongatapu TR Europe/Istanbul
+TT America/Port_of_Spain TW Asia/Taipei TZ Africa/Dar_es_Salaam UA Europe/Kyiv UG Africa/Kampala UY America/Montevideo
+UZ Asia/Tashkent VA Europe/Vatican VC America/St_Vincent VE America/Caracas VG America/Tortola VI America/St_Thomas
+VN Asia/Ho_Chi_Minh VU Pacific/Efate WS Pacific/Apia XK Europe/Belgrade YE Asia/Aden YT Indian/Mayotte
+ZA Africa/Johannesburg ZM Africa/Lusaka ZW Africa/Harare
+`)
+
+// zoneCity is a city of a country with several time zones, the station gets the zone of the nearest one.
+type zoneCity struct {
+	zone      string
+	lat, long float64
+}
+
+var multiZoneCountries = map[string][]zoneCity{
+	"US": {
+		{"America/New_York", 40.71, -74.01}, {"America/Chicago", 41.88, -87.63}, {"America/Denver", 39.74, -104.99},
+		{"America/Phoenix", 33.45, -112.07}, {"America/Los_Angeles", 34.05, -118.24}, {"America/Anchorage", 61.22, -149.9},
+		{"Pacific/Honolulu", 21.31, -157.86},
+	},
+	"CA": {
+		{"America/Toronto", 43.65, -79.38}, {"America/St_Johns", 47.56, -52.71}, {"America/Halifax", 44.65, -63.58},
+		{"America/Winnipeg", 49.9, -97.14}, {"America/Regina", 50.45, -104.61}, {"America/Edmonton", 53.55, -113.49},
+		{"America/Vancouver", 49.28, -123.12},
+	},
+	"MX": {
+		{"America/Mexico_City", 19.43, -99.13}, {"America/Cancun", 21.16, -86.85}, {"America/Mazatlan", 23.25, -106.41},
+		{"America/Tijuana", 32.51, -117.04},
+	},
+	"BR": {
+		{"America/Sao_Paulo", -23.55, -46.63}, {"America/Recife", -8.05, -34.9}, {"America/Fortaleza", -3.73, -38.52},
+		{"America/Belem", -1.46, -48.5}, {"America/Cuiaba", -15.6, -56.1}, {"America/Manaus", -3.12, -60.02},
+		{"America/Rio_Branco", -9.97, -67.81},
+	},
+	"RU": {
+		{"Europe/Moscow", 55.76, 37.62}, {"Europe/Kaliningrad", 54.71, 20.51}, {"Europe/Samara", 53.2, 50.15},
+		{"Asia/Yekaterinburg", 56.84, 60.6}, {"Asia/Omsk", 54.99, 73.37}, {"Asia/Novosibirsk", 55.01, 82.93},
+		{"Asia/Krasnoyarsk", 56.01, 92.89}, {"Asia/Irkutsk", 52.29, 104.28}, {"Asia/Yakutsk", 62.03, 129.73},
+		{"Asia/Vladivostok", 43.12, 131.89}, {"Asia/Magadan", 59.56, 150.81}, {"Asia/Kamchatka", 53.02, 158.65},
+	},
+	"AU": {
+		{"Australia/Sydney", -33.87, 151.21}, {"Australia/Melbourne", -37.81, 144.96}, {"Australia/Brisbane", -27.47, 153.03},
+		{"Australia/Adelaide", -34.93, 138.6}, {"Australia/Darwin", -12.46, 130.84}, {"Australia/Perth", -31.95, 115.86},
+		{"Australia/Hobart", -42.88, 147.33},
+	},
+	"ID": {{"Asia/Jakarta", -6.21, 106.85}, {"Asia/Makassar", -5.15, 119.43}, {"Asia/Jayapura", -2.53, 140.72}},
+	"KZ": {{"Asia/Almaty", 43.24, 76.89}, {"Asia/Aqtobe", 50.28, 57.17}},
+	"CD": {{"Africa/Kinshasa", -4.44, 15.27}, {"Africa/Lubumbashi", -11.66, 27.48}},
+	"ES": {{"Europe/Madrid", 40.42, -3.7}, {"Atlantic/Canary", 28.12, -15.44}},
+	"PT": {{"Europe/Lisbon", 38.72, -9.14}, {"Atlantic/Azores", 37.74, -25.67}},
+}
+
+func parseCountryZones(s string) map[string]string {
+	res := make(map[string]string)
+	fields := strings.Fields(s)
+	for i := 0; i+1 < len(fields); i += 2 {
+		res[fields[i]] = fields[i+1]
+	}
+	return res
+}
+
+// TimeZone returns the time zone of the station, from its country and its location. approx is true
+// when it's a guess: the main zone of a country with several, or the offset of the longitude for a
+// station without a country. ok is false when the station has neither.
+func (s Station) TimeZone() (loc *time.Location, approx bool, ok bool) {
+	cc := strings.ToUpper(strings.TrimSpace(s.Countrycode))
+	lat, long, hasGeo := s.Location()
+	if cities, multi := multiZoneCountries[cc]; multi {
+		city := cities[0]
+		if hasGeo {
+			for _, c := range cities[1:] {
+				if DistanceKm(lat, long, c.lat, c.long) < DistanceKm(lat, long, city.lat, city.long) {
+					city = c
+				}
+			}
+		}
+		if loc, err := time.LoadLocation(city.zone); err == nil {
+			return loc, !hasGeo, true
+		}
+	}
+	if zone, single := countryZones[cc]; single {
+		if loc, err := time.LoadLocation(zone); err == nil {
+			return loc, false, true
+		}
+	}
+	if hasGeo {
+		offset := int(math.Round(long / 15))
+		return time.FixedZone(fmt.Sprintf("UTC%+d", offset), offset*3600), true, true
+	}
+	return nil, false, false
+}
@@ -0,0 +1,52 @@
+package browser
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStationTimeZone(t *testing.T) {
+	tests := []struct {
+		name    string
+		station Station
+		zone    string
+		approx  bool
+		noZone  bool
+	}{
+		{"single zone country", Station{Countrycode: "fr"}, "Europe/Paris", false, false},
+		{"located in a multi zone country", Station{Countrycode: "US", GeoLat: 37.77, GeoLong: -122.42}, "America/Los_Angeles", false, false},
+		{"multi zone country", Station{Countrycode: "US"}, "America/New_York", true, false},
+		{"located without country", Station{GeoLat: 0.0, GeoLong: 31.0}, "UTC+2", true, false},
+		{"unknown", Station{}, "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, approx, ok := tt.station.TimeZone()
+			if ok == tt.noZone {
+				t.Fatalf("TimeZone() ok=%v", ok)
+			}
+			if !ok {
+				return
+			}
+			if loc.String() != tt.zone || approx != tt.approx {
+				t.Errorf("TimeZone()=%v, %v, want %v, %v", loc, approx, tt.zone, tt.approx)
+			}
+		})
+	}
+}
+
+func TestCountryZones(t *testing.T) {
+	zones := slices.Collect(maps.Values(countryZones))
+	for _, cities := range multiZoneCountries {
+		for _, c := range cities {
+			zones = append(zones, c.zone)
+		}
+	}
+	for _, zone := range zones {
+		if _, err := time.LoadLocation(zone); err != nil {
+			t.Error(err)
+		}
+	}
+}
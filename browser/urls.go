@@ -3,9 +3,10 @@ package browser
 const (
 	urlStations       = "/json/stations/search"
 	urlStationsByUUID = "/json/stations/byuuid"
+	urlStationsByUrl  = "/json/stations/byurl"
 	urlClickCount     = "/json/url/"
 	urlCountries      = "/json/countries"
 	urlLangs          = "/json/languages"
-	urlTags           = "/json/tags "
+	urlTags           = "/json/tags?order=stationcount&reverse=true&hidebroken=true&limit=500"
 	urlVote           = "/json/vote/"
 )
@@ -0,0 +1,161 @@
+package cast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os/exec"
+	"strconv"
+	"sync"
+)
+
+const (
+	// raopSenderBin streams raw PCM to an AirPlay device, from https://github.com/philippe44/libraop
+	raopSenderBin = "raop_play"
+	decoderBin    = "ffmpeg"
+)
+
+var errNoAirplaySender = fmt.Errorf("AirPlay needs %s and %s in PATH", raopSenderBin, decoderBin)
+
+// airplayAvailable tells if the external programs that send the stream to AirPlay devices are installed.
+func airplayAvailable() bool {
+	for _, bin := range []string{raopSenderBin, decoderBin} {
+		if _, err := exec.LookPath(bin); err != nil && !errors.Is(err, exec.ErrDot) {
+			return false
+		}
+	}
+	return true
+}
+
+// airplayRenderer decodes the stream with ffmpeg and pipes it to raop_play, which sends it to the AirPlay device.
+// Like ffplay, pausing stops the pipeline and the volume can only be changed while stopped.
+type airplayRenderer struct {
+	device Device
+	host   string
+	port   string
+
+	mtx     sync.Mutex
+	url     string
+	volume  int
+	playing context.CancelFunc
+	done    chan struct{}
+}
+
+func newAirplayRenderer(d Device) (*airplayRenderer, error) {
+	if !airplayAvailable() {
+		return nil, errNoAirplaySender
+	}
+	host, port, err := net.SplitHostPort(d.Addr)
+	if err != nil {
+		return nil, err
+	}
+	return &airplayRenderer{device: d, host: host, port: port, volume: 100}, nil
+}
+
+func (r *airplayRenderer) Name() string {
+	return r.device.Name
+}
+
+func (r *airplayRenderer) Play(url string) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.stop()
+	return r.start(url)
+}
+
+func (r *airplayRenderer) start(url string) error {
+	log := slog.With("method", "cast.airplayRenderer.start")
+	ctx, cancel := context.WithCancel(context.Background())
+	dec := exec.CommandContext(ctx, decoderBin,
+		"-hide_banner", "-loglevel", "error",
+		"-i", url,
+		"-f", "s16le", "-ar", "44100", "-ac", "2", "-",
+	)
+	send := exec.CommandContext(ctx, raopSenderBin,
+		"-p", r.port,
+		"-v", strconv.Itoa(r.volume),
+		r.host, "-",
+	)
+	for _, cmd := range []*exec.Cmd{dec, send} {
+		if errors.Is(cmd.Err, exec.ErrDot) {
+			cmd.Err = nil
+		}
+	}
+	pipe, err := dec.StdoutPipe()
+	if err != nil {
+		cancel()
+		return err
+	}
+	send.Stdin = pipe
+	if err := dec.Start(); err != nil {
+		cancel()
+		return err
+	}
+	if err := send.Start(); err != nil {
+		cancel()
+		dec.Wait()
+		return err
+	}
+	log.Info("started", "device", r.device.Name, "url", url, "decoder", dec.Process.Pid, "sender", send.Process.Pid)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := send.Wait(); err != nil && ctx.Err() == nil {
+			log.Error("sender exited", "device", r.device.Name, "error", err)
+		}
+		cancel()
+		dec.Wait()
+	}()
+	r.url = url
+	r.playing = cancel
+	r.done = done
+	return nil
+}
+
+func (r *airplayRenderer) stop() {
+	if r.playing == nil {
+		return
+	}
+	r.playing()
+	<-r.done
+	r.playing = nil
+	r.done = nil
+}
+
+func (r *airplayRenderer) Pause(value bool) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if value {
+		r.stop()
+		return nil
+	}
+	if r.playing != nil || r.url == "" {
+		return nil
+	}
+	return r.start(r.url)
+}
+
+func (r *airplayRenderer) Stop() error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.stop()
+	r.url = ""
+	return nil
+}
+
+func (r *airplayRenderer) SetVolume(value int) (int, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.playing == nil {
+		r.volume = value
+	}
+	return r.volume, nil
+}
+
+// Close stops the playback, which depends on the pipeline of this process.
+func (r *airplayRenderer) Close() error {
+	return r.Stop()
+}
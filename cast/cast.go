@@ -0,0 +1,121 @@
+// Package cast sends streams to the Chromecast, AirPlay and DLNA/UPnP renderers on the local network.
+package cast
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Kind uint8
+
+const (
+	Chromecast Kind = iota
+	DLNA
+	AirPlay
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Chromecast:
+		return "Chromecast"
+	case DLNA:
+		return "DLNA"
+	case AirPlay:
+		return "AirPlay"
+	}
+	return "unknown Kind"
+}
+
+// Device is a renderer found on the network.
+type Device struct {
+	Name string
+	Kind Kind
+	// Addr is the host:port of a Chromecast or AirPlay device, or the description url of a DLNA renderer.
+	Addr string
+
+	// control urls of a DLNA renderer
+	avTransport      string
+	renderingControl string
+}
+
+// Renderer plays a stream on a device, instead of the local player.
+type Renderer interface {
+	Name() string
+	Play(url string) error
+	Pause(value bool) error
+	Stop() error
+	SetVolume(value int) (int, error)
+	Close() error
+}
+
+var errNotFound = errors.New("no renderers found")
+
+// Discover searches the network for Chromecasts, AirPlay devices and DLNA renderers until ctx is done.
+// The devices are sorted by name.
+func Discover(ctx context.Context) ([]Device, error) {
+	log := slog.With("method", "cast.Discover")
+
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	var devices []Device
+	var errs []error
+	for _, discover := range []func(context.Context) ([]Device, error){discoverMdns, discoverRenderers} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := discover(ctx)
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil {
+				log.Error("", "error", err)
+				errs = append(errs, err)
+			}
+			devices = append(devices, res...)
+		}()
+	}
+	wg.Wait()
+
+	if len(devices) == 0 {
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+		return nil, errNotFound
+	}
+	slices.SortFunc(devices, func(a, b Device) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return devices, nil
+}
+
+// Connect returns the renderer for a discovered device.
+func Connect(ctx context.Context, d Device) (Renderer, error) {
+	switch d.Kind {
+	case Chromecast:
+		return dialChromecast(ctx, d)
+	case DLNA:
+		return newDlnaRenderer(d), nil
+	case AirPlay:
+		return newAirplayRenderer(d)
+	}
+	return nil, errors.New("unknown device kind")
+}
+
+// unblockOnDone makes the reads from conn return when ctx is done.
+// The returned func must be called when conn is no longer read.
+func unblockOnDone(ctx context.Context, conn net.PacketConn) func() {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Now())
+		case <-done:
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,159 @@
+package cast
+
+import (
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const description = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <friendlyName>Living Room</friendlyName>
+    <deviceList>
+      <device>
+        <serviceList>
+          <service>
+            <serviceType>urn:schemas-upnp-org:service:RenderingControl:1</serviceType>
+            <controlURL>/rc/control</controlURL>
+          </service>
+          <service>
+            <serviceType>urn:schemas-upnp-org:service:AVTransport:1</serviceType>
+            <controlURL>avt/control</controlURL>
+          </service>
+        </serviceList>
+      </device>
+    </deviceList>
+  </device>
+</root>`
+
+func TestDlnaRenderer(t *testing.T) {
+	var actions []string
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actions = append(actions, r.URL.Path+" "+r.Header.Get("SOAPAction"))
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if strings.HasSuffix(r.Header.Get("SOAPAction"), `#Pause"`) {
+			w.WriteHeader(http.StatusInternalServerError)
+			io.WriteString(w, `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><s:Fault>
+<detail><UPnPError xmlns="urn:schemas-upnp-org:control-1-0"><errorCode>701</errorCode><errorDescription>Transition not available</errorDescription></UPnPError></detail>
+</s:Fault></s:Body></s:Envelope>`)
+		}
+	}))
+	defer srv.Close()
+
+	d, err := parseDescription(srv.URL+"/desc/device.xml", []byte(description))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name != "Living Room" || d.avTransport != srv.URL+"/desc/avt/control" || d.renderingControl != srv.URL+"/rc/control" {
+		t.Fatalf("device %+v", d)
+	}
+
+	r := newDlnaRenderer(d)
+	if err := r.Play("http://radio.example/stream?a=1&b=2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.SetVolume(40); err != nil {
+		t.Fatal(err)
+	}
+	err = r.Pause(true)
+	if err == nil || !strings.Contains(err.Error(), "701") {
+		t.Errorf("pause error %v", err)
+	}
+
+	want := []string{
+		`/desc/avt/control "urn:schemas-upnp-org:service:AVTransport:1#SetAVTransportURI"`,
+		`/desc/avt/control "urn:schemas-upnp-org:service:AVTransport:1#Play"`,
+		`/rc/control "urn:schemas-upnp-org:service:RenderingControl:1#SetVolume"`,
+		`/desc/avt/control "urn:schemas-upnp-org:service:AVTransport:1#Pause"`,
+	}
+	if strings.Join(actions, "\n") != strings.Join(want, "\n") {
+		t.Errorf("actions\n%s", strings.Join(actions, "\n"))
+	}
+	if !strings.Contains(bodies[0], "<CurrentURI>http://radio.example/stream?a=1&amp;b=2</CurrentURI>") {
+		t.Errorf("SetAVTransportURI body %s", bodies[0])
+	}
+	if !strings.Contains(bodies[2], "<DesiredVolume>40</DesiredVolume>") {
+		t.Errorf("SetVolume body %s", bodies[2])
+	}
+}
+
+func TestCastMessage(t *testing.T) {
+	msg := castMessage{source: senderID, destination: receiverID, namespace: nsReceiver, payload: `{"type":"GET_STATUS"}`}
+	got, err := unmarshalCastMessage(msg.marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != msg {
+		t.Errorf("got %+v", got)
+	}
+}
+
+// appendName writes the name as labels, or as a pointer to ptr if not 0.
+func appendName(b []byte, name string, ptr int) []byte {
+	if ptr != 0 {
+		return binary.BigEndian.AppendUint16(b, uint16(0xC000|ptr))
+	}
+	for _, l := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		b = append(b, byte(len(l)))
+		b = append(b, l...)
+	}
+	return append(b, 0)
+}
+
+func appendRecord(b []byte, name []byte, typ uint16, data []byte) []byte {
+	b = append(b, name...)
+	b = binary.BigEndian.AppendUint16(b, typ)
+	b = binary.BigEndian.AppendUint16(b, dnsClassIN)
+	b = binary.BigEndian.AppendUint32(b, 120)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(data)))
+	return append(b, data...)
+}
+
+func TestParseMdnsResponse(t *testing.T) {
+	msg := make([]byte, dnsHeaderLen)
+	binary.BigEndian.PutUint16(msg[6:], 2)  // answers
+	binary.BigEndian.PutUint16(msg[10:], 4) // additional
+
+	serviceOff := len(msg)
+	instance := "Chromecast-abc." + googlecastName
+	msg = appendRecord(msg, appendName(nil, googlecastName, 0), dnsTypePTR, appendName(nil, instance, 0))
+	instanceOff := len(msg) - len(appendName(nil, instance, 0))
+	raopInstance := "AABBCCDDEEFF@Bedroom." + raopName
+	msg = appendRecord(msg, appendName(nil, raopName, 0), dnsTypePTR, appendName(nil, raopInstance, 0))
+	raopOff := len(msg) - len(appendName(nil, raopInstance, 0))
+
+	txt := []byte("\x05id=ab\x12fn=Kitchen Speaker")
+	msg = appendRecord(msg, appendName(nil, "", instanceOff), dnsTypeTXT, txt)
+
+	srv := []byte{0, 0, 0, 0, 0x1F, 0x49} // port 8009
+	srv = appendName(srv, "abc.local.", 0)
+	msg = appendRecord(msg, appendName(nil, "", instanceOff), dnsTypeSRV, srv)
+	raopSrv := []byte{0, 0, 0, 0, 0x1B, 0x58} // port 7000
+	raopSrv = appendName(raopSrv, "abc.local.", 0)
+	msg = appendRecord(msg, appendName(nil, "", raopOff), dnsTypeSRV, raopSrv)
+
+	msg = appendRecord(msg, appendName(nil, "abc.local.", 0), dnsTypeA, []byte{192, 168, 1, 20})
+
+	if name, _, err := readName(msg, serviceOff); err != nil || name != googlecastName {
+		t.Fatalf("readName = %q, %v", name, err)
+	}
+	devices, err := parseMdnsResponse(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.SortFunc(devices, func(a, b Device) int { return int(a.Kind) - int(b.Kind) })
+	want := []Device{
+		{Name: "Kitchen Speaker", Kind: Chromecast, Addr: "192.168.1.20:8009"},
+		{Name: "Bedroom", Kind: AirPlay, Addr: "192.168.1.20:7000"},
+	}
+	if !slices.Equal(devices, want) {
+		t.Errorf("devices %+v", devices)
+	}
+}
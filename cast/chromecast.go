@@ -0,0 +1,390 @@
+package cast
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	nsConnection = "urn:x-cast:com.google.cast.tp.connection"
+	nsHeartbeat  = "urn:x-cast:com.google.cast.tp.heartbeat"
+	nsReceiver   = "urn:x-cast:com.google.cast.receiver"
+	nsMedia      = "urn:x-cast:com.google.cast.media"
+
+	senderID   = "sender-0"
+	receiverID = "receiver-0"
+	// defaultMediaReceiver is the app that plays media urls on a Chromecast
+	defaultMediaReceiver = "CC1AD845"
+
+	maxCastMessageLen = 64 * 1024
+	heartbeatInterval = 5 * time.Second
+)
+
+// castMessage is the CastMessage protobuf of the Cast v2 protocol, with a text payload.
+type castMessage struct {
+	source      string
+	destination string
+	namespace   string
+	payload     string
+}
+
+// marshal encodes the protobuf fields, including the required protocol version and payload type, which are 0.
+func (m castMessage) marshal() []byte {
+	var b []byte
+	b = append(b, 1<<3|0, 0) // protocol_version CASTV2_1_0
+	b = appendProtoString(b, 2, m.source)
+	b = appendProtoString(b, 3, m.destination)
+	b = appendProtoString(b, 4, m.namespace)
+	b = append(b, 5<<3|0, 0) // payload_type STRING
+	b = appendProtoString(b, 6, m.payload)
+	return b
+}
+
+func appendProtoString(b []byte, field int, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(field<<3|2))
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func unmarshalCastMessage(b []byte) (castMessage, error) {
+	var m castMessage
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return m, errors.New("invalid cast message")
+		}
+		b = b[n:]
+		field, wireType := key>>3, key&7
+		switch wireType {
+		case 0:
+			_, n := binary.Uvarint(b)
+			if n <= 0 {
+				return m, errors.New("invalid cast message")
+			}
+			b = b[n:]
+		case 2:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return m, errors.New("invalid cast message")
+			}
+			v := string(b[n : n+int(l)])
+			b = b[n+int(l):]
+			switch field {
+			case 2:
+				m.source = v
+			case 3:
+				m.destination = v
+			case 4:
+				m.namespace = v
+			case 6:
+				m.payload = v
+			}
+		default:
+			return m, fmt.Errorf("unexpected cast message wire type %d", wireType)
+		}
+	}
+	return m, nil
+}
+
+// castPayload has the fields of the receiver and media messages that are used.
+type castPayload struct {
+	Type      string          `json:"type"`
+	RequestID int             `json:"requestId,omitempty"`
+	Reason    string          `json:"reason,omitempty"`
+	Status    json.RawMessage `json:"status,omitempty"`
+}
+
+type receiverStatus struct {
+	Applications []struct {
+		AppID       string `json:"appId"`
+		TransportID string `json:"transportId"`
+	} `json:"applications"`
+}
+
+type mediaStatus []struct {
+	MediaSessionID int `json:"mediaSessionId"`
+}
+
+// chromecast plays the stream with the default media receiver app of a Chromecast.
+type chromecast struct {
+	name string
+	conn net.Conn
+
+	writeMtx sync.Mutex
+
+	mtx       sync.Mutex
+	requestID int
+	pending   map[int]chan castPayload
+	// transport is the connection id of the launched receiver app
+	transport      string
+	mediaSessionID int
+
+	done chan struct{}
+}
+
+func dialChromecast(ctx context.Context, d Device) (*chromecast, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: config.CastReqTimeout},
+		// Chromecasts use self-signed certificates
+		Config: &tls.Config{InsecureSkipVerify: true},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", d.Addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &chromecast{
+		name:    d.Name,
+		conn:    conn,
+		pending: make(map[int]chan castPayload),
+		done:    make(chan struct{}),
+	}
+	go c.read()
+	go c.heartbeat()
+	if err := c.send(receiverID, nsConnection, map[string]any{"type": "CONNECT"}); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *chromecast) Name() string {
+	return c.name
+}
+
+func (c *chromecast) Play(url string) error {
+	transport, err := c.launch()
+	if err != nil {
+		return err
+	}
+	res, err := c.request(transport, nsMedia, map[string]any{
+		"type": "LOAD",
+		"media": map[string]any{
+			"contentId":   url,
+			"contentType": "audio/mpeg",
+			"streamType":  "LIVE",
+		},
+		"autoplay": true,
+	})
+	if err != nil {
+		return err
+	}
+	if res.Type != "MEDIA_STATUS" {
+		return fmt.Errorf("load %s: %s %s", url, res.Type, res.Reason)
+	}
+	var status mediaStatus
+	if err := json.Unmarshal(res.Status, &status); err != nil || len(status) == 0 {
+		return fmt.Errorf("load %s: no media session", url)
+	}
+	c.mtx.Lock()
+	c.mediaSessionID = status[0].MediaSessionID
+	c.mtx.Unlock()
+	return nil
+}
+
+// launch starts the default media receiver, unless already running, and returns its transport id.
+func (c *chromecast) launch() (string, error) {
+	c.mtx.Lock()
+	transport := c.transport
+	c.mtx.Unlock()
+	if transport != "" {
+		return transport, nil
+	}
+
+	res, err := c.request(receiverID, nsReceiver, map[string]any{"type": "LAUNCH", "appId": defaultMediaReceiver})
+	if err != nil {
+		return "", err
+	}
+	var status receiverStatus
+	if res.Type == "RECEIVER_STATUS" {
+		json.Unmarshal(res.Status, &status)
+	}
+	for _, app := range status.Applications {
+		if app.AppID != defaultMediaReceiver {
+			continue
+		}
+		if err := c.send(app.TransportID, nsConnection, map[string]any{"type": "CONNECT"}); err != nil {
+			return "", err
+		}
+		c.mtx.Lock()
+		c.transport = app.TransportID
+		c.mtx.Unlock()
+		return app.TransportID, nil
+	}
+	return "", fmt.Errorf("launch media receiver: %s %s", res.Type, res.Reason)
+}
+
+func (c *chromecast) Pause(value bool) error {
+	if value {
+		return c.mediaCmd("PAUSE")
+	}
+	return c.mediaCmd("PLAY")
+}
+
+func (c *chromecast) Stop() error {
+	return c.mediaCmd("STOP")
+}
+
+func (c *chromecast) mediaCmd(typ string) error {
+	c.mtx.Lock()
+	transport, mediaSessionID := c.transport, c.mediaSessionID
+	c.mtx.Unlock()
+	if transport == "" || mediaSessionID == 0 {
+		return nil
+	}
+	res, err := c.request(transport, nsMedia, map[string]any{"type": typ, "mediaSessionId": mediaSessionID})
+	if err != nil {
+		return err
+	}
+	if res.Type != "MEDIA_STATUS" {
+		return fmt.Errorf("%s: %s %s", typ, res.Type, res.Reason)
+	}
+	return nil
+}
+
+func (c *chromecast) SetVolume(value int) (int, error) {
+	_, err := c.request(receiverID, nsReceiver, map[string]any{
+		"type":   "SET_VOLUME",
+		"volume": map[string]any{"level": float64(value) / 100},
+	})
+	return value, err
+}
+
+// Close disconnects from the Chromecast, which keeps playing.
+func (c *chromecast) Close() error {
+	select {
+	case <-c.done:
+	default:
+		c.mtx.Lock()
+		transport := c.transport
+		c.mtx.Unlock()
+		if transport != "" {
+			c.send(transport, nsConnection, map[string]any{"type": "CLOSE"})
+		}
+		c.send(receiverID, nsConnection, map[string]any{"type": "CLOSE"})
+	}
+	return c.conn.Close()
+}
+
+// request sends the payload with a new request id and waits for the response with the same id.
+func (c *chromecast) request(destination, namespace string, payload map[string]any) (castPayload, error) {
+	c.mtx.Lock()
+	c.requestID++
+	id := c.requestID
+	ch := make(chan castPayload, 1)
+	c.pending[id] = ch
+	c.mtx.Unlock()
+	defer func() {
+		c.mtx.Lock()
+		delete(c.pending, id)
+		c.mtx.Unlock()
+	}()
+
+	payload["requestId"] = id
+	if err := c.send(destination, namespace, payload); err != nil {
+		return castPayload{}, err
+	}
+	select {
+	case res := <-ch:
+		return res, nil
+	case <-c.done:
+		return castPayload{}, errors.New("chromecast connection closed")
+	case <-time.After(config.CastReqTimeout):
+		return castPayload{}, fmt.Errorf("%v request timed out", payload["type"])
+	}
+}
+
+func (c *chromecast) send(destination, namespace string, payload map[string]any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	msg := castMessage{source: senderID, destination: destination, namespace: namespace, payload: string(b)}.marshal()
+
+	c.writeMtx.Lock()
+	defer c.writeMtx.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(config.CastReqTimeout))
+	if err := binary.Write(c.conn, binary.BigEndian, uint32(len(msg))); err != nil {
+		return err
+	}
+	_, err = c.conn.Write(msg)
+	return err
+}
+
+// read answers the pings of the Chromecast and hands the responses to the pending requests.
+func (c *chromecast) read() {
+	log := slog.With("method", "cast.chromecast.read")
+	defer close(c.done)
+	for {
+		var length uint32
+		if err := binary.Read(c.conn, binary.BigEndian, &length); err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Error("", "error", err)
+			}
+			return
+		}
+		if length > maxCastMessageLen {
+			log.Error("message too long", "length", length)
+			return
+		}
+		b := make([]byte, length)
+		if _, err := io.ReadFull(c.conn, b); err != nil {
+			log.Error("", "error", err)
+			return
+		}
+		msg, err := unmarshalCastMessage(b)
+		if err != nil {
+			log.Error("", "error", err)
+			continue
+		}
+		var payload castPayload
+		if err := json.Unmarshal([]byte(msg.payload), &payload); err != nil {
+			continue
+		}
+		switch {
+		case msg.namespace == nsHeartbeat && payload.Type == "PING":
+			c.send(msg.source, nsHeartbeat, map[string]any{"type": "PONG"})
+		case msg.namespace == nsConnection && payload.Type == "CLOSE":
+			// the receiver app was stopped, e.g. from another sender
+			c.mtx.Lock()
+			if msg.source == c.transport {
+				c.transport, c.mediaSessionID = "", 0
+			}
+			c.mtx.Unlock()
+		case payload.RequestID != 0:
+			c.mtx.Lock()
+			ch, ok := c.pending[payload.RequestID]
+			c.mtx.Unlock()
+			if ok {
+				select {
+				case ch <- payload:
+				default:
+				}
+			}
+		}
+	}
+}
+
+// heartbeat keeps the connection open, the Chromecast closes it when it doesn't hear from the sender.
+func (c *chromecast) heartbeat() {
+	t := time.NewTicker(heartbeatInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-t.C:
+			c.send(receiverID, nsHeartbeat, map[string]any{"type": "PING"})
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package cast
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	ssdpAddr         = "239.255.255.250:1900"
+	mediaRendererURN = "urn:schemas-upnp-org:device:MediaRenderer:1"
+	avTransportURN   = "urn:schemas-upnp-org:service:AVTransport:1"
+	renderingCtrlURN = "urn:schemas-upnp-org:service:RenderingControl:1"
+)
+
+// discoverRenderers sends an SSDP search for media renderers and reads their device descriptions.
+func discoverRenderers(ctx context.Context) ([]Device, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	defer unblockOnDone(ctx, conn)()
+
+	dst, err := net.ResolveUDPAddr("udp4", ssdpAddr)
+	if err != nil {
+		return nil, err
+	}
+	search := "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: " + ssdpAddr + "\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 2\r\n" +
+		"ST: " + mediaRendererURN + "\r\n\r\n"
+	if _, err := conn.WriteTo([]byte(search), dst); err != nil {
+		return nil, err
+	}
+
+	locations := make(map[string]struct{})
+	buf := make([]byte, 4096)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+		res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buf[:n])), nil)
+		if err != nil {
+			continue
+		}
+		res.Body.Close()
+		if loc := res.Header.Get("Location"); loc != "" {
+			locations[loc] = struct{}{}
+		}
+	}
+
+	log := slog.With("method", "cast.discoverRenderers")
+	var devices []Device
+	for loc := range locations {
+		d, err := describeRenderer(loc)
+		if err != nil {
+			log.Error("device description", "location", loc, "error", err)
+			continue
+		}
+		devices = append(devices, d)
+	}
+	return devices, nil
+}
+
+type upnpDevice struct {
+	FriendlyName string        `xml:"friendlyName"`
+	Services     []upnpService `xml:"serviceList>service"`
+	Devices      []upnpDevice  `xml:"deviceList>device"`
+}
+
+type upnpService struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+// describeRenderer reads the name and the control urls from the device description at location.
+func describeRenderer(location string) (Device, error) {
+	client := &http.Client{Timeout: config.CastReqTimeout}
+	res, err := client.Get(location)
+	if err != nil {
+		return Device{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Device{}, fmt.Errorf("description response status %s", res.Status)
+	}
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return Device{}, err
+	}
+	return parseDescription(location, b)
+}
+
+func parseDescription(location string, b []byte) (Device, error) {
+	var root struct {
+		URLBase string     `xml:"URLBase"`
+		Device  upnpDevice `xml:"device"`
+	}
+	if err := xml.Unmarshal(b, &root); err != nil {
+		return Device{}, err
+	}
+	base, err := url.Parse(location)
+	if err != nil {
+		return Device{}, err
+	}
+	if root.URLBase != "" {
+		if u, err := url.Parse(root.URLBase); err == nil {
+			base = u
+		}
+	}
+
+	d := Device{Name: root.Device.FriendlyName, Kind: DLNA, Addr: location}
+	var find func(dev upnpDevice)
+	find = func(dev upnpDevice) {
+		for _, s := range dev.Services {
+			u, err := base.Parse(strings.TrimSpace(s.ControlURL))
+			if err != nil {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(s.ServiceType, "urn:schemas-upnp-org:service:AVTransport:") && d.avTransport == "":
+				d.avTransport = u.String()
+			case strings.HasPrefix(s.ServiceType, "urn:schemas-upnp-org:service:RenderingControl:") && d.renderingControl == "":
+				d.renderingControl = u.String()
+			}
+		}
+		for _, sub := range dev.Devices {
+			find(sub)
+		}
+	}
+	find(root.Device)
+	if d.avTransport == "" {
+		return Device{}, fmt.Errorf("%s has no AVTransport service", d.Name)
+	}
+	if d.Name == "" {
+		d.Name = base.Host
+	}
+	return d, nil
+}
+
+// dlnaRenderer controls a UPnP media renderer with SOAP actions.
+type dlnaRenderer struct {
+	device Device
+	client *http.Client
+}
+
+func newDlnaRenderer(d Device) *dlnaRenderer {
+	return &dlnaRenderer{device: d, client: &http.Client{Timeout: config.CastReqTimeout}}
+}
+
+func (r *dlnaRenderer) Name() string {
+	return r.device.Name
+}
+
+func (r *dlnaRenderer) Play(url string) error {
+	err := r.action(r.device.avTransport, avTransportURN, "SetAVTransportURI",
+		"InstanceID", "0",
+		"CurrentURI", url,
+		"CurrentURIMetaData", didlMetadata(url),
+	)
+	if err != nil {
+		return err
+	}
+	return r.action(r.device.avTransport, avTransportURN, "Play", "InstanceID", "0", "Speed", "1")
+}
+
+func (r *dlnaRenderer) Pause(value bool) error {
+	if value {
+		return r.action(r.device.avTransport, avTransportURN, "Pause", "InstanceID", "0")
+	}
+	return r.action(r.device.avTransport, avTransportURN, "Play", "InstanceID", "0", "Speed", "1")
+}
+
+func (r *dlnaRenderer) Stop() error {
+	return r.action(r.device.avTransport, avTransportURN, "Stop", "InstanceID", "0")
+}
+
+func (r *dlnaRenderer) SetVolume(value int) (int, error) {
+	if r.device.renderingControl == "" {
+		return 0, fmt.Errorf("%s has no volume control", r.device.Name)
+	}
+	err := r.action(r.device.renderingControl, renderingCtrlURN, "SetVolume",
+		"InstanceID", "0",
+		"Channel", "Master",
+		"DesiredVolume", fmt.Sprint(value),
+	)
+	return value, err
+}
+
+func (r *dlnaRenderer) Close() error {
+	return nil
+}
+
+// action calls a SOAP action with the argument names and values in order.
+func (r *dlnaRenderer) action(controlURL, service, name string, args ...string) error {
+	var body bytes.Buffer
+	body.WriteString(`<?xml version="1.0" encoding="utf-8"?>`)
+	body.WriteString(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	fmt.Fprintf(&body, `<u:%s xmlns:u="%s">`, name, service)
+	for i := 0; i+1 < len(args); i += 2 {
+		fmt.Fprintf(&body, "<%s>", args[i])
+		xml.EscapeText(&body, []byte(args[i+1]))
+		fmt.Fprintf(&body, "</%s>", args[i])
+	}
+	fmt.Fprintf(&body, "</u:%s></s:Body></s:Envelope>", name)
+
+	req, err := http.NewRequest(http.MethodPost, controlURL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", fmt.Sprintf(`"%s#%s"`, service, name))
+	res, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	b, _ := io.ReadAll(res.Body)
+	var fault struct {
+		Code        string `xml:"Body>Fault>detail>UPnPError>errorCode"`
+		Description string `xml:"Body>Fault>detail>UPnPError>errorDescription"`
+	}
+	if err := xml.Unmarshal(b, &fault); err == nil && fault.Code != "" {
+		return fmt.Errorf("%s: upnp error %s %s", name, fault.Code, fault.Description)
+	}
+	return fmt.Errorf("%s response status %s", name, res.Status)
+}
+
+// didlMetadata describes the stream as a broadcast, which some renderers require before playing a url.
+func didlMetadata(url string) string {
+	var u bytes.Buffer
+	xml.EscapeText(&u, []byte(url))
+	return `<DIDL-Lite xmlns="urn:schemas-upnp-org:metadata-1-0/DIDL-Lite/" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:upnp="urn:schemas-upnp-org:metadata-1-0/upnp/">` +
+		`<item id="0" parentID="-1" restricted="1"><dc:title>sonicradio</dc:title>` +
+		`<upnp:class>object.item.audioItem.audioBroadcast</upnp:class>` +
+		`<res protocolInfo="http-get:*:audio/mpeg:*">` + u.String() + `</res></item></DIDL-Lite>`
+}
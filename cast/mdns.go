@@ -0,0 +1,250 @@
+package cast
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	mdnsAddr        = "224.0.0.251:5353"
+	googlecastName  = "_googlecast._tcp.local."
+	raopName        = "_raop._tcp.local."
+	dnsTypeA        = 1
+	dnsTypePTR      = 12
+	dnsTypeTXT      = 16
+	dnsTypeSRV      = 33
+	dnsClassIN      = 1
+	dnsHeaderLen    = 12
+	maxNamePointers = 16
+)
+
+var errDNSMessage = errors.New("invalid dns message")
+
+// mdnsServices are the services of the devices found with mDNS.
+var mdnsServices = map[string]Kind{
+	googlecastName: Chromecast,
+	raopName:       AirPlay,
+}
+
+var defPorts = map[Kind]int{
+	Chromecast: 8009,
+	AirPlay:    5000,
+}
+
+// discoverMdns sends an mDNS query for the Google Cast and AirPlay services, answered by each device on the network.
+// AirPlay devices are skipped when the programs that send them the stream are not installed.
+func discoverMdns(ctx context.Context) ([]Device, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	defer unblockOnDone(ctx, conn)()
+
+	dst, err := net.ResolveUDPAddr("udp4", mdnsAddr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteTo(mdnsQuery(googlecastName, raopName), dst); err != nil {
+		return nil, err
+	}
+
+	airplay := airplayAvailable()
+	found := make(map[string]Device)
+	buf := make([]byte, 9000)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+		devices, err := parseMdnsResponse(buf[:n])
+		if err != nil {
+			continue
+		}
+		for _, d := range devices {
+			if d.Kind == AirPlay && !airplay {
+				continue
+			}
+			found[d.Kind.String()+d.Addr] = d
+		}
+	}
+
+	var devices []Device
+	for _, d := range found {
+		devices = append(devices, d)
+	}
+	return devices, nil
+}
+
+// mdnsQuery is a one-shot query for the PTR records of the services, answered with unicast.
+func mdnsQuery(services ...string) []byte {
+	b := make([]byte, dnsHeaderLen, 128)
+	binary.BigEndian.PutUint16(b[4:], uint16(len(services))) // questions
+	for _, service := range services {
+		for _, label := range strings.Split(strings.TrimSuffix(service, "."), ".") {
+			b = append(b, byte(len(label)))
+			b = append(b, label...)
+		}
+		b = append(b, 0)
+		b = binary.BigEndian.AppendUint16(b, dnsTypePTR)
+		b = binary.BigEndian.AppendUint16(b, dnsClassIN)
+	}
+	return b
+}
+
+type dnsRecord struct {
+	name  string
+	typ   uint16
+	data  []byte
+	start int // offset of data in the message, for names compressed in the data
+}
+
+// parseMdnsResponse builds the devices from the PTR, SRV, TXT and A records of an mDNS response.
+func parseMdnsResponse(msg []byte) ([]Device, error) {
+	if len(msg) < dnsHeaderLen {
+		return nil, errDNSMessage
+	}
+	questions := int(binary.BigEndian.Uint16(msg[4:]))
+	records := int(binary.BigEndian.Uint16(msg[6:])) + int(binary.BigEndian.Uint16(msg[8:])) + int(binary.BigEndian.Uint16(msg[10:]))
+
+	off := dnsHeaderLen
+	for range questions {
+		_, n, err := readName(msg, off)
+		if err != nil {
+			return nil, err
+		}
+		off = n + 4
+	}
+
+	instances := make(map[string]Kind)
+	type srv struct {
+		target string
+		port   int
+	}
+	srvs := make(map[string]srv)
+	names := make(map[string]string)
+	addrs := make(map[string]net.IP)
+	for range records {
+		r, n, err := readRecord(msg, off)
+		if err != nil {
+			return nil, err
+		}
+		off = n
+		switch r.typ {
+		case dnsTypePTR:
+			kind, ok := mdnsServices[strings.ToLower(r.name)]
+			if !ok {
+				continue
+			}
+			instance, _, err := readName(msg, r.start)
+			if err != nil {
+				return nil, err
+			}
+			instances[instance] = kind
+		case dnsTypeSRV:
+			if len(r.data) < 7 {
+				return nil, errDNSMessage
+			}
+			target, _, err := readName(msg, r.start+6)
+			if err != nil {
+				return nil, err
+			}
+			srvs[r.name] = srv{target: target, port: int(binary.BigEndian.Uint16(r.data[4:]))}
+		case dnsTypeTXT:
+			for d := r.data; len(d) > 0 && int(d[0]) < len(d); d = d[1+d[0]:] {
+				if v, ok := strings.CutPrefix(string(d[1:1+d[0]]), "fn="); ok {
+					names[r.name] = v
+				}
+			}
+		case dnsTypeA:
+			if len(r.data) == 4 {
+				addrs[r.name] = net.IP(r.data)
+			}
+		}
+	}
+
+	var devices []Device
+	for instance, kind := range instances {
+		s, ok := srvs[instance]
+		if !ok {
+			continue
+		}
+		ip, ok := addrs[s.target]
+		if !ok {
+			continue
+		}
+		port := s.port
+		if port == 0 {
+			port = defPorts[kind]
+		}
+		name := names[instance]
+		if name == "" {
+			name, _, _ = strings.Cut(instance, ".")
+			// AirPlay instances are named MAC@name
+			if _, after, ok := strings.Cut(name, "@"); ok {
+				name = after
+			}
+		}
+		devices = append(devices, Device{
+			Name: name,
+			Kind: kind,
+			Addr: net.JoinHostPort(ip.String(), strconv.Itoa(port)),
+		})
+	}
+	return devices, nil
+}
+
+func readRecord(msg []byte, off int) (dnsRecord, int, error) {
+	name, off, err := readName(msg, off)
+	if err != nil {
+		return dnsRecord{}, 0, err
+	}
+	if off+10 > len(msg) {
+		return dnsRecord{}, 0, errDNSMessage
+	}
+	typ := binary.BigEndian.Uint16(msg[off:])
+	length := int(binary.BigEndian.Uint16(msg[off+8:]))
+	start := off + 10
+	if start+length > len(msg) {
+		return dnsRecord{}, 0, errDNSMessage
+	}
+	return dnsRecord{name: name, typ: typ, data: msg[start : start+length], start: start}, start + length, nil
+}
+
+// readName returns the name at off, following the compression pointers, and the offset after it.
+func readName(msg []byte, off int) (string, int, error) {
+	var labels []string
+	end := -1
+	for pointers := 0; ; {
+		if off >= len(msg) {
+			return "", 0, errDNSMessage
+		}
+		l := int(msg[off])
+		switch {
+		case l == 0:
+			if end < 0 {
+				end = off + 1
+			}
+			return strings.Join(labels, ".") + ".", end, nil
+		case l&0xC0 == 0xC0:
+			if off+1 >= len(msg) || pointers == maxNamePointers {
+				return "", 0, errDNSMessage
+			}
+			if end < 0 {
+				end = off + 2
+			}
+			off = int(binary.BigEndian.Uint16(msg[off:]) & 0x3FFF)
+			pointers++
+		default:
+			if off+1+l > len(msg) {
+				return "", 0, errDNSMessage
+			}
+			labels = append(labels, string(msg[off+1:off+1+l]))
+			off += 1 + l
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// StationActivity is what the user did with a station, counted locally: the plays, which radio-browser
+// counts as clicks, and the votes.
+type StationActivity struct {
+	Clicks    int       `json:"clicks,omitempty"`
+	LastClick time.Time `json:"lastClick"`
+	Votes     int       `json:"votes,omitempty"`
+	LastVote  time.Time `json:"lastVote"`
+}
+
+// ClicksString describes the plays of the station, for the station info.
+func (a StationActivity) ClicksString() string {
+	return activityString(a.Clicks, a.LastClick)
+}
+
+// VotesString describes the votes for the station, for the station info.
+func (a StationActivity) VotesString() string {
+	return activityString(a.Votes, a.LastVote)
+}
+
+func activityString(count int, last time.Time) string {
+	if count == 0 {
+		return "never"
+	}
+	return fmt.Sprintf("%d, last at %s", count, last.Format(tsFormat))
+}
+
+// Activity returns what the user did with the station.
+func (v *Value) Activity(uuid string) StationActivity {
+	v.activityMtx.Lock()
+	defer v.activityMtx.Unlock()
+	return v.Activities[uuid]
+}
+
+// AddClick records a play of the station.
+func (v *Value) AddClick(uuid string) {
+	v.updateActivity(uuid, func(a *StationActivity) {
+		a.Clicks++
+		a.LastClick = time.Now()
+	})
+}
+
+// AddVote records a vote for the station.
+func (v *Value) AddVote(uuid string) {
+	v.updateActivity(uuid, func(a *StationActivity) {
+		a.Votes++
+		a.LastVote = time.Now()
+	})
+}
+
+func (v *Value) updateActivity(uuid string, update func(a *StationActivity)) {
+	v.activityMtx.Lock()
+	defer v.activityMtx.Unlock()
+	if v.Activities == nil {
+		v.Activities = make(map[string]StationActivity)
+	}
+	a := v.Activities[uuid]
+	update(&a)
+	v.Activities[uuid] = a
+}
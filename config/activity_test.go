@@ -0,0 +1,27 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestActivity(t *testing.T) {
+	v := &Value{}
+	if a := v.Activity("1"); a.ClicksString() != "never" || a.VotesString() != "never" {
+		t.Errorf("no activity %+v", a)
+	}
+	v.AddClick("1")
+	v.AddClick("1")
+	v.AddVote("1")
+	v.AddClick("2")
+	a := v.Activity("1")
+	if a.Clicks != 2 || a.Votes != 1 || a.LastClick.IsZero() || a.LastVote.IsZero() {
+		t.Errorf("activity %+v", a)
+	}
+	if !strings.HasPrefix(a.ClicksString(), "2, last at ") {
+		t.Errorf("clicks %q", a.ClicksString())
+	}
+	if v.Activity("2").Votes != 0 {
+		t.Errorf("votes of another station %+v", v.Activity("2"))
+	}
+}
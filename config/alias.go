@@ -0,0 +1,35 @@
+package config
+
+import (
+	"cmp"
+	"strings"
+)
+
+// Alias returns the display name given to the favorite, "" if it has none.
+func (v *Value) Alias(uuid string) string {
+	if !v.IsFavorite(uuid) {
+		return ""
+	}
+	return v.Aliases[uuid]
+}
+
+// StationName returns the alias of the station if it's a favorite with one, its name otherwise.
+func (v *Value) StationName(uuid, name string) string {
+	return cmp.Or(v.Alias(uuid), name)
+}
+
+// SetAlias gives a display name to the favorite, an empty alias restores the station name.
+func (v *Value) SetAlias(uuid, alias string) {
+	alias = strings.TrimSpace(alias)
+	if alias == "" {
+		delete(v.Aliases, uuid)
+		return
+	}
+	if !v.IsFavorite(uuid) {
+		return
+	}
+	if v.Aliases == nil {
+		v.Aliases = make(map[string]string)
+	}
+	v.Aliases[uuid] = alias
+}
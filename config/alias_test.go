@@ -0,0 +1,33 @@
+package config
+
+import "testing"
+
+func TestAlias(t *testing.T) {
+	cfg := &Value{Favorites: []string{"a", "b"}}
+	if name := cfg.StationName("a", "Station A"); name != "Station A" {
+		t.Errorf("no alias name %q", name)
+	}
+
+	cfg.SetAlias("a", "  A  ")
+	cfg.SetAlias("x", "X")
+	if name := cfg.StationName("a", "Station A"); name != "A" {
+		t.Errorf("alias name %q", name)
+	}
+	if cfg.Alias("x") != "" {
+		t.Error("alias of a station not in favorites")
+	}
+
+	cfg.DeleteFavorite("a")
+	if cfg.Alias("a") != "" {
+		t.Error("alias of a removed favorite")
+	}
+	cfg.InsertFavorite("a", 1)
+	if cfg.Alias("a") != "A" {
+		t.Error("alias lost after moving the favorite")
+	}
+	cfg.SetAlias("b", "B")
+	cfg.SetAlias("b", "")
+	if name := cfg.StationName("b", "Station B"); name != "Station B" || len(cfg.Aliases) != 1 {
+		t.Errorf("cleared alias name %q %v", name, cfg.Aliases)
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	backupSuffix = ".bak"
+	// limit of a file in the archive, far above any config file
+	archiveFileMax = 64 << 20
+)
+
+var errNoArchiveConfig = errors.New("no " + cfgFilename + " in the archive")
+
+// ExportState writes the application state, the config with the favorites and the history, as a
+// tar.gz archive to import on another machine.
+func ExportState(w io.Writer) error {
+	dir, err := getOrCreateConfigDir()
+	if err != nil {
+		return err
+	}
+	return exportDir(w, dir)
+}
+
+// ImportState replaces the application state with the one of the archive written by ExportState.
+// The current config is kept with the .bak suffix.
+func ImportState(r io.Reader) error {
+	dir, err := getOrCreateConfigDir()
+	if err != nil {
+		return err
+	}
+	return importDir(r, dir)
+}
+
+// isStateFile reports whether the file of the config dir is part of the state, unlike the pid file
+// of the running instance and the backups.
+func isStateFile(name string) bool {
+	return name != pidFileName && !strings.HasSuffix(name, backupSuffix)
+}
+
+func exportDir(w io.Writer, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !isStateFile(e.Name()) {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{Name: e.Name(), Mode: 0o644, Size: int64(len(b)), ModTime: time.Now()}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(b); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func importDir(r io.Reader, dir string) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+		name := hdr.Name
+		// only the files at the root of the config dir, never outside of it
+		if hdr.Typeflag != tar.TypeReg || name != filepath.Base(name) || !isStateFile(name) {
+			continue
+		}
+		if hdr.Size > archiveFileMax {
+			return fmt.Errorf("%s too large in the archive", name)
+		}
+		b, err := io.ReadAll(io.LimitReader(tr, archiveFileMax))
+		if err != nil {
+			return err
+		}
+		files[name] = b
+	}
+
+	// check the config before replacing anything
+	b, ok := files[cfgFilename]
+	if !ok {
+		return errNoArchiveConfig
+	}
+	if err := json.Unmarshal(b, &Value{}); err != nil {
+		return fmt.Errorf("%s in the archive: %w", cfgFilename, err)
+	}
+
+	for name, b := range files {
+		fp := filepath.Join(dir, name)
+		if err := os.Rename(fp, fp+backupSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err := os.WriteFile(fp, b, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStateArchive(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		cfgFilename: `{"favorites":["1","2"]}`,
+		pidFileName: "123",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(src, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dst, cfgFilename), []byte(`{"favorites":["3"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var archive bytes.Buffer
+	if err := exportDir(&archive, src); err != nil {
+		t.Fatal(err)
+	}
+	if err := importDir(bytes.NewReader(archive.Bytes()), dst); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(filepath.Join(dst, cfgFilename)); string(b) != files[cfgFilename] {
+		t.Errorf("imported config %s", b)
+	}
+	if b, _ := os.ReadFile(filepath.Join(dst, cfgFilename+backupSuffix)); string(b) != `{"favorites":["3"]}` {
+		t.Errorf("config backup %s", b)
+	}
+	if _, err := os.Stat(filepath.Join(dst, pidFileName)); err == nil {
+		t.Error("pid file imported")
+	}
+}
+
+func TestImportStateInvalid(t *testing.T) {
+	archive := func(files map[string]string) *bytes.Reader {
+		var b bytes.Buffer
+		gw := gzip.NewWriter(&b)
+		tw := tar.NewWriter(gw)
+		for name, content := range files {
+			tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))})
+			tw.Write([]byte(content))
+		}
+		tw.Close()
+		gw.Close()
+		return bytes.NewReader(b.Bytes())
+	}
+	dir := t.TempDir()
+	if err := importDir(archive(map[string]string{"other.json": "{}"}), dir); err == nil {
+		t.Error("imported without a config")
+	}
+	if err := importDir(archive(map[string]string{cfgFilename: "{"}), dir); err == nil {
+		t.Error("imported an invalid config")
+	}
+	if err := importDir(archive(map[string]string{cfgFilename: "{}", "../escape": "x"}), dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escape")); err == nil {
+		t.Error("file written outside of the config dir")
+	}
+}
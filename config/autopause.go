@@ -0,0 +1,22 @@
+package config
+
+// ResumePolicy tells when the playback paused by a lock of the session or an unplug of the headphones
+// resumes by itself.
+type ResumePolicy string
+
+const (
+	ResumeNever  ResumePolicy = ""
+	ResumeUnlock ResumePolicy = "unlock"
+	ResumeReplug ResumePolicy = "replug"
+	ResumeAlways ResumePolicy = "always"
+)
+
+// OnUnlock tells if the playback paused by the lock resumes when the session unlocks.
+func (p ResumePolicy) OnUnlock() bool {
+	return p == ResumeUnlock || p == ResumeAlways
+}
+
+// OnReplug tells if the playback paused by the unplug resumes when the headphones are plugged back.
+func (p ResumePolicy) OnReplug() bool {
+	return p == ResumeReplug || p == ResumeAlways
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	bandwidthDayFormat   = "2006-01-02"
+	bandwidthMonthFormat = "2006-01"
+	// bandwidthKeepDays is how long the daily usage is kept, enough for the current and previous month
+	bandwidthKeepDays = 62
+	// DefStreamBitrate is the bitrate in kbps assumed for the stations that don't tell theirs
+	DefStreamBitrate = 128
+	// BandwidthWarnPct of the monthly cap shows a warning
+	BandwidthWarnPct = 90
+)
+
+// StreamBytes estimates the bytes streamed at the bitrate in kbps during d.
+func StreamBytes(bitrate int64, d time.Duration) int64 {
+	if bitrate <= 0 {
+		bitrate = DefStreamBitrate
+	}
+	return bitrate * 1000 / 8 * d.Milliseconds() / 1000
+}
+
+// AddBandwidth adds the bytes streamed on the day of now, and forgets the days too old to count.
+func (v *Value) AddBandwidth(now time.Time, n int64) {
+	v.bandwidthMtx.Lock()
+	defer v.bandwidthMtx.Unlock()
+
+	if v.Bandwidth == nil {
+		v.Bandwidth = make(map[string]int64)
+	}
+	v.Bandwidth[now.Format(bandwidthDayFormat)] += n
+	oldest := now.AddDate(0, 0, -bandwidthKeepDays).Format(bandwidthDayFormat)
+	for day := range v.Bandwidth {
+		if day < oldest {
+			delete(v.Bandwidth, day)
+		}
+	}
+}
+
+// DayBandwidth returns the bytes streamed on the day of now.
+func (v *Value) DayBandwidth(now time.Time) int64 {
+	v.bandwidthMtx.Lock()
+	defer v.bandwidthMtx.Unlock()
+	return v.Bandwidth[now.Format(bandwidthDayFormat)]
+}
+
+// MonthBandwidth returns the bytes streamed in the month of now.
+func (v *Value) MonthBandwidth(now time.Time) int64 {
+	v.bandwidthMtx.Lock()
+	defer v.bandwidthMtx.Unlock()
+
+	month := now.Format(bandwidthMonthFormat)
+	var total int64
+	for day, n := range v.Bandwidth {
+		if strings.HasPrefix(day, month) {
+			total += n
+		}
+	}
+	return total
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBandwidth(t *testing.T) {
+	cfg := &Value{}
+	now := time.Date(2024, 3, 31, 20, 0, 0, 0, time.UTC)
+	cfg.AddBandwidth(now.AddDate(0, 0, -70), 1000)
+	cfg.AddBandwidth(now.AddDate(0, 0, -1), 300)
+	cfg.AddBandwidth(now, StreamBytes(128, time.Minute))
+	cfg.AddBandwidth(now, StreamBytes(0, time.Minute))
+
+	if len(cfg.Bandwidth) != 2 {
+		t.Errorf("kept %d days, want 2", len(cfg.Bandwidth))
+	}
+	if got := cfg.DayBandwidth(now); got != 1_920_000 {
+		t.Errorf("day bandwidth %d, want 1920000", got)
+	}
+	if got := cfg.MonthBandwidth(now); got != 1_920_300 {
+		t.Errorf("month bandwidth %d, want 1920300", got)
+	}
+	if got := cfg.MonthBandwidth(now.AddDate(0, 0, 1)); got != 0 {
+		t.Errorf("next month bandwidth %d, want 0", got)
+	}
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+)
+
+// ColorMode overrides the colors detected for the terminal, with the -color arg.
+type ColorMode string
+
+const (
+	// ColorAuto uses the colors detected from the environment, at most the 16 ones with the low refresh
+	ColorAuto ColorMode = ""
+	ColorTrue ColorMode = "truecolor"
+	Color256  ColorMode = "256"
+	Color16   ColorMode = "16"
+	ColorNone ColorMode = "none"
+)
+
+var colorModes = []ColorMode{ColorTrue, Color256, Color16, ColorNone}
+
+var color ColorMode
+
+func init() {
+	flag.Var(&color, "color", "use -color truecolor, 256, 16 or none to override the colors detected for the terminal")
+}
+
+func (c *ColorMode) String() string {
+	return string(*c)
+}
+
+func (c *ColorMode) Set(s string) error {
+	if s == "auto" {
+		s = string(ColorAuto)
+	}
+	if s != string(ColorAuto) && !slices.Contains(colorModes, ColorMode(s)) {
+		return fmt.Errorf("unknown color mode %q, expected auto, truecolor, 256, 16 or none", s)
+	}
+	*c = ColorMode(s)
+	return nil
+}
+
+// Color returns the colors set with the -color arg.
+func Color() ColorMode {
+	return color
+}
@@ -0,0 +1,18 @@
+package config
+
+import "testing"
+
+func TestColorMode_Set(t *testing.T) {
+	var c ColorMode
+	for _, s := range []string{"truecolor", "256", "16", "none"} {
+		if err := c.Set(s); err != nil || c != ColorMode(s) {
+			t.Errorf("Set(%q): %v, got %q", s, err, c)
+		}
+	}
+	if err := c.Set("auto"); err != nil || c != ColorAuto {
+		t.Errorf("Set(auto): %v, got %q", err, c)
+	}
+	if err := c.Set("88"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
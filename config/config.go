@@ -1,6 +1,7 @@
 package config
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -15,13 +16,85 @@ import (
 	"time"
 )
 
-var debug = flag.Bool("debug", false, "use -debug arg to log to a file")
+var (
+	debug  = flag.Bool("debug", false, "use -debug arg to log to a file")
+	daemon = flag.Bool("daemon", false, "use -daemon arg to run the player in the background, for the app to attach to")
+	join   = flag.String("join", "", "use -join host:port to follow the station changes of the relay at host:port")
+	reader = flag.Bool("screen-reader", false, "use -screen-reader arg for a plain text output, read by screen readers")
+	strict = flag.Bool("strict-api", false, "use -strict-api arg with -debug to log the API responses not matching the expected fields")
+	demo   = flag.Bool("demo", false, "use -demo arg to run with bundled stations and simulated playback, without the network or audio")
+	pprof  = flag.String("pprof", "", "use -pprof localhost:6060 to serve the profiles of net/http/pprof at the address")
+	audio  = flag.String("audio-server", "", "use -audio-server /path/to/pulse/native to play on the PulseAudio or PipeWire socket, e.g. of the host in a container")
+	health = flag.String("health", "", "use -health :8080 with -daemon to serve the health of the player on /healthz at the address")
+	logs   = flag.Bool("log-view", false, "use -log-view arg to show the log in the app with ctrl+l, filtered by level and searched")
+)
+
+// baseDir replaces the user config and cache dirs when set, see UseDir.
+var baseDir string
 
 const (
 	ApiReqTimeout     = 10 * time.Second
 	MpvIpcConnTimeout = 10 * time.Second
 	VlcConnTimeout    = 20 * time.Millisecond
 
+	MetadataPollInterval = 500 * time.Millisecond
+	MetadataPollJitter   = 50 * time.Millisecond
+
+	SearchDebounce = 300 * time.Millisecond
+
+	// the next pages of a search are loaded in the background one at a time, up to LoadAllMax stations
+	LoadAllPageSize  = 500
+	LoadAllPageDelay = time.Second
+	LoadAllMax       = 10000
+
+	IdentifyTimeout = 40 * time.Second
+
+	DaemonConnTimeout = 2 * time.Second
+	DaemonReqTimeout  = 10 * time.Second
+
+	CastDiscoveryTimeout = 3 * time.Second
+	CastReqTimeout       = 10 * time.Second
+
+	IdleCheckInterval = 5 * time.Second
+	IdleStopWarning   = time.Minute
+
+	BandwidthTickInterval = 10 * time.Second
+
+	// the favorites are backed up when they changed since the last check
+	FavoritesBackupInterval = 10 * time.Second
+
+	// a gap of the clock between the checks longer than ResumeMinGap is a suspend of the system
+	ResumeCheckInterval  = 2 * time.Second
+	ResumeMinGap         = 30 * time.Second
+	NetworkCheckInterval = 3 * time.Second
+	ReconnectDelay       = 5 * time.Second
+
+	// TickInterval is the tick of the UI, refreshing the clock, that the periodic checks subscribe to
+	TickInterval = time.Second
+
+	// the stream of the station the cursor rests on is resolved ahead of playing it
+	PreconnectDelay = 700 * time.Millisecond
+	PreconnectTTL   = 5 * time.Minute
+
+	// a warning tells when the buffer of the playing stream stays under BufferLow for BufferLowFor
+	BufferLow    = 2 * time.Second
+	BufferLowFor = 30 * time.Second
+
+	GuideCheckInterval   = 30 * time.Second
+	GuideRefreshInterval = 6 * time.Hour
+
+	// the reminders due are notified, tuning in being offered for ReminderTuneInFor
+	ReminderCheckInterval = 30 * time.Second
+	ReminderTuneInFor     = time.Hour
+
+	SyncInterval    = 30 * time.Second
+	GitSyncInterval = 5 * time.Minute
+	GitSyncTimeout  = time.Minute
+	GitSyncSubDir   = "sync-git"
+
+	WebdavSyncInterval = time.Minute
+	WebdavSyncTimeout  = 30 * time.Second
+
 	VolumeStep  = 5
 	SeekStepSec = 10
 
@@ -33,25 +106,152 @@ const (
 const (
 	DefVolume         = 100
 	DefHistorySaveMax = 100
+	DefRelayPort      = 8765
+	DefConnectTimeout = 15
+	DefApiRateLimit   = 5
 )
 
 type Value struct {
 	Version     string      `json:"-"`
 	Favorites   []string    `json:"favorites,omitempty"` // Ordered station UUID's for user favorites
 	Volume      *int        `json:"volume,omitempty"`
+	MaxVolume   int         `json:"maxVolume,omitempty"` // Hard cap of the volume, none if 0
 	Theme       int         `json:"theme"`
 	StationView StationView `json:"stationView"`
-
-	Player PlayerType `json:"playerType"`
+	// Volume set at the start instead of the last one, 0 for the last one
+	StartupVolume int `json:"startupVolume,omitempty"`
+
+	// Favorite station UUID to the name it's displayed with, kept when the station is moved in the favorites
+	Aliases map[string]string `json:"aliases,omitempty"`
+	Notes   map[string]string `json:"notes,omitempty"`  // Favorite station UUID to the note attached to it
+	Labels  map[string]Label  `json:"labels,omitempty"` // Favorite station UUID to its color label
+	Trash   []TrashedFavorite `json:"trash,omitempty"`  // Favorites removed recently, the latest first
+	// Favorite station UUID to the reminder to tune into it
+	Reminders map[string]Reminder `json:"reminders,omitempty"`
+
+	FavoriteBackups *int `json:"favoriteBackups,omitempty"` // Number of favorites backups kept, 0 for none
+
+	// File in a synced folder the favorites and the history are merged with, to share them between machines
+	SyncFile        string               `json:"syncFile,omitempty"`
+	FavoriteChanges map[string]time.Time `json:"favoriteChanges,omitempty"` // Time of the last add or removal of each favorite
+	SyncGit         string               `json:"syncGit,omitempty"`         // Git remote the favorites are pushed to and pulled from
+	SyncWebdav      *WebdavSync          `json:"syncWebdav,omitempty"`      // WebDAV file the favorites and the history are merged with
+
+	// Quick dial slot to station UUID, nil until assigned, the first favorites being on the slots
+	Dial     map[int]string `json:"dial"`
+	DialKeys DialKeys       `json:"dialKeys"`
+
+	Macros map[string][]string `json:"macros,omitempty"` // Function key to the key sequence it replays
+
+	Player     PlayerType `json:"playerType"`
+	MpvPath    string     `json:"mpvPath,omitempty"`    // Binary of mpv, found in the PATH if empty
+	MpvArgs    []string   `json:"mpvArgs,omitempty"`    // Extra arguments of mpv, e.g. --cache-secs=30 or --af=loudnorm
+	FFplayPath string     `json:"ffplayPath,omitempty"` // Binary of ffplay, found in the PATH if empty
+	FFplayArgs []string   `json:"ffplayArgs,omitempty"` // Extra arguments of ffplay, e.g. -af loudnorm
+	// Milliseconds between the song title polls of the player, 0 for its default, MetadataPollManual for the key only
+	MetadataPollMs int `json:"metadataPollMs,omitempty"`
+	// Milliseconds of the fade between the stations played, 0 to switch at once
+	CrossfadeMs int `json:"crossfadeMs,omitempty"`
+	// Milliseconds of the fade in of the first station played by a session, 0 to start at the volume
+	StartupFadeMs int `json:"startupFadeMs,omitempty"`
+	// Keep a second mpv on standby, the highlighted station playing muted on it, so switching to it has no gap
+	Gapless bool `json:"gapless,omitempty"`
+	// Socket of the PulseAudio or PipeWire server the players play on, looked up if empty
+	AudioServer string `json:"audioServer,omitempty"`
+	// Address serving the health of the daemon on /healthz, e.g. :8080 for a container healthcheck
+	HealthAddr string `json:"healthAddr,omitempty"`
 
 	historyMtx     sync.Mutex          `json:"-"`
 	History        []HistoryEntry      `json:"history,omitempty"`
 	HistorySaveMax *int                `json:"historySaveMax,omitempty"`
 	HistoryChan    chan []HistoryEntry `json:"-"`
+	HistoryDeleted []time.Time         `json:"historyDeleted,omitempty"` // Timestamps of the deleted entries, for the sync
+	HistoryCleared time.Time           `json:"historyCleared"`
 
 	AutoplayFavorite string `json:"autoplayFavorite"`
 
-	saveMtx sync.Mutex
+	Location *Location `json:"location,omitempty"` // Location of the user, for the stations near them
+
+	activityMtx sync.Mutex                 `json:"-"`
+	Activities  map[string]StationActivity `json:"activities,omitempty"` // Station UUID to the plays and votes of the user
+
+	LearnLanguage string           `json:"learnLanguage,omitempty"` // Language the browsed stations are filtered by, for language immersion
+	PreferTalk    bool             `json:"preferTalk"`              // List the talk and news stations first
+	listeningMtx  sync.Mutex       `json:"-"`
+	Listening     map[string]int64 `json:"listening,omitempty"` // Language to the seconds listened to stations speaking it
+
+	SongRules       map[string]SongRule `json:"songRules,omitempty"`       // Station UUID to artist/title parsing rule
+	MusicBrainz     bool                `json:"musicBrainz"`               // Look up the playing song on MusicBrainz
+	Artwork         bool                `json:"artwork"`                   // Display cover art and station logos
+	LyricsProviders []string            `json:"lyricsProviders,omitempty"` // Lyrics providers in the order they are asked, all by default
+	AcoustIDKey     string              `json:"acoustIdKey,omitempty"`     // AcoustID api key, enables identifying songs by fingerprint
+
+	recordingsMtx   sync.Mutex          `json:"-"`
+	Schedules       []RecordingSchedule `json:"schedules,omitempty"`
+	Recordings      []RecordingEntry    `json:"recordings,omitempty"`
+	RecordingsDir   string              `json:"recordingsDir,omitempty"`
+	SplitRecordings bool                `json:"splitRecordings"` // Save every song of a recording to its own file
+
+	CacheLimitMB      int `json:"cacheLimitMb,omitempty"`      // Size limit of the cache dir, 0 for no limit
+	RecordingsLimitMB int `json:"recordingsLimitMb,omitempty"` // Size limit of the recordings dir, 0 for no limit
+
+	ConnectTimeoutSec int `json:"connectTimeoutSec,omitempty"` // Seconds for a station to start playing, DefConnectTimeout by default
+	IdleStopHours     int `json:"idleStopHours,omitempty"`     // Stop the playback after hours without user interaction, 0 to never stop
+
+	bandwidthMtx   sync.Mutex       `json:"-"`
+	Bandwidth      map[string]int64 `json:"bandwidth,omitempty"`      // Bytes streamed each day
+	BandwidthCapMB int              `json:"bandwidthCapMb,omitempty"` // Monthly data cap, 0 for no cap
+	BandwidthStop  bool             `json:"bandwidthStop"`            // Stop the playback when the cap is reached, instead of only warning
+	LowBandwidth   bool             `json:"lowBandwidth"`             // Play the lowest bitrate variant of the stations and don't fetch images
+	Preconnect     bool             `json:"preconnect"`               // Resolve the stream of the highlighted station, so it starts faster
+
+	guidesMtx sync.Mutex              `json:"-"`
+	Guides    map[string]StationGuide `json:"guides,omitempty"` // Station UUID to its program schedule
+	// Tags ranking the live programs, the most interesting first. The tags of the favorites are used if empty.
+	InterestTags []string `json:"interestTags,omitempty"`
+
+	Relay     bool `json:"relay"`               // Serve the playing station over HTTP on the LAN
+	RelayPort int  `json:"relayPort,omitempty"` // Port of the relay, DefRelayPort by default
+
+	ClockFormat  string `json:"clockFormat,omitempty"` // Layout of the status bar clock, as in the time package, empty to hide it
+	StreamUptime bool   `json:"streamUptime"`          // Show in the status bar how long the station has been streaming without interruption
+	BufferHealth bool   `json:"bufferHealth"`          // Show in the status bar the duration of the stream buffered ahead by the player
+	Banner       bool   `json:"banner"`                // Show the song title, or the station name, in large letters under the playing station
+	TitleFormat  string `json:"titleFormat,omitempty"` // Template of the terminal title, as in the text/template package, empty to leave it
+
+	// Templates of the display strings, as in the text/template package, the default layouts when empty
+	RowFormat          string `json:"rowFormat,omitempty"`          // Second line of the station rows, with the fields of the station
+	NowPlayingFormat   string `json:"nowPlayingFormat,omitempty"`   // Line under the playing station, with its song
+	NotificationFormat string `json:"notificationFormat,omitempty"` // Notice of a followed program starting
+	OsdFile            string `json:"osdFile,omitempty"`            // File kept with the playing station and song, e.g. for a streaming overlay
+	OsdFormat          string `json:"osdFormat,omitempty"`          // Text of the OSD file
+
+	ScreenReader  bool          `json:"screenReader"`            // Plain text output without decorative glyphs, the state changes printed as lines
+	SymbolSignals bool          `json:"symbolSignals"`           // Tell by symbols and words too what is told by colors, like the line under the cursor
+	ReducedMotion bool          `json:"reducedMotion"`           // No spinner, blinking cursor or seconds counting, the view only changes with the state
+	RenderProfile RenderProfile `json:"renderProfile,omitempty"` // Full or low refresh rendering, low over SSH by default
+	IconSet       IconSet       `json:"icons,omitempty"`         // Nerd Font, Unicode or ASCII indicators, as detected for the terminal by default
+
+	ApiRateLimit float64 `json:"apiRateLimit,omitempty"` // Requests per second to the radio-browser servers, DefApiRateLimit by default
+	// Base URL of the radio-browser server the requests are sent to, e.g. a mirror, instead of the ones looked up
+	ApiUrl string `json:"apiUrl,omitempty"`
+
+	RememberOutputVolume bool           `json:"rememberOutputVolume"`
+	OutputVolumes        map[string]int `json:"outputVolumes,omitempty"` // Volume of each output, restored when switching to it
+
+	PauseOnLock   bool         `json:"pauseOnLock,omitempty"`   // Pause when the session locks, on Linux
+	PauseOnUnplug bool         `json:"pauseOnUnplug,omitempty"` // Pause when the headphones are unplugged, on Linux
+	AutoResume    ResumePolicy `json:"autoResume,omitempty"`    // When the playback paused by a lock or an unplug resumes
+
+	AutoDuck   bool `json:"autoDuck,omitempty"`   // Lower the volume while another app plays a notification or a call, on Linux
+	DuckVolume int  `json:"duckVolume,omitempty"` // Percent of the volume kept while lowered, DefDuckVolume if 0
+
+	// Hours the desktop notifications and the reminders are held and the volume capped, none if nil
+	QuietHours *QuietHours `json:"quietHours,omitempty"`
+
+	// envOverrides are the JSON names of the values overridden by the environment, see applyEnv
+	envOverrides map[string]envOverride
+	saveMtx      sync.Mutex
 }
 
 type PlayerType uint8
@@ -76,17 +276,122 @@ func (p PlayerType) String() string {
 	return playerNames[p]
 }
 
+// PlayerPath returns the binary of the player set in the config, empty for the one of the PATH.
+func (v *Value) PlayerPath(p PlayerType) string {
+	switch p {
+	case Mpv:
+		return v.MpvPath
+	case FFPlay:
+		return v.FFplayPath
+	}
+	return ""
+}
+
+// SongFormat is the order of artist and title in a station's stream title.
+type SongFormat string
+
+const (
+	SongFormatAuto        SongFormat = ""
+	SongFormatArtistTitle SongFormat = "artist-title"
+	SongFormatTitleArtist SongFormat = "title-artist"
+	SongFormatTitleOnly   SongFormat = "title"
+)
+
+// SongRule overrides the artist/title parsing heuristics for one station.
+type SongRule struct {
+	Format    SongFormat `json:"format,omitempty"`
+	Prefix    string     `json:"prefix,omitempty"`    // text to drop from the start of the stream title
+	Separator string     `json:"separator,omitempty"` // separator between artist and title
+}
+
+// GetSongRule returns the parsing rule for the station, or the zero rule.
+func (v *Value) GetSongRule(uuid string) SongRule {
+	return v.SongRules[uuid]
+}
+
 func (v *Value) GetVolume() int {
 	if v.Volume != nil {
-		return *v.Volume
+		return min(*v.Volume, v.VolumeCap())
 	}
-	return DefVolume
+	return min(DefVolume, v.VolumeCap())
 }
 
 func (v *Value) SetVolume(value int) {
+	value = min(value, v.VolumeCap())
 	v.Volume = &value
 }
 
+// VolumeCap returns the highest volume the players are set to, 100 without a cap.
+func (v *Value) VolumeCap() int {
+	return ClampVolume(100, v.MaxVolume)
+}
+
+// ClampVolume returns the volume between 0 and the cap maxVolume, no cap being set by 0.
+func ClampVolume(value, maxVolume int) int {
+	if maxVolume <= 0 || maxVolume > 100 {
+		maxVolume = 100
+	}
+	return min(max(value, 0), maxVolume)
+}
+
+// GetConnectTimeout returns how long a station has to start playing before giving up.
+func (v *Value) GetConnectTimeout() time.Duration {
+	if v.ConnectTimeoutSec > 0 {
+		return time.Duration(v.ConnectTimeoutSec) * time.Second
+	}
+	return DefConnectTimeout * time.Second
+}
+
+// Crossfade returns how long the station played fades out while the next one fades in, 0 for none.
+func (v *Value) Crossfade() time.Duration {
+	return time.Duration(max(v.CrossfadeMs, 0)) * time.Millisecond
+}
+
+// StartupFade returns how long the first station played by a session fades in, 0 for none.
+func (v *Value) StartupFade() time.Duration {
+	return time.Duration(max(v.StartupFadeMs, 0)) * time.Millisecond
+}
+
+// UseStartupVolume sets the volume to the startup one, if set, instead of the last one.
+func (v *Value) UseStartupVolume() {
+	if v.StartupVolume > 0 {
+		v.SetVolume(v.StartupVolume)
+	}
+}
+
+// GetApiRateLimit returns how many requests per second are sent to the radio-browser servers at most.
+func (v *Value) GetApiRateLimit() float64 {
+	if v.ApiRateLimit > 0 {
+		return v.ApiRateLimit
+	}
+	return DefApiRateLimit
+}
+
+func (v *Value) GetRelayPort() int {
+	if v.RelayPort > 0 {
+		return v.RelayPort
+	}
+	return DefRelayPort
+}
+
+// SwitchOutputVolume remembers the current volume for the output from, and sets the volume remembered for the output to.
+// It returns false if the volume didn't change, when disabled or if there's no volume remembered for the output to.
+func (v *Value) SwitchOutputVolume(from, to string) bool {
+	if !v.RememberOutputVolume || from == to {
+		return false
+	}
+	if v.OutputVolumes == nil {
+		v.OutputVolumes = make(map[string]int)
+	}
+	v.OutputVolumes[from] = v.GetVolume()
+	vol, ok := v.OutputVolumes[to]
+	if !ok || vol == v.GetVolume() {
+		return false
+	}
+	v.SetVolume(vol)
+	return true
+}
+
 func (v *Value) IsFavorite(uuid string) bool {
 	return slices.Contains(v.Favorites, uuid)
 }
@@ -96,8 +401,10 @@ func (v *Value) ToggleFavorite(uuid string) bool {
 	l1 := len(v.Favorites)
 	v.Favorites = slices.DeleteFunc(v.Favorites, func(el string) bool { return el == uuid })
 	l2 := len(v.Favorites)
+	v.touchFavorite(uuid)
 	if l2 == l1 {
 		v.Favorites = append(v.Favorites, uuid)
+		v.untrash(uuid)
 		return true
 	}
 	return false
@@ -108,13 +415,33 @@ func (v *Value) DeleteFavorite(uuid string) bool {
 	l1 := len(v.Favorites)
 	v.Favorites = slices.DeleteFunc(v.Favorites, func(el string) bool { return el == uuid })
 	l2 := len(v.Favorites)
+	if l2 != l1 {
+		v.touchFavorite(uuid)
+	}
 	return l2 != l1
 }
 
+// SetFavorites replaces the favorites, recording the changes for the sync.
+func (v *Value) SetFavorites(uuids []string) {
+	for _, uuid := range v.Favorites {
+		if !slices.Contains(uuids, uuid) {
+			v.touchFavorite(uuid)
+		}
+	}
+	for _, uuid := range uuids {
+		if !v.IsFavorite(uuid) {
+			v.touchFavorite(uuid)
+		}
+	}
+	v.Favorites = slices.Clone(uuids)
+}
+
 func (v *Value) InsertFavorite(uuid string, idx int) bool {
 	if slices.Contains(v.Favorites, uuid) {
 		return false
 	}
+	v.touchFavorite(uuid)
+	v.untrash(uuid)
 	if idx >= len(v.Favorites) {
 		v.Favorites = append(v.Favorites, uuid)
 		return true
@@ -137,7 +464,17 @@ func (v *Value) String() string {
 // - either a default value if no previously saved config is found in the file system
 //
 // - either the found config Value
+//
+// The values are then overridden by the SONICRADIO_ environment variables, which aren't saved.
 func Load() (cfg *Value, err error) {
+	cfg, err = load()
+	for _, envErr := range cfg.applyEnv(os.Environ()) {
+		slog.With("method", "config.Load").Warn("invalid environment value", "error", envErr)
+	}
+	return cfg, err
+}
+
+func load() (cfg *Value, err error) {
 	versionVal := os.Getenv("SONIC_VERSION")
 	if versionVal == "" {
 		versionVal = defVersion
@@ -165,7 +502,8 @@ func Load() (cfg *Value, err error) {
 	if err != nil {
 		return
 	}
-	err = json.Unmarshal(b, &cfg)
+	// decoded into the defaults, a null config keeps them instead of a nil value
+	err = json.Unmarshal(b, cfg)
 	if err != nil {
 		return
 	}
@@ -183,6 +521,7 @@ func Load() (cfg *Value, err error) {
 	if len(cfg.History) > *cfg.HistorySaveMax {
 		cfg.History = cfg.History[len(cfg.History)-*cfg.HistorySaveMax:]
 	}
+	cfg.PurgeTrash(time.Now())
 	return
 }
 
@@ -190,6 +529,10 @@ func (v *Value) Save() error {
 	v.saveMtx.Lock()
 	defer v.saveMtx.Unlock()
 
+	saved, err := v.savedJSON()
+	if err != nil {
+		return err
+	}
 	fp, err := getOrCreateConfigDir()
 	if err != nil {
 		return err
@@ -201,7 +544,7 @@ func (v *Value) Save() error {
 	}
 	enc := json.NewEncoder(f)
 	enc.SetIndent("  ", "  ")
-	err = enc.Encode(v)
+	err = enc.Encode(saved)
 	if err != nil {
 		return err
 	}
@@ -210,27 +553,64 @@ func (v *Value) Save() error {
 }
 
 func getOrCreateConfigDir() (string, error) {
-	logger := slog.With("method", "getOrCreateConfigDir")
-
+	if baseDir != "" {
+		return getOrCreateDir(filepath.Join(baseDir, "config"))
+	}
 	dir, err := os.UserConfigDir()
 	if err != nil {
 		return "", fmt.Errorf("get user config dir: %v", err)
 	}
+	return getOrCreateDir(filepath.Join(dir, cfgSubDir))
+}
 
-	fp := filepath.Join(dir, cfgSubDir)
-	_, err = os.Stat(fp)
+// GetOrCreateCacheDir returns the application directory for data that can be safely deleted.
+func GetOrCreateCacheDir() (string, error) {
+	if baseDir != "" {
+		return getOrCreateDir(filepath.Join(baseDir, "cache"))
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("get user cache dir: %v", err)
+	}
+	return getOrCreateDir(filepath.Join(dir, cfgSubDir))
+}
+
+// GitSyncDir returns the path of the clone of the git sync remote in the config dir, out of the reach of the
+// cache limit, moving there the clone kept in the cache dir by the previous versions.
+func GitSyncDir() (string, error) {
+	cfgDir, err := getOrCreateConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(cfgDir, GitSyncSubDir)
+	if _, err := os.Stat(dir); !errors.Is(err, fs.ErrNotExist) {
+		return dir, nil
+	}
+	if cacheDir, err := GetOrCreateCacheDir(); err == nil {
+		old := filepath.Join(cacheDir, GitSyncSubDir)
+		if err := os.Rename(old, dir); err == nil {
+			slog.With("method", "config.GitSyncDir").Info("moved the git sync clone", "from", old, "to", dir)
+		}
+	}
+	return dir, nil
+}
+
+func getOrCreateDir(fp string) (string, error) {
+	logger := slog.With("method", "getOrCreateDir")
+
+	_, err := os.Stat(fp)
 	if err == nil {
-		logger.Info(fmt.Sprintf("found config dir at path %s", fp))
+		logger.Info(fmt.Sprintf("found dir at path %s", fp))
 		return fp, nil
 	}
 
 	if !errors.Is(err, fs.ErrNotExist) {
-		return "", fmt.Errorf("checking config dir at path %s", fp)
+		return "", fmt.Errorf("checking dir at path %s", fp)
 	}
 
-	logger.Info(fmt.Sprintf("creating config dir at path %s", fp))
+	logger.Info(fmt.Sprintf("creating dir at path %s", fp))
 	if err = os.MkdirAll(fp, os.ModePerm); err != nil {
-		return "", fmt.Errorf("creating config dir at path %s: %v", fp, err)
+		return "", fmt.Errorf("creating dir at path %s: %v", fp, err)
 	}
 
 	return fp, nil
@@ -239,3 +619,56 @@ func getOrCreateConfigDir() (string, error) {
 func Debug() bool {
 	return *debug
 }
+
+// StrictApi reports whether the mismatches of the API responses with the expected fields are logged.
+func StrictApi() bool {
+	return *strict
+}
+
+// LogView reports whether the log is shown in the app, by its log view.
+func LogView() bool {
+	return *logs
+}
+
+// Pprof returns the address serving the profiles, empty if none.
+func Pprof() string {
+	return *pprof
+}
+
+// Demo reports whether the app runs on the bundled stations, with a simulated player.
+func Demo() bool {
+	return *demo
+}
+
+// UseDir keeps the config, the cache and the sockets of the app in dir instead of the user dirs,
+// e.g. for the demo mode not to change the saved config.
+func UseDir(dir string) {
+	baseDir = dir
+}
+
+func Daemon() bool {
+	return *daemon
+}
+
+// JoinRoom returns the address of the relay whose station changes are followed, empty if none.
+func JoinRoom() string {
+	return *join
+}
+
+// ScreenReaderMode reports whether the output is plain text for the screen readers, set in the config
+// file or by the -screen-reader arg.
+func (v *Value) ScreenReaderMode() bool {
+	return v.ScreenReader || *reader
+}
+
+// AudioServerPath returns the socket of the audio server set by the -audio-server arg or in the config
+// file, empty to look it up.
+func (v *Value) AudioServerPath() string {
+	return cmp.Or(*audio, v.AudioServer)
+}
+
+// HealthCheckAddr returns the address serving the health of the daemon, set by the -health arg or in
+// the config file, empty if none.
+func (v *Value) HealthCheckAddr() string {
+	return cmp.Or(*health, v.HealthAddr)
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 )
 
@@ -26,3 +29,70 @@ func Test_save(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func Test_switchOutputVolume(t *testing.T) {
+	vol := 80
+	cfg := &Value{Volume: &vol, RememberOutputVolume: true}
+	if cfg.SwitchOutputVolume("local", "cast:Kitchen") {
+		t.Error("no volume remembered for the new output")
+	}
+	cfg.SetVolume(30)
+	if !cfg.SwitchOutputVolume("cast:Kitchen", "local") || cfg.GetVolume() != 80 {
+		t.Errorf("local volume %d, want 80", cfg.GetVolume())
+	}
+	if !cfg.SwitchOutputVolume("local", "cast:Kitchen") || cfg.GetVolume() != 30 {
+		t.Errorf("cast volume %d, want 30", cfg.GetVolume())
+	}
+
+	cfg.RememberOutputVolume = false
+	if cfg.SwitchOutputVolume("cast:Kitchen", "local") || cfg.GetVolume() != 30 {
+		t.Error("volume changed while disabled")
+	}
+}
+
+func Test_useStartupVolume(t *testing.T) {
+	vol := 80
+	cfg := &Value{Volume: &vol}
+	cfg.UseStartupVolume()
+	if cfg.GetVolume() != 80 {
+		t.Errorf("got volume %d, want the last one", cfg.GetVolume())
+	}
+	cfg.StartupVolume = 40
+	cfg.UseStartupVolume()
+	if cfg.GetVolume() != 40 {
+		t.Errorf("got volume %d, want 40", cfg.GetVolume())
+	}
+	cfg.MaxVolume = 30
+	cfg.UseStartupVolume()
+	if cfg.GetVolume() != 30 {
+		t.Errorf("got volume %d, want capped at 30", cfg.GetVolume())
+	}
+}
+
+func Test_useDir(t *testing.T) {
+	dir := t.TempDir()
+	UseDir(dir)
+	t.Cleanup(func() { UseDir("") })
+
+	cfg, _ := Load()
+	cfg.SetFavorites([]string{"uuid-1"})
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config", cfgFilename)); err != nil {
+		t.Fatalf("expected the config saved in the dir: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(cfg.Favorites, []string{"uuid-1"}) {
+		t.Errorf("loaded favorites %v, want the saved ones", cfg.Favorites)
+	}
+	if cache, _ := GetOrCreateCacheDir(); cache != filepath.Join(dir, "cache") {
+		t.Errorf("got cache dir %s", cache)
+	}
+	if p := EventsSocketPath(); filepath.Dir(p) != dir {
+		t.Errorf("got events socket %s", p)
+	}
+}
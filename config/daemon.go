@@ -0,0 +1,32 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const daemonSocketName = "sonicradio.sock"
+
+// DaemonSocketPath returns the unix socket of the player daemon.
+// It's in XDG_RUNTIME_DIR, the %t of the systemd socket unit, when set.
+func DaemonSocketPath() string {
+	if baseDir != "" {
+		return filepath.Join(baseDir, daemonSocketName)
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, daemonSocketName)
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("sonicradio-%d.sock", os.Getuid()))
+}
+
+// EventsSocketPath returns the unix socket streaming the events of the running app, next to the daemon socket.
+func EventsSocketPath() string {
+	if baseDir != "" {
+		return filepath.Join(baseDir, "sonicradio-events.sock")
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "sonicradio-events.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("sonicradio-events-%d.sock", os.Getuid()))
+}
@@ -0,0 +1,83 @@
+package config
+
+// DialSlots is the number of quick dial slots, played with the keys 1 to 9.
+const DialSlots = 9
+
+// DialKeys are the keys playing the quick dial slots.
+type DialKeys uint8
+
+func (k DialKeys) String() string {
+	switch k {
+	case DialAlt:
+		return "alt+1..9"
+	case DialDigits:
+		return "1..9"
+	case DialOff:
+		return "Off"
+	}
+	return "unknown DialKeys"
+}
+
+const (
+	// DialDigits replaces the go to station number of the lists
+	DialDigits DialKeys = iota
+	DialAlt
+	DialOff
+)
+
+// DialStation returns the favorite of the quick dial slot, or "" if the slot is empty.
+// Until a slot is assigned, the slots are the first favorites.
+func (v *Value) DialStation(slot int) string {
+	if slot < 1 || slot > DialSlots {
+		return ""
+	}
+	if v.Dial == nil {
+		if slot > len(v.Favorites) {
+			return ""
+		}
+		return v.Favorites[slot-1]
+	}
+	if uuid := v.Dial[slot]; v.IsFavorite(uuid) {
+		return uuid
+	}
+	return ""
+}
+
+// DialSlot returns the quick dial slot of the station, 0 if it has none.
+func (v *Value) DialSlot(uuid string) int {
+	return v.DialSlotMap()[uuid]
+}
+
+// DialSlotMap returns the quick dial slot of each station on one, to look up many stations at once.
+func (v *Value) DialSlotMap() map[string]int {
+	res := make(map[string]int, DialSlots)
+	for slot := 1; slot <= DialSlots; slot++ {
+		if uuid := v.DialStation(slot); uuid != "" {
+			res[uuid] = slot
+		}
+	}
+	return res
+}
+
+// SetDial assigns the favorite to the quick dial slot, moving it from its previous slot.
+// A slot of 0 removes the station from the quick dial. Assigning a slot for the first time
+// keeps the other favorites on the slots they had by default.
+func (v *Value) SetDial(slot int, uuid string) {
+	if slot < 0 || slot > DialSlots || !v.IsFavorite(uuid) {
+		return
+	}
+	if v.Dial == nil {
+		v.Dial = make(map[int]string)
+		for i := range min(DialSlots, len(v.Favorites)) {
+			v.Dial[i+1] = v.Favorites[i]
+		}
+	}
+	for s, u := range v.Dial {
+		if u == uuid {
+			delete(v.Dial, s)
+		}
+	}
+	if slot > 0 {
+		v.Dial[slot] = uuid
+	}
+}
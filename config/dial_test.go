@@ -0,0 +1,43 @@
+package config
+
+import "testing"
+
+func TestDial(t *testing.T) {
+	cfg := &Value{Favorites: []string{"a", "b", "c"}}
+	if cfg.DialStation(2) != "b" || cfg.DialSlot("c") != 3 || cfg.DialStation(4) != "" {
+		t.Errorf("default slots %q %d", cfg.DialStation(2), cfg.DialSlot("c"))
+	}
+
+	cfg.SetDial(5, "a")
+	if cfg.DialStation(5) != "a" || cfg.DialStation(1) != "" || cfg.DialStation(2) != "b" {
+		t.Errorf("slots %v", cfg.Dial)
+	}
+	cfg.SetDial(1, "x")
+	if cfg.DialStation(1) != "" {
+		t.Error("not favorite station assigned")
+	}
+	cfg.SetDial(0, "b")
+	if cfg.DialSlot("b") != 0 {
+		t.Errorf("slots %v", cfg.Dial)
+	}
+	cfg.SetDial(0, "a")
+	cfg.SetDial(0, "c")
+	if cfg.DialStation(1) != "" {
+		t.Errorf("default slots restored after clearing %v", cfg.Dial)
+	}
+
+	cfg.ToggleFavorite("a")
+	cfg.SetDial(1, "c")
+	cfg.ToggleFavorite("c")
+	if cfg.DialStation(1) != "" {
+		t.Error("removed favorite still on a slot")
+	}
+}
+
+func TestDialSlotMap(t *testing.T) {
+	cfg := &Value{Favorites: []string{"a", "b", "c"}}
+	cfg.SetDial(7, "b")
+	if got := cfg.DialSlotMap(); len(got) != 3 || got["a"] != 1 || got["b"] != 7 || got["c"] != 3 {
+		t.Errorf("got %v", got)
+	}
+}
@@ -0,0 +1,13 @@
+package config
+
+// DefDuckVolume is the default percent of the volume kept while another app plays a notification or a call.
+const DefDuckVolume = 30
+
+// DuckedVolume returns the volume lowered for a notification or a call.
+func (v *Value) DuckedVolume() int {
+	pct := v.DuckVolume
+	if pct <= 0 || pct > 100 {
+		pct = DefDuckVolume
+	}
+	return v.GetVolume() * pct / 100
+}
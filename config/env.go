@@ -0,0 +1,146 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EnvPrefix starts the names of the environment variables overriding the config values, followed by
+// the JSON name of the value in upper snake case, e.g. SONICRADIO_PLAYER_TYPE for playerType.
+const EnvPrefix = "SONICRADIO_"
+
+// envOverride is a config value overridden by the environment, its original JSON being saved in its place.
+type envOverride struct {
+	original json.RawMessage
+	// omit tells the original value is left out of the JSON, being empty
+	omit bool
+}
+
+// envParsers parse the environment values of the types read by name rather than as JSON.
+var envParsers = map[reflect.Type]func(string) (any, error){
+	reflect.TypeOf(Mpv): func(s string) (any, error) {
+		for p, name := range playerNames {
+			if strings.EqualFold(s, name) {
+				return p, nil
+			}
+		}
+		n, err := strconv.ParseUint(s, 10, 8)
+		if err != nil || n >= uint64(len(Players)) {
+			return nil, fmt.Errorf("unknown player %q", s)
+		}
+		return PlayerType(n), nil
+	},
+}
+
+// EnvName returns the name of the environment variable overriding the config value of the JSON name.
+func EnvName(jsonName string) string {
+	var b strings.Builder
+	b.WriteString(EnvPrefix)
+	prev := rune(0)
+	for _, r := range jsonName {
+		if unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)) {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+		prev = r
+	}
+	return b.String()
+}
+
+// applyEnv overrides the config values with the environment variables set, as KEY=value pairs of
+// os.Environ. The strings are taken as they are, the players by name, the other values as JSON, e.g.
+// SONICRADIO_MPV_ARGS='["--cache-secs=30"]'. The values which can't be parsed are returned as errors.
+func (v *Value) applyEnv(environ []string) []error {
+	env := make(map[string]string)
+	for _, kv := range environ {
+		if k, val, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(k, EnvPrefix) {
+			env[k] = val
+		}
+	}
+	if len(env) == 0 {
+		return nil
+	}
+
+	var errs []error
+	rv := reflect.ValueOf(v).Elem()
+	t := rv.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		envName := EnvName(name)
+		s, ok := env[envName]
+		if !ok {
+			continue
+		}
+		fv := rv.Field(i)
+		original, err := json.Marshal(fv.Interface())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", envName, err))
+			continue
+		}
+		o := envOverride{original: original, omit: strings.Contains(opts, "omitempty") && fv.IsZero()}
+		if err := setEnvValue(fv, s); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", envName, err))
+			continue
+		}
+		if v.envOverrides == nil {
+			v.envOverrides = make(map[string]envOverride)
+		}
+		if _, ok := v.envOverrides[name]; !ok {
+			v.envOverrides[name] = o
+		}
+	}
+	return errs
+}
+
+func setEnvValue(fv reflect.Value, s string) error {
+	if parse, ok := envParsers[fv.Type()]; ok {
+		val, err := parse(s)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(val))
+		return nil
+	}
+	if fv.Kind() == reflect.String {
+		fv.SetString(s)
+		return nil
+	}
+	ptr := reflect.New(fv.Type())
+	if err := json.Unmarshal([]byte(s), ptr.Interface()); err != nil {
+		return err
+	}
+	fv.Set(ptr.Elem())
+	return nil
+}
+
+// savedJSON returns the JSON of the config to save, with the original values in place of the ones
+// overridden by the environment.
+func (v *Value) savedJSON() (any, error) {
+	if len(v.envOverrides) == 0 {
+		return v, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for name, o := range v.envOverrides {
+		if o.omit {
+			delete(fields, name)
+			continue
+		}
+		fields[name] = o.original
+	}
+	return fields, nil
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func Test_envName(t *testing.T) {
+	tests := map[string]string{
+		"volume":         "SONICRADIO_VOLUME",
+		"playerType":     "SONICRADIO_PLAYER_TYPE",
+		"apiUrl":         "SONICRADIO_API_URL",
+		"metadataPollMs": "SONICRADIO_METADATA_POLL_MS",
+		"ffplayArgs":     "SONICRADIO_FFPLAY_ARGS",
+	}
+	for name, want := range tests {
+		if got := EnvName(name); got != want {
+			t.Errorf("EnvName(%q) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func Test_applyEnv(t *testing.T) {
+	vol := 100
+	v := &Value{Volume: &vol}
+	errs := v.applyEnv([]string{
+		"SONICRADIO_VOLUME=40",
+		"SONICRADIO_THEME=2",
+		"SONICRADIO_PLAYER_TYPE=MPV",
+		"SONICRADIO_API_URL=https://mirror.example.org/",
+		`SONICRADIO_MPV_ARGS=["--cache-secs=30"]`,
+		"SONICRADIO_METADATA_POLL_MS=soon",
+		"SONICRADIO_UNKNOWN=1",
+		"HOME=/root",
+	})
+	if len(errs) != 1 {
+		t.Errorf("got errors %v, want the poll interval only", errs)
+	}
+	if v.GetVolume() != 40 || v.Theme != 2 || v.Player != Mpv {
+		t.Errorf("got volume %d, theme %d, player %v", v.GetVolume(), v.Theme, v.Player)
+	}
+	if v.ApiUrl != "https://mirror.example.org/" || !slices.Equal(v.MpvArgs, []string{"--cache-secs=30"}) {
+		t.Errorf("got api url %q, mpv args %v", v.ApiUrl, v.MpvArgs)
+	}
+	if v.MetadataPollMs != 0 {
+		t.Errorf("got poll interval %d for an invalid value", v.MetadataPollMs)
+	}
+
+	if errs := v.applyEnv([]string{"SONICRADIO_PLAYER_TYPE=winamp"}); len(errs) != 1 || v.Player != Mpv {
+		t.Errorf("got errors %v, player %v for an unknown player", errs, v.Player)
+	}
+}
+
+func Test_envNotSaved(t *testing.T) {
+	dir := t.TempDir()
+	UseDir(dir)
+	t.Cleanup(func() { UseDir("") })
+
+	cfg, _ := Load()
+	cfg.Theme = 1
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SONICRADIO_THEME", "3")
+	t.Setenv("SONICRADIO_API_URL", "http://localhost:8080")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Theme != 3 || cfg.ApiUrl != "http://localhost:8080" {
+		t.Fatalf("got theme %d, api url %q, want the environment ones", cfg.Theme, cfg.ApiUrl)
+	}
+	cfg.SetFavorites([]string{"uuid-1"})
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "config", cfgFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved map[string]json.RawMessage
+	if err := json.Unmarshal(b, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if string(saved["theme"]) != "1" {
+		t.Errorf("saved theme %s, want the original 1", saved["theme"])
+	}
+	if _, ok := saved["apiUrl"]; ok {
+		t.Errorf("saved api url %s, want it left out", saved["apiUrl"])
+	}
+	var favorites []string
+	if err := json.Unmarshal(saved["favorites"], &favorites); err != nil || !slices.Equal(favorites, []string{"uuid-1"}) {
+		t.Errorf("saved favorites %s, want the changed ones", saved["favorites"])
+	}
+}
+
+func Test_envNullConfig(t *testing.T) {
+	dir := t.TempDir()
+	UseDir(dir)
+	t.Cleanup(func() { UseDir("") })
+	if err := os.MkdirAll(filepath.Join(dir, "config"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config", cfgFilename), []byte("null"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SONICRADIO_VOLUME", "40")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg == nil || cfg.GetVolume() != 40 || *cfg.HistorySaveMax != DefHistorySaveMax {
+		t.Fatalf("got %+v, want the defaults with the environment volume", cfg)
+	}
+}
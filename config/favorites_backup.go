@@ -0,0 +1,195 @@
+package config
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// DefFavoriteBackups is the number of favorites backups kept when not set in the config file.
+	DefFavoriteBackups = 10
+
+	favoritesBackupDir     = "favorites-backups"
+	favoritesBackupPrefix  = "favorites-"
+	favoritesBackupExt     = ".json"
+	favoritesBackupTimeFmt = "20060102-150405"
+)
+
+// FavoritesBackup is a copy of the favorites, in their order, with their names, notes and labels.
+type FavoritesBackup struct {
+	Favorites []string          `json:"favorites"`
+	Aliases   map[string]string `json:"aliases,omitempty"`
+	Notes     map[string]string `json:"notes,omitempty"`
+	Labels    map[string]Label  `json:"labels,omitempty"`
+}
+
+// FavoritesBackupFile is a favorites backup written in the config dir.
+type FavoritesBackupFile struct {
+	Path  string
+	Time  time.Time
+	Count int // number of favorites
+}
+
+// FavoritesBackup returns a copy of the favorites, the names, notes and labels of the stations
+// removed from the favorites being left out.
+func (v *Value) FavoritesBackup() FavoritesBackup {
+	b := FavoritesBackup{Favorites: slices.Clone(v.Favorites)}
+	for _, uuid := range v.Favorites {
+		if a, ok := v.Aliases[uuid]; ok {
+			b.Aliases = setEntry(b.Aliases, uuid, a)
+		}
+		if n, ok := v.Notes[uuid]; ok {
+			b.Notes = setEntry(b.Notes, uuid, n)
+		}
+		if l, ok := v.Labels[uuid]; ok {
+			b.Labels = setEntry(b.Labels, uuid, l)
+		}
+	}
+	return b
+}
+
+func setEntry[V any](m map[string]V, k string, v V) map[string]V {
+	if m == nil {
+		m = make(map[string]V)
+	}
+	m[k] = v
+	return m
+}
+
+// Equal reports whether the backups have the same favorites, names, notes and labels.
+func (b FavoritesBackup) Equal(o FavoritesBackup) bool {
+	return slices.Equal(b.Favorites, o.Favorites) && maps.Equal(b.Aliases, o.Aliases) &&
+		maps.Equal(b.Notes, o.Notes) && maps.Equal(b.Labels, o.Labels)
+}
+
+// RestoreFavorites replaces the favorites with the ones of the backup, recording the changes for the
+// sync, and restores their names, notes and labels.
+func (v *Value) RestoreFavorites(b FavoritesBackup) {
+	v.SetFavorites(b.Favorites)
+	for _, uuid := range b.Favorites {
+		v.untrash(uuid)
+	}
+	for uuid, a := range b.Aliases {
+		v.Aliases = setEntry(v.Aliases, uuid, a)
+	}
+	for uuid, n := range b.Notes {
+		v.Notes = setEntry(v.Notes, uuid, n)
+	}
+	for uuid, l := range b.Labels {
+		v.Labels = setEntry(v.Labels, uuid, l)
+	}
+}
+
+// FavoriteBackupsKept returns the number of favorites backups kept, 0 for none to be written.
+func (v *Value) FavoriteBackupsKept() int {
+	if v.FavoriteBackups == nil {
+		return DefFavoriteBackups
+	}
+	return max(*v.FavoriteBackups, 0)
+}
+
+func getOrCreateFavoritesBackupDir() (string, error) {
+	dir, err := getOrCreateConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return getOrCreateDir(filepath.Join(dir, favoritesBackupDir))
+}
+
+// WriteFavoritesBackup writes the backup named after its time, then removes the oldest backups but
+// the last keep ones.
+func WriteFavoritesBackup(b FavoritesBackup, now time.Time, keep int) error {
+	dir, err := getOrCreateFavoritesBackupDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := favoritesBackupPrefix + now.Format(favoritesBackupTimeFmt) + favoritesBackupExt
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		return err
+	}
+
+	names, err := favoritesBackupNames(dir)
+	if err != nil {
+		return err
+	}
+	for _, n := range names[min(len(names), max(keep, 1)):] {
+		if err := os.Remove(filepath.Join(dir, n)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// favoritesBackupNames returns the names of the backups in dir, the latest first.
+func favoritesBackupNames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if _, ok := favoritesBackupTime(e.Name()); ok && e.Type().IsRegular() {
+			names = append(names, e.Name())
+		}
+	}
+	// the names sort by time
+	slices.Sort(names)
+	slices.Reverse(names)
+	return names, nil
+}
+
+func favoritesBackupTime(name string) (time.Time, bool) {
+	s, ok := strings.CutPrefix(name, favoritesBackupPrefix)
+	if !ok {
+		return time.Time{}, false
+	}
+	if s, ok = strings.CutSuffix(s, favoritesBackupExt); !ok {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(favoritesBackupTimeFmt, s, time.Local)
+	return t, err == nil
+}
+
+// FavoritesBackups returns the favorites backups written, the latest first. The backups which can't be
+// read are left out.
+func FavoritesBackups() ([]FavoritesBackupFile, error) {
+	dir, err := getOrCreateFavoritesBackupDir()
+	if err != nil {
+		return nil, err
+	}
+	names, err := favoritesBackupNames(dir)
+	if err != nil {
+		return nil, err
+	}
+	var res []FavoritesBackupFile
+	for _, n := range names {
+		fp := filepath.Join(dir, n)
+		b, err := ReadFavoritesBackup(fp)
+		if err != nil {
+			continue
+		}
+		t, _ := favoritesBackupTime(n)
+		res = append(res, FavoritesBackupFile{Path: fp, Time: t, Count: len(b.Favorites)})
+	}
+	return res, nil
+}
+
+// ReadFavoritesBackup reads the backup written by WriteFavoritesBackup.
+func ReadFavoritesBackup(path string) (FavoritesBackup, error) {
+	var b FavoritesBackup
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return b, err
+	}
+	err = json.Unmarshal(data, &b)
+	return b, err
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFavoritesBackup(t *testing.T) {
+	cfg := &Value{
+		Favorites: []string{"a", "b"},
+		Aliases:   map[string]string{"a": "Morning", "x": "removed"},
+		Labels:    map[string]Label{"b": LabelRed},
+	}
+	b := cfg.FavoritesBackup()
+	if len(b.Aliases) != 1 || b.Labels["b"] != LabelRed || b.Notes != nil {
+		t.Errorf("backup %+v, want the entries of the favorites", b)
+	}
+	if !b.Equal(cfg.FavoritesBackup()) {
+		t.Error("expected the unchanged favorites equal")
+	}
+
+	cfg.DeleteFavorite("a")
+	cfg.TrashFavorite("a", "Radio A", time.Now())
+	cfg.SetAlias("b", "Evening")
+	if b.Equal(cfg.FavoritesBackup()) {
+		t.Error("expected the changed favorites not equal")
+	}
+
+	cfg.RestoreFavorites(b)
+	if !slices.Equal(cfg.Favorites, []string{"a", "b"}) || cfg.Alias("a") != "Morning" || len(cfg.Trash) != 0 {
+		t.Errorf("favorites %v, aliases %v, trash %v after the restore", cfg.Favorites, cfg.Aliases, cfg.Trash)
+	}
+}
+
+func TestWriteFavoritesBackup(t *testing.T) {
+	UseDir(t.TempDir())
+	t.Cleanup(func() { UseDir("") })
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	for i := range 4 {
+		b := FavoritesBackup{Favorites: make([]string, i)}
+		if err := WriteFavoritesBackup(b, start.Add(time.Duration(i)*time.Minute), 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := FavoritesBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("got %d backups, want the last 3", len(files))
+	}
+	if !files[0].Time.Equal(start.Add(3*time.Minute)) || files[0].Count != 3 || files[2].Count != 1 {
+		t.Errorf("backups %+v, want the latest first", files)
+	}
+	b, err := ReadFavoritesBackup(files[1].Path)
+	if err != nil || len(b.Favorites) != 2 {
+		t.Errorf("read %+v, %v", b, err)
+	}
+}
+
+func TestFavoriteBackupsKept(t *testing.T) {
+	cfg := &Value{}
+	if cfg.FavoriteBackupsKept() != DefFavoriteBackups {
+		t.Errorf("kept %d, want the default", cfg.FavoriteBackupsKept())
+	}
+	n := -1
+	cfg.FavoriteBackups = &n
+	if cfg.FavoriteBackupsKept() != 0 {
+		t.Errorf("kept %d, want none", cfg.FavoriteBackupsKept())
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"slices"
+	"strings"
+)
+
+// StationGuide is the program schedule attached to a favorite station.
+type StationGuide struct {
+	URL      string   `json:"url"`
+	Followed []string `json:"followed,omitempty"` // Titles of the programs notified when they start
+}
+
+func (v *Value) GetGuide(uuid string) (StationGuide, bool) {
+	v.guidesMtx.Lock()
+	defer v.guidesMtx.Unlock()
+	g, ok := v.Guides[uuid]
+	return g, ok
+}
+
+// SetGuideURL attaches the schedule at url to the station, or removes its guide if url is empty.
+func (v *Value) SetGuideURL(uuid, url string) {
+	v.guidesMtx.Lock()
+	defer v.guidesMtx.Unlock()
+	if url == "" {
+		delete(v.Guides, uuid)
+		return
+	}
+	if v.Guides == nil {
+		v.Guides = make(map[string]StationGuide)
+	}
+	g := v.Guides[uuid]
+	g.URL = url
+	v.Guides[uuid] = g
+}
+
+// ToggleFollowed follows or unfollows the program of the station, and returns if it's followed.
+func (v *Value) ToggleFollowed(uuid, title string) bool {
+	v.guidesMtx.Lock()
+	defer v.guidesMtx.Unlock()
+	g, ok := v.Guides[uuid]
+	if !ok {
+		return false
+	}
+	followed := !slices.Contains(g.Followed, title)
+	if followed {
+		g.Followed = append(g.Followed, title)
+	} else {
+		g.Followed = slices.DeleteFunc(g.Followed, func(t string) bool { return t == title })
+	}
+	v.Guides[uuid] = g
+	return followed
+}
+
+// GuideStations returns the stations with a program guide.
+func (v *Value) GuideStations() []string {
+	v.guidesMtx.Lock()
+	defer v.guidesMtx.Unlock()
+	uuids := make([]string, 0, len(v.Guides))
+	for uuid := range v.Guides {
+		uuids = append(uuids, uuid)
+	}
+	slices.Sort(uuids)
+	return uuids
+}
+
+// InterestWeights returns the weight of the tags the user is interested in. The InterestTags
+// are weighted by their order, the first one the most, otherwise the tags are weighted by
+// how many times they appear in favoriteTags.
+func (v *Value) InterestWeights(favoriteTags []string) map[string]int {
+	weights := make(map[string]int)
+	if len(v.InterestTags) > 0 {
+		for i, t := range v.InterestTags {
+			t = normalizeTag(t)
+			if _, ok := weights[t]; !ok && t != "" {
+				weights[t] = len(v.InterestTags) - i
+			}
+		}
+		return weights
+	}
+	for _, t := range favoriteTags {
+		if t = normalizeTag(t); t != "" {
+			weights[t]++
+		}
+	}
+	return weights
+}
+
+// InterestScore sums the weights of the comma separated tags of a station.
+func InterestScore(tags string, weights map[string]int) int {
+	var score int
+	for _, t := range strings.Split(tags, ",") {
+		score += weights[normalizeTag(t)]
+	}
+	return score
+}
+
+func normalizeTag(t string) string {
+	return strings.ToLower(strings.TrimSpace(t))
+}
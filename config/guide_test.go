@@ -0,0 +1,43 @@
+package config
+
+import "testing"
+
+func TestGuide(t *testing.T) {
+	cfg := &Value{}
+	if cfg.ToggleFollowed("1", "Jazz night") {
+		t.Error("followed a program without guide")
+	}
+	cfg.SetGuideURL("1", "https://radiox.example/schedule.ics")
+	if !cfg.ToggleFollowed("1", "Jazz night") {
+		t.Error("program not followed")
+	}
+	cfg.SetGuideURL("1", "https://radiox.example/schedule.json")
+	g, ok := cfg.GetGuide("1")
+	if !ok || g.URL != "https://radiox.example/schedule.json" || len(g.Followed) != 1 {
+		t.Errorf("guide %+v", g)
+	}
+	if cfg.ToggleFollowed("1", "Jazz night") {
+		t.Error("program still followed")
+	}
+	cfg.SetGuideURL("1", "")
+	if len(cfg.GuideStations()) != 0 {
+		t.Error("guide not removed")
+	}
+}
+
+func TestInterestWeights(t *testing.T) {
+	cfg := &Value{}
+	w := cfg.InterestWeights([]string{"jazz", "Jazz ", "news", ""})
+	if w["jazz"] != 2 || w["news"] != 1 || len(w) != 2 {
+		t.Errorf("weights of the favorite tags %v", w)
+	}
+	if s := InterestScore("rock,JAZZ, news", w); s != 3 {
+		t.Errorf("score %d, want 3", s)
+	}
+
+	cfg.InterestTags = []string{"News", "jazz", "news"}
+	w = cfg.InterestWeights([]string{"jazz", "jazz"})
+	if w["news"] != 3 || w["jazz"] != 2 || len(w) != 2 {
+		t.Errorf("weights of the interest tags %v", w)
+	}
+}
@@ -38,6 +38,8 @@ func (v *Value) DeleteHistoryEntry(delEntry HistoryEntry) {
 	v.History = slices.DeleteFunc(v.History, func(e HistoryEntry) bool {
 		return e.Timestamp.Equal(delEntry.Timestamp)
 	})
+	v.HistoryDeleted = append(v.HistoryDeleted, delEntry.Timestamp)
+	v.HistoryDeleted = v.HistoryDeleted[max(0, len(v.HistoryDeleted)-*v.HistorySaveMax):]
 	v.saveHistory()
 }
 
@@ -46,6 +48,8 @@ func (v *Value) ClearHistory() {
 	defer v.historyMtx.Unlock()
 
 	v.History = v.History[:0]
+	v.HistoryDeleted = nil
+	v.HistoryCleared = time.Now()
 	v.saveHistory()
 }
 
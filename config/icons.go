@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"slices"
+	"strings"
+)
+
+// IconSet is the glyphs of the play, pause, favorite and recording indicators.
+type IconSet string
+
+const (
+	// IconsAuto is the Nerd Font icons in the terminals bundling them, ASCII in the ones without Unicode
+	// fonts and the Unicode symbols otherwise
+	IconsAuto    IconSet = ""
+	IconsNerd    IconSet = "nerd"
+	IconsUnicode IconSet = "unicode"
+	IconsASCII   IconSet = "ascii"
+)
+
+var (
+	// nerdFontTerms are the terminals bundling the Nerd Font symbols, as TERM or TERM_PROGRAM
+	nerdFontTerms = []string{"xterm-kitty", "xterm-ghostty", "ghostty", "wezterm", "WezTerm"}
+	// asciiTerms are the terminals whose fonts lack most of the Unicode symbols, like the Linux console
+	asciiTerms = []string{"linux", "vt100", "vt220", "dumb"}
+)
+
+// Icons returns the glyphs of the indicators, as set or detected.
+func (v *Value) Icons() IconSet {
+	if v.IconSet != IconsAuto {
+		return v.IconSet
+	}
+	return detectIcons(os.Getenv("TERM"), os.Getenv("TERM_PROGRAM"), locale())
+}
+
+func detectIcons(term, termProgram, locale string) IconSet {
+	switch {
+	case slices.Contains(nerdFontTerms, term) || slices.Contains(nerdFontTerms, termProgram):
+		return IconsNerd
+	case slices.Contains(asciiTerms, term):
+		return IconsASCII
+	case !utf8Locale(locale):
+		return IconsASCII
+	}
+	return IconsUnicode
+}
+
+// utf8Locale reports whether the charset of the locale, like en_US.UTF-8, is UTF-8. The locales without
+// one, like C, are taken as UTF-8, the charset of the terminals nowadays.
+func utf8Locale(locale string) bool {
+	_, charset, ok := strings.Cut(locale, ".")
+	if !ok {
+		return true
+	}
+	charset, _, _ = strings.Cut(charset, "@")
+	charset = strings.ToLower(strings.ReplaceAll(charset, "-", ""))
+	return charset == "utf8"
+}
+
+// locale returns the locale of the characters, the first of the variables set, as the C library does.
+func locale() string {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if l := os.Getenv(name); l != "" {
+			return l
+		}
+	}
+	return ""
+}
@@ -0,0 +1,36 @@
+package config
+
+import "testing"
+
+func Test_detectIcons(t *testing.T) {
+	tests := []struct {
+		term, termProgram, locale string
+		want                      IconSet
+	}{
+		{"xterm-kitty", "", "en_US.UTF-8", IconsNerd},
+		{"xterm-256color", "WezTerm", "en_US.UTF-8", IconsNerd},
+		{"xterm-ghostty", "", "", IconsNerd},
+		{"xterm-256color", "", "en_US.UTF-8", IconsUnicode},
+		{"xterm-256color", "", "de_DE.utf8", IconsUnicode},
+		{"xterm-256color", "", "C", IconsUnicode},
+		{"xterm-256color", "", "", IconsUnicode},
+		{"xterm-256color", "", "fr_FR.ISO-8859-15@euro", IconsASCII},
+		{"linux", "", "en_US.UTF-8", IconsASCII},
+	}
+	for _, tt := range tests {
+		if got := detectIcons(tt.term, tt.termProgram, tt.locale); got != tt.want {
+			t.Errorf("detectIcons(%q, %q, %q)=%q, want %q", tt.term, tt.termProgram, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestValue_Icons(t *testing.T) {
+	t.Setenv("TERM", "linux")
+	t.Setenv("TERM_PROGRAM", "")
+	if got := (&Value{}).Icons(); got != IconsASCII {
+		t.Errorf("got %q, want ASCII detected", got)
+	}
+	if got := (&Value{IconSet: IconsNerd}).Icons(); got != IconsNerd {
+		t.Errorf("got %q, want the Nerd Font icons set", got)
+	}
+}
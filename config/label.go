@@ -0,0 +1,81 @@
+package config
+
+import "strings"
+
+// Label is a color label grouping the favorites.
+type Label uint8
+
+func (l Label) String() string {
+	switch l {
+	case NoLabel:
+		return "none"
+	case LabelRed:
+		return "red"
+	case LabelOrange:
+		return "orange"
+	case LabelYellow:
+		return "yellow"
+	case LabelGreen:
+		return "green"
+	case LabelBlue:
+		return "blue"
+	case LabelPurple:
+		return "purple"
+	}
+	return "unknown Label"
+}
+
+const (
+	NoLabel Label = iota
+	LabelRed
+	LabelOrange
+	LabelYellow
+	LabelGreen
+	LabelBlue
+	LabelPurple
+)
+
+// Labels are the color labels in the order they are cycled through.
+var Labels = []Label{LabelRed, LabelOrange, LabelYellow, LabelGreen, LabelBlue, LabelPurple}
+
+// Next returns the label after l, NoLabel after the last one.
+func (l Label) Next() Label {
+	return (l + 1) % Label(len(Labels)+1)
+}
+
+// ParseLabel returns the label whose name starts with prefix.
+func ParseLabel(prefix string) (Label, bool) {
+	prefix = strings.ToLower(prefix)
+	if prefix == "" {
+		return NoLabel, false
+	}
+	for _, l := range Labels {
+		if strings.HasPrefix(l.String(), prefix) {
+			return l, true
+		}
+	}
+	return NoLabel, false
+}
+
+// Label returns the color label of the favorite.
+func (v *Value) Label(uuid string) Label {
+	if !v.IsFavorite(uuid) {
+		return NoLabel
+	}
+	return v.Labels[uuid]
+}
+
+// SetLabel sets the color label of the favorite, NoLabel removes it.
+func (v *Value) SetLabel(uuid string, l Label) {
+	if l == NoLabel {
+		delete(v.Labels, uuid)
+		return
+	}
+	if !v.IsFavorite(uuid) {
+		return
+	}
+	if v.Labels == nil {
+		v.Labels = make(map[string]Label)
+	}
+	v.Labels[uuid] = l
+}
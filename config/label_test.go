@@ -0,0 +1,39 @@
+package config
+
+import "testing"
+
+func TestLabel(t *testing.T) {
+	cfg := &Value{Favorites: []string{"a"}}
+	l := NoLabel
+	for range Labels {
+		l = l.Next()
+	}
+	if l != LabelPurple || l.Next() != NoLabel {
+		t.Errorf("cycled to %v, then %v", l, l.Next())
+	}
+
+	cfg.SetLabel("a", LabelGreen)
+	cfg.SetLabel("x", LabelRed)
+	if cfg.Label("a") != LabelGreen || cfg.Label("x") != NoLabel {
+		t.Errorf("labels %v", cfg.Labels)
+	}
+	cfg.SetLabel("a", NoLabel)
+	if len(cfg.Labels) != 0 {
+		t.Errorf("cleared labels %v", cfg.Labels)
+	}
+
+	for _, tc := range []struct {
+		prefix string
+		want   Label
+		ok     bool
+	}{
+		{"gr", LabelGreen, true},
+		{"Blue", LabelBlue, true},
+		{"", NoLabel, false},
+		{"pink", NoLabel, false},
+	} {
+		if l, ok := ParseLabel(tc.prefix); l != tc.want || ok != tc.ok {
+			t.Errorf("ParseLabel(%q)=%v,%v want %v,%v", tc.prefix, l, ok, tc.want, tc.ok)
+		}
+	}
+}
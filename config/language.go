@@ -0,0 +1,50 @@
+package config
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// LanguageTime is how long the user listened to stations speaking a language.
+type LanguageTime struct {
+	Language string
+	Time     time.Duration
+}
+
+// AddListening adds d to each of the languages of a station, the comma separated list of radio-browser.
+func (v *Value) AddListening(languages string, d time.Duration) {
+	sec := int64(d.Seconds())
+	if sec <= 0 {
+		return
+	}
+	v.listeningMtx.Lock()
+	defer v.listeningMtx.Unlock()
+
+	for _, l := range strings.Split(languages, ",") {
+		l = strings.ToLower(strings.TrimSpace(l))
+		if l == "" {
+			continue
+		}
+		if v.Listening == nil {
+			v.Listening = make(map[string]int64)
+		}
+		v.Listening[l] += sec
+	}
+}
+
+// ListeningTimes returns the time listened to each language, the most listened first.
+func (v *Value) ListeningTimes() []LanguageTime {
+	v.listeningMtx.Lock()
+	defer v.listeningMtx.Unlock()
+
+	res := make([]LanguageTime, 0, len(v.Listening))
+	for l, sec := range v.Listening {
+		res = append(res, LanguageTime{Language: l, Time: time.Duration(sec) * time.Second})
+	}
+	slices.SortFunc(res, func(a, b LanguageTime) int {
+		return cmp.Or(cmp.Compare(b.Time, a.Time), cmp.Compare(a.Language, b.Language))
+	})
+	return res
+}
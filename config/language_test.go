@@ -0,0 +1,20 @@
+package config
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestListening(t *testing.T) {
+	cfg := &Value{}
+	cfg.AddListening("French, english", 10*time.Minute)
+	cfg.AddListening("french", 5*time.Minute)
+	cfg.AddListening("", time.Hour)
+	cfg.AddListening("german", 500*time.Millisecond)
+
+	want := []LanguageTime{{"french", 15 * time.Minute}, {"english", 10 * time.Minute}}
+	if got := cfg.ListeningTimes(); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,16 @@
+package config
+
+import "cmp"
+
+const DefNearbyRadiusKm = 100
+
+// Location is where the user is, for the stations near them.
+type Location struct {
+	Lat      float64 `json:"lat"`
+	Long     float64 `json:"long"`
+	RadiusKm int     `json:"radiusKm,omitempty"` // Distance of the stations near the location, DefNearbyRadiusKm by default
+}
+
+func (l Location) Radius() int {
+	return cmp.Or(l.RadiusKm, DefNearbyRadiusKm)
+}
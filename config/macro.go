@@ -0,0 +1,46 @@
+package config
+
+import (
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	// MacroSlots is the number of macros, bound to the keys f1 to f12.
+	MacroSlots = 12
+	// MacroStepDelay is the pause between the keys of a macro, for the view to follow.
+	MacroStepDelay = 50 * time.Millisecond
+	// MacroMaxKeys limits the keys recorded, in case the recording is forgotten.
+	MacroMaxKeys = 200
+)
+
+// MacroKey returns the function key of the macro slot, from 1.
+func MacroKey(slot int) string {
+	return "f" + strconv.Itoa(slot)
+}
+
+// IsMacroKey tells if the key is one the macros are bound to.
+func IsMacroKey(k string) bool {
+	for slot := 1; slot <= MacroSlots; slot++ {
+		if MacroKey(slot) == k {
+			return true
+		}
+	}
+	return false
+}
+
+// SetMacro binds the key sequence to the macro key, an empty sequence removing the macro.
+func (v *Value) SetMacro(key string, keys []string) {
+	if !IsMacroKey(key) {
+		return
+	}
+	if len(keys) == 0 {
+		delete(v.Macros, key)
+		return
+	}
+	if v.Macros == nil {
+		v.Macros = make(map[string][]string)
+	}
+	v.Macros[key] = slices.Clone(keys)
+}
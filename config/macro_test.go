@@ -0,0 +1,31 @@
+package config
+
+import "testing"
+
+func TestIsMacroKey(t *testing.T) {
+	for _, k := range []string{"f1", "f9", "f12"} {
+		if !IsMacroKey(k) {
+			t.Errorf("%q not a macro key", k)
+		}
+	}
+	for _, k := range []string{"", "f", "f0", "f13", "f01", "g1", "ctrl+f1", "1"} {
+		if IsMacroKey(k) {
+			t.Errorf("%q is a macro key", k)
+		}
+	}
+}
+
+func TestSetMacro(t *testing.T) {
+	cfg := &Value{}
+	keys := []string{"s", "j", "a", "z", "z", "enter"}
+	cfg.SetMacro("f2", keys)
+	cfg.SetMacro("x", keys)
+	keys[0] = "q"
+	if len(cfg.Macros) != 1 || cfg.Macros["f2"][0] != "s" {
+		t.Fatalf("macros %v", cfg.Macros)
+	}
+	cfg.SetMacro("f2", nil)
+	if len(cfg.Macros) != 0 {
+		t.Errorf("macro not removed %v", cfg.Macros)
+	}
+}
@@ -0,0 +1,33 @@
+package config
+
+import "time"
+
+// MetadataPollManual is the MetadataPollMs of the manual refresh of the song title only.
+const MetadataPollManual = -1
+
+// metadataPollMin is the shortest interval between the polls of each player: FFplay's title is scraped
+// from its whole output, VLC is asked over its console, while mpv answers on its IPC socket and MPlayer
+// keeps the title read.
+var metadataPollMin = map[PlayerType]time.Duration{
+	Mpv:     250 * time.Millisecond,
+	FFPlay:  time.Second,
+	Vlc:     500 * time.Millisecond,
+	MPlayer: 250 * time.Millisecond,
+}
+
+// MetadataPollMin returns the shortest interval between the song title polls of the player.
+func MetadataPollMin(p PlayerType) time.Duration {
+	return max(metadataPollMin[p], MetadataPollJitter)
+}
+
+// MetadataPoll returns the interval between the song title polls of the player, the configured one
+// or the default, but not under the minimum of the player. It's false for the manual refresh only.
+func (v *Value) MetadataPoll() (time.Duration, bool) {
+	switch {
+	case v.MetadataPollMs < 0:
+		return 0, false
+	case v.MetadataPollMs == 0:
+		return max(MetadataPollInterval, MetadataPollMin(v.Player)), true
+	}
+	return max(time.Duration(v.MetadataPollMs)*time.Millisecond, MetadataPollMin(v.Player)), true
+}
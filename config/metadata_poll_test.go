@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetadataPoll(t *testing.T) {
+	for _, tt := range []struct {
+		player PlayerType
+		ms     int
+		want   time.Duration
+		ok     bool
+	}{
+		{Mpv, 0, MetadataPollInterval, true},
+		{FFPlay, 0, time.Second, true},
+		{Mpv, 2000, 2 * time.Second, true},
+		{FFPlay, 250, time.Second, true},
+		{Vlc, MetadataPollManual, 0, false},
+	} {
+		cfg := &Value{Player: tt.player, MetadataPollMs: tt.ms}
+		if got, ok := cfg.MetadataPoll(); got != tt.want || ok != tt.ok {
+			t.Errorf("%v %dms: got %v, %v, want %v, %v", tt.player, tt.ms, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import "strings"
+
+// Note returns the note attached to the favorite, "" if it has none.
+func (v *Value) Note(uuid string) string {
+	if !v.IsFavorite(uuid) {
+		return ""
+	}
+	return v.Notes[uuid]
+}
+
+// SetNote attaches a note to the favorite, an empty note removes it.
+func (v *Value) SetNote(uuid, note string) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		delete(v.Notes, uuid)
+		return
+	}
+	if !v.IsFavorite(uuid) {
+		return
+	}
+	if v.Notes == nil {
+		v.Notes = make(map[string]string)
+	}
+	v.Notes[uuid] = note
+}
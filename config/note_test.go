@@ -0,0 +1,20 @@
+package config
+
+import "testing"
+
+func TestNote(t *testing.T) {
+	cfg := &Value{Favorites: []string{"a"}}
+	cfg.SetNote("a", " morning show, CET ")
+	cfg.SetNote("x", "not a favorite")
+	if note := cfg.Note("a"); note != "morning show, CET" {
+		t.Errorf("note %q", note)
+	}
+	if cfg.Note("x") != "" || len(cfg.Notes) != 1 {
+		t.Errorf("note of a station not in favorites %v", cfg.Notes)
+	}
+
+	cfg.SetNote("a", "")
+	if cfg.Note("a") != "" || len(cfg.Notes) != 0 {
+		t.Errorf("cleared note %v", cfg.Notes)
+	}
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"time"
+)
+
+// DefQuietMaxVolume is the default volume cap of the quiet hours.
+const DefQuietMaxVolume = 30
+
+// QuietHours are the hours of the day the desktop notifications and the reminders are held and the
+// volume is capped, whatever the settings of these features.
+type QuietHours struct {
+	From      string `json:"from"`                // Local time of the day they start, as 22:00
+	To        string `json:"to"`                  // Local time of the day they end, the next day if not after From
+	MaxVolume int    `json:"maxVolume,omitempty"` // Volume cap, DefQuietMaxVolume if 0
+}
+
+// Active tells if now is within the quiet hours, false if their times are invalid.
+func (q QuietHours) Active(now time.Time) bool {
+	from, err := time.Parse(reminderAtFormat, q.From)
+	if err != nil {
+		return false
+	}
+	to, err := time.Parse(reminderAtFormat, q.To)
+	if err != nil {
+		return false
+	}
+	now = now.Local()
+	at := now.Hour()*60 + now.Minute()
+	start, end := from.Hour()*60+from.Minute(), to.Hour()*60+to.Minute()
+	if start < end {
+		return at >= start && at < end
+	}
+	return at >= start || at < end
+}
+
+// Quiet tells if now is within the quiet hours.
+func (v *Value) Quiet(now time.Time) bool {
+	return v.QuietHours != nil && v.QuietHours.Active(now)
+}
+
+// QuietMaxVolume returns the volume cap of the quiet hours.
+func (v *Value) QuietMaxVolume() int {
+	if v.QuietHours == nil || v.QuietHours.MaxVolume <= 0 || v.QuietHours.MaxVolume > 100 {
+		return DefQuietMaxVolume
+	}
+	return v.QuietHours.MaxVolume
+}
+
+// QuietVolume returns the volume capped if now is within the quiet hours.
+func (v *Value) QuietVolume(vol int, now time.Time) int {
+	if !v.Quiet(now) {
+		return vol
+	}
+	return min(vol, v.QuietMaxVolume())
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHours_Active(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2026, 10, 16, hour, minute, 0, 0, time.Local) }
+	tests := []struct {
+		q    QuietHours
+		now  time.Time
+		want bool
+	}{
+		{QuietHours{From: "22:00", To: "07:00"}, at(23, 30), true},
+		{QuietHours{From: "22:00", To: "07:00"}, at(6, 59), true},
+		{QuietHours{From: "22:00", To: "07:00"}, at(7, 0), false},
+		{QuietHours{From: "22:00", To: "07:00"}, at(21, 59), false},
+		{QuietHours{From: "13:00", To: "15:00"}, at(14, 0), true},
+		{QuietHours{From: "13:00", To: "15:00"}, at(15, 0), false},
+		{QuietHours{From: "0:00", To: "0:00"}, at(12, 0), true},
+		{QuietHours{From: "22:00"}, at(23, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.q.Active(tt.now); got != tt.want {
+			t.Errorf("%+v.Active(%s) = %v, want %v", tt.q, tt.now.Format("15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestValue_QuietVolume(t *testing.T) {
+	night := time.Date(2026, 10, 16, 23, 0, 0, 0, time.Local)
+	day := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	v := &Value{}
+	if got := v.QuietVolume(80, night); got != 80 {
+		t.Errorf("got %d without quiet hours, want 80", got)
+	}
+	v.QuietHours = &QuietHours{From: "22:00", To: "07:00"}
+	if got := v.QuietVolume(80, night); got != DefQuietMaxVolume {
+		t.Errorf("got %d, want the default cap", got)
+	}
+	v.QuietHours.MaxVolume = 50
+	if got := v.QuietVolume(80, night); got != 50 {
+		t.Errorf("got %d, want 50", got)
+	}
+	if got := v.QuietVolume(20, night); got != 20 {
+		t.Errorf("got %d, want the volume under the cap kept", got)
+	}
+	if got := v.QuietVolume(80, day); got != 80 {
+		t.Errorf("got %d out of the quiet hours, want 80", got)
+	}
+}
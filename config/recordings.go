@@ -0,0 +1,188 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+const recordingsSubDir = "sonicradio"
+
+type Repeat uint8
+
+const (
+	RepeatOnce Repeat = iota
+	RepeatDaily
+	RepeatWeekly
+)
+
+var Repeats = [3]Repeat{RepeatOnce, RepeatDaily, RepeatWeekly}
+
+func (r Repeat) String() string {
+	switch r {
+	case RepeatOnce:
+		return "Once"
+	case RepeatDaily:
+		return "Daily"
+	case RepeatWeekly:
+		return "Weekly"
+	}
+	return "unknown Repeat"
+}
+
+// RecordingSchedule is a time window during which a station is recorded, optionally repeated.
+type RecordingSchedule struct {
+	ID          string        `json:"id"`
+	StationUuid string        `json:"stationUuid"`
+	StationName string        `json:"stationName"`
+	URL         string        `json:"url"`
+	Start       time.Time     `json:"start"`
+	Duration    time.Duration `json:"duration"`
+	Repeat      Repeat        `json:"repeat"`
+}
+
+// Next returns the start of the first occurrence that has not ended at now.
+// It returns false if a one-time schedule has ended.
+func (s RecordingSchedule) Next(now time.Time) (time.Time, bool) {
+	start := s.Start
+	var step int
+	switch s.Repeat {
+	case RepeatDaily:
+		step = 1
+	case RepeatWeekly:
+		step = 7
+	}
+	if step > 0 {
+		// AddDate keeps the wall clock time across daylight saving changes
+		for !start.Add(s.Duration).After(now) {
+			start = start.AddDate(0, 0, step)
+		}
+	}
+	if !start.Add(s.Duration).After(now) {
+		return time.Time{}, false
+	}
+	return start, true
+}
+
+func (s RecordingSchedule) Title() string {
+	return s.StationName
+}
+
+func (s RecordingSchedule) Description() string {
+	start, ok := s.Next(time.Now())
+	if !ok {
+		start = s.Start
+	}
+	end := start.Add(s.Duration)
+	desc := fmt.Sprintf("%s - %s", start.Format(tsFormat), end.Format("15:04"))
+	if s.Repeat != RepeatOnce {
+		desc += fmt.Sprintf(" (%s)", s.Repeat)
+	}
+	return desc
+}
+
+func (s RecordingSchedule) FilterValue() string {
+	return s.StationName
+}
+
+// RecordingEntry is a finished recording.
+type RecordingEntry struct {
+	ScheduleID  string    `json:"scheduleId"`
+	StationName string    `json:"stationName"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Path        string    `json:"path"`
+	Size        int64     `json:"size"`
+	Err         string    `json:"error,omitempty"`
+}
+
+func (e RecordingEntry) Title() string {
+	return e.StationName
+}
+
+func (e RecordingEntry) Description() string {
+	desc := fmt.Sprintf("%s - %s %s %s", e.Start.Format(tsFormat), e.End.Format("15:04"), separator, filepath.Base(e.Path))
+	if e.Err != "" {
+		desc += fmt.Sprintf(" %s %s", separator, e.Err)
+	}
+	return desc
+}
+
+func (e RecordingEntry) FilterValue() string {
+	return e.StationName + filepath.Base(e.Path)
+}
+
+// GetRecordingsDir returns the dir where recordings are saved, the user's music dir by default.
+func (v *Value) GetRecordingsDir() (string, error) {
+	if v.RecordingsDir != "" {
+		return v.RecordingsDir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("get user home dir: %v", err)
+	}
+	return filepath.Join(home, "Music", recordingsSubDir), nil
+}
+
+func (v *Value) GetSchedules() []RecordingSchedule {
+	v.recordingsMtx.Lock()
+	defer v.recordingsMtx.Unlock()
+	return slices.Clone(v.Schedules)
+}
+
+func (v *Value) GetRecordings() []RecordingEntry {
+	v.recordingsMtx.Lock()
+	defer v.recordingsMtx.Unlock()
+	return slices.Clone(v.Recordings)
+}
+
+func (v *Value) AddSchedule(s RecordingSchedule) {
+	v.recordingsMtx.Lock()
+	defer v.recordingsMtx.Unlock()
+	v.Schedules = append(v.Schedules, s)
+}
+
+func (v *Value) DeleteSchedule(id string) {
+	v.recordingsMtx.Lock()
+	defer v.recordingsMtx.Unlock()
+	v.Schedules = slices.DeleteFunc(v.Schedules, func(s RecordingSchedule) bool { return s.ID == id })
+}
+
+// AddRecording saves a finished recording. If done, a schedule that doesn't repeat is removed.
+func (v *Value) AddRecording(e RecordingEntry, done bool) {
+	v.recordingsMtx.Lock()
+	defer v.recordingsMtx.Unlock()
+	v.Recordings = append(v.Recordings, e)
+	if !done {
+		return
+	}
+	v.Schedules = slices.DeleteFunc(v.Schedules, func(s RecordingSchedule) bool {
+		return s.ID == e.ScheduleID && s.Repeat == RepeatOnce
+	})
+}
+
+// DeleteRecording removes the entry, the recorded file is kept.
+func (v *Value) DeleteRecording(e RecordingEntry) {
+	v.recordingsMtx.Lock()
+	defer v.recordingsMtx.Unlock()
+	v.Recordings = slices.DeleteFunc(v.Recordings, func(r RecordingEntry) bool {
+		return r.Path == e.Path && r.Start.Equal(e.Start)
+	})
+}
+
+// PruneRecordings removes the entries whose recorded file or folder no longer exists, e.g. after the disk quota was enforced.
+func (v *Value) PruneRecordings() {
+	v.recordingsMtx.Lock()
+	defer v.recordingsMtx.Unlock()
+	v.Recordings = slices.DeleteFunc(v.Recordings, func(r RecordingEntry) bool {
+		if r.Path == "" {
+			return false
+		}
+		_, err := os.Stat(r.Path)
+		return errors.Is(err, fs.ErrNotExist)
+	})
+}
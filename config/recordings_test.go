@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordingSchedule_Next(t *testing.T) {
+	loc := time.UTC
+	// a Saturday
+	start := time.Date(2024, 6, 1, 18, 0, 0, 0, loc)
+	tests := []struct {
+		name   string
+		repeat Repeat
+		now    time.Time
+		want   time.Time
+		wantOk bool
+	}{
+		{"once, before", RepeatOnce, start.Add(-time.Hour), start, true},
+		{"once, during", RepeatOnce, start.Add(time.Hour), start, true},
+		{"once, ended", RepeatOnce, start.Add(3 * time.Hour), time.Time{}, false},
+		{"daily, next day", RepeatDaily, start.Add(3 * time.Hour), start.AddDate(0, 0, 1), true},
+		{"weekly, during a later week", RepeatWeekly, start.AddDate(0, 0, 14).Add(time.Hour), start.AddDate(0, 0, 14), true},
+		{"weekly, after", RepeatWeekly, start.AddDate(0, 0, 2), start.AddDate(0, 0, 7), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := RecordingSchedule{Start: start, Duration: 2 * time.Hour, Repeat: tt.repeat}
+			got, ok := s.Next(tt.now)
+			if ok != tt.wantOk || !got.Equal(tt.want) {
+				t.Errorf("got (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestValue_AddRecording(t *testing.T) {
+	v := &Value{Schedules: []RecordingSchedule{
+		{ID: "once", Repeat: RepeatOnce},
+		{ID: "weekly", Repeat: RepeatWeekly},
+	}}
+	v.AddRecording(RecordingEntry{ScheduleID: "once"}, false)
+	if len(v.Schedules) != 2 {
+		t.Errorf("got schedules %+v, want interrupted schedule kept", v.Schedules)
+	}
+	v.AddRecording(RecordingEntry{ScheduleID: "once"}, true)
+	v.AddRecording(RecordingEntry{ScheduleID: "weekly"}, true)
+	if len(v.Schedules) != 1 || v.Schedules[0].ID != "weekly" {
+		t.Errorf("got schedules %+v, want only the weekly one", v.Schedules)
+	}
+	if len(v.Recordings) != 3 {
+		t.Errorf("got %d recordings, want 3", len(v.Recordings))
+	}
+}
+
+func TestPruneRecordings(t *testing.T) {
+	dir := t.TempDir()
+	kept := filepath.Join(dir, "kept.mp3")
+	if err := os.WriteFile(kept, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	v := &Value{Recordings: []RecordingEntry{
+		{Path: kept},
+		{Path: filepath.Join(dir, "removed.mp3")},
+		{Err: "could not connect"},
+	}}
+	v.PruneRecordings()
+	if len(v.Recordings) != 2 || v.Recordings[0].Path != kept || v.Recordings[1].Err == "" {
+		t.Errorf("got %+v", v.Recordings)
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const redacted = "[redacted]"
+
+// secretFields are the JSON names of the values not shared in the reports, like the passwords.
+var secretFields = map[string]bool{
+	"password":    true,
+	"user":        true,
+	"acoustIdKey": true,
+	"location":    true,
+}
+
+// Redacted returns the settings set, one "name: value" per line, e.g. for a crash report. The secrets are
+// redacted, as the credentials of the URLs, and the collections like the favorites and the history are
+// replaced by their sizes.
+func (v *Value) Redacted() string {
+	var b strings.Builder
+	writeRedacted(&b, "", reflect.ValueOf(v).Elem())
+	return b.String()
+}
+
+func writeRedacted(b *strings.Builder, prefix string, rv reflect.Value) {
+	t := rv.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fv := rv.Field(i)
+		if fv.IsZero() {
+			continue
+		}
+		if fv.Kind() == reflect.Pointer {
+			fv = fv.Elem()
+		}
+
+		switch {
+		case secretFields[name]:
+			fmt.Fprintf(b, "%s%s: %s\n", prefix, name, redacted)
+		case fv.Kind() == reflect.Map || fv.Kind() == reflect.Slice:
+			fmt.Fprintf(b, "%s%s: %d entries\n", prefix, name, fv.Len())
+		case fv.Kind() == reflect.Struct && fv.Type() != reflect.TypeOf(time.Time{}):
+			writeRedacted(b, prefix+name+".", fv)
+		case fv.Kind() == reflect.String:
+			fmt.Fprintf(b, "%s%s: %s\n", prefix, name, redactURL(fv.String()))
+		default:
+			fmt.Fprintf(b, "%s%s: %v\n", prefix, name, fv.Interface())
+		}
+	}
+}
+
+// redactURL hides the credentials of a URL, returning any other text as it is.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	u.User = url.User("redacted")
+	return u.String()
+}
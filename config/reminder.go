@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const reminderAtFormat = "15:04"
+
+var (
+	weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	weekends = []time.Weekday{time.Saturday, time.Sunday}
+
+	errReminderSpec = errors.New("expected [daily|weekdays|weekends|mon,wed,...] HH:MM")
+)
+
+// Reminder tells to tune into a favorite at a time of the day, with the station, for the daemon to
+// play it without the app.
+type Reminder struct {
+	StationName string         `json:"stationName"`
+	URL         string         `json:"url"`
+	Days        []time.Weekday `json:"days,omitempty"` // Every day if empty
+	At          string         `json:"at"`             // Local time of the day, as 18:00
+}
+
+// ParseReminder returns the reminder of the spec, the days followed by the time, e.g. "weekdays 18:00",
+// "mon,wed,fri 7:30" or "18:00" for every day.
+func ParseReminder(spec string) (Reminder, error) {
+	fields := strings.Fields(strings.ToLower(spec))
+	if len(fields) == 0 || len(fields) > 2 {
+		return Reminder{}, errReminderSpec
+	}
+	at, err := time.Parse(reminderAtFormat, fields[len(fields)-1])
+	if err != nil {
+		return Reminder{}, errReminderSpec
+	}
+	r := Reminder{At: at.Format(reminderAtFormat)}
+	if len(fields) == 1 {
+		return r, nil
+	}
+	switch fields[0] {
+	case "daily":
+	case "weekdays":
+		r.Days = slices.Clone(weekdays)
+	case "weekends":
+		r.Days = slices.Clone(weekends)
+	default:
+		for _, name := range strings.Split(fields[0], ",") {
+			d, ok := parseWeekday(name)
+			if !ok {
+				return Reminder{}, fmt.Errorf("unknown day %q, %w", name, errReminderSpec)
+			}
+			if !slices.Contains(r.Days, d) {
+				r.Days = append(r.Days, d)
+			}
+		}
+	}
+	return r, nil
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	if len(name) < 3 {
+		return 0, false
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.HasPrefix(strings.ToLower(d.String()), name) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// Spec returns the days and the time of the reminder, as parsed by ParseReminder.
+func (r Reminder) Spec() string {
+	sorted := slices.Clone(r.Days)
+	slices.Sort(sorted)
+	switch {
+	case len(r.Days) == 0 || len(sorted) == 7:
+		return "daily " + r.At
+	case slices.Equal(sorted, weekdays):
+		return "weekdays " + r.At
+	case slices.Equal(sorted, []time.Weekday{time.Sunday, time.Saturday}):
+		return "weekends " + r.At
+	}
+	names := make([]string, len(r.Days))
+	for i, d := range r.Days {
+		names[i] = strings.ToLower(d.String()[:3])
+	}
+	return strings.Join(names, ",") + " " + r.At
+}
+
+// Due tells if the reminder is due after from, until to. After a suspend of more than a week, only the
+// last week is checked.
+func (r Reminder) Due(from, to time.Time) bool {
+	at, err := time.Parse(reminderAtFormat, r.At)
+	if err != nil || !to.After(from) {
+		return false
+	}
+	if week := to.AddDate(0, 0, -7); from.Before(week) {
+		from = week
+	}
+	from, to = from.Local(), to.Local()
+	for day := from; !day.After(to.AddDate(0, 0, 1)); day = day.AddDate(0, 0, 1) {
+		if len(r.Days) > 0 && !slices.Contains(r.Days, day.Weekday()) {
+			continue
+		}
+		t := time.Date(day.Year(), day.Month(), day.Day(), at.Hour(), at.Minute(), 0, 0, time.Local)
+		if t.After(from) && !t.After(to) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reminder returns the reminder of the favorite, false if it has none.
+func (v *Value) Reminder(uuid string) (Reminder, bool) {
+	if !v.IsFavorite(uuid) {
+		return Reminder{}, false
+	}
+	r, ok := v.Reminders[uuid]
+	return r, ok
+}
+
+// SetReminder sets the reminder of the favorite, nil removing it.
+func (v *Value) SetReminder(uuid string, r *Reminder) {
+	if r == nil {
+		delete(v.Reminders, uuid)
+		return
+	}
+	if !v.IsFavorite(uuid) {
+		return
+	}
+	if v.Reminders == nil {
+		v.Reminders = make(map[string]Reminder)
+	}
+	v.Reminders[uuid] = *r
+}
+
+// DueReminders returns the favorites whose reminder is due after from, until to.
+func (v *Value) DueReminders(from, to time.Time) []string {
+	var res []string
+	for uuid, r := range v.Reminders {
+		if v.IsFavorite(uuid) && r.Due(from, to) {
+			res = append(res, uuid)
+		}
+	}
+	slices.Sort(res)
+	return res
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseReminder(t *testing.T) {
+	tests := []struct {
+		spec    string
+		days    []time.Weekday
+		at      string
+		wantErr bool
+	}{
+		{spec: "18:00", at: "18:00"},
+		{spec: "daily 7:30", at: "07:30"},
+		{spec: "Weekdays 18:00", days: weekdays, at: "18:00"},
+		{spec: "weekends 10:15", days: weekends, at: "10:15"},
+		{spec: "mon,wednesday,fri,mon 18:00", days: []time.Weekday{time.Monday, time.Wednesday, time.Friday}, at: "18:00"},
+		{spec: "", wantErr: true},
+		{spec: "weekdays", wantErr: true},
+		{spec: "mo 18:00", wantErr: true},
+		{spec: "daily 25:00", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			r, err := ParseReminder(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReminder(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !slices.Equal(r.Days, tt.days) || r.At != tt.at {
+				t.Errorf("got %v at %s, want %v at %s", r.Days, r.At, tt.days, tt.at)
+			}
+			if err != nil {
+				return
+			}
+			again, err := ParseReminder(r.Spec())
+			if err != nil || !slices.Equal(again.Days, r.Days) || again.At != r.At {
+				t.Errorf("spec %q parsed again as %+v, %v", r.Spec(), again, err)
+			}
+		})
+	}
+}
+
+func TestReminder_Due(t *testing.T) {
+	r := Reminder{Days: weekdays, At: "18:00"}
+	// a Friday
+	fri := time.Date(2026, 10, 16, 17, 59, 30, 0, time.Local)
+	if !r.Due(fri, fri.Add(time.Minute)) {
+		t.Error("expected due at 18:00 on a weekday")
+	}
+	if r.Due(fri.Add(time.Minute), fri.Add(2*time.Minute)) {
+		t.Error("expected due once")
+	}
+	sat := fri.AddDate(0, 0, 1)
+	if r.Due(sat, sat.Add(time.Minute)) {
+		t.Error("expected not due on Saturday")
+	}
+	// after a suspend over the weekend, due on Monday
+	if !r.Due(sat, sat.AddDate(0, 0, 2).Add(time.Minute)) {
+		t.Error("expected due on Monday")
+	}
+	if (Reminder{At: "18:00"}).Due(sat, sat.Add(time.Minute)) != true {
+		t.Error("expected due every day")
+	}
+}
+
+func TestValue_DueReminders(t *testing.T) {
+	v := &Value{Favorites: []string{"a", "b"}}
+	r, _ := ParseReminder("daily 18:00")
+	v.SetReminder("a", &r)
+	v.SetReminder("c", &r)
+	if _, ok := v.Reminder("c"); ok {
+		t.Fatal("expected no reminder for a station which isn't a favorite")
+	}
+	from := time.Date(2026, 10, 16, 17, 59, 0, 0, time.Local)
+	if got := v.DueReminders(from, from.Add(time.Minute)); !slices.Equal(got, []string{"a"}) {
+		t.Errorf("got due %v, want a", got)
+	}
+	v.SetReminder("a", nil)
+	if got := v.DueReminders(from, from.Add(time.Minute)); len(got) != 0 {
+		t.Errorf("got due %v after the reminder was removed", got)
+	}
+}
@@ -0,0 +1,47 @@
+package config
+
+import "os"
+
+// RenderProfile is how often and how richly the view is drawn.
+type RenderProfile string
+
+const (
+	// RenderAuto is the low refresh profile in a remote session, over SSH, and the full one otherwise
+	RenderAuto RenderProfile = ""
+	RenderFull RenderProfile = "full"
+	// RenderLow draws the view at LowRefreshFPS, without the animations and with the 16 ANSI colors,
+	// for the high latency links
+	RenderLow RenderProfile = "low"
+
+	LowRefreshFPS = 4
+)
+
+// sshEnvVars are set by the SSH server in the remote sessions
+var sshEnvVars = []string{"SSH_CONNECTION", "SSH_CLIENT", "SSH_TTY"}
+
+// RemoteSession reports whether the app runs in an SSH session, where every redraw crosses the network.
+// Only the SSH variables are checked, the latency isn't measured: a slow link without SSH isn't detected.
+func RemoteSession() bool {
+	for _, name := range sshEnvVars {
+		if os.Getenv(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// LowRefresh reports whether the view is drawn with the low refresh profile, as set or detected.
+func (v *Value) LowRefresh() bool {
+	switch v.RenderProfile {
+	case RenderLow:
+		return true
+	case RenderFull:
+		return false
+	}
+	return RemoteSession()
+}
+
+// NoMotion reports whether the animations are off, with the reduced motion or the low refresh.
+func (v *Value) NoMotion() bool {
+	return v.ReducedMotion || v.LowRefresh()
+}
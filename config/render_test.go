@@ -0,0 +1,29 @@
+package config
+
+import "testing"
+
+func TestLowRefresh(t *testing.T) {
+	for _, name := range sshEnvVars {
+		t.Setenv(name, "")
+	}
+	tests := []struct {
+		profile RenderProfile
+		ssh     string
+		want    bool
+	}{
+		{RenderAuto, "", false},
+		{RenderAuto, "10.0.0.2 51234 10.0.0.1 22", true},
+		{RenderFull, "10.0.0.2 51234 10.0.0.1 22", false},
+		{RenderLow, "", true},
+	}
+	for _, tt := range tests {
+		t.Setenv("SSH_CONNECTION", tt.ssh)
+		cfg := &Value{RenderProfile: tt.profile}
+		if got := cfg.LowRefresh(); got != tt.want {
+			t.Errorf("profile %q, SSH_CONNECTION %q: low refresh %v, want %v", tt.profile, tt.ssh, got, tt.want)
+		}
+		if got := cfg.NoMotion(); got != tt.want {
+			t.Errorf("profile %q, SSH_CONNECTION %q: no motion %v, want %v", tt.profile, tt.ssh, got, tt.want)
+		}
+	}
+}
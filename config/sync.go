@@ -0,0 +1,198 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SyncFavorite is a favorite in the sync file, kept after it's removed so that the removal is synced too.
+type SyncFavorite struct {
+	Uuid     string    `json:"uuid"`
+	Deleted  bool      `json:"deleted,omitempty"`
+	Modified time.Time `json:"modified"`
+}
+
+// SyncData is the content of the sync file, shared by the machines through a synced folder.
+type SyncData struct {
+	Favorites      []SyncFavorite `json:"favorites"` // The favorites in order, then the removed ones
+	History        []HistoryEntry `json:"history,omitempty"`
+	HistoryDeleted []time.Time    `json:"historyDeleted,omitempty"`
+	HistoryCleared time.Time      `json:"historyCleared"`
+
+	raw []byte
+}
+
+// WebdavSync is a file on a WebDAV server, like Nextcloud, used as the sync file.
+type WebdavSync struct {
+	URL      string `json:"url"` // e.g. https://cloud.example.com/remote.php/dav/files/me/sonicradio.json
+	User     string `json:"user"`
+	Password string `json:"password"` // An app password, for servers with two-factor authentication
+}
+
+// ReadSync reads the sync file, an empty SyncData if it doesn't exist yet.
+func ReadSync(path string) (*SyncData, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &SyncData{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return DecodeSync(b)
+}
+
+// DecodeSync parses the content of a sync file.
+func DecodeSync(b []byte) (*SyncData, error) {
+	d := &SyncData{}
+	if err := json.Unmarshal(b, d); err != nil {
+		return nil, err
+	}
+	d.raw = b
+	return d, nil
+}
+
+// EncodeSync returns the content of the sync file, and whether it changed since it was read.
+func EncodeSync(d *SyncData) (b []byte, changed bool, err error) {
+	b, err = json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, false, err
+	}
+	return b, !bytes.Equal(b, d.raw), nil
+}
+
+// WriteSync writes the data to the sync file, unless it has not changed since it was read.
+// It writes a temporary file first, so that a partly written file is never synced.
+func WriteSync(path string, d *SyncData) error {
+	b, changed, err := EncodeSync(d)
+	if err != nil || !changed {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// touchFavorite records the time the favorite was added or removed, for the sync.
+func (v *Value) touchFavorite(uuid string) {
+	if v.FavoriteChanges == nil {
+		v.FavoriteChanges = make(map[string]time.Time)
+	}
+	v.FavoriteChanges[uuid] = time.Now()
+}
+
+func (v *Value) syncData() *SyncData {
+	d := &SyncData{
+		History:        slices.Clone(v.History),
+		HistoryDeleted: slices.Clone(v.HistoryDeleted),
+		HistoryCleared: v.HistoryCleared,
+	}
+	for _, uuid := range v.Favorites {
+		d.Favorites = append(d.Favorites, SyncFavorite{Uuid: uuid, Modified: v.FavoriteChanges[uuid]})
+	}
+	var deleted []SyncFavorite
+	for uuid, t := range v.FavoriteChanges {
+		if !v.IsFavorite(uuid) {
+			deleted = append(deleted, SyncFavorite{Uuid: uuid, Deleted: true, Modified: t})
+		}
+	}
+	slices.SortFunc(deleted, func(a, b SyncFavorite) int { return strings.Compare(a.Uuid, b.Uuid) })
+	d.Favorites = append(d.Favorites, deleted...)
+	return d
+}
+
+// MergeSync merges the data of the sync file into the favorites and the history, the latest change
+// of each entry winning, and returns the data to write back. changed reports whether the favorites
+// or the history changed.
+func (v *Value) MergeSync(remote *SyncData) (merged *SyncData, changed bool) {
+	v.historyMtx.Lock()
+	defer v.historyMtx.Unlock()
+
+	merged = mergeSyncData(v.syncData(), remote, *v.HistorySaveMax)
+	merged.raw = remote.raw
+
+	var favorites []string
+	changes := make(map[string]time.Time)
+	for _, f := range merged.Favorites {
+		if !f.Deleted {
+			favorites = append(favorites, f.Uuid)
+		}
+		if !f.Modified.IsZero() {
+			changes[f.Uuid] = f.Modified
+		}
+	}
+	changed = !slices.Equal(favorites, v.Favorites) || !slices.EqualFunc(merged.History, v.History, func(a, b HistoryEntry) bool {
+		return a.Timestamp.Equal(b.Timestamp) && a.Uuid == b.Uuid && a.Song == b.Song
+	})
+	v.Favorites = favorites
+	v.FavoriteChanges = changes
+	v.History = slices.Clone(merged.History)
+	v.HistoryDeleted = slices.Clone(merged.HistoryDeleted)
+	v.HistoryCleared = merged.HistoryCleared
+	return merged, changed
+}
+
+func mergeSyncData(local, remote *SyncData, historyMax int) *SyncData {
+	res := &SyncData{}
+
+	latest := make(map[string]SyncFavorite)
+	for _, f := range slices.Concat(local.Favorites, remote.Favorites) {
+		if prev, ok := latest[f.Uuid]; !ok || f.Modified.After(prev.Modified) {
+			latest[f.Uuid] = f
+		}
+	}
+	// the local order first, then the favorites added on the other machines
+	added := make(map[string]bool)
+	for _, f := range slices.Concat(local.Favorites, remote.Favorites) {
+		if f.Deleted || added[f.Uuid] || latest[f.Uuid].Deleted {
+			continue
+		}
+		added[f.Uuid] = true
+		res.Favorites = append(res.Favorites, latest[f.Uuid])
+	}
+	var deleted []SyncFavorite
+	for _, f := range latest {
+		if f.Deleted {
+			deleted = append(deleted, f)
+		}
+	}
+	slices.SortFunc(deleted, func(a, b SyncFavorite) int { return strings.Compare(a.Uuid, b.Uuid) })
+	res.Favorites = append(res.Favorites, deleted...)
+
+	res.HistoryCleared = local.HistoryCleared
+	if remote.HistoryCleared.After(res.HistoryCleared) {
+		res.HistoryCleared = remote.HistoryCleared
+	}
+	isDeleted := make(map[int64]bool)
+	for _, t := range slices.Concat(local.HistoryDeleted, remote.HistoryDeleted) {
+		if !isDeleted[t.UnixNano()] && t.After(res.HistoryCleared) {
+			isDeleted[t.UnixNano()] = true
+			res.HistoryDeleted = append(res.HistoryDeleted, t)
+		}
+	}
+	slices.SortFunc(res.HistoryDeleted, func(a, b time.Time) int { return a.Compare(b) })
+	res.HistoryDeleted = res.HistoryDeleted[max(0, len(res.HistoryDeleted)-historyMax):]
+
+	seen := make(map[int64]bool)
+	for _, e := range slices.Concat(local.History, remote.History) {
+		ts := e.Timestamp.UnixNano()
+		if seen[ts] || isDeleted[ts] || !e.Timestamp.After(res.HistoryCleared) {
+			continue
+		}
+		seen[ts] = true
+		res.History = append(res.History, e)
+	}
+	slices.SortStableFunc(res.History, func(a, b HistoryEntry) int { return a.Timestamp.Compare(b.Timestamp) })
+	res.History = res.History[max(0, len(res.History)-historyMax):]
+	return res
+}
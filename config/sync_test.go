@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func newSyncValue(favorites ...string) *Value {
+	historyMax := DefHistorySaveMax
+	return &Value{Favorites: favorites, HistorySaveMax: &historyMax}
+}
+
+func syncWith(t *testing.T, v *Value, path string) bool {
+	t.Helper()
+	remote, err := ReadSync(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, changed := v.MergeSync(remote)
+	if err := WriteSync(path, merged); err != nil {
+		t.Fatal(err)
+	}
+	return changed
+}
+
+func TestSync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sonicradio-sync.json")
+	now := time.Now()
+	a := newSyncValue("1", "2")
+	a.History = []HistoryEntry{{Uuid: "1", Song: "a1", Timestamp: now.Add(-3 * time.Minute)}}
+	b := newSyncValue()
+	b.History = []HistoryEntry{{Uuid: "3", Song: "b1", Timestamp: now.Add(-2 * time.Minute)}}
+
+	if syncWith(t, a, path) {
+		t.Error("changed by an empty sync file")
+	}
+	if !syncWith(t, b, path) {
+		t.Error("not changed by the sync file")
+	}
+	if !slices.Equal(b.Favorites, []string{"1", "2"}) || len(b.History) != 2 || b.History[0].Song != "a1" {
+		t.Fatalf("merged favorites %v, history %v", b.Favorites, b.History)
+	}
+
+	b.DeleteFavorite("1")
+	b.ToggleFavorite("4")
+	b.DeleteHistoryEntry(b.History[0])
+	syncWith(t, b, path)
+	time.Sleep(time.Millisecond)
+	a.ToggleFavorite("5")
+	syncWith(t, a, path)
+	if !slices.Equal(a.Favorites, []string{"2", "5", "4"}) {
+		t.Errorf("merged favorites %v", a.Favorites)
+	}
+	if len(a.History) != 1 || a.History[0].Song != "b1" {
+		t.Errorf("merged history %v", a.History)
+	}
+
+	a.ToggleFavorite("1")
+	syncWith(t, a, path)
+	syncWith(t, b, path)
+	if !slices.Equal(b.Favorites, []string{"2", "4", "5", "1"}) {
+		t.Errorf("favorite added again %v", b.Favorites)
+	}
+
+	b.ClearHistory()
+	syncWith(t, b, path)
+	syncWith(t, a, path)
+	if len(a.History) != 0 {
+		t.Errorf("cleared history %v", a.History)
+	}
+}
+
+func TestGitSyncDir(t *testing.T) {
+	dir := t.TempDir()
+	UseDir(dir)
+	t.Cleanup(func() { UseDir("") })
+	head := filepath.Join(dir, "cache", GitSyncSubDir, ".git", "HEAD")
+	if err := os.MkdirAll(filepath.Dir(head), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(head, []byte("ref: refs/heads/main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := GitSyncDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "config", GitSyncSubDir); got != want {
+		t.Fatalf("GitSyncDir = %s, want %s", got, want)
+	}
+	// the clone of the cache dir is moved
+	if _, err := os.Stat(filepath.Join(got, ".git", "HEAD")); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(head); !os.IsNotExist(err) {
+		t.Error("the old clone was kept")
+	}
+}
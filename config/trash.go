@@ -0,0 +1,52 @@
+package config
+
+import (
+	"slices"
+	"time"
+)
+
+// TrashRetention is how long a removed favorite stays in the trash, restorable, before being purged.
+const TrashRetention = 30 * 24 * time.Hour
+
+// TrashedFavorite is a favorite removed less than TrashRetention ago, with its name for the list.
+// Its note and label aren't removed from the config, so the restore finds them back.
+type TrashedFavorite struct {
+	Uuid    string    `json:"uuid"`
+	Name    string    `json:"name"`
+	Removed time.Time `json:"removed"`
+}
+
+// Expires returns when the favorite is purged from the trash.
+func (t TrashedFavorite) Expires() time.Time {
+	return t.Removed.Add(TrashRetention)
+}
+
+// TrashFavorite puts the removed favorite in the trash, the latest first, named for the list.
+func (v *Value) TrashFavorite(uuid, name string, now time.Time) {
+	v.untrash(uuid)
+	v.Trash = slices.Insert(v.Trash, 0, TrashedFavorite{Uuid: uuid, Name: name, Removed: now})
+}
+
+// RestoreFavorite adds the favorite in the trash back to the end of the favorites, returning false
+// if it's not in the trash.
+func (v *Value) RestoreFavorite(uuid string) bool {
+	if !v.untrash(uuid) {
+		return false
+	}
+	v.InsertFavorite(uuid, len(v.Favorites))
+	return true
+}
+
+// PurgeTrash removes the favorites trashed more than TrashRetention before now, returning how many.
+func (v *Value) PurgeTrash(now time.Time) int {
+	l := len(v.Trash)
+	v.Trash = slices.DeleteFunc(v.Trash, func(t TrashedFavorite) bool { return !now.Before(t.Expires()) })
+	return l - len(v.Trash)
+}
+
+// untrash removes the station from the trash, once it's a favorite again.
+func (v *Value) untrash(uuid string) bool {
+	l := len(v.Trash)
+	v.Trash = slices.DeleteFunc(v.Trash, func(t TrashedFavorite) bool { return t.Uuid == uuid })
+	return l != len(v.Trash)
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTrash(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := &Value{
+		Favorites: []string{"a", "b", "c"},
+		Notes:     map[string]string{"a": "morning show"},
+		Labels:    map[string]Label{"a": LabelBlue},
+	}
+
+	cfg.DeleteFavorite("a")
+	cfg.TrashFavorite("a", "Radio A", now)
+	cfg.ToggleFavorite("b")
+	cfg.TrashFavorite("b", "Radio B", now.Add(time.Hour))
+	if len(cfg.Trash) != 2 || cfg.Trash[0].Uuid != "b" || cfg.Trash[1].Name != "Radio A" {
+		t.Fatalf("trash %+v, want the latest first", cfg.Trash)
+	}
+
+	if !cfg.RestoreFavorite("a") || cfg.RestoreFavorite("x") {
+		t.Error("expected only the trashed favorite restored")
+	}
+	if !slices.Equal(cfg.Favorites, []string{"c", "a"}) || cfg.Note("a") != "morning show" || cfg.Label("a") != LabelBlue {
+		t.Errorf("favorites %v, note %q, label %v after the restore", cfg.Favorites, cfg.Note("a"), cfg.Label("a"))
+	}
+
+	cfg.ToggleFavorite("b")
+	if len(cfg.Trash) != 0 {
+		t.Errorf("trash %+v, want a favorite added back out of the trash", cfg.Trash)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := &Value{}
+	cfg.TrashFavorite("old", "Old", now.Add(-TrashRetention))
+	cfg.TrashFavorite("recent", "Recent", now.Add(-TrashRetention+time.Minute))
+
+	if n := cfg.PurgeTrash(now); n != 1 {
+		t.Errorf("purged %d, want 1", n)
+	}
+	if len(cfg.Trash) != 1 || cfg.Trash[0].Uuid != "recent" {
+		t.Errorf("trash %+v, want the recent favorite kept", cfg.Trash)
+	}
+}
@@ -0,0 +1,75 @@
+// Package crash writes the reports of the panics of the app, with the stack, the config without its secrets
+// and the last log lines, to be attached to an issue.
+package crash
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	// LogLines is the number of the last log lines kept for the reports
+	LogLines = 200
+
+	reportsSubDir = "crashes"
+	reportTimeFmt = "20060102-150405"
+	noConfig      = "(none loaded)"
+)
+
+// Logs keeps the last lines of the app log, the logger writing to it too.
+var Logs = NewTail(LogLines)
+
+// Crash is a recovered panic.
+type Crash struct {
+	Value any
+	Stack []byte
+	Time  time.Time
+}
+
+// Recovered returns the crash of the value recovered from a panic, with the stack of the panicking goroutine.
+// It must be called by the deferred function recovering it.
+func Recovered(value any) *Crash {
+	return &Crash{Value: value, Stack: debug.Stack(), Time: time.Now()}
+}
+
+// Report returns the text of the crash report.
+func (c *Crash) Report(cfg *config.Value, logs []string) string {
+	var b strings.Builder
+	version, settings := "", noConfig
+	if cfg != nil {
+		version, settings = cfg.Version, cfg.Redacted()
+	}
+	fmt.Fprintf(&b, "sonicradio %s crashed at %s\n", version, c.Time.Format(time.RFC3339))
+	fmt.Fprintf(&b, "%s %s/%s\n\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "panic: %v\n\n%s\n", c.Value, c.Stack)
+	fmt.Fprintf(&b, "config:\n%s\n", settings)
+	fmt.Fprintf(&b, "last %d log lines:\n", len(logs))
+	for _, l := range logs {
+		b.WriteString(l + "\n")
+	}
+	return b.String()
+}
+
+// Write saves the report in the cache dir, with the last lines of Logs, and returns its path.
+func (c *Crash) Write(cfg *config.Value) (string, error) {
+	dir, err := config.GetOrCreateCacheDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, reportsSubDir)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("create the crash reports dir: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s.txt", c.Time.Format(reportTimeFmt)))
+	if err := os.WriteFile(path, []byte(c.Report(cfg, Logs.Lines())), 0o600); err != nil {
+		return "", fmt.Errorf("write the crash report: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,62 @@
+package crash
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+func recovered() (c *Crash) {
+	defer func() { c = Recovered(recover()) }()
+	panic("boom")
+}
+
+func Test_Report(t *testing.T) {
+	c := recovered()
+	cfg := &config.Value{
+		Version:    "1.2.3",
+		Favorites:  []string{"uuid-1", "uuid-2"},
+		SyncWebdav: &config.WebdavSync{URL: "https://cloud.example.com/sonicradio.json", User: "me", Password: "hunter2"},
+	}
+	report := c.Report(cfg, []string{"level=INFO msg=started"})
+	for _, want := range []string{
+		"sonicradio 1.2.3 crashed at ",
+		"panic: boom",
+		"crash.recovered",
+		"favorites: 2 entries",
+		"syncWebdav.url: https://cloud.example.com/sonicradio.json",
+		"syncWebdav.password: [redacted]",
+		"last 1 log lines:\nlevel=INFO msg=started",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("missing %q in the report:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "hunter2") || strings.Contains(report, "uuid-1") {
+		t.Errorf("expected the secrets and the favorites left out:\n%s", report)
+	}
+}
+
+func Test_Write(t *testing.T) {
+	dir := t.TempDir()
+	config.UseDir(dir)
+	t.Cleanup(func() { config.UseDir("") })
+
+	path, err := recovered().Write(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(path) != filepath.Join(dir, "cache", reportsSubDir) {
+		t.Errorf("got the report at %s", path)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "panic: boom") || !strings.Contains(string(b), noConfig) {
+		t.Errorf("got the report:\n%s", b)
+	}
+}
@@ -0,0 +1,49 @@
+package crash
+
+import (
+	"strings"
+	"sync"
+)
+
+// Tail is a writer keeping its last lines.
+type Tail struct {
+	mtx   sync.Mutex
+	lines []string
+	next  int
+	full  bool
+	// partial is the end of the last write, until its line ends
+	partial string
+}
+
+func NewTail(size int) *Tail {
+	return &Tail{lines: make([]string, max(1, size))}
+}
+
+func (t *Tail) Write(p []byte) (int, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	text := t.partial + string(p)
+	lines := strings.Split(text, "\n")
+	t.partial = lines[len(lines)-1]
+	for _, l := range lines[:len(lines)-1] {
+		t.lines[t.next] = l
+		t.next = (t.next + 1) % len(t.lines)
+		t.full = t.full || t.next == 0
+	}
+	return len(p), nil
+}
+
+// Lines returns the last lines written, the oldest first.
+func (t *Tail) Lines() []string {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	var res []string
+	if t.full {
+		res = append(res, t.lines[t.next:]...)
+	}
+	res = append(res, t.lines[:t.next]...)
+	if t.partial != "" {
+		res = append(res, t.partial)
+	}
+	return res[max(0, len(res)-len(t.lines)):]
+}
@@ -0,0 +1,26 @@
+package crash
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func Test_Tail(t *testing.T) {
+	tail := NewTail(3)
+	if got := tail.Lines(); len(got) != 0 {
+		t.Fatalf("got %v, want no lines", got)
+	}
+	fmt.Fprint(tail, "a\nb\n")
+	if got := tail.Lines(); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("got %v", got)
+	}
+	fmt.Fprint(tail, "c\nd\ne")
+	if got := tail.Lines(); !slices.Equal(got, []string{"c", "d", "e"}) {
+		t.Errorf("got %v, want the last lines and the unfinished one", got)
+	}
+	fmt.Fprint(tail, "nd\n")
+	if got := tail.Lines(); !slices.Equal(got, []string{"c", "d", "end"}) {
+		t.Errorf("got %v, want the unfinished line completed", got)
+	}
+}
@@ -0,0 +1,222 @@
+// Package daemon runs the player in the background, for the app to attach to over a unix socket.
+// It can be started by systemd socket activation, so the playback continues when the terminal is closed.
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/dancnb/sonicradio/audiosink"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/notify"
+	"github.com/dancnb/sonicradio/player"
+	"github.com/dancnb/sonicradio/player/model"
+	"github.com/dancnb/sonicradio/player/remote"
+)
+
+// Backend is the player controlled by the daemon.
+type Backend interface {
+	GetType() config.PlayerType
+	Play(url string) error
+	Pause(value bool) error
+	Stop() error
+	SetVolume(value int) (int, error)
+	// SetMaxVolume sets the hard cap of the volume, which SetVolume never exceeds, 0 for none
+	SetMaxVolume(value int) error
+	Metadata() *model.Metadata
+	Seek(amtSec int) *model.Metadata
+}
+
+// Run serves the player on the daemon socket until ctx is done.
+func Run(ctx context.Context, cfg *config.Value) error {
+	log := slog.With("method", "daemon.Run")
+	sd := NewSDNotifier()
+	l, err := Listen(config.DaemonSocketPath())
+	if err != nil {
+		return err
+	}
+	audio, err := audiosink.Setup(cfg.AudioServerPath())
+	if err != nil {
+		log.Info("audio server", "error", err)
+	}
+	// the stations aren't preloaded over the socket, a standby player would only idle
+	cfg.Gapless = false
+	p, err := player.NewPlayer(ctx, cfg)
+	if err != nil {
+		l.Close()
+		return err
+	}
+	defer func() {
+		if err := p.Stop(); err != nil {
+			log.Error("player stop", "error", err)
+		}
+		if err := p.Close(); err != nil {
+			log.Error("player close", "error", err)
+		}
+	}()
+	log.Info("listening", "addr", l.Addr())
+	srv := NewServer(p, cfg.GetVolume())
+	if addr := cfg.HealthCheckAddr(); addr != "" {
+		go srv.serveHealth(ctx, addr, audio)
+	}
+	go srv.WatchReminders(ctx, config.ReminderCheckInterval, config.Load)
+	if sd.Watchdog > 0 {
+		log.Info("watchdog", "interval", sd.Watchdog)
+		go srv.Watchdog(ctx, sd.Watchdog, func() error { return sd.Notify(sdWatchdog) })
+	}
+	if err := sd.Notify(sdReady); err != nil {
+		log.Error("notify ready", "error", err)
+	}
+	defer func() { _ = sd.Notify(sdStopping) }()
+	return srv.Serve(ctx, l)
+}
+
+// Server handles the requests of the attached apps.
+type Server struct {
+	player Backend
+	// stop ends Serve, when an app quits
+	stop context.CancelFunc
+
+	// notify shows the notifications of the reminders, telling if their action was clicked
+	notify func(ctx context.Context, body, action string) (bool, error)
+
+	mtx   sync.Mutex
+	state model.State
+	// attached is the number of the apps attached
+	attached int
+}
+
+func NewServer(p Backend, volume int) *Server {
+	return &Server{player: p, notify: notify.SendAction, state: model.State{Volume: volume}}
+}
+
+// Serve accepts connections until ctx is done or an app quits.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	ctx, s.stop = context.WithCancel(ctx)
+	defer s.stop()
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.handle(ctx, conn)
+		}()
+	}
+}
+
+func (s *Server) handle(ctx context.Context, conn net.Conn) {
+	log := slog.With("method", "daemon.Server.handle")
+	log.Info("attached")
+	defer log.Info("detached")
+	s.mtx.Lock()
+	s.attached++
+	s.mtx.Unlock()
+	defer func() {
+		s.mtx.Lock()
+		s.attached--
+		s.mtx.Unlock()
+	}()
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	defer conn.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	enc := json.NewEncoder(conn)
+	for {
+		var req remote.Request
+		if err := dec.Decode(&req); err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				log.Error("decode request", "error", err)
+			}
+			return
+		}
+		if err := enc.Encode(s.do(req)); err != nil {
+			log.Error("encode response", "error", err)
+			return
+		}
+		if req.Method == remote.MethodQuit {
+			log.Info("quit")
+			s.stop()
+			return
+		}
+	}
+}
+
+func (s *Server) do(req remote.Request) remote.Response {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var res remote.Response
+	var err error
+	switch req.Method {
+	case remote.MethodType:
+		res.PlayerType = s.player.GetType()
+	case remote.MethodPlay:
+		if err = s.player.Play(req.URL); err == nil {
+			s.state = model.State{URL: req.URL, Playing: true, Volume: s.state.Volume}
+		}
+	case remote.MethodPause:
+		if err = s.player.Pause(req.Pause); err == nil {
+			s.state.Paused = req.Pause
+		}
+	case remote.MethodStop:
+		if err = s.player.Stop(); err == nil {
+			s.state = model.State{Volume: s.state.Volume}
+		}
+	case remote.MethodVolume:
+		if res.Volume, err = s.player.SetVolume(req.Value); err == nil {
+			s.state.Volume = res.Volume
+		}
+	case remote.MethodMaxVolume:
+		if err = s.player.SetMaxVolume(req.Value); err != nil {
+			break
+		}
+		// lowered under the cap
+		if res.Volume, err = s.player.SetVolume(s.state.Volume); err == nil {
+			s.state.Volume = res.Volume
+		}
+	case remote.MethodMetadata:
+		res.Metadata = remote.NewMetadata(s.player.Metadata())
+	case remote.MethodSeek:
+		res.Metadata = remote.NewMetadata(s.player.Seek(req.Value))
+	case remote.MethodState:
+		state := s.state
+		state.Pid = os.Getpid()
+		res.State = &state
+	case remote.MethodQuit:
+		// the player is stopped when Serve returns
+	case remote.MethodSetStation:
+		if s.state.Playing {
+			s.state.StationUuid = req.StationUuid
+			s.state.StationName = req.StationName
+		}
+	default:
+		err = fmt.Errorf("unknown method %q", req.Method)
+	}
+	if err != nil {
+		res.Err = err.Error()
+	}
+	return res
+}
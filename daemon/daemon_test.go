@@ -0,0 +1,336 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dancnb/sonicradio/audiosink"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/player/model"
+	"github.com/dancnb/sonicradio/player/remote"
+)
+
+type fakeBackend struct {
+	url       string
+	paused    bool
+	volume    int
+	maxVolume int
+}
+
+func (b *fakeBackend) GetType() config.PlayerType { return config.Vlc }
+
+func (b *fakeBackend) Play(url string) error {
+	if url == "" {
+		return errors.New("empty url")
+	}
+	b.url = url
+	return nil
+}
+
+func (b *fakeBackend) Pause(value bool) error { b.paused = value; return nil }
+
+func (b *fakeBackend) Stop() error { b.url = ""; return nil }
+
+func (b *fakeBackend) SetVolume(value int) (int, error) {
+	b.volume = config.ClampVolume(value, b.maxVolume)
+	return b.volume, nil
+}
+
+func (b *fakeBackend) SetMaxVolume(value int) error { b.maxVolume = value; return nil }
+
+func (b *fakeBackend) Metadata() *model.Metadata { return &model.Metadata{Title: "song of " + b.url} }
+
+func (b *fakeBackend) Seek(amtSec int) *model.Metadata {
+	return &model.Metadata{Err: errors.New("not seekable")}
+}
+
+// socketPath is short, as unix socket paths are limited to about 100 bytes.
+func socketPath(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "srd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "d.sock")
+}
+
+func TestServer(t *testing.T) {
+	path := socketPath(t)
+	l, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error)
+	go func() { served <- NewServer(&fakeBackend{}, 50).Serve(ctx, l) }()
+
+	c, err := remote.Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.GetType() != config.Vlc {
+		t.Errorf("got type %v", c.GetType())
+	}
+	if err := c.Play(""); err == nil || err.Error() != "empty url" {
+		t.Errorf("got play error %v", err)
+	}
+	if err := c.Play("http://stream"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetStation("uuid", "Station"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Pause(true); err != nil {
+		t.Fatal(err)
+	}
+	if m := c.Metadata(); m == nil || m.Title != "song of http://stream" {
+		t.Errorf("got metadata %+v", m)
+	}
+	if m := c.Seek(10); m == nil || m.Err == nil {
+		t.Errorf("got seek metadata %+v", m)
+	}
+	// the app quits, the playback goes on
+	c.Close()
+
+	if _, err := Listen(path); !errors.Is(err, ErrRunning) {
+		t.Errorf("got listen error %v, want ErrRunning", err)
+	}
+
+	c, err = remote.Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	st, err := c.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.State{StationUuid: "uuid", StationName: "Station", URL: "http://stream", Playing: true, Paused: true, Volume: 50, Pid: os.Getpid()}
+	if st != want {
+		t.Errorf("got state %+v, want %+v", st, want)
+	}
+
+	// an app quitting stops the daemon
+	if err := c.Quit(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("daemon did not stop")
+	}
+	cancel()
+}
+
+func TestListen_staleSocket(t *testing.T) {
+	path := socketPath(t)
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	l, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+}
+
+func TestServer_health(t *testing.T) {
+	s := NewServer(&fakeBackend{}, 50)
+	s.do(remote.Request{Method: remote.MethodPlay, URL: "http://stream"})
+	s.do(remote.Request{Method: remote.MethodSetStation, StationUuid: "uuid", StationName: "Station"})
+
+	audioPath := socketPath(t)
+	audio, err := net.Listen("unix", audioPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error)
+	go func() { served <- s.ServeHealth(ctx, l, audiosink.Server{Path: audioPath}) }()
+
+	get := func() (int, Health) {
+		res, err := http.Get("http://" + l.Addr().String() + healthPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		var h Health
+		if err := json.NewDecoder(res.Body).Decode(&h); err != nil {
+			t.Fatal(err)
+		}
+		return res.StatusCode, h
+	}
+	code, h := get()
+	want := Health{Status: "ok", Player: "VLC", Playing: true, Station: "Station", Audio: "unix:" + audioPath}
+	if code != http.StatusOK || h != want {
+		t.Errorf("got %d %+v, want %+v", code, h, want)
+	}
+
+	// the audio server of the host went away
+	audio.Close()
+	if code, h := get(); code != http.StatusServiceUnavailable || h.Status != "unhealthy" || h.AudioError == "" {
+		t.Errorf("got %d %+v, want unhealthy", code, h)
+	}
+
+	cancel()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("health endpoint did not stop")
+	}
+}
+
+func TestServer_reminders(t *testing.T) {
+	b := &fakeBackend{}
+	s := NewServer(b, 50)
+	notified := make(chan string, 1)
+	s.notify = func(ctx context.Context, body, action string) (bool, error) {
+		notified <- body
+		return true, nil
+	}
+	r, err := config.ParseReminder("daily 18:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.StationName, r.URL = "Jazz", "http://jazz"
+	cfg := &config.Value{Favorites: []string{"uuid"}}
+	cfg.SetReminder("uuid", &r)
+	load := func() (*config.Value, error) { return cfg, nil }
+	from := time.Date(2026, 10, 16, 17, 59, 30, 0, time.Local)
+
+	// the attached app notifies the reminders
+	s.attached = 1
+	s.checkReminders(context.Background(), load, from, from.Add(time.Minute))
+	select {
+	case body := <-notified:
+		t.Fatalf("got notified %q while an app is attached", body)
+	default:
+	}
+
+	s.attached = 0
+	s.checkReminders(context.Background(), load, from, from.Add(time.Minute))
+	select {
+	case body := <-notified:
+		if body != "Time to tune into Jazz" {
+			t.Errorf("got notified %q", body)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("reminder not notified")
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		s.mtx.Lock()
+		st := s.state
+		s.mtx.Unlock()
+		if st.StationUuid != "" {
+			if st.URL != "http://jazz" || !st.Playing || st.StationUuid != "uuid" || st.StationName != "Jazz" {
+				t.Errorf("got state %+v, want the reminded station playing", st)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("reminded station not played on the action")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestServer_maxVolume(t *testing.T) {
+	b := &fakeBackend{}
+	s := NewServer(b, 80)
+	if res := s.do(remote.Request{Method: remote.MethodMaxVolume, Value: 60}); res.Err != "" || res.Volume != 60 {
+		t.Fatalf("got %+v, want the volume lowered under the cap", res)
+	}
+	if res := s.do(remote.Request{Method: remote.MethodVolume, Value: 90}); res.Volume != 60 || b.volume != 60 {
+		t.Errorf("got volume %d, player %d, want capped at 60", res.Volume, b.volume)
+	}
+	s.do(remote.Request{Method: remote.MethodMaxVolume})
+	if res := s.do(remote.Request{Method: remote.MethodVolume, Value: 90}); res.Volume != 90 {
+		t.Errorf("got volume %d without a cap, want 90", res.Volume)
+	}
+}
+
+func TestSDNotifier(t *testing.T) {
+	path := socketPath(t)
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", path)
+	t.Setenv("WATCHDOG_USEC", "20000000")
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+
+	n := NewSDNotifier()
+	if n.Watchdog != 10*time.Second {
+		t.Errorf("got the watchdog interval %v, want half the timeout", n.Watchdog)
+	}
+	if _, ok := os.LookupEnv("NOTIFY_SOCKET"); ok {
+		t.Error("expected NOTIFY_SOCKET unset for the player processes")
+	}
+	if err := n.Notify(sdReady); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 64)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	if k, err := conn.Read(buf); err != nil || string(buf[:k]) != sdReady {
+		t.Errorf("got %q, %v, want %s", buf[:k], err, sdReady)
+	}
+
+	t.Setenv("WATCHDOG_USEC", "20000000")
+	t.Setenv("WATCHDOG_PID", "1")
+	if n := NewSDNotifier(); n.Watchdog != 0 || n.Notify(sdReady) != nil {
+		t.Errorf("got %+v, want no watchdog of another process and no socket", n)
+	}
+}
+
+func TestServer_watchdog(t *testing.T) {
+	s := NewServer(&fakeBackend{}, 50)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pings := make(chan struct{}, 16)
+	go s.Watchdog(ctx, 10*time.Millisecond, func() error {
+		pings <- struct{}{}
+		return nil
+	})
+	select {
+	case <-pings:
+	case <-time.After(time.Second):
+		t.Fatal("no watchdog ping")
+	}
+
+	// a request hung on the player, after the pings already passing it
+	s.mtx.Lock()
+	time.Sleep(20 * time.Millisecond)
+	for len(pings) > 0 {
+		<-pings
+	}
+	time.Sleep(50 * time.Millisecond)
+	if len(pings) > 0 {
+		t.Error("expected no ping while a request hangs")
+	}
+	s.mtx.Unlock()
+	select {
+	case <-pings:
+	case <-time.After(time.Second):
+		t.Fatal("no watchdog ping after the request")
+	}
+}
@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/dancnb/sonicradio/audiosink"
+)
+
+const (
+	healthPath            = "/healthz"
+	healthShutdownTimeout = 2 * time.Second
+)
+
+// Health is the answer of the health endpoint.
+type Health struct {
+	Status  string `json:"status"`
+	Player  string `json:"player"`
+	Playing bool   `json:"playing"`
+	Paused  bool   `json:"paused,omitempty"`
+	Station string `json:"station,omitempty"`
+	// Audio is the PulseAudio or PipeWire server the player plays on, empty if none was found
+	Audio      string `json:"audio,omitempty"`
+	AudioError string `json:"audioError,omitempty"`
+}
+
+// HealthHandler answers the health of the daemon as JSON, with the 503 status while the audio server
+// doesn't accept connections.
+func (s *Server) HealthHandler(audio audiosink.Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mtx.Lock()
+		h := Health{
+			Status:  "ok",
+			Player:  s.player.GetType().String(),
+			Playing: s.state.Playing,
+			Paused:  s.state.Paused,
+			Station: s.state.StationName,
+		}
+		s.mtx.Unlock()
+
+		status := http.StatusOK
+		if audio.Path != "" {
+			h.Audio = audio.String()
+			if err := audio.Reachable(); err != nil {
+				h.Status, h.AudioError = "unhealthy", err.Error()
+				status = http.StatusServiceUnavailable
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(h)
+	})
+}
+
+// ServeHealth serves the health of the daemon on /healthz of the listener until ctx is done.
+func (s *Server) ServeHealth(ctx context.Context, l net.Listener, audio audiosink.Server) error {
+	mux := http.NewServeMux()
+	mux.Handle("GET "+healthPath, s.HealthHandler(audio))
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: time.Second}
+	stop := context.AfterFunc(ctx, func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), healthShutdownTimeout)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	})
+	defer stop()
+	if err := srv.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// serveHealth listens on addr for the health endpoint, logging its errors.
+func (s *Server) serveHealth(ctx context.Context, addr string, audio audiosink.Server) {
+	log := slog.With("method", "daemon.Server.serveHealth")
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Error("listen", "addr", addr, "error", err)
+		return
+	}
+	log.Info("listening", "addr", l.Addr())
+	if err := s.ServeHealth(ctx, l, audio); err != nil {
+		log.Error("serve", "error", err)
+	}
+}
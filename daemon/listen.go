@@ -0,0 +1,55 @@
+package daemon
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strconv"
+)
+
+// listenFdsStart is the first file descriptor passed by systemd.
+const listenFdsStart = 3
+
+var ErrRunning = errors.New("daemon is already running")
+
+// Listen returns the socket passed by systemd socket activation, otherwise it listens on the unix socket path.
+func Listen(path string) (net.Listener, error) {
+	if l, err := activationListener(); l != nil || err != nil {
+		return l, err
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, ErrRunning
+	}
+	// left by a daemon that didn't exit cleanly
+	_ = os.Remove(path)
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// activationListener returns the socket passed by systemd, nil if not started by socket activation.
+func activationListener() (net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n < 1 {
+		return nil, nil
+	}
+	// not inherited by the player processes
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	f := os.NewFile(uintptr(listenFdsStart), "sonicradio.socket")
+	defer f.Close()
+	return net.FileListener(f)
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package daemon
+
+import "syscall"
+
+// detachedProcAttr starts the daemon in a new session, so it's not terminated with the terminal.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,10 @@
+package daemon
+
+import "syscall"
+
+const detachedProcess = 0x00000008
+
+// detachedProcAttr starts the daemon without a console, so it's not terminated with the terminal.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP}
+}
@@ -0,0 +1,75 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/player/remote"
+)
+
+const (
+	reminderMsg    = "Time to tune into %s"
+	reminderAction = "Tune in"
+)
+
+// WatchReminders notifies the reminders of the favorites due while no app is attached, the app
+// notifying them itself, and outside the quiet hours, until ctx is done. The config is loaded at every check, for the reminders set
+// since the start. The action of the notification plays the station.
+func (s *Server) WatchReminders(ctx context.Context, interval time.Duration, load func() (*config.Value, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	from := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.checkReminders(ctx, load, from, now)
+			from = now
+		}
+	}
+}
+
+func (s *Server) checkReminders(ctx context.Context, load func() (*config.Value, error), from, to time.Time) {
+	s.mtx.Lock()
+	attached := s.attached
+	s.mtx.Unlock()
+	if attached > 0 {
+		return
+	}
+	cfg, err := load()
+	if err != nil {
+		slog.With("method", "daemon.Server.checkReminders").Error("load config", "error", err)
+		return
+	}
+	if cfg.Quiet(to) {
+		return
+	}
+	for _, uuid := range cfg.DueReminders(from, to) {
+		r, _ := cfg.Reminder(uuid)
+		go s.remind(ctx, uuid, cfg.StationName(uuid, r.StationName), r.URL)
+	}
+}
+
+// remind shows the notification of the reminder, playing the station when its action is clicked.
+func (s *Server) remind(ctx context.Context, uuid, name, url string) {
+	log := slog.With("method", "daemon.Server.remind")
+	ctx, cancel := context.WithTimeout(ctx, config.ReminderTuneInFor)
+	defer cancel()
+	log.Info("notify", "station", name)
+	clicked, err := s.notify(ctx, fmt.Sprintf(reminderMsg, name), reminderAction)
+	if err != nil {
+		log.Info("notification", "error", err)
+	}
+	if !clicked {
+		return
+	}
+	if res := s.do(remote.Request{Method: remote.MethodPlay, URL: url}); res.Err != "" {
+		log.Error("play", "station", name, "error", res.Err)
+		return
+	}
+	s.do(remote.Request{Method: remote.MethodSetStation, StationUuid: uuid, StationName: name})
+}
@@ -0,0 +1,46 @@
+package daemon
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"time"
+)
+
+const (
+	startTimeout    = 10 * time.Second
+	startRetryDelay = 50 * time.Millisecond
+)
+
+// Start runs the daemon in a new process, detached from the terminal, and waits for it to listen on the socket path.
+func Start(path string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(exe, "-daemon")
+	cmd.SysProcAttr = detachedProcAttr()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	timeout := time.After(startTimeout)
+	for {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil
+		}
+		select {
+		case err := <-exited:
+			return fmt.Errorf("daemon exited: %v", err)
+		case <-timeout:
+			return fmt.Errorf("daemon not listening after %v", startTimeout)
+		case <-time.After(startRetryDelay):
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package daemon
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	sdReady    = "READY=1"
+	sdStopping = "STOPPING=1"
+	sdWatchdog = "WATCHDOG=1"
+)
+
+// SDNotifier tells systemd the state of the daemon by the sd_notify protocol, when started by a unit of
+// Type=notify, and pings its watchdog when the unit has WatchdogSec.
+type SDNotifier struct {
+	// addr is the datagram socket of systemd, empty when not started by it
+	addr string
+	// Watchdog is the interval of the pings, half the timeout of the unit, 0 without a watchdog
+	Watchdog time.Duration
+}
+
+// NewSDNotifier returns the notifier of the socket and the watchdog timeout passed by systemd, unset so
+// the player processes don't inherit them.
+func NewSDNotifier() SDNotifier {
+	n := SDNotifier{addr: os.Getenv("NOTIFY_SOCKET")}
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	pid, pidErr := strconv.Atoi(os.Getenv("WATCHDOG_PID"))
+	if err == nil && usec > 0 && (pidErr != nil || pid == os.Getpid()) {
+		n.Watchdog = time.Duration(usec) * time.Microsecond / 2
+	}
+	os.Unsetenv("NOTIFY_SOCKET")
+	os.Unsetenv("WATCHDOG_USEC")
+	os.Unsetenv("WATCHDOG_PID")
+	return n
+}
+
+// Notify sends the state to systemd, nothing when not started by it.
+func (n SDNotifier) Notify(state string) error {
+	if n.addr == "" {
+		return nil
+	}
+	// an address starting with @ is in the abstract namespace, which net handles
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: n.addr, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// Watchdog pings the watchdog of systemd every interval until ctx is done, while the daemon handles the
+// requests: a ping waits for the request holding the state, so a daemon hung on its player misses them and
+// systemd restarts it.
+func (s *Server) Watchdog(ctx context.Context, interval time.Duration, ping func() error) {
+	log := slog.With("method", "daemon.Server.Watchdog")
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		// waits for the request holding the state
+		s.mtx.Lock()
+		s.mtx.Unlock()
+		if err := ping(); err != nil {
+			log.Error("ping", "error", err)
+		}
+	}
+}
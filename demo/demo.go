@@ -0,0 +1,72 @@
+// Package demo runs the app on bundled stations and a simulated player, without the network or audio,
+// e.g. for screenshots, trying the themes or working on the UI.
+package demo
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/browser/fakedir"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/player"
+)
+
+//go:embed stations.json
+var stationsJSON []byte
+
+// favoritesCount is the number of stations favorited at the start, for the favorites tab not to be empty.
+const favoritesCount = 5
+
+// Stations returns the bundled stations, by votes.
+func Stations() ([]browser.Station, error) {
+	var res []browser.Station
+	if err := json.Unmarshal(stationsJSON, &res); err != nil {
+		return nil, fmt.Errorf("decode the demo stations: %w", err)
+	}
+	return res, nil
+}
+
+// Start serves the bundled stations on the loopback interface until ctx is done. It returns the api browsing them
+// and the player simulating their playback, and favorites the first stations when cfg has no favorites.
+func Start(ctx context.Context, cfg *config.Value) (*browser.Api, *player.Player, error) {
+	log := slog.With("method", "demo.Start")
+	stations, err := Stations()
+	if err != nil {
+		return nil, nil, err
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, nil, fmt.Errorf("listen for the demo directory: %w", err)
+	}
+	srv := &http.Server{Handler: fakedir.New(stations...)}
+	go func() {
+		if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+			log.Error("serve the demo directory", "error", err)
+		}
+	}()
+	context.AfterFunc(ctx, func() { _ = srv.Close() })
+	log.Info("serving the demo directory", "addr", ln.Addr().String(), "stations", len(stations))
+
+	if len(cfg.Favorites) == 0 {
+		favorites := make([]string, 0, favoritesCount)
+		for _, s := range stations[:min(favoritesCount, len(stations))] {
+			favorites = append(favorites, s.Stationuuid)
+		}
+		cfg.SetFavorites(favorites)
+	}
+
+	api := browser.NewApiWithServers(cfg, "http://"+ln.Addr().String())
+	return api, NewPlayer(), nil
+}
+
+// NewPlayer returns a player simulating the playback of the streams, reporting a song title changing regularly.
+func NewPlayer() *player.Player {
+	return player.NewWithBackend(newBackend())
+}
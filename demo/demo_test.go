@@ -0,0 +1,97 @@
+package demo
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+)
+
+func Test_Stations(t *testing.T) {
+	stations, err := Stations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stations) < favoritesCount {
+		t.Fatalf("got %d stations", len(stations))
+	}
+	seen := make(map[string]bool)
+	for _, s := range stations {
+		if s.Stationuuid == "" || s.Name == "" || s.URL == "" {
+			t.Errorf("incomplete station %+v", s)
+		}
+		if seen[s.Stationuuid] {
+			t.Errorf("duplicate uuid %s", s.Stationuuid)
+		}
+		seen[s.Stationuuid] = true
+	}
+}
+
+func Test_Start(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := &config.Value{Version: "test", ApiRateLimit: 1000}
+	api, p, err := Start(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Favorites) != favoritesCount {
+		t.Errorf("got favorites %v", cfg.Favorites)
+	}
+
+	params := browser.DefaultSearchParams()
+	params.TagList = "jazz"
+	got, err := api.Search(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) == 0 || !slices.ContainsFunc(got, func(s browser.Station) bool { return s.Name == "Blue Hour Jazz" }) {
+		t.Errorf("search by tag got %v", got)
+	}
+
+	if err := p.Play(got[0].URL); err != nil {
+		t.Fatal(err)
+	}
+	if m := p.Metadata(); m.Err != nil || m.Title == "" {
+		t.Errorf("got metadata %+v, want a song title", m)
+	}
+}
+
+func Test_backend(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newBackend()
+	b.now = func() time.Time { return now }
+
+	if m := b.Metadata(); m.Err == nil {
+		t.Errorf("got metadata %+v when stopped", m)
+	}
+	_ = b.Play("http://demo.invalid/a")
+	first := b.Metadata().Title
+	now = now.Add(songDuration - time.Second)
+	if m := b.Metadata(); m.Title != first || *m.PlaybackTimeSec != 29 {
+		t.Errorf("got %q at %ds, want %q at 29s", m.Title, *m.PlaybackTimeSec, first)
+	}
+
+	_ = b.Pause(true)
+	now = now.Add(time.Hour)
+	if m := b.Metadata(); m.Title != first || *m.PlaybackTimeSec != 29 {
+		t.Errorf("got %q at %ds while paused", m.Title, *m.PlaybackTimeSec)
+	}
+	_ = b.Pause(false)
+	now = now.Add(time.Second)
+	second := b.Metadata().Title
+	if second == first {
+		t.Errorf("expected the next song after %v", songDuration)
+	}
+
+	if m := b.Seek(-60); *m.PlaybackTimeSec != 0 || m.Title != first {
+		t.Errorf("got %q at %ds after seeking back", m.Title, *m.PlaybackTimeSec)
+	}
+	_ = b.Stop()
+	if m := b.Metadata(); m.Err == nil {
+		t.Errorf("got metadata %+v after stop", m)
+	}
+}
@@ -0,0 +1,136 @@
+package demo
+
+import (
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/player"
+	"github.com/dancnb/sonicradio/player/model"
+)
+
+// songDuration is how long each simulated song plays.
+const songDuration = 30 * time.Second
+
+var errStopped = errors.New("no metadata")
+
+// songs are the titles reported by the stations, each station starting at its own one.
+var songs = []string{
+	"The Lanterns - Harbour at Dawn",
+	"Mira Okafor - Slow Orbit",
+	"Grey Meridian - Paper Cities",
+	"Los Faroles - Noche de Marea",
+	"Juniper Vale - Northbound",
+	"The Quiet Engines - Static Bloom",
+	"Ana Lindqvist - Birch & Ember",
+	"Velvet Transit - Last Tram Home",
+	"Kofi Mensah Trio - Red Earth Waltz",
+	"Neon Archive - 1987 Forever",
+	"Hollow Pines - Winter Signal",
+	"Sora Kimura - Glass Rain",
+	"The Brass Foxes - Second Line Stroll",
+	"Elena Brandt - Salt and Silver",
+	"Dust Radio - Mesa Lights",
+	"Orchestra Nuvola - Nocturne in Blue",
+}
+
+var _ player.Backend = (*backend)(nil)
+
+// backend plays nothing, reporting a new song title of the stream every songDuration and the time played.
+type backend struct {
+	mtx    sync.Mutex
+	now    func() time.Time
+	url    string
+	volume int
+	// played is the time played until the start, which is zero while paused
+	played time.Duration
+	start  time.Time
+}
+
+func newBackend() *backend {
+	return &backend{now: time.Now, volume: config.DefVolume}
+}
+
+func (b *backend) GetType() config.PlayerType {
+	return config.Mpv
+}
+
+func (b *backend) Play(url string) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.url, b.played, b.start = url, 0, b.now()
+	return nil
+}
+
+func (b *backend) Pause(value bool) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if b.url == "" {
+		return nil
+	}
+	switch {
+	case value && !b.start.IsZero():
+		b.played, b.start = b.elapsed(), time.Time{}
+	case !value && b.start.IsZero():
+		b.start = b.now()
+	}
+	return nil
+}
+
+func (b *backend) Stop() error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.url, b.played, b.start = "", 0, time.Time{}
+	return nil
+}
+
+func (b *backend) SetVolume(value int) (int, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.volume = value
+	return value, nil
+}
+
+func (b *backend) Metadata() *model.Metadata {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.metadata()
+}
+
+func (b *backend) Seek(amtSec int) *model.Metadata {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if b.url == "" {
+		return &model.Metadata{Err: errStopped}
+	}
+	b.played = max(0, b.elapsed()+time.Duration(amtSec)*time.Second)
+	if !b.start.IsZero() {
+		b.start = b.now()
+	}
+	return b.metadata()
+}
+
+func (b *backend) Close() error {
+	return b.Stop()
+}
+
+func (b *backend) elapsed() time.Duration {
+	if b.start.IsZero() {
+		return b.played
+	}
+	return b.played + b.now().Sub(b.start)
+}
+
+func (b *backend) metadata() *model.Metadata {
+	if b.url == "" {
+		return &model.Metadata{Err: errStopped}
+	}
+	elapsed := b.elapsed()
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(b.url))
+	idx := (int(h.Sum32()%uint32(len(songs))) + int(elapsed/songDuration)) % len(songs)
+	sec := int64(elapsed / time.Second)
+	return &model.Metadata{Title: songs[idx], PlaybackTimeSec: &sec}
+}
@@ -0,0 +1,145 @@
+// Package diskquota keeps directories under a size limit by removing their oldest files.
+package diskquota
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+const MB = 1 << 20
+
+type file struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// Usage returns the total size of the files in dir and its subdirectories.
+func Usage(dir string) (int64, error) {
+	return UsageEntries(dir, nil)
+}
+
+// UsageEntries returns the total size of the named files and subdirectories of dir, all of them when entries is nil.
+func UsageEntries(dir string, entries []string) (int64, error) {
+	files, err := listEntries(dir, entries)
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+	return total, err
+}
+
+// Enforce removes the least recently modified files of dir until its usage is at most limit.
+// Files modified after keepAfter are kept, as they may still be written to.
+// The directories left empty are removed as well.
+// It returns the removed files and the number of bytes freed.
+func Enforce(dir string, limit int64, keepAfter time.Time) ([]string, int64, error) {
+	return EnforceEntries(dir, nil, limit, keepAfter)
+}
+
+// EnforceEntries is Enforce limited to the named files and subdirectories of dir, all of them when entries is nil.
+// The other files of dir are neither counted nor removed.
+func EnforceEntries(dir string, entries []string, limit int64, keepAfter time.Time) ([]string, int64, error) {
+	files, err := listEntries(dir, entries)
+	if err != nil {
+		return nil, 0, err
+	}
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+	if total <= limit {
+		return nil, 0, nil
+	}
+
+	slices.SortFunc(files, func(a, b file) int {
+		return a.modTime.Compare(b.modTime)
+	})
+	var removed []string
+	var freed int64
+	var errs []error
+	for _, f := range files {
+		if total-freed <= limit {
+			break
+		}
+		if f.modTime.After(keepAfter) {
+			continue
+		}
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		removed = append(removed, f.path)
+		freed += f.size
+		removeEmptyParents(dir, filepath.Dir(f.path))
+	}
+	return removed, freed, errors.Join(errs...)
+}
+
+func listEntries(dir string, entries []string) ([]file, error) {
+	if entries == nil {
+		return list(dir)
+	}
+	var files []file
+	var errs []error
+	for _, e := range entries {
+		f, err := list(filepath.Join(dir, e))
+		files = append(files, f...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return files, errors.Join(errs...)
+}
+
+func list(dir string) ([]file, error) {
+	var files []file
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			// removed since the dir was read
+			return nil
+		}
+		files = append(files, file{path: path, size: fi.Size(), modTime: fi.ModTime()})
+		return nil
+	})
+	return files, err
+}
+
+// removeEmptyParents removes dir and its parents up to root while they are empty.
+func removeEmptyParents(root, dir string) {
+	for dir != root && len(dir) > len(root) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// FormatSize returns n bytes in a human readable unit.
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,95 @@
+package diskquota
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, fp string, size int, modTime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(fp), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fp, make([]byte, size), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(fp, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnforce(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeFile(t, filepath.Join(dir, "old", "a"), 100, now.Add(-3*time.Hour))
+	writeFile(t, filepath.Join(dir, "b"), 100, now.Add(-2*time.Hour))
+	writeFile(t, filepath.Join(dir, "c"), 100, now.Add(-time.Hour))
+	writeFile(t, filepath.Join(dir, "writing"), 100, now)
+
+	if n, err := Usage(dir); err != nil || n != 400 {
+		t.Fatalf("Usage = %d, %v", n, err)
+	}
+
+	removed, freed, err := Enforce(dir, 150, now.Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the recent file is kept even if it's over the limit
+	if len(removed) != 3 || freed != 300 {
+		t.Errorf("removed %q, freed %d", removed, freed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "old")); !os.IsNotExist(err) {
+		t.Error("empty dir was not removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "writing")); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Error("root dir was removed")
+	}
+}
+
+func TestEnforce_underLimit(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a"), 100, time.Now().Add(-time.Hour))
+	removed, _, err := Enforce(dir, 100, time.Now())
+	if err != nil || len(removed) != 0 {
+		t.Errorf("removed %q, %v", removed, err)
+	}
+}
+
+func TestEnforce_missingDir(t *testing.T) {
+	if _, _, err := Enforce(filepath.Join(t.TempDir(), "missing"), 0, time.Now()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEnforceEntries(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-time.Hour)
+	writeFile(t, filepath.Join(dir, "artwork", "a"), 100, old.Add(-time.Hour))
+	writeFile(t, filepath.Join(dir, "index.json"), 100, old)
+	writeFile(t, filepath.Join(dir, "sync-git", ".git", "HEAD"), 100, old.Add(-2*time.Hour))
+
+	if n, err := UsageEntries(dir, []string{"artwork", "index.json", "missing"}); err != nil || n != 200 {
+		t.Fatalf("UsageEntries = %d, %v", n, err)
+	}
+	removed, _, err := EnforceEntries(dir, []string{"artwork", "index.json", "missing"}, 0, time.Now())
+	if err != nil || len(removed) != 2 {
+		t.Errorf("removed %q, %v", removed, err)
+	}
+	// the oldest file is not one of the entries
+	if _, err := os.Stat(filepath.Join(dir, "sync-git", ".git", "HEAD")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	for n, want := range map[int64]string{512: "512 B", 1536: "1.5 KiB", 3 * MB: "3.0 MiB"} {
+		if got := FormatSize(n); got != want {
+			t.Errorf("FormatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
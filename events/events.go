@@ -0,0 +1,167 @@
+// Package events streams the events of the running app, the song and station changes, the errors and
+// the volume, as newline delimited JSON over a unix socket, for the scripts.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+type Type string
+
+const (
+	SongChange    Type = "song"
+	StationChange Type = "station"
+	Error         Type = "error"
+	Volume        Type = "volume"
+)
+
+// clientQueueSize is how many events wait for a slow client before the next ones are dropped for it
+const clientQueueSize = 64
+
+var (
+	ErrNotRunning = errors.New("no running instance to stream the events of")
+	ErrRunning    = errors.New("another instance already streams its events")
+)
+
+// Event is a line of the stream, with the fields of its type.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Type    Type      `json:"type"`
+	Station *Station  `json:"station,omitempty"`
+	Song    string    `json:"song,omitempty"`
+	Volume  *int      `json:"volume,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Station is the station of the event, nil for a station change when the playback stopped.
+type Station struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// Hub sends the events published to every connected client.
+type Hub struct {
+	l net.Listener
+
+	mtx     sync.Mutex
+	clients map[chan []byte]struct{}
+}
+
+// Listen serves the events on the unix socket path, replacing the one left by an instance that didn't exit cleanly.
+// It returns ErrRunning when another instance serves them, leaving its socket alone.
+func Listen(path string) (*Hub, error) {
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, ErrRunning
+	}
+	// left by an instance that didn't exit cleanly
+	_ = os.Remove(path)
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	h := &Hub{l: l, clients: make(map[chan []byte]struct{})}
+	go h.accept()
+	return h, nil
+}
+
+func (h *Hub) accept() {
+	for {
+		conn, err := h.l.Accept()
+		if err != nil {
+			return
+		}
+		ch := make(chan []byte, clientQueueSize)
+		h.mtx.Lock()
+		h.clients[ch] = struct{}{}
+		h.mtx.Unlock()
+		go h.send(conn, ch)
+	}
+}
+
+// send writes the events to the client until it disconnects or the hub is closed.
+func (h *Hub) send(conn net.Conn, ch chan []byte) {
+	defer conn.Close()
+	for line := range ch {
+		if _, err := conn.Write(line); err != nil {
+			slog.With("method", "events.Hub.send").Info("client gone", "error", err)
+			h.mtx.Lock()
+			delete(h.clients, ch)
+			h.mtx.Unlock()
+			return
+		}
+	}
+}
+
+// Publish sends the event to the connected clients, dropping it for those too slow to keep up.
+// It's a no-op on a nil hub.
+func (h *Hub) Publish(e Event) {
+	if h == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		slog.With("method", "events.Hub.Publish").Error("marshal", "error", err)
+		return
+	}
+	line = append(line, '\n')
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	for ch := range h.clients {
+		select {
+		case ch <- line:
+		default:
+		}
+	}
+}
+
+// Close stops listening and disconnects the clients.
+func (h *Hub) Close() error {
+	if h == nil {
+		return nil
+	}
+	err := h.l.Close()
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	for ch := range h.clients {
+		delete(h.clients, ch)
+		close(ch)
+	}
+	return err
+}
+
+// Stream copies the events of the instance listening on the unix socket path to w, until it quits or ctx is done.
+func Stream(ctx context.Context, path string, w io.Writer) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err != nil {
+		return ErrNotRunning
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	_, err = io.Copy(w, conn)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,113 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// socketPath is short, as unix socket paths are limited to about 100 bytes.
+func socketPath(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "sre")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "e.sock")
+}
+
+func TestStream(t *testing.T) {
+	path := socketPath(t)
+	h, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	r, w := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- Stream(ctx, path, w)
+		w.Close()
+	}()
+
+	vol := 40
+	want := []Event{
+		{Type: StationChange, Station: &Station{UUID: "u1", Name: "Jazz FM"}},
+		{Type: SongChange, Station: &Station{UUID: "u1", Name: "Jazz FM"}, Song: "Artist - Title"},
+		{Type: Volume, Volume: &vol},
+	}
+	for connected := false; !connected; time.Sleep(10 * time.Millisecond) {
+		h.mtx.Lock()
+		connected = len(h.clients) == 1
+		h.mtx.Unlock()
+	}
+	for _, e := range want {
+		h.Publish(e)
+	}
+	sc := bufio.NewScanner(r)
+	for _, e := range want {
+		if !sc.Scan() {
+			t.Fatal("missing event", e.Type)
+		}
+		var got Event
+		if err := json.Unmarshal(sc.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Type != e.Type || got.Song != e.Song || got.Time.IsZero() ||
+			(e.Station != nil && got.Station.Name != e.Station.Name) || (e.Volume != nil && *got.Volume != *e.Volume) {
+			t.Errorf("got %s, want %+v", sc.Text(), e)
+		}
+	}
+
+	h.Close()
+	if err := <-done; err != nil {
+		t.Errorf("stream error %v after the instance quit", err)
+	}
+}
+
+func TestStreamNotRunning(t *testing.T) {
+	err := Stream(context.Background(), socketPath(t), io.Discard)
+	if !errors.Is(err, ErrNotRunning) {
+		t.Errorf("got %v, want ErrNotRunning", err)
+	}
+}
+
+func TestListenRunning(t *testing.T) {
+	path := socketPath(t)
+	h, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	if _, err := Listen(path); !errors.Is(err, ErrRunning) {
+		t.Fatalf("got %v, want ErrRunning", err)
+	}
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("the socket of the first instance is gone: %v", err)
+	}
+	conn.Close()
+}
+
+func TestListenStaleSocket(t *testing.T) {
+	path := socketPath(t)
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	h, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Close()
+}
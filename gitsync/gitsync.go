@@ -0,0 +1,164 @@
+// Package gitsync shares the favorites through a git remote, as a plain text file with a station
+// UUID per line. The clone is reset to the remote on every sync, so there are never merge conflicts:
+// the favorites are merged with the last synced ones as the base, keeping the stations of both sides.
+package gitsync
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	gitBin        = "git"
+	FavoritesFile = "favorites.txt"
+	commitMsg     = "Update the favorites"
+	// the commits are made by the app, whatever the git config of the user
+	commitName  = "sonicradio"
+	commitEmail = "sonicradio@localhost"
+)
+
+var ErrNoGit = errors.New("git is not installed")
+
+// Sync merges the favorites with the ones of the remote, pushes the result and returns it.
+// dir is the local clone, created on the first sync.
+func Sync(ctx context.Context, dir, remote string, favorites []string) ([]string, error) {
+	if _, err := exec.LookPath(gitBin); err != nil {
+		return nil, ErrNoGit
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); errors.Is(err, fs.ErrNotExist) {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return nil, err
+		}
+		if _, err := git(ctx, dir, "init", "--quiet"); err != nil {
+			return nil, err
+		}
+		if _, err := git(ctx, dir, "remote", "add", "origin", remote); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	if _, err := git(ctx, dir, "remote", "set-url", "origin", remote); err != nil {
+		return nil, err
+	}
+	branch, err := remoteBranch(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	// an empty remote has no branch to fetch yet
+	if _, err := git(ctx, dir, "fetch", "--quiet", "origin"); err != nil {
+		return nil, err
+	}
+	base, _ := showFile(ctx, dir, "HEAD")
+	upstream, remoteErr := showFile(ctx, dir, "origin/"+branch)
+	if remoteErr == nil {
+		if _, err := git(ctx, dir, "reset", "--quiet", "--hard", "origin/"+branch); err != nil {
+			return nil, err
+		}
+	}
+
+	merged := Merge(base, favorites, upstream)
+	if err := os.WriteFile(filepath.Join(dir, FavoritesFile), format(merged), 0o644); err != nil {
+		return nil, err
+	}
+	if _, err := git(ctx, dir, "add", FavoritesFile); err != nil {
+		return nil, err
+	}
+	if _, err := git(ctx, dir, "diff", "--cached", "--quiet"); err == nil {
+		return merged, nil
+	}
+	if _, err := git(ctx, dir, "-c", "user.name="+commitName, "-c", "user.email="+commitEmail,
+		"commit", "--quiet", "-m", commitMsg); err != nil {
+		return nil, err
+	}
+	if _, err := git(ctx, dir, "push", "--quiet", "origin", "HEAD:"+branch); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// Merge merges the local and the remote favorites, both changed since base: a station added on
+// either side is kept, and a station is only removed when one side removed it and the other did not
+// add it again. The local order is kept, followed by the stations added on the remote.
+func Merge(base, local, remote []string) []string {
+	var res []string
+	for _, uuid := range local {
+		if slices.Contains(remote, uuid) || !slices.Contains(base, uuid) {
+			res = append(res, uuid)
+		}
+	}
+	for _, uuid := range remote {
+		if !slices.Contains(res, uuid) && !slices.Contains(local, uuid) && !slices.Contains(base, uuid) {
+			res = append(res, uuid)
+		}
+	}
+	return res
+}
+
+// remoteBranch returns the default branch of the remote, or the local branch if the remote is empty.
+func remoteBranch(ctx context.Context, dir string) (string, error) {
+	out, err := git(ctx, dir, "ls-remote", "--symref", "origin", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if ref, ok := strings.CutPrefix(line, "ref: refs/heads/"); ok {
+			branch, _, _ := strings.Cut(ref, "\t")
+			return branch, nil
+		}
+	}
+	out, err = git(ctx, dir, "symbolic-ref", "--short", "HEAD")
+	return strings.TrimSpace(out), err
+}
+
+func showFile(ctx context.Context, dir, rev string) ([]string, error) {
+	out, err := git(ctx, dir, "show", rev+":"+FavoritesFile)
+	if err != nil {
+		return nil, err
+	}
+	return parse([]byte(out)), nil
+}
+
+// parse reads a station UUID per line, skipping the empty lines and the comments.
+func parse(b []byte) []string {
+	var res []string
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") || slices.Contains(res, line) {
+			continue
+		}
+		res = append(res, line)
+	}
+	return res
+}
+
+func format(favorites []string) []byte {
+	var b bytes.Buffer
+	for _, uuid := range favorites {
+		b.WriteString(uuid + "\n")
+	}
+	return b.Bytes()
+}
+
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, gitBin, append([]string{"-C", dir}, args...)...)
+	// never ask for credentials, the sync runs in the background
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
@@ -0,0 +1,64 @@
+package gitsync
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name                string
+		base, local, remote []string
+		want                []string
+	}{
+		{"first sync", nil, []string{"a", "b"}, []string{"c", "a"}, []string{"a", "b", "c"}},
+		{"added on both", []string{"a"}, []string{"a", "b"}, []string{"a", "c"}, []string{"a", "b", "c"}},
+		{"removed locally", []string{"a", "b"}, []string{"a"}, []string{"a", "b"}, []string{"a"}},
+		{"removed on the remote", []string{"a", "b"}, []string{"b", "a"}, []string{"a"}, []string{"a"}},
+		{"removed and added again", []string{"a", "b"}, []string{"a"}, []string{"a", "b", "c"}, []string{"a", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Merge(tt.base, tt.local, tt.remote); !slices.Equal(got, tt.want) {
+				t.Errorf("Merge()=%v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSync(t *testing.T) {
+	if _, err := exec.LookPath(gitBin); err != nil {
+		t.Skip(err)
+	}
+	ctx := context.Background()
+	tmp := t.TempDir()
+	remote := filepath.Join(tmp, "remote.git")
+	if _, err := git(ctx, tmp, "init", "--quiet", "--bare", remote); err != nil {
+		t.Fatal(err)
+	}
+	dirA, dirB := filepath.Join(tmp, "a"), filepath.Join(tmp, "b")
+
+	sync := func(dir string, favorites ...string) []string {
+		t.Helper()
+		res, err := Sync(ctx, dir, remote, favorites)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	if got := sync(dirA, "1", "2"); !slices.Equal(got, []string{"1", "2"}) {
+		t.Fatalf("first sync %v", got)
+	}
+	if got := sync(dirB, "3"); !slices.Equal(got, []string{"3", "1", "2"}) {
+		t.Fatalf("second machine %v", got)
+	}
+	if got := sync(dirA, "1"); !slices.Equal(got, []string{"1", "3"}) {
+		t.Fatalf("removed %v", got)
+	}
+	if got := sync(dirB, "3", "1", "2"); !slices.Equal(got, []string{"3", "1"}) {
+		t.Fatalf("removal pulled %v", got)
+	}
+}
@@ -0,0 +1,132 @@
+// Package guide reads the program schedules of the stations, published as iCalendar or JSON.
+package guide
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const (
+	maxScheduleSize = 4 << 20
+	// the recurring programs are listed from a day ago to a week ahead
+	pastWindow   = 24 * time.Hour
+	futureWindow = 7 * 24 * time.Hour
+)
+
+var ErrFormat = errors.New("unknown schedule format, expected iCalendar or JSON")
+
+// Program is a show of the station schedule.
+type Program struct {
+	Title string
+	Start time.Time
+	End   time.Time
+}
+
+// Fetch downloads and parses the schedule at url.
+func Fetch(ctx context.Context, url string) ([]Program, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("schedule %s: %s", url, res.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(res.Body, maxScheduleSize))
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b, time.Now())
+}
+
+// Parse reads an iCalendar or JSON schedule, sorted by start time.
+// The recurring programs are expanded around now.
+func Parse(b []byte, now time.Time) ([]Program, error) {
+	b = bytes.TrimSpace(bytes.TrimPrefix(b, []byte("\xef\xbb\xbf")))
+	var programs []Program
+	var err error
+	switch {
+	case bytes.HasPrefix(b, []byte("BEGIN:VCALENDAR")):
+		programs, err = parseICal(b, now)
+	case len(b) > 0 && (b[0] == '[' || b[0] == '{'):
+		programs, err = parseJSON(b)
+	default:
+		return nil, ErrFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(programs, func(a, b Program) int { return a.Start.Compare(b.Start) })
+	return programs, nil
+}
+
+type jsonProgram struct {
+	Title string    `json:"title"`
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// parseJSON reads a list of programs with RFC 3339 times, or an object with the list in "programs".
+func parseJSON(b []byte) ([]Program, error) {
+	var list []jsonProgram
+	if b[0] == '{' {
+		var obj struct {
+			Programs []jsonProgram `json:"programs"`
+		}
+		if err := json.Unmarshal(b, &obj); err != nil {
+			return nil, err
+		}
+		list = obj.Programs
+	} else if err := json.Unmarshal(b, &list); err != nil {
+		return nil, err
+	}
+	programs := make([]Program, 0, len(list))
+	for _, p := range list {
+		if p.Start.IsZero() {
+			continue
+		}
+		programs = append(programs, Program{Title: cmp.Or(p.Title, p.Name), Start: p.Start, End: p.End})
+	}
+	return programs, nil
+}
+
+// Current returns the program on air at now, and the next one, either may be nil.
+func Current(programs []Program, now time.Time) (*Program, *Program) {
+	var curr, next *Program
+	for i := range programs {
+		p := &programs[i]
+		switch {
+		case !p.Start.After(now) && (p.End.IsZero() || p.End.After(now)):
+			curr = p
+		case p.Start.After(now):
+			if next == nil {
+				next = p
+			}
+		}
+	}
+	return curr, next
+}
+
+// Starting returns the programs starting after from and until to.
+func Starting(programs []Program, from, to time.Time) []Program {
+	var res []Program
+	for _, p := range programs {
+		if p.Start.After(from) && !p.Start.After(to) {
+			res = append(res, p)
+		}
+	}
+	return res
+}
@@ -0,0 +1,69 @@
+package guide
+
+import (
+	"testing"
+	"time"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Morning\\, live\r\n" +
+	"DTSTART:20240101T070000Z\r\n" +
+	"DTEND:20240101T090000Z\r\n" +
+	"RRULE:FREQ=DAILY\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Jazz \r\n" +
+	" night\r\n" +
+	"DTSTART;TZID=UTC:20240105T200000\r\n" +
+	"DURATION:PT2H\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=FR\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Special\r\n" +
+	"DTSTART:20240310T120000Z\r\n" +
+	"DTEND:20240310T130000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICal(t *testing.T) {
+	// a Friday
+	now := time.Date(2024, 3, 8, 8, 0, 0, 0, time.UTC)
+	programs, err := Parse([]byte(testCalendar), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	curr, next := Current(programs, now)
+	if curr == nil || curr.Title != "Morning, live" || !curr.End.Equal(now.Add(time.Hour)) {
+		t.Errorf("current program %+v", curr)
+	}
+	wantNext := time.Date(2024, 3, 8, 20, 0, 0, 0, time.UTC)
+	if next == nil || next.Title != "Jazz night" || !next.Start.Equal(wantNext) || !next.End.Equal(wantNext.Add(2*time.Hour)) {
+		t.Errorf("next program %+v", next)
+	}
+	// 9 mornings from yesterday to a week ahead, a Friday night and the special
+	if len(programs) != 11 {
+		t.Errorf("%d programs, want 11", len(programs))
+	}
+	if starting := Starting(programs, now, now.Add(4*24*time.Hour)); len(starting) != 6 {
+		t.Errorf("%d programs starting in 4 days, want 6", len(starting))
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	b := []byte(`{"programs": [
+		{"title": "Late", "start": "2024-03-08T22:00:00Z", "end": "2024-03-09T00:00:00Z"},
+		{"name": "Early", "start": "2024-03-08T06:00:00Z"}
+	]}`)
+	programs, err := Parse(b, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(programs) != 2 || programs[0].Title != "Early" || programs[1].Title != "Late" {
+		t.Errorf("programs %+v", programs)
+	}
+	if _, err := Parse([]byte("<html>"), time.Now()); err != ErrFormat {
+		t.Errorf("error %v, want %v", err, ErrFormat)
+	}
+}
@@ -0,0 +1,215 @@
+package guide
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxOccurrences bounds the expansion of a recurring event.
+const maxOccurrences = 1000
+
+type icalEvent struct {
+	summary string
+	start   time.Time
+	end     time.Time
+	rrule   map[string]string
+}
+
+// parseICal reads the VEVENTs of an iCalendar file, with the DAILY and WEEKLY recurrence rules.
+func parseICal(b []byte, now time.Time) ([]Program, error) {
+	var programs []Program
+	var ev *icalEvent
+	for _, line := range unfold(b) {
+		name, params, value := splitProperty(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			ev = &icalEvent{}
+		case ev == nil:
+		case name == "END" && value == "VEVENT":
+			programs = append(programs, ev.occurrences(now)...)
+			ev = nil
+		case name == "SUMMARY":
+			ev.summary = unescapeText(value)
+		case name == "DTSTART":
+			ev.start = parseTime(value, params)
+		case name == "DTEND":
+			ev.end = parseTime(value, params)
+		case name == "DURATION" && !ev.start.IsZero():
+			if d, ok := parseDuration(value); ok {
+				ev.end = ev.start.Add(d)
+			}
+		case name == "RRULE":
+			ev.rrule = make(map[string]string)
+			for _, part := range strings.Split(value, ";") {
+				k, v, _ := strings.Cut(part, "=")
+				ev.rrule[strings.ToUpper(k)] = v
+			}
+		}
+	}
+	return programs, nil
+}
+
+// unfold joins the content lines continued on the next line by a leading space or tab.
+func unfold(b []byte) []string {
+	var lines []string
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	sc.Buffer(make([]byte, 64*1024), maxScheduleSize)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitProperty splits e.g. DTSTART;TZID=Europe/Paris:20240101T180000 into its name, parameters and value.
+func splitProperty(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	params := make(map[string]string)
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+func unescapeText(s string) string {
+	r := strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return r.Replace(s)
+}
+
+func parseTime(value string, params map[string]string) time.Time {
+	loc := time.Local
+	if tz := params["TZID"]; tz != "" {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		}
+	}
+	layouts := []string{"20060102T150405Z", "20060102T150405", "20060102"}
+	for _, layout := range layouts {
+		if len(value) != len(layout) {
+			continue
+		}
+		if strings.HasSuffix(layout, "Z") {
+			loc = time.UTC
+		}
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseDuration reads the durations of the form PT1H30M or P1D.
+func parseDuration(s string) (time.Duration, bool) {
+	s, ok := strings.CutPrefix(s, "P")
+	if !ok {
+		return 0, false
+	}
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour, 'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+	var d time.Duration
+	var num string
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == 'T':
+		case c >= '0' && c <= '9':
+			num += string(c)
+		default:
+			n, err := strconv.Atoi(num)
+			unit, ok := units[c]
+			if err != nil || !ok {
+				return 0, false
+			}
+			d += time.Duration(n) * unit
+			num = ""
+		}
+	}
+	return d, true
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// occurrences expands the event in the window around now, or returns it once if it doesn't recur.
+func (e *icalEvent) occurrences(now time.Time) []Program {
+	if e.start.IsZero() {
+		return nil
+	}
+	duration := e.end.Sub(e.start)
+	if e.end.IsZero() {
+		duration = 0
+	}
+	program := func(start time.Time) Program {
+		p := Program{Title: e.summary, Start: start}
+		if duration > 0 {
+			p.End = start.Add(duration)
+		}
+		return p
+	}
+	if e.rrule == nil {
+		return []Program{program(e.start)}
+	}
+
+	freq := e.rrule["FREQ"]
+	if freq != "DAILY" && freq != "WEEKLY" {
+		return []Program{program(e.start)}
+	}
+	interval, err := strconv.Atoi(e.rrule["INTERVAL"])
+	if err != nil || interval < 1 {
+		interval = 1
+	}
+	count, _ := strconv.Atoi(e.rrule["COUNT"])
+	var until time.Time
+	if v := e.rrule["UNTIL"]; v != "" {
+		until = parseTime(v, map[string]string{})
+		if len(v) == len("20060102") {
+			until = until.Add(24*time.Hour - time.Second)
+		}
+	}
+	days := make(map[time.Weekday]bool)
+	for _, d := range strings.Split(e.rrule["BYDAY"], ",") {
+		// the ordinal prefixes of the monthly rules are not supported
+		if wd, ok := weekdays[strings.ToUpper(strings.TrimLeft(d, "+-0123456789"))]; ok {
+			days[wd] = true
+		}
+	}
+	if freq == "WEEKLY" && len(days) == 0 {
+		days[e.start.Weekday()] = true
+	}
+
+	from, to := now.Add(-pastWindow), now.Add(futureWindow)
+	var res []Program
+	n := 0
+	// the days are walked one by one, the time of day is kept in the event location across DST changes
+	for day := 0; day < maxOccurrences*7; day++ {
+		start := e.start.AddDate(0, 0, day)
+		if start.After(to) || (!until.IsZero() && start.After(until)) || (count > 0 && n >= count) {
+			break
+		}
+		var match bool
+		if freq == "DAILY" {
+			match = day%interval == 0
+		} else {
+			week := day / 7
+			match = week%interval == 0 && days[start.Weekday()]
+		}
+		if !match {
+			continue
+		}
+		n++
+		if !start.Add(duration).Before(from) {
+			res = append(res, program(start))
+		}
+	}
+	return res
+}
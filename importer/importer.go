@@ -0,0 +1,352 @@
+// Package importer reads the stations saved by other radio players, and finds them on radio-browser.
+package importer
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dancnb/sonicradio/browser"
+)
+
+type Format string
+
+const (
+	Tuner     Format = "tuner"     // GNOME Tuner starred stations
+	Shortwave Format = "shortwave" // Shortwave library
+	PyRadio   Format = "pyradio"   // PyRadio stations.csv
+	Mpd       Format = "mpd"       // mpd m3u playlists
+	CSV       Format = "csv"       // CSV with a header of name, url and uuid columns
+)
+
+var Formats = []Format{Tuner, Shortwave, PyRadio, Mpd, CSV}
+
+var (
+	ErrNoFile    = errors.New("no file found, give its path")
+	ErrNoSqlite  = errors.New("sqlite3 is not installed")
+	errCSVHeader = errors.New("no name, url or uuid column in the csv header")
+)
+
+// Entry is a station saved by another player. Uuid is the radio-browser station UUID, when the player
+// uses radio-browser too.
+type Entry struct {
+	Name string
+	URL  string
+	Uuid string
+}
+
+func (e Entry) String() string {
+	return cmp.Or(e.Name, e.URL, e.Uuid)
+}
+
+// Read reads the stations of the format from path, or from the default location of the player if
+// path is empty.
+func Read(format Format, path string) ([]Entry, error) {
+	if !slices.Contains(Formats, format) {
+		return nil, fmt.Errorf("unknown format %q, one of %v", format, Formats)
+	}
+	if path == "" {
+		path = DefaultPath(format)
+		if path == "" {
+			return nil, ErrNoFile
+		}
+	}
+	switch format {
+	case Shortwave:
+		return readShortwave(path)
+	case Mpd:
+		return readPlaylists(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch format {
+	case Tuner:
+		return parseTuner(f)
+	case PyRadio:
+		return parsePyRadio(f)
+	default:
+		return parseCSV(f)
+	}
+}
+
+// DefaultPath returns the first existing location of the stations of the player, empty if none.
+func DefaultPath(format Format) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	dataHome := cmp.Or(os.Getenv("XDG_DATA_HOME"), filepath.Join(home, ".local", "share"))
+	configHome := cmp.Or(os.Getenv("XDG_CONFIG_HOME"), filepath.Join(home, ".config"))
+	var paths []string
+	switch format {
+	case Tuner:
+		paths = []string{
+			filepath.Join(dataHome, "com.github.louis77.tuner", "favorites.json"),
+			filepath.Join(dataHome, "io.github.louis77.tuner", "favorites.json"),
+			filepath.Join(home, ".var", "app", "com.github.louis77.tuner", "data", "com.github.louis77.tuner", "favorites.json"),
+		}
+	case Shortwave:
+		paths = []string{
+			filepath.Join(dataHome, "Shortwave", "Shortwave.db"),
+			filepath.Join(home, ".var", "app", "de.haeckerfelix.Shortwave", "data", "Shortwave", "Shortwave.db"),
+		}
+	case PyRadio:
+		paths = []string{filepath.Join(configHome, "pyradio", "stations.csv")}
+	case Mpd:
+		paths = []string{
+			filepath.Join(configHome, "mpd", "playlists"),
+			filepath.Join(home, ".mpd", "playlists"),
+			filepath.Join(dataHome, "mpd", "playlists"),
+		}
+	}
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// parseTuner reads the starred stations of Tuner, a JSON list of radio-browser stations.
+func parseTuner(r io.Reader) ([]Entry, error) {
+	var stations []struct {
+		Id    string `json:"id"`
+		Title string `json:"title"`
+		URL   string `json:"url"`
+	}
+	if err := json.NewDecoder(r).Decode(&stations); err != nil {
+		return nil, err
+	}
+	var res []Entry
+	for _, s := range stations {
+		res = append(res, Entry{Name: s.Title, URL: s.URL, Uuid: s.Id})
+	}
+	return res, nil
+}
+
+// readShortwave reads the library of Shortwave, a SQLite database with the radio-browser UUIDs of the
+// stations, through the sqlite3 command.
+func readShortwave(path string) ([]Entry, error) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		return nil, ErrNoSqlite
+	}
+	out, err := exec.Command("sqlite3", "-readonly", "-batch", path, "SELECT uuid FROM library").Output()
+	if err != nil {
+		return nil, fmt.Errorf("sqlite3: %w", err)
+	}
+	var res []Entry
+	for _, uuid := range strings.Fields(string(out)) {
+		res = append(res, Entry{Uuid: uuid})
+	}
+	return res, nil
+}
+
+// parsePyRadio reads the stations.csv of PyRadio: name, url and optional columns, without a header.
+// The group headers have "-" as url.
+func parsePyRadio(r io.Reader) ([]Entry, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	var res []Entry
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return res, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if len(rec) < 2 || !isStreamUrl(rec[1]) {
+			continue
+		}
+		res = append(res, Entry{Name: strings.TrimSpace(rec[0]), URL: strings.TrimSpace(rec[1])})
+	}
+}
+
+// readPlaylists reads the m3u playlist at path, or all of them if path is the playlists folder of mpd.
+func readPlaylists(path string) ([]Entry, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if fi.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.m3u"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	var res []Entry
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := parseM3u(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, entries...)
+	}
+	return res, nil
+}
+
+// parseM3u reads the stream urls of a playlist, named by the #EXTINF line before them. The local files
+// are skipped.
+func parseM3u(r io.Reader) ([]Entry, error) {
+	var res []Entry
+	var name string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if info, ok := strings.CutPrefix(line, "#EXTINF:"); ok {
+			_, name, _ = strings.Cut(info, ",")
+			name = strings.TrimSpace(name)
+			continue
+		}
+		if isStreamUrl(line) {
+			res = append(res, Entry{Name: name, URL: line})
+		}
+		if line != "" && !strings.HasPrefix(line, "#") {
+			name = ""
+		}
+	}
+	return res, sc.Err()
+}
+
+// parseCSV reads a CSV whose header names the name, url and uuid columns, any of them being optional.
+func parseCSV(r io.Reader) ([]Entry, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	col := func(names ...string) int {
+		return slices.IndexFunc(header, func(h string) bool {
+			return slices.Contains(names, strings.ToLower(strings.TrimSpace(h)))
+		})
+	}
+	nameIx, urlIx, uuidIx := col("name", "title"), col("url", "stream", "stream_url"), col("uuid", "stationuuid")
+	if nameIx < 0 && urlIx < 0 && uuidIx < 0 {
+		return nil, errCSVHeader
+	}
+	field := func(rec []string, ix int) string {
+		if ix < 0 || ix >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[ix])
+	}
+	var res []Entry
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return res, nil
+		} else if err != nil {
+			return nil, err
+		}
+		e := Entry{Name: field(rec, nameIx), URL: field(rec, urlIx), Uuid: field(rec, uuidIx)}
+		if e != (Entry{}) {
+			res = append(res, e)
+		}
+	}
+}
+
+func isStreamUrl(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// StationApi is the part of the radio-browser api the entries are looked up with.
+type StationApi interface {
+	GetStations(uuids []string) ([]browser.Station, error)
+	StationsByUrl(ctx context.Context, streamUrl string) ([]browser.Station, error)
+	SearchCtx(ctx context.Context, s browser.SearchParams) ([]browser.Station, error)
+}
+
+// Resolve finds the radio-browser stations of the entries: by UUID, then by stream url, then by the
+// exact name if a single station has it. It returns the UUIDs found, in order and without duplicates,
+// and the entries that were not found.
+func Resolve(ctx context.Context, api StationApi, entries []Entry) (uuids []string, missing []Entry, err error) {
+	var byUuid []string
+	for _, e := range entries {
+		if e.Uuid != "" {
+			byUuid = append(byUuid, e.Uuid)
+		}
+	}
+	known := make(map[string]bool)
+	if len(byUuid) > 0 {
+		stations, err := api.GetStations(byUuid)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, s := range stations {
+			known[s.Stationuuid] = true
+		}
+	}
+
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		uuid := ""
+		if known[e.Uuid] {
+			uuid = e.Uuid
+		}
+		if uuid == "" && e.URL != "" {
+			stations, err := api.StationsByUrl(ctx, e.URL)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(stations) > 0 {
+				uuid = stations[0].Stationuuid
+			}
+		}
+		if uuid == "" && e.Name != "" {
+			uuid, err = findByName(ctx, api, e.Name)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		if uuid == "" {
+			missing = append(missing, e)
+		} else if !slices.Contains(uuids, uuid) {
+			uuids = append(uuids, uuid)
+		}
+	}
+	return uuids, missing, nil
+}
+
+func findByName(ctx context.Context, api StationApi, name string) (string, error) {
+	params := browser.DefaultSearchParams()
+	params.Name = name
+	stations, err := api.SearchCtx(ctx, params)
+	if err != nil && !errors.Is(err, browser.ErrLocalResults) {
+		return "", err
+	}
+	var uuid string
+	for _, s := range stations {
+		if !strings.EqualFold(strings.TrimSpace(s.Name), name) {
+			continue
+		} else if uuid != "" {
+			// ambiguous, the same name is used by several stations
+			return "", nil
+		}
+		uuid = s.Stationuuid
+	}
+	return uuid, nil
+}
@@ -0,0 +1,119 @@
+package importer
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dancnb/sonicradio/browser"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(string) ([]Entry, error)
+		in    string
+		want  []Entry
+	}{
+		{
+			"tuner",
+			func(s string) ([]Entry, error) { return parseTuner(strings.NewReader(s)) },
+			`[{"id":"u1","title":"Jazz FM","url":"http://jazz/stream","starred":true}]`,
+			[]Entry{{Name: "Jazz FM", URL: "http://jazz/stream", Uuid: "u1"}},
+		},
+		{
+			"pyradio",
+			func(s string) ([]Entry, error) { return parsePyRadio(strings.NewReader(s)) },
+			"# comment\nNews,-\n\"Radio, Paris\",https://paris/live,,\nJazz FM, http://jazz/stream\n",
+			[]Entry{{Name: "Radio, Paris", URL: "https://paris/live"}, {Name: "Jazz FM", URL: "http://jazz/stream"}},
+		},
+		{
+			"m3u",
+			func(s string) ([]Entry, error) { return parseM3u(strings.NewReader(s)) },
+			"#EXTM3U\n#EXTINF:-1,Jazz FM\nhttp://jazz/stream\nmusic/song.mp3\nhttps://paris/live\n",
+			[]Entry{{Name: "Jazz FM", URL: "http://jazz/stream"}, {URL: "https://paris/live"}},
+		},
+		{
+			"csv",
+			func(s string) ([]Entry, error) { return parseCSV(strings.NewReader(s)) },
+			"Stream,Name,Genre\nhttp://jazz/stream,Jazz FM,jazz\n,Radio Paris\n",
+			[]Entry{{Name: "Jazz FM", URL: "http://jazz/stream"}, {Name: "Radio Paris"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parse(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseCSV(strings.NewReader("a,b\n1,2\n")); err == nil {
+		t.Error("no error without a known csv column")
+	}
+}
+
+type fakeApi struct {
+	stations []browser.Station
+}
+
+func (a *fakeApi) GetStations(uuids []string) ([]browser.Station, error) {
+	var res []browser.Station
+	for _, s := range a.stations {
+		if slices.Contains(uuids, s.Stationuuid) {
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+
+func (a *fakeApi) StationsByUrl(_ context.Context, streamUrl string) ([]browser.Station, error) {
+	var res []browser.Station
+	for _, s := range a.stations {
+		if s.URL == streamUrl {
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+
+func (a *fakeApi) SearchCtx(_ context.Context, p browser.SearchParams) ([]browser.Station, error) {
+	var res []browser.Station
+	for _, s := range a.stations {
+		if strings.Contains(strings.ToLower(s.Name), strings.ToLower(p.Name)) {
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+
+func TestResolve(t *testing.T) {
+	api := &fakeApi{stations: []browser.Station{
+		{Stationuuid: "1", Name: "Jazz FM", URL: "http://jazz/stream"},
+		{Stationuuid: "2", Name: "Radio Paris", URL: "https://paris/live"},
+		{Stationuuid: "3", Name: "Rock", URL: "http://rock/1"},
+		{Stationuuid: "4", Name: "Rock", URL: "http://rock/2"},
+	}}
+	entries := []Entry{
+		{Name: "Jazz", Uuid: "1"},
+		{Name: "Paris", URL: "https://paris/live"},
+		{Name: "jazz fm"},
+		{Name: "Rock"},
+		{Name: "Unknown", Uuid: "x", URL: "http://unknown"},
+	}
+	uuids, missing, err := Resolve(context.Background(), api, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(uuids, []string{"1", "2"}) {
+		t.Errorf("found %v", uuids)
+	}
+	if len(missing) != 2 || missing[0].Name != "Rock" || missing[1].Name != "Unknown" {
+		t.Errorf("missing %v", missing)
+	}
+}
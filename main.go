@@ -2,18 +2,32 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"log/slog"
+	"net/http"
+	_ "net/http/pprof"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/dancnb/sonicradio/audiosink"
 	"github.com/dancnb/sonicradio/browser"
 	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/crash"
+	"github.com/dancnb/sonicradio/daemon"
+	"github.com/dancnb/sonicradio/demo"
+	"github.com/dancnb/sonicradio/events"
+	"github.com/dancnb/sonicradio/importer"
 	"github.com/dancnb/sonicradio/player"
+	"github.com/dancnb/sonicradio/profile"
 	"github.com/dancnb/sonicradio/ui"
 )
 
@@ -29,6 +43,39 @@ func run() {
 		_ = logWC.Close()
 	}()
 
+	if addr := config.Pprof(); addr != "" {
+		go servePprof(addr)
+	}
+
+	if config.Daemon() {
+		runDaemon()
+		return
+	}
+	if flag.Arg(0) == "events" {
+		runEvents(flag.Arg(1))
+		return
+	}
+
+	// the demo starts from the defaults every time, leaving the saved config alone
+	var demoDir string
+	var crashed bool
+	if config.Demo() {
+		var err error
+		demoDir, err = os.MkdirTemp("", "sonicradio-demo")
+		if err != nil {
+			fmt.Printf("demo: %v\n", err)
+			_ = logWC.Close()
+			os.Exit(1)
+		}
+		defer func() {
+			// the crash report is kept with the demo dir
+			if !crashed {
+				_ = os.RemoveAll(demoDir)
+			}
+		}()
+		config.UseDir(demoDir)
+	}
+
 	pidFile, err := config.CheckPidFile()
 	if err != nil {
 		fmt.Printf("check running instance: %v\n", err)
@@ -53,25 +100,263 @@ func run() {
 	if cfg == nil {
 		panic("could not get config")
 	}
+	cfg.UseStartupVolume()
 
 	slog.Info("loaded", "config", cfg.String())
 
+	if config.Demo() {
+		cfg.RecordingsDir = filepath.Join(demoDir, "recordings")
+		b, p, err := demo.Start(ctx, cfg)
+		if err != nil {
+			panic(err)
+		}
+		crashed = runUI(ctx, cfg, b, p)
+		return
+	}
+
+	switch {
+	case flag.Arg(0) == "export" && !profile.IsFormat(flag.Arg(1)):
+		runExport(flag.Arg(1))
+		return
+	case flag.Arg(0) == "import" && flag.Arg(1) == stateFormat:
+		runImportState(flag.Arg(2))
+		return
+	}
+
 	b, err := browser.NewApi(ctx, cfg)
 	if err != nil {
 		panic(err)
 	}
-	p, err := player.NewPlayer(ctx, cfg)
-	if err != nil {
-		panic(err)
+	switch flag.Arg(0) {
+	case "import":
+		runImport(ctx, cfg, b, importer.Format(flag.Arg(1)), flag.Arg(2))
+		return
+	case "export":
+		runExportPage(cfg, b, profile.Format(flag.Arg(1)), flag.Arg(2))
+		return
+	}
+	// the players, and the daemon started on detach, inherit the audio server found
+	if _, err := audiosink.Setup(cfg.AudioServerPath()); err != nil {
+		slog.Info("audio server", "error", err.Error())
 	}
+	p, err := player.Attach(config.DaemonSocketPath())
+	if err != nil && flag.Arg(0) == "attach" {
+		fmt.Println("No playback running in the background to attach to.")
+		return
+	} else if err != nil {
+		slog.Info("no daemon to attach to", "error", err.Error())
+		p, err = player.NewPlayer(ctx, cfg)
+		if err != nil {
+			panic(err)
+		}
+	}
+	runUI(ctx, cfg, b, p)
+}
+
+// runUI runs the app until it quits, telling if it crashed.
+func runUI(ctx context.Context, cfg *config.Value, b *browser.Api, p *player.Player) (crashed bool) {
 	m := ui.NewModel(ctx, cfg, b, p)
 	defer func() {
+		// the report is saved first, in case quitting fails too after the crash
+		c := m.Crash()
+		if c != nil {
+			reportCrash(cfg, c)
+			crashed = true
+		}
 		m.Quit()
+		if c == nil && m.Detached() {
+			fmt.Println("Playback continues in the background, run `sonicradio attach` to get back to it.")
+		}
 	}()
 
 	if _, err := m.Progr.Run(); err != nil {
 		slog.Info(fmt.Sprintf("Error running program: %s", err.Error()))
 	}
+	return
+}
+
+// servePprof serves the profiles of the app, e.g. for `go tool pprof http://localhost:6060/debug/pprof/profile`.
+func servePprof(addr string) {
+	slog.Info("serving pprof", "addr", addr)
+	if err := http.ListenAndServe(addr, nil); err != nil {
+		slog.Error("serve pprof", "error", err.Error())
+	}
+}
+
+const issuesURL = "https://github.com/dancnb/sonicradio/issues"
+
+// reportCrash saves the report of the crash of the app and tells where it is, once the terminal is restored.
+func reportCrash(cfg *config.Value, c *crash.Crash) {
+	path, err := c.Write(cfg)
+	slog.Error("crashed", "panic", fmt.Sprint(c.Value), "report", path)
+	if err != nil {
+		slog.Error("write crash report", "error", err.Error())
+		fmt.Printf("sonicradio crashed: %v\n\n%s\nThe crash report could not be saved: %v\n", c.Value, c.Stack, err)
+		return
+	}
+	fmt.Printf("sonicradio crashed: %v\nThe crash report is saved in %s, please attach it to an issue at %s\n", c.Value, path, issuesURL)
+}
+
+// runDaemon plays in the background until terminated, for the app to attach to.
+func runDaemon() {
+	slog.Info("----------------------Starting daemon----------------------")
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Info("load config", "error", err.Error())
+	}
+	if cfg == nil {
+		panic("could not get config")
+	}
+	cfg.UseStartupVolume()
+
+	if err := daemon.Run(ctx, cfg); err != nil {
+		slog.Error("daemon", "error", err.Error())
+		fmt.Printf("daemon: %v\n", err)
+	}
+}
+
+// runEvents prints the events of the running app as JSON lines, until interrupted or the app quits.
+func runEvents(format string) {
+	if format != "--json" && format != "-json" {
+		fmt.Println("usage: sonicradio events --json")
+		os.Exit(1)
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	err := events.Stream(ctx, config.EventsSocketPath(), os.Stdout)
+	if errors.Is(err, events.ErrNotRunning) {
+		fmt.Println("No running instance to stream the events of.")
+		os.Exit(1)
+	} else if err != nil {
+		slog.Error("events", "error", err.Error())
+		fmt.Printf("events: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// stateFormat is the import format of the archives written by the export.
+const stateFormat = "state"
+
+// runExport writes the application state to an archive, to import on another machine.
+func runExport(path string) {
+	if path == "" {
+		path = fmt.Sprintf("sonicradio-%s.tar.gz", time.Now().Format(time.DateOnly))
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Printf("export: %v\n", err)
+		return
+	}
+	err = config.ExportState(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Printf("export: %v\n", err)
+		return
+	}
+	fmt.Printf("Exported to %s, run `sonicradio import state %s` on the other machine.\n", path, path)
+}
+
+func runImportState(path string) {
+	if path == "" {
+		fmt.Println("import state: give the path of the exported archive")
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Printf("import state: %v\n", err)
+		return
+	}
+	defer f.Close()
+	if err := config.ImportState(f); err != nil {
+		fmt.Printf("import state: %v\n", err)
+		return
+	}
+	fmt.Printf("Imported %s, the previous config is kept with the .bak suffix.\n", path)
+}
+
+// runExportPage writes a page of the favorites to publish, with their names, tags and homepages.
+func runExportPage(cfg *config.Value, b *browser.Api, format profile.Format, path string) {
+	if path == "" {
+		path = "favorites." + string(format)
+	}
+	found, err := b.GetStations(cfg.Favorites)
+	if err != nil {
+		fmt.Printf("export %s: %v\n", format, err)
+		return
+	}
+	var stations []profile.Station
+	for _, uuid := range cfg.Favorites {
+		ix := slices.IndexFunc(found, func(s browser.Station) bool { return s.Stationuuid == uuid })
+		if ix < 0 {
+			continue
+		}
+		s := found[ix]
+		var tags []string
+		for _, tag := range strings.Split(s.Tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		stations = append(stations, profile.Station{
+			Name:     cfg.StationName(uuid, strings.TrimSpace(s.Name)),
+			Homepage: strings.TrimSpace(s.Homepage),
+			Tags:     tags,
+			Country:  s.Country,
+		})
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Printf("export %s: %v\n", format, err)
+		return
+	}
+	err = profile.Write(f, format, "Favorite stations", stations)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Printf("export %s: %v\n", format, err)
+		return
+	}
+	fmt.Printf("Exported %d favorites to %s.\n", len(stations), path)
+}
+
+// runImport adds the stations saved by another player to the favorites.
+func runImport(ctx context.Context, cfg *config.Value, b *browser.Api, format importer.Format, path string) {
+	entries, err := importer.Read(format, path)
+	if err != nil {
+		fmt.Printf("import %s: %v\n", format, err)
+		return
+	}
+	fmt.Printf("Looking up %d stations...\n", len(entries))
+	uuids, missing, err := importer.Resolve(ctx, b, entries)
+	if err != nil {
+		fmt.Printf("import %s: %v\n", format, err)
+		return
+	}
+	added := 0
+	for _, uuid := range uuids {
+		if cfg.InsertFavorite(uuid, len(cfg.Favorites)) {
+			added++
+		}
+	}
+	if err := cfg.Save(); err != nil {
+		fmt.Printf("save config: %v\n", err)
+		return
+	}
+	fmt.Printf("Added %d favorites, %d already in the favorites.\n", added, len(uuids)-added)
+	if len(missing) > 0 {
+		fmt.Println("Not found on radio-browser:")
+		for _, e := range missing {
+			fmt.Printf("  %s\n", e)
+		}
+	}
 }
 
 type nopWriterCloser struct {
@@ -97,7 +382,12 @@ func createLogger() io.WriteCloser {
 	opts := &slog.HandlerOptions{
 		Level: slog.LevelDebug,
 	}
-	handler := slog.NewTextHandler(logW, opts)
+	// the last lines are kept for the crash reports, even without a log file
+	w := io.MultiWriter(logW, crash.Logs)
+	if config.LogView() {
+		w = io.MultiWriter(logW, crash.Logs, ui.Logs)
+	}
+	handler := slog.NewTextHandler(w, opts)
 	logger := slog.New(handler)
 	log.SetFlags(log.Flags() &^ (log.Ldate))
 	slog.SetDefault(logger)
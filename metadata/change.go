@@ -0,0 +1,36 @@
+package metadata
+
+// ChangeDetector reports a song change only when the normalized title of the playing station changes,
+// so that repeated or cosmetically different ICY updates are not seen as new songs.
+// It is not safe for concurrent use.
+type ChangeDetector struct {
+	station string
+	title   string
+}
+
+// Observe returns the normalized title and whether it differs from the last one observed for the station.
+// An empty title is not a change, so a station briefly dropping its metadata doesn't repeat the song.
+func (d *ChangeDetector) Observe(stationUuid, title string) (string, bool) {
+	title = Normalize(title)
+	if stationUuid != d.station {
+		d.station = stationUuid
+		d.title = title
+		return title, true
+	}
+	if title == "" || title == d.title {
+		return d.title, false
+	}
+	d.title = title
+	return title, true
+}
+
+// Station returns the station of the last observed song.
+func (d *ChangeDetector) Station() string {
+	return d.station
+}
+
+// Reset forgets the last observed song.
+func (d *ChangeDetector) Reset() {
+	d.station = ""
+	d.title = ""
+}
@@ -0,0 +1,129 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	acoustIdUrl = "https://api.acoustid.org/v2/lookup"
+	fpcalcBin   = "fpcalc"
+	// captureSec is the length of the stream capture that is fingerprinted
+	captureSec = 20
+)
+
+var (
+	ErrNoFpcalc  = errors.New("fpcalc (Chromaprint) not found in PATH")
+	ErrNoMatch   = errors.New("song not recognized")
+	ErrNoApiKey  = errors.New("missing AcoustID api key")
+	errFpcalcOut = errors.New("invalid fpcalc output")
+)
+
+// Identifier names the song playing on a stream from an audio fingerprint of a short capture,
+// computed with Chromaprint's fpcalc and looked up on AcoustID.
+type Identifier struct {
+	client  *http.Client
+	baseUrl string
+	apiKey  string
+	fpcalc  string
+}
+
+func NewIdentifier(apiKey string) (*Identifier, error) {
+	if apiKey == "" {
+		return nil, ErrNoApiKey
+	}
+	fpcalc, err := exec.LookPath(fpcalcBin)
+	if err != nil {
+		return nil, ErrNoFpcalc
+	}
+	return &Identifier{
+		client:  &http.Client{Timeout: config.ApiReqTimeout},
+		baseUrl: acoustIdUrl,
+		apiKey:  apiKey,
+		fpcalc:  fpcalc,
+	}, nil
+}
+
+type fingerprint struct {
+	Duration    float64 `json:"duration"`
+	Fingerprint string  `json:"fingerprint"`
+}
+
+// Identify captures a few seconds of the stream and returns the matching song.
+func (id *Identifier) Identify(ctx context.Context, streamUrl string) (Song, error) {
+	out, err := exec.CommandContext(ctx, id.fpcalc, "-json", "-length", strconv.Itoa(captureSec), streamUrl).Output()
+	if err != nil {
+		return Song{}, fmt.Errorf("fpcalc: %w", err)
+	}
+	var fp fingerprint
+	if err := json.Unmarshal(out, &fp); err != nil || fp.Fingerprint == "" {
+		return Song{}, errFpcalcOut
+	}
+	return id.lookup(ctx, fp)
+}
+
+type acoustIdResponse struct {
+	Status  string `json:"status"`
+	Results []struct {
+		Score      float64 `json:"score"`
+		Recordings []struct {
+			Title   string `json:"title"`
+			Artists []struct {
+				Name string `json:"name"`
+			} `json:"artists"`
+		} `json:"recordings"`
+	} `json:"results"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (id *Identifier) lookup(ctx context.Context, fp fingerprint) (Song, error) {
+	form := url.Values{
+		"client":      {id.apiKey},
+		"meta":        {"recordings"},
+		"duration":    {strconv.Itoa(int(fp.Duration))},
+		"fingerprint": {fp.Fingerprint},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, id.baseUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Song{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := id.client.Do(req)
+	if err != nil {
+		return Song{}, err
+	}
+	defer res.Body.Close()
+
+	var body acoustIdResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return Song{}, err
+	}
+	if body.Status != "ok" {
+		if body.Error != nil {
+			return Song{}, fmt.Errorf("acoustid: %s", body.Error.Message)
+		}
+		return Song{}, fmt.Errorf("acoustid response status %s", res.Status)
+	}
+	sort.SliceStable(body.Results, func(i, j int) bool { return body.Results[i].Score > body.Results[j].Score })
+	for _, r := range body.Results {
+		for _, rec := range r.Recordings {
+			if rec.Title == "" || len(rec.Artists) == 0 {
+				continue
+			}
+			return Song{Artist: rec.Artists[0].Name, Title: rec.Title}, nil
+		}
+	}
+	return Song{}, ErrNoMatch
+}
@@ -0,0 +1,51 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIdentifier_lookup(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    Song
+		wantErr error
+	}{
+		{
+			name: "best score",
+			body: `{"status":"ok","results":[
+				{"score":0.5,"recordings":[{"title":"Other","artists":[{"name":"Someone"}]}]},
+				{"score":0.9,"recordings":[{"title":"One More Time","artists":[{"name":"Daft Punk"}]}]}]}`,
+			want: Song{Artist: "Daft Punk", Title: "One More Time"},
+		},
+		{
+			name:    "no match",
+			body:    `{"status":"ok","results":[{"score":0.9}]}`,
+			wantErr: ErrNoMatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.FormValue("client") != "key" || r.FormValue("fingerprint") != "AQAA" || r.FormValue("duration") != "20" {
+					t.Errorf("got form %v", r.Form)
+				}
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			id := &Identifier{client: srv.Client(), baseUrl: srv.URL, apiKey: "key"}
+			got, err := id.lookup(context.Background(), fingerprint{Duration: 20.4, Fingerprint: "AQAA"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got err=%v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
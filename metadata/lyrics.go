@@ -0,0 +1,205 @@
+package metadata
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	LyricsCacheSubDir = "lyrics"
+
+	lrclibUrl    = "https://lrclib.net/api/get"
+	lyricsOvhUrl = "https://api.lyrics.ovh/v1/"
+	// songs without lyrics are looked up again after a while, as the providers are community maintained
+	noLyricsMaxAge = 7 * 24 * time.Hour
+)
+
+var ErrNoLyrics = errors.New("no lyrics found")
+
+// LyricsProvider finds the lyrics of a song, returning ErrNoLyrics if it doesn't know them.
+type LyricsProvider interface {
+	Name() string
+	Lyrics(ctx context.Context, s Song) (string, error)
+}
+
+// LyricsProviders are the available providers by name, in their default order.
+var LyricsProviders = []string{"lrclib", "lyricsovh"}
+
+func NewLyricsProvider(name string, ua string) (LyricsProvider, error) {
+	client := &http.Client{Timeout: config.ApiReqTimeout}
+	switch name {
+	case "lrclib":
+		return &lrclib{client: client, baseUrl: lrclibUrl, ua: ua}, nil
+	case "lyricsovh":
+		return &lyricsOvh{client: client, baseUrl: lyricsOvhUrl, ua: ua}, nil
+	}
+	return nil, fmt.Errorf("unknown lyrics provider %q", name)
+}
+
+// Lyrics asks the providers in order for the lyrics of a song, caching the result on disk.
+type Lyrics struct {
+	providers []LyricsProvider
+	dir       string
+}
+
+// NewLyrics creates the named providers, or all of them if names is empty.
+func NewLyrics(version string, dir string, names []string) *Lyrics {
+	log := slog.With("method", "metadata.NewLyrics")
+	if len(names) == 0 {
+		names = LyricsProviders
+	}
+	ua := fmt.Sprintf("sonicradio/%s ( https://github.com/dancnb/sonicradio )", version)
+	l := &Lyrics{dir: dir}
+	for _, n := range names {
+		p, err := NewLyricsProvider(n, ua)
+		if err != nil {
+			log.Error("", "error", err)
+			continue
+		}
+		l.providers = append(l.providers, p)
+	}
+	if dir != "" {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			log.Error("create cache dir", "error", err)
+			l.dir = ""
+		}
+	}
+	return l
+}
+
+func (l *Lyrics) path(s Song) string {
+	if l.dir == "" {
+		return ""
+	}
+	sum := sha1.Sum([]byte(cacheKey(s)))
+	return filepath.Join(l.dir, hex.EncodeToString(sum[:])+".txt")
+}
+
+// Get returns the lyrics of the song, or ErrNoLyrics if no provider has them.
+func (l *Lyrics) Get(ctx context.Context, s Song) (string, error) {
+	log := slog.With("method", "metadata.Lyrics.Get")
+	if s.Artist == "" || s.Title == "" {
+		return "", ErrNoLyrics
+	}
+	fp := l.path(s)
+	if fi, err := os.Stat(fp); err == nil {
+		if fi.Size() == 0 && time.Since(fi.ModTime()) < noLyricsMaxAge {
+			return "", ErrNoLyrics
+		} else if b, err := os.ReadFile(fp); err == nil && len(b) > 0 {
+			return string(b), nil
+		}
+	}
+
+	var errs []error
+	for _, p := range l.providers {
+		text, err := p.Lyrics(ctx, s)
+		if errors.Is(err, ErrNoLyrics) {
+			continue
+		} else if err != nil {
+			log.Error("", "provider", p.Name(), "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		l.store(fp, text)
+		return text, nil
+	}
+	if len(errs) > 0 {
+		// don't remember the miss, a provider could not be reached
+		return "", errors.Join(errs...)
+	}
+	l.store(fp, "")
+	return "", ErrNoLyrics
+}
+
+func (l *Lyrics) store(fp string, text string) {
+	if fp == "" {
+		return
+	}
+	if err := os.WriteFile(fp, []byte(text), 0o644); err != nil {
+		slog.With("method", "metadata.Lyrics.store").Error("", "error", err)
+	}
+}
+
+func getJson(ctx context.Context, client *http.Client, u string, ua string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", ua)
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return ErrNoLyrics
+	} else if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("lyrics response status %s", res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// lrclib uses https://lrclib.net
+type lrclib struct {
+	client  *http.Client
+	baseUrl string
+	ua      string
+}
+
+func (p *lrclib) Name() string { return "lrclib" }
+
+func (p *lrclib) Lyrics(ctx context.Context, s Song) (string, error) {
+	u := p.baseUrl + "?" + url.Values{"artist_name": {s.Artist}, "track_name": {s.Title}}.Encode()
+	var res struct {
+		PlainLyrics  string `json:"plainLyrics"`
+		Instrumental bool   `json:"instrumental"`
+	}
+	if err := getJson(ctx, p.client, u, p.ua, &res); err != nil {
+		return "", err
+	}
+	if res.Instrumental {
+		return "[Instrumental]", nil
+	}
+	if strings.TrimSpace(res.PlainLyrics) == "" {
+		return "", ErrNoLyrics
+	}
+	return res.PlainLyrics, nil
+}
+
+// lyricsOvh uses https://lyricsovh.docs.apiary.io
+type lyricsOvh struct {
+	client  *http.Client
+	baseUrl string
+	ua      string
+}
+
+func (p *lyricsOvh) Name() string { return "lyricsovh" }
+
+func (p *lyricsOvh) Lyrics(ctx context.Context, s Song) (string, error) {
+	u := p.baseUrl + url.PathEscape(s.Artist) + "/" + url.PathEscape(s.Title)
+	var res struct {
+		Lyrics string `json:"lyrics"`
+	}
+	if err := getJson(ctx, p.client, u, p.ua, &res); err != nil {
+		return "", err
+	}
+	text := strings.TrimSpace(strings.ReplaceAll(res.Lyrics, "\r\n", "\n"))
+	if text == "" {
+		return "", ErrNoLyrics
+	}
+	return text, nil
+}
@@ -0,0 +1,43 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type testProvider struct {
+	lyrics map[string]string
+	calls  int
+}
+
+func (p *testProvider) Name() string { return "test" }
+
+func (p *testProvider) Lyrics(_ context.Context, s Song) (string, error) {
+	p.calls++
+	if l, ok := p.lyrics[s.Title]; ok {
+		return l, nil
+	}
+	return "", ErrNoLyrics
+}
+
+func TestLyrics_Get(t *testing.T) {
+	first := &testProvider{lyrics: map[string]string{"A": "first A"}}
+	second := &testProvider{lyrics: map[string]string{"A": "second A", "B": "second B"}}
+	l := &Lyrics{providers: []LyricsProvider{first, second}, dir: t.TempDir()}
+
+	for i := 0; i < 2; i++ {
+		if got, err := l.Get(context.Background(), Song{Artist: "X", Title: "A"}); err != nil || got != "first A" {
+			t.Errorf("got %q, err=%v", got, err)
+		}
+		if got, err := l.Get(context.Background(), Song{Artist: "X", Title: "B"}); err != nil || got != "second B" {
+			t.Errorf("got %q, err=%v", got, err)
+		}
+		if _, err := l.Get(context.Background(), Song{Artist: "X", Title: "C"}); !errors.Is(err, ErrNoLyrics) {
+			t.Errorf("got err=%v, want ErrNoLyrics", err)
+		}
+	}
+	if first.calls != 3 || second.calls != 2 {
+		t.Errorf("got calls=%d,%d, want cached lookups", first.calls, second.calls)
+	}
+}
@@ -0,0 +1,224 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	MusicBrainzCacheFilename = "musicbrainz.json"
+
+	musicBrainzUrl = "https://musicbrainz.org/ws/2/recording"
+	// musicBrainzRate is the request rate allowed for anonymous clients.
+	musicBrainzRate     = time.Second
+	musicBrainzMinScore = 80
+)
+
+// Recording is a MusicBrainz recording matching a song, with the IDs used for scrobbling and cover art.
+type Recording struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Artist    string `json:"artist"`
+	ArtistID  string `json:"artistId"`
+	Album     string `json:"album,omitempty"`
+	ReleaseID string `json:"releaseId,omitempty"`
+	Year      string `json:"year,omitempty"`
+}
+
+// Found reports whether the lookup matched a recording; misses are cached as empty recordings.
+func (r Recording) Found() bool {
+	return r.ID != ""
+}
+
+// MusicBrainz resolves songs to recordings, caching the results on disk and
+// keeping to the service's limit of one request per second.
+type MusicBrainz struct {
+	client  *http.Client
+	baseUrl string
+	ua      string
+
+	rateMtx sync.Mutex
+	lastReq time.Time
+
+	mtx   sync.Mutex
+	path  string
+	cache map[string]Recording
+	dirty bool
+}
+
+func NewMusicBrainz(version string, cachePath string) *MusicBrainz {
+	log := slog.With("method", "metadata.NewMusicBrainz")
+	mb := &MusicBrainz{
+		client:  &http.Client{Timeout: config.ApiReqTimeout},
+		baseUrl: musicBrainzUrl,
+		ua:      fmt.Sprintf("sonicradio/%s ( https://github.com/dancnb/sonicradio )", version),
+		path:    cachePath,
+		cache:   make(map[string]Recording),
+	}
+	if cachePath == "" {
+		return mb
+	}
+	b, err := os.ReadFile(cachePath)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Error("read cache", "error", err)
+		}
+		return mb
+	}
+	if err := json.Unmarshal(b, &mb.cache); err != nil {
+		log.Error("unmarshal cache", "error", err)
+	}
+	return mb
+}
+
+func cacheKey(s Song) string {
+	return strings.ToLower(s.Artist) + "\x00" + strings.ToLower(s.Title)
+}
+
+// Lookup returns the best matching recording for the song, which is not Found when there is no good match.
+func (mb *MusicBrainz) Lookup(ctx context.Context, s Song) (Recording, error) {
+	log := slog.With("method", "metadata.MusicBrainz.Lookup")
+	if s.Artist == "" || s.Title == "" {
+		return Recording{}, nil
+	}
+	key := cacheKey(s)
+	mb.mtx.Lock()
+	r, ok := mb.cache[key]
+	mb.mtx.Unlock()
+	if ok {
+		return r, nil
+	}
+
+	if err := mb.wait(ctx); err != nil {
+		return Recording{}, err
+	}
+	r, err := mb.search(ctx, s)
+	if err != nil {
+		return Recording{}, err
+	}
+	log.Info("", "song", s.String(), "recording", r.ID)
+
+	mb.mtx.Lock()
+	mb.cache[key] = r
+	mb.dirty = true
+	mb.mtx.Unlock()
+	return r, nil
+}
+
+// wait blocks until a request can be made without exceeding the rate limit.
+func (mb *MusicBrainz) wait(ctx context.Context) error {
+	mb.rateMtx.Lock()
+	defer mb.rateMtx.Unlock()
+
+	if d := time.Until(mb.lastReq.Add(musicBrainzRate)); d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	mb.lastReq = time.Now()
+	return nil
+}
+
+type mbResponse struct {
+	Recordings []struct {
+		ID           string `json:"id"`
+		Score        int    `json:"score"`
+		Title        string `json:"title"`
+		ArtistCredit []struct {
+			Name   string `json:"name"`
+			Artist struct {
+				ID string `json:"id"`
+			} `json:"artist"`
+		} `json:"artist-credit"`
+		Releases []struct {
+			ID    string `json:"id"`
+			Title string `json:"title"`
+			Date  string `json:"date"`
+		} `json:"releases"`
+	} `json:"recordings"`
+}
+
+func (mb *MusicBrainz) search(ctx context.Context, s Song) (Recording, error) {
+	q := fmt.Sprintf(`recording:"%s" AND artist:"%s"`, escapeLucene(s.Title), escapeLucene(s.Artist))
+	u := mb.baseUrl + "?" + url.Values{"query": {q}, "fmt": {"json"}, "limit": {"5"}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return Recording{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", mb.ua)
+	res, err := mb.client.Do(req)
+	if err != nil {
+		return Recording{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Recording{}, fmt.Errorf("musicbrainz response status %s", res.Status)
+	}
+
+	var body mbResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return Recording{}, err
+	}
+	for _, rec := range body.Recordings {
+		if rec.Score < musicBrainzMinScore || len(rec.ArtistCredit) == 0 {
+			continue
+		}
+		r := Recording{
+			ID:       rec.ID,
+			Title:    rec.Title,
+			Artist:   rec.ArtistCredit[0].Name,
+			ArtistID: rec.ArtistCredit[0].Artist.ID,
+		}
+		// the earliest dated release is most likely the original album
+		for _, rel := range rec.Releases {
+			if r.ReleaseID == "" || (rel.Date != "" && (r.Year == "" || rel.Date < r.Year)) {
+				r.Album, r.ReleaseID, r.Year = rel.Title, rel.ID, rel.Date
+			}
+		}
+		if len(r.Year) > 4 {
+			r.Year = r.Year[:4]
+		}
+		return r, nil
+	}
+	return Recording{}, nil
+}
+
+func escapeLucene(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// Save persists the lookup cache.
+func (mb *MusicBrainz) Save() error {
+	mb.mtx.Lock()
+	defer mb.mtx.Unlock()
+
+	if !mb.dirty || mb.path == "" {
+		return nil
+	}
+	b, err := json.Marshal(mb.cache)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(mb.path, b, 0o644); err != nil {
+		return err
+	}
+	mb.dirty = false
+	return nil
+}
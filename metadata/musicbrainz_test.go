@@ -0,0 +1,67 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const mbBody = `{"recordings":[
+	{"id":"low","score":40,"title":"Other","artist-credit":[{"name":"Someone","artist":{"id":"a0"}}]},
+	{"id":"rec1","score":100,"title":"One More Time","artist-credit":[{"name":"Daft Punk","artist":{"id":"a1"}}],
+	 "releases":[{"id":"r2","title":"Alive 2007","date":"2007-11-19"},{"id":"r1","title":"Discovery","date":"2001-03-12"}]}
+]}`
+
+func TestMusicBrainz_Lookup(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if !strings.HasPrefix(r.Header.Get("User-Agent"), "sonicradio/") {
+			t.Errorf("got User-Agent=%q", r.Header.Get("User-Agent"))
+		}
+		_, _ = w.Write([]byte(mbBody))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), MusicBrainzCacheFilename)
+	mb := NewMusicBrainz("test", path)
+	mb.baseUrl = srv.URL
+	song := Song{Artist: "Daft Punk", Title: "One More Time"}
+	want := Recording{ID: "rec1", Title: "One More Time", Artist: "Daft Punk", ArtistID: "a1", Album: "Discovery", ReleaseID: "r1", Year: "2001"}
+	for i := 0; i < 2; i++ {
+		got, err := mb.Lookup(context.Background(), song)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("got requests=%d, want 1", requests)
+	}
+
+	if err := mb.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := NewMusicBrainz("test", path)
+	reloaded.baseUrl = "http://127.0.0.1:0"
+	if got, err := reloaded.Lookup(context.Background(), Song{Artist: "daft punk", Title: "one more time"}); err != nil || got != want {
+		t.Errorf("got cached %+v, err=%v", got, err)
+	}
+}
+
+func TestMusicBrainz_wait(t *testing.T) {
+	mb := NewMusicBrainz("test", "")
+	if err := mb.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := mb.wait(ctx); err == nil {
+		t.Error("expected rate limited wait to return the context error")
+	}
+}
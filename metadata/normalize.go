@@ -0,0 +1,90 @@
+// Package metadata handles the song information published by radio streams.
+package metadata
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// nowPlayingPrefixes are announcements some stations put in front of the ICY title.
+var nowPlayingPrefixes = []string{
+	"now playing",
+	"now on air",
+	"on air",
+	"playing",
+}
+
+// Normalize cleans up an ICY stream title: it repairs mis-decoded text,
+// unescapes HTML entities, drops control characters and "now playing:"
+// prefixes, strips wrapping quotes and collapses whitespace.
+func Normalize(title string) string {
+	title = fixEncoding(title)
+	title = html.UnescapeString(title)
+	title = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\ufeff' || r == '\u200b' || r == utf8.RuneError:
+			return -1
+		case unicode.IsControl(r) || unicode.IsSpace(r):
+			return ' '
+		}
+		return r
+	}, title)
+	title = strings.Join(strings.Fields(title), " ")
+	title = trimNowPlaying(title)
+	title = strings.Trim(title, `'" `)
+	return title
+}
+
+func trimNowPlaying(title string) string {
+	lower := strings.ToLower(title)
+	for _, p := range nowPlayingPrefixes {
+		if !strings.HasPrefix(lower, p) {
+			continue
+		}
+		rest := strings.TrimLeft(title[len(p):], " ")
+		if len(rest) > 0 && strings.ContainsRune(":-–|>", []rune(rest)[0]) {
+			_, size := utf8.DecodeRuneInString(rest)
+			return strings.TrimSpace(rest[size:])
+		}
+	}
+	return title
+}
+
+// fixEncoding converts Latin-1 bytes to UTF-8 and repairs UTF-8 text that was
+// decoded as Latin-1 along the way (e.g. "BeyoncÃ©").
+func fixEncoding(s string) string {
+	if !utf8.ValidString(s) {
+		return latin1ToUTF8([]byte(s))
+	}
+	b := make([]byte, 0, len(s))
+	hasHigh := false
+	for _, r := range s {
+		if r > 0xFF {
+			return s
+		}
+		if r >= 0x80 {
+			hasHigh = true
+		}
+		b = append(b, byte(r))
+	}
+	if hasHigh && utf8.Valid(b) {
+		return string(b)
+	}
+	return s
+}
+
+func latin1ToUTF8(b []byte) string {
+	var sb strings.Builder
+	sb.Grow(len(b) * 2)
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if r == utf8.RuneError && size == 1 {
+			r = rune(b[0])
+		}
+		sb.WriteRune(r)
+		b = b[size:]
+	}
+	return sb.String()
+}
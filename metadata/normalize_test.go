@@ -0,0 +1,55 @@
+package metadata
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Artist - Title", "Artist - Title"},
+		{"  Artist  -\tTitle \r\n", "Artist - Title"},
+		{"Now Playing: Artist - Title", "Artist - Title"},
+		{"NOW PLAYING - Artist - Title", "Artist - Title"},
+		{"Playing Artist - Title", "Playing Artist - Title"},
+		{"'Artist - Title'", "Artist - Title"},
+		{"Simon &amp; Garfunkel - Mrs. Robinson", "Simon & Garfunkel - Mrs. Robinson"},
+		{"BeyoncÃ© - Halo", "Beyoncé - Halo"},
+		{"Beyonc\xe9 - Halo", "Beyoncé - Halo"},
+		{"\ufeffArtist\u200b - Title", "Artist - Title"},
+		{"Motörhead - Ace of Spades", "Motörhead - Ace of Spades"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestChangeDetector(t *testing.T) {
+	var d ChangeDetector
+	steps := []struct {
+		station string
+		title   string
+		want    string
+		changed bool
+	}{
+		{"a", "Artist - Title", "Artist - Title", true},
+		{"a", "Artist  -  Title ", "Artist - Title", false},
+		{"a", "Now playing: Artist - Title", "Artist - Title", false},
+		{"a", "", "Artist - Title", false},
+		{"a", "Artist - Other", "Artist - Other", true},
+		{"b", "Artist - Other", "Artist - Other", true},
+	}
+	for i, s := range steps {
+		got, changed := d.Observe(s.station, s.title)
+		if got != s.want || changed != s.changed {
+			t.Errorf("step %d: got (%q, %v), want (%q, %v)", i, got, changed, s.want, s.changed)
+		}
+	}
+	d.Reset()
+	if _, changed := d.Observe("b", "Artist - Other"); !changed {
+		t.Error("expected change after reset")
+	}
+}
@@ -0,0 +1,98 @@
+package metadata
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+// Song is a stream title split into its artist and title.
+type Song struct {
+	Artist string
+	Title  string
+}
+
+func (s Song) String() string {
+	if s.Artist == "" {
+		return s.Title
+	}
+	return s.Artist + " - " + s.Title
+}
+
+// artistTitleSeparators are tried in order when the rule doesn't name a separator.
+var artistTitleSeparators = []string{" - ", " – ", " — ", " ~ ", " | ", " :: "}
+
+// titleArtistSeparators are separators where the title comes first, as in "Title / Artist" or "Title by Artist".
+var titleArtistSeparators = []string{" / ", " by "}
+
+// Parse splits a stream title into artist and title using rule, falling back to the heuristics
+// for the most common formats when the rule is not set. Titles that can't be split are
+// returned with an empty Artist.
+func Parse(streamTitle string, rule config.SongRule) Song {
+	title := Normalize(streamTitle)
+	if rule.Prefix != "" && len(title) >= len(rule.Prefix) && strings.EqualFold(title[:len(rule.Prefix)], rule.Prefix) {
+		title = strings.TrimLeft(title[len(rule.Prefix):], " :-|")
+	}
+	title = trimStationPrefix(title)
+
+	switch rule.Format {
+	case config.SongFormatTitleOnly:
+		return Song{Title: title}
+	case config.SongFormatArtistTitle, config.SongFormatTitleArtist:
+		seps := artistTitleSeparators
+		if rule.Separator != "" {
+			seps = []string{rule.Separator}
+		} else if rule.Format == config.SongFormatTitleArtist {
+			seps = slices.Concat(titleArtistSeparators, seps)
+		}
+		a, b, ok := split(title, seps)
+		if !ok {
+			return Song{Title: title}
+		}
+		if rule.Format == config.SongFormatTitleArtist {
+			return Song{Artist: b, Title: a}
+		}
+		return Song{Artist: a, Title: b}
+	}
+
+	if rule.Separator != "" {
+		if a, b, ok := split(title, []string{rule.Separator}); ok {
+			return Song{Artist: a, Title: b}
+		}
+	}
+	if a, b, ok := split(title, artistTitleSeparators); ok {
+		return Song{Artist: a, Title: b}
+	}
+	if a, b, ok := split(title, titleArtistSeparators); ok {
+		return Song{Artist: b, Title: a}
+	}
+	return Song{Title: title}
+}
+
+// trimStationPrefix drops a leading station tag such as "[Radio X] " or "Radio X | " when
+// the remainder still looks like a song.
+func trimStationPrefix(title string) string {
+	if strings.HasPrefix(title, "[") {
+		if i := strings.Index(title, "]"); i > 0 && i < len(title)-1 {
+			return strings.TrimSpace(title[i+1:])
+		}
+	}
+	if before, after, ok := strings.Cut(title, " | "); ok && !strings.Contains(before, " - ") {
+		if _, _, ok := split(after, artistTitleSeparators); ok {
+			return after
+		}
+	}
+	return title
+}
+
+func split(title string, seps []string) (string, string, bool) {
+	for _, sep := range seps {
+		a, b, ok := strings.Cut(title, sep)
+		a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+		if ok && a != "" && b != "" {
+			return a, b, true
+		}
+	}
+	return "", "", false
+}
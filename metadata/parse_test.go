@@ -0,0 +1,36 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		rule  config.SongRule
+		want  Song
+	}{
+		{"artist title", "Daft Punk - One More Time", config.SongRule{}, Song{"Daft Punk", "One More Time"}},
+		{"en dash", "Daft Punk – One More Time", config.SongRule{}, Song{"Daft Punk", "One More Time"}},
+		{"hyphenated artist", "Jay-Z - 99 Problems", config.SongRule{}, Song{"Jay-Z", "99 Problems"}},
+		{"title slash artist", "One More Time / Daft Punk", config.SongRule{}, Song{"Daft Punk", "One More Time"}},
+		{"title by artist", "One More Time by Daft Punk", config.SongRule{}, Song{"Daft Punk", "One More Time"}},
+		{"bracket prefix", "[Radio X] Daft Punk - One More Time", config.SongRule{}, Song{"Daft Punk", "One More Time"}},
+		{"pipe prefix", "Radio X | Daft Punk - One More Time", config.SongRule{}, Song{"Daft Punk", "One More Time"}},
+		{"no separator", "Station jingle", config.SongRule{}, Song{"", "Station jingle"}},
+		{"rule prefix", "RADIOX: Daft Punk - One More Time", config.SongRule{Prefix: "radiox"}, Song{"Daft Punk", "One More Time"}},
+		{"rule title artist", "One More Time - Daft Punk", config.SongRule{Format: config.SongFormatTitleArtist}, Song{"Daft Punk", "One More Time"}},
+		{"rule title only", "Live - Morning show", config.SongRule{Format: config.SongFormatTitleOnly}, Song{"", "Live - Morning show"}},
+		{"rule separator", "Daft Punk ** One More Time", config.SongRule{Separator: " ** "}, Song{"Daft Punk", "One More Time"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.title, tt.rule); got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.title, got, tt.want)
+			}
+		})
+	}
+}
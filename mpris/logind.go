@@ -0,0 +1,98 @@
+package mpris
+
+import (
+	"os"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	logindName         = "org.freedesktop.login1"
+	logindPath         = dbus.ObjectPath("/org/freedesktop/login1")
+	logindManagerIface = "org.freedesktop.login1.Manager"
+	logindSessionIface = "org.freedesktop.login1.Session"
+)
+
+// LockWatcher follows the lock of the logind session of the app on the system bus.
+type LockWatcher struct {
+	conn *dbus.Conn
+	path dbus.ObjectPath
+}
+
+// WatchLock connects to the system bus and passes true to the locked func when the session locks, false
+// when it unlocks, from the connection's goroutine. The session is locked by the Lock signal, sent by
+// loginctl lock-session, or by the LockedHint set by the screen lockers.
+func WatchLock(locked func(bool)) (*LockWatcher, error) {
+	c, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, err
+	}
+	w := &LockWatcher{conn: c}
+	if w.path, err = w.session(); err != nil {
+		c.Close()
+		return nil, err
+	}
+	for _, rule := range [][]dbus.MatchOption{
+		{dbus.WithMatchSender(logindName), dbus.WithMatchInterface(logindSessionIface), dbus.WithMatchObjectPath(w.path)},
+		{
+			dbus.WithMatchSender(logindName), dbus.WithMatchInterface(ifaceProps), dbus.WithMatchMember("PropertiesChanged"),
+			dbus.WithMatchObjectPath(w.path), dbus.WithMatchArg(0, logindSessionIface),
+		},
+	} {
+		if err := c.AddMatchSignal(rule...); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+
+	signals := make(chan *dbus.Signal, 8)
+	c.Signal(signals)
+	go func() {
+		// closed with the connection
+		for s := range signals {
+			if s.Path != w.path {
+				continue
+			}
+			if v, ok := lockSignal(s); ok {
+				locked(v)
+			}
+		}
+	}()
+	return w, nil
+}
+
+// session returns the object path of the session of the app, by its process or, when it's started
+// outside of the session, e.g. by a user service, by the display session of the user.
+func (w *LockWatcher) session() (dbus.ObjectPath, error) {
+	var p dbus.ObjectPath
+	obj := w.conn.Object(logindName, logindPath)
+	err := obj.Call(logindManagerIface+".GetSessionByPID", 0, uint32(os.Getpid())).Store(&p)
+	if err != nil {
+		err = obj.Call(logindManagerIface+".GetSession", 0, "auto").Store(&p)
+	}
+	return p, err
+}
+
+// lockSignal reads the lock state from a signal of the session.
+func lockSignal(s *dbus.Signal) (bool, bool) {
+	switch s.Name {
+	case logindSessionIface + ".Lock":
+		return true, true
+	case logindSessionIface + ".Unlock":
+		return false, true
+	case ifaceProps + ".PropertiesChanged":
+		if len(s.Body) < 2 {
+			return false, false
+		}
+		changed, _ := s.Body[1].(map[string]dbus.Variant)
+		if v, ok := changed["LockedHint"]; ok {
+			hint, ok := v.Value().(bool)
+			return hint, ok
+		}
+	}
+	return false, false
+}
+
+func (w *LockWatcher) Close() error {
+	return w.conn.Close()
+}
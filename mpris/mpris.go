@@ -0,0 +1,365 @@
+// Package mpris exposes the playback on the D-Bus session bus with the MPRIS interface,
+// so that the media keys and the desktop media controls reach the app when its terminal is not focused.
+// It also follows the lock of the logind session on the system bus.
+package mpris
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+)
+
+const (
+	namePrefix   = "org.mpris.MediaPlayer2."
+	appName      = "sonicradio"
+	objPath      = dbus.ObjectPath("/org/mpris/MediaPlayer2")
+	ifaceRoot    = "org.mpris.MediaPlayer2"
+	ifacePlayer  = "org.mpris.MediaPlayer2.Player"
+	ifaceProps   = "org.freedesktop.DBus.Properties"
+	ifaceIntro   = "org.freedesktop.DBus.Introspectable"
+	noTrack      = dbus.ObjectPath("/org/mpris/MediaPlayer2/TrackList/NoTrack")
+	trackPathFmt = "/org/sonicradio/track/%d"
+
+	errUnknownProperty = "org.freedesktop.DBus.Error.UnknownProperty"
+	errInvalidArgs     = "org.freedesktop.DBus.Error.InvalidArgs"
+	errNotSupported    = "org.freedesktop.DBus.Error.NotSupported"
+)
+
+type Action uint8
+
+const (
+	PlayPause Action = iota
+	Play
+	Pause
+	Stop
+	Next
+	Previous
+	Volume
+)
+
+// Command is a request of an MPRIS client, e.g. a media key handled by the desktop.
+type Command struct {
+	Action Action
+	// Volume is the requested volume of a Volume command, 0-100
+	Volume int
+}
+
+type Status uint8
+
+const (
+	Stopped Status = iota
+	Playing
+	Paused
+)
+
+func (s Status) String() string {
+	switch s {
+	case Stopped:
+		return "Stopped"
+	case Playing:
+		return "Playing"
+	case Paused:
+		return "Paused"
+	}
+	return "unknown Status"
+}
+
+// State is the playback shown to the MPRIS clients.
+type State struct {
+	Status  Status
+	Station string
+	Artist  string
+	Title   string
+	ArtURL  string
+	Volume  int
+}
+
+// Server answers the MPRIS clients on the session bus.
+type Server struct {
+	conn     *dbus.Conn
+	commands func(Command)
+
+	mtx   sync.Mutex
+	state State
+	track int
+}
+
+// sessionBusAddress returns the address of the session bus, without launching one when there is none.
+func sessionBusAddress() (string, error) {
+	if addr := os.Getenv("DBUS_SESSION_BUS_ADDRESS"); addr != "" {
+		return addr, nil
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return "unix:path=" + dir + "/bus", nil
+	}
+	return "", errors.New("no session bus address")
+}
+
+// Start connects to the session bus and registers the app as an MPRIS player.
+// The commands of the clients are passed to the commands func, from the connection's goroutines.
+func Start(commands func(Command)) (*Server, error) {
+	addr, err := sessionBusAddress()
+	if err != nil {
+		return nil, err
+	}
+	c, err := dbus.Connect(addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{conn: c, commands: commands}
+	if err := s.export(); err != nil {
+		c.Close()
+		return nil, err
+	}
+	// another instance may own the name, the instance name is then made unique by the process id
+	for _, name := range []string{namePrefix + appName, fmt.Sprintf("%s%s.instance%d", namePrefix, appName, os.Getpid())} {
+		res, err := c.RequestName(name, dbus.NameFlagDoNotQueue)
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+		if res == dbus.RequestNameReplyPrimaryOwner {
+			return s, nil
+		}
+	}
+	c.Close()
+	return nil, fmt.Errorf("%s name is taken", namePrefix+appName)
+}
+
+func (s *Server) export() error {
+	player := map[string]any{
+		// live streams can't seek
+		"Seek":        func(int64) *dbus.Error { return nil },
+		"SetPosition": func(dbus.ObjectPath, int64) *dbus.Error { return nil },
+		"OpenUri":     func(string) *dbus.Error { return dbus.NewError(errNotSupported, nil) },
+	}
+	for name, a := range map[string]Action{
+		"PlayPause": PlayPause,
+		"Play":      Play,
+		"Pause":     Pause,
+		"Stop":      Stop,
+		"Next":      Next,
+		"Previous":  Previous,
+	} {
+		player[name] = func() *dbus.Error {
+			s.commands(Command{Action: a})
+			return nil
+		}
+	}
+	tables := []struct {
+		iface   string
+		methods map[string]any
+	}{
+		{ifacePlayer, player},
+		{ifaceRoot, map[string]any{
+			"Raise": func() *dbus.Error { return nil },
+			"Quit":  func() *dbus.Error { return nil },
+		}},
+		{ifaceProps, map[string]any{"Get": s.get, "GetAll": s.getAll, "Set": s.set}},
+	}
+	for _, t := range tables {
+		if err := s.conn.ExportMethodTable(t.methods, objPath, t.iface); err != nil {
+			return err
+		}
+	}
+	return s.conn.Export(introspect.Introspectable(introspection), objPath, ifaceIntro)
+}
+
+// Update sets the playback state, notifying the clients of the changed properties.
+func (s *Server) Update(st State) {
+	s.mtx.Lock()
+	prev := s.state
+	if st == prev {
+		s.mtx.Unlock()
+		return
+	}
+	if st.Title != prev.Title || st.Station != prev.Station {
+		s.track++
+	}
+	s.state = st
+	changed := make(map[string]dbus.Variant)
+	if st.Status != prev.Status {
+		changed["PlaybackStatus"] = s.property("PlaybackStatus")
+	}
+	if st.Volume != prev.Volume {
+		changed["Volume"] = s.property("Volume")
+	}
+	if st.Title != prev.Title || st.Station != prev.Station || st.Artist != prev.Artist || st.ArtURL != prev.ArtURL {
+		changed["Metadata"] = s.property("Metadata")
+	}
+	s.mtx.Unlock()
+
+	if len(changed) == 0 {
+		return
+	}
+	if err := s.conn.Emit(objPath, ifaceProps+".PropertiesChanged", ifacePlayer, changed, []string{}); err != nil {
+		slog.With("method", "mpris.Server.Update").Error("", "error", err)
+	}
+}
+
+func (s *Server) Close() error {
+	return s.conn.Close()
+}
+
+var rootProperties = []string{"CanQuit", "CanRaise", "HasTrackList", "Identity", "SupportedUriSchemes", "SupportedMimeTypes"}
+
+var playerProperties = []string{
+	"PlaybackStatus", "LoopStatus", "Rate", "Shuffle", "Metadata", "Volume", "Position",
+	"MinimumRate", "MaximumRate", "CanGoNext", "CanGoPrevious", "CanPlay", "CanPause", "CanSeek", "CanControl",
+}
+
+// property returns the value of a property of the root or player interface, s.mtx must be held.
+func (s *Server) property(name string) dbus.Variant {
+	st := s.state
+	switch name {
+	case "CanQuit", "CanRaise", "HasTrackList", "Shuffle", "CanSeek":
+		return dbus.MakeVariant(false)
+	case "CanGoNext", "CanGoPrevious", "CanPlay", "CanPause", "CanControl":
+		return dbus.MakeVariant(true)
+	case "Identity":
+		return dbus.MakeVariant(appName)
+	case "SupportedUriSchemes", "SupportedMimeTypes":
+		return dbus.MakeVariant([]string{})
+	case "PlaybackStatus":
+		return dbus.MakeVariant(st.Status.String())
+	case "LoopStatus":
+		return dbus.MakeVariant("None")
+	case "Rate", "MinimumRate", "MaximumRate":
+		return dbus.MakeVariant(1.0)
+	case "Volume":
+		return dbus.MakeVariant(float64(st.Volume) / 100)
+	case "Position":
+		return dbus.MakeVariant(int64(0))
+	case "Metadata":
+		md := map[string]dbus.Variant{"mpris:trackid": dbus.MakeVariant(noTrack)}
+		if st.Status == Stopped {
+			return dbus.MakeVariant(md)
+		}
+		md["mpris:trackid"] = dbus.MakeVariant(dbus.ObjectPath(fmt.Sprintf(trackPathFmt, s.track)))
+		title := st.Title
+		if title == "" {
+			title = st.Station
+		}
+		md["xesam:title"] = dbus.MakeVariant(title)
+		md["xesam:album"] = dbus.MakeVariant(st.Station)
+		if st.Artist != "" {
+			md["xesam:artist"] = dbus.MakeVariant([]string{st.Artist})
+		}
+		if st.ArtURL != "" {
+			md["mpris:artUrl"] = dbus.MakeVariant(st.ArtURL)
+		}
+		return dbus.MakeVariant(md)
+	}
+	return dbus.Variant{}
+}
+
+func properties(iface string) ([]string, *dbus.Error) {
+	switch iface {
+	case ifaceRoot:
+		return rootProperties, nil
+	case ifacePlayer:
+		return playerProperties, nil
+	}
+	return nil, dbus.NewError(errInvalidArgs, []any{"unknown interface " + iface})
+}
+
+func (s *Server) get(iface, name string) (dbus.Variant, *dbus.Error) {
+	names, derr := properties(iface)
+	if derr != nil {
+		return dbus.Variant{}, derr
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, n := range names {
+		if n == name {
+			return s.property(n), nil
+		}
+	}
+	return dbus.Variant{}, dbus.NewError(errUnknownProperty, []any{"unknown property " + name})
+}
+
+func (s *Server) getAll(iface string) (map[string]dbus.Variant, *dbus.Error) {
+	names, derr := properties(iface)
+	if derr != nil {
+		return nil, derr
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	all := make(map[string]dbus.Variant, len(names))
+	for _, n := range names {
+		all[n] = s.property(n)
+	}
+	return all, nil
+}
+
+// set handles the only writable property, the volume.
+func (s *Server) set(iface, name string, v dbus.Variant) *dbus.Error {
+	if _, derr := properties(iface); derr != nil {
+		return derr
+	}
+	vol, ok := v.Value().(float64)
+	if iface != ifacePlayer || name != "Volume" || !ok {
+		return dbus.NewError(errNotSupported, nil)
+	}
+	s.commands(Command{Action: Volume, Volume: int(vol*100 + 0.5)})
+	return nil
+}
+
+const introspection = `<!DOCTYPE node PUBLIC "-//freedesktop//DTD D-BUS Object Introspection 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/introspect.dtd">
+<node>
+  <interface name="org.freedesktop.DBus.Introspectable">
+    <method name="Introspect"><arg name="data" type="s" direction="out"/></method>
+  </interface>
+  <interface name="org.freedesktop.DBus.Peer">
+    <method name="Ping"/>
+  </interface>
+  <interface name="org.freedesktop.DBus.Properties">
+    <method name="Get"><arg name="interface" type="s" direction="in"/><arg name="name" type="s" direction="in"/><arg name="value" type="v" direction="out"/></method>
+    <method name="GetAll"><arg name="interface" type="s" direction="in"/><arg name="properties" type="a{sv}" direction="out"/></method>
+    <method name="Set"><arg name="interface" type="s" direction="in"/><arg name="name" type="s" direction="in"/><arg name="value" type="v" direction="in"/></method>
+    <signal name="PropertiesChanged"><arg name="interface" type="s"/><arg name="changed" type="a{sv}"/><arg name="invalidated" type="as"/></signal>
+  </interface>
+  <interface name="org.mpris.MediaPlayer2">
+    <method name="Raise"/>
+    <method name="Quit"/>
+    <property name="CanQuit" type="b" access="read"/>
+    <property name="CanRaise" type="b" access="read"/>
+    <property name="HasTrackList" type="b" access="read"/>
+    <property name="Identity" type="s" access="read"/>
+    <property name="SupportedUriSchemes" type="as" access="read"/>
+    <property name="SupportedMimeTypes" type="as" access="read"/>
+  </interface>
+  <interface name="org.mpris.MediaPlayer2.Player">
+    <method name="Next"/>
+    <method name="Previous"/>
+    <method name="Pause"/>
+    <method name="PlayPause"/>
+    <method name="Stop"/>
+    <method name="Play"/>
+    <method name="Seek"><arg name="Offset" type="x" direction="in"/></method>
+    <method name="SetPosition"><arg name="TrackId" type="o" direction="in"/><arg name="Position" type="x" direction="in"/></method>
+    <method name="OpenUri"><arg name="Uri" type="s" direction="in"/></method>
+    <signal name="Seeked"><arg name="Position" type="x"/></signal>
+    <property name="PlaybackStatus" type="s" access="read"/>
+    <property name="LoopStatus" type="s" access="read"/>
+    <property name="Rate" type="d" access="read"/>
+    <property name="Shuffle" type="b" access="read"/>
+    <property name="Metadata" type="a{sv}" access="read"/>
+    <property name="Volume" type="d" access="readwrite"/>
+    <property name="Position" type="x" access="read"/>
+    <property name="MinimumRate" type="d" access="read"/>
+    <property name="MaximumRate" type="d" access="read"/>
+    <property name="CanGoNext" type="b" access="read"/>
+    <property name="CanGoPrevious" type="b" access="read"/>
+    <property name="CanPlay" type="b" access="read"/>
+    <property name="CanPause" type="b" access="read"/>
+    <property name="CanSeek" type="b" access="read"/>
+    <property name="CanControl" type="b" access="read"/>
+  </interface>
+</node>`
@@ -0,0 +1,253 @@
+package mpris
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestProperties(t *testing.T) {
+	var got []Command
+	s := &Server{commands: func(c Command) { got = append(got, c) }}
+	s.state = State{Status: Playing, Station: "Radio", Artist: "Artist", Title: "Title"}
+	s.track = 1
+
+	if v, err := s.get(ifacePlayer, "PlaybackStatus"); err != nil || v.Value() != "Playing" {
+		t.Errorf("PlaybackStatus = %v, %v", v, err)
+	}
+	if _, err := s.get(ifacePlayer, "Missing"); err == nil || err.Name != errUnknownProperty {
+		t.Errorf("missing property error %v", err)
+	}
+	if _, err := s.getAll("org.example.Missing"); err == nil || err.Name != errInvalidArgs {
+		t.Errorf("missing interface error %v", err)
+	}
+	all, err := s.getAll(ifacePlayer)
+	if err != nil || len(all) != len(playerProperties) {
+		t.Fatalf("GetAll = %v, %v", all, err)
+	}
+	want := map[string]dbus.Variant{
+		"mpris:trackid": dbus.MakeVariant(dbus.ObjectPath("/org/sonicradio/track/1")),
+		"xesam:title":   dbus.MakeVariant("Title"),
+		"xesam:album":   dbus.MakeVariant("Radio"),
+		"xesam:artist":  dbus.MakeVariant([]string{"Artist"}),
+	}
+	if md := all["Metadata"].Value(); !reflect.DeepEqual(md, want) {
+		t.Errorf("Metadata = %v, want %v", md, want)
+	}
+
+	if err := s.set(ifacePlayer, "Volume", dbus.MakeVariant(0.42)); err != nil {
+		t.Error(err)
+	}
+	if err := s.set(ifacePlayer, "Rate", dbus.MakeVariant(2.0)); err == nil || err.Name != errNotSupported {
+		t.Errorf("Rate set error %v", err)
+	}
+	if !reflect.DeepEqual(got, []Command{{Action: Volume, Volume: 42}}) {
+		t.Errorf("commands %v", got)
+	}
+}
+
+func TestLockSignal(t *testing.T) {
+	session := dbus.ObjectPath("/org/freedesktop/login1/session/_32")
+	tests := []struct {
+		s      *dbus.Signal
+		locked bool
+		ok     bool
+	}{
+		{&dbus.Signal{Path: session, Name: logindSessionIface + ".Lock"}, true, true},
+		{&dbus.Signal{Path: session, Name: logindSessionIface + ".Unlock"}, false, true},
+		{&dbus.Signal{
+			Path: session, Name: ifaceProps + ".PropertiesChanged",
+			Body: []any{logindSessionIface, map[string]dbus.Variant{"LockedHint": dbus.MakeVariant(true)}, []string{}},
+		}, true, true},
+		{&dbus.Signal{
+			Path: session, Name: ifaceProps + ".PropertiesChanged",
+			Body: []any{logindSessionIface, map[string]dbus.Variant{"IdleHint": dbus.MakeVariant(true)}, []string{}},
+		}, false, false},
+	}
+	for _, tt := range tests {
+		if locked, ok := lockSignal(tt.s); locked != tt.locked || ok != tt.ok {
+			t.Errorf("%s %v: got locked %v %v, want %v %v", tt.s.Name, tt.s.Body, locked, ok, tt.locked, tt.ok)
+		}
+	}
+}
+
+// startBus runs a private dbus-daemon and returns its address, skipping the test without one.
+func startBus(t *testing.T) string {
+	t.Helper()
+	bin, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("no dbus-daemon")
+	}
+	dir := t.TempDir()
+	conf := filepath.Join(dir, "bus.conf")
+	err = os.WriteFile(conf, []byte(fmt.Sprintf(`<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:dir=%s</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>`, dir)), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(bin, "--config-file="+conf, "--nofork", "--print-address=1")
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	addr, err := bufio.NewReader(out).ReadString('\n')
+	if err != nil {
+		t.Fatalf("dbus-daemon address: %v", err)
+	}
+	return strings.TrimSpace(addr)
+}
+
+func connect(t *testing.T, addr string) *dbus.Conn {
+	t.Helper()
+	c, err := dbus.Connect(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestServer_bus(t *testing.T) {
+	addr := startBus(t)
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", addr)
+	commands := make(chan Command, 1)
+	s, err := Start(func(c Command) { commands <- c })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// a second instance gets its own name
+	other, err := Start(func(Command) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Close()
+
+	client := connect(t, addr)
+	obj := client.Object(namePrefix+appName, objPath)
+	var status string
+	if err := obj.Call(ifaceProps+".Get", 0, ifacePlayer, "PlaybackStatus").Store(&status); err != nil || status != "Stopped" {
+		t.Errorf("PlaybackStatus = %q, %v", status, err)
+	}
+	if err := obj.Call(ifacePlayer+".PlayPause", 0).Err; err != nil {
+		t.Error(err)
+	}
+	if c := <-commands; c.Action != PlayPause {
+		t.Errorf("command %v", c)
+	}
+	if err := obj.SetProperty(ifacePlayer+".Volume", dbus.MakeVariant(0.5)); err != nil {
+		t.Error(err)
+	}
+	if c := <-commands; c != (Command{Action: Volume, Volume: 50}) {
+		t.Errorf("command %v", c)
+	}
+	if err := obj.Call(ifacePlayer+".OpenUri", 0, "http://localhost").Err; err == nil {
+		t.Error("OpenUri is not supported")
+	}
+
+	err = client.AddMatchSignal(dbus.WithMatchObjectPath(objPath), dbus.WithMatchInterface(ifaceProps), dbus.WithMatchMember("PropertiesChanged"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signals := make(chan *dbus.Signal, 1)
+	client.Signal(signals)
+	s.Update(State{Status: Playing, Station: "Radio", Artist: "Artist", Title: "Title", ArtURL: "file:///tmp/cover.jpg"})
+	select {
+	case sig := <-signals:
+		changed := sig.Body[1].(map[string]dbus.Variant)
+		md, _ := changed["Metadata"].Value().(map[string]dbus.Variant)
+		if changed["PlaybackStatus"].Value() != "Playing" || !reflect.DeepEqual(md["xesam:artist"].Value(), []string{"Artist"}) ||
+			md["mpris:artUrl"].Value() != "file:///tmp/cover.jpg" {
+			t.Errorf("PropertiesChanged %v", sig.Body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no PropertiesChanged")
+	}
+}
+
+// fakeLogind owns the logind name on the bus, with the session of the app.
+type fakeLogind struct {
+	conn    *dbus.Conn
+	session dbus.ObjectPath
+}
+
+func newFakeLogind(t *testing.T, addr string) *fakeLogind {
+	t.Helper()
+	l := &fakeLogind{conn: connect(t, addr), session: "/org/freedesktop/login1/session/_32"}
+	err := l.conn.ExportMethodTable(map[string]any{
+		"GetSessionByPID": func(uint32) (dbus.ObjectPath, *dbus.Error) { return l.session, nil },
+	}, logindPath, logindManagerIface)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, err := l.conn.RequestName(logindName, dbus.NameFlagDoNotQueue); err != nil || res != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("RequestName = %v, %v", res, err)
+	}
+	return l
+}
+
+func TestWatchLock_bus(t *testing.T) {
+	addr := startBus(t)
+	t.Setenv("DBUS_SYSTEM_BUS_ADDRESS", addr)
+	logind := newFakeLogind(t, addr)
+
+	locked := make(chan bool, 1)
+	w, err := WatchLock(func(v bool) { locked <- v })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if w.path != logind.session {
+		t.Fatalf("session %s", w.path)
+	}
+
+	emit := func(name string, values ...any) {
+		t.Helper()
+		if err := logind.conn.Emit(logind.session, name, values...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the signals of other sessions and the other properties are ignored
+	if err := logind.conn.Emit("/org/freedesktop/login1/session/_1", logindSessionIface+".Unlock"); err != nil {
+		t.Fatal(err)
+	}
+	emit(ifaceProps+".PropertiesChanged", logindSessionIface, map[string]dbus.Variant{"IdleHint": dbus.MakeVariant(true)}, []string{})
+	emit(logindSessionIface + ".Lock")
+	emit(ifaceProps+".PropertiesChanged", logindSessionIface, map[string]dbus.Variant{"LockedHint": dbus.MakeVariant(false)}, []string{})
+	for _, want := range []bool{true, false} {
+		select {
+		case v := <-locked:
+			if v != want {
+				t.Errorf("locked %v, want %v", v, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no lock %v", want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Package netwatch detects the changes of the network, like switching Wi-Fi networks or docking,
+// after which the open connections are stale.
+package netwatch
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strings"
+)
+
+const procRoute = "/proc/net/route"
+
+// State describes the network: the IPv4 addresses of the interfaces that are up and, on Linux,
+// the interfaces and gateways of the default routes. The IPv6 addresses are left out, as the
+// temporary ones change regularly on the same network.
+// An empty State means there's no network.
+func State() (string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", err
+	}
+	var parts []string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			ipNet, ok := a.(*net.IPNet)
+			if !ok || ipNet.IP.To4() == nil || !ipNet.IP.IsGlobalUnicast() {
+				continue
+			}
+			parts = append(parts, iface.Name+"="+ipNet.String())
+		}
+	}
+	if f, err := os.Open(procRoute); err == nil {
+		defer f.Close()
+		parts = append(parts, defaultRoutes(f)...)
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, ","), nil
+}
+
+// defaultRoutes reads the default routes of the Linux routing table, as interface and hex gateway.
+func defaultRoutes(r io.Reader) []string {
+	var routes []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		// Iface Destination Gateway Flags ... Mask
+		if len(fields) < 8 || fields[1] != "00000000" || fields[7] != "00000000" {
+			continue
+		}
+		routes = append(routes, "default="+fields[0]+"/"+fields[2])
+	}
+	return routes
+}
@@ -0,0 +1,31 @@
+package netwatch
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDefaultRoutes(t *testing.T) {
+	table := `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+wlan0	00000000	0101A8C0	0003	0	0	600	00000000	0	0	0
+wlan0	0001A8C0	00000000	0001	0	0	600	00FFFFFF	0	0	0
+eth0	00000000	010200C0	0003	0	0	100	00000000	0	0	0
+`
+	got := defaultRoutes(strings.NewReader(table))
+	want := []string{"default=wlan0/0101A8C0", "default=eth0/010200C0"}
+	if !slices.Equal(got, want) {
+		t.Errorf("routes %v, want %v", got, want)
+	}
+}
+
+func TestState(t *testing.T) {
+	s1, err := State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, _ := State()
+	if s1 != s2 {
+		t.Errorf("state changed without a network change: %q %q", s1, s2)
+	}
+}
@@ -0,0 +1,58 @@
+// Package notify shows the desktop notifications with notify-send.
+package notify
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os/exec"
+	"strings"
+)
+
+const (
+	appName = "sonicradio"
+	// actionKey is printed by notify-send when the action of the notification is clicked
+	actionKey = "action"
+)
+
+// bin is the command showing the notifications, replaced by the tests.
+var bin = "notify-send"
+
+// Available tells if the desktop notifications can be shown.
+func Available() bool {
+	_, err := exec.LookPath(bin)
+	return err == nil
+}
+
+// Send shows the notification in the background, if available.
+func Send(body string) {
+	if !Available() {
+		return
+	}
+	go func() {
+		if err := exec.Command(bin, appName, body).Run(); err != nil {
+			slog.With("method", "notify.Send").Info(bin, "error", err)
+		}
+	}()
+}
+
+// SendAction shows the notification with an action, waiting until it is clicked, dismissed or ctx is
+// done, and tells if the action was clicked. The versions of notify-send without actions, before 0.7.9,
+// show the notification without it.
+func SendAction(ctx context.Context, body, action string) (bool, error) {
+	if !Available() {
+		return false, nil
+	}
+	out, err := exec.CommandContext(ctx, bin, "--wait", "--action="+actionKey+"="+action, appName, body).Output()
+	if ctx.Err() != nil {
+		return false, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return false, exec.Command(bin, appName, body).Run()
+	}
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(out)) == actionKey, nil
+}
@@ -0,0 +1,64 @@
+//go:build !windows
+
+package notify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeNotifySend replaces notify-send with the script, which gets the arguments in $@ and logs them.
+func fakeNotifySend(t *testing.T, script string) string {
+	t.Helper()
+	dir := t.TempDir()
+	log := filepath.Join(dir, "args")
+	path := filepath.Join(dir, "notify-send")
+	content := "#!/bin/sh\necho \"$@\" >> " + log + "\n" + script + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	prev := bin
+	bin = path
+	t.Cleanup(func() { bin = prev })
+	return log
+}
+
+func calls(t *testing.T, log string) []string {
+	t.Helper()
+	b, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(b)), "\n")
+}
+
+func TestSendAction(t *testing.T) {
+	log := fakeNotifySend(t, "echo action")
+	clicked, err := SendAction(context.Background(), "Tune into Jazz", "Tune in")
+	if err != nil || !clicked {
+		t.Fatalf("got clicked %v, %v, want the action clicked", clicked, err)
+	}
+	if got := calls(t, log); len(got) != 1 || !strings.HasPrefix(got[0], "--wait --action=action=Tune in sonicradio") {
+		t.Errorf("got calls %q", got)
+	}
+}
+
+func TestSendAction_dismissed(t *testing.T) {
+	fakeNotifySend(t, "")
+	if clicked, err := SendAction(context.Background(), "Tune into Jazz", "Tune in"); err != nil || clicked {
+		t.Errorf("got clicked %v, %v, want dismissed", clicked, err)
+	}
+}
+
+func TestSendAction_noActions(t *testing.T) {
+	log := fakeNotifySend(t, `case "$1" in --wait) echo "Unknown option --wait" >&2; exit 1;; esac`)
+	if clicked, err := SendAction(context.Background(), "Tune into Jazz", "Tune in"); err != nil || clicked {
+		t.Errorf("got clicked %v, %v, want shown without the action", clicked, err)
+	}
+	if got := calls(t, log); len(got) != 2 || got[1] != "sonicradio Tune into Jazz" {
+		t.Errorf("got calls %q, want shown again without the action", got)
+	}
+}
@@ -0,0 +1,93 @@
+// Package perf keeps the timings of the API requests, the renders and the player round-trips,
+// shown by the debug overlay to diagnose a sluggish app.
+package perf
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// the names of the timings recorded
+const (
+	API    = "api"
+	Render = "render"
+	Update = "update"
+	// IPC is followed by the player, e.g. "ipc mpv"
+	IPC = "ipc"
+)
+
+// window is the number of the last samples the percentiles are computed from.
+const window = 256
+
+// Stat sums up the timings of a name, the percentiles being over the last samples.
+type Stat struct {
+	Name  string
+	Count int
+	Last  time.Duration
+	P50   time.Duration
+	P95   time.Duration
+	Max   time.Duration
+}
+
+type samples struct {
+	last  [window]time.Duration
+	count int
+	max   time.Duration
+}
+
+var (
+	mtx    sync.Mutex
+	series = make(map[string]*samples)
+)
+
+// Record adds a timing of name.
+func Record(name string, d time.Duration) {
+	mtx.Lock()
+	defer mtx.Unlock()
+	s := series[name]
+	if s == nil {
+		s = new(samples)
+		series[name] = s
+	}
+	s.last[s.count%window] = d
+	s.count++
+	s.max = max(s.max, d)
+}
+
+// Since records the time elapsed since start, e.g. `defer perf.Since(perf.API, time.Now())`.
+func Since(name string, start time.Time) {
+	Record(name, time.Since(start))
+}
+
+// Stats returns the timings recorded, by name.
+func Stats() []Stat {
+	mtx.Lock()
+	defer mtx.Unlock()
+	res := make([]Stat, 0, len(series))
+	for name, s := range series {
+		n := min(s.count, window)
+		sorted := slices.Clone(s.last[:n])
+		slices.Sort(sorted)
+		res = append(res, Stat{
+			Name:  name,
+			Count: s.count,
+			Last:  s.last[(s.count-1)%window],
+			P50:   percentile(sorted, 50),
+			P95:   percentile(sorted, 95),
+			Max:   s.max,
+		})
+	}
+	slices.SortFunc(res, func(a, b Stat) int { return strings.Compare(a.Name, b.Name) })
+	return res
+}
+
+// percentile returns the nearest-rank percentile p of the sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(0, rank-1)]
+}
@@ -0,0 +1,54 @@
+package perf
+
+import (
+	"testing"
+	"time"
+)
+
+// reset clears the timings recorded by the other tests, or by the previous runs of -count.
+func reset() {
+	mtx.Lock()
+	defer mtx.Unlock()
+	series = make(map[string]*samples)
+}
+
+func Test_Stats(t *testing.T) {
+	reset()
+	for i := 1; i <= 100; i++ {
+		Record("test", time.Duration(i)*time.Millisecond)
+	}
+	Record("a test", time.Second)
+
+	stats := Stats()
+	var got *Stat
+	for i := range stats {
+		if stats[i].Name == "test" {
+			got = &stats[i]
+		}
+	}
+	if got == nil || stats[0].Name != "a test" {
+		t.Fatalf("got stats %+v, want them by name", stats)
+	}
+	want := Stat{Name: "test", Count: 100, Last: 100 * time.Millisecond, P50: 50 * time.Millisecond, P95: 95 * time.Millisecond, Max: 100 * time.Millisecond}
+	if *got != want {
+		t.Errorf("got %+v, want %+v", *got, want)
+	}
+}
+
+func Test_StatsWindow(t *testing.T) {
+	reset()
+	Record("window", time.Hour)
+	for range window {
+		Record("window", time.Millisecond)
+	}
+	for _, s := range Stats() {
+		if s.Name != "window" {
+			continue
+		}
+		if s.Count != window+1 || s.P95 != time.Millisecond || s.Max != time.Hour {
+			t.Errorf("got %+v, want the percentiles of the last %d samples and the max of all", s, window)
+		}
+		return
+	}
+	t.Fatal("missing the stat")
+}
@@ -0,0 +1,171 @@
+package player
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+// crossfadeSteps is the number of volume changes of a crossfade.
+const crossfadeSteps = 20
+
+// SetCrossfade sets the duration of the fade between the streams played, 0 to switch at once.
+func (p *Player) SetCrossfade(d time.Duration) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.crossfade = d
+}
+
+// canCrossfade tells if the next stream fades in over the playing one: a crossfade is set, the playback
+// is local, not attached to the daemon, and the player changes its volume while playing, which FFplay doesn't.
+func (p *Player) canCrossfade() bool {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	return p.crossfade > 0 && p.playing && p.newBackend != nil && p.remote == nil && p.delegate.GetType() != config.FFPlay
+}
+
+// playCrossfade plays the url on another backend, silent at first, and fades it in while the playing
+// one fades out, retiring it at the end. The other backend is the standby one with the gapless
+// switching, where the url may already be playing. It replaces the playing one at once, for the song
+// titles and the controls.
+func (p *Player) playCrossfade(url string) error {
+	next, preloaded := p.takeStandby(url)
+	if next == nil {
+		var err error
+		if next, err = p.newBackend(); err != nil {
+			return err
+		}
+		if _, err := next.SetVolume(0); err != nil {
+			closeBackend(next)
+			return err
+		}
+	}
+	if !preloaded {
+		if err := next.Play(url); err != nil {
+			p.retire(next)
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	p.mtx.Lock()
+	prev := p.delegate
+	p.delegate = next
+	p.playing = true
+	d := p.crossfade
+	p.fadeCancel, p.fadeDone = cancel, done
+	p.mtx.Unlock()
+
+	go func() {
+		defer close(done)
+		p.fade(ctx, prev, next, d)
+		p.retire(prev)
+	}()
+	return nil
+}
+
+// takeFadeIn returns the fade in of the stream played, once, 0 when the playback isn't local or FFplay
+// plays it.
+func (p *Player) takeFadeIn() time.Duration {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	d := p.fadeIn
+	p.fadeIn = 0
+	if p.remote != nil || p.delegate.GetType() == config.FFPlay {
+		return 0
+	}
+	return d
+}
+
+// playFadeIn plays the url silent at first, and raises the volume to the one of the player over d.
+func (p *Player) playFadeIn(url string, d time.Duration) error {
+	b := p.backend()
+	if _, err := b.SetVolume(0); err != nil {
+		return err
+	}
+	if err := b.Play(url); err != nil {
+		p.mtx.RLock()
+		vol := p.volume
+		p.mtx.RUnlock()
+		_, _ = b.SetVolume(vol)
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	p.mtx.Lock()
+	p.playing = true
+	p.fadeCancel, p.fadeDone = cancel, done
+	p.mtx.Unlock()
+	go func() {
+		defer close(done)
+		p.fade(ctx, nil, b, d)
+	}()
+	return nil
+}
+
+// fade raises the volume of next to the one of the player while lowering prev, if any, over d or until
+// ctx is done, next being at the volume of the player at the end.
+func (p *Player) fade(ctx context.Context, prev, next Backend, d time.Duration) {
+	log := slog.With("method", "Player.fade")
+	t := time.NewTicker(d / crossfadeSteps)
+	defer t.Stop()
+	for i := 1; i <= crossfadeSteps; i++ {
+		select {
+		case <-ctx.Done():
+			i = crossfadeSteps
+		case <-t.C:
+		}
+		p.mtx.RLock()
+		vol := p.volume
+		p.mtx.RUnlock()
+		in := vol * i / crossfadeSteps
+		if _, err := next.SetVolume(in); err != nil {
+			log.Error("fade in", "error", err)
+		}
+		if i == crossfadeSteps || prev == nil {
+			continue
+		}
+		if _, err := prev.SetVolume(vol - in); err != nil {
+			log.Error("fade out", "error", err)
+		}
+	}
+}
+
+// stopFade ends the crossfade in progress, if any, retiring the backend fading out.
+func (p *Player) stopFade() {
+	p.mtx.Lock()
+	cancel, done := p.fadeCancel, p.fadeDone
+	p.fadeCancel, p.fadeDone = nil, nil
+	p.mtx.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// fading tells if a crossfade is in progress, p.mtx must be held.
+func (p *Player) fading() bool {
+	if p.fadeDone == nil {
+		return false
+	}
+	select {
+	case <-p.fadeDone:
+		return false
+	default:
+		return true
+	}
+}
+
+func closeBackend(b Backend) {
+	log := slog.With("method", "player.closeBackend")
+	if err := b.Stop(); err != nil {
+		log.Error("stop", "error", err)
+	}
+	if err := b.Close(); err != nil {
+		log.Error("close", "error", err)
+	}
+}
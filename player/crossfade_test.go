@@ -0,0 +1,180 @@
+package player
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/player/model"
+)
+
+type fakeBackend struct {
+	mtx     sync.Mutex
+	typ     config.PlayerType
+	url     string
+	plays   int
+	volumes []int
+	closed  bool
+}
+
+func (b *fakeBackend) GetType() config.PlayerType { return b.typ }
+
+func (b *fakeBackend) Play(url string) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.url = url
+	b.plays++
+	return nil
+}
+
+func (b *fakeBackend) Pause(value bool) error { return nil }
+
+func (b *fakeBackend) Stop() error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.url = ""
+	return nil
+}
+
+func (b *fakeBackend) SetVolume(value int) (int, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.volumes = append(b.volumes, value)
+	return value, nil
+}
+
+func (b *fakeBackend) Metadata() *model.Metadata { return nil }
+
+func (b *fakeBackend) Seek(amtSec int) *model.Metadata { return nil }
+
+func (b *fakeBackend) Close() error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.closed = true
+	return nil
+}
+
+func (b *fakeBackend) state() (string, []int, bool) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.url, append([]int(nil), b.volumes...), b.closed
+}
+
+func newFadePlayer(typ config.PlayerType) (*Player, *[]*fakeBackend) {
+	first := &fakeBackend{typ: typ}
+	backends := []*fakeBackend{first}
+	p := NewWithBackend(first)
+	p.volume = 80
+	p.crossfade = 20 * time.Millisecond
+	p.newBackend = func() (Backend, error) {
+		b := &fakeBackend{typ: typ}
+		backends = append(backends, b)
+		return b, nil
+	}
+	return p, &backends
+}
+
+func TestPlayer_crossfade(t *testing.T) {
+	p, backends := newFadePlayer(config.Mpv)
+	if err := p.Play("http://one"); err != nil {
+		t.Fatal(err)
+	}
+	if len(*backends) != 1 {
+		t.Fatal("expected the first station played without a fade")
+	}
+	if err := p.Play("http://two"); err != nil {
+		t.Fatal(err)
+	}
+	if len(*backends) != 2 || p.backend() != (*backends)[1] {
+		t.Fatal("expected the next station played by another backend")
+	}
+	prev, next := (*backends)[0], (*backends)[1]
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, _, closed := prev.state(); closed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the previous backend closed after the fade")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	url, volumes, _ := next.state()
+	if url != "http://two" || volumes[0] != 0 || volumes[len(volumes)-1] != 80 {
+		t.Errorf("got next %s with volumes %v, want faded in from 0 to 80", url, volumes)
+	}
+	_, volumes, _ = prev.state()
+	for i := 1; i < len(volumes); i++ {
+		if volumes[i] > volumes[i-1] {
+			t.Fatalf("got previous volumes %v, want fading out", volumes)
+		}
+	}
+
+	// switching during a fade ends it, the stream faded out being closed at once
+	p.SetCrossfade(time.Hour)
+	if err := p.Play("http://three"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if _, volumes, closed := next.state(); !closed || volumes[len(volumes)-1] > 80 {
+		t.Errorf("expected the faded out backend closed on stop, volumes %v", volumes)
+	}
+	if _, volumes, _ := (*backends)[2].state(); volumes[len(volumes)-1] != 80 {
+		t.Errorf("got volumes %v, want the stopped fade at the full volume", volumes)
+	}
+}
+
+func TestPlayer_noCrossfade(t *testing.T) {
+	p, backends := newFadePlayer(config.FFPlay)
+	_ = p.Play("http://one")
+	_ = p.Play("http://two")
+	if len(*backends) != 1 {
+		t.Error("expected no crossfade with FFplay, whose volume is set at the start")
+	}
+
+	p, backends = newFadePlayer(config.Mpv)
+	p.SetCrossfade(0)
+	_ = p.Play("http://one")
+	_ = p.Play("http://two")
+	if len(*backends) != 1 {
+		t.Error("expected no crossfade when disabled")
+	}
+}
+
+func TestPlayer_fadeIn(t *testing.T) {
+	p, backends := newFadePlayer(config.Mpv)
+	p.fadeIn = 20 * time.Millisecond
+	if err := p.Play("http://one"); err != nil {
+		t.Fatal(err)
+	}
+	b := (*backends)[0]
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, volumes, _ := b.state(); len(volumes) > 0 && volumes[len(volumes)-1] == 80 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the first stream faded in to the volume")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if _, volumes, _ := b.state(); volumes[0] != 0 || len(volumes) < 3 {
+		t.Errorf("got volumes %v, want raised from 0", volumes)
+	}
+
+	p.SetCrossfade(0)
+	_ = p.Play("http://two")
+	if _, volumes, _ := b.state(); volumes[len(volumes)-1] != 80 {
+		t.Errorf("got volumes %v, want the next stream played at the volume", volumes)
+	}
+
+	p, backends = newFadePlayer(config.FFPlay)
+	p.fadeIn = time.Hour
+	_ = p.Play("http://one")
+	if _, volumes, _ := (*backends)[0].state(); len(volumes) != 0 {
+		t.Errorf("got volumes %v, want no fade in with FFplay", volumes)
+	}
+}
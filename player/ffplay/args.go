@@ -0,0 +1,47 @@
+package ffplay
+
+import (
+	"fmt"
+	"strings"
+)
+
+// reservedOpts are the ffplay options the player relies on, which the extra args can't change, true for
+// the ones taking a value: the verbose log the song titles are read from, the volume, the window kept
+// off under the UI and the exit at the end of the stream.
+var reservedOpts = map[string]bool{
+	"loglevel": true,
+	"v":        true,
+	"volume":   true,
+	"i":        true,
+	"nodisp":   false,
+	"autoexit": false,
+}
+
+// ValidArgs returns the extra args which are ffplay options, -name followed by its value if it takes
+// one, and don't change a reserved option, with an error listing the ones left out.
+func ValidArgs(args []string) ([]string, error) {
+	var valid, invalid []string
+	for i := 0; i < len(args); i++ {
+		a := strings.TrimSpace(args[i])
+		name, ok := strings.CutPrefix(a, "-")
+		takesValue, reserved := reservedOpts[name]
+		switch {
+		case a == "":
+			continue
+		case !ok && len(valid) == 0:
+			invalid = append(invalid, fmt.Sprintf("%q is not an option", a))
+		case ok && reserved:
+			if takesValue && i+1 < len(args) {
+				i++
+				a += " " + args[i]
+			}
+			invalid = append(invalid, fmt.Sprintf("%q changes an option the player relies on", a))
+		default:
+			valid = append(valid, a)
+		}
+	}
+	if len(invalid) > 0 {
+		return valid, fmt.Errorf("invalid ffplay args: %s", strings.Join(invalid, ", "))
+	}
+	return valid, nil
+}
@@ -0,0 +1,23 @@
+package ffplay
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidArgs(t *testing.T) {
+	args := []string{"stray", "-af", "loudnorm", "-loglevel", "quiet", "", "-nodisp", "-infbuf"}
+	valid, err := ValidArgs(args)
+	if want := []string{"-af", "loudnorm", "-infbuf"}; !slices.Equal(valid, want) {
+		t.Errorf("valid %q, want %q", valid, want)
+	}
+	if err == nil {
+		t.Fatal("expected the invalid args reported")
+	}
+	for _, a := range []string{`"stray"`, `"-loglevel quiet"`, `"-nodisp"`} {
+		if !strings.Contains(err.Error(), a) {
+			t.Errorf("expected %s in %v", a, err)
+		}
+	}
+}
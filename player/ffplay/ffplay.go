@@ -40,16 +40,29 @@ var (
 )
 
 type FFPlay struct {
-	url     string
-	playing *exec.Cmd
+	bin       string
+	extraArgs []string
+	url       string
+	playing   *exec.Cmd
 
 	pt     *playerutils.PlaybackTime
 	volume int
 }
 
-func NewFFPlay(ctx context.Context) (*FFPlay, error) {
+// NewFFPlay returns the player running bin, ffplay of the PATH if empty, with the extra args before the
+// url of the stream, the invalid ones being left out, see ValidArgs.
+func NewFFPlay(ctx context.Context, bin string, extraArgs []string) (*FFPlay, error) {
+	extra, err := ValidArgs(extraArgs)
+	if err != nil {
+		slog.With("method", "NewFFPlay").Warn("ffplay extra args", "error", err)
+	}
+	if bin == "" {
+		bin = GetBaseCmd()
+	}
 	return &FFPlay{
-		pt: &playerutils.PlaybackTime{},
+		bin:       bin,
+		extraArgs: extra,
+		pt:        &playerutils.PlaybackTime{},
 	}, nil
 }
 
@@ -74,8 +87,9 @@ func (f *FFPlay) play(url string) error {
 
 	args := slices.Clone(baseArgs)
 	args = append(args, fmt.Sprintf(volArg, f.volume))
+	args = append(args, f.extraArgs...)
 	args = append(args, url)
-	cmd := exec.Command(GetBaseCmd(), args...)
+	cmd := exec.Command(f.bin, args...)
 	if errors.Is(cmd.Err, exec.ErrDot) {
 		cmd.Err = nil
 	} else if cmd.Err != nil {
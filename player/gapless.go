@@ -0,0 +1,116 @@
+package player
+
+import (
+	"log/slog"
+)
+
+// startStandby starts the backend the next stations are preloaded on, muted, for the gapless switching.
+// Without it, the stations are switched on the playing backend.
+func (p *Player) startStandby() {
+	b, err := p.newBackend()
+	if err == nil {
+		_, err = b.SetVolume(0)
+	}
+	if err != nil {
+		slog.With("method", "Player.startStandby").Error("gapless switching unavailable", "error", err)
+		if b != nil {
+			closeBackend(b)
+		}
+		return
+	}
+	p.standbyMtx.Lock()
+	defer p.standbyMtx.Unlock()
+	p.gapless = true
+	p.standby = b
+}
+
+// Preload plays the url muted on the standby backend, so playing it next only swaps which backend is
+// heard. It does nothing without the gapless switching, or when attached to the daemon.
+func (p *Player) Preload(url string) error {
+	if p.remote != nil {
+		return nil
+	}
+	p.standbyMtx.Lock()
+	defer p.standbyMtx.Unlock()
+	if p.standby == nil || p.standbyURL == url {
+		return nil
+	}
+	p.standbyURL = ""
+	if err := p.standby.Play(url); err != nil {
+		return err
+	}
+	p.standbyURL = url
+	return nil
+}
+
+func (p *Player) hasStandby() bool {
+	p.standbyMtx.Lock()
+	defer p.standbyMtx.Unlock()
+	return p.standby != nil && p.remote == nil
+}
+
+// takeStandby removes the standby backend, nil if none, telling if the url is preloaded on it.
+func (p *Player) takeStandby(url string) (Backend, bool) {
+	p.standbyMtx.Lock()
+	defer p.standbyMtx.Unlock()
+	b, preloaded := p.standby, p.standbyURL == url
+	p.standby, p.standbyURL = nil, ""
+	return b, b != nil && preloaded
+}
+
+// retire stops the backend no longer heard, which becomes the standby one with the gapless switching,
+// or is closed.
+func (p *Player) retire(b Backend) {
+	log := slog.With("method", "Player.retire")
+	p.standbyMtx.Lock()
+	defer p.standbyMtx.Unlock()
+	if !p.gapless || p.standby != nil {
+		closeBackend(b)
+		return
+	}
+	if err := b.Stop(); err != nil {
+		log.Error("stop", "error", err)
+	}
+	if _, err := b.SetVolume(0); err != nil {
+		log.Error("mute", "error", err)
+		closeBackend(b)
+		return
+	}
+	p.standby = b
+}
+
+// playStandby plays the url on the standby backend, where it may already be playing muted, and
+// unmutes it, the playing backend becoming the standby one.
+func (p *Player) playStandby(url string) error {
+	next, preloaded := p.takeStandby(url)
+	if !preloaded {
+		if err := next.Play(url); err != nil {
+			p.retire(next)
+			return err
+		}
+	}
+	p.mtx.RLock()
+	vol := p.volume
+	p.mtx.RUnlock()
+	if _, err := next.SetVolume(vol); err != nil {
+		p.retire(next)
+		return err
+	}
+
+	p.mtx.Lock()
+	prev := p.delegate
+	p.delegate = next
+	p.playing = true
+	p.mtx.Unlock()
+	p.retire(prev)
+	return nil
+}
+
+func (p *Player) closeStandby() {
+	p.standbyMtx.Lock()
+	defer p.standbyMtx.Unlock()
+	if p.standby != nil {
+		closeBackend(p.standby)
+	}
+	p.gapless, p.standby, p.standbyURL = false, nil, ""
+}
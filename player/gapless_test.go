@@ -0,0 +1,64 @@
+package player
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+func TestPlayer_gapless(t *testing.T) {
+	p, backends := newFadePlayer(config.Mpv)
+	p.SetCrossfade(0)
+	first, standby := (*backends)[0], &fakeBackend{typ: config.Mpv}
+	p.gapless, p.standby = true, standby
+
+	if err := p.Play("http://one"); err != nil {
+		t.Fatal(err)
+	}
+	if p.backend() != standby || p.standby != first {
+		t.Fatal("expected the station played on the standby backend, the playing one on standby")
+	}
+	if url, volumes, _ := first.state(); url != "" || volumes[len(volumes)-1] != 0 {
+		t.Errorf("got standby %q at volumes %v, want stopped and muted", url, volumes)
+	}
+
+	if err := p.Preload("http://two"); err != nil {
+		t.Fatal(err)
+	}
+	if url, volumes, _ := first.state(); url != "http://two" || volumes[len(volumes)-1] != 0 {
+		t.Errorf("got standby %q at volumes %v, want the preloaded station muted", url, volumes)
+	}
+	if err := p.Play("http://two"); err != nil {
+		t.Fatal(err)
+	}
+	if p.backend() != first || p.standby != standby {
+		t.Fatal("expected the backends swapped")
+	}
+	if url, volumes, _ := first.state(); url != "http://two" || first.plays != 1 || volumes[len(volumes)-1] != 80 {
+		t.Errorf("got %q played %d times at volumes %v, want the preloaded station unmuted", url, first.plays, volumes)
+	}
+
+	// the crossfade fades in the preloaded station, the one faded out going on standby
+	_ = p.Preload("http://three")
+	p.SetCrossfade(20 * time.Millisecond)
+	if err := p.Play("http://three"); err != nil {
+		t.Fatal(err)
+	}
+	p.stopFade()
+	if len(*backends) != 1 || p.backend() != standby || p.standby != first {
+		t.Fatal("expected the crossfade on the standby backend")
+	}
+	if _, _, closed := first.state(); closed {
+		t.Error("expected the backend faded out kept on standby")
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []*fakeBackend{first, standby} {
+		if _, _, closed := b.state(); !closed {
+			t.Error("expected both backends closed")
+		}
+	}
+}
@@ -4,4 +4,29 @@ type Metadata struct {
 	Title           string
 	PlaybackTimeSec *int64
 	Err             error
+	// BufferSec is the duration of the stream buffered ahead, nil if the player doesn't report it
+	BufferSec *float64
+	// Stream are the audio parameters of the stream playing, nil if the player doesn't report them
+	Stream *StreamInfo
+}
+
+// StreamInfo are the audio parameters of the stream actually playing, which may differ from the ones
+// listed by radio-browser. The zero values are unknown.
+type StreamInfo struct {
+	Codec       string `json:"codec,omitempty"`
+	BitrateKbps int    `json:"bitrateKbps,omitempty"`
+	SampleRate  int    `json:"sampleRate,omitempty"`
+	Channels    int    `json:"channels,omitempty"`
+}
+
+// State is the playback of the daemon, restored by the app when it attaches.
+type State struct {
+	StationUuid string `json:"stationUuid,omitempty"`
+	StationName string `json:"stationName,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Playing     bool   `json:"playing"`
+	Paused      bool   `json:"paused"`
+	Volume      int    `json:"volume"`
+	// Pid is the process id of the daemon, whose children play the stream
+	Pid int `json:"pid,omitempty"`
 }
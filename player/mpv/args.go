@@ -0,0 +1,44 @@
+package mpv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// reservedOpts are the mpv options the player relies on, which the extra args can't change: the IPC
+// socket it's driven by, the idle mode keeping it running between the stations and the terminal
+// and video kept off under the UI.
+var reservedOpts = map[string]bool{
+	"idle":             true,
+	"terminal":         true,
+	"video":            true,
+	"vid":              true,
+	"input-ipc-server": true,
+	"input-ipc-client": true,
+	"config":           true,
+}
+
+// ValidArgs returns the extra args which are mpv options, --name or --name=value, and don't change a
+// reserved option, with an error listing the ones left out.
+func ValidArgs(args []string) ([]string, error) {
+	var valid, invalid []string
+	for _, a := range args {
+		a = strings.TrimSpace(a)
+		name, ok := strings.CutPrefix(a, "--")
+		name, _, _ = strings.Cut(name, "=")
+		switch {
+		case a == "":
+			continue
+		case !ok || name == "":
+			invalid = append(invalid, fmt.Sprintf("%q is not an option", a))
+		case reservedOpts[strings.TrimPrefix(name, "no-")]:
+			invalid = append(invalid, fmt.Sprintf("%q changes an option the player relies on", a))
+		default:
+			valid = append(valid, a)
+		}
+	}
+	if len(invalid) > 0 {
+		return valid, fmt.Errorf("invalid mpv args: %s", strings.Join(invalid, ", "))
+	}
+	return valid, nil
+}
@@ -0,0 +1,27 @@
+package mpv
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidArgs(t *testing.T) {
+	args := []string{"--cache-secs=30", " --af=loudnorm ", "", "--no-idle", "--input-ipc-server=/tmp/x", "http://stream", "--", "--ytdl-format=bestaudio"}
+	valid, err := ValidArgs(args)
+	if want := []string{"--cache-secs=30", "--af=loudnorm", "--ytdl-format=bestaudio"}; !slices.Equal(valid, want) {
+		t.Errorf("valid %q, want %q", valid, want)
+	}
+	if err == nil {
+		t.Fatal("expected the invalid args reported")
+	}
+	for _, a := range []string{"--no-idle", "--input-ipc-server=/tmp/x", "http://stream"} {
+		if !strings.Contains(err.Error(), a) {
+			t.Errorf("expected %q in %v", a, err)
+		}
+	}
+
+	if _, err := ValidArgs([]string{"--volume-max=150"}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -13,9 +13,11 @@ import (
 	"os/exec"
 	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/perf"
 	"github.com/dancnb/sonicradio/player/model"
 	playerutils "github.com/dancnb/sonicradio/player/utils"
 )
@@ -42,6 +44,10 @@ const (
 	metadata
 	mediaTitle
 	playbackTime
+	cacheSec
+	audioCodec
+	audioBitrate
+	audioParams
 	seek
 	quit
 )
@@ -55,10 +61,25 @@ var ipcCmds = map[ipcCmd]string{
 	metadata:     `["get_property_string", "metadata"]`,
 	mediaTitle:   `["get_property", "media-title"]`,
 	playbackTime: `["get_property", "playback-time"]`,
+	cacheSec:     `["get_property", "demuxer-cache-duration"]`,
+	audioCodec:   `["get_property", "audio-codec-name"]`,
+	audioBitrate: `["get_property", "audio-bitrate"]`,
+	audioParams:  `["get_property", "audio-params"]`,
 	seek:         `["seek", %d]`,
 	quit:         `[ "quit"]`,
 }
 
+var instances atomic.Int32
+
+// sockPath is unique for every mpv started by the process, as each playback session has its own.
+func sockPath() string {
+	fp := fmt.Sprintf(sockFile, os.Getpid())
+	if n := instances.Add(1) - 1; n > 0 {
+		fp += fmt.Sprintf(".%d", n)
+	}
+	return fp
+}
+
 type MpvSocket struct {
 	sockFile string
 	conn     net.Conn
@@ -66,12 +87,17 @@ type MpvSocket struct {
 	cmd *exec.Cmd
 }
 
-func NewMPVSocket(ctx context.Context) (*MpvSocket, error) {
+// NewMPVSocket starts bin, mpv of the PATH if empty, with the extra args appended to its base ones, the
+// invalid ones being left out, see ValidArgs.
+func NewMPVSocket(ctx context.Context, bin string, extraArgs []string) (*MpvSocket, error) {
 	mpv := &MpvSocket{
-		sockFile: fmt.Sprintf(sockFile, os.Getpid()),
+		sockFile: sockPath(),
 	}
 
-	cmd, err := mpvCmd(ctx, mpv.sockFile)
+	if bin == "" {
+		bin = GetBaseCmd()
+	}
+	cmd, err := mpvCmd(ctx, bin, mpv.sockFile, extraArgs)
 	if err != nil {
 		return nil, err
 	}
@@ -104,18 +130,24 @@ loop:
 	return mpv, nil
 }
 
-func mpvCmd(ctx context.Context, sockFile string) (*exec.Cmd, error) {
+func mpvCmd(ctx context.Context, bin, sockFile string, extraArgs []string) (*exec.Cmd, error) {
 	log := slog.With("method", "mpvCmd")
 	args := slices.Clone(baseSockArgs)
+	extra, err := ValidArgs(extraArgs)
+	if err != nil {
+		log.Warn("mpv extra args", "error", err)
+	}
+	args = append(args, extra...)
 	args = append(args, fmt.Sprintf(ipcArg, sockFile))
-	cmd := exec.CommandContext(ctx, GetBaseCmd(), args...)
+	log.Info("mpv cmd", "args", args)
+	cmd := exec.CommandContext(ctx, bin, args...)
 	if errors.Is(cmd.Err, exec.ErrDot) {
 		cmd.Err = nil
 	} else if cmd.Err != nil {
 		log.Error("mpv cmd error", "error", cmd.Err.Error())
 		return nil, cmd.Err
 	}
-	err := cmd.Start()
+	err = cmd.Start()
 	if err != nil {
 		log.Error("mpv cmd start", "error", err)
 		return nil, err
@@ -155,7 +187,7 @@ func (mpv *MpvSocket) Play(url string) error {
 }
 
 func (mpv *MpvSocket) Metadata() *model.Metadata {
-	m := mpv.getMetadata()
+	m, icy := mpv.getMetadata()
 	// TODO? alternate title
 	// if m.Err != nil || len(m.Title) == 0 {
 	// 	m = mpv.getMediaTitle()
@@ -171,6 +203,14 @@ func (mpv *MpvSocket) Metadata() *model.Metadata {
 			m.PlaybackTimeSec = &intV
 		}
 	}
+	res, _ = mpv.ipcRequest(ipcCmds[cacheSec])
+	if resF, ok := res.(float64); ok {
+		m.BufferSec = &resF
+	}
+	codec, _ := mpv.ipcRequest(ipcCmds[audioCodec])
+	bitrate, _ := mpv.ipcRequest(ipcCmds[audioBitrate])
+	params, _ := mpv.ipcRequest(ipcCmds[audioParams])
+	m.Stream = streamInfo(codec, bitrate, params, icy)
 	return &m
 }
 
@@ -196,25 +236,26 @@ type icyMetadata struct {
 	Title       string `json:"icy-title"`
 }
 
-func (mpv *MpvSocket) getMetadata() model.Metadata {
+// getMetadata returns the song title, with the ICY headers of the stream.
+func (mpv *MpvSocket) getMetadata() (model.Metadata, icyMetadata) {
 	cmd := ipcCmds[metadata]
 	res, err := mpv.ipcRequest(cmd)
 	if err != nil {
-		return model.Metadata{Err: err}
+		return model.Metadata{Err: err}, icyMetadata{}
 	}
 	resS, ok := res.(string)
 	if !ok {
-		return model.Metadata{Err: ErrNoMetadata}
+		return model.Metadata{Err: ErrNoMetadata}, icyMetadata{}
 	}
 	if len(resS) == 0 {
-		return model.Metadata{Err: ErrNoMetadata}
+		return model.Metadata{Err: ErrNoMetadata}, icyMetadata{}
 	}
 	var m icyMetadata
 	err = json.Unmarshal([]byte(resS), &m)
 	if err != nil {
-		return model.Metadata{Err: fmt.Errorf("metadata unmarhsal err: %v", err.Error())}
+		return model.Metadata{Err: fmt.Errorf("metadata unmarhsal err: %v", err.Error())}, icyMetadata{}
 	}
-	return model.Metadata{Title: strings.TrimSpace(m.Title)}
+	return model.Metadata{Title: strings.TrimSpace(m.Title)}, m
 }
 
 func (mpv *MpvSocket) getMediaTitle() model.Metadata {
@@ -285,6 +326,7 @@ func (mpv *MpvSocket) ipcRequest(command string) (any, error) {
 	id := rand.IntN(999) + 1
 	cmd := fmt.Sprintf("{ \"command\": %s, \"request_id\": %d }\n", command, id)
 	log.Info("ipc", "cmd", cmd)
+	defer perf.Since(perf.IPC+" mpv", time.Now())
 
 	mpv.conn.SetDeadline(time.Now().Add(config.MpvIpcConnTimeout))
 	_, err := mpv.conn.Write([]byte(cmd))
@@ -0,0 +1,50 @@
+package mpv
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dancnb/sonicradio/player/model"
+)
+
+// streamInfo returns the audio parameters from the audio-codec-name, audio-bitrate and audio-params
+// properties of mpv, the ICY headers of the server filling the ones mpv hasn't measured yet. It's nil
+// when none is known.
+func streamInfo(codec, bitrate, params any, icy icyMetadata) *model.StreamInfo {
+	var s model.StreamInfo
+	if v, ok := codec.(string); ok {
+		s.Codec = strings.ToUpper(v)
+	}
+	if v, ok := bitrate.(float64); ok && v > 0 {
+		s.BitrateKbps = int(math.Round(v / 1000))
+	}
+	if p, ok := params.(map[string]any); ok {
+		if v, ok := p["samplerate"].(float64); ok {
+			s.SampleRate = int(v)
+		}
+		if v, ok := p["channel-count"].(float64); ok {
+			s.Channels = int(v)
+		}
+	}
+	if s.BitrateKbps == 0 {
+		s.BitrateKbps = icyInt(icy.BitRate)
+	}
+	if s.SampleRate == 0 {
+		s.SampleRate = icyInt(icy.Sr)
+	}
+	if s == (model.StreamInfo{}) {
+		return nil
+	}
+	return &s
+}
+
+// icyInt returns the first number of an ICY header, some servers listing one per variant, 0 if none.
+func icyInt(v string) int {
+	first, _, _ := strings.Cut(v, ",")
+	n, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
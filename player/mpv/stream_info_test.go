@@ -0,0 +1,33 @@
+package mpv
+
+import (
+	"testing"
+
+	"github.com/dancnb/sonicradio/player/model"
+)
+
+func Test_streamInfo(t *testing.T) {
+	params := map[string]any{"samplerate": float64(48000), "channel-count": float64(2), "format": "floatp"}
+	tests := []struct {
+		name    string
+		codec   any
+		bitrate any
+		params  any
+		icy     icyMetadata
+		want    *model.StreamInfo
+	}{
+		{"measured", "mp3", float64(127_600), params, icyMetadata{BitRate: "192", Sr: "44100"},
+			&model.StreamInfo{Codec: "MP3", BitrateKbps: 128, SampleRate: 48000, Channels: 2}},
+		{"icy headers before measured", "aac", nil, nil, icyMetadata{BitRate: "64,64", Sr: "44100"},
+			&model.StreamInfo{Codec: "AAC", BitrateKbps: 64, SampleRate: 44100}},
+		{"unknown", nil, nil, nil, icyMetadata{BitRate: "n/a"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := streamInfo(tt.codec, tt.bitrate, tt.params, tt.icy)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,25 +1,56 @@
 package player
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"os"
 	"os/exec"
+	"sync"
+	"time"
 
+	"github.com/dancnb/sonicradio/cast"
 	"github.com/dancnb/sonicradio/config"
 	"github.com/dancnb/sonicradio/player/ffplay"
 	"github.com/dancnb/sonicradio/player/model"
 	"github.com/dancnb/sonicradio/player/mplayer"
 	"github.com/dancnb/sonicradio/player/mpv"
+	"github.com/dancnb/sonicradio/player/remote"
 	"github.com/dancnb/sonicradio/player/vlc"
 )
 
 type Player struct {
-	delegate  backendPlayer
+	// mtx guards the delegate, replaced by the one fading in on a crossfade, and the playback state
+	mtx       sync.RWMutex
+	delegate  Backend
 	available map[config.PlayerType]struct{}
+	// remote is set when the playback runs in the daemon
+	remote *remote.Client
+	// output is set when the playback is cast to a renderer on the network
+	output cast.Renderer
+
+	// newBackend starts another backend for the crossfades, nil if not available
+	newBackend func() (Backend, error)
+	crossfade  time.Duration
+	fadeIn     time.Duration
+	volume     int
+	maxVolume  int
+	playing    bool
+	// fadeCancel ends the crossfade in progress, fadeDone being closed once the stream faded out is closed
+	fadeCancel context.CancelFunc
+	fadeDone   chan struct{}
+
+	// standbyMtx guards the standby backend, kept muted for the gapless switching, and the url preloaded on it
+	standbyMtx sync.Mutex
+	gapless    bool
+	standby    Backend
+	standbyURL string
 }
 
-type backendPlayer interface {
+// Backend plays the streams, like mpv, or a fake one in the tests.
+type Backend interface {
 	GetType() config.PlayerType
 	Play(url string) error
 	Pause(value bool) error
@@ -37,49 +68,114 @@ func NewPlayer(ctx context.Context, cfg *config.Value) (*Player, error) {
 		return nil, err
 	}
 
-	vol := cfg.GetVolume()
-	switch cfg.Player {
+	p.maxVolume = cfg.MaxVolume
+	vol := p.clampVolume(cfg.GetVolume())
+	p.delegate, err = newBackend(ctx, cfg.Player, cfg, vol)
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.delegate.SetVolume(vol)
+	if err != nil {
+		return nil, err
+	}
+	p.volume = vol
+	p.crossfade = cfg.Crossfade()
+	p.fadeIn = cfg.StartupFade()
+	playerType := cfg.Player
+	p.newBackend = func() (Backend, error) {
+		return newBackend(ctx, playerType, cfg, 0)
+	}
+	if cfg.Gapless && playerType == config.Mpv {
+		p.startStandby()
+	}
+
+	return p, nil
+}
+
+func newBackend(ctx context.Context, t config.PlayerType, cfg *config.Value, vol int) (Backend, error) {
+	switch t {
 	case config.Mpv:
-		mpvPlayer, err := mpv.NewMPVSocket(ctx)
-		if err != nil {
-			return nil, err
-		}
-		p.delegate = mpvPlayer
+		return mpv.NewMPVSocket(ctx, cfg.MpvPath, cfg.MpvArgs)
 	case config.FFPlay:
-		ffplayPlayer, err := ffplay.NewFFPlay(ctx)
-		if err != nil {
-			return nil, err
-		}
-		p.delegate = ffplayPlayer
+		return ffplay.NewFFPlay(ctx, cfg.FFplayPath, cfg.FFplayArgs)
 	case config.Vlc:
-		vlcPlayer, err := vlc.NewVlc(ctx)
-		if err != nil {
-			return nil, err
-		}
-		p.delegate = vlcPlayer
+		return vlc.NewVlc(ctx)
 	case config.MPlayer:
-		mplayer, err := mplayer.New(ctx, vol)
-		if err != nil {
-			return nil, err
-		}
-		p.delegate = mplayer
+		return mplayer.New(ctx, vol)
 	}
+	return nil, fmt.Errorf("unknown player %v", t)
+}
 
-	_, err = p.delegate.SetVolume(clampVolume(vol))
+// NewWithBackend returns a player playing the streams with b, e.g. a fake one for driving the UI in the tests.
+func NewWithBackend(b Backend) *Player {
+	return &Player{
+		delegate:  b,
+		available: map[config.PlayerType]struct{}{b.GetType(): {}},
+	}
+}
+
+// Attach connects to the daemon listening at the socket path, whose player keeps playing after the app quits.
+func Attach(path string) (*Player, error) {
+	c, err := remote.Dial(path)
 	if err != nil {
 		return nil, err
 	}
+	return &Player{
+		delegate:  c,
+		available: map[config.PlayerType]struct{}{c.GetType(): {}},
+		remote:    c,
+	}, nil
+}
 
-	return p, nil
+// Attached tells if the playback runs in the daemon.
+func (p *Player) Attached() bool {
+	return p.remote != nil
 }
 
-var errNoPlayerAvailable = errors.New("No available player found. Must have at least one of the following in PATH: mpv, ffplay, vlc.")
+// State returns the playback of the daemon, nil if not attached.
+func (p *Player) State() *model.State {
+	if p.remote == nil {
+		return nil
+	}
+	s, err := p.remote.State()
+	if err != nil {
+		slog.With("method", "Player.State").Error("", "error", err)
+		return nil
+	}
+	return &s
+}
+
+// Pid returns the id of the process whose children play the stream, the daemon if attached.
+func (p *Player) Pid() int {
+	if s := p.State(); s != nil && s.Pid != 0 {
+		return s.Pid
+	}
+	return os.Getpid()
+}
+
+// SetStation records the playing station in the daemon, if attached.
+func (p *Player) SetStation(uuid, name string) error {
+	if p.remote == nil {
+		return nil
+	}
+	return p.remote.SetStation(uuid, name)
+}
+
+// Shutdown stops the playback of the daemon and the daemon itself, if attached.
+func (p *Player) Shutdown() error {
+	if p.remote == nil {
+		return nil
+	}
+	return p.remote.Quit()
+}
+
+var errNoPlayerAvailable = errors.New("No available player found. Must have at least one of the following in PATH: mpv, ffplay, vlc, or set mpvPath or ffplayPath in the config file.")
 
 func (p *Player) checkPlayerType(cfg *config.Value) error {
 	p.available = make(map[config.PlayerType]struct{}, len(config.Players))
 	var firstAvailable *config.PlayerType
 	for _, v := range config.Players {
-		if ok := checkAvailablePlayer(v); !ok {
+		if ok := checkAvailablePlayer(v, cfg.PlayerPath(v)); !ok {
 			continue
 		}
 		if firstAvailable == nil {
@@ -104,12 +200,14 @@ var baseCmds = map[config.PlayerType]func() string{
 	config.MPlayer: mplayer.GetBaseCmd,
 }
 
-func checkAvailablePlayer(p config.PlayerType) bool {
+// checkAvailablePlayer reports whether the player is found at the path set in the config, or in the PATH
+// without one.
+func checkAvailablePlayer(p config.PlayerType, configured string) bool {
 	baseCmdFn, ok := baseCmds[p]
 	if !ok {
 		return false
 	}
-	baseCmd := baseCmdFn()
+	baseCmd := cmp.Or(configured, baseCmdFn())
 	path, err := exec.LookPath(baseCmd)
 	slog.Info("checkAvailablePlayer", "cmd", baseCmd, "path", path, "err", err)
 	if err != nil && !errors.Is(err, exec.ErrDot) {
@@ -128,39 +226,141 @@ func (p *Player) PlayerTypes() []config.PlayerType {
 	return res
 }
 
+func (p *Player) GetType() config.PlayerType {
+	return p.backend().GetType()
+}
+
+func (p *Player) backend() Backend {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	return p.delegate
+}
+
+// SetOutput casts the playback to the renderer, or plays it locally again if r is nil.
+// The previous renderer is closed.
+func (p *Player) SetOutput(r cast.Renderer) {
+	if p.output != nil {
+		if err := p.output.Close(); err != nil {
+			slog.With("method", "Player.SetOutput").Error("close output", "error", err)
+		}
+	}
+	p.output = r
+}
+
+// Output returns the renderer the playback is cast to, nil if playing locally.
+func (p *Player) Output() cast.Renderer {
+	return p.output
+}
+
+// Play plays the url, fading from the playing stream when a crossfade is set, on the standby backend
+// with the gapless switching.
 func (p *Player) Play(url string) error {
-	return p.delegate.Play(url)
+	if p.output != nil {
+		return p.output.Play(url)
+	}
+	p.stopFade()
+	if d := p.takeFadeIn(); d > 0 {
+		return p.playFadeIn(url, d)
+	}
+	if p.canCrossfade() {
+		return p.playCrossfade(url)
+	}
+	if p.hasStandby() {
+		return p.playStandby(url)
+	}
+	if err := p.backend().Play(url); err != nil {
+		return err
+	}
+	p.setPlaying(true)
+	return nil
 }
 
 func (p *Player) Pause(value bool) error {
-	return p.delegate.Pause(value)
+	if p.output != nil {
+		return p.output.Pause(value)
+	}
+	p.stopFade()
+	if err := p.backend().Pause(value); err != nil {
+		return err
+	}
+	p.setPlaying(!value)
+	return nil
 }
 
 func (p *Player) Stop() error {
-	return p.delegate.Stop()
+	if p.output != nil {
+		return p.output.Stop()
+	}
+	p.stopFade()
+	if err := p.backend().Stop(); err != nil {
+		return err
+	}
+	p.setPlaying(false)
+	return nil
 }
 
-func clampVolume(value int) int {
-	if value < 0 {
-		value = 0
-	} else if value > 100 {
-		value = 100
+func (p *Player) setPlaying(v bool) {
+	p.mtx.Lock()
+	p.playing = v
+	p.mtx.Unlock()
+}
+
+// SetMaxVolume sets the hard cap of the volume, 0 for none, also of the daemon when attached. The
+// backend playing above it is lowered by the next SetVolume, the crossfades already fading to the cap.
+func (p *Player) SetMaxVolume(value int) error {
+	p.mtx.Lock()
+	p.maxVolume = value
+	p.volume = config.ClampVolume(p.volume, value)
+	p.mtx.Unlock()
+	if p.remote != nil {
+		_, err := p.remote.SetMaxVolume(value)
+		return err
 	}
-	return value
+	return nil
+}
+
+// clampVolume returns the volume between 0 and the cap.
+func (p *Player) clampVolume(value int) int {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	return config.ClampVolume(value, p.maxVolume)
 }
 
+// SetVolume sets the volume of the playing backend, or of the cast target, never above the cap.
 func (p *Player) SetVolume(value int) (int, error) {
-	return p.delegate.SetVolume(clampVolume(value))
+	value = p.clampVolume(value)
+	if p.output != nil {
+		return p.output.SetVolume(value)
+	}
+	p.mtx.Lock()
+	p.volume = value
+	if p.fading() {
+		// the crossfade in progress fades to the volume
+		p.mtx.Unlock()
+		return p.volume, nil
+	}
+	p.mtx.Unlock()
+	return p.backend().SetVolume(value)
 }
 
+// Metadata returns nil while casting, renderers don't report the stream title.
 func (p *Player) Metadata() *model.Metadata {
-	return p.delegate.Metadata()
+	if p.output != nil {
+		return nil
+	}
+	return p.backend().Metadata()
 }
 
 func (p *Player) Seek(amtSec int) *model.Metadata {
-	return p.delegate.Seek(amtSec)
+	if p.output != nil {
+		return nil
+	}
+	return p.backend().Seek(amtSec)
 }
 
 func (p *Player) Close() error {
-	return p.delegate.Close()
+	p.SetOutput(nil)
+	p.stopFade()
+	p.closeStandby()
+	return p.backend().Close()
 }
@@ -0,0 +1,30 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+func TestPlayer_SetMaxVolume(t *testing.T) {
+	b := &fakeBackend{typ: config.Mpv}
+	p := NewWithBackend(b)
+	p.SetMaxVolume(60)
+	for _, tt := range []struct{ set, want int }{{90, 60}, {40, 40}, {-5, 0}} {
+		if got, err := p.SetVolume(tt.set); err != nil || got != tt.want {
+			t.Errorf("SetVolume(%d) = %d, %v, want %d", tt.set, got, err, tt.want)
+		}
+		if _, volumes, _ := b.state(); volumes[len(volumes)-1] != tt.want {
+			t.Errorf("got backend volumes %v, want %d last", volumes, tt.want)
+		}
+	}
+
+	p.SetMaxVolume(0)
+	if got, _ := p.SetVolume(120); got != 100 {
+		t.Errorf("got %d without a cap, want 100", got)
+	}
+	p.SetMaxVolume(50)
+	if p.volume != 50 {
+		t.Errorf("got volume %d, want lowered to the cap for the fades", p.volume)
+	}
+}
@@ -0,0 +1,197 @@
+// Package playertest provides a fake player backend, to drive the UI in the tests without mpv or the other players.
+package playertest
+
+import (
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/player"
+	"github.com/dancnb/sonicradio/player/model"
+)
+
+var ErrNoMetadata = errors.New("no metadata")
+
+var _ player.Backend = (*Fake)(nil)
+
+// Fake is a player.Backend playing nothing, which records the calls and reports the song title it is given.
+type Fake struct {
+	mtx     sync.Mutex
+	played  []string
+	url     string
+	paused  bool
+	volume  int
+	title   string
+	seekSec int64
+	closed  bool
+	// playErr fails the next plays
+	playErr error
+	// hold blocks the plays until closed, held counting them
+	hold chan struct{}
+	held int
+	// bufferSec and stream are reported while playing, if set
+	bufferSec *float64
+	stream    *model.StreamInfo
+}
+
+func New() *Fake {
+	return &Fake{volume: config.DefVolume}
+}
+
+func (f *Fake) GetType() config.PlayerType {
+	return config.Mpv
+}
+
+func (f *Fake) Play(url string) error {
+	f.mtx.Lock()
+	hold := f.hold
+	if hold != nil {
+		f.held++
+	}
+	f.mtx.Unlock()
+	if hold != nil {
+		<-hold
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if f.playErr != nil {
+		return f.playErr
+	}
+	f.played = append(f.played, url)
+	f.url, f.paused, f.title, f.seekSec = url, false, "", 0
+	return nil
+}
+
+func (f *Fake) Pause(value bool) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.paused = value
+	return nil
+}
+
+func (f *Fake) Stop() error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.url, f.paused, f.title = "", false, ""
+	return nil
+}
+
+func (f *Fake) SetVolume(value int) (int, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.volume = value
+	return value, nil
+}
+
+// Metadata reports the title set by SetTitle, the buffer set by SetBuffer and the stream set by
+// SetStream while playing.
+func (f *Fake) Metadata() *model.Metadata {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var buffer *float64
+	var stream *model.StreamInfo
+	if f.url != "" {
+		buffer, stream = f.bufferSec, f.stream
+	}
+	if f.url == "" || f.title == "" {
+		return &model.Metadata{Err: ErrNoMetadata, BufferSec: buffer, Stream: stream}
+	}
+	sec := f.seekSec
+	return &model.Metadata{Title: f.title, PlaybackTimeSec: &sec, BufferSec: buffer, Stream: stream}
+}
+
+func (f *Fake) Seek(amtSec int) *model.Metadata {
+	f.mtx.Lock()
+	f.seekSec = max(0, f.seekSec+int64(amtSec))
+	f.mtx.Unlock()
+	return f.Metadata()
+}
+
+func (f *Fake) Close() error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.url, f.closed = "", true
+	return nil
+}
+
+// SetTitle sets the song title of the playing stream.
+func (f *Fake) SetTitle(title string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.title = title
+}
+
+// SetBuffer sets the seconds buffered ahead of the playing stream.
+func (f *Fake) SetBuffer(sec float64) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.bufferSec = &sec
+}
+
+// SetStream sets the audio parameters of the playing stream.
+func (f *Fake) SetStream(s model.StreamInfo) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.stream = &s
+}
+
+// FailPlay makes the next plays fail with err, nil to play again.
+func (f *Fake) FailPlay(err error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.playErr = err
+}
+
+// HoldPlay blocks the next plays, like a station slow to connect, until release is called, once or more.
+func (f *Fake) HoldPlay() (release func()) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	hold := make(chan struct{})
+	f.hold = hold
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			f.mtx.Lock()
+			defer f.mtx.Unlock()
+			if f.hold == hold {
+				f.hold = nil
+			}
+			close(hold)
+		})
+	}
+}
+
+// Held returns the number of plays blocked by HoldPlay so far.
+func (f *Fake) Held() int {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.held
+}
+
+// Played returns the urls played, the last one last.
+func (f *Fake) Played() []string {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return slices.Clone(f.played)
+}
+
+// Playing returns the url playing, empty when stopped, and if it is paused.
+func (f *Fake) Playing() (url string, paused bool) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.url, f.paused
+}
+
+func (f *Fake) Volume() int {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.volume
+}
+
+func (f *Fake) Closed() bool {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.closed
+}
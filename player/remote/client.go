@@ -0,0 +1,138 @@
+package remote
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/perf"
+	"github.com/dancnb/sonicradio/player/model"
+)
+
+// Client is a player whose playback runs in the daemon, so it continues after the app quits.
+type Client struct {
+	mtx        sync.Mutex
+	conn       net.Conn
+	enc        *json.Encoder
+	dec        *json.Decoder
+	playerType config.PlayerType
+}
+
+// Dial connects to the daemon at the socket path.
+func Dial(path string) (*Client, error) {
+	conn, err := net.DialTimeout("unix", path, config.DaemonConnTimeout)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		conn: conn,
+		enc:  json.NewEncoder(conn),
+		dec:  json.NewDecoder(bufio.NewReader(conn)),
+	}
+	res, err := c.do(Request{Method: MethodType})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("daemon handshake: %w", err)
+	}
+	c.playerType = res.PlayerType
+	return c, nil
+}
+
+func (c *Client) do(req Request) (Response, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	defer perf.Since(perf.IPC+" daemon", time.Now())
+
+	var res Response
+	if err := c.conn.SetDeadline(time.Now().Add(config.DaemonReqTimeout)); err != nil {
+		return res, err
+	}
+	if err := c.enc.Encode(req); err != nil {
+		return res, err
+	}
+	if err := c.dec.Decode(&res); err != nil {
+		return res, err
+	}
+	if res.Err != "" {
+		return res, errors.New(res.Err)
+	}
+	return res, nil
+}
+
+func (c *Client) GetType() config.PlayerType {
+	return c.playerType
+}
+
+func (c *Client) Play(url string) error {
+	_, err := c.do(Request{Method: MethodPlay, URL: url})
+	return err
+}
+
+func (c *Client) Pause(value bool) error {
+	_, err := c.do(Request{Method: MethodPause, Pause: value})
+	return err
+}
+
+func (c *Client) Stop() error {
+	_, err := c.do(Request{Method: MethodStop})
+	return err
+}
+
+func (c *Client) SetVolume(value int) (int, error) {
+	res, err := c.do(Request{Method: MethodVolume, Value: value})
+	return res.Volume, err
+}
+
+func (c *Client) Metadata() *model.Metadata {
+	res, err := c.do(Request{Method: MethodMetadata})
+	if err != nil {
+		return &model.Metadata{Err: err}
+	}
+	return res.Metadata.model()
+}
+
+func (c *Client) Seek(amtSec int) *model.Metadata {
+	res, err := c.do(Request{Method: MethodSeek, Value: amtSec})
+	if err != nil {
+		return &model.Metadata{Err: err}
+	}
+	return res.Metadata.model()
+}
+
+// State returns the playback of the daemon.
+func (c *Client) State() (model.State, error) {
+	res, err := c.do(Request{Method: MethodState})
+	if err != nil || res.State == nil {
+		return model.State{}, err
+	}
+	return *res.State, nil
+}
+
+// SetStation tells the daemon which station is playing, for the next attached app to show it.
+func (c *Client) SetStation(uuid, name string) error {
+	_, err := c.do(Request{Method: MethodSetStation, StationUuid: uuid, StationName: name})
+	return err
+}
+
+// SetMaxVolume sets the hard cap of the volume of the daemon, 0 for none, returning the volume lowered
+// under it.
+func (c *Client) SetMaxVolume(value int) (int, error) {
+	res, err := c.do(Request{Method: MethodMaxVolume, Value: value})
+	return res.Volume, err
+}
+
+// Quit stops the playback and the daemon.
+func (c *Client) Quit() error {
+	_, err := c.do(Request{Method: MethodQuit})
+	return err
+}
+
+// Close disconnects from the daemon, which keeps playing.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
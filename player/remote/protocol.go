@@ -0,0 +1,74 @@
+// Package remote controls a player running in the sonicradio daemon over a unix socket.
+//
+// Requests and responses are JSON objects, one per line.
+package remote
+
+import (
+	"errors"
+
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/player/model"
+)
+
+const (
+	MethodType       = "type"
+	MethodPlay       = "play"
+	MethodPause      = "pause"
+	MethodStop       = "stop"
+	MethodVolume     = "volume"
+	MethodMetadata   = "metadata"
+	MethodSeek       = "seek"
+	MethodState      = "state"
+	MethodSetStation = "setStation"
+	MethodMaxVolume  = "maxVolume"
+	MethodQuit       = "quit"
+)
+
+type Request struct {
+	Method      string `json:"method"`
+	URL         string `json:"url,omitempty"`
+	Value       int    `json:"value,omitempty"`
+	Pause       bool   `json:"pause,omitempty"`
+	StationUuid string `json:"stationUuid,omitempty"`
+	StationName string `json:"stationName,omitempty"`
+}
+
+type Response struct {
+	Err        string            `json:"error,omitempty"`
+	PlayerType config.PlayerType `json:"playerType,omitempty"`
+	Volume     int               `json:"volume,omitempty"`
+	Metadata   *Metadata         `json:"metadata,omitempty"`
+	State      *model.State      `json:"state,omitempty"`
+}
+
+// Metadata is model.Metadata with the error as text.
+type Metadata struct {
+	Title           string   `json:"title,omitempty"`
+	PlaybackTimeSec *int64   `json:"playbackTimeSec,omitempty"`
+	BufferSec       *float64 `json:"bufferSec,omitempty"`
+	Err             string   `json:"error,omitempty"`
+	// Stream is sent as is, its fields being tagged
+	Stream *model.StreamInfo `json:"stream,omitempty"`
+}
+
+func NewMetadata(m *model.Metadata) *Metadata {
+	if m == nil {
+		return nil
+	}
+	res := &Metadata{Title: m.Title, PlaybackTimeSec: m.PlaybackTimeSec, BufferSec: m.BufferSec, Stream: m.Stream}
+	if m.Err != nil {
+		res.Err = m.Err.Error()
+	}
+	return res
+}
+
+func (m *Metadata) model() *model.Metadata {
+	if m == nil {
+		return nil
+	}
+	res := &model.Metadata{Title: m.Title, PlaybackTimeSec: m.PlaybackTimeSec, BufferSec: m.BufferSec, Stream: m.Stream}
+	if m.Err != "" {
+		res.Err = errors.New(m.Err)
+	}
+	return res
+}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/perf"
 	"github.com/dancnb/sonicradio/player/model"
 	playerutils "github.com/dancnb/sonicradio/player/utils"
 )
@@ -257,6 +258,7 @@ func (v *Vlc) Close() (err error) {
 func (v *Vlc) doRequest(cmd string) (string, error) {
 	log := slog.With("method", "Vlc.doRequest")
 	log.Info("vlc", "cmd", cmd)
+	defer perf.Since(perf.IPC+" vlc", time.Now())
 
 	v.conn.SetDeadline(time.Now().Add(config.VlcConnTimeout))
 	_, err := v.conn.Write([]byte(cmd))
@@ -0,0 +1,104 @@
+// Package profile renders the favorite stations as a static page to publish, in HTML or Markdown.
+package profile
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strings"
+)
+
+type Format string
+
+const (
+	HTML     Format = "html"
+	Markdown Format = "md"
+)
+
+var Formats = []Format{HTML, Markdown}
+
+// Station is a favorite as shown on the page, without anything private like the notes.
+type Station struct {
+	Name     string
+	Homepage string
+	Tags     []string
+	Country  string
+}
+
+// IsFormat reports whether s is one of the page formats.
+func IsFormat(s string) bool {
+	return slices.Contains(Formats, Format(s))
+}
+
+// Write renders the page of the stations.
+func Write(w io.Writer, format Format, title string, stations []Station) error {
+	switch format {
+	case HTML:
+		return htmlPage.Execute(w, struct {
+			Title    string
+			Stations []Station
+		}{title, stations})
+	case Markdown:
+		return writeMarkdown(w, title, stations)
+	}
+	return fmt.Errorf("unknown page format %q, one of %v", format, Formats)
+}
+
+var htmlPage = template.Must(template.New("profile").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+  li { margin: 0.6rem 0; }
+  .tag { display: inline-block; margin-right: 0.3rem; padding: 0 0.4rem; border-radius: 0.6rem; background: #eee; font-size: 0.85em; }
+  .country { color: #777; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<ol>
+{{- range .Stations}}
+  <li>
+    {{if .Homepage}}<a href="{{.Homepage}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}
+    {{- if .Country}} <span class="country">{{.Country}}</span>{{end}}
+    {{- if .Tags}}<br>{{range .Tags}}<span class="tag">{{.}}</span>{{end}}{{end}}
+  </li>
+{{- end}}
+</ol>
+<p><small>Made with <a href="https://github.com/dancnb/sonicradio">sonicradio</a></small></p>
+</body>
+</html>
+`))
+
+func writeMarkdown(w io.Writer, title string, stations []Station) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", escapeMarkdown(title))
+	for i, s := range stations {
+		name := escapeMarkdown(s.Name)
+		if s.Homepage != "" {
+			name = fmt.Sprintf("[%s](<%s>)", name, s.Homepage)
+		}
+		fmt.Fprintf(&b, "%d. %s", i+1, name)
+		if s.Country != "" {
+			fmt.Fprintf(&b, " (%s)", escapeMarkdown(s.Country))
+		}
+		if len(s.Tags) > 0 {
+			fmt.Fprintf(&b, " — %s", escapeMarkdown(strings.Join(s.Tags, ", ")))
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
@@ -0,0 +1,42 @@
+package profile
+
+import (
+	"strings"
+	"testing"
+)
+
+var testStations = []Station{
+	{Name: "Jazz <FM>", Homepage: "https://jazz.example.com/", Tags: []string{"jazz", "smooth"}, Country: "France"},
+	{Name: "Radio *One*"},
+}
+
+func TestWriteHTML(t *testing.T) {
+	var b strings.Builder
+	if err := Write(&b, HTML, "My stations", testStations); err != nil {
+		t.Fatal(err)
+	}
+	page := b.String()
+	for _, want := range []string{
+		"<title>My stations</title>",
+		`<a href="https://jazz.example.com/">Jazz &lt;FM&gt;</a>`,
+		`<span class="tag">smooth</span>`,
+		"Radio *One*",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("no %q in the page:\n%s", want, page)
+		}
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var b strings.Builder
+	if err := Write(&b, Markdown, "My stations", testStations); err != nil {
+		t.Fatal(err)
+	}
+	want := "# My stations\n\n" +
+		"1. [Jazz \\<FM\\>](<https://jazz.example.com/>) (France) — jazz, smooth\n" +
+		"2. Radio \\*One\\*\n"
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+}
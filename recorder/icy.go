@@ -0,0 +1,85 @@
+package recorder
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const streamTitleKey = "StreamTitle='"
+
+// icyMetaint returns the number of audio bytes between the ICY metadata blocks of the response, 0 if it has none.
+func icyMetaint(res *http.Response) int {
+	n, err := strconv.Atoi(strings.TrimSpace(res.Header.Get("Icy-Metaint")))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// icyReader strips the ICY metadata blocks interleaved with the audio, calling onTitle with every stream title.
+type icyReader struct {
+	r       io.Reader
+	metaint int
+	left    int
+	onTitle func(string) error
+}
+
+func newIcyReader(r io.Reader, metaint int, onTitle func(string) error) *icyReader {
+	return &icyReader{r: r, metaint: metaint, left: metaint, onTitle: onTitle}
+}
+
+func (ir *icyReader) Read(p []byte) (int, error) {
+	if ir.left == 0 {
+		if err := ir.readMetadata(); err != nil {
+			return 0, err
+		}
+		ir.left = ir.metaint
+	}
+	if len(p) > ir.left {
+		p = p[:ir.left]
+	}
+	n, err := ir.r.Read(p)
+	ir.left -= n
+	return n, err
+}
+
+func (ir *icyReader) readMetadata() error {
+	var l [1]byte
+	if _, err := io.ReadFull(ir.r, l[:]); err != nil {
+		return err
+	}
+	if l[0] == 0 {
+		return nil
+	}
+	b := make([]byte, int(l[0])*16)
+	if _, err := io.ReadFull(ir.r, b); err != nil {
+		return err
+	}
+	title, ok := streamTitle(b)
+	if !ok {
+		return nil
+	}
+	return ir.onTitle(title)
+}
+
+// streamTitle returns the StreamTitle value of an ICY metadata block.
+func streamTitle(b []byte) (string, bool) {
+	b = bytes.TrimRight(b, "\x00")
+	start := bytes.Index(b, []byte(streamTitleKey))
+	if start == -1 {
+		return "", false
+	}
+	b = b[start+len(streamTitleKey):]
+	// the title can contain quotes, so it ends at the quote before the next field
+	end := bytes.Index(b, []byte("';"))
+	if end == -1 {
+		end = bytes.LastIndexByte(b, '\'')
+	}
+	if end == -1 {
+		end = len(b)
+	}
+	return string(b[:end]), true
+}
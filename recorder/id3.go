@@ -0,0 +1,59 @@
+package recorder
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"time"
+)
+
+// id3Tags are the ID3v2.4 text frames written at the start of a song file.
+type id3Tags struct {
+	Title   string
+	Artist  string
+	Station string
+	Track   int
+	Date    time.Time
+}
+
+// hasID3 tells if the files with the extension can start with an ID3v2 tag, which players skip.
+func hasID3(ext string) bool {
+	return ext == ".mp3" || ext == ".aac"
+}
+
+func writeID3(w io.Writer, t id3Tags) error {
+	var frames bytes.Buffer
+	textFrame(&frames, "TIT2", t.Title)
+	textFrame(&frames, "TPE1", t.Artist)
+	textFrame(&frames, "TRSN", t.Station)
+	if t.Track > 0 {
+		textFrame(&frames, "TRCK", strconv.Itoa(t.Track))
+	}
+	if !t.Date.IsZero() {
+		textFrame(&frames, "TDRC", t.Date.Format("2006-01-02T15:04:05"))
+	}
+
+	var b bytes.Buffer
+	b.WriteString("ID3")
+	b.Write([]byte{4, 0, 0})
+	b.Write(synchsafe(frames.Len()))
+	b.Write(frames.Bytes())
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// textFrame writes a UTF-8 text frame, skipping empty values.
+func textFrame(b *bytes.Buffer, id, val string) {
+	if val == "" {
+		return
+	}
+	b.WriteString(id)
+	b.Write(synchsafe(len(val) + 1))
+	b.Write([]byte{0, 0, 3})
+	b.WriteString(val)
+}
+
+// synchsafe encodes n in 4 bytes of 7 bits, as ID3v2.4 sizes are.
+func synchsafe(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7f), byte(n >> 14 & 0x7f), byte(n >> 7 & 0x7f), byte(n & 0x7f)}
+}
@@ -0,0 +1,151 @@
+// Package recorder saves radio streams to disk.
+package recorder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	reconnectDelay = 5 * time.Second
+	fileTsFormat   = "20060102-1504"
+)
+
+var unsafeFilenameChars = regexp.MustCompile(`[^\p{L}\p{N}._ -]+`)
+
+// extensions maps the stream content types to file extensions.
+var extensions = map[string]string{
+	"audio/mpeg":      ".mp3",
+	"audio/mp3":       ".mp3",
+	"audio/aac":       ".aac",
+	"audio/aacp":      ".aac",
+	"audio/x-aac":     ".aac",
+	"audio/ogg":       ".ogg",
+	"application/ogg": ".ogg",
+	"audio/opus":      ".opus",
+	"audio/flac":      ".flac",
+}
+
+// Filename returns a file name safe for all platforms made of the parts joined by "_".
+func Filename(ext string, parts ...string) string {
+	for i := range parts {
+		parts[i] = strings.TrimSpace(unsafeFilenameChars.ReplaceAllString(parts[i], ""))
+	}
+	return strings.Join(parts, "_") + ext
+}
+
+// Recorder copies a stream to disk.
+type Recorder struct {
+	client *http.Client
+}
+
+func New() *Recorder {
+	return &Recorder{client: &http.Client{}}
+}
+
+// permanentError stops a recording instead of reconnecting to the stream.
+type permanentError struct{ error }
+
+func (e permanentError) Unwrap() error { return e.error }
+
+// Record saves the stream at url in dir until ctx is done, reconnecting when the stream drops.
+// It returns the path of the file and the number of bytes written.
+func (r *Recorder) Record(ctx context.Context, url string, dir string, name string) (string, int64, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", 0, err
+	}
+
+	var f *os.File
+	var written int64
+	defer func() {
+		if f != nil {
+			f.Close()
+		}
+	}()
+	err := r.stream(ctx, url, false, func(res *http.Response) error {
+		if f == nil {
+			fp := filepath.Join(dir, Filename(extension(res.Header.Get("Content-Type")), name, time.Now().Format(fileTsFormat)))
+			var err error
+			f, err = os.Create(fp)
+			if err != nil {
+				return permanentError{err}
+			}
+		}
+		n, err := io.Copy(f, res.Body)
+		written += n
+		return err
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	if f == nil {
+		return "", 0, fmt.Errorf("could not connect to %s", url)
+	}
+	return f.Name(), written, nil
+}
+
+// stream calls copy with every connection to url until ctx is done, reconnecting when the stream drops.
+func (r *Recorder) stream(ctx context.Context, url string, icy bool, copy func(res *http.Response) error) error {
+	log := slog.With("method", "recorder.Recorder.stream")
+	for {
+		res, err := r.connect(ctx, url, icy)
+		if err == nil {
+			err = copy(res)
+			res.Body.Close()
+		}
+		var perr permanentError
+		if errors.As(err, &perr) {
+			return perr.error
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.Error("stream interrupted", "url", url, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// connect requests the stream, with the ICY metadata interleaved in the audio if icy is true.
+func (r *Recorder) connect(ctx context.Context, url string, icy bool) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if icy {
+		req.Header.Set("Icy-MetaData", "1")
+	}
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("stream response status %s", res.Status)
+	}
+	return res, nil
+}
+
+func extension(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ".mp3"
+	}
+	if ext, ok := extensions[mt]; ok {
+		return ext
+	}
+	return ".mp3"
+}
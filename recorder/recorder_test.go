@@ -0,0 +1,92 @@
+package recorder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+func streamServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/aacp")
+		for {
+			if _, err := w.Write([]byte("audio")); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}))
+}
+
+func TestFilename(t *testing.T) {
+	got := Filename(".mp3", "Radio: One / FM?", "20240601-1800")
+	if got != "Radio One  FM_20240601-1800.mp3" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRecorder_Record(t *testing.T) {
+	srv := streamServer(t)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	dir := t.TempDir()
+	fp, n, err := New().Record(ctx, srv.URL, dir, "station")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Ext(fp) != ".aac" || !strings.HasPrefix(filepath.Base(fp), "station_") {
+		t.Errorf("got path %q", fp)
+	}
+	b, err := os.ReadFile(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n == 0 || int64(len(b)) != n || !strings.HasPrefix(string(b), "audio") {
+		t.Errorf("got n=%d, content %q", n, b)
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	srv := streamServer(t)
+	defer srv.Close()
+
+	cfg := &config.Value{RecordingsDir: t.TempDir()}
+	now := time.Now()
+	cfg.AddSchedule(config.RecordingSchedule{ID: "now", StationName: "station", URL: srv.URL, Start: now, Duration: 100 * time.Millisecond})
+	cfg.AddSchedule(config.RecordingSchedule{ID: "later", StationName: "station", URL: srv.URL, Start: now.Add(time.Hour), Duration: time.Hour})
+
+	changed := make(chan struct{}, 4)
+	s := NewScheduler(cfg, func() { changed <- struct{}{} })
+	s.check(context.Background(), now)
+	if !s.IsRunning("now") || s.IsRunning("later") {
+		t.Fatal("expected only the due schedule to be running")
+	}
+	<-changed
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("recording did not end")
+	}
+
+	recs := cfg.GetRecordings()
+	if len(recs) != 1 || recs[0].ScheduleID != "now" || recs[0].Err != "" || recs[0].Size == 0 {
+		t.Errorf("got recordings %+v", recs)
+	}
+	if sch := cfg.GetSchedules(); len(sch) != 1 || sch[0].ID != "later" {
+		t.Errorf("got schedules %+v, want the finished one removed", sch)
+	}
+}
@@ -0,0 +1,185 @@
+package recorder
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/diskquota"
+)
+
+const (
+	checkInterval = 15 * time.Second
+	// recentlyWritten files are kept by the disk quota, as a running recording is still written to
+	recentlyWritten = time.Minute
+)
+
+var (
+	errStopped     = errors.New("stopped")
+	errInterrupted = errors.New("interrupted")
+)
+
+// Scheduler starts and stops the recordings of the scheduled time windows.
+type Scheduler struct {
+	cfg      *config.Value
+	recorder *Recorder
+	onChange func()
+
+	mtx     sync.Mutex
+	running map[string]context.CancelCauseFunc
+	wg      sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler calling onChange whenever a recording starts or ends.
+func NewScheduler(cfg *config.Value, onChange func()) *Scheduler {
+	return &Scheduler{
+		cfg:      cfg,
+		recorder: New(),
+		onChange: onChange,
+		running:  make(map[string]context.CancelCauseFunc),
+	}
+}
+
+func (s *Scheduler) Run(ctx context.Context) {
+	t := time.NewTicker(checkInterval)
+	defer t.Stop()
+	for {
+		s.check(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Check starts the recordings that are due now, e.g. after a schedule was added.
+func (s *Scheduler) Check(ctx context.Context) {
+	s.check(ctx, time.Now())
+}
+
+func (s *Scheduler) check(ctx context.Context, now time.Time) {
+	for _, sch := range s.cfg.GetSchedules() {
+		start, ok := sch.Next(now)
+		if !ok || start.After(now) || s.IsRunning(sch.ID) {
+			continue
+		}
+		s.start(ctx, sch, start.Add(sch.Duration))
+	}
+}
+
+func (s *Scheduler) start(ctx context.Context, sch config.RecordingSchedule, end time.Time) {
+	log := slog.With("method", "recorder.Scheduler.start")
+	dir, err := s.cfg.GetRecordingsDir()
+	if err != nil {
+		log.Error("", "error", err)
+		return
+	}
+
+	ctx, stop := context.WithCancelCause(ctx)
+	ctx, cancel := context.WithDeadline(ctx, end)
+	s.mtx.Lock()
+	s.running[sch.ID] = stop
+	s.mtx.Unlock()
+	s.notify()
+	log.Info("recording", "station", sch.StationName, "until", end)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		startTs := time.Now()
+		var fp string
+		var n int64
+		if s.cfg.SplitRecordings {
+			fp, n, err = s.recorder.RecordSongs(ctx, sch.URL, dir, sch.StationName, s.cfg.GetSongRule(sch.StationUuid))
+		} else {
+			fp, n, err = s.recorder.Record(ctx, sch.URL, dir, sch.StationName)
+		}
+		entry := config.RecordingEntry{
+			ScheduleID:  sch.ID,
+			StationName: sch.StationName,
+			Start:       startTs,
+			End:         time.Now(),
+			Path:        fp,
+			Size:        n,
+		}
+		// the app quitting before the end of the window is an interruption, the recording is resumed on the next start
+		stopped := errors.Is(context.Cause(ctx), errStopped)
+		interrupted := !stopped && time.Now().Before(end)
+		switch {
+		case err != nil:
+			entry.Err = err.Error()
+		case stopped:
+			entry.Err = errStopped.Error()
+		case interrupted:
+			entry.Err = errInterrupted.Error()
+		}
+
+		s.mtx.Lock()
+		delete(s.running, sch.ID)
+		s.mtx.Unlock()
+		s.cfg.AddRecording(entry, !interrupted)
+		s.EnforceQuota()
+		s.notify()
+	}()
+}
+
+// EnforceQuota removes the oldest recordings while the recordings dir is over its size limit.
+func (s *Scheduler) EnforceQuota() {
+	if s.cfg.RecordingsLimitMB <= 0 {
+		return
+	}
+	log := slog.With("method", "recorder.Scheduler.EnforceQuota")
+	dir, err := s.cfg.GetRecordingsDir()
+	if err != nil {
+		log.Error("", "error", err)
+		return
+	}
+	limit := int64(s.cfg.RecordingsLimitMB) * diskquota.MB
+	removed, freed, err := diskquota.Enforce(dir, limit, time.Now().Add(-recentlyWritten))
+	if err != nil {
+		log.Error("", "error", err)
+	}
+	if len(removed) == 0 {
+		return
+	}
+	log.Info("removed recordings", "files", len(removed), "freed", diskquota.FormatSize(freed))
+	s.cfg.PruneRecordings()
+	s.notify()
+}
+
+func (s *Scheduler) IsRunning(id string) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	_, ok := s.running[id]
+	return ok
+}
+
+// Stop ends the recording of the schedule, if running.
+func (s *Scheduler) Stop(id string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if stop, ok := s.running[id]; ok {
+		stop(errStopped)
+	}
+}
+
+func (s *Scheduler) notify() {
+	if s.onChange != nil {
+		s.onChange()
+	}
+}
+
+// Close interrupts the running recordings and waits for them to be saved.
+func (s *Scheduler) Close() {
+	s.mtx.Lock()
+	for _, stop := range s.running {
+		stop(errInterrupted)
+	}
+	s.mtx.Unlock()
+	s.wg.Wait()
+}
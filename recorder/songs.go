@@ -0,0 +1,142 @@
+package recorder
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/metadata"
+)
+
+// maxPending is the audio kept before the first song title is known, after which it's saved under the station name.
+const maxPending = 1 << 20
+
+// RecordSongs saves the stream at url until ctx is done, in a new folder of dir with a file for every song.
+// The songs are told apart by the ICY stream titles, parsed into artist and title with the rule.
+// If the stream has no ICY metadata, it's saved to a single file, as with Record.
+// It returns the path of the folder and the number of bytes written.
+func (r *Recorder) RecordSongs(ctx context.Context, url string, dir string, name string, rule config.SongRule) (string, int64, error) {
+	s := &songFiles{
+		dir:     filepath.Join(dir, Filename("", name, time.Now().Format(fileTsFormat))),
+		station: name,
+		rule:    rule,
+	}
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return "", 0, err
+	}
+	defer s.close()
+
+	connected := false
+	err := r.stream(ctx, url, true, func(res *http.Response) error {
+		if !connected {
+			connected = true
+			s.ext = extension(res.Header.Get("Content-Type"))
+		}
+		var body io.Reader = res.Body
+		if metaint := icyMetaint(res); metaint > 0 {
+			body = newIcyReader(res.Body, metaint, s.setTitle)
+		}
+		_, err := io.Copy(s, body)
+		return err
+	})
+	if err == nil {
+		err = s.flush()
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	if !connected {
+		os.Remove(s.dir)
+		return "", 0, fmt.Errorf("could not connect to %s", url)
+	}
+	return s.dir, s.written, nil
+}
+
+// songFiles writes the audio of a stream to a new file on every song change.
+type songFiles struct {
+	dir     string
+	station string
+	rule    config.SongRule
+	ext     string
+
+	f       *os.File
+	title   string
+	track   int
+	pending []byte
+	written int64
+}
+
+func (s *songFiles) Write(p []byte) (int, error) {
+	if s.f == nil {
+		if len(s.pending)+len(p) <= maxPending {
+			s.pending = append(s.pending, p...)
+			return len(p), nil
+		}
+		if err := s.next(metadata.Song{}); err != nil {
+			return 0, err
+		}
+	}
+	n, err := s.f.Write(p)
+	s.written += int64(n)
+	return n, err
+}
+
+// setTitle starts the file of a new song, while an empty title, e.g. during ads, keeps the current one.
+func (s *songFiles) setTitle(raw string) error {
+	title := metadata.Normalize(raw)
+	if title == "" || title == s.title {
+		return nil
+	}
+	s.title = title
+	return s.next(metadata.Parse(title, s.rule))
+}
+
+// next closes the current file and starts the one of song, named after the station if the song is unknown.
+func (s *songFiles) next(song metadata.Song) error {
+	s.close()
+	s.track++
+	name := s.station
+	if song.Title != "" {
+		name = song.String()
+	}
+	f, err := os.Create(filepath.Join(s.dir, Filename(s.ext, fmt.Sprintf("%02d", s.track), name)))
+	if err != nil {
+		return permanentError{err}
+	}
+	s.f = f
+	if hasID3(s.ext) {
+		tags := id3Tags{Title: song.Title, Artist: song.Artist, Station: s.station, Track: s.track, Date: time.Now()}
+		if err := writeID3(f, tags); err != nil {
+			return permanentError{err}
+		}
+	}
+	if len(s.pending) > 0 {
+		n, err := f.Write(s.pending)
+		s.written += int64(n)
+		s.pending = nil
+		if err != nil {
+			return permanentError{err}
+		}
+	}
+	return nil
+}
+
+// flush saves the audio received before any song title.
+func (s *songFiles) flush() error {
+	if s.f == nil && len(s.pending) > 0 {
+		return s.next(metadata.Song{})
+	}
+	return nil
+}
+
+func (s *songFiles) close() {
+	if s.f != nil {
+		s.f.Close()
+		s.f = nil
+	}
+}
@@ -0,0 +1,134 @@
+package recorder
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+const testMetaint = 8
+
+// icyBlock returns the metadata block of the title, padded to 16 bytes.
+func icyBlock(title string) []byte {
+	meta := []byte("StreamTitle='" + title + "';")
+	l := (len(meta) + 15) / 16
+	meta = append(meta, make([]byte, l*16-len(meta))...)
+	return append([]byte{byte(l)}, meta...)
+}
+
+func icyServer(t *testing.T, titles []string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Icy-MetaData") != "1" {
+			t.Error("missing Icy-MetaData request header")
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Header().Set("Icy-Metaint", "8")
+		for i := 0; ; i++ {
+			var b bytes.Buffer
+			b.WriteString("audio-" + string(rune('0'+i%10)) + "!")
+			b.Write(icyBlock(titles[min(i/2, len(titles)-1)]))
+			if _, err := w.Write(b.Bytes()); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(5 * time.Millisecond):
+			}
+		}
+	}))
+}
+
+func TestStreamTitle(t *testing.T) {
+	tests := []struct {
+		block string
+		want  string
+		ok    bool
+	}{
+		{"StreamTitle='Artist - Song';StreamUrl='';\x00\x00", "Artist - Song", true},
+		{"StreamTitle='Rock 'n' Roll';\x00", "Rock 'n' Roll", true},
+		{"StreamTitle='';", "", true},
+		{"StreamUrl='http://example.com';", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := streamTitle([]byte(tt.block))
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("streamTitle(%q) = %q, %v, want %q, %v", tt.block, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIcyReader(t *testing.T) {
+	var stream bytes.Buffer
+	stream.WriteString("12345678")
+	stream.Write(icyBlock("One"))
+	stream.WriteString("abcdefgh")
+	stream.WriteByte(0)
+	stream.WriteString("ABCD")
+
+	var titles []string
+	r := newIcyReader(&stream, testMetaint, func(s string) error {
+		titles = append(titles, s)
+		return nil
+	})
+	var audio bytes.Buffer
+	if _, err := audio.ReadFrom(r); err != nil {
+		t.Fatal(err)
+	}
+	if audio.String() != "12345678abcdefghABCD" {
+		t.Errorf("got audio %q", audio.String())
+	}
+	if len(titles) != 1 || titles[0] != "One" {
+		t.Errorf("got titles %q", titles)
+	}
+}
+
+func TestRecorder_RecordSongs(t *testing.T) {
+	srv := icyServer(t, []string{"Artist A - Song A", "Artist B - Song B", "Artist C - Song C"})
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	dir, n, err := New().RecordSongs(ctx, srv.URL, t.TempDir(), "station", config.SongRule{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n == 0 {
+		t.Error("nothing written")
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.mp3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) < 3 {
+		t.Fatalf("got files %q, want one per song", files)
+	}
+	if filepath.Base(files[0]) != "01_Artist A - Song A.mp3" || filepath.Base(files[1]) != "02_Artist B - Song B.mp3" {
+		t.Errorf("got files %q", files)
+	}
+
+	b, err := os.ReadFile(files[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(b, []byte("ID3\x04")) {
+		t.Fatalf("missing ID3 tag in %q", b)
+	}
+	for _, frame := range []string{"TIT2", "Song B", "TPE1", "Artist B", "TRSN", "station", "TRCK"} {
+		if !bytes.Contains(b, []byte(frame)) {
+			t.Errorf("tag is missing %q", frame)
+		}
+	}
+	if strings.Contains(string(b), "StreamTitle") {
+		t.Error("metadata was written with the audio")
+	}
+}
@@ -0,0 +1,245 @@
+// Package relay serves the playing station over HTTP, so that other devices on the LAN can tune into it.
+// The listeners share one connection to the station, passed through with its ICY headers and metadata.
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	streamPath     = "/"
+	playlistPath   = "/listen.m3u"
+	copyBufSize    = 16 * 1024
+	upstreamDialTo = 10 * time.Second
+	noStationMsg   = "Nothing playing"
+)
+
+// passedHeaders are the upstream headers sent to the listeners, along with the icy-* ones.
+var passedHeaders = []string{"Content-Type", "Cache-Control"}
+
+// Station is the stream relayed to the listeners.
+type Station struct {
+	Uuid string `json:"uuid"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type Relay struct {
+	srv    *http.Server
+	ln     net.Listener
+	client *http.Client
+
+	mtx     sync.Mutex
+	station Station
+	// changed is canceled when the station changes, disconnecting the listeners of the previous one
+	changed context.Context
+	cancel  context.CancelFunc
+	// up is the connection to the station while it has listeners
+	up *upstream
+	// received counts the bytes of the station streams, see Received
+	received atomic.Int64
+	// guests are the listening room members following the station changes
+	guests map[chan Station]struct{}
+	done   chan struct{}
+}
+
+// Start listens on addr, e.g. ":8765".
+func Start(addr string) (*Relay, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	r := &Relay{
+		ln: ln,
+		client: &http.Client{Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           (&net.Dialer{Timeout: upstreamDialTo}).DialContext,
+			ResponseHeaderTimeout: upstreamDialTo,
+		}},
+	}
+	r.changed, r.cancel = context.WithCancel(context.Background())
+	r.guests = make(map[chan Station]struct{})
+	r.done = make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc(roomPath, r.serveRoom)
+	mux.HandleFunc(playlistPath, r.servePlaylist)
+	mux.HandleFunc(streamPath, r.serveStream)
+	r.srv = &http.Server{Handler: mux, ReadHeaderTimeout: upstreamDialTo}
+	go func() {
+		if err := r.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.With("method", "relay.Relay.Serve").Error("", "error", err)
+		}
+	}()
+	return r, nil
+}
+
+// Addr returns the address the relay listens on.
+func (r *Relay) Addr() net.Addr {
+	return r.ln.Addr()
+}
+
+// URL returns the stream url for the other devices, with the first LAN address of the host.
+func (r *Relay) URL() string {
+	port := strconv.Itoa(r.ln.Addr().(*net.TCPAddr).Port)
+	host := "localhost"
+	if ip := lanIP(); ip != nil {
+		host = ip.String()
+	}
+	return "http://" + net.JoinHostPort(host, port) + streamPath
+}
+
+func lanIP() net.IP {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+	for _, a := range addrs {
+		ipNet, ok := a.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() || ipNet.IP.To4() == nil {
+			continue
+		}
+		if ipNet.IP.IsPrivate() {
+			return ipNet.IP
+		}
+	}
+	return nil
+}
+
+// SetStation changes the relayed station, the zero Station when nothing plays.
+func (r *Relay) SetStation(s Station) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if s == r.station {
+		return
+	}
+	slog.With("method", "relay.Relay.SetStation").Info("", "station", s.Name, "url", s.URL)
+	r.station = s
+	if r.up != nil {
+		r.up.cancel()
+		r.up = nil
+	}
+	r.cancel()
+	r.changed, r.cancel = context.WithCancel(context.Background())
+	r.publish(s)
+}
+
+func (r *Relay) current() (Station, context.Context) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.station, r.changed
+}
+
+// Received returns the bytes received from the stations since the last call, to count in the bandwidth used.
+func (r *Relay) Received() int64 {
+	return r.received.Swap(0)
+}
+
+func (r *Relay) Close() error {
+	r.mtx.Lock()
+	if r.up != nil {
+		r.up.cancel()
+	}
+	r.cancel()
+	r.mtx.Unlock()
+	close(r.done)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return r.srv.Shutdown(ctx)
+}
+
+func (r *Relay) serveStream(w http.ResponseWriter, req *http.Request) {
+	log := slog.With("method", "relay.Relay.serveStream", "remote", req.RemoteAddr)
+	if req.URL.Path != streamPath {
+		http.NotFound(w, req)
+		return
+	}
+	station, changed := r.current()
+	if station.URL == "" {
+		http.Error(w, noStationMsg, http.StatusServiceUnavailable)
+		return
+	}
+
+	up, ch := r.join(station, req.UserAgent())
+	defer r.leave(up, ch)
+	select {
+	case <-up.ready:
+	case <-req.Context().Done():
+		return
+	}
+	if up.err != nil {
+		http.Error(w, up.err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	for k, v := range up.header {
+		w.Header()[k] = v
+	}
+	write := func(c chunk) error {
+		if c.audio == nil {
+			return nil
+		}
+		_, err := w.Write(c.audio)
+		return err
+	}
+	if req.Header.Get("Icy-MetaData") == "1" && up.metaint > 0 {
+		w.Header().Set("icy-metaint", strconv.Itoa(up.metaint))
+		iw := &icyWriter{w: w, metaint: up.metaint, left: up.metaint}
+		write = iw.write
+	}
+	w.WriteHeader(http.StatusOK)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	log.Info("listener connected", "station", station.Name)
+	n, err := serveChunks(req.Context(), changed, w, ch, write)
+	log.Info("listener disconnected", "bytes", n, "error", err)
+}
+
+// serveChunks sends the stream as it arrives, instead of when the response buffer is full, until the
+// listener leaves, the station changes or its stream ends.
+func serveChunks(ctx, changed context.Context, w http.ResponseWriter, ch chan chunk, write func(chunk) error) (int64, error) {
+	flusher, _ := w.(http.Flusher)
+	var total int64
+	for {
+		var c chunk
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return total, ctx.Err()
+		case <-changed.Done():
+			return total, nil
+		case c, ok = <-ch:
+		}
+		if !ok {
+			return total, nil
+		}
+		if err := write(c); err != nil {
+			return total, err
+		}
+		total += int64(len(c.audio))
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// servePlaylist returns an m3u playlist with the stream url, for the players that open playlists.
+func (r *Relay) servePlaylist(w http.ResponseWriter, req *http.Request) {
+	station, _ := r.current()
+	name := station.Name
+	if name == "" {
+		name = "sonicradio"
+	}
+	w.Header().Set("Content-Type", "audio/x-mpegurl")
+	fmt.Fprintf(w, "#EXTM3U\n#EXTINF:-1,%s\nhttp://%s%s\n", name, req.Host, streamPath)
+}
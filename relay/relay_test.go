@@ -0,0 +1,166 @@
+package relay
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRelay(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Header().Set("icy-br", "128")
+		if r.Header.Get("Icy-MetaData") == "1" {
+			w.Header().Set("icy-metaint", "4")
+		}
+		w.Write([]byte("abcd"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer upstream.Close()
+
+	r, err := Start("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	url := "http://" + r.Addr().String() + streamPath
+
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status %d without station, want %d", res.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	r.SetStation(Station{Name: "Radio X", URL: upstream.URL})
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Icy-MetaData", "1")
+	res, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.Header.Get("Content-Type") != "audio/mpeg" || res.Header.Get("icy-br") != "128" ||
+		res.Header.Get("icy-metaint") != "4" || res.Header.Get("icy-name") != "Radio X" {
+		t.Errorf("headers %v", res.Header)
+	}
+	b := make([]byte, 4)
+	if _, err := io.ReadFull(res.Body, b); err != nil || string(b) != "abcd" {
+		t.Fatalf("read %q %v", b, err)
+	}
+
+	// the listeners of the previous station are disconnected
+	done := make(chan error)
+	go func() {
+		_, err := io.ReadAll(res.Body)
+		done <- err
+	}()
+	r.SetStation(Station{})
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Error("listener not disconnected after the station change")
+	}
+}
+
+func TestRelay_fanOut(t *testing.T) {
+	var connections atomic.Int32
+	start := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connections.Add(1)
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Header().Set("icy-metaint", "4")
+		w.(http.Flusher).Flush()
+		<-start
+		w.Write([]byte("abcd\x01StreamTitle='x';efgh"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer upstream.Close()
+
+	r, err := Start("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	r.SetStation(Station{Name: "Radio X", URL: upstream.URL})
+	url := "http://" + r.Addr().String() + streamPath
+
+	listen := func(metadata bool) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		if metadata {
+			req.Header.Set("Icy-MetaData", "1")
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { res.Body.Close() })
+		return res
+	}
+	withMeta, withoutMeta := listen(true), listen(false)
+	close(start)
+
+	for _, tt := range []struct {
+		res     *http.Response
+		metaint string
+		want    string
+	}{
+		{withMeta, "4", "abcd\x01StreamTitle='x';efgh"},
+		{withoutMeta, "", "abcdefgh"},
+	} {
+		if got := tt.res.Header.Get("icy-metaint"); got != tt.metaint {
+			t.Errorf("icy-metaint %q, want %q", got, tt.metaint)
+		}
+		b := make([]byte, len(tt.want))
+		if _, err := io.ReadFull(tt.res.Body, b); err != nil || string(b) != tt.want {
+			t.Errorf("read %q %v, want %q", b, err, tt.want)
+		}
+	}
+	if n := connections.Load(); n != 1 {
+		t.Errorf("%d connections to the station, want one for all the listeners", n)
+	}
+	if n := r.Received(); n != 25 {
+		t.Errorf("received %d bytes, want 25", n)
+	}
+}
+
+func TestRoom(t *testing.T) {
+	r, err := Start("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	r.SetStation(Station{Uuid: "1", Name: "Radio X", URL: "http://radiox.example/live"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stations := make(chan Station, 4)
+	go Join(ctx, r.Addr().String(), func(s Station) { stations <- s })
+
+	want := []Station{
+		{Uuid: "1", Name: "Radio X", URL: "http://radiox.example/live"},
+		{Uuid: "2", Name: "Radio Y", URL: "http://radioy.example/live"},
+	}
+	for i, w := range want {
+		select {
+		case s := <-stations:
+			if s != w {
+				t.Errorf("station %+v, want %+v", s, w)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("station %d not received", i)
+		}
+		if i == 0 {
+			r.SetStation(want[1])
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package relay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	roomPath = "/room"
+	// roomKeepAlive keeps the guest connections open through the idle timeouts of proxies and NAT
+	roomKeepAlive   = 30 * time.Second
+	roomRetryMin    = time.Second
+	roomRetryMax    = time.Minute
+	roomGuestBuffer = 4
+)
+
+// publish sends the station to the guests, r.mtx must be held.
+// A guest too slow to read the previous changes only gets the latest one.
+func (r *Relay) publish(s Station) {
+	for ch := range r.guests {
+		select {
+		case ch <- s:
+			continue
+		default:
+		}
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- s:
+		default:
+		}
+	}
+}
+
+// serveRoom streams the station changes to a guest, one JSON line for each, starting with the current station.
+func (r *Relay) serveRoom(w http.ResponseWriter, req *http.Request) {
+	log := slog.With("method", "relay.Relay.serveRoom", "remote", req.RemoteAddr)
+	ch := make(chan Station, roomGuestBuffer)
+	r.mtx.Lock()
+	ch <- r.station
+	r.guests[ch] = struct{}{}
+	r.mtx.Unlock()
+	defer func() {
+		r.mtx.Lock()
+		delete(r.guests, ch)
+		r.mtx.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	log.Info("guest joined")
+	defer log.Info("guest left")
+
+	t := time.NewTicker(roomKeepAlive)
+	defer t.Stop()
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-r.done:
+			return
+		case s := <-ch:
+			if err := enc.Encode(s); err != nil {
+				return
+			}
+		case <-t.C:
+			// an empty line, ignored by the guests
+			if _, err := w.Write([]byte("\n")); err != nil {
+				return
+			}
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// Join follows the station changes of the host at addr, e.g. "192.168.1.10:8765", until ctx is done.
+// The connection is retried when lost, and onStation gets the current station of the host after every reconnect.
+func Join(ctx context.Context, addr string, onStation func(Station)) {
+	log := slog.With("method", "relay.Join", "host", addr)
+	client := &http.Client{Transport: &http.Transport{
+		Proxy:       http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{Timeout: upstreamDialTo}).DialContext,
+	}}
+	retry := roomRetryMin
+	for {
+		start := time.Now()
+		err := follow(ctx, client, addr, onStation)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Info("connection lost", "error", err)
+		if time.Since(start) > roomRetryMax {
+			retry = roomRetryMin
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+		retry = min(2*retry, roomRetryMax)
+	}
+}
+
+func follow(ctx context.Context, client *http.Client, addr string, onStation func(Station)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+roomPath, nil)
+	if err != nil {
+		return err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("host responded %s", res.Status)
+	}
+
+	sc := bufio.NewScanner(res.Body)
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var s Station
+		if err := json.Unmarshal(sc.Bytes(), &s); err != nil {
+			return err
+		}
+		onStation(s)
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return errors.New("host closed the connection")
+}
@@ -0,0 +1,260 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// listenerBuffer is the number of chunks a listener can lag behind the station before it's disconnected.
+const listenerBuffer = 64
+
+// chunk is a piece of the stream: audio, or a metadata block with its length byte.
+type chunk struct {
+	audio []byte
+	meta  []byte
+}
+
+// upstream is the one connection to the station, its stream passed to all the listeners.
+// It asks for the ICY metadata, which is inserted again for the listeners asking for it.
+type upstream struct {
+	station Station
+	// userAgent is the one of the first listener
+	userAgent string
+	cancel    context.CancelFunc
+	// ready is closed when the headers are read, or err set
+	ready chan struct{}
+	err   error
+	// header are the headers passed to the listeners, metaint the interval of the metadata blocks, 0 without
+	header  http.Header
+	metaint int
+
+	// the fields below are guarded by Relay.mtx
+	listeners map[chan chunk]struct{}
+	// meta is the last metadata block, sent to the listeners joining
+	meta []byte
+}
+
+// join adds a listener to the upstream of the station, connecting to it for the first listener.
+func (r *Relay) join(station Station, userAgent string) (*upstream, chan chunk) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	up := r.up
+	if up == nil || up.station != station {
+		if up != nil {
+			up.cancel()
+		}
+		var ctx context.Context
+		up = &upstream{station: station, userAgent: userAgent, ready: make(chan struct{}), listeners: make(map[chan chunk]struct{})}
+		ctx, up.cancel = context.WithCancel(context.Background())
+		r.up = up
+		go r.read(ctx, up)
+	}
+	ch := make(chan chunk, listenerBuffer)
+	up.listeners[ch] = struct{}{}
+	if up.meta != nil {
+		ch <- chunk{meta: up.meta}
+	}
+	return up, ch
+}
+
+// leave removes a listener, disconnecting from the station after the last one.
+func (r *Relay) leave(up *upstream, ch chan chunk) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if _, ok := up.listeners[ch]; !ok {
+		return
+	}
+	delete(up.listeners, ch)
+	close(ch)
+	if len(up.listeners) == 0 {
+		up.cancel()
+		if r.up == up {
+			r.up = nil
+		}
+	}
+}
+
+// broadcast sends the chunk to the listeners, disconnecting the ones too slow to read the stream.
+func (r *Relay) broadcast(up *upstream, c chunk) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if c.meta != nil {
+		up.meta = c.meta
+	}
+	for ch := range up.listeners {
+		select {
+		case ch <- c:
+		default:
+			delete(up.listeners, ch)
+			close(ch)
+		}
+	}
+}
+
+// read streams the station to the listeners until it ends or the upstream is canceled.
+func (r *Relay) read(ctx context.Context, up *upstream) {
+	log := slog.With("method", "relay.Relay.read", "station", up.station.Name)
+	defer func() {
+		r.mtx.Lock()
+		for ch := range up.listeners {
+			delete(up.listeners, ch)
+			close(ch)
+		}
+		if r.up == up {
+			r.up = nil
+		}
+		r.mtx.Unlock()
+		up.cancel()
+	}()
+
+	res, err := r.connect(ctx, up)
+	if err == nil {
+		defer res.Body.Close()
+		up.header, up.metaint, err = upstreamHeader(res, up.station)
+	}
+	up.err = err
+	close(up.ready)
+	if err != nil {
+		log.Error("upstream", "error", err)
+		return
+	}
+
+	log.Info("upstream connected")
+	n, err := r.readStream(res.Body, up)
+	log.Info("upstream disconnected", "bytes", n, "error", err)
+}
+
+func (r *Relay) connect(ctx context.Context, up *upstream) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, up.station.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", up.userAgent)
+	req.Header.Set("Icy-MetaData", "1")
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("station responded %s", res.Status)
+	}
+	return res, nil
+}
+
+// upstreamHeader returns the headers of the station passed to the listeners, and its metadata interval.
+func upstreamHeader(res *http.Response, station Station) (http.Header, int, error) {
+	h := make(http.Header)
+	for k, v := range res.Header {
+		if strings.HasPrefix(strings.ToLower(k), "icy-") {
+			h[k] = v
+		}
+	}
+	for _, k := range passedHeaders {
+		if v := res.Header.Get(k); v != "" {
+			h.Set(k, v)
+		}
+	}
+	if h.Get("icy-name") == "" && station.Name != "" {
+		h.Set("icy-name", station.Name)
+	}
+	var metaint int
+	if v := h.Get("icy-metaint"); v != "" {
+		h.Del("icy-metaint")
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, 0, fmt.Errorf("invalid icy-metaint %q", v)
+		}
+		metaint = n
+	}
+	return h, metaint, nil
+}
+
+// readStream splits the stream into its audio and its metadata blocks, counting the bytes received.
+func (r *Relay) readStream(body io.Reader, up *upstream) (int64, error) {
+	var total int64
+	buf := make([]byte, copyBufSize)
+	left := up.metaint
+	for {
+		size := len(buf)
+		if up.metaint > 0 {
+			size = min(size, left)
+		}
+		n, err := body.Read(buf[:size])
+		if n > 0 {
+			total += int64(n)
+			r.received.Add(int64(n))
+			r.broadcast(up, chunk{audio: append([]byte(nil), buf[:n]...)})
+			left -= n
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return total, nil
+			}
+			return total, err
+		}
+		if up.metaint == 0 || left > 0 {
+			continue
+		}
+
+		left = up.metaint
+		var length [1]byte
+		if _, err := io.ReadFull(body, length[:]); err != nil {
+			return total, err
+		}
+		meta := make([]byte, 1+int(length[0])*16)
+		meta[0] = length[0]
+		if _, err := io.ReadFull(body, meta[1:]); err != nil {
+			return total, err
+		}
+		total += int64(len(meta))
+		r.received.Add(int64(len(meta)))
+		if length[0] > 0 {
+			r.broadcast(up, chunk{meta: meta})
+		}
+	}
+}
+
+// icyWriter writes the audio to a listener asking for the metadata, a block every metaint bytes: the
+// last one of the station when it changed, otherwise an empty one.
+type icyWriter struct {
+	w       io.Writer
+	metaint int
+	left    int
+	meta    []byte
+}
+
+func (iw *icyWriter) write(c chunk) error {
+	if c.meta != nil {
+		iw.meta = c.meta
+		return nil
+	}
+	audio := c.audio
+	for len(audio) > 0 {
+		// the block is written with the next audio, for the metadata read after the previous one
+		if iw.left == 0 {
+			meta := iw.meta
+			if meta == nil {
+				meta = []byte{0}
+			}
+			if _, err := iw.w.Write(meta); err != nil {
+				return err
+			}
+			iw.meta = nil
+			iw.left = iw.metaint
+		}
+		n := min(len(audio), iw.left)
+		if _, err := iw.w.Write(audio[:n]); err != nil {
+			return err
+		}
+		audio = audio[n:]
+		iw.left -= n
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// announceQueueSize is how many lines wait to be printed before the next ones are dropped
+const announceQueueSize = 32
+
+// announcer prints the state changes as plain lines above the view in the screen reader mode, in the
+// order they happened, since the screen readers read the new lines of the terminal but not the redraws.
+type announcer struct {
+	lines chan string
+
+	mtx  sync.Mutex
+	last string
+}
+
+func newAnnouncer() *announcer {
+	return &announcer{lines: make(chan string, announceQueueSize)}
+}
+
+// announce queues the line, unless it repeats the previous one. It's a no-op on a nil announcer,
+// when the screen reader mode is off.
+func (a *announcer) announce(line string) {
+	line = strings.TrimSpace(line)
+	if a == nil || line == "" {
+		return
+	}
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if line == a.last {
+		return
+	}
+	a.last = line
+	select {
+	case a.lines <- line:
+	default:
+		slog.With("method", "ui.announcer.announce").Warn("queue full, line dropped", "line", line)
+	}
+}
+
+// run prints the queued lines until ctx is done.
+func (a *announcer) run(ctx context.Context, print func(line string)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line := <-a.lines:
+			print(line)
+		}
+	}
+}
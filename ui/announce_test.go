@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAnnouncer(t *testing.T) {
+	var nilAnnouncer *announcer
+	nilAnnouncer.announce("ignored")
+
+	a := newAnnouncer()
+	for _, line := range []string{"Favorites tab", " Playing Jazz FM ", "Playing Jazz FM", "", "Paused"} {
+		a.announce(line)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var printed []string
+	a.run(ctx, func(line string) {
+		printed = append(printed, line)
+		if len(printed) == 3 {
+			cancel()
+		}
+	})
+	want := []string{"Favorites tab", "Playing Jazz FM", "Paused"}
+	if !slices.Equal(printed, want) {
+		t.Errorf("printed %q, want %q", printed, want)
+	}
+}
@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"context"
+	"image"
+	"log/slog"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/artwork"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	artCols = 20
+	artRows = 10
+)
+
+// renderArtwork encodes the image for the terminal, slow enough to be cached by the views.
+var renderArtwork = artwork.Render
+
+// artRender is an image rendered, with what it was rendered for.
+type artRender struct {
+	stationUuid string
+	// version is the one of the images when rendered
+	version    int
+	cols, rows int
+	proto      artwork.Protocol
+	out        string
+}
+
+// artworkModel holds the image displayed for the playing station:
+// the cover of the current song when known, otherwise the station favicon.
+// The favicon of a station viewed in the info view is held separately.
+type artworkModel struct {
+	cfg     *config.Value
+	fetcher *artwork.Fetcher
+	proto   artwork.Protocol
+
+	stationUuid string
+	favicon     string
+	url         string
+	img         image.Image
+	// fileUrl is the file url of the cached image, shown to the MPRIS clients instead of its remote url
+	fileUrl string
+
+	detailUuid string
+	detailImg  image.Image
+
+	// version changes with the images, for the render of the previous ones to be dropped
+	version int
+	// rendered is the last image rendered, the render resizing and encoding it on every frame otherwise
+	rendered artRender
+	// shown is whether the last frame showed the image, kitty keeping it on screen until deleted
+	shown bool
+}
+
+func newArtworkModel(cfg *config.Value) *artworkModel {
+	dir := ""
+	if cacheDir, err := config.GetOrCreateCacheDir(); err == nil {
+		dir = filepath.Join(cacheDir, artwork.CacheSubDir)
+	}
+	return &artworkModel{
+		cfg:     cfg,
+		fetcher: artwork.NewFetcher(dir),
+		proto:   artwork.Detect(),
+	}
+}
+
+// reset is called when a new station starts playing.
+func (a *artworkModel) reset(stationUuid, favicon string) {
+	a.stationUuid = stationUuid
+	a.favicon = favicon
+	a.url = ""
+	a.img = nil
+	a.fileUrl = ""
+	a.version++
+}
+
+// fetchCmd loads the image displayed for the playing station.
+func (a *artworkModel) fetchCmd(url string) tea.Cmd {
+	if !a.cfg.Artwork || a.cfg.LowBandwidth || url == "" || url == a.url {
+		return nil
+	}
+	return a.getCmd(a.stationUuid, url, false)
+}
+
+// detailCmd loads the favicon of a station opened in the info view. Favicons are only
+// displayed with a graphics protocol, they are too small to be recognizable as ASCII art.
+func (a *artworkModel) detailCmd(s browser.Station) tea.Cmd {
+	a.detailUuid = s.Stationuuid
+	a.detailImg = nil
+	a.version++
+	if !a.cfg.Artwork || a.cfg.LowBandwidth || a.proto == artwork.ASCII || s.Stationuuid == a.stationUuid {
+		return nil
+	}
+	return a.getCmd(s.Stationuuid, s.Favicon, true)
+}
+
+func (a *artworkModel) getCmd(stationUuid, url string, detail bool) tea.Cmd {
+	return func() tea.Msg {
+		img, err := a.fetcher.Get(context.Background(), url)
+		if err != nil {
+			slog.With("method", "ui.artworkModel.getCmd").Info("", "url", url, "error", err)
+			return nil
+		}
+		return artworkMsg{stationUuid: stationUuid, url: url, img: img, detail: detail}
+	}
+}
+
+func (a *artworkModel) update(msg artworkMsg) {
+	switch {
+	case msg.detail && msg.stationUuid == a.detailUuid:
+		a.detailImg = msg.img
+		a.version++
+	case !msg.detail && msg.stationUuid == a.stationUuid:
+		a.url = msg.url
+		a.img = msg.img
+		a.fileUrl = a.fetcher.ArtUrl(msg.url)
+		a.version++
+	}
+}
+
+// artUrl returns the file url of the cover or the logo cached for the playing station, empty if none was loaded.
+func (a *artworkModel) artUrl(stationUuid string) string {
+	if stationUuid != a.stationUuid || !a.cfg.Artwork {
+		return ""
+	}
+	return a.fileUrl
+}
+
+// image returns the image of the station, nil if it wasn't loaded or the artwork is disabled.
+func (a *artworkModel) image(stationUuid string) image.Image {
+	img := a.img
+	if stationUuid != a.stationUuid {
+		img = nil
+		if stationUuid == a.detailUuid {
+			img = a.detailImg
+		}
+	}
+	if !a.cfg.Artwork {
+		return nil
+	}
+	return img
+}
+
+// view renders the image of the station, if it was loaded, rendering it again only when the image changes.
+func (a *artworkModel) view(stationUuid string) string {
+	img := a.image(stationUuid)
+	if img == nil {
+		return ""
+	}
+	r := &a.rendered
+	want := artRender{stationUuid: stationUuid, version: a.version, cols: artCols, rows: artRows, proto: a.proto}
+	if want.out = r.out; want != *r {
+		want.out = renderArtwork(img, artCols, artRows, a.proto)
+		*r = want
+	}
+	return r.out
+}
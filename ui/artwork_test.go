@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"image"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dancnb/sonicradio/artwork"
+	"github.com/dancnb/sonicradio/config"
+)
+
+func Test_artworkView(t *testing.T) {
+	renders := 0
+	prev := renderArtwork
+	renderArtwork = func(img image.Image, cols, rows int, proto artwork.Protocol) string {
+		renders++
+		return "art"
+	}
+	t.Cleanup(func() { renderArtwork = prev })
+
+	a := &artworkModel{cfg: &config.Value{Artwork: true}, fetcher: artwork.NewFetcher(""), proto: artwork.Kitty}
+	a.reset("uuid-1", "")
+	if a.view("uuid-1") != "" || renders != 0 {
+		t.Fatal("expected nothing rendered before the image is loaded")
+	}
+	a.update(artworkMsg{stationUuid: "uuid-1", url: "http://img", img: image.NewRGBA(image.Rect(0, 0, 4, 4))})
+	for range 3 {
+		if a.view("uuid-1") != "art" {
+			t.Fatal("expected the image rendered")
+		}
+	}
+	if renders != 1 {
+		t.Errorf("rendered %d times, want once for the same image", renders)
+	}
+	a.update(artworkMsg{stationUuid: "uuid-1", url: "http://cover", img: image.NewRGBA(image.Rect(0, 0, 4, 4))})
+	a.view("uuid-1")
+	if renders != 2 {
+		t.Errorf("rendered %d times, want again for a new image", renders)
+	}
+}
+
+func Test_artworkArtUrl(t *testing.T) {
+	f := artwork.NewFetcher(t.TempDir())
+	a := &artworkModel{cfg: &config.Value{Artwork: true}, fetcher: f}
+	a.reset("uuid-1", "http://station/favicon.png")
+	if err := os.WriteFile(f.Path("http://station/favicon.png"), []byte("png"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a.update(artworkMsg{stationUuid: "uuid-1", url: "http://station/favicon.png", img: image.NewRGBA(image.Rect(0, 0, 4, 4))})
+	if u := a.artUrl("uuid-1"); !strings.HasPrefix(u, "file://") {
+		t.Errorf("got art url %q, want the cached file", u)
+	}
+	if u := a.artUrl("uuid-2"); u != "" {
+		t.Errorf("got art url %q for another station", u)
+	}
+	a.reset("uuid-2", "")
+	if u := a.artUrl("uuid-2"); u != "" {
+		t.Errorf("got art url %q before the art of the new station is loaded", u)
+	}
+}
+
+func Test_e2eKittyClear(t *testing.T) {
+	d := newUIDriver(t)
+	d.m.art.proto = artwork.Kitty
+	if strings.Contains(d.m.View(), artwork.ClearKitty()) {
+		t.Error("expected no clear while no image was shown")
+	}
+	d.m.art.shown = true
+	if !strings.Contains(d.m.View(), artwork.ClearKitty()) {
+		t.Error("expected a clear once the image is hidden")
+	}
+	if strings.Contains(d.m.View(), artwork.ClearKitty()) {
+		t.Error("expected the clear only on the frame hiding the image")
+	}
+}
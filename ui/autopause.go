@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/audiosink"
+	"github.com/dancnb/sonicradio/mpris"
+)
+
+const (
+	pausedOnLockMsg   = "Paused while the session is locked"
+	pausedOnUnplugMsg = "Paused, the headphones were unplugged"
+)
+
+// autoPause is the reason the playback was paused by itself, to resume it as the config allows.
+type autoPause uint8
+
+const (
+	noAutoPause autoPause = iota
+	lockPause
+	unplugPause
+)
+
+// startAutoPause follows the lock of the session and the audio output, to pause on them as set in the
+// config file.
+func (m *Model) startAutoPause(ctx context.Context, progr *tea.Program) {
+	log := slog.With("method", "ui.Model.startAutoPause")
+	if m.cfg.PauseOnLock {
+		w, err := mpris.WatchLock(func(locked bool) {
+			progr.Send(sessionLockMsg{locked: locked})
+		})
+		if err != nil {
+			log.Info("session lock unavailable", "error", err)
+		} else {
+			m.lockWatcher = w
+		}
+	}
+	if m.cfg.PauseOnUnplug && audiosink.Available() {
+		go func() {
+			err := audiosink.WatchOutput(ctx, func(prev, cur audiosink.Output) {
+				progr.Send(outputChangeMsg{prev: prev, cur: cur})
+			})
+			if err != nil {
+				log.Info("audio output changes unavailable", "error", err)
+			}
+		}()
+	}
+}
+
+// sessionLocked pauses the playback when the session locks, and resumes it on unlock if the config allows.
+func (m *Model) sessionLocked(msg sessionLockMsg) tea.Cmd {
+	slog.With("method", "ui.Model.sessionLocked").Info("session lock", "locked", msg.locked)
+	if msg.locked {
+		return m.autoPauseCmd(lockPause, pausedOnLockMsg)
+	}
+	if m.cfg.AutoResume.OnUnlock() {
+		return m.autoResumeCmd(lockPause)
+	}
+	return nil
+}
+
+// outputChanged pauses the playback when the output switches from the headphones to another, and
+// resumes it when they're back if the config allows.
+func (m *Model) outputChanged(msg outputChangeMsg) tea.Cmd {
+	slog.With("method", "ui.Model.outputChanged").Info("audio output changed", "from", msg.prev, "to", msg.cur)
+	switch {
+	case msg.prev.Headphones() && !msg.cur.Headphones():
+		return m.autoPauseCmd(unplugPause, pausedOnUnplugMsg)
+	case msg.cur.Headphones() && m.cfg.AutoResume.OnReplug():
+		return m.autoResumeCmd(unplugPause)
+	}
+	return nil
+}
+
+func (m *Model) autoPauseCmd(reason autoPause, status string) tea.Cmd {
+	m.delegate.playingMtx.RLock()
+	playing := m.delegate.currPlaying != nil
+	m.delegate.playingMtx.RUnlock()
+	if !playing {
+		return nil
+	}
+	m.autoPaused = reason
+	m.updateStatus(status)
+	return m.delegate.pauseCmd()
+}
+
+// autoResumeCmd resumes the playback paused for the reason, unless it was resumed or paused since by the user.
+func (m *Model) autoResumeCmd(reason autoPause) tea.Cmd {
+	if m.autoPaused != reason {
+		return nil
+	}
+	m.autoPaused = noAutoPause
+
+	m.delegate.playingMtx.RLock()
+	paused := m.delegate.currPlaying == nil && m.delegate.prevPlaying != nil
+	m.delegate.playingMtx.RUnlock()
+	if !paused {
+		return nil
+	}
+	return tea.Batch(m.initSpinner(), m.delegate.resumeCmd())
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dancnb/sonicradio/audiosink"
+	"github.com/dancnb/sonicradio/config"
+)
+
+func Test_e2eAutoPause(t *testing.T) {
+	d := newUIDriver(t, e2eStations(3, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 3 })
+	playing := func() bool {
+		d.m.delegate.playingMtx.RLock()
+		defer d.m.delegate.playingMtx.RUnlock()
+		return d.m.connecting == nil && d.m.delegate.currPlaying != nil
+	}
+	paused := func() bool {
+		d.m.delegate.playingMtx.RLock()
+		defer d.m.delegate.playingMtx.RUnlock()
+		return d.m.delegate.currPlaying == nil && d.m.delegate.prevPlaying != nil
+	}
+	d.keys("enter")
+	d.waitFor("the station to play", playing)
+
+	d.send(sessionLockMsg{locked: true})
+	d.waitFor("the pause on lock", paused)
+	if !strings.Contains(d.view, pausedOnLockMsg) {
+		t.Errorf("expected the pause shown:\n%s", d.view)
+	}
+	d.send(sessionLockMsg{locked: false})
+	if d.m.autoPaused != lockPause || !paused() {
+		t.Fatal("expected the playback kept paused without an auto resume policy")
+	}
+	d.m.cfg.AutoResume = config.ResumeUnlock
+	d.send(sessionLockMsg{locked: true})
+	d.send(sessionLockMsg{locked: false})
+	d.waitFor("the resume on unlock", playing)
+
+	headphones := audiosink.Output{Sink: "alsa_output.pci", Port: "analog-output-headphones"}
+	speakers := audiosink.Output{Sink: "alsa_output.pci", Port: "analog-output-speaker"}
+	d.send(outputChangeMsg{prev: headphones, cur: speakers})
+	d.waitFor("the pause on unplug", paused)
+	d.send(outputChangeMsg{prev: speakers, cur: headphones})
+	if !paused() {
+		t.Fatal("expected the playback kept paused, resumed on unlock only")
+	}
+
+	d.m.cfg.AutoResume = config.ResumeAlways
+	d.keys(" ")
+	d.waitFor("the resume by the user", playing)
+	d.send(outputChangeMsg{prev: headphones, cur: speakers})
+	d.waitFor("the pause on unplug", paused)
+	// paused and resumed by the user since, the replug leaves the playback alone
+	d.keys(" ")
+	d.waitFor("the resume by the user", playing)
+	d.keys(" ")
+	d.waitFor("the pause by the user", paused)
+	d.send(outputChangeMsg{prev: speakers, cur: headphones})
+	if !paused() {
+		t.Fatal("expected the pause of the user kept")
+	}
+
+	d.keys(" ")
+	d.waitFor("the resume by the user", playing)
+	d.send(outputChangeMsg{prev: headphones, cur: speakers})
+	d.waitFor("the pause on unplug", paused)
+	d.send(outputChangeMsg{prev: speakers, cur: headphones})
+	d.waitFor("the resume on replug", playing)
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/diskquota"
+)
+
+const (
+	bandwidthWarningMsg = "Data cap almost reached: %s of %s this month"
+	bandwidthCapMsg     = "Data cap reached: %s of %s this month"
+	bandwidthStoppedMsg = "Playback stopped, data cap of %s reached"
+)
+
+// trackBandwidth counts the bytes streamed by the sessions playing locally since the last tick,
+// estimated from the bitrate of their stations. The cast targets stream on their own, but their
+// time is counted with the local one in the listening time of the languages. The bytes relayed to the
+// listeners on the LAN are counted as well, and stopped with the playback when the cap is reached.
+func (m *Model) trackBandwidth(now time.Time) tea.Cmd {
+	elapsed := now.Sub(m.bandwidthTick)
+	m.bandwidthTick = now
+	if elapsed <= 0 || elapsed > 2*config.BandwidthTickInterval {
+		// the first tick, or a suspended system
+		return nil
+	}
+
+	m.saveSession()
+	for _, s := range m.sessions.sessions {
+		if s.currPlaying == nil {
+			continue
+		}
+		m.cfg.AddListening(s.currPlaying.Language, elapsed)
+		if s.player.Output() != nil {
+			continue
+		}
+		bitrate := s.currPlaying.Bitrate
+		if s == m.sessions.sessions[m.sessions.active] {
+			// the bitrate measured by the player, the listed one is often stale
+			if live := m.live.get(s.currPlaying.Stationuuid); live != nil && live.BitrateKbps > 0 {
+				bitrate = int64(live.BitrateKbps)
+			}
+		}
+		n := config.StreamBytes(bitrate, elapsed)
+		s.streamed += n
+		m.cfg.AddBandwidth(now, n)
+	}
+	if m.relay != nil {
+		// the stream of the relay listeners, measured
+		m.cfg.AddBandwidth(now, m.relay.Received())
+	}
+	return m.checkBandwidthCap(now)
+}
+
+// checkBandwidthCap warns when the monthly cap is near and stops the playback when it's reached, if enabled.
+func (m *Model) checkBandwidthCap(now time.Time) tea.Cmd {
+	prev := m.bandwidthWarning
+	defer func() {
+		if m.bandwidthWarning != prev {
+			m.announcer.announce(m.bandwidthWarning)
+		}
+	}()
+	m.bandwidthWarning = ""
+	if m.cfg.BandwidthCapMB <= 0 {
+		return nil
+	}
+	capBytes := int64(m.cfg.BandwidthCapMB) * diskquota.MB
+	used := m.cfg.MonthBandwidth(now)
+	usedView, capView := diskquota.FormatSize(used), diskquota.FormatSize(capBytes)
+	switch {
+	case used >= capBytes && m.cfg.BandwidthStop:
+		return m.stopAllSessions(fmt.Sprintf(bandwidthStoppedMsg, capView))
+	case used >= capBytes:
+		m.bandwidthWarning = fmt.Sprintf(bandwidthCapMsg, usedView, capView)
+	case used*100 >= capBytes*config.BandwidthWarnPct:
+		m.bandwidthWarning = fmt.Sprintf(bandwidthWarningMsg, usedView, capView)
+	}
+	return nil
+}
+
+// stopAllSessions stops the playback of every session, the active one showing the status.
+func (m *Model) stopAllSessions(status string) tea.Cmd {
+	log := slog.With("method", "ui.Model.stopAllSessions")
+	for i, s := range m.sessions.sessions {
+		if i == m.sessions.active || s.currPlaying == nil {
+			continue
+		}
+		if err := s.player.Stop(); err != nil {
+			log.Error("player stop", "session", s.name, "error", err)
+			continue
+		}
+		s.currPlaying = nil
+		s.prevPlaying = nil
+	}
+	return m.delegate.stopCmd(status)
+}
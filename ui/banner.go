@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/banner"
+	"github.com/dancnb/sonicradio/ui/styles"
+)
+
+// bannerVisible reports whether the banner is shown under the playing station, never in plain text.
+func (m *Model) bannerVisible() bool {
+	return m.cfg.Banner && !m.cfg.ScreenReaderMode()
+}
+
+// toggleBanner shows or hides the banner, the tabs being resized to the new header.
+func (m *Model) toggleBanner() tea.Cmd {
+	m.cfg.Banner = !m.cfg.Banner
+	m.headerHeight = strings.Count(m.headerView(m.width), "\n")
+	return m.resizeTabs()
+}
+
+// bannerView returns the song title, or the station name before the first song, in large letters. The
+// lines are blank while nothing plays, for the header not to change its height.
+func (m *Model) bannerView(width int) string {
+	m.delegate.playingMtx.RLock()
+	d := m.playingData()
+	m.delegate.playingMtx.RUnlock()
+
+	text := d.Title
+	if text == "" {
+		text = d.Song
+	}
+	if text == "" {
+		text = d.Station
+	}
+	gap := strings.Repeat(" ", styles.HeaderPadDist)
+	lines := banner.Render(text, max(0, width-4*styles.HeaderPadDist), styles.BannerBlocks)
+	for i, l := range lines {
+		lines[i] = gap + m.style.PrimaryColorStyle.Render(l)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dancnb/sonicradio/banner"
+	"github.com/dancnb/sonicradio/ui/styles"
+)
+
+func Test_e2eBanner(t *testing.T) {
+	d := newUIDriver(t, e2eStations(3, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 3 })
+	d.keys("enter")
+	d.waitFor("the station to play", func() bool { return d.m.connecting == nil && d.m.delegate.currPlaying != nil })
+	height := d.m.headerHeight
+
+	d.keys("T")
+	if !d.m.cfg.Banner || d.m.headerHeight != height+banner.Height+1 {
+		t.Fatalf("header of %d lines, want the %d of the banner more", d.m.headerHeight-height, banner.Height+1)
+	}
+	want := banner.Render("Jazz 0", 100, styles.BannerBlocks)
+	for _, l := range want {
+		if !strings.Contains(d.view, l) {
+			t.Fatalf("expected the station name in large letters:\n%s", d.view)
+		}
+	}
+
+	d.keys("T")
+	if d.m.cfg.Banner || d.m.headerHeight != height || strings.Contains(d.view, want[0]) {
+		t.Errorf("expected the banner hidden:\n%s", d.view)
+	}
+}
@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/crash"
+)
+
+const (
+	tabFailedMsg   = "The %s tab failed: %v"
+	tabReportMsg   = "The crash report is saved in %s, please attach it to an issue."
+	tabNoReportMsg = "The crash report could not be saved: %v"
+	tabRetryMsg    = "The other tabs keep working. Retry the tab, or go to another one."
+)
+
+// tabFault is the panic recovered from a tab, shown in place of the tab until it's retried.
+type tabFault struct {
+	crash  *crash.Crash
+	report string
+	err    error
+}
+
+// tabBoundary keeps the panics of a tab from quitting the app: the tab shows its error instead, the
+// other tabs going on. It's kept by pointer for the panics of View to be recorded too.
+type tabBoundary struct {
+	faults map[uiTabIndex]*tabFault
+	keymap faultKeymap
+	help   help.Model
+}
+
+func newTabBoundary(m *Model) *tabBoundary {
+	h := help.New()
+	h.ShortSeparator = "   "
+	h.Styles = m.style.HelpStyles()
+	return &tabBoundary{
+		faults: make(map[uiTabIndex]*tabFault),
+		keymap: newFaultKeymap(),
+		help:   h,
+	}
+}
+
+// recoverTab records the panic of the tab, if any, then calls onPanic. It must be deferred.
+func (m *Model) recoverTab(ix uiTabIndex, onPanic func()) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	c := crash.Recovered(r)
+	f := &tabFault{crash: c}
+	f.report, f.err = c.Write(m.cfg)
+	name := strings.TrimSpace(ix.String())
+	slog.With("method", "ui.Model.recoverTab").Error("tab panic", "tab", name, "panic", r, "report", f.report, "error", f.err, "stack", string(c.Stack))
+	m.boundary.faults[ix] = f
+	m.announcer.announce(fmt.Sprintf(tabFailedMsg, name, r))
+	onPanic()
+}
+
+func (m *Model) initTab(ix uiTabIndex) (cmd tea.Cmd) {
+	defer m.recoverTab(ix, func() { cmd = nil })
+	return m.tabs[ix].Init(m)
+}
+
+func (m *Model) updateTab(ix uiTabIndex, msg tea.Msg) (res tea.Model, cmd tea.Cmd) {
+	defer m.recoverTab(ix, func() { res, cmd = m, nil })
+	return m.tabs[ix].Update(m, msg)
+}
+
+// viewTab returns the error of the tab instead of its view, once it panicked.
+func (m *Model) viewTab(ix uiTabIndex) (view string) {
+	if f := m.boundary.faults[ix]; f != nil {
+		return m.faultView(ix, f)
+	}
+	defer m.recoverTab(ix, func() { view = m.faultView(ix, m.boundary.faults[ix]) })
+	return m.tabs[ix].View()
+}
+
+// updateFault handles the keys of the active tab after it panicked, reporting false for the other keys,
+// which the tab doesn't get until retried.
+func (m *Model) updateFault(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.boundary.faults[m.activeTabIdx] == nil {
+		return nil, false
+	}
+	k := m.boundary.keymap
+	switch {
+	case key.Matches(msg, k.retry):
+		delete(m.boundary.faults, m.activeTabIdx)
+		return nil, true
+	case key.Matches(msg, k.nextTab):
+		return m.toTab((m.activeTabIdx + 1) % uiTabIndex(len(m.tabs))), true
+	case key.Matches(msg, k.prevTab):
+		return m.toTab((m.activeTabIdx + uiTabIndex(len(m.tabs)) - 1) % uiTabIndex(len(m.tabs))), true
+	}
+	return nil, false
+}
+
+// toTab goes to the tab, as its keys do.
+func (m *Model) toTab(ix uiTabIndex) tea.Cmd {
+	switch ix {
+	case favoriteTabIx:
+		m.toFavoritesTab()
+	case browseTabIx:
+		m.toBrowseTab()
+	case historyTabIx:
+		m.toHistoryTab()
+	case recordingsTabIx:
+		m.toRecordingsTab()
+	case settingsTabIx:
+		return m.toSettingsTab()
+	}
+	return nil
+}
+
+func (m *Model) faultView(ix uiTabIndex, f *tabFault) string {
+	width, height := m.tabWidth(), m.totHeight-m.headerHeight
+	h, v := m.style.DocStyle.GetFrameSize()
+	text := m.style.ViewStyle.Width(max(0, width-h))
+
+	var b strings.Builder
+	b.WriteString("\n")
+	failed := fmt.Sprintf(tabFailedMsg, strings.TrimSpace(ix.String()), f.crash.Value)
+	b.WriteString(text.Render(m.style.StatusErrorStyle.Render(" " + failed + " ")))
+	b.WriteString("\n\n")
+	if f.err != nil {
+		b.WriteString(text.Render(fmt.Sprintf(tabNoReportMsg, f.err)))
+	} else {
+		b.WriteString(text.Render(fmt.Sprintf(tabReportMsg, f.report)))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(text.Render(tabRetryMsg))
+
+	m.boundary.help.Width = max(0, width-h)
+	help := m.style.HelpStyle.Render(m.boundary.help.View(m.boundary.keymap))
+	for i := lipgloss.Height(b.String()); i < height-v-lipgloss.Height(help); i++ {
+		b.WriteString("\n")
+	}
+	return b.String() + help
+}
+
+type faultKeymap struct {
+	retry   key.Binding
+	nextTab key.Binding
+	prevTab key.Binding
+}
+
+func newFaultKeymap() faultKeymap {
+	return faultKeymap{
+		retry: key.NewBinding(
+			key.WithKeys("enter", "esc"),
+			key.WithHelp("enter/esc", "retry tab"),
+		),
+		nextTab: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "go to next tab"),
+		),
+		prevTab: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "go to prev tab"),
+		),
+	}
+}
+
+func (k faultKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.retry, k.nextTab, k.prevTab}
+}
+
+func (k faultKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
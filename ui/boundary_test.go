@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// panicTab panics in View, then in Update, while failing.
+type panicTab struct {
+	uiTab
+	failing bool
+}
+
+func (t *panicTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if t.failing {
+		panic("update boom")
+	}
+	return t.uiTab.Update(m, msg)
+}
+
+func (t *panicTab) View() string {
+	if t.failing {
+		panic("view boom")
+	}
+	return t.uiTab.View()
+}
+
+func Test_e2eTabBoundary(t *testing.T) {
+	d := newUIDriver(t, e2eStations(3, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 3 })
+	tab := &panicTab{uiTab: d.m.tabs[historyTabIx], failing: true}
+	d.m.tabs[historyTabIx] = tab
+
+	d.keys("H")
+	if d.m.activeTabIdx != historyTabIx || !strings.Contains(d.view, "The History tab failed: view boom") {
+		t.Fatalf("expected the error of the tab:\n%s", d.view)
+	}
+	f := d.m.boundary.faults[historyTabIx]
+	if f == nil || f.err != nil {
+		t.Fatalf("got fault %+v, want the crash report written", f)
+	}
+	if b, err := os.ReadFile(f.report); err != nil || !strings.Contains(string(b), "panic: view boom") {
+		t.Errorf("crash report %q: %v", b, err)
+	}
+
+	d.keys("tab")
+	if d.m.activeTabIdx != recordingsTabIx || strings.Contains(d.view, "tab failed") {
+		t.Errorf("expected the next tab working:\n%s", d.view)
+	}
+	d.keys("shift+tab", "x")
+	if !strings.Contains(d.view, "The History tab failed: view boom") {
+		t.Errorf("expected the error kept, without the keys reaching the tab:\n%s", d.view)
+	}
+
+	tab.failing = false
+	d.keys("esc")
+	if d.m.boundary.faults[historyTabIx] != nil || strings.Contains(d.view, "tab failed") {
+		t.Errorf("expected the tab retried:\n%s", d.view)
+	}
+
+	tab.failing = true
+	d.send(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if f := d.m.boundary.faults[historyTabIx]; f == nil || f.crash.Value != "update boom" {
+		t.Errorf("got fault %+v, want the panic of the update", f)
+	}
+}
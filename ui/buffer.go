@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	lowBufferMsg             = "The buffer keeps running low, the stream may stall: try the low bandwidth mode in the settings"
+	lowBufferLowBandwidthMsg = "The buffer keeps running low, the stream may stall"
+)
+
+// bufferHealth follows the duration of the playing stream buffered ahead by the player, to warn when it
+// keeps running low.
+type bufferHealth struct {
+	stationUuid string
+	// level is the last duration reported, nil if the player doesn't report it
+	level    *time.Duration
+	lowSince time.Time
+	warned   bool
+}
+
+// observe records the duration buffered for the station, telling if it has been under config.BufferLow
+// for config.BufferLowFor, once per station.
+func (b *bufferHealth) observe(uuid string, level *time.Duration, now time.Time) bool {
+	if uuid != b.stationUuid {
+		*b = bufferHealth{stationUuid: uuid}
+	}
+	b.level = level
+	if level == nil || *level >= config.BufferLow {
+		b.lowSince = time.Time{}
+		return false
+	}
+	if b.lowSince.IsZero() {
+		b.lowSince = now
+	}
+	if b.warned || now.Sub(b.lowSince) < config.BufferLowFor {
+		return false
+	}
+	b.warned = true
+	return true
+}
+
+// updateBuffer records the duration buffered for the playing station, warning when it keeps running low.
+func (m *Model) updateBuffer(uuid string, level *time.Duration) {
+	if !m.buffer.observe(uuid, level, time.Now()) {
+		return
+	}
+	if m.cfg.LowBandwidth {
+		m.updateStatusWarn(lowBufferLowBandwidthMsg)
+	} else {
+		m.updateStatusWarn(lowBufferMsg)
+	}
+}
+
+// bufferView returns the duration buffered ahead for the status bar, if enabled in the settings and
+// reported by the player. With reduced motion, it only tells if the buffer is low.
+func (m *Model) bufferView() string {
+	m.delegate.playingMtx.RLock()
+	curr := m.delegate.currPlaying
+	m.delegate.playingMtx.RUnlock()
+	if !m.cfg.BufferHealth || curr == nil || curr.Stationuuid != m.buffer.stationUuid || m.buffer.level == nil {
+		return ""
+	}
+	level := *m.buffer.level
+	if m.cfg.NoMotion() {
+		if level < config.BufferLow {
+			return "buffer low · "
+		}
+		return "buffer ok · "
+	}
+	return fmt.Sprintf("buffer %.1fs · ", level.Seconds())
+}
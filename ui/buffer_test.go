@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+func Test_bufferHealth_observe(t *testing.T) {
+	low, ok := 500*time.Millisecond, 10*time.Second
+	now := time.Now()
+	var b bufferHealth
+	if b.observe("a", &low, now) || b.observe("a", &low, now.Add(config.BufferLowFor-time.Second)) {
+		t.Fatal("warned before the buffer was low for long")
+	}
+	if !b.observe("a", &low, now.Add(config.BufferLowFor)) {
+		t.Fatal("expected a warning once low for long")
+	}
+	if b.observe("a", &low, now.Add(2*config.BufferLowFor)) {
+		t.Error("warned twice for the station")
+	}
+
+	// the low spell starts over when the buffer recovers, and for another station
+	if b.observe("b", &low, now) || b.observe("b", &ok, now.Add(config.BufferLowFor/2)) ||
+		b.observe("b", &low, now.Add(config.BufferLowFor)) {
+		t.Fatal("warned before the buffer was low for long")
+	}
+	if b.observe("b", nil, now.Add(3*config.BufferLowFor)) {
+		t.Error("warned without a buffer reported")
+	}
+}
+
+func Test_e2eBufferHealth(t *testing.T) {
+	d := newUIDriver(t, e2eStations(1, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 1 })
+	d.m.setMetadataPoll(config.MetadataPollManual)
+	d.keys("enter")
+	d.waitFor("the stream to play", func() bool { return d.m.connecting == nil && d.m.delegate.currPlaying != nil })
+
+	// streams without song titles still report their buffer
+	d.player.SetBuffer(12.5)
+	d.keys("U")
+	d.waitFor("the buffer reported", func() bool { return d.m.buffer.level != nil })
+	if strings.Contains(d.view, "buffer 12.5s") {
+		t.Error("buffer shown while disabled")
+	}
+
+	d.m.cfg.BufferHealth = true
+	d.keys("U")
+	d.waitFor("the buffer shown", func() bool { return strings.Contains(d.view, "buffer 12.5s") })
+}
@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/importer"
+	"github.com/dancnb/sonicradio/ui/styles"
+)
+
+const (
+	bulkAddTitle      = "Add favorites"
+	bulkAddHint       = "Paste the station links or uuids, one per line"
+	bulkAddingMsg     = "Looking up %d stations..."
+	bulkAddedMsg      = "Added %d favorites, %d already in the favorites, %d failed"
+	bulkNotFoundMsg   = "not found on radio-browser"
+	bulkNotStationMsg = "not a station link or uuid"
+)
+
+// bulkAddView adds the stations of a pasted list of links and uuids to the favorites, with a summary
+// of the lines which failed.
+type bulkAddView struct {
+	enabled bool
+	style   *styles.Style
+
+	input  textarea.Model
+	cancel context.CancelFunc
+	// summary is the result of the last add, shown until the next one
+	summary string
+	failed  []bulkAddFailure
+
+	keymap bulkAddKeymap
+	help   help.Model
+	width  int
+	height int
+}
+
+type bulkAddFailure struct {
+	line   string
+	reason string
+}
+
+func newBulkAddView(s *styles.Style) *bulkAddView {
+	h := help.New()
+	h.ShortSeparator = "   "
+	h.Styles = s.HelpStyles()
+	input := textarea.New()
+	input.Placeholder = bulkAddHint
+	input.ShowLineNumbers = false
+	input.Prompt = ""
+	input.CharLimit = 0
+	input.MaxHeight = 0
+	input.Cursor.SetMode(styles.CursorMode)
+	return &bulkAddView{
+		style:  s,
+		input:  input,
+		keymap: newBulkAddKeymap(),
+		help:   h,
+	}
+}
+
+func (v *bulkAddView) setSize(width, height int) {
+	h, vf := v.style.DocStyle.GetFrameSize()
+	v.width = width - h
+	v.height = height - vf
+	v.help.Width = v.width
+	v.input.SetWidth(max(0, v.width-styles.HeaderPadDist))
+	v.input.SetHeight(max(1, v.height/2))
+}
+
+func (v *bulkAddView) View() string {
+	var b strings.Builder
+	b.WriteString("\n" + v.style.PrimaryColorStyle.Render(bulkAddTitle) + "\n\n")
+	b.WriteString(v.input.View() + "\n\n")
+	switch {
+	case v.cancel != nil:
+		b.WriteString(v.style.ItalicStyle.Render(fmt.Sprintf(bulkAddingMsg, len(bulkAddLines(v.input.Value())))) + "\n")
+	case v.summary != "":
+		b.WriteString(v.style.SecondaryColorStyle.Render(v.summary) + "\n")
+		for _, f := range v.failed {
+			line := fmt.Sprintf("  %s: %s", f.line, f.reason)
+			b.WriteString(v.style.ItalicStyle.MaxWidth(v.width).Render(line) + "\n")
+		}
+	}
+
+	help := v.style.HelpStyle.Render(v.help.View(&v.keymap))
+	for i := lipgloss.Height(b.String()); i < v.height-lipgloss.Height(help); i++ {
+		b.WriteString("\n")
+	}
+	return b.String() + help
+}
+
+// toggleBulkAdd shows or hides the view, keeping the text pasted.
+func (m *Model) toggleBulkAdd() tea.Cmd {
+	v := m.bulkAdd
+	v.enabled = !v.enabled
+	if !v.enabled {
+		v.input.Blur()
+		return nil
+	}
+	v.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	return v.input.Focus()
+}
+
+func (m *Model) updateBulkAdd(msg tea.Msg) tea.Cmd {
+	v := m.bulkAdd
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, v.keymap.cancel):
+			if v.cancel != nil {
+				v.cancel()
+				v.cancel = nil
+				return nil
+			}
+			return m.toggleBulkAdd()
+		case v.cancel != nil:
+			return nil
+		case key.Matches(msg, v.keymap.add):
+			return m.bulkAddCmd(v.input.Value())
+		case key.Matches(msg, v.keymap.clear):
+			v.input.Reset()
+			v.summary, v.failed = "", nil
+			return nil
+		}
+	}
+	var cmd tea.Cmd
+	v.input, cmd = v.input.Update(msg)
+	return cmd
+}
+
+// bulkAddCmd looks up the stations of the lines on radio-browser, outside of the update.
+func (m *Model) bulkAddCmd(text string) tea.Cmd {
+	lines := bulkAddLines(text)
+	if len(lines) == 0 {
+		return nil
+	}
+	var entries []importer.Entry
+	var failed []bulkAddFailure
+	for _, l := range lines {
+		uuid, streamUrl := browser.ParseStationRef(l)
+		if uuid == "" && streamUrl == "" {
+			failed = append(failed, bulkAddFailure{line: l, reason: bulkNotStationMsg})
+			continue
+		}
+		entries = append(entries, importer.Entry{Uuid: uuid, URL: streamUrl})
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.bulkAdd.cancel = cancel
+	b := m.browser
+	return func() tea.Msg {
+		defer cancel()
+		uuids, missing, err := importer.Resolve(ctx, b, entries)
+		for _, e := range missing {
+			failed = append(failed, bulkAddFailure{line: e.String(), reason: bulkNotFoundMsg})
+		}
+		return bulkAddMsg{uuids: uuids, failed: failed, err: err}
+	}
+}
+
+// onBulkAdd adds the stations found to the favorites, reloading them.
+func (m *Model) onBulkAdd(msg bulkAddMsg) tea.Cmd {
+	v := m.bulkAdd
+	if v.cancel == nil {
+		// cancelled
+		return nil
+	}
+	v.cancel = nil
+	if msg.err != nil {
+		m.updateStatusError(msg.err.Error())
+		return nil
+	}
+	added := 0
+	for _, uuid := range msg.uuids {
+		if m.cfg.InsertFavorite(uuid, len(m.cfg.Favorites)) {
+			added++
+		}
+	}
+	v.summary = fmt.Sprintf(bulkAddedMsg, added, len(msg.uuids)-added, len(msg.failed))
+	v.failed = msg.failed
+	m.updateStatus(v.summary)
+	if added == 0 {
+		return nil
+	}
+	return m.favoritesReqCmd
+}
+
+// bulkAddLines returns the lines of the text which aren't blank.
+func bulkAddLines(text string) []string {
+	var res []string
+	for _, l := range strings.Split(text, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+type bulkAddKeymap struct {
+	add    key.Binding
+	clear  key.Binding
+	cancel key.Binding
+}
+
+func newBulkAddKeymap() bulkAddKeymap {
+	return bulkAddKeymap{
+		add: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("ctrl+s", "add to favorites"),
+		),
+		clear: key.NewBinding(
+			key.WithKeys("ctrl+l"),
+			key.WithHelp("ctrl+l", "clear"),
+		),
+		cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "close"),
+		),
+	}
+}
+
+func (k *bulkAddKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.add, k.clear, k.cancel}
+}
+
+func (k *bulkAddKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
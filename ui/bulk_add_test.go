@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func Test_bulkAddLines(t *testing.T) {
+	got := bulkAddLines(" a \n\n\tb\r\n  \n")
+	if !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("got %q, want the lines which aren't blank", got)
+	}
+}
+
+func Test_e2eBulkAdd(t *testing.T) {
+	stations := e2eStations(3, "Jazz", "jazz")
+	stations[0].Stationuuid = "9617a958-0601-11e8-ae97-52543be04c81"
+	d := newUIDriver(t, stations...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 3 })
+	d.m.cfg.ToggleFavorite("jazz-2")
+
+	d.keys("V")
+	if !d.m.bulkAdd.enabled || !strings.Contains(d.view, bulkAddHint) {
+		t.Fatalf("expected the bulk add view:\n%s", d.view)
+	}
+	pasted := strings.Join([]string{
+		"9617A958-0601-11E8-AE97-52543BE04C81",
+		"http://stream.example.com/jazz/1",
+		"",
+		"http://stream.example.com/jazz/2",
+		"https://other.example.org/live",
+		"not a link",
+	}, "\n")
+	d.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(pasted), Paste: true})
+	d.keys("ctrl+s")
+	d.waitFor("the lookup", func() bool { return d.m.bulkAdd.cancel == nil })
+
+	want := []string{"jazz-2", "9617a958-0601-11e8-ae97-52543be04c81", "jazz-1"}
+	if !slices.Equal(d.m.cfg.Favorites, want) {
+		t.Errorf("favorites %v, want %v", d.m.cfg.Favorites, want)
+	}
+	if summary := "Added 2 favorites, 1 already in the favorites, 2 failed"; d.m.bulkAdd.summary != summary {
+		t.Errorf("summary %q, want %q", d.m.bulkAdd.summary, summary)
+	}
+	for _, line := range []string{"not a link: " + bulkNotStationMsg, "https://other.example.org/live: " + bulkNotFoundMsg} {
+		if !strings.Contains(d.view, line) {
+			t.Errorf("expected the failure %q:\n%s", line, d.view)
+		}
+	}
+	d.waitFor("the favorites reloaded", func() bool {
+		return len(d.m.tabs[favoriteTabIx].(stationTab).Stations().list.Items()) == 3
+	})
+
+	d.keys("esc")
+	if d.m.bulkAdd.enabled {
+		t.Error("expected the view closed")
+	}
+}
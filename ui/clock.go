@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clockFormats are the layouts of the status bar clock offered in the settings,
+// any other layout can be set in the config file.
+var clockFormats = []string{"15:04", "15:04:05", "3:04 PM"}
+
+// updateUptime follows the station streaming in the active session. The uptime is kept while the same
+// station is reconnected or the sessions switched, and starts over when the station changes or stops.
+func (m *Model) updateUptime(now time.Time) tea.Cmd {
+	s := m.sessions.sessions[m.sessions.active]
+	m.delegate.playingMtx.RLock()
+	curr := m.delegate.currPlaying
+	m.delegate.playingMtx.RUnlock()
+	switch {
+	case curr == nil:
+		s.uptimeUuid = ""
+		s.uptimeSince = time.Time{}
+	case curr.Stationuuid != s.uptimeUuid:
+		s.uptimeUuid = curr.Stationuuid
+		s.uptimeSince = now
+	}
+	return nil
+}
+
+// clockView returns the stream uptime and the local time for the status bar, as enabled in the settings.
+func (m *Model) clockView(now time.Time) string {
+	var res string
+	if s := m.sessions.sessions[m.sessions.active]; m.cfg.StreamUptime && !s.uptimeSince.IsZero() {
+		up := now.Sub(s.uptimeSince)
+		if m.cfg.NoMotion() {
+			res += fmt.Sprintf("up %02d:%02d · ", int(up.Hours()), int(up.Minutes())%60)
+		} else {
+			res += fmt.Sprintf("up %02d:%02d:%02d · ", int(up.Hours()), int(up.Minutes())%60, int(up.Seconds())%60)
+		}
+	}
+	if m.cfg.ClockFormat != "" {
+		if m.cfg.LowRefresh() {
+			// the seconds stand still, not to redraw the status bar every second
+			now = now.Truncate(time.Minute)
+		}
+		res += now.Format(m.cfg.ClockFormat) + " · "
+	}
+	return res
+}
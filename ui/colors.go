@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/muesli/termenv"
+)
+
+var (
+	colorModeProfiles = map[config.ColorMode]termenv.Profile{
+		config.ColorTrue: termenv.TrueColor,
+		config.Color256:  termenv.ANSI256,
+		config.Color16:   termenv.ANSI,
+		config.ColorNone: termenv.Ascii,
+	}
+	profileNames = map[termenv.Profile]string{
+		termenv.TrueColor: "truecolor",
+		termenv.ANSI256:   "256",
+		termenv.ANSI:      "16",
+		termenv.Ascii:     "none",
+	}
+
+	// truecolorTerms are the terminals with the 24-bit colors which don't tell it by COLORTERM
+	truecolorTerms = []string{"xterm-ghostty", "alacritty", "foot", "foot-extra", "contour", "rio"}
+	// ansiTermPrefixes are the terminals with at least the 16 colors which don't tell it by TERM
+	ansiTermPrefixes = []string{"xterm", "screen", "tmux", "rxvt", "putty", "cygwin", "konsole", "gnome", "vte"}
+)
+
+// setColorProfile sets the colors of the view: the ones detected for the terminal unless -color is set,
+// at most the 16 ANSI colors of the shortest escape sequences with the low refresh.
+func setColorProfile(cfg *config.Value) termenv.Profile {
+	detected := detectColorProfile()
+	p := detected
+	if forced, ok := colorModeProfiles[config.Color()]; ok {
+		p = forced
+	} else if cfg.LowRefresh() {
+		// the profiles with fewer colors are the greater ones
+		p = max(p, termenv.ANSI)
+	}
+	slog.With("method", "ui.setColorProfile").Info("colors", "detected", profileNames[detected], "used", profileNames[p])
+	lipgloss.SetColorProfile(p)
+	return p
+}
+
+// detectColorProfile returns the colors of the terminal, as detected by termenv from the environment,
+// or from the TERM of the terminals it doesn't know.
+func detectColorProfile() termenv.Profile {
+	p := lipgloss.ColorProfile()
+	if p != termenv.Ascii || os.Getenv("NO_COLOR") != "" {
+		return p
+	}
+	if fi, err := os.Stdout.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return p
+	}
+	return termProfile(os.Getenv("TERM"))
+}
+
+// termProfile returns the colors of the terminal named by TERM, none if it's unknown.
+func termProfile(term string) termenv.Profile {
+	if strings.HasSuffix(term, "-direct") || slices.Contains(truecolorTerms, term) {
+		return termenv.TrueColor
+	}
+	for _, prefix := range ansiTermPrefixes {
+		if strings.HasPrefix(term, prefix) {
+			return termenv.ANSI
+		}
+	}
+	return termenv.Ascii
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/muesli/termenv"
+)
+
+func Test_termProfile(t *testing.T) {
+	tests := []struct {
+		term string
+		want termenv.Profile
+	}{
+		{"xterm", termenv.ANSI},
+		{"screen", termenv.ANSI},
+		{"rxvt-unicode", termenv.ANSI},
+		{"xterm-direct", termenv.TrueColor},
+		{"foot", termenv.TrueColor},
+		{"alacritty", termenv.TrueColor},
+		{"dumb", termenv.Ascii},
+		{"vt100", termenv.Ascii},
+		{"", termenv.Ascii},
+	}
+	for _, tt := range tests {
+		if got := termProfile(tt.term); got != tt.want {
+			t.Errorf("termProfile(%q)=%v, want %v", tt.term, got, tt.want)
+		}
+	}
+}
+
+func Test_setColorProfile(t *testing.T) {
+	// the test output is not a terminal
+	t.Setenv("TERM", "xterm-256color")
+	t.Cleanup(func() {
+		_ = flag.Set("color", "auto")
+		lipgloss.SetColorProfile(termenv.Ascii)
+	})
+
+	cfg := &config.Value{RenderProfile: config.RenderLow}
+	if got := setColorProfile(cfg); got != termenv.Ascii {
+		t.Errorf("got %v, want no colors kept with the low refresh", got)
+	}
+	if err := flag.Set("color", "256"); err != nil {
+		t.Fatal(err)
+	}
+	if got := setColorProfile(cfg); got != termenv.ANSI256 || lipgloss.ColorProfile() != termenv.ANSI256 {
+		t.Errorf("got %v, want the 256 colors of -color", got)
+	}
+}
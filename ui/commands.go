@@ -1,12 +1,14 @@
 package ui
 
 import (
-	"fmt"
+	"context"
 	"log/slog"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dancnb/sonicradio/browser"
 	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/metadata"
 )
 
 func (m *Model) favoritesReqCmd() tea.Msg {
@@ -19,7 +21,7 @@ func (m *Model) favoritesReqCmd() tea.Msg {
 	stations, err := m.browser.GetStations(m.cfg.Favorites)
 	res := favoritesStationRespMsg{stations: stations}
 	if err != nil {
-		res.statusMsg = statusMsg(err.Error())
+		res.errorMsg = errorMsg(err.Error())
 	} else if len(stations) == 0 {
 		res.viewMsg = noStationsFound
 	}
@@ -27,10 +29,18 @@ func (m *Model) favoritesReqCmd() tea.Msg {
 }
 
 func (m *Model) topStationsCmd() tea.Msg {
-	stations, err := m.browser.TopStations()
+	var stations []browser.Station
+	var err error
+	if lang := strings.TrimSpace(m.cfg.LearnLanguage); lang != "" {
+		params := browser.DefaultSearchParams()
+		params.Language = lang
+		stations, err = m.browser.Search(params)
+	} else {
+		stations, err = m.browser.TopStations()
+	}
 	res := topStationsRespMsg{stations: stations}
 	if err != nil {
-		res.statusMsg = statusMsg(err.Error())
+		res.errorMsg = errorMsg(err.Error())
 	} else if len(stations) == 0 {
 		res.viewMsg = noStationsFound
 	}
@@ -38,9 +48,10 @@ func (m *Model) topStationsCmd() tea.Msg {
 }
 
 func (m *Model) volumeCmd(up bool) tea.Cmd {
+	maxVol := m.maxVolume()
 	return func() tea.Msg {
 		currVol := m.cfg.GetVolume()
-		newVol := currVol + config.VolumeStep
+		newVol := min(currVol+config.VolumeStep, maxVol)
 		if !up {
 			newVol = currVol - config.VolumeStep
 		}
@@ -84,21 +95,60 @@ func (m *Model) seekCmd(amtSec int) tea.Cmd {
 
 func (m *Model) playStationCmd(selStation browser.Station) tea.Cmd {
 	m.songTitle = ""
+	m.songChange.Reset()
+	m.song = metadata.Song{}
+	m.recording = metadata.Recording{}
 	m.playbackTime = 0
-	m.updateStatus(fmt.Sprintf("Connecting to %s...", selStation.Name))
-	cmds := []tea.Cmd{m.initSpinner(), m.delegate.playCmd(selStation)}
+	m.art.reset(selStation.Stationuuid, selStation.Favicon)
+	cmds := []tea.Cmd{m.initSpinner(), m.connectCmd(selStation), m.art.fetchCmd(selStation.Favicon)}
 	return tea.Batch(cmds...)
 }
 
+// swapStationCmd plays the station played before the current one, like the last channel button of a remote.
+func (m *Model) swapStationCmd() tea.Cmd {
+	m.delegate.playingMtx.RLock()
+	last := m.delegate.lastPlayed
+	m.delegate.playingMtx.RUnlock()
+	if last == nil {
+		m.updateStatusWarn(noLastStationMsg)
+		return nil
+	}
+	return m.playStationCmd(*last)
+}
+
 func (m *Model) playUuidCmd(uuid string) tea.Cmd {
 	return func() tea.Msg {
+		if s, ok := browser.TransientStationByUuid(uuid); ok {
+			return playUuidRespMsg{stations: []browser.Station{s}}
+		}
 		stations, err := m.browser.GetStations([]string{uuid})
 		res := playUuidRespMsg{stations: stations}
 		if err != nil {
-			res.statusMsg = statusMsg(err.Error())
+			res.errorMsg = errorMsg(err.Error())
 		} else if len(stations) == 0 {
 			res.viewMsg = noStationsFound
 		}
 		return res
 	}
 }
+
+// identifyCmd names the song of the playing station from an audio fingerprint,
+// for stations that don't send song titles.
+func (m *Model) identifyCmd() tea.Cmd {
+	m.delegate.playingMtx.RLock()
+	defer m.delegate.playingMtx.RUnlock()
+	if m.delegate.currPlaying == nil {
+		return nil
+	}
+	s := *m.delegate.currPlaying
+	m.updateStatus("Identifying song...")
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), config.IdentifyTimeout)
+		defer cancel()
+		song, err := m.identifier.Identify(ctx, s.URL)
+		if err != nil {
+			slog.With("method", "ui.Model.identifyCmd").Error("", "error", err)
+		}
+		return identifyMsg{stationUuid: s.Stationuuid, stationName: s.Name, song: song, err: err}
+	}
+}
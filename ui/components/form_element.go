@@ -157,3 +157,7 @@ func (e *FormElement) Keymap() help.KeyMap {
 func (e *FormElement) Description() string {
 	return e.description
 }
+
+func (e *FormElement) SetDescription(desc string) {
+	e.description = desc
+}
@@ -93,6 +93,12 @@ func (o *OptionList) SetIdx(v int) {
 	o.previewIdx = v
 }
 
+// SetOptions replaces the options, e.g. of a list of files, selecting the first one.
+func (o *OptionList) SetOptions(options []OptionValue) {
+	o.options = options
+	o.SetIdx(0)
+}
+
 func (o *OptionList) SetActive(v bool) {
 	o.active = v
 	o.Keymap.setEnable(v)
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/browser"
+)
+
+const (
+	connectingMsg       = "Connecting to %s... esc to cancel"
+	connectCancelledMsg = "Cancelled connecting to %s"
+)
+
+// connectAttempt is a station starting to play, cancelled by a new one or by the user.
+type connectAttempt struct {
+	station browser.Station
+	cancel  context.CancelFunc
+}
+
+// connectCmd starts the station within the connect timeout, replacing the attempt in progress.
+func (m *Model) connectCmd(s browser.Station) tea.Cmd {
+	if m.connecting != nil {
+		m.connecting.cancel()
+	}
+	s = m.probedStation(s)
+	ctx, cancel := context.WithTimeout(context.Background(), m.cfg.GetConnectTimeout())
+	a := &connectAttempt{station: s, cancel: cancel}
+	m.connecting = a
+	m.announcer.announce(fmt.Sprintf(connectingMsg, m.stationName(s)))
+	play := m.delegate.playCmd(ctx, s)
+	return func() tea.Msg {
+		msg := play()
+		if resp, ok := msg.(playRespMsg); ok {
+			resp.attempt = a
+			return resp
+		}
+		return msg
+	}
+}
+
+func (m *Model) cancelConnect() {
+	m.connecting.cancel()
+	m.updateStatus(fmt.Sprintf(connectCancelledMsg, m.stationName(m.connecting.station)))
+	m.connecting = nil
+	m.spinner = nil
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_e2eConnectEsc(t *testing.T) {
+	stations := e2eStations(2, "Jazz", "jazz")
+	d := newUIDriver(t, stations...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 2 })
+	d.keys("enter")
+	d.waitFor("the station to play", func() bool { return d.m.connecting == nil && d.m.delegate.currPlaying != nil })
+
+	release := d.player.HoldPlay()
+	defer release()
+	d.keys("down", "enter")
+	d.waitFor("the player to start the station", func() bool { return d.player.Held() == 1 })
+	d.keys("esc")
+	// the previous station too, replaced by the player
+	d.waitFor("the cancel", func() bool { return d.m.connecting == nil && d.m.delegate.playingUuid() == "" })
+
+	release()
+	d.waitFor("the station started after the cancel to stop", func() bool { url, _ := d.player.Playing(); return url == "" })
+}
+
+func Test_playCmd_cancelledBeforePlay(t *testing.T) {
+	stations := e2eStations(2, "Jazz", "jazz")
+	d := newUIDriver(t, stations...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 2 })
+	d.keys("enter")
+	d.waitFor("the station to play", func() bool { return d.m.connecting == nil && d.m.delegate.currPlaying != nil })
+	playing := d.m.delegate.currPlaying
+
+	// another station is still connecting
+	d.m.delegate.connectMtx.Lock()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	msg := d.m.delegate.playCmd(ctx, stations[1])()
+	d.m.delegate.connectMtx.Unlock()
+
+	if resp, ok := msg.(playRespMsg); !ok || !resp.cancelled {
+		t.Fatalf("got %#v, want the play cancelled", msg)
+	}
+	if d.m.delegate.currPlaying != playing {
+		t.Errorf("got %v playing, want %s kept as the player never got the station", d.m.delegate.currPlaying, playing.Name)
+	}
+	if url, _ := d.player.Playing(); url != stations[0].URL {
+		t.Errorf("player on %q, want %q", url, stations[0].URL)
+	}
+}
+
+func Test_playCmd_timeout(t *testing.T) {
+	stations := e2eStations(2, "Jazz", "jazz")
+	d := newUIDriver(t, stations...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 2 })
+	d.keys("enter")
+	d.waitFor("the station to play", func() bool { return d.m.connecting == nil && d.m.delegate.currPlaying != nil })
+
+	release := d.player.HoldPlay()
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	msg := d.m.delegate.playCmd(ctx, stations[1])()
+	if resp, ok := msg.(playRespMsg); !ok || !strings.HasPrefix(resp.err, "Could not connect to Jazz 1") {
+		t.Fatalf("got %#v, want the timeout", msg)
+	}
+	if d.m.delegate.currPlaying != nil || d.m.delegate.prevPlaying != nil {
+		t.Errorf("got %v %v playing, want none as the player replaced them", d.m.delegate.currPlaying, d.m.delegate.prevPlaying)
+	}
+
+	release()
+	d.waitFor("the station started after the timeout to stop", func() bool { url, _ := d.player.Playing(); return url == "" })
+}
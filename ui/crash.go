@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/crash"
+)
+
+// crashMsg quits the program after a command panicked.
+type crashMsg struct{}
+
+// crashState is the first panic recovered, shared by the model and its commands.
+type crashState struct {
+	mtx   sync.Mutex
+	crash *crash.Crash
+}
+
+func (s *crashState) set(c *crash.Crash) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.crash == nil {
+		s.crash = c
+	}
+}
+
+func (s *crashState) get() *crash.Crash {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.crash
+}
+
+// Crash returns the panic which quit the program, nil if none.
+func (m *Model) Crash() *crash.Crash {
+	return m.crash.get()
+}
+
+// crashGuard runs the model for the program, recovering the panics of the model and its commands to quit
+// with the terminal restored, for the crash to be reported, instead of printing them over the view.
+type crashGuard struct {
+	*Model
+}
+
+func (g crashGuard) Init() (cmd tea.Cmd) {
+	defer g.recover(func() { cmd = tea.Quit })
+	return g.guard(g.Model.Init())
+}
+
+func (g crashGuard) Update(msg tea.Msg) (res tea.Model, cmd tea.Cmd) {
+	if g.crash.get() != nil {
+		return g, tea.Quit
+	}
+	defer g.recover(func() { res, cmd = g, tea.Quit })
+	_, cmd = g.Model.Update(msg)
+	return g, g.guard(cmd)
+}
+
+func (g crashGuard) View() (view string) {
+	if g.crash.get() != nil {
+		return ""
+	}
+	defer g.recover(func() {
+		view = ""
+		// View runs in the event loop, which must not wait on itself
+		go g.Progr.Quit()
+	})
+	return g.Model.View()
+}
+
+// recover keeps the panic recovered, if any, then calls onPanic.
+func (g crashGuard) recover(onPanic func()) {
+	if r := recover(); r != nil {
+		g.crash.set(crash.Recovered(r))
+		onPanic()
+	}
+}
+
+// guard recovers the panics of the command, and of the commands it batches. The message of tea.Sequence is
+// unexported, so the sequenced commands are guarded when sequenced, by Model.sequence.
+func (g crashGuard) guard(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() (msg tea.Msg) {
+		defer g.recover(func() { msg = crashMsg{} })
+		return g.guardMsg(cmd())
+	}
+}
+
+func (g crashGuard) guardMsg(msg tea.Msg) tea.Msg {
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for i := range batch {
+			batch[i] = g.guard(batch[i])
+		}
+		return batch
+	}
+	return msg
+}
+
+// sequenceCmds runs the commands in order, replaced by the tests driving the model without the program.
+var sequenceCmds = tea.Sequence
+
+// sequence runs the commands in order like tea.Sequence, with their panics recovered like the other commands.
+func (m *Model) sequence(cmds ...tea.Cmd) tea.Cmd {
+	g := crashGuard{m}
+	for i := range cmds {
+		cmds[i] = g.guard(cmds[i])
+	}
+	return sequenceCmds(cmds...)
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func Test_crashGuardCmd(t *testing.T) {
+	g := crashGuard{&Model{crash: new(crashState)}}
+	ok := func() tea.Msg { return "ok" }
+	boom := func() tea.Msg { panic("boom") }
+
+	batch, isBatch := g.guard(tea.Batch(ok, boom))().(tea.BatchMsg)
+	if !isBatch || len(batch) != 2 {
+		t.Fatalf("got %v, want the batch", batch)
+	}
+	if msg := batch[0](); msg != "ok" {
+		t.Errorf("got %v from the first command", msg)
+	}
+	if g.Crash() != nil {
+		t.Fatal("expected no crash yet")
+	}
+	if msg := batch[1](); msg != (crashMsg{}) {
+		t.Errorf("got %v, want the crash message", msg)
+	}
+	c := g.Crash()
+	if c == nil || c.Value != "boom" || !strings.Contains(string(c.Stack), "Test_crashGuardCmd") {
+		t.Fatalf("got the crash %+v, want the panic with its stack", c)
+	}
+
+	if _, cmd := g.Update(crashMsg{}); cmd == nil || cmd() != tea.Quit() {
+		t.Error("expected the program to quit after the crash")
+	}
+	if v := g.View(); v != "" {
+		t.Errorf("got the view %q after the crash", v)
+	}
+}
+
+func Test_crashGuardSequence(t *testing.T) {
+	m := &Model{crash: new(crashState)}
+	var ran []string
+	seq := m.sequence(
+		func() tea.Msg { ran = append(ran, "first"); return "first" },
+		func() tea.Msg { panic("in sequence") },
+	)
+	msgs := runSequence(t, seq)
+	if len(msgs) != 2 || msgs[0] != "first" || msgs[1] != (crashMsg{}) {
+		t.Errorf("got %v, want the first message then the crash message", msgs)
+	}
+	if c := m.Crash(); c == nil || c.Value != "in sequence" {
+		t.Errorf("got the crash %+v", c)
+	}
+}
+
+// runSequence runs the commands of the sequence in order, like the program, through its exported behavior:
+// a sequence of one command per step, run by tea.Sequence itself.
+func runSequence(t *testing.T, seq tea.Cmd) []tea.Msg {
+	t.Helper()
+	var msgs []tea.Msg
+	p := tea.NewProgram(seqRecorder{msgs: &msgs, want: 2}, tea.WithInput(nil), tea.WithoutRenderer())
+	go p.Send(seqStart{seq})
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return msgs
+}
+
+type seqStart struct{ cmd tea.Cmd }
+
+type seqRecorder struct {
+	msgs *[]tea.Msg
+	want int
+}
+
+func (r seqRecorder) Init() tea.Cmd { return nil }
+
+func (r seqRecorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case seqStart:
+		return r, msg.cmd
+	case string, crashMsg:
+		*r.msgs = append(*r.msgs, msg)
+		if len(*r.msgs) == r.want {
+			return r, tea.Quit
+		}
+	}
+	return r, nil
+}
+
+func (r seqRecorder) View() string { return "" }
+
+func Test_crashGuardUpdate(t *testing.T) {
+	// the tabs of the empty model are missing, which panics
+	g := crashGuard{&Model{crash: new(crashState)}}
+	res, cmd := g.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if res != g || cmd == nil || cmd() != tea.Quit() {
+		t.Errorf("got %v, %v, want to quit", res, cmd)
+	}
+	if g.Crash() == nil {
+		t.Error("expected the crash kept")
+	}
+}
@@ -0,0 +1,25 @@
+package ui
+
+import (
+	"time"
+)
+
+// crossfades are the milliseconds of the crossfade options, 0 being off.
+var crossfades = []int{0, 1000, 2000, 3000, 5000}
+
+// fadeName returns the name of the crossfade or startup fade option of the milliseconds.
+func fadeName(ms int) string {
+	if ms <= 0 {
+		return "Off"
+	}
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+// setCrossfade changes the milliseconds of the fade between the stations, for the players of all the
+// sessions.
+func (m *Model) setCrossfade(ms int) {
+	m.cfg.CrossfadeMs = ms
+	for _, s := range m.sessions.sessions {
+		s.player.SetCrossfade(m.cfg.Crossfade())
+	}
+}
@@ -0,0 +1,20 @@
+package ui
+
+import "testing"
+
+func Test_e2eCrossfade(t *testing.T) {
+	d := newUIDriver(t)
+	d.keys("S", "k", "enter", "3")
+	d.waitFor("the crossfade set", func() bool { return d.m.cfg.CrossfadeMs == 2000 })
+
+	d.keys("enter", "k", "k", "enter")
+	d.waitFor("the crossfade off", func() bool { return d.m.cfg.CrossfadeMs == 0 })
+}
+
+func Test_fadeName(t *testing.T) {
+	for ms, want := range map[int]string{0: "Off", -1: "Off", 1500: "1.5s", 3000: "3s"} {
+		if got := fadeName(ms); got != want {
+			t.Errorf("fadeName(%d) = %s, want %s", ms, got, want)
+		}
+	}
+}
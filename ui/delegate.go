@@ -1,11 +1,14 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"strings"
 	"sync"
+	"time"
 	"unicode/utf8"
 
 	"github.com/dancnb/sonicradio/ui/styles"
@@ -47,10 +50,23 @@ type stationDelegate struct {
 	playingMtx  sync.RWMutex
 	prevPlaying *browser.Station
 	currPlaying *browser.Station
+	// lastPlayed is the station played before the current one, to swap back to it
+	lastPlayed *browser.Station
+	// connectMtx orders the station starts, so a cancelled one is stopped before the next one starts
+	connectMtx sync.Mutex
 
 	deleted *browser.Station
+	// dialSlots are the quick dial slots of the stations, looked up once per update instead of per row
+	dialSlots map[string]int
+
+	// selected returns the station selected in the active tab, for the short help of its actions
+	selected func() (browser.Station, bool)
+	// favoritesTab is whether the favorites are listed, whose short help has the actions on a favorite
+	favoritesTab bool
 
 	keymap *delegateKeyMap
+	// rowTmpl is the template of the second line of the rows
+	rowTmpl textTemplate
 
 	defaultDelegate list.DefaultDelegate
 }
@@ -100,7 +116,14 @@ func (d *stationDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
 			if !isSel {
 				break
 			}
+			if selStation.Transient() {
+				return func() tea.Msg { return warnMsg(transientFavoriteMsg) }
+			}
+			name := d.cfg.StationName(selStation.Stationuuid, selStation.Name)
 			added := d.cfg.ToggleFavorite(selStation.Stationuuid)
+			if !added {
+				d.cfg.TrashFavorite(selStation.Stationuuid, name, time.Now())
+			}
 			return func() tea.Msg { return toggleFavoriteMsg{added, selStation} }
 		case key.Matches(msg, d.keymap.toggleAutoplay):
 			if !isSel {
@@ -177,7 +200,7 @@ func (d *stationDelegate) pauseCmd() tea.Cmd {
 		err := d.player.Pause(true)
 		if err != nil {
 			log.Error(fmt.Sprintf("player pause: %v", err))
-			return pauseRespMsg{fmt.Sprintf("Could not pause station %s (%s)!", d.currPlaying.Name, d.currPlaying.URL)}
+			return pauseRespMsg{fmt.Sprintf("Could not pause station %s (%s)!", d.cfg.StationName(d.currPlaying.Stationuuid, d.currPlaying.Name), d.currPlaying.URL)}
 		}
 		d.prevPlaying = d.currPlaying
 		d.currPlaying = nil
@@ -185,6 +208,29 @@ func (d *stationDelegate) pauseCmd() tea.Cmd {
 	}
 }
 
+// stopCmd stops the playback without keeping the station to resume, and shows the status.
+func (d *stationDelegate) stopCmd(status string) tea.Cmd {
+	return func() tea.Msg {
+		log := slog.With("method", "ui.stationDelegate.stopCmd")
+		log.Info("begin")
+		defer log.Info("end")
+
+		d.playingMtx.Lock()
+		defer d.playingMtx.Unlock()
+
+		if d.currPlaying == nil {
+			return nil
+		}
+		if err := d.player.Stop(); err != nil {
+			log.Error("player stop", "error", err)
+			return errorMsg(fmt.Sprintf("Could not stop station %s!", d.cfg.StationName(d.currPlaying.Stationuuid, d.currPlaying.Name)))
+		}
+		d.currPlaying = nil
+		d.prevPlaying = nil
+		return statusMsg(status)
+	}
+}
+
 func (d *stationDelegate) resumeCmd() tea.Cmd {
 	return func() tea.Msg {
 		log := slog.With("method", "ui.stationDelegate.resumeCmd")
@@ -200,7 +246,7 @@ func (d *stationDelegate) resumeCmd() tea.Cmd {
 		err := d.player.Pause(false)
 		if err != nil {
 			log.Error(fmt.Sprintf("player resume: %v", err))
-			return playRespMsg{fmt.Sprintf("Could not resume playback for station %s (%s)!", d.currPlaying.Name, d.currPlaying.URL)}
+			return playRespMsg{err: fmt.Sprintf("Could not resume playback for station %s (%s)!", d.cfg.StationName(d.prevPlaying.Stationuuid, d.prevPlaying.Name), d.prevPlaying.URL)}
 		}
 		d.currPlaying = d.prevPlaying
 		d.prevPlaying = nil
@@ -208,23 +254,87 @@ func (d *stationDelegate) resumeCmd() tea.Cmd {
 	}
 }
 
-func (d *stationDelegate) playCmd(s browser.Station) tea.Cmd {
+// playCmd starts the station without holding the playing lock, so the header keeps rendering while
+// a slow station connects. It gives up when ctx is done: cancelled, or the connect timeout elapsed.
+func (d *stationDelegate) playCmd(ctx context.Context, s browser.Station) tea.Cmd {
 	return func() tea.Msg {
 		log := slog.With("method", "ui.stationDelegate.playCmd")
 		log.Info("begin")
 		defer log.Info("end")
 
-		d.playingMtx.Lock()
-		defer d.playingMtx.Unlock()
+		if d.cfg.LowBandwidth {
+			s = d.lowBandwidthVariant(s)
+		}
+
+		d.playingMtx.RLock()
+		p := d.player
+		d.playingMtx.RUnlock()
 
 		log.Info("playing", "id", s.Stationuuid)
-		go d.increaseCounter(s)
+		done := make(chan error, 1)
+		// started is whether the player was asked to play, replacing the previous station
+		var startMtx sync.Mutex
+		var started bool
+		go func() {
+			d.connectMtx.Lock()
+			defer d.connectMtx.Unlock()
+			startMtx.Lock()
+			if ctx.Err() != nil {
+				startMtx.Unlock()
+				done <- ctx.Err()
+				return
+			}
+			started = true
+			startMtx.Unlock()
+			err := p.Play(s.URL)
+			if err == nil && ctx.Err() != nil {
+				// started after giving up on it
+				if err := p.Stop(); err != nil {
+					log.Error("player stop", "error", err)
+				}
+			}
+			done <- err
+		}()
 
-		err := d.player.Play(s.URL)
+		var err error
+		select {
+		case err = <-done:
+		case <-ctx.Done():
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			log.Info("connect", "id", s.Stationuuid, "error", ctxErr)
+			startMtx.Lock()
+			replaced := started
+			startMtx.Unlock()
+			if replaced {
+				// the previous station was replaced by the player
+				d.playingMtx.Lock()
+				d.currPlaying, d.prevPlaying = nil, nil
+				d.playingMtx.Unlock()
+			}
+			if errors.Is(ctxErr, context.DeadlineExceeded) {
+				return playRespMsg{err: fmt.Sprintf("Could not connect to %s within %s!", d.cfg.StationName(s.Stationuuid, s.Name), d.cfg.GetConnectTimeout())}
+			}
+			return playRespMsg{cancelled: true}
+		}
 		if err != nil {
 			errMsg := fmt.Sprintf("error playing station %s: %s", s.Name, err.Error())
 			log.Error(errMsg)
-			return playRespMsg{fmt.Sprintf("Could not start playback for %s (%s)!", s.Name, s.URL)}
+			return playRespMsg{err: fmt.Sprintf("Could not start playback for %s (%s)!", d.cfg.StationName(s.Stationuuid, s.Name), s.URL)}
+		}
+		go d.increaseCounter(s)
+
+		d.playingMtx.Lock()
+		defer d.playingMtx.Unlock()
+		if err := p.SetStation(s.Stationuuid, s.Name); err != nil {
+			log.Error("daemon set station", "error", err)
+		}
+		last := d.currPlaying
+		if last == nil {
+			last = d.prevPlaying
+		}
+		if last != nil && last.Stationuuid != s.Stationuuid {
+			d.lastPlayed = last
 		}
 		d.prevPlaying = d.currPlaying
 		d.currPlaying = &s
@@ -232,36 +342,102 @@ func (d *stationDelegate) playCmd(s browser.Station) tea.Cmd {
 	}
 }
 
+// lowBandwidthVariant returns the station with the stream of its lowest bitrate variant, if radio-browser lists one.
+// The station keeps its uuid, so that it's still shown as playing in the favorites and history.
+func (d *stationDelegate) lowBandwidthVariant(s browser.Station) browser.Station {
+	if s.Transient() {
+		return s
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), config.ApiReqTimeout)
+	defer cancel()
+	v := d.b.LowestBitrateVariant(ctx, s)
+	if v.Stationuuid == s.Stationuuid {
+		return s
+	}
+	slog.With("method", "ui.stationDelegate.lowBandwidthVariant").Info("variant", "id", s.Stationuuid, "variant", v.Stationuuid, "bitrate", v.Bitrate)
+	s.URL = v.URL
+	s.URLResolved = v.URLResolved
+	s.Codec = v.Codec
+	s.Bitrate = v.Bitrate
+	return s
+}
+
 func (d *stationDelegate) increaseCounter(station browser.Station) {
+	if station.Transient() {
+		return
+	}
+	d.cfg.AddClick(station.Stationuuid)
 	d.b.StationCounter(station.Stationuuid)
 }
 
+// nowPlaying returns the station playing or paused, empty if there is none.
+// It's shared by all the lists to mark the station wherever it appears.
+func (d *stationDelegate) nowPlaying() (uuid string, paused bool) {
+	d.playingMtx.RLock()
+	defer d.playingMtx.RUnlock()
+	switch {
+	case d.currPlaying != nil:
+		return d.currPlaying.Stationuuid, false
+	case d.prevPlaying != nil:
+		return d.prevPlaying.Stationuuid, true
+	}
+	return "", false
+}
+
+func (d *stationDelegate) playingUuid() string {
+	uuid, _ := d.nowPlaying()
+	return uuid
+}
+
+// nowPlayingIcon returns the icon marking the station playing or paused.
+func nowPlayingIcon(paused bool) string {
+	if paused {
+		return " " + styles.PauseChar
+	}
+	return " " + styles.PlayChar
+}
+
 func (d *stationDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
 	s, ok := listItem.(browser.Station)
 	if !ok {
 		return
 	}
-	name := s.Name
+	name := d.cfg.StationName(s.Stationuuid, s.Name)
 	if d.cfg.IsFavorite(s.Stationuuid) {
 		name += styles.FavChar
 	}
 	if d.cfg.AutoplayFavorite == s.Stationuuid {
 		name += d.style.BaseBold.Render(styles.AutoplayChar)
 	}
+	if l := d.cfg.Label(s.Stationuuid); l != config.NoLabel {
+		name += styles.LabelDot(int(l), l.String())
+	}
+	if d.cfg.DialKeys != config.DialOff {
+		if slot := d.dialSlots[s.Stationuuid]; slot > 0 {
+			name += d.style.BaseBold.Render(fmt.Sprintf(styles.DialFmt, slot))
+		}
+	}
 
 	isSel := index == m.Index()
 
-	d.playingMtx.RLock()
-	defer d.playingMtx.RUnlock()
-
-	isCurr := d.currPlaying != nil && d.currPlaying.Stationuuid == s.Stationuuid
-	isPrev := d.currPlaying == nil && d.prevPlaying != nil && d.prevPlaying.Stationuuid == s.Stationuuid
+	playingUuid, paused := d.nowPlaying()
+	isPlaying := playingUuid == s.Stationuuid
+	if isPlaying {
+		name += d.style.BaseBold.Render(nowPlayingIcon(paused))
+	}
+	desc := s.Description()
+	if text, ok := d.rowTmpl.line(d.cfg.RowFormat, s); ok {
+		desc = text
+	}
 	var str string
 
 	prefix := styles.IndexString(index + 1)
+	if isSel {
+		prefix = styles.MarkSelected(prefix)
+	}
 
 	listWidth := m.Width()
-	if isCurr || isPrev {
+	if isPlaying {
 		itStyle := d.style.PrimaryColorStyle
 		descStyle := d.style.SecondaryColorStyle
 		if isSel {
@@ -271,7 +447,7 @@ func (d *stationDelegate) Render(w io.Writer, m list.Model, index int, listItem
 		prefixStyle := d.style.NowPlayingPrefixStyle
 		widthOffset := 1
 
-		str = d.renderStationView(prefix, name, s.Description(), listWidth, widthOffset, prefixStyle, itStyle, descStyle)
+		str = d.renderStationView(prefix, name, desc, listWidth, widthOffset, prefixStyle, itStyle, descStyle)
 
 		str = d.style.SelectedBorderStyle.Render(str)
 	} else {
@@ -284,7 +460,7 @@ func (d *stationDelegate) Render(w io.Writer, m list.Model, index int, listItem
 		prefixStyle := d.style.PrefixStyle
 		widthOffset := 0
 
-		str = d.renderStationView(prefix, name, s.Description(), listWidth, widthOffset, prefixStyle, itStyle, descStyle)
+		str = d.renderStationView(prefix, name, desc, listWidth, widthOffset, prefixStyle, itStyle, descStyle)
 	}
 
 	fmt.Fprint(w, str)
@@ -410,10 +586,55 @@ func (d *stationDelegate) renderMinimalView(
 	return res.String()
 }
 
+// ShortHelp returns the actions available on the selected station, which depend on the tab and on the station,
+// e.g. unfavorite on the favorites tab and favorite or vote on the browse results.
 func (d *stationDelegate) ShortHelp() []key.Binding {
-	return []key.Binding{
-		d.keymap.playSelected, d.keymap.pause, d.keymap.toggleFavorite, d.keymap.toggleAutoplay,
+	var st browser.Station
+	ok := false
+	if d.selected != nil {
+		st, ok = d.selected()
+	}
+	playing := d.playingUuid()
+	var keys []key.Binding
+	if ok && st.Stationuuid != playing {
+		keys = append(keys, d.keymap.playSelected)
+	}
+	if playing != "" {
+		keys = append(keys, d.keymap.pause)
+	}
+	keys = append(keys, d.keymap.tuneIn)
+	if !ok {
+		return keys
 	}
+
+	favorite := d.cfg.IsFavorite(st.Stationuuid)
+	if d.favoritesTab {
+		keys = append(keys, withHelp(d.keymap.delete, "unfavorite"))
+		if d.cfg.AutoplayFavorite == st.Stationuuid {
+			keys = append(keys, withHelp(d.keymap.toggleAutoplay, "no autoplay"))
+		} else {
+			keys = append(keys, withHelp(d.keymap.toggleAutoplay, "autoplay"))
+		}
+	} else if !st.Transient() {
+		if favorite {
+			keys = append(keys, withHelp(d.keymap.toggleFavorite, "unfavorite"))
+		} else {
+			keys = append(keys, withHelp(d.keymap.toggleFavorite, "favorite"))
+		}
+	}
+	if favorite {
+		keys = append(keys, withHelp(d.keymap.editFavorite, "edit"))
+	}
+	if !d.favoritesTab && !st.Transient() {
+		keys = append(keys, withHelp(d.keymap.info, "info and vote"))
+	}
+	return keys
+}
+
+// withHelp returns a copy of the binding with the help description, the binding keeping its own.
+func withHelp(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
 }
 
 func (d *stationDelegate) FullHelp() [][]key.Binding {
@@ -425,12 +646,40 @@ func (d *stationDelegate) FullHelp() [][]key.Binding {
 			d.keymap.volumeUp,
 			d.keymap.seekBack,
 			d.keymap.seekFw,
+			d.keymap.lyrics,
+			d.keymap.lyricsUp,
+			d.keymap.lyricsDown,
+			d.keymap.identify,
+			d.keymap.sessions,
+			d.keymap.outputs,
+			d.keymap.guide,
+			d.keymap.live,
+			d.keymap.radioMap,
+			d.keymap.nearby,
+			d.keymap.swap,
+			d.keymap.dial,
+			d.keymap.assignDial,
+			d.keymap.toPlaying,
+			d.keymap.editFavorite,
+			d.keymap.tuneIn,
+			d.keymap.bulkAdd,
+			d.keymap.trash,
+			d.keymap.refreshMetadata,
+			d.keymap.label,
+			d.keymap.syncNow,
+			d.keymap.palette,
+			d.keymap.logView,
+			d.keymap.perfOverlay,
+			d.keymap.banner,
+			d.keymap.recordMacro,
+			d.keymap.scheduleRecording,
 			d.keymap.info,
 			d.keymap.toggleFavorite,
 			d.keymap.toggleAutoplay,
 			d.keymap.delete,
 			d.keymap.pasteAfter,
 			d.keymap.pasteBefore,
+			d.keymap.quit,
 		},
 	}
 }
@@ -485,20 +734,179 @@ func newDelegateKeyMap() *delegateKeyMap {
 			key.WithKeys("right", "l"),
 			key.WithHelp("→/l", "seek forward"),
 		),
+		lyrics: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "toggle lyrics"),
+		),
+		lyricsUp: key.NewBinding(
+			key.WithKeys("alt+up", "alt+k"),
+			key.WithHelp("alt+↑/k", "scroll lyrics up"),
+		),
+		lyricsDown: key.NewBinding(
+			key.WithKeys("alt+down", "alt+j"),
+			key.WithHelp("alt+↓/j", "scroll lyrics down"),
+		),
+		identify: key.NewBinding(
+			key.WithKeys("I"),
+			key.WithHelp("I", "identify song"),
+		),
+		scheduleRecording: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "schedule recording"),
+		),
+		sessions: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "sessions"),
+		),
+		outputs: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "outputs"),
+		),
+		guide: key.NewBinding(
+			key.WithKeys("ctrl+g"),
+			key.WithHelp("ctrl+g", "program guide"),
+		),
+		live: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "live now"),
+		),
+		radioMap: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", "radio map"),
+		),
+		nearby: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "stations near me"),
+		),
+		swap: key.NewBinding(
+			key.WithKeys("b", "backspace"),
+			key.WithHelp("b", "swap station"),
+		),
+		// the keys are set from the config
+		dial: key.NewBinding(
+			key.WithHelp("1..9", "quick dial"),
+		),
+		assignDial: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "quick dial slot"),
+		),
+		toPlaying: key.NewBinding(
+			key.WithKeys("J"),
+			key.WithHelp("J", "jump to playing"),
+		),
+		editFavorite: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "name, note and reminder"),
+		),
+		// enabled while a reminder is due
+		tuneIn: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "tune in reminder"),
+			key.WithDisabled(),
+		),
+		bulkAdd: key.NewBinding(
+			key.WithKeys("V"),
+			key.WithHelp("V", "add pasted favorites"),
+		),
+		trash: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "removed favorites"),
+		),
+		refreshMetadata: key.NewBinding(
+			key.WithKeys("U"),
+			key.WithHelp("U", "refresh song title"),
+		),
+		label: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "color label"),
+		),
+		syncNow: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("ctrl+s", "sync now"),
+		),
+		palette: key.NewBinding(
+			key.WithKeys("ctrl+p"),
+			key.WithHelp("ctrl+p", "command palette"),
+		),
+		logView: key.NewBinding(
+			key.WithKeys("ctrl+l"),
+			key.WithHelp("ctrl+l", "log"),
+			key.WithDisabled(),
+		),
+		perfOverlay: key.NewBinding(
+			key.WithKeys("ctrl+d"),
+			key.WithHelp("ctrl+d", "timings overlay"),
+		),
+		banner: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "large banner"),
+		),
+		// also the key of the lists clearing their filter, shown in their help
+		clearFilters: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "clear filters"),
+		),
+		recordMacro: key.NewBinding(
+			key.WithKeys("ctrl+x"),
+			key.WithHelp("ctrl+x", "record macro"),
+		),
+		cancelConnect: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel connecting"),
+		),
+		detach: key.NewBinding(
+			key.WithKeys("q"),
+			key.WithHelp("q", "detach"),
+		),
+		quit: key.NewBinding(
+			key.WithKeys("Q"),
+			key.WithHelp("Q", "quit"),
+		),
 	}
 }
 
 type delegateKeyMap struct {
-	pause          key.Binding
-	playSelected   key.Binding
-	info           key.Binding
-	toggleFavorite key.Binding
-	toggleAutoplay key.Binding
-	delete         key.Binding
-	pasteAfter     key.Binding
-	pasteBefore    key.Binding
-	volumeDown     key.Binding
-	volumeUp       key.Binding
-	seekBack       key.Binding
-	seekFw         key.Binding
+	pause             key.Binding
+	playSelected      key.Binding
+	info              key.Binding
+	toggleFavorite    key.Binding
+	toggleAutoplay    key.Binding
+	delete            key.Binding
+	pasteAfter        key.Binding
+	pasteBefore       key.Binding
+	volumeDown        key.Binding
+	volumeUp          key.Binding
+	seekBack          key.Binding
+	seekFw            key.Binding
+	lyrics            key.Binding
+	lyricsUp          key.Binding
+	lyricsDown        key.Binding
+	identify          key.Binding
+	scheduleRecording key.Binding
+	sessions          key.Binding
+	outputs           key.Binding
+	guide             key.Binding
+	live              key.Binding
+	radioMap          key.Binding
+	nearby            key.Binding
+	swap              key.Binding
+	dial              key.Binding
+	assignDial        key.Binding
+	toPlaying         key.Binding
+	editFavorite      key.Binding
+	tuneIn            key.Binding
+	bulkAdd           key.Binding
+	trash             key.Binding
+	refreshMetadata   key.Binding
+	label             key.Binding
+	palette           key.Binding
+	logView           key.Binding
+	perfOverlay       key.Binding
+	banner            key.Binding
+	recordMacro       key.Binding
+	clearFilters      key.Binding
+	syncNow           key.Binding
+	cancelConnect     key.Binding
+	detach            key.Binding
+	quit              key.Binding
 }
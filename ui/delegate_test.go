@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+func shortHelp(d *uiDriver) []string {
+	var res []string
+	for _, b := range d.m.delegate.ShortHelp() {
+		if b.Enabled() {
+			res = append(res, b.Help().Key+" "+b.Help().Desc)
+		}
+	}
+	return res
+}
+
+func Test_e2eShortHelp(t *testing.T) {
+	d := newUIDriver(t, e2eStations(2, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 2 })
+	d.m.toBrowseTab()
+	if got, want := shortHelp(d), []string{"enter play", "f favorite", "i info and vote"}; !slices.Equal(got, want) {
+		t.Errorf("browse help %v, want %v", got, want)
+	}
+
+	d.keys("f")
+	d.waitFor("the favorite added", func() bool { return d.m.cfg.IsFavorite("jazz-0") })
+	if got := shortHelp(d); !slices.Contains(got, "f unfavorite") || !slices.Contains(got, "r edit") {
+		t.Errorf("browse help of a favorite %v, want unfavorite and edit", got)
+	}
+
+	d.keys("F")
+	d.waitFor("the favorites tab", func() bool { return d.m.activeTabIdx == favoriteTabIx })
+	want := []string{"enter play", "d unfavorite", "a autoplay", "r edit"}
+	d.waitFor("the favorites help", func() bool { return slices.Equal(shortHelp(d), want) })
+
+	d.keys("enter")
+	d.waitFor("the favorite to play", func() bool { return d.m.delegate.playingUuid() == "jazz-0" })
+	if got := shortHelp(d); slices.Contains(got, "enter play") || !slices.Contains(got, "space pause") {
+		t.Errorf("help of the station playing %v, want pause instead of play", got)
+	}
+}
+
+func Test_withHelp(t *testing.T) {
+	b := key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete"))
+	if got := withHelp(b, "unfavorite").Help(); got.Key != "d" || got.Desc != "unfavorite" {
+		t.Errorf("got help %v, want d unfavorite", got)
+	}
+	if b.Help().Desc != "delete" {
+		t.Errorf("binding help changed to %q", b.Help().Desc)
+	}
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"log/slog"
+
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/daemon"
+	"github.com/dancnb/sonicradio/player"
+)
+
+// Detached tells if the playback continues in the background after the app quit.
+func (m *Model) Detached() bool {
+	return m.detached
+}
+
+// handOff continues the playing station in a new daemon, for the app to attach to later.
+func (m *Model) handOff() bool {
+	log := slog.With("method", "ui.Model.handOff")
+	// the simulated playback of the demo has nothing to continue
+	if config.Demo() {
+		return false
+	}
+
+	m.delegate.playingMtx.RLock()
+	s := m.delegate.currPlaying
+	m.delegate.playingMtx.RUnlock()
+	if s == nil {
+		return false
+	}
+
+	path := config.DaemonSocketPath()
+	if err := daemon.Start(path); err != nil {
+		log.Error("daemon start", "error", err)
+		return false
+	}
+	p, err := player.Attach(path)
+	if err != nil {
+		log.Error("daemon attach", "error", err)
+		return false
+	}
+	defer p.Close()
+
+	// the local playback is stopped first, so the two don't overlap
+	if err := m.player.Stop(); err != nil {
+		log.Error("player stop", "error", err)
+	}
+	if err := p.SetMaxVolume(m.cfg.MaxVolume); err != nil {
+		log.Error("daemon max volume", "error", err)
+	}
+	if _, err := p.SetVolume(m.cfg.GetVolume()); err != nil {
+		log.Error("daemon volume", "error", err)
+	}
+	if err := p.Play(s.URL); err != nil {
+		log.Error("daemon play", "error", err)
+		_ = p.Shutdown()
+		return false
+	}
+	if err := p.SetStation(s.Stationuuid, s.Name); err != nil {
+		log.Error("daemon set station", "error", err)
+	}
+	return true
+}
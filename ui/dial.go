@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	dialAssignMsg   = "Press 1-9 to put %s on a quick dial slot, 0 to remove it"
+	dialAssignedMsg = "%s on quick dial %d"
+	dialRemovedMsg  = "%s removed from the quick dial"
+	dialEmptyMsg    = "Quick dial %d is empty"
+	dialNotFavorite = "Add the station to favorites to put it on the quick dial"
+)
+
+// dialSlot returns the quick dial slot of a quick dial key.
+func dialSlot(msg tea.KeyMsg) int {
+	slot, _ := strconv.Atoi(strings.TrimPrefix(msg.String(), "alt+"))
+	return slot
+}
+
+// dialCmd plays the favorite of the quick dial slot.
+func (m *Model) dialCmd(slot int) tea.Cmd {
+	uuid := m.cfg.DialStation(slot)
+	for _, s := range m.favoriteStations() {
+		if uuid != "" && s.Stationuuid == uuid {
+			return m.playStationCmd(s)
+		}
+	}
+	m.updateStatusWarn(fmt.Sprintf(dialEmptyMsg, slot))
+	return nil
+}
+
+// startDialAssign waits for the slot to put the selected favorite on.
+func (m *Model) startDialAssign() {
+	t, ok := m.tabs[m.activeTabIdx].(stationTab)
+	if !ok {
+		return
+	}
+	s, ok := t.Stations().list.SelectedItem().(browser.Station)
+	if !ok {
+		return
+	}
+	if !m.cfg.IsFavorite(s.Stationuuid) {
+		m.updateStatusWarn(dialNotFavorite)
+		return
+	}
+	m.dialAssign = &s
+	m.updateStatus(fmt.Sprintf(dialAssignMsg, m.stationName(s)))
+}
+
+// assignDial puts the station on the slot of the digit pressed, any other key cancels.
+func (m *Model) assignDial(msg tea.KeyMsg) {
+	s := m.dialAssign
+	m.dialAssign = nil
+	slot, err := strconv.Atoi(msg.String())
+	if err != nil || slot > config.DialSlots {
+		m.updateStatus("")
+		return
+	}
+	m.cfg.SetDial(slot, s.Stationuuid)
+	if slot == 0 {
+		m.updateStatus(fmt.Sprintf(dialRemovedMsg, m.stationName(*s)))
+	} else {
+		m.updateStatus(fmt.Sprintf(dialAssignedMsg, m.stationName(*s), slot))
+	}
+}
+
+func (m *Model) setDialKeys(keys config.DialKeys) {
+	m.cfg.DialKeys = keys
+	var prefix string
+	if keys == config.DialAlt {
+		prefix = "alt+"
+	}
+	var dialKeys []string
+	for slot := 1; slot <= config.DialSlots; slot++ {
+		dialKeys = append(dialKeys, prefix+strconv.Itoa(slot))
+	}
+	m.delegate.keymap.dial.SetKeys(dialKeys...)
+	m.delegate.keymap.dial.SetHelp(keys.String(), "quick dial")
+	m.delegate.keymap.dial.SetEnabled(keys != config.DialOff)
+	m.delegate.keymap.assignDial.SetEnabled(keys != config.DialOff)
+	// the digits go to the quick dial instead of the station numbers
+	for _, t := range m.tabs {
+		switch t := t.(type) {
+		case stationTab:
+			t.Stations().listKeymap.digitHelp.SetEnabled(keys != config.DialDigits)
+		case *historyTab:
+			t.keymap.digitHelp.SetEnabled(keys != config.DialDigits)
+		}
+	}
+}
+
+// updateDialSlots looks up the quick dial slots shown in the lists, after the favorites or the slots changed.
+func (m *Model) updateDialSlots() {
+	m.delegate.dialSlots = m.cfg.DialSlotMap()
+}
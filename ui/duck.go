@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/audiosink"
+)
+
+// setAutoDuck follows the notifications and calls of the other apps to lower the volume during them,
+// or stops following them and restores the volume.
+func (m *Model) setAutoDuck(enabled bool) tea.Cmd {
+	log := slog.With("method", "ui.Model.setAutoDuck")
+	m.cfg.AutoDuck = enabled
+	if !enabled {
+		if m.stopDuck != nil {
+			m.stopDuck()
+			m.stopDuck = nil
+		}
+		return m.duck(false)
+	}
+	if m.stopDuck != nil || m.Progr == nil || !audiosink.Available() {
+		return nil
+	}
+	// stopped on quit
+	ctx, cancel := context.WithCancel(context.Background())
+	m.stopDuck = cancel
+	progr := m.Progr
+	go func() {
+		err := audiosink.WatchInterruptions(ctx, func(active bool) {
+			progr.Send(duckMsg{active: active})
+		})
+		if err != nil {
+			log.Info("notifications and calls unavailable", "error", err)
+		}
+	}()
+	return nil
+}
+
+// duck lowers the volume of the player while a notification or a call plays, and restores it after.
+// The volume of the config is kept, so the volume keys raise it back.
+func (m *Model) duck(active bool) tea.Cmd {
+	if active == m.ducked || (active && !m.cfg.AutoDuck) {
+		return nil
+	}
+	m.ducked = active
+	vol := m.cfg.GetVolume()
+	if active {
+		vol = m.cfg.DuckedVolume()
+	}
+	slog.With("method", "ui.Model.duck").Info("volume", "ducked", active, "volume", vol)
+	return func() tea.Msg {
+		if _, err := m.player.SetVolume(vol); err != nil {
+			return volumeMsg{err}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,32 @@
+package ui
+
+import "testing"
+
+func Test_e2eAutoDuck(t *testing.T) {
+	d := newUIDriver(t, e2eStations(3, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 3 })
+	d.keys("enter")
+	d.waitFor("the station to play", func() bool { return d.m.connecting == nil && d.m.delegate.currPlaying != nil })
+
+	d.send(duckMsg{active: true})
+	if d.m.ducked {
+		t.Fatal("expected the volume kept without the auto duck")
+	}
+
+	d.keys("S", "k", "k", "enter", "j", "enter")
+	d.waitFor("the auto duck enabled", func() bool { return d.m.cfg.AutoDuck })
+	d.send(duckMsg{active: true})
+	d.waitFor("the volume lowered", func() bool { return d.player.Volume() == 30 })
+	if d.m.cfg.GetVolume() != 100 {
+		t.Errorf("config volume %d, want it kept", d.m.cfg.GetVolume())
+	}
+	d.send(duckMsg{active: false})
+	d.waitFor("the volume restored", func() bool { return d.player.Volume() == 100 })
+
+	d.m.cfg.DuckVolume = 50
+	d.send(duckMsg{active: true})
+	d.waitFor("the volume lowered", func() bool { return d.player.Volume() == 50 })
+	d.keys("enter", "k", "enter")
+	d.waitFor("the auto duck disabled", func() bool { return !d.m.cfg.AutoDuck && !d.m.ducked })
+	d.waitFor("the volume restored", func() bool { return d.player.Volume() == 100 })
+}
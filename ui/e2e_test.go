@@ -0,0 +1,243 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/browser/browsertest"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/player"
+	"github.com/dancnb/sonicradio/player/playertest"
+)
+
+const uiWaitTimeout = 5 * time.Second
+
+// uiDriver runs the model like the bubbletea program, on the fake directory and player, without a terminal.
+// The keys are handled in the test goroutine, the commands run in their own, their messages being handled
+// while waiting for a condition.
+type uiDriver struct {
+	t      *testing.T
+	m      *Model
+	srv    *browsertest.Server
+	player *playertest.Fake
+	view   string
+
+	msgs chan tea.Msg
+	done chan struct{}
+}
+
+func newUIDriver(t *testing.T, stations ...browser.Station) *uiDriver {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("XDG_CACHE_HOME", dir)
+	// without a config file, the defaults are kept
+	cfg, _ := config.Load()
+	cfg.ApiRateLimit = 1000
+
+	srv := browsertest.NewServer(stations...)
+	t.Cleanup(srv.Close)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	fake := playertest.New()
+
+	d := &uiDriver{
+		t:      t,
+		m:      newModel(ctx, cfg, srv.Api(cfg), player.NewWithBackend(fake)),
+		srv:    srv,
+		player: fake,
+		msgs:   make(chan tea.Msg, 64),
+		done:   make(chan struct{}),
+	}
+	t.Cleanup(func() { close(d.done) })
+	// the message of tea.Sequence is unexported, the sequences of the model are delivered by the driver
+	prevSequence := sequenceCmds
+	sequenceCmds = func(cmds ...tea.Cmd) tea.Cmd {
+		return func() tea.Msg { return sequenceMsg(cmds) }
+	}
+	t.Cleanup(func() { sequenceCmds = prevSequence })
+	d.run(d.m.Init())
+	d.send(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return d
+}
+
+func (d *uiDriver) run(cmd tea.Cmd) {
+	if cmd != nil {
+		go func() { d.deliver(cmd()) }()
+	}
+}
+
+// sequenceMsg are the commands of Model.sequence, run in order by the driver.
+type sequenceMsg []tea.Cmd
+
+func (d *uiDriver) deliver(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case nil:
+		return
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			d.run(cmd)
+		}
+		return
+	case sequenceMsg:
+		for _, cmd := range msg {
+			if cmd != nil {
+				d.deliver(cmd())
+			}
+		}
+		return
+	}
+	select {
+	case d.msgs <- msg:
+	case <-d.done:
+	}
+}
+
+// send handles the message, then renders the view.
+func (d *uiDriver) send(msg tea.Msg) {
+	_, cmd := d.m.Update(msg)
+	d.run(cmd)
+	d.view = d.m.View()
+}
+
+func (d *uiDriver) keys(keys ...string) {
+	for _, k := range keys {
+		d.send(keyMsg(k))
+	}
+}
+
+func (d *uiDriver) typeText(text string) {
+	for _, r := range text {
+		d.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+// waitFor handles the messages of the commands until cond holds.
+func (d *uiDriver) waitFor(desc string, cond func() bool) {
+	d.t.Helper()
+	timeout := time.After(uiWaitTimeout)
+	poll := time.NewTicker(10 * time.Millisecond)
+	defer poll.Stop()
+	for !cond() {
+		select {
+		case msg := <-d.msgs:
+			d.send(msg)
+		case <-poll.C:
+		case <-timeout:
+			d.t.Fatalf("timed out waiting for %s, view:\n%s", desc, d.view)
+		}
+	}
+}
+
+func (d *uiDriver) browse() *browseTab {
+	return d.m.tabs[browseTabIx].(*browseTab)
+}
+
+func (d *uiDriver) listed() int {
+	b := d.browse()
+	return len(b.list.Items()) + len(b.window.pending)
+}
+
+func e2eStations(n int, name, tags string) []browser.Station {
+	res := make([]browser.Station, n)
+	for i := range res {
+		res[i] = browser.Station{
+			Stationuuid: fmt.Sprintf("%s-%d", tags, i),
+			Name:        fmt.Sprintf("%s %d", name, i),
+			URL:         fmt.Sprintf("http://stream.example.com/%s/%d", tags, i),
+			Tags:        tags,
+			Countrycode: "DE",
+			Votes:       int64(n - i),
+		}
+	}
+	return res
+}
+
+func Test_e2eSearchAndPlay(t *testing.T) {
+	d := newUIDriver(t, slices.Concat(e2eStations(3, "Jazz", "jazz"), e2eStations(3, "Rock", "rock"))...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 6 })
+
+	d.keys("s")
+	d.typeText("rock")
+	d.keys("enter")
+	d.waitFor("the search results", func() bool { return !d.browse().searchModel.searching && d.listed() == 3 })
+	if s := d.browse().list.SelectedItem().(browser.Station); s.Name != "Rock 0" {
+		t.Fatalf("selected %q, want the most voted Rock 0", s.Name)
+	}
+
+	d.keys("enter")
+	d.waitFor("the station to play", func() bool { return d.m.connecting == nil && d.m.delegate.currPlaying != nil })
+	if url, paused := d.player.Playing(); url != "http://stream.example.com/rock/0" || paused {
+		t.Errorf("playing %q paused=%v, want the stream of Rock 0", url, paused)
+	}
+	d.waitFor("the click to be counted", func() bool { return d.srv.Clicks("rock-0") == 1 })
+
+	d.keys(" ")
+	d.waitFor("the pause", func() bool { _, paused := d.player.Playing(); return paused })
+}
+
+func Test_e2eSearchByLink(t *testing.T) {
+	listed := browser.Station{Stationuuid: "9617a958-0601-11e8-ae97-52543be04c81", Name: "Listed", URL: "http://stream.example.com/listed"}
+	d := newUIDriver(t, append(e2eStations(3, "Jazz", "jazz"), listed)...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 4 })
+	search := func(text string) {
+		d.keys("s")
+		d.typeText(text)
+		d.keys("enter")
+		d.waitFor("the lookup", func() bool { return !d.browse().searchModel.searching && d.listed() == 1 })
+	}
+
+	search(strings.ToUpper(listed.Stationuuid))
+	if s := d.browse().list.SelectedItem().(browser.Station); s.Name != "Listed" || d.m.statusMsg != lookupFoundMsg {
+		t.Errorf("got %q with status %q, want the station of the uuid", s.Name, d.m.statusMsg)
+	}
+
+	search("http://stream.example.com/jazz/2")
+	if s := d.browse().list.SelectedItem().(browser.Station); s.Name != "Jazz 2" {
+		t.Errorf("got %q, want the station of the stream url", s.Name)
+	}
+
+	search("https://other.example.org/live")
+	s := d.browse().list.SelectedItem().(browser.Station)
+	if !s.Transient() || s.Name != "other.example.org" || d.m.statusMsg != transientStationMsg {
+		t.Fatalf("got %+v with status %q, want a transient station", s, d.m.statusMsg)
+	}
+	d.keys("f")
+	d.waitFor("the warning", func() bool { return d.m.statusMsg == transientFavoriteMsg })
+	if d.m.cfg.IsFavorite(s.Stationuuid) {
+		t.Error("expected the transient station not saved to favorites")
+	}
+	d.keys("enter")
+	d.waitFor("the stream to play", func() bool { return d.m.connecting == nil && d.m.delegate.currPlaying != nil })
+	if url, _ := d.player.Playing(); url != "https://other.example.org/live" {
+		t.Errorf("playing %q, want the pasted stream", url)
+	}
+}
+
+func Test_e2eLoadAll(t *testing.T) {
+	d := newUIDriver(t, e2eStations(70, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == browser.DefLimit })
+
+	d.keys("s")
+	d.typeText("jazz")
+	d.keys("enter")
+	d.waitFor("the first page", func() bool { return !d.browse().searchModel.searching && d.browse().nextPage != nil })
+	if n := d.listed(); n != browser.DefLimit {
+		t.Fatalf("listed %d stations, want the first %d", n, browser.DefLimit)
+	}
+
+	d.keys("A")
+	d.waitFor("all the pages", func() bool { return d.browse().loadAll == nil && d.listed() == 70 })
+	if d.browse().nextPage != nil {
+		t.Error("expected no page left")
+	}
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/events"
+)
+
+// startEvents serves the event stream read by `sonicradio events --json`.
+func (m *Model) startEvents() {
+	h, err := events.Listen(config.EventsSocketPath())
+	if errors.Is(err, events.ErrRunning) {
+		slog.With("method", "ui.Model.startEvents").Info("event stream served by another instance")
+		return
+	}
+	if err != nil {
+		slog.With("method", "ui.Model.startEvents").Error("event stream unavailable", "error", err)
+		return
+	}
+	m.events = h
+	m.eventVolume = m.cfg.GetVolume()
+}
+
+// publishEvents streams the station and volume changes. Like updateMpris, it runs after every update.
+func (m *Model) publishEvents() {
+	if m.events == nil || !m.delegate.playingMtx.TryRLock() {
+		return
+	}
+	var st *events.Station
+	if s := m.delegate.currPlaying; s != nil {
+		st = &events.Station{UUID: s.Stationuuid, Name: m.stationName(*s), URL: s.URLResolved}
+		if st.URL == "" {
+			st.URL = s.URL
+		}
+	}
+	m.delegate.playingMtx.RUnlock()
+
+	var uuid string
+	if st != nil {
+		uuid = st.UUID
+	}
+	if uuid != m.eventStation {
+		m.eventStation = uuid
+		m.events.Publish(events.Event{Type: events.StationChange, Station: st})
+	}
+	if vol := m.cfg.GetVolume(); vol != m.eventVolume {
+		m.eventVolume = vol
+		m.events.Publish(events.Event{Type: events.Volume, Volume: &vol})
+	}
+}
+
+func (m *Model) publishSong(stationUuid, stationName, title string) {
+	m.events.Publish(events.Event{
+		Type:    events.SongChange,
+		Station: &events.Station{UUID: stationUuid, Name: stationName},
+		Song:    title,
+	})
+}
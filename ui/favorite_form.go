@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/ui/styles"
+)
+
+const (
+	favoriteSavedMsg       = "%s saved"
+	favoriteFormHint       = "Leave the name empty to show the station name, the reminder empty to remove it"
+	favoriteNotFavorite    = "Add the station to favorites to edit it"
+	favoriteReminderErrMsg = "Invalid reminder: %v"
+)
+
+type favoriteInputIdx byte
+
+const (
+	favoriteAliasIdx favoriteInputIdx = iota
+	favoriteNoteIdx
+	favoriteReminderIdx
+)
+
+// favoriteForm edits the display name, the note and the reminder of a favorite.
+type favoriteForm struct {
+	enabled bool
+	style   *styles.Style
+
+	station browser.Station
+	inputs  []textinput.Model
+	idx     favoriteInputIdx
+
+	keymap favoriteFormKeymap
+	help   help.Model
+	width  int
+	height int
+}
+
+func newFavoriteForm(s *styles.Style) *favoriteForm {
+	h := help.New()
+	h.ShortSeparator = "   "
+	h.Styles = s.HelpStyles()
+	return &favoriteForm{
+		style: s,
+		inputs: []textinput.Model{
+			s.NewInputModel("Name          ", "station name", nil, nil, nil, nil),
+			s.NewInputModel("Note          ", "why it's saved, best shows, time zone", nil, nil, nil, nil),
+			s.NewInputModel("Reminder      ", "weekdays 18:00, mon,fri 7:30, daily 21:00", nil, nil, nil, nil),
+		},
+		keymap: newFavoriteFormKeymap(),
+		help:   h,
+	}
+}
+
+func (f *favoriteForm) setSize(width, height int) {
+	h, vf := f.style.DocStyle.GetFrameSize()
+	f.width = width - h
+	f.height = height - vf
+	f.help.Width = f.width
+	for i := range f.inputs {
+		f.inputs[i].Width = max(0, f.width-lipgloss.Width(f.inputs[i].Prompt)-1)
+	}
+}
+
+func (f *favoriteForm) focus() tea.Cmd {
+	var cmd tea.Cmd
+	for i := range f.inputs {
+		if i == int(f.idx) {
+			cmd = f.inputs[i].Focus()
+			continue
+		}
+		f.inputs[i].Blur()
+	}
+	return cmd
+}
+
+func (f *favoriteForm) View() string {
+	var b strings.Builder
+	b.WriteString("\n" + f.style.PrimaryColorStyle.MaxWidth(f.width).Render(f.station.Name) + "\n\n")
+	for i := range f.inputs {
+		b.WriteString(f.inputs[i].View() + "\n\n")
+	}
+	b.WriteString(f.style.ItalicStyle.Render(favoriteFormHint) + "\n")
+
+	help := f.style.HelpStyle.Render(f.help.View(&f.keymap))
+	for i := lipgloss.Height(b.String()); i < f.height-lipgloss.Height(help); i++ {
+		b.WriteString("\n")
+	}
+	return b.String() + help
+}
+
+// startFavoriteForm shows the display name, the note and the reminder of the selected favorite to edit them.
+func (m *Model) startFavoriteForm() tea.Cmd {
+	activeTab, ok := m.tabs[m.activeTabIdx].(stationTab)
+	if !ok {
+		return nil
+	}
+	s, ok := activeTab.Stations().list.SelectedItem().(browser.Station)
+	if !ok {
+		return nil
+	}
+	if !m.cfg.IsFavorite(s.Stationuuid) {
+		m.updateStatusWarn(favoriteNotFavorite)
+		return nil
+	}
+	f := m.favoriteForm
+	f.enabled = true
+	f.station = s
+	f.inputs[favoriteAliasIdx].SetValue(m.cfg.Alias(s.Stationuuid))
+	f.inputs[favoriteNoteIdx].SetValue(m.cfg.Note(s.Stationuuid))
+	f.inputs[favoriteReminderIdx].SetValue("")
+	if r, ok := m.cfg.Reminder(s.Stationuuid); ok {
+		f.inputs[favoriteReminderIdx].SetValue(r.Spec())
+	}
+	for i := range f.inputs {
+		f.inputs[i].CursorEnd()
+	}
+	f.idx = favoriteAliasIdx
+	f.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	return f.focus()
+}
+
+func (m *Model) updateFavoriteForm(msg tea.Msg) tea.Cmd {
+	f := m.favoriteForm
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, f.keymap.cancel):
+			f.enabled = false
+			return nil
+		case key.Matches(msg, f.keymap.save):
+			reminder, err := f.reminder()
+			if err != nil {
+				m.updateStatusWarn(fmt.Sprintf(favoriteReminderErrMsg, err))
+				f.idx = favoriteReminderIdx
+				return f.focus()
+			}
+			f.enabled = false
+			uuid := f.station.Stationuuid
+			m.cfg.SetAlias(uuid, f.inputs[favoriteAliasIdx].Value())
+			m.cfg.SetNote(uuid, f.inputs[favoriteNoteIdx].Value())
+			m.cfg.SetReminder(uuid, reminder)
+			m.updateStatus(fmt.Sprintf(favoriteSavedMsg, m.stationName(f.station)))
+			return nil
+		case key.Matches(msg, f.keymap.next):
+			f.idx = (f.idx + 1) % favoriteInputIdx(len(f.inputs))
+			return f.focus()
+		case key.Matches(msg, f.keymap.prev):
+			f.idx = (f.idx + favoriteInputIdx(len(f.inputs)) - 1) % favoriteInputIdx(len(f.inputs))
+			return f.focus()
+		}
+	}
+	var cmd tea.Cmd
+	f.inputs[f.idx], cmd = f.inputs[f.idx].Update(msg)
+	return cmd
+}
+
+// reminder returns the reminder entered, nil if empty.
+func (f *favoriteForm) reminder() (*config.Reminder, error) {
+	spec := strings.TrimSpace(f.inputs[favoriteReminderIdx].Value())
+	if spec == "" {
+		return nil, nil
+	}
+	r, err := config.ParseReminder(spec)
+	if err != nil {
+		return nil, err
+	}
+	r.StationName = f.station.Name
+	r.URL = f.station.URLResolved
+	if r.URL == "" {
+		r.URL = f.station.URL
+	}
+	return &r, nil
+}
+
+// stationName returns the name the station is displayed with, its alias for the favorites with one.
+func (m *Model) stationName(s browser.Station) string {
+	return m.cfg.StationName(s.Stationuuid, s.Name)
+}
+
+type favoriteFormKeymap struct {
+	next   key.Binding
+	prev   key.Binding
+	save   key.Binding
+	cancel key.Binding
+}
+
+func newFavoriteFormKeymap() favoriteFormKeymap {
+	return favoriteFormKeymap{
+		next: key.NewBinding(
+			key.WithKeys("tab", "down"),
+			key.WithHelp("tab/↓", "next field"),
+		),
+		prev: key.NewBinding(
+			key.WithKeys("shift+tab", "up"),
+			key.WithHelp("shift+tab/↑", "prev field"),
+		),
+		save: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "save"),
+		),
+		cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+	}
+}
+
+func (k *favoriteFormKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.next, k.prev, k.save, k.cancel}
+}
+
+func (k *favoriteFormKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
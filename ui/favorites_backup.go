@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	favoritesBackupFmt   = "Jan 2 15:04:05"
+	favoritesRestoredMsg = "Restored the %d favorites of %s"
+)
+
+// latestFavoritesBackup returns the last favorites backup written, for the favorites to be backed up
+// only once they change. Without one, the favorites are backed up on the first check.
+func latestFavoritesBackup() config.FavoritesBackup {
+	files, err := config.FavoritesBackups()
+	if err != nil || len(files) == 0 {
+		return config.FavoritesBackup{}
+	}
+	b, _ := config.ReadFavoritesBackup(files[0].Path)
+	return b
+}
+
+// backupFavorites writes a backup of the favorites, outside of the update, when they changed since the
+// last one.
+func (m *Model) backupFavorites(now time.Time) tea.Cmd {
+	keep := m.cfg.FavoriteBackupsKept()
+	b := m.cfg.FavoritesBackup()
+	if keep == 0 || b.Equal(m.favoritesBackup) {
+		return nil
+	}
+	m.favoritesBackup = b
+	return func() tea.Msg {
+		if err := config.WriteFavoritesBackup(b, now, keep); err != nil {
+			slog.With("method", "ui.Model.backupFavorites").Error("write favorites backup", "error", err)
+		}
+		return nil
+	}
+}
+
+// restoreFavorites replaces the favorites with the ones of the backup, the favorites replaced being
+// backed up first.
+func (m *Model) restoreFavorites(f config.FavoritesBackupFile) tea.Cmd {
+	b, err := config.ReadFavoritesBackup(f.Path)
+	if err != nil {
+		m.updateStatusError(err.Error())
+		return nil
+	}
+	backupCmd := m.backupFavorites(time.Now())
+	m.cfg.RestoreFavorites(b)
+	m.favoritesBackup = b
+	m.updateStatus(fmt.Sprintf(favoritesRestoredMsg, len(b.Favorites), f.Time.Format(favoritesBackupFmt)))
+	return tea.Batch(backupCmd, m.favoritesReqCmd)
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+func Test_e2eFavoritesBackup(t *testing.T) {
+	d := newUIDriver(t, e2eStations(3, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 3 })
+	backups := func(n int) func() bool {
+		return func() bool {
+			files, _ := config.FavoritesBackups()
+			return len(files) == n
+		}
+	}
+
+	start := time.Now().Add(-time.Hour)
+	d.m.cfg.SetFavorites([]string{"jazz-0", "jazz-1"})
+	d.run(d.m.backupFavorites(start))
+	d.waitFor("the first backup", backups(1))
+	if d.m.backupFavorites(start.Add(time.Minute)) != nil {
+		t.Error("expected no backup of the unchanged favorites")
+	}
+	d.m.cfg.SetFavorites([]string{"jazz-2"})
+	d.run(d.m.backupFavorites(start.Add(time.Minute)))
+	d.waitFor("the second backup", backups(2))
+	d.m.cfg.DeleteFavorite("jazz-2")
+
+	d.keys("S", "k", "k", "k", "enter")
+	for _, opt := range []string{"Current", "· 1 favorites", "· 2 favorites"} {
+		if !strings.Contains(d.view, opt) {
+			t.Fatalf("expected the backup %q listed:\n%s", opt, d.view)
+		}
+	}
+	d.keys("j", "j", "enter")
+	d.waitFor("the restore", func() bool { return slices.Equal(d.m.cfg.Favorites, []string{"jazz-0", "jazz-1"}) })
+	d.waitFor("the replaced favorites backed up", backups(3))
+	d.waitFor("the favorites reloaded", func() bool {
+		return len(d.m.tabs[favoriteTabIx].(stationTab).Stations().list.Items()) == 2
+	})
+}
@@ -0,0 +1,396 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/guide"
+	"github.com/dancnb/sonicradio/notify"
+	"github.com/dancnb/sonicradio/ui/styles"
+)
+
+const (
+	guideTimeFmt       = "Mon 15:04"
+	guideNextFmt       = "15:04"
+	guideNextMsg       = " (next: %s at %s)"
+	guideNotFavorite   = "Add the station to favorites to attach a program guide"
+	guideLoadingMsg    = "Loading the program guide..."
+	guideNoProgramsMsg = "No upcoming programs"
+	guideStartsMsg     = "%s starts on %s"
+	guideFollowMsg     = "Following %s"
+	guideUnfollowMsg   = "Not following %s"
+)
+
+// stationGuide is the schedule fetched for a station.
+type stationGuide struct {
+	programs []guide.Program
+	fetched  time.Time
+	loading  bool
+	err      error
+}
+
+// guideView lists the upcoming programs of a favorite station, to follow them or change the schedule url.
+type guideView struct {
+	enabled bool
+	style   *styles.Style
+
+	station  browser.Station
+	programs []guide.Program
+	idx      int
+
+	editing  bool
+	urlInput textinput.Model
+
+	keymap guideKeymap
+	help   help.Model
+	width  int
+	height int
+}
+
+func newGuideView(s *styles.Style) *guideView {
+	h := help.New()
+	h.ShortSeparator = "   "
+	h.Styles = s.HelpStyles()
+	return &guideView{
+		style:    s,
+		urlInput: s.NewInputModel("Schedule url", "iCalendar or JSON url", nil, nil, nil, nil),
+		keymap:   newGuideKeymap(),
+		help:     h,
+	}
+}
+
+func (v *guideView) setSize(width, height int) {
+	h, vf := v.style.DocStyle.GetFrameSize()
+	v.width = width - h
+	v.height = height - vf
+	v.help.Width = v.width
+	v.urlInput.Width = max(0, v.width-lipgloss.Width(v.urlInput.Prompt)-1)
+}
+
+func (v *guideView) View(cfg *config.Value, g *stationGuide) string {
+	var b strings.Builder
+	b.WriteString("\n" + v.style.PrimaryColorStyle.Render(cfg.StationName(v.station.Stationuuid, v.station.Name)) + "\n")
+	sg, _ := cfg.GetGuide(v.station.Stationuuid)
+	if v.editing {
+		b.WriteString("\n" + v.urlInput.View() + "\n")
+	} else if sg.URL != "" {
+		b.WriteString(v.style.ItalicStyle.Render(sg.URL) + "\n")
+	}
+	b.WriteString("\n")
+
+	switch {
+	case sg.URL == "":
+	case g == nil || g.loading:
+		b.WriteString(v.style.ItalicStyle.Render(guideLoadingMsg) + "\n")
+	case g.err != nil:
+		b.WriteString(v.style.ItalicStyle.Render(g.err.Error()) + "\n")
+	case len(v.programs) == 0:
+		b.WriteString(v.style.ItalicStyle.Render(guideNoProgramsMsg) + "\n")
+	}
+
+	now := time.Now()
+	help := v.style.HelpStyle.Render(v.help.View(&v.keymap))
+	rows := max(1, v.height-lipgloss.Height(b.String())-lipgloss.Height(help))
+	first := max(0, v.idx-rows+1)
+	for i := first; i < len(v.programs) && i < first+rows; i++ {
+		p := v.programs[i]
+		prefix := "  "
+		if !p.Start.After(now) {
+			prefix = styles.PlayChar + " "
+		}
+		title := p.Title
+		if slices.Contains(sg.Followed, p.Title) {
+			title += styles.FavChar
+		}
+		itStyle := v.style.SecondaryColorStyle
+		if i == v.idx {
+			itStyle = v.style.HistorySelItemStyle
+		}
+		start := v.style.ItalicStyle.Render(p.Start.Local().Format(guideTimeFmt) + "  ")
+		line := prefix + title
+		fill := max(0, v.width-lipgloss.Width(start)-lipgloss.Width(line))
+		b.WriteString(start + itStyle.Render(line+strings.Repeat(" ", fill)) + "\n")
+	}
+
+	for i := lipgloss.Height(b.String()); i < v.height-lipgloss.Height(help); i++ {
+		b.WriteString("\n")
+	}
+	return b.String() + help
+}
+
+// upcoming returns the programs on air or starting later.
+func upcoming(programs []guide.Program, now time.Time) []guide.Program {
+	var res []guide.Program
+	for _, p := range programs {
+		if p.Start.After(now) || p.End.After(now) {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// toggleGuide shows or hides the program guide of the selected favorite.
+func (m *Model) toggleGuide() tea.Cmd {
+	v := m.guideView
+	if v.enabled {
+		v.enabled = false
+		return nil
+	}
+	activeTab, ok := m.tabs[m.activeTabIdx].(stationTab)
+	if !ok {
+		return nil
+	}
+	s, ok := activeTab.Stations().list.SelectedItem().(browser.Station)
+	if !ok {
+		return nil
+	}
+	if !m.cfg.IsFavorite(s.Stationuuid) {
+		m.updateStatusWarn(guideNotFavorite)
+		return nil
+	}
+	v.enabled = true
+	v.station = s
+	v.idx = 0
+	v.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	m.setGuidePrograms()
+	if _, ok := m.cfg.GetGuide(s.Stationuuid); !ok {
+		return v.editURL("")
+	}
+	return nil
+}
+
+func (v *guideView) editURL(url string) tea.Cmd {
+	v.editing = true
+	v.urlInput.SetValue(url)
+	return v.urlInput.Focus()
+}
+
+// setGuidePrograms shows the fetched programs of the station in the guide view.
+func (m *Model) setGuidePrograms() {
+	v := m.guideView
+	v.programs = nil
+	if g := m.guides[v.station.Stationuuid]; g != nil {
+		v.programs = upcoming(g.programs, time.Now())
+	}
+	v.idx = min(v.idx, max(0, len(v.programs)-1))
+}
+
+func (m *Model) updateGuide(msg tea.Msg) tea.Cmd {
+	v := m.guideView
+	uuid := v.station.Stationuuid
+	if v.editing {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch {
+			case key.Matches(keyMsg, v.keymap.cancel):
+				v.editing = false
+				v.urlInput.Blur()
+				if _, ok := m.cfg.GetGuide(uuid); !ok {
+					v.enabled = false
+				}
+				return nil
+			case key.Matches(keyMsg, v.keymap.follow):
+				v.editing = false
+				v.urlInput.Blur()
+				url := strings.TrimSpace(v.urlInput.Value())
+				m.cfg.SetGuideURL(uuid, url)
+				delete(m.guides, uuid)
+				m.setGuidePrograms()
+				if url == "" {
+					return nil
+				}
+				return m.loadGuideCmd(uuid, url)
+			}
+		}
+		var cmd tea.Cmd
+		v.urlInput, cmd = v.urlInput.Update(msg)
+		return cmd
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	sg, hasGuide := m.cfg.GetGuide(uuid)
+	switch {
+	case key.Matches(keyMsg, v.keymap.up) && len(v.programs) > 0:
+		v.idx = (v.idx + len(v.programs) - 1) % len(v.programs)
+	case key.Matches(keyMsg, v.keymap.down) && len(v.programs) > 0:
+		v.idx = (v.idx + 1) % len(v.programs)
+	case key.Matches(keyMsg, v.keymap.follow) && len(v.programs) > 0:
+		title := v.programs[v.idx].Title
+		if m.cfg.ToggleFollowed(uuid, title) {
+			m.updateStatus(fmt.Sprintf(guideFollowMsg, title))
+		} else {
+			m.updateStatus(fmt.Sprintf(guideUnfollowMsg, title))
+		}
+	case key.Matches(keyMsg, v.keymap.edit):
+		return v.editURL(sg.URL)
+	case key.Matches(keyMsg, v.keymap.refresh) && hasGuide:
+		delete(m.guides, uuid)
+		m.setGuidePrograms()
+		return m.loadGuideCmd(uuid, sg.URL)
+	case key.Matches(keyMsg, v.keymap.remove) && hasGuide:
+		m.cfg.SetGuideURL(uuid, "")
+		delete(m.guides, uuid)
+		m.setGuidePrograms()
+	case key.Matches(keyMsg, v.keymap.cancel, m.delegate.keymap.guide):
+		v.enabled = false
+	}
+	return nil
+}
+
+// loadGuideCmd fetches the schedule of the station, keeping the previous programs until it's received.
+func (m *Model) loadGuideCmd(uuid, url string) tea.Cmd {
+	g := m.guides[uuid]
+	if g == nil {
+		g = &stationGuide{}
+		m.guides[uuid] = g
+	}
+	g.loading = true
+	g.fetched = time.Now()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), config.ApiReqTimeout)
+		defer cancel()
+		programs, err := guide.Fetch(ctx, url)
+		if err != nil {
+			slog.With("method", "ui.Model.loadGuideCmd").Info("", "uuid", uuid, "url", url, "error", err)
+		}
+		return guideMsg{uuid: uuid, url: url, programs: programs, err: err}
+	}
+}
+
+func (m *Model) onGuide(msg guideMsg) {
+	if sg, ok := m.cfg.GetGuide(msg.uuid); !ok || sg.URL != msg.url {
+		return
+	}
+	m.guides[msg.uuid] = &stationGuide{programs: msg.programs, fetched: time.Now(), err: msg.err}
+	if m.guideView.enabled && m.guideView.station.Stationuuid == msg.uuid {
+		m.setGuidePrograms()
+	}
+	m.setLivePrograms()
+}
+
+// checkGuides notifies the followed programs that started since the last tick, and refreshes the old schedules.
+func (m *Model) checkGuides(now time.Time) tea.Cmd {
+	from := m.guideChecked
+	m.guideChecked = now
+	m.setLivePrograms()
+	var cmds []tea.Cmd
+	for _, uuid := range m.cfg.GuideStations() {
+		sg, _ := m.cfg.GetGuide(uuid)
+		g := m.guides[uuid]
+		if g == nil || now.Sub(g.fetched) > config.GuideRefreshInterval {
+			cmds = append(cmds, m.loadGuideCmd(uuid, sg.URL))
+			continue
+		}
+		if from.IsZero() || len(sg.Followed) == 0 {
+			continue
+		}
+		for _, p := range guide.Starting(g.programs, from, now) {
+			if slices.Contains(sg.Followed, p.Title) {
+				m.notifyProgram(uuid, p)
+			}
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) notifyProgram(uuid string, p guide.Program) {
+	name := uuid
+	for _, s := range m.favoriteStations() {
+		if s.Stationuuid == uuid {
+			name = m.stationName(s)
+			break
+		}
+	}
+	notice := fmt.Sprintf(guideStartsMsg, p.Title, name)
+	data := notificationData{Program: p.Title, Station: name, Start: p.Start, End: p.End}
+	if text, ok := m.templates.notification.line(m.cfg.NotificationFormat, data); ok {
+		notice = text
+	}
+	slog.With("method", "ui.Model.notifyProgram").Info(notice)
+	m.guideNotice = notice
+	m.announcer.announce(notice)
+	if !m.cfg.Quiet(time.Now()) {
+		notify.Send(notice)
+	}
+}
+
+// currentProgram returns the title of the program on air of the station and the next one, if it has a guide.
+func (m *Model) currentProgram(uuid string) string {
+	g := m.guides[uuid]
+	if g == nil {
+		return ""
+	}
+	curr, next := guide.Current(g.programs, time.Now())
+	var res string
+	if curr != nil {
+		res = curr.Title
+	}
+	if next != nil {
+		res += fmt.Sprintf(guideNextMsg, next.Title, next.Start.Local().Format(guideNextFmt))
+	}
+	return strings.TrimSpace(res)
+}
+
+type guideKeymap struct {
+	up      key.Binding
+	down    key.Binding
+	follow  key.Binding
+	edit    key.Binding
+	refresh key.Binding
+	remove  key.Binding
+	cancel  key.Binding
+}
+
+func newGuideKeymap() guideKeymap {
+	return guideKeymap{
+		up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		follow: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "follow"),
+		),
+		edit: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "edit url"),
+		),
+		refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "refresh"),
+		),
+		remove: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "remove guide"),
+		),
+		cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back"),
+		),
+	}
+}
+
+func (k *guideKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.up, k.down, k.follow, k.edit, k.refresh, k.remove, k.cancel}
+}
+
+func (k *guideKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
@@ -0,0 +1,25 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/dancnb/sonicradio/metadata"
+)
+
+func Test_e2eIdentifyStale(t *testing.T) {
+	d := newUIDriver(t, e2eStations(2, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 2 })
+	d.keys("enter")
+	d.waitFor("the station to play", func() bool { return d.m.delegate.playingUuid() == "jazz-0" })
+
+	song := metadata.Song{Artist: "Artist", Title: "Old song"}
+	d.send(identifyMsg{stationUuid: "jazz-1", stationName: "Jazz 1", song: song})
+	if d.m.songTitle != "" || d.m.songChange.Station() == "jazz-1" {
+		t.Errorf("got song %q of %s, want the song of the previous station dropped", d.m.songTitle, d.m.songChange.Station())
+	}
+
+	d.send(identifyMsg{stationUuid: "jazz-0", stationName: "Jazz 0", song: song})
+	if d.m.songTitle != song.String() {
+		t.Errorf("got song %q, want %q", d.m.songTitle, song)
+	}
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	idleWarningMsg = "Playback stops in a minute without activity, press any key to keep listening"
+	idleStoppedMsg = "Playback stopped after %d hours without activity"
+)
+
+// observeInput records the last user interaction, which dismisses the idle warning and the program and reminder notices.
+func (m *Model) observeInput(msg tea.Msg) {
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg, mediaKeyMsg:
+		m.lastInput = time.Now()
+		m.idleWarning = false
+		m.guideNotice = ""
+		m.reminders.notice = ""
+	}
+}
+
+// checkIdle stops the playback when there was no user interaction for the configured hours,
+// with a warning shown a minute before.
+func (m *Model) checkIdle(now time.Time) tea.Cmd {
+	hours := m.cfg.IdleStopHours
+	if hours <= 0 {
+		m.idleWarning = false
+		return nil
+	}
+	m.delegate.playingMtx.RLock()
+	playing := m.delegate.currPlaying != nil
+	m.delegate.playingMtx.RUnlock()
+	if !playing {
+		m.idleWarning = false
+		return nil
+	}
+
+	idle := now.Sub(m.lastInput)
+	limit := time.Duration(hours) * time.Hour
+	switch {
+	case idle >= limit:
+		m.idleWarning = false
+		return m.delegate.stopCmd(fmt.Sprintf(idleStoppedMsg, hours))
+	case idle >= limit-config.IdleStopWarning:
+		if !m.idleWarning {
+			m.announcer.announce(idleWarningMsg)
+		}
+		m.idleWarning = true
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_e2eIdleStop(t *testing.T) {
+	d := newUIDriver(t, e2eStations(2, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 2 })
+	d.keys("enter")
+	d.waitFor("the station to play", func() bool { return d.m.connecting == nil && d.m.delegate.playingUuid() != "" })
+	d.m.cfg.IdleStopHours = 1
+	tick := func(now time.Time) { d.send(tickMsg{seq: d.m.ticker.seq, t: now}) }
+
+	// the last key pressed 50 minutes ago
+	last := time.Now().Add(-50 * time.Minute)
+	d.m.lastInput = last
+	tick(last.Add(time.Hour - 2*time.Minute))
+	if d.m.idleWarning {
+		t.Error("warned two minutes before the stop")
+	}
+	tick(last.Add(time.Hour - 30*time.Second))
+	if !d.m.idleWarning || !strings.Contains(d.view, idleWarningMsg) {
+		t.Fatalf("expected the warning a minute before the stop:\n%s", d.view)
+	}
+
+	// any key keeps the playback
+	d.keys("down")
+	if d.m.idleWarning || !d.m.lastInput.After(last) {
+		t.Fatal("expected the key to reset the idle time")
+	}
+	tick(last.Add(time.Hour + 10*time.Second))
+	if url, _ := d.player.Playing(); url == "" || strings.Contains(d.view, idleWarningMsg) {
+		t.Fatalf("stopped at the time of the previous activity, playing %q:\n%s", url, d.view)
+	}
+
+	tick(d.m.lastInput.Add(time.Hour + 10*time.Second))
+	d.waitFor("the stop", func() bool { url, _ := d.player.Playing(); return url == "" && d.m.delegate.playingUuid() == "" })
+	d.waitFor("the stop message", func() bool { return d.m.statusMsg == fmt.Sprintf(idleStoppedMsg, 1) })
+}
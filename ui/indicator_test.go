@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/dancnb/sonicradio/config"
+)
+
+// marked returns the indexes of the visible items rendered with the icon.
+func marked(l list.Model, d list.ItemDelegate, icon string) []int {
+	var res []int
+	for i, it := range l.VisibleItems() {
+		var b strings.Builder
+		d.Render(&b, l, i, it)
+		if strings.Contains(b.String(), strings.TrimSpace(icon)) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+func Test_e2eNowPlayingIndicator(t *testing.T) {
+	tests := []struct {
+		name    string
+		playing int
+		paused  bool
+		filter  string
+		want    []int
+	}{
+		{name: "nothing playing", playing: -1},
+		{name: "first item", playing: 0, want: []int{0}},
+		{name: "last item", playing: 3, want: []int{3}},
+		{name: "paused", playing: 2, paused: true, want: []int{2}},
+		{name: "filtered in", playing: 1, filter: "Jazz 1", want: []int{0}},
+		{name: "filtered out", playing: 2, filter: "Jazz 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stations := e2eStations(4, "Jazz", "jazz")
+			d := newUIDriver(t, stations...)
+			d.waitFor("the top stations", func() bool { return d.listed() == 4 })
+			if tt.playing >= 0 {
+				d.run(d.m.playStationCmd(stations[tt.playing]))
+				d.waitFor("the station to play", func() bool {
+					return d.m.connecting == nil && d.m.delegate.playingUuid() == stations[tt.playing].Stationuuid
+				})
+			}
+			if tt.paused {
+				d.keys(" ")
+				d.waitFor("the pause", func() bool { _, paused := d.m.delegate.nowPlaying(); return paused })
+			}
+			b := d.browse()
+			d.m.toBrowseTab()
+			if tt.filter != "" {
+				d.keys("/")
+				d.typeText(tt.filter)
+				d.keys("enter")
+				d.waitFor("the filter", func() bool {
+					return b.list.FilterState() == list.FilterApplied && len(b.list.VisibleItems()) == 1
+				})
+			}
+
+			if got := marked(b.list, d.m.delegate, nowPlayingIcon(tt.paused)); !slices.Equal(got, tt.want) {
+				t.Errorf("got the items %v marked, want %v", got, tt.want)
+			}
+			if tt.paused {
+				if got := marked(b.list, d.m.delegate, nowPlayingIcon(false)); got != nil {
+					t.Errorf("got the items %v marked playing, want them paused", got)
+				}
+			}
+		})
+	}
+}
+
+func Test_e2eNowPlayingIndicatorHistory(t *testing.T) {
+	stations := e2eStations(3, "Jazz", "jazz")
+	// the history lists the latest entry first: Jazz 2, Jazz 1, Jazz 0, Jazz 1
+	entries := []config.HistoryEntry{
+		{Uuid: stations[1].Stationuuid, Station: stations[1].Name, Song: "Swing"},
+		{Uuid: stations[0].Stationuuid, Station: stations[0].Name, Song: "Bossa"},
+		{Uuid: stations[1].Stationuuid, Station: stations[1].Name, Song: "Blues"},
+		{Uuid: stations[2].Stationuuid, Station: stations[2].Name, Song: "Bebop"},
+	}
+	tests := []struct {
+		name    string
+		playing int
+		want    []int
+	}{
+		{name: "first entry", playing: 2, want: []int{0}},
+		{name: "last entry", playing: 1, want: []int{1, 3}},
+		{name: "middle entry", playing: 0, want: []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newUIDriver(t, stations...)
+			d.waitFor("the top stations", func() bool { return d.listed() == 3 })
+			h := d.m.tabs[historyTabIx].(*historyTab)
+			h.setEntries(entries)
+			setPlaying(d, stations[tt.playing])
+
+			delegate := &historyEntryDelegate{keymap: &h.keymap, style: h.style, nowPlaying: d.m.delegate.nowPlaying}
+			if got := marked(h.list, delegate, nowPlayingIcon(false)); !slices.Equal(got, tt.want) {
+				t.Errorf("got the entries %v marked, want %v", got, tt.want)
+			}
+		})
+	}
+}
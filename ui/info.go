@@ -3,22 +3,32 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
 	"github.com/dancnb/sonicradio/ui/styles"
 )
 
+const localTimeFmt = "Mon 15:04"
+
 type infoModel struct {
 	enabled bool
 
 	style *styles.Style
 
 	b       *browser.Api
+	cfg     *config.Value
 	station browser.Station
+	art     *artworkModel
+	// live are the audio parameters of the playing station
+	live *liveStream
+	// the station already voted for, voted again only when the vote key is pressed twice
+	voteAgain string
 
 	keymap infoKeymap
 	help   help.Model
@@ -26,7 +36,7 @@ type infoModel struct {
 	height int
 }
 
-func newInfoModel(b *browser.Api, s *styles.Style) *infoModel {
+func newInfoModel(b *browser.Api, cfg *config.Value, s *styles.Style) *infoModel {
 	k := newInfoKeymap()
 
 	h := help.New()
@@ -36,6 +46,7 @@ func newInfoModel(b *browser.Api, s *styles.Style) *infoModel {
 
 	return &infoModel{
 		b:      b,
+		cfg:    cfg,
 		style:  s,
 		keymap: k,
 		help:   h,
@@ -44,7 +55,11 @@ func newInfoModel(b *browser.Api, s *styles.Style) *infoModel {
 
 func (i *infoModel) Init(s browser.Station) tea.Cmd {
 	i.station = s
+	i.voteAgain = ""
 	i.setEnabled(true)
+	if i.art != nil {
+		return i.art.detailCmd(s)
+	}
 	return nil
 }
 
@@ -76,11 +91,23 @@ func (i *infoModel) Update(msg tea.Msg) (*infoModel, tea.Cmd) {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, i.keymap.vote):
+			if i.station.Transient() {
+				return i, func() tea.Msg { return warnMsg(transientVoteMsg) }
+			}
+			uuid := i.station.Stationuuid
+			if a := i.cfg.Activity(uuid); a.Votes > 0 && i.voteAgain != uuid {
+				i.voteAgain = uuid
+				return i, func() tea.Msg {
+					return warnMsg(fmt.Sprintf(alreadyVoted, a.LastVote.Format(time.DateOnly)))
+				}
+			}
+			i.voteAgain = ""
 			return i, func() tea.Msg {
-				err := i.b.StationVote(i.station.Stationuuid)
+				err := i.b.StationVote(uuid)
 				if err != nil {
-					return statusMsg(err.Error())
+					return errorMsg(err.Error())
 				}
+				i.cfg.AddVote(uuid)
 				return statusMsg(voteSuccesful)
 			}
 		case key.Matches(msg, i.keymap.cancel):
@@ -97,6 +124,9 @@ func (i *infoModel) Update(msg tea.Msg) (*infoModel, tea.Cmd) {
 func (i *infoModel) View() string {
 	var b strings.Builder
 	i.renderInfoField(&b, "Name          ", i.station.Name)
+	if alias := i.cfg.Alias(i.station.Stationuuid); alias != "" {
+		i.renderInfoField(&b, "Alias         ", alias)
+	}
 	i.renderInfoField(&b, "Homepage      ", i.station.Homepage)
 	i.renderInfoField(&b, "Stream URL    ", i.station.URL)
 	i.renderInfoField(&b, "Tags          ", i.station.Tags)
@@ -107,18 +137,21 @@ func (i *infoModel) View() string {
 		trend = "+" + trend
 	}
 	i.renderInfoField(&b, "Trending      ", trend)
-	i.renderInfoField(&b, "Codec         ", i.station.Codec)
-	br := ""
-	if i.station.Bitrate != 0 {
-		br = fmt.Sprintf("%d", i.station.Bitrate)
-	}
-	i.renderInfoField(&b, "Bitrate       ", br)
+	activity := i.cfg.Activity(i.station.Stationuuid)
+	i.renderInfoField(&b, "My plays      ", activity.ClicksString())
+	i.renderInfoField(&b, "My votes      ", activity.VotesString())
+	live := i.live.get(i.station.Stationuuid)
+	i.renderInfoField(&b, "Codec         ", liveCodecView(live, i.station.Codec))
+	i.renderInfoField(&b, "Bitrate       ", liveBitrateView(live, i.station.Bitrate))
 	country := i.station.Country
 	cc := strings.TrimSpace(i.station.Countrycode)
 	if cc != "" {
 		country += fmt.Sprintf(" [%s]", cc)
 	}
 	i.renderInfoField(&b, "Country       ", country)
+	if loc, approx, ok := i.station.TimeZone(); ok {
+		i.renderInfoField(&b, "Local time    ", stationLocalTime(time.Now(), loc, approx))
+	}
 	i.renderInfoField(&b, "State         ", i.station.State)
 	i.renderInfoField(&b, "Language      ", i.station.Language)
 	i.renderInfoField(&b, "Last ok check ", i.station.Lastcheckoktime)
@@ -132,6 +165,18 @@ func (i *infoModel) View() string {
 		long = fmt.Sprintf("%v", i.station.GeoLong)
 	}
 	i.renderInfoField(&b, "Geo longitude ", long)
+	if note := i.cfg.Note(i.station.Stationuuid); note != "" {
+		i.renderInfoNote(&b, note)
+	}
+
+	if i.art != nil {
+		if art := i.art.view(i.station.Stationuuid); art != "" && lipgloss.Width(b.String())+artCols+2 <= i.width {
+			content := lipgloss.JoinHorizontal(lipgloss.Top, b.String(), "  ", art)
+			b.Reset()
+			b.WriteString(content)
+			b.WriteString("\n")
+		}
+	}
 
 	availHeight := i.height
 	help := i.style.HelpStyle.Render(i.help.View(&i.keymap))
@@ -157,6 +202,23 @@ func (i *infoModel) renderInfoField(b *strings.Builder, fieldName, fieldValue st
 	b.WriteString("\n")
 }
 
+// renderInfoNote wraps the note of a favorite, which can be longer than a line.
+func (i *infoModel) renderInfoNote(b *strings.Builder, note string) {
+	fnRender := i.style.InfoFieldNameStyle.Render(styles.PadFieldName("Note          ", nil))
+	fv := i.style.SecondaryColorStyle.Width(max(1, i.width-lipgloss.Width(fnRender))).Render(note)
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, fnRender, fv))
+	b.WriteString("\n")
+}
+
+// stationLocalTime shows the time at the station, marked with ~ when its zone is a guess.
+func stationLocalTime(now time.Time, loc *time.Location, approx bool) string {
+	res := fmt.Sprintf("%s (%s)", now.In(loc).Format(localTimeFmt), loc)
+	if approx {
+		res = "~ " + res
+	}
+	return res
+}
+
 type infoKeymap struct {
 	cancel key.Binding
 	vote   key.Binding
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+)
+
+func Test_e2eToPlaying(t *testing.T) {
+	tests := []struct {
+		name    string
+		playing int
+		from    uiTabIndex
+		sel     int
+		filter  string
+		// wantFiltered is whether the filter is kept, the station being visible
+		wantFiltered bool
+		wantSel      int
+	}{
+		{name: "first item", playing: 0, from: browseTabIx, sel: 3, wantSel: 0},
+		{name: "last item", playing: 3, from: browseTabIx, sel: 0, wantSel: 3},
+		{name: "filtered out", playing: 3, from: browseTabIx, filter: "Jazz 1", wantSel: 3},
+		{name: "filtered in", playing: 1, from: browseTabIx, filter: "Jazz 1", wantFiltered: true, wantSel: 0},
+		{name: "from the favorites", playing: 2, from: favoriteTabIx, wantSel: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stations := e2eStations(4, "Jazz", "jazz")
+			d := newUIDriver(t, stations...)
+			d.waitFor("the top stations", func() bool { return d.listed() == 4 })
+			d.run(d.m.playStationCmd(stations[tt.playing]))
+			d.waitFor("the station to play", func() bool {
+				return d.m.connecting == nil && d.m.delegate.playingUuid() == stations[tt.playing].Stationuuid
+			})
+
+			b := d.browse()
+			b.list.Select(tt.sel)
+			if tt.filter != "" {
+				d.m.toBrowseTab()
+				d.keys("/")
+				d.typeText(tt.filter)
+				d.keys("enter")
+				d.waitFor("the filter", func() bool { return b.list.FilterState() == list.FilterApplied && len(b.list.VisibleItems()) == 1 })
+			}
+			d.m.activeTabIdx = tt.from
+
+			d.keys("J")
+			if d.m.activeTabIdx != browseTabIx {
+				t.Errorf("got tab %d, want the browse tab", d.m.activeTabIdx)
+			}
+			if got := b.list.FilterState() == list.FilterApplied; got != tt.wantFiltered {
+				t.Errorf("got filter applied %v, want %v", got, tt.wantFiltered)
+			}
+			if b.list.Index() != tt.wantSel {
+				t.Errorf("got index %d, want %d", b.list.Index(), tt.wantSel)
+			}
+			if s := b.list.SelectedItem().(browser.Station); s.Stationuuid != stations[tt.playing].Stationuuid {
+				t.Errorf("got %q selected, want the station playing", s.Name)
+			}
+		})
+	}
+}
+
+// setPlaying makes the station the one playing, without playing it nor adding it to the history.
+func setPlaying(d *uiDriver, s browser.Station) {
+	d.m.delegate.playingMtx.Lock()
+	defer d.m.delegate.playingMtx.Unlock()
+	d.m.delegate.currPlaying = &s
+}
+
+func Test_e2eToPlayingHistory(t *testing.T) {
+	stations := e2eStations(3, "Jazz", "jazz")
+	// the history lists the latest entry first: Jazz 2, Jazz 1 (Blues), Jazz 0, Jazz 1 (Swing)
+	entries := []config.HistoryEntry{
+		{Uuid: stations[1].Stationuuid, Station: stations[1].Name, Song: "Swing"},
+		{Uuid: stations[0].Stationuuid, Station: stations[0].Name, Song: "Bossa"},
+		{Uuid: stations[1].Stationuuid, Station: stations[1].Name, Song: "Blues"},
+		{Uuid: stations[2].Stationuuid, Station: stations[2].Name, Song: "Bebop"},
+	}
+	tests := []struct {
+		name    string
+		playing browser.Station
+		sel     int
+		filter  string
+		wantSel int
+	}{
+		{name: "first entry", playing: stations[2], sel: 3, wantSel: 0},
+		{name: "last entry", playing: stations[0], sel: 0, wantSel: 2},
+		{name: "latest entry of the station", playing: stations[1], sel: 3, wantSel: 1},
+		{name: "filtered out", playing: stations[0], filter: "Bebop", wantSel: 2},
+		{name: "not in the lists", playing: browser.Station{Stationuuid: "other", Name: "Other"}, wantSel: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newUIDriver(t, stations...)
+			d.waitFor("the top stations", func() bool { return d.listed() == 3 })
+			h := d.m.tabs[historyTabIx].(*historyTab)
+			h.setEntries(entries)
+			setPlaying(d, tt.playing)
+
+			d.m.toHistoryTab()
+			h.list.Select(tt.sel)
+			if tt.filter != "" {
+				d.keys("/")
+				d.typeText(tt.filter)
+				d.keys("enter")
+				d.waitFor("the filter", func() bool { return h.list.FilterState() == list.FilterApplied && len(h.list.VisibleItems()) == 1 })
+			}
+
+			d.m.statusMsg = ""
+			d.keys("J")
+			if tt.wantSel < 0 {
+				if d.m.statusMsg != playingNotListed {
+					t.Errorf("got status %q, want %q", d.m.statusMsg, playingNotListed)
+				}
+				return
+			}
+			if d.m.activeTabIdx != historyTabIx {
+				t.Errorf("got tab %d, want the history tab", d.m.activeTabIdx)
+			}
+			if h.list.FilterState() != list.Unfiltered {
+				t.Errorf("got filter %v, want it cleared", h.list.FilterState())
+			}
+			if h.list.Index() != tt.wantSel {
+				t.Errorf("got index %d, want %d", h.list.Index(), tt.wantSel)
+			}
+		})
+	}
+}
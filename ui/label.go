@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	labelSetMsg      = "%s labeled %s"
+	labelRemovedMsg  = "%s label removed"
+	labelNotFavorite = "Add the station to favorites to label it"
+	labelFilterChar  = "@"
+)
+
+// cycleLabel moves the selected favorite to the next color label.
+func (m *Model) cycleLabel() {
+	activeTab, ok := m.tabs[m.activeTabIdx].(stationTab)
+	if !ok {
+		return
+	}
+	s, ok := activeTab.Stations().list.SelectedItem().(browser.Station)
+	if !ok {
+		return
+	}
+	if !m.cfg.IsFavorite(s.Stationuuid) {
+		m.updateStatusWarn(labelNotFavorite)
+		return
+	}
+	l := m.cfg.Label(s.Stationuuid).Next()
+	m.cfg.SetLabel(s.Stationuuid, l)
+	if l == config.NoLabel {
+		m.updateStatus(fmt.Sprintf(labelRemovedMsg, m.stationName(s)))
+	} else {
+		m.updateStatus(fmt.Sprintf(labelSetMsg, m.stationName(s), l))
+	}
+}
+
+// labelFilter splits a filter term like "@red jazz" into the label and the rest of the term.
+func labelFilter(term string) (config.Label, string) {
+	fields := strings.Fields(term)
+	for i, f := range fields {
+		name, ok := strings.CutPrefix(f, labelFilterChar)
+		if !ok {
+			continue
+		}
+		if l, ok := config.ParseLabel(name); ok {
+			return l, strings.Join(append(fields[:i:i], fields[i+1:]...), " ")
+		}
+	}
+	return config.NoLabel, term
+}
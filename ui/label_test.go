@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+func Test_labelFilter(t *testing.T) {
+	tests := []struct {
+		term      string
+		wantLabel config.Label
+		wantTerm  string
+	}{
+		{"@red jazz", config.LabelRed, "jazz"},
+		{"jazz @bl", config.LabelBlue, "jazz"},
+		{"@gr", config.LabelGreen, ""},
+		{"@pink jazz", config.NoLabel, "@pink jazz"},
+		{"jazz", config.NoLabel, "jazz"},
+	}
+	for _, tt := range tests {
+		l, term := labelFilter(tt.term)
+		if l != tt.wantLabel || term != tt.wantTerm {
+			t.Errorf("labelFilter(%q)=%v,%q want %v,%q", tt.term, l, term, tt.wantLabel, tt.wantTerm)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+)
+
+// listeningViewMax is the number of languages shown in the listening time
+const listeningViewMax = 5
+
+// preferredOrder lists the talk and news stations first when preferred, for the language learners.
+func (m *Model) preferredOrder(stations []browser.Station) []browser.Station {
+	if !m.cfg.PreferTalk {
+		return stations
+	}
+	return browser.TalkFirst(stations)
+}
+
+// listeningView describes the time listened to the most listened languages.
+func listeningView(times []config.LanguageTime) string {
+	if len(times) == 0 {
+		return "none yet"
+	}
+	views := make([]string, 0, listeningViewMax)
+	for _, t := range times[:min(len(times), listeningViewMax)] {
+		views = append(views, t.Language+" "+listeningTime(t.Time))
+	}
+	return strings.Join(views, ", ")
+}
+
+func listeningTime(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+}
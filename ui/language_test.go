@@ -0,0 +1,21 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+func TestListeningView(t *testing.T) {
+	times := []config.LanguageTime{
+		{Language: "french", Time: 2*time.Hour + 15*time.Minute},
+		{Language: "english", Time: 40 * time.Minute},
+	}
+	if got, want := listeningView(times), "french 2h 15m, english 40m"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := listeningView(nil), "none yet"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
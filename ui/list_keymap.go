@@ -8,6 +8,10 @@ func newListKeymap() listKeymap {
 			key.WithKeys("s"),
 			key.WithHelp("s", "search"),
 		),
+		loadAll: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "load all matching"),
+		),
 		toNowPlaying: key.NewBinding(
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "go to now playing"),
@@ -32,6 +36,10 @@ func newListKeymap() listKeymap {
 			key.WithKeys("H"),
 			key.WithHelp("H", "go to history tab"),
 		),
+		recordingsTab: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "go to recordings tab"),
+		),
 		settingsTab: key.NewBinding(
 			key.WithKeys("S"),
 			key.WithHelp("S", "go to settings tab"),
@@ -61,27 +69,31 @@ func newListKeymap() listKeymap {
 }
 
 type listKeymap struct {
-	search       key.Binding
-	toNowPlaying key.Binding
-	nextTab      key.Binding
-	prevTab      key.Binding
-	favoritesTab key.Binding
-	browseTab    key.Binding
-	historyTab   key.Binding
-	settingsTab  key.Binding
-	stationView  key.Binding
-	digits       []key.Binding
-	digitHelp    key.Binding
+	search        key.Binding
+	loadAll       key.Binding
+	toNowPlaying  key.Binding
+	nextTab       key.Binding
+	prevTab       key.Binding
+	favoritesTab  key.Binding
+	browseTab     key.Binding
+	historyTab    key.Binding
+	recordingsTab key.Binding
+	settingsTab   key.Binding
+	stationView   key.Binding
+	digits        []key.Binding
+	digitHelp     key.Binding
 }
 
 func (k *listKeymap) setEnabled(v bool) {
 	k.search.SetEnabled(v)
+	k.loadAll.SetEnabled(v)
 	k.toNowPlaying.SetEnabled(v)
 	k.nextTab.SetEnabled(v)
 	k.prevTab.SetEnabled(v)
 	k.favoritesTab.SetEnabled(v)
 	k.browseTab.SetEnabled(v)
 	k.historyTab.SetEnabled(v)
+	k.recordingsTab.SetEnabled(v)
 	k.settingsTab.SetEnabled(v)
 	k.stationView.SetEnabled(v)
 	for i := range k.digits {
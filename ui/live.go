@@ -0,0 +1,237 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/guide"
+	"github.com/dancnb/sonicradio/ui/styles"
+)
+
+const (
+	liveTitleFmt   = "Live now · %s"
+	liveTimeFmt    = "15:04 MST"
+	liveNoGuides   = "Attach a program guide to the favorites with ctrl+g"
+	liveNothingMsg = "Nothing on air"
+	liveLoadingMsg = "Loading the program guides..."
+)
+
+// liveProgram is a program on air on a favorite station.
+type liveProgram struct {
+	station browser.Station
+	program guide.Program
+	next    *guide.Program
+	score   int
+}
+
+// liveView lists the programs on air on the favorites with a guide, the most interesting first.
+type liveView struct {
+	enabled bool
+	style   *styles.Style
+
+	programs []liveProgram
+	loading  bool
+	idx      int
+
+	keymap liveKeymap
+	help   help.Model
+	width  int
+	height int
+}
+
+func newLiveView(s *styles.Style) *liveView {
+	h := help.New()
+	h.ShortSeparator = "   "
+	h.Styles = s.HelpStyles()
+	return &liveView{
+		style:  s,
+		keymap: newLiveKeymap(),
+		help:   h,
+	}
+}
+
+func (v *liveView) setSize(width, height int) {
+	h, vf := v.style.DocStyle.GetFrameSize()
+	v.width = width - h
+	v.height = height - vf
+	v.help.Width = v.width
+}
+
+func (v *liveView) View(cfg *config.Value) string {
+	var b strings.Builder
+	now := time.Now()
+	// the times are shown in the local time zone, whatever the zone of the schedules
+	b.WriteString("\n" + v.style.PrimaryColorStyle.Render(fmt.Sprintf(liveTitleFmt, now.Format(liveTimeFmt))) + "\n\n")
+
+	switch {
+	case len(cfg.GuideStations()) == 0:
+		b.WriteString(v.style.ItalicStyle.Render(liveNoGuides) + "\n")
+	case len(v.programs) == 0 && v.loading:
+		b.WriteString(v.style.ItalicStyle.Render(liveLoadingMsg) + "\n")
+	case len(v.programs) == 0:
+		b.WriteString(v.style.ItalicStyle.Render(liveNothingMsg) + "\n")
+	}
+
+	help := v.style.HelpStyle.Render(v.help.View(&v.keymap))
+	rows := max(1, (v.height-lipgloss.Height(b.String())-lipgloss.Height(help))/2)
+	first := max(0, v.idx-rows+1)
+	for i := first; i < len(v.programs) && i < first+rows; i++ {
+		p := v.programs[i]
+		itStyle := v.style.SecondaryColorStyle
+		if i == v.idx {
+			itStyle = v.style.HistorySelItemStyle
+		}
+		line := cfg.StationName(p.station.Stationuuid, p.station.Name) + "  " + styles.PlayChar + " " + p.program.Title
+		fill := max(0, v.width-lipgloss.Width(line))
+		b.WriteString(itStyle.Render(line+strings.Repeat(" ", fill)) + "\n")
+
+		var desc []string
+		if !p.program.End.IsZero() {
+			desc = append(desc, "until "+p.program.End.Local().Format(guideNextFmt))
+		}
+		if p.next != nil {
+			desc = append(desc, "next "+p.next.Title+" at "+p.next.Start.Local().Format(guideNextFmt))
+		}
+		if p.station.Tags != "" {
+			desc = append(desc, p.station.Tags)
+		}
+		b.WriteString(v.style.ItalicStyle.MaxWidth(v.width).Render("  "+strings.Join(desc, " · ")) + "\n")
+	}
+
+	for i := lipgloss.Height(b.String()); i < v.height-lipgloss.Height(help); i++ {
+		b.WriteString("\n")
+	}
+	return b.String() + help
+}
+
+// liveNow returns the programs on air on the stations, sorted by the interest score of the
+// station tags, then by the time left.
+func liveNow(stations []browser.Station, guides map[string]*stationGuide, weights map[string]int, now time.Time) []liveProgram {
+	var res []liveProgram
+	for _, s := range stations {
+		g := guides[s.Stationuuid]
+		if g == nil {
+			continue
+		}
+		curr, next := guide.Current(g.programs, now)
+		if curr == nil {
+			continue
+		}
+		res = append(res, liveProgram{station: s, program: *curr, next: next, score: config.InterestScore(s.Tags, weights)})
+	}
+	slices.SortStableFunc(res, func(a, b liveProgram) int {
+		if c := cmp.Compare(b.score, a.score); c != 0 {
+			return c
+		}
+		if a.program.End.IsZero() || b.program.End.IsZero() {
+			return cmp.Compare(boolInt(a.program.End.IsZero()), boolInt(b.program.End.IsZero()))
+		}
+		return a.program.End.Compare(b.program.End)
+	})
+	return res
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// toggleLive shows or hides the programs on air.
+func (m *Model) toggleLive() tea.Cmd {
+	v := m.liveView
+	if v.enabled {
+		v.enabled = false
+		return nil
+	}
+	v.enabled = true
+	v.idx = 0
+	v.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	m.setLivePrograms()
+	return nil
+}
+
+// setLivePrograms ranks the programs on air, when the view is shown or a schedule is received.
+func (m *Model) setLivePrograms() {
+	v := m.liveView
+	if !v.enabled {
+		return
+	}
+	stations := m.favoriteStations()
+	var tags []string
+	for _, s := range stations {
+		tags = append(tags, strings.Split(s.Tags, ",")...)
+	}
+	v.programs = liveNow(stations, m.guides, m.cfg.InterestWeights(tags), time.Now())
+	v.loading = false
+	for _, g := range m.guides {
+		v.loading = v.loading || g.loading
+	}
+	v.idx = min(v.idx, max(0, len(v.programs)-1))
+}
+
+func (m *Model) updateLive(msg tea.Msg) tea.Cmd {
+	v := m.liveView
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	switch {
+	case key.Matches(keyMsg, v.keymap.up) && len(v.programs) > 0:
+		v.idx = (v.idx + len(v.programs) - 1) % len(v.programs)
+	case key.Matches(keyMsg, v.keymap.down) && len(v.programs) > 0:
+		v.idx = (v.idx + 1) % len(v.programs)
+	case key.Matches(keyMsg, v.keymap.play) && len(v.programs) > 0:
+		v.enabled = false
+		return m.playStationCmd(v.programs[v.idx].station)
+	case key.Matches(keyMsg, v.keymap.cancel, m.delegate.keymap.live):
+		v.enabled = false
+	}
+	return nil
+}
+
+type liveKeymap struct {
+	up     key.Binding
+	down   key.Binding
+	play   key.Binding
+	cancel key.Binding
+}
+
+func newLiveKeymap() liveKeymap {
+	return liveKeymap{
+		up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		play: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "play"),
+		),
+		cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back"),
+		),
+	}
+}
+
+func (k *liveKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.up, k.down, k.play, k.cancel}
+}
+
+func (k *liveKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	loadAllNoPagesMsg  = "No more matching stations to load, search the server first"
+	loadAllProgressMsg = "Loading all the matching stations: %d, press A to stop"
+	loadAllDoneMsg     = "Loaded all the %d matching stations"
+	loadAllMaxMsg      = "Loaded the first %d matching stations, press A to load more"
+	loadAllStoppedMsg  = "Stopped loading at %d matching stations"
+	loadAllErrMsg      = "Loading the matching stations failed at %d: %v"
+)
+
+// loadAll fetches the next pages of the listed search in the background, one at a time.
+type loadAll struct {
+	seq    int
+	ctx    context.Context
+	cancel context.CancelFunc
+	params browser.SearchParams
+	// seen has the uuids of the listed stations, as the pages shift when the server adds or removes stations
+	seen map[string]bool
+	// max is the count of stations to stop at
+	max int
+}
+
+// nextPage is the page of the search after the stations, nil when they are all the matching ones.
+func nextPage(params *browser.SearchParams, stations []browser.Station) *browser.SearchParams {
+	if params == nil || params.Limit <= 0 || len(stations) < params.Limit {
+		return nil
+	}
+	next := *params
+	next.Offset += len(stations)
+	next.Limit = config.LoadAllPageSize
+	return &next
+}
+
+// allStations are the stations of the list, with the ones not yet handed to the list model.
+func (t *browseTab) allStations() []browser.Station {
+	items := t.list.Items()
+	res := make([]browser.Station, 0, len(items)+len(t.window.pending))
+	for i := range items {
+		if s, ok := items[i].(browser.Station); ok {
+			res = append(res, s)
+		}
+	}
+	return append(res, t.window.pending...)
+}
+
+// listSearch stops loading the pages of the previous stations, keeping the next page of the new ones.
+func (t *browseTab) listSearch(params *browser.SearchParams, stations []browser.Station) {
+	t.stopLoadAll()
+	t.nextPage = nextPage(params, stations)
+}
+
+// toggleLoadAll starts loading the rest of the matching stations, or stops it.
+func (t *browseTab) toggleLoadAll(m *Model) tea.Cmd {
+	if t.loadAll != nil {
+		t.stopLoadAll()
+		m.updateStatus(fmt.Sprintf(loadAllStoppedMsg, len(t.allStations())))
+		return nil
+	}
+	if t.nextPage == nil {
+		m.updateStatusWarn(loadAllNoPagesMsg)
+		return nil
+	}
+	stations := t.allStations()
+	seen := make(map[string]bool, len(stations))
+	for _, s := range stations {
+		seen[s.Stationuuid] = true
+	}
+	t.loadAllSeq++
+	ctx, cancel := context.WithCancel(t.searchModel.ctx)
+	t.loadAll = &loadAll{
+		seq:    t.loadAllSeq,
+		ctx:    ctx,
+		cancel: cancel,
+		params: *t.nextPage,
+		seen:   seen,
+		max:    len(stations) + config.LoadAllMax,
+	}
+	m.updateStatus(fmt.Sprintf(loadAllProgressMsg, len(stations)))
+	return t.loadPageCmd(0)
+}
+
+func (t *browseTab) stopLoadAll() {
+	if t.loadAll != nil {
+		t.loadAll.cancel()
+		t.loadAll = nil
+	}
+}
+
+// loadPageCmd fetches the next page after the delay, which keeps the requests to the server apart.
+func (t *browseTab) loadPageCmd(delay time.Duration) tea.Cmd {
+	l := t.loadAll
+	api, ctx, seq, params := t.searchModel.browser, l.ctx, l.seq, l.params
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		stations, err := api.SearchCtx(ctx, params)
+		return loadAllPageMsg{seq: seq, stations: stations, err: err}
+	})
+}
+
+// onLoadAllPage appends the new stations of the page and fetches the next one, until the last page.
+func (t *browseTab) onLoadAllPage(m *Model, msg loadAllPageMsg) tea.Cmd {
+	l := t.loadAll
+	if l == nil || l.seq != msg.seq {
+		return nil
+	}
+	if msg.err != nil {
+		// the stations of the local index, on ErrLocalResults, are not a page of the search
+		t.stopLoadAll()
+		m.updateStatusError(fmt.Sprintf(loadAllErrMsg, len(t.allStations()), msg.err))
+		return nil
+	}
+
+	var page []browser.Station
+	for _, s := range msg.stations {
+		if !l.seen[s.Stationuuid] {
+			l.seen[s.Stationuuid] = true
+			page = append(page, s)
+		}
+	}
+	cmd := t.addStations(m.preferredOrder(page))
+	t.nextPage = nextPage(&l.params, msg.stations)
+	n := len(t.allStations())
+	switch {
+	case t.nextPage == nil:
+		t.stopLoadAll()
+		m.updateStatus(fmt.Sprintf(loadAllDoneMsg, n))
+		return cmd
+	case n >= l.max:
+		t.stopLoadAll()
+		m.updateStatus(fmt.Sprintf(loadAllMaxMsg, n))
+		return cmd
+	}
+	l.params = *t.nextPage
+	m.updateStatus(fmt.Sprintf(loadAllProgressMsg, n))
+	return tea.Batch(cmd, t.loadPageCmd(config.LoadAllPageDelay))
+}
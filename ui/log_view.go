@@ -0,0 +1,267 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/crash"
+	"github.com/dancnb/sonicradio/ui/styles"
+)
+
+const (
+	// LogViewLines is the number of the last log lines kept for the log view
+	LogViewLines = 2000
+
+	logViewTitle       = "Log"
+	logViewPrompt      = "Search"
+	logViewPlaceholder = "type to search the log lines"
+	logViewNoMatch     = "No %s log lines matching"
+	logViewSavedMsg    = "Saved %d log lines to %s"
+	logViewSaveErrMsg  = "Could not save the log lines: %v"
+	logsSubDir         = "logs"
+	logTimeFmt         = "20060102-150405"
+)
+
+// Logs keeps the last lines of the app log shown by the log view, the logger writing to it with -log-view.
+var Logs = crash.NewTail(LogViewLines)
+
+// logLevels are the minimum levels of the lines shown, cycled by the level key.
+var logLevels = []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError}
+
+// logView tails the app log, the lines filtered by their minimum level and by the search.
+type logView struct {
+	enabled bool
+	style   *styles.Style
+
+	input textinput.Model
+	// level is the index in logLevels of the minimum level shown
+	level int
+	// offset is how many of the matching lines are scrolled past at the bottom, 0 following the new ones
+	offset int
+
+	keymap logViewKeymap
+	help   help.Model
+	width  int
+	height int
+}
+
+func newLogView(s *styles.Style) *logView {
+	h := help.New()
+	h.ShortSeparator = "   "
+	h.Styles = s.HelpStyles()
+	return &logView{
+		style:  s,
+		input:  s.NewInputModel(logViewPrompt, logViewPlaceholder, nil, nil, nil, nil),
+		level:  1,
+		keymap: newLogViewKeymap(),
+		help:   h,
+	}
+}
+
+func (v *logView) setSize(width, height int) {
+	h, vf := v.style.DocStyle.GetFrameSize()
+	v.width = width - h
+	v.height = height - vf
+	v.help.Width = v.width
+	v.input.Width = max(0, v.width-lipgloss.Width(v.input.Prompt)-1)
+}
+
+// logLevel returns the level of a line of the text handler, info if it has none.
+func logLevel(line string) slog.Level {
+	_, rest, ok := strings.Cut(line, "level=")
+	if !ok {
+		return slog.LevelInfo
+	}
+	name, _, _ := strings.Cut(rest, " ")
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(name)); err != nil {
+		return slog.LevelInfo
+	}
+	return l
+}
+
+// lines returns the lines of at least the level chosen containing the search, ignoring the case, the oldest first.
+func (v *logView) lines(all []string) []string {
+	query := strings.ToLower(strings.TrimSpace(v.input.Value()))
+	var res []string
+	for _, l := range all {
+		if logLevel(l) < logLevels[v.level] {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(l), query) {
+			continue
+		}
+		res = append(res, l)
+	}
+	return res
+}
+
+func (v *logView) lineStyle(line string) lipgloss.Style {
+	switch l := logLevel(line); {
+	case l >= slog.LevelError:
+		return v.style.StatusErrorStyle
+	case l >= slog.LevelWarn:
+		return v.style.StatusWarnStyle
+	case l < slog.LevelInfo:
+		return v.style.ItalicStyle
+	}
+	return v.style.SecondaryColorStyle
+}
+
+func (v *logView) View() string {
+	var b strings.Builder
+	title := fmt.Sprintf("%s · %s and above", logViewTitle, logLevels[v.level])
+	b.WriteString("\n" + v.style.PrimaryColorStyle.Render(title) + "\n\n")
+	b.WriteString(v.input.View() + "\n\n")
+
+	help := v.style.HelpStyle.Render(v.help.View(&v.keymap))
+	rows := max(1, v.height-lipgloss.Height(b.String())-lipgloss.Height(help))
+	lines := v.lines(Logs.Lines())
+	if len(lines) == 0 {
+		b.WriteString(v.style.ItalicStyle.Render(fmt.Sprintf(logViewNoMatch, logLevels[v.level])) + "\n")
+	}
+	v.offset = min(v.offset, max(0, len(lines)-rows))
+	end := len(lines) - v.offset
+	for _, l := range lines[max(0, end-rows):end] {
+		b.WriteString(v.lineStyle(l).MaxWidth(v.width).Render(l) + "\n")
+	}
+
+	for i := lipgloss.Height(b.String()); i < v.height-lipgloss.Height(help); i++ {
+		b.WriteString("\n")
+	}
+	return b.String() + help
+}
+
+// save writes the lines shown, all of them and not only the visible ones, to a file in the cache dir and returns its path.
+func (v *logView) save(now time.Time) (string, int, error) {
+	lines := v.lines(Logs.Lines())
+	dir, err := config.GetOrCreateCacheDir()
+	if err != nil {
+		return "", 0, err
+	}
+	dir = filepath.Join(dir, logsSubDir)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", 0, fmt.Errorf("create the logs dir: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("log-%s.txt", now.Format(logTimeFmt)))
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		return "", 0, fmt.Errorf("write the log lines: %w", err)
+	}
+	return path, len(lines), nil
+}
+
+// toggleLogView shows or hides the log view, following the new lines when shown.
+func (m *Model) toggleLogView() tea.Cmd {
+	v := m.logView
+	v.enabled = !v.enabled
+	if !v.enabled {
+		v.input.Blur()
+		return nil
+	}
+	v.offset = 0
+	v.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	return v.input.Focus()
+}
+
+func (m *Model) updateLogView(msg tea.KeyMsg) tea.Cmd {
+	v := m.logView
+	page := max(1, v.height/2)
+	switch {
+	case key.Matches(msg, v.keymap.up):
+		v.offset++
+		return nil
+	case key.Matches(msg, v.keymap.down):
+		v.offset = max(0, v.offset-1)
+		return nil
+	case key.Matches(msg, v.keymap.pageUp):
+		v.offset += page
+		return nil
+	case key.Matches(msg, v.keymap.pageDown):
+		v.offset = max(0, v.offset-page)
+		return nil
+	case key.Matches(msg, v.keymap.level):
+		v.level = (v.level + 1) % len(logLevels)
+		v.offset = 0
+		return nil
+	case key.Matches(msg, v.keymap.save):
+		path, n, err := v.save(time.Now())
+		if err != nil {
+			slog.With("method", "ui.Model.updateLogView").Error("save", "error", err)
+			m.updateStatusError(fmt.Sprintf(logViewSaveErrMsg, err))
+			return nil
+		}
+		m.updateStatus(fmt.Sprintf(logViewSavedMsg, n, path))
+		return nil
+	case key.Matches(msg, v.keymap.cancel, m.delegate.keymap.logView):
+		return m.toggleLogView()
+	}
+
+	prev := v.input.Value()
+	var cmd tea.Cmd
+	v.input, cmd = v.input.Update(msg)
+	if v.input.Value() != prev {
+		v.offset = 0
+	}
+	return cmd
+}
+
+type logViewKeymap struct {
+	up       key.Binding
+	down     key.Binding
+	pageUp   key.Binding
+	pageDown key.Binding
+	level    key.Binding
+	save     key.Binding
+	cancel   key.Binding
+}
+
+func newLogViewKeymap() logViewKeymap {
+	return logViewKeymap{
+		up: key.NewBinding(
+			key.WithKeys("up"),
+			key.WithHelp("↑", "older"),
+		),
+		down: key.NewBinding(
+			key.WithKeys("down"),
+			key.WithHelp("↓", "newer"),
+		),
+		pageUp: key.NewBinding(
+			key.WithKeys("pgup"),
+			key.WithHelp("pgup", "page up"),
+		),
+		pageDown: key.NewBinding(
+			key.WithKeys("pgdown"),
+			key.WithHelp("pgdn", "page down"),
+		),
+		level: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "level"),
+		),
+		save: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("ctrl+s", "save"),
+		),
+		cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back"),
+		),
+	}
+}
+
+func (k *logViewKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.up, k.down, k.pageUp, k.pageDown, k.level, k.save, k.cancel}
+}
+
+func (k *logViewKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
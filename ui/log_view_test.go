@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dancnb/sonicradio/crash"
+)
+
+func Test_logLevel(t *testing.T) {
+	for line, want := range map[string]slog.Level{
+		`time=2024-05-01T10:00:00.000+02:00 level=DEBUG msg=tick`:          slog.LevelDebug,
+		`time=2024-05-01T10:00:00.000+02:00 level=WARN msg="slow station"`: slog.LevelWarn,
+		`time=2024-05-01T10:00:00.000+02:00 level=ERROR+2 msg=failed`:      slog.LevelError + 2,
+		`a line of a panic`: slog.LevelInfo,
+	} {
+		if got := logLevel(line); got != want {
+			t.Errorf("logLevel(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
+
+func Test_e2eLogView(t *testing.T) {
+	d := newUIDriver(t)
+	d.m.delegate.keymap.logView.SetEnabled(true)
+	prev := Logs
+	Logs = crash.NewTail(LogViewLines)
+	t.Cleanup(func() { Logs = prev })
+	fmt.Fprintln(Logs, `level=DEBUG msg="player tick"`)
+	fmt.Fprintln(Logs, `level=INFO msg="play station" station=jazz-0`)
+	fmt.Fprintln(Logs, `level=ERROR msg="play station" station=rock-0 error=timeout`)
+
+	d.keys("ctrl+l")
+	d.waitFor("the log view", func() bool { return d.m.logView.enabled })
+	view := d.m.View()
+	if strings.Contains(view, "player tick") || !strings.Contains(view, "station=jazz-0") {
+		t.Errorf("expected the info lines and above in the view:\n%s", view)
+	}
+
+	d.typeText("rock")
+	view = d.m.View()
+	if strings.Contains(view, "station=jazz-0") || !strings.Contains(view, "error=timeout") {
+		t.Errorf("expected only the lines matching the search in the view:\n%s", view)
+	}
+
+	d.keys("ctrl+s")
+	d.waitFor("the lines saved", func() bool { return strings.HasPrefix(d.m.statusMsg, "Saved 1 log lines") })
+	path := strings.TrimPrefix(d.m.statusMsg, "Saved 1 log lines to ")
+	if b, err := os.ReadFile(path); err != nil || !strings.Contains(string(b), "error=timeout") {
+		t.Errorf("got the saved lines %q, %v", b, err)
+	}
+
+	d.keys("backspace", "backspace", "backspace", "backspace", "tab", "tab")
+	if got := d.m.logView.lines(Logs.Lines()); len(got) != 1 || !strings.Contains(got[0], "error=timeout") {
+		t.Errorf("got %v, want only the error line at the error level", got)
+	}
+	d.keys("tab")
+	if view := d.m.View(); !strings.Contains(view, "player tick") {
+		t.Errorf("expected the debug lines after the level wraps around:\n%s", view)
+	}
+
+	d.keys("esc")
+	d.waitFor("the log view closed", func() bool { return !d.m.logView.enabled })
+}
@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/metadata"
+	"github.com/dancnb/sonicradio/ui/styles"
+)
+
+const (
+	lyricsPanelMaxWidth = 50
+	lyricsPanelGap      = 2
+
+	noSongLyricsMsg  = "No song information available."
+	noLyricsMsg      = "No lyrics found."
+	lyricsLoadingMsg = "Loading lyrics..."
+)
+
+// lyricsPanel is a scrollable side panel showing the lyrics of the current song.
+type lyricsPanel struct {
+	enabled bool
+	style   *styles.Style
+	vp      viewport.Model
+	song    metadata.Song
+}
+
+func newLyricsPanel(s *styles.Style) *lyricsPanel {
+	return &lyricsPanel{
+		style: s,
+		vp:    viewport.New(0, 0),
+	}
+}
+
+// width returns the width taken by the panel, out of the total width.
+func (p *lyricsPanel) width(total int) int {
+	if !p.enabled {
+		return 0
+	}
+	return min(lyricsPanelMaxWidth, total/3)
+}
+
+func (p *lyricsPanel) setSize(width, height int) {
+	p.vp.Width = max(0, width-lyricsPanelGap)
+	p.vp.Height = max(0, height-2)
+}
+
+// setSong resets the panel for a new song, returning false if the song is unchanged.
+func (p *lyricsPanel) setSong(s metadata.Song) bool {
+	if s == p.song && p.vp.TotalLineCount() > 0 {
+		return false
+	}
+	p.song = s
+	if s.Artist == "" {
+		p.setText(noSongLyricsMsg)
+		return false
+	}
+	p.setText(lyricsLoadingMsg)
+	return true
+}
+
+func (p *lyricsPanel) update(msg lyricsMsg) {
+	if msg.song != p.song {
+		return
+	}
+	switch {
+	case errors.Is(msg.err, metadata.ErrNoLyrics):
+		p.setText(noLyricsMsg)
+	case msg.err != nil:
+		p.setText(msg.err.Error())
+	default:
+		p.setText(msg.text)
+	}
+}
+
+func (p *lyricsPanel) setText(text string) {
+	wrapped := lipgloss.NewStyle().Width(p.vp.Width).Render(strings.TrimSpace(text))
+	p.vp.SetContent(p.style.SecondaryColorStyle.Render(wrapped))
+	p.vp.GotoTop()
+}
+
+func (p *lyricsPanel) scroll(down bool) {
+	if down {
+		p.vp.LineDown(1)
+	} else {
+		p.vp.LineUp(1)
+	}
+}
+
+func (p *lyricsPanel) View() string {
+	title := p.song.String()
+	for lipgloss.Width(title) > p.vp.Width && len(title) > 0 {
+		title = title[:len(title)-1]
+	}
+	header := p.style.PrimaryColorStyle.Render(title)
+	content := header + "\n\n" + p.vp.View()
+	return lipgloss.NewStyle().PaddingLeft(lyricsPanelGap).Render(content)
+}
+
+func (m *Model) lyricsCmd(s metadata.Song) tea.Cmd {
+	return func() tea.Msg {
+		text, err := m.lyrics.Get(context.Background(), s)
+		return lyricsMsg{song: s, text: text, err: err}
+	}
+}
+
+// toggleLyrics shows or hides the lyrics panel, resizing the tabs to make room for it.
+func (m *Model) toggleLyrics() tea.Cmd {
+	m.lyricsPanel.enabled = !m.lyricsPanel.enabled
+	cmds := []tea.Cmd{m.resizeTabs()}
+	if m.lyricsPanel.enabled && m.lyricsPanel.setSong(m.song) {
+		cmds = append(cmds, m.lyricsCmd(m.song))
+	}
+	return tea.Batch(cmds...)
+}
+
+// tabWidth is the width available to the tabs, next to the side panel.
+func (m *Model) tabWidth() int {
+	return m.width - m.lyricsPanel.width(m.width)
+}
+
+func (m *Model) resizeTabs() tea.Cmd {
+	_, v := m.style.DocStyle.GetFrameSize()
+	m.lyricsPanel.setSize(m.lyricsPanel.width(m.width), m.totHeight-m.headerHeight-v)
+	m.sessions.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	m.guideView.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	m.liveView.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	m.mapView.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	m.favoriteForm.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	m.bulkAdd.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	m.trash.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	m.outputs.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	m.palette.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	m.logView.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	var cmds []tea.Cmd
+	msg := tea.WindowSizeMsg{Width: m.tabWidth(), Height: m.totHeight}
+	for i := range m.tabs {
+		_, tcmd := m.updateTab(uiTabIndex(i), msg)
+		cmds = append(cmds, tcmd)
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	macroRecordingMsg = "Recording a macro, press ctrl+x to stop"
+	macroBindMsg      = "Press f1 to f12 to bind the macro of %d keys, esc to discard it"
+	macroUnbindMsg    = "Press f1 to f12 to remove its macro, esc to cancel"
+	macroBoundMsg     = "Macro bound to %s"
+	macroRemovedMsg   = "Macro of %s removed"
+	macroDiscardedMsg = "Macro discarded"
+	macroEmptyMsg     = "No macro bound to %s, press ctrl+x to record one"
+	macroStoppedMsg   = "Macro stopped"
+	macroRecordingTag = "recording macro"
+	macroCommandFmt   = "macro %s"
+)
+
+// macroRecording is the key sequence recorded until ctrl+x, then bound to a function key.
+type macroRecording struct {
+	keys []string
+	// binding waits for the function key to bind the keys to
+	binding bool
+}
+
+// macroReplay has the keys of the macro left to replay.
+type macroReplay struct {
+	keys []string
+	seq  int
+}
+
+// updateMacro records the keys pressed and replays the macros of the function keys.
+// It tells if the key was handled, the recorded keys being handled as usual too.
+func (m *Model) updateMacro(msg tea.KeyMsg) (tea.Cmd, bool) {
+	k := msg.String()
+	if msg.Paste {
+		k = string(msg.Runes)
+	}
+	if m.macroReplay != nil && !m.replaying {
+		// a key pressed during the replay takes over
+		m.macroReplay = nil
+		m.updateStatus(macroStoppedMsg)
+	}
+
+	switch {
+	case m.macroRec != nil && m.macroRec.binding:
+		m.bindMacro(k)
+		return nil, true
+	case key.Matches(msg, m.delegate.keymap.recordMacro):
+		m.toggleMacroRecording()
+		return nil, true
+	case config.IsMacroKey(k):
+		if m.macroRec != nil || m.replaying {
+			return nil, true
+		}
+		return m.replayMacroCmd(k), true
+	case m.macroRec != nil:
+		m.macroRec.keys = append(m.macroRec.keys, k)
+		if len(m.macroRec.keys) >= config.MacroMaxKeys {
+			m.toggleMacroRecording()
+		}
+	}
+	return nil, false
+}
+
+// toggleMacroRecording starts recording the keys, or stops and waits for the key to bind them to.
+func (m *Model) toggleMacroRecording() {
+	if m.macroRec == nil {
+		m.macroRec = &macroRecording{}
+		m.updateStatus(macroRecordingMsg)
+		return
+	}
+	m.macroRec.binding = true
+	if len(m.macroRec.keys) == 0 {
+		m.updateStatus(macroUnbindMsg)
+	} else {
+		m.updateStatus(fmt.Sprintf(macroBindMsg, len(m.macroRec.keys)))
+	}
+}
+
+// bindMacro binds the recorded keys to the function key pressed, an empty recording removing its macro.
+// Any other key discards the recording.
+func (m *Model) bindMacro(k string) {
+	keys := m.macroRec.keys
+	m.macroRec = nil
+	switch {
+	case !config.IsMacroKey(k):
+		m.updateStatus(macroDiscardedMsg)
+	case len(keys) == 0:
+		m.cfg.SetMacro(k, nil)
+		m.updateStatus(fmt.Sprintf(macroRemovedMsg, k))
+	default:
+		m.cfg.SetMacro(k, keys)
+		m.updateStatus(fmt.Sprintf(macroBoundMsg, k))
+	}
+}
+
+func (m *Model) replayMacroCmd(k string) tea.Cmd {
+	keys := m.cfg.Macros[k]
+	if len(keys) == 0 {
+		m.updateStatusWarn(fmt.Sprintf(macroEmptyMsg, k))
+		return nil
+	}
+	m.macroSeq++
+	m.macroReplay = &macroReplay{keys: slices.Clone(keys), seq: m.macroSeq}
+	return macroStepCmd(m.macroSeq)
+}
+
+func macroStepCmd(seq int) tea.Cmd {
+	return tea.Tick(config.MacroStepDelay, func(time.Time) tea.Msg {
+		return macroStepMsg{seq: seq}
+	})
+}
+
+// macroStep replays the next key of the macro, once the previous ones are done
+// searching, loading the stations or connecting to the station.
+func (m *Model) macroStep(msg macroStepMsg) tea.Cmd {
+	r := m.macroReplay
+	if r == nil || r.seq != msg.seq {
+		return nil
+	}
+	if m.macroWaiting() {
+		return macroStepCmd(r.seq)
+	}
+	k := r.keys[0]
+	r.keys = r.keys[1:]
+	if len(r.keys) == 0 {
+		m.macroReplay = nil
+	}
+	m.replaying = true
+	_, cmd := m.Update(keyMsg(k))
+	m.replaying = false
+	if m.macroReplay != r {
+		return cmd
+	}
+	return tea.Batch(cmd, macroStepCmd(r.seq))
+}
+
+func (m *Model) macroWaiting() bool {
+	b := m.tabs[browseTabIx].(*browseTab)
+	return b.searchModel.searching || b.viewMsg == loadingMsg || m.connecting != nil
+}
+
+// macroCommands replay the macros from the palette, named by their keys.
+func (m *Model) macroCommands() []paletteCommand {
+	var res []paletteCommand
+	for slot := 1; slot <= config.MacroSlots; slot++ {
+		k := config.MacroKey(slot)
+		keys, ok := m.cfg.Macros[k]
+		if !ok {
+			continue
+		}
+		names := slices.Clone(keys)
+		for i := range names {
+			if names[i] == " " {
+				names[i] = "space"
+			}
+		}
+		msg := keyMsg(k)
+		res = append(res, paletteCommand{
+			name: fmt.Sprintf(macroCommandFmt, strings.Join(names, " ")),
+			key:  k,
+			run: func(*Model) tea.Cmd {
+				return func() tea.Msg { return msg }
+			},
+		})
+	}
+	return res
+}
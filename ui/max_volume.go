@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxVolumes are the options of the volume cap, 0 being none.
+var maxVolumes = []int{0, 90, 80, 70, 60, 50}
+
+// maxVolumeName returns the name of the volume cap option.
+func maxVolumeName(v int) string {
+	if v <= 0 || v >= 100 {
+		return "Off"
+	}
+	return fmt.Sprintf("%d%%", v)
+}
+
+// setMaxVolume changes the hard cap of the volume, for the players of all the sessions, lowering the
+// volume above it.
+func (m *Model) setMaxVolume(v int) tea.Cmd {
+	m.cfg.MaxVolume = v
+	for _, s := range m.sessions.sessions {
+		if err := s.player.SetMaxVolume(v); err != nil {
+			slog.With("method", "ui.Model.setMaxVolume").Error("player", "session", s.name, "error", err)
+		}
+	}
+	return m.setVolumeCmd(m.cfg.GetVolume())
+}
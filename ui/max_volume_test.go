@@ -0,0 +1,35 @@
+package ui
+
+import "testing"
+
+func Test_e2eMaxVolume(t *testing.T) {
+	d := newUIDriver(t)
+	d.m.cfg.SetVolume(80)
+	d.keys("S", "j", "j", "j", "j", "j", "j", "j", "j", "j", "enter", "5")
+	d.waitFor("the volume lowered under the cap", func() bool {
+		return d.m.cfg.MaxVolume == 60 && d.player.Volume() == 60
+	})
+	if d.m.cfg.GetVolume() != 60 {
+		t.Errorf("got volume %d, want 60", d.m.cfg.GetVolume())
+	}
+
+	d.run(d.m.volumeCmd(true))
+	d.run(d.m.setVolumeCmd(100))
+	d.waitFor("the volume changes", func() bool { return d.m.cfg.GetVolume() == 60 })
+	if _, err := d.m.player.SetVolume(100); err != nil || d.player.Volume() != 60 {
+		t.Errorf("got player volume %d, %v, want capped", d.player.Volume(), err)
+	}
+
+	d.keys("enter", "k", "k", "k", "k", "enter")
+	d.waitFor("the cap removed", func() bool { return d.m.cfg.MaxVolume == 0 })
+	d.run(d.m.setVolumeCmd(100))
+	d.waitFor("the volume raised", func() bool { return d.player.Volume() == 100 })
+}
+
+func Test_maxVolumeName(t *testing.T) {
+	for v, want := range map[int]string{0: "Off", 100: "Off", 60: "60%"} {
+		if got := maxVolumeName(v); got != want {
+			t.Errorf("maxVolumeName(%d) = %s, want %s", v, got, want)
+		}
+	}
+}
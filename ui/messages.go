@@ -2,10 +2,19 @@ package ui
 
 import (
 	"fmt"
+	"image"
 	"time"
 
+	"github.com/dancnb/sonicradio/audiosink"
 	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/cast"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/guide"
+	"github.com/dancnb/sonicradio/metadata"
+	"github.com/dancnb/sonicradio/mpris"
 	"github.com/dancnb/sonicradio/player/model"
+	"github.com/dancnb/sonicradio/relay"
+	"github.com/dancnb/sonicradio/webdav"
 )
 
 // tea.Msg
@@ -19,35 +28,204 @@ type (
 		stationName  string
 		songTitle    string
 		playbackTime *time.Duration
+		buffer       *time.Duration
+		stream       *model.StreamInfo
+	}
+
+	// duration buffered ahead and audio parameters, for the streams without metadata
+	streamMsg struct {
+		stationUuid string
+		buffer      *time.Duration
+		stream      *model.StreamInfo
+	}
+
+	// MusicBrainz match for the playing song
+	songInfoMsg struct {
+		stationUuid string
+		song        metadata.Song
+		recording   metadata.Recording
+	}
+
+	artworkMsg struct {
+		stationUuid string
+		url         string
+		img         image.Image
+		detail      bool // favicon of the station in the info view
+	}
+
+	identifyMsg struct {
+		stationUuid string
+		stationName string
+		song        metadata.Song
+		err         error
+	}
+
+	lyricsMsg struct {
+		song metadata.Song
+		text string
+		err  error
 	}
 
 	volumeMsg struct {
 		err error
 	}
 
-	// used for status info/error message
+	// tick of the UI, refreshing the status bar clock, the periodic work subscribes to
+	tickMsg struct {
+		seq int
+		t   time.Time
+	}
+
+	// the cursor rested on the station
+	hoverMsg string
+
+	// stream url resolved by the preconnect
+	probeMsg struct {
+		uuid string
+		url  string
+		err  error
+	}
+
+	mapCountriesMsg struct {
+		countries []browser.Country
+		err       error
+	}
+
+	// syncs of the favorites, periodic unless manual
+	syncReadMsg struct {
+		data   *config.SyncData
+		err    error
+		manual bool
+	}
+
+	gitSyncMsg struct {
+		snapshot  []string // the favorites pushed by the sync
+		favorites []string
+		err       error
+		manual    bool
+	}
+
+	webdavReadMsg struct {
+		file   *webdav.File
+		data   *config.SyncData
+		err    error
+		manual bool
+	}
+	webdavPutMsg struct {
+		err    error
+		manual bool
+	}
+
+	// state of the network, read periodically to reconnect on the changes
+	networkStateMsg struct {
+		state string
+		err   error
+	}
+
+	// reconnection of the streams after a suspend or a network change, only the last one scheduled is done
+	reconnectMsg struct {
+		seq int
+	}
+
+	// replay of the next key of a macro, only for the last macro started
+	macroStepMsg struct {
+		seq int
+	}
+
+	// station change of the listening room host
+	roomStationMsg relay.Station
+
+	// stations of the pasted links and uuids found, to add to the favorites
+	bulkAddMsg struct {
+		uuids  []string
+		failed []bulkAddFailure
+		err    error
+	}
+
+	// schedule of a station with a program guide
+	guideMsg struct {
+		uuid     string
+		url      string
+		programs []guide.Program
+		err      error
+	}
+
+	// click on the action of a reminder notification, to tune into the favorite
+	tuneInMsg struct {
+		stationUuid string
+	}
+
+	// media key of the terminal or command of an MPRIS client
+	mediaKeyMsg struct {
+		action mpris.Action
+		volume int
+	}
+
+	// lock or unlock of the session, to pause and resume the playback
+	sessionLockMsg struct {
+		locked bool
+	}
+
+	// change of the default audio output, to pause when the headphones are unplugged
+	outputChangeMsg struct {
+		prev, cur audiosink.Output
+	}
+
+	// start or end of the notifications and calls of the other apps, to lower the volume during them
+	duckMsg struct {
+		active bool
+	}
+
+	// used for status info message
 	statusMsg string
+	// used for status warning message
+	warnMsg string
+	// used for status error message
+	errorMsg string
 
 	// view msg instead of list
 	viewMsg string
 
 	favoritesStationRespMsg struct {
 		viewMsg
-		statusMsg
+		errorMsg
 		stations []browser.Station
+		// synced is a reload after the favorites changed on another machine, which doesn't autoplay
+		synced bool
 	}
 
 	topStationsRespMsg struct {
 		viewMsg
-		statusMsg
+		errorMsg
 		stations []browser.Station
 	}
 
 	searchRespMsg struct {
 		viewMsg
-		statusMsg
+		errorMsg
 		stations  []browser.Station
 		cancelled bool
+		// params of the server search, for loading its next pages
+		params *browser.SearchParams
+		// status shown with the stations found by a pasted uuid or link
+		status string
+	}
+
+	loadAllPageMsg struct {
+		seq      int
+		stations []browser.Station
+		err      error
+	}
+
+	// debounce timer for the as-you-type search
+	instantSearchTickMsg struct {
+		seq int
+	}
+
+	instantSearchRespMsg struct {
+		seq      int
+		stations []browser.Station
+		err      error
 	}
 
 	toggleFavoriteMsg struct {
@@ -61,7 +239,10 @@ type (
 	}
 
 	playRespMsg struct {
-		err string
+		err       string
+		cancelled bool
+		// the start of a station, nil when resuming
+		attempt *connectAttempt
 	}
 
 	pauseRespMsg struct {
@@ -72,9 +253,27 @@ type (
 		uuid string
 	}
 
+	// recordingsChangedMsg is sent when a scheduled recording starts or ends
+	recordingsChangedMsg struct{}
+
+	outputsFoundMsg struct {
+		sinks   []audiosink.Sink
+		devices []cast.Device
+	}
+
+	sessionCreatedMsg struct {
+		session *session
+	}
+
+	// diskUsageMsg has the size in bytes of the cache and recordings dirs
+	diskUsageMsg struct {
+		cache      int64
+		recordings int64
+	}
+
 	playUuidRespMsg struct {
 		viewMsg
-		statusMsg
+		errorMsg
 		stations []browser.Station
 	}
 )
@@ -89,9 +288,23 @@ func getMetadataMsg(s browser.Station, m model.Metadata) metadataMsg {
 		t := time.Second * (time.Duration(*m.PlaybackTimeSec))
 		msg.playbackTime = &t
 	}
+	msg.buffer = bufferDuration(m)
+	msg.stream = m.Stream
 	return msg
 }
 
+func getStreamMsg(s browser.Station, m model.Metadata) streamMsg {
+	return streamMsg{stationUuid: s.Stationuuid, buffer: bufferDuration(m), stream: m.Stream}
+}
+
+func bufferDuration(m model.Metadata) *time.Duration {
+	if m.BufferSec == nil {
+		return nil
+	}
+	d := time.Duration(*m.BufferSec * float64(time.Second))
+	return &d
+}
+
 func (m metadataMsg) String() string {
 	var pt time.Duration
 	if m.playbackTime != nil {
@@ -7,6 +7,8 @@ import (
 	"math"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
@@ -21,9 +23,18 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/artwork"
 	"github.com/dancnb/sonicradio/browser"
 	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/demo"
+	"github.com/dancnb/sonicradio/events"
+	"github.com/dancnb/sonicradio/metadata"
+	"github.com/dancnb/sonicradio/mpris"
+	"github.com/dancnb/sonicradio/perf"
 	"github.com/dancnb/sonicradio/player"
+	"github.com/dancnb/sonicradio/recorder"
+	"github.com/dancnb/sonicradio/relay"
+	"github.com/muesli/termenv"
 )
 
 const (
@@ -34,47 +45,155 @@ const (
 	emptyHistoryMsg     = "\n  No playback history available. \n"
 
 	// header status
-	noPlayingMsg     = "Nothing playing"
-	missingFavorites = "Some stations not found"
-	prevTermErr      = "Could not terminate previous playback!"
-	voteSuccesful    = "Station was voted successfully"
-	statusMsgTimeout = 1 * time.Second
+	noPlayingMsg      = "Nothing playing"
+	noLastStationMsg  = "No previous station to swap to"
+	playingNotListed  = "The playing station is not in the lists"
+	missingFavorites  = "Some stations not found"
+	prevTermErr       = "Could not terminate previous playback!"
+	voteSuccesful     = "Station was voted successfully"
+	alreadyVoted      = "Already voted for this station on %s, press again to vote again"
+	transientVoteMsg  = "Only the stations listed in the directory can be voted"
+	playingAnnounce   = "Playing %s"
+	pausedAnnounce    = "Paused"
+	tabAnnounce       = "%s tab"
+	filtersClearedMsg = "Filters cleared"
+	statusMsgTimeout  = 1 * time.Second
+	statusWarnTimeout = 3 * time.Second
+	statusErrTimeout  = 5 * time.Second
 
 	// metadata
-	volumeFmt          = "%3d%%%s"
-	playerPollInterval = 500 * time.Millisecond
+	volumeFmt = "%3d%%%s"
 )
 
 func NewModel(ctx context.Context, cfg *config.Value, b *browser.Api, p *player.Player) *Model {
 	m := newModel(ctx, cfg, b, p)
-	progr := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx))
+	opts := []tea.ProgramOption{tea.WithContext(ctx)}
+	if m.cfg.LowRefresh() {
+		opts = append(opts, tea.WithFPS(config.LowRefreshFPS))
+	}
+	if m.announcer == nil {
+		// the lines announced in the screen reader mode are printed above the view, which the
+		// alternate screen doesn't keep
+		opts = append(opts, tea.WithAltScreen())
+	}
+	progr := tea.NewProgram(crashGuard{m}, opts...)
 	m.Progr = progr
+	if m.announcer != nil {
+		go m.announcer.run(ctx, func(line string) { progr.Println(line) })
+	}
 	trapSignal(progr)
-	go updatePlayerMetadata(ctx, progr, m)
+	m.startMpris(progr)
+	m.startAutoPause(ctx, progr)
+	m.setAutoDuck(m.cfg.AutoDuck)
+	m.startEvents()
+	if host := config.JoinRoom(); host != "" {
+		m.joinRoom(ctx, host, progr)
+	}
+	interval, _ := m.cfg.MetadataPoll()
+	m.poller = newMetadataPoller(interval, config.MetadataPollJitter, m.pollMetadata, progr.Send)
+	go m.scheduler.Run(ctx)
 	return m
 }
 
 func newModel(ctx context.Context, cfg *config.Value, b *browser.Api, p *player.Player) *Model {
+	var ann *announcer
+	switch cfg.Icons() {
+	case config.IconsNerd:
+		styles.UseNerdFont()
+	case config.IconsASCII:
+		styles.UseASCII()
+	}
+	if cfg.SymbolSignals {
+		styles.UseSymbols()
+	}
+	if cfg.NoMotion() {
+		styles.UseReducedMotion()
+	}
+	if setColorProfile(cfg) == termenv.Ascii {
+		// the cursor and the active tab are otherwise only told by the colors
+		styles.UseSymbols()
+	}
+	if cfg.ScreenReaderMode() {
+		styles.UsePlainText()
+		ann = newAnnouncer()
+	}
 	style := styles.NewStyle(cfg.Theme)
 
 	delegate := newStationDelegate(cfg, style, p, b)
 
-	infoModel := newInfoModel(b, style)
+	infoModel := newInfoModel(b, cfg, style)
 	m := Model{
 		cfg:          cfg,
 		style:        style,
 		browser:      b,
 		player:       p,
 		delegate:     delegate,
-		statusUpdate: make(chan struct{}),
+		statusUpdate: make(chan time.Duration),
+		announcer:    ann,
+		lastInput:    time.Now(),
+		netState:     networkState(),
+		crash:        new(crashState),
+		templates:    new(displayTemplates),
 
 		volumeBar: getVolumeBar(style.GetSecondColor()),
 	}
+	mbCachePath := ""
+	if cacheDir, err := config.GetOrCreateCacheDir(); err == nil {
+		mbCachePath = filepath.Join(cacheDir, metadata.MusicBrainzCacheFilename)
+	}
+	m.musicBrainz = metadata.NewMusicBrainz(cfg.Version, mbCachePath)
+	m.art = newArtworkModel(cfg)
+	infoModel.art = m.art
+	m.live = &liveStream{}
+	infoModel.live = m.live
+	lyricsDir := ""
+	if cacheDir, err := config.GetOrCreateCacheDir(); err == nil {
+		lyricsDir = filepath.Join(cacheDir, metadata.LyricsCacheSubDir)
+	}
+	m.lyrics = metadata.NewLyrics(cfg.Version, lyricsDir, cfg.LyricsProviders)
+	m.lyricsPanel = newLyricsPanel(style)
+	if cfg.AcoustIDKey != "" {
+		id, err := metadata.NewIdentifier(cfg.AcoustIDKey)
+		if err != nil {
+			slog.Error("song identifier", "error", err)
+		}
+		m.identifier = id
+	}
+	delegate.keymap.identify.SetEnabled(m.identifier != nil)
+	m.sessions = newSessionsView(style, &session{name: defSessionName, player: p, volume: cfg.GetVolume()})
+	m.outputs = newOutputsView(style)
+	m.guideView = newGuideView(style)
+	m.liveView = newLiveView(style)
+	m.mapView = newMapView(style)
+	m.favoriteForm = newFavoriteForm(style)
+	m.bulkAdd = newBulkAddView(style)
+	m.trash = newTrashView(style)
+	m.favoritesBackup = latestFavoritesBackup()
+	m.palette = newPaletteView(style)
+	m.logView = newLogView(style)
+	delegate.keymap.logView.SetEnabled(config.LogView())
+	m.guides = make(map[string]*stationGuide)
+	m.probed = make(map[string]probedStream)
+	m.subscribeTicks()
+	delegate.selected = m.selectedStation
+	m.boundary = newTabBoundary(&m)
+	m.newPlayer = func() (*player.Player, error) {
+		if config.Demo() {
+			return demo.NewPlayer(), nil
+		}
+		return player.NewPlayer(ctx, cfg)
+	}
+	m.scheduler = recorder.NewScheduler(cfg, func() {
+		if m.Progr != nil {
+			m.Progr.Send(recordingsChangedMsg{})
+		}
+	})
 	m.tabs = []uiTab{
 		newFavoritesTab(infoModel, style),
-		newBrowseTab(ctx, b, infoModel, style),
+		newBrowseTab(ctx, b, cfg, infoModel, style),
 		newHistoryTab(ctx, cfg, style),
-		newSettingsTab(ctx, cfg, style, p.PlayerTypes(), m.changeTheme),
+		newRecordingsTab(ctx, cfg, style, m.scheduler),
+		newSettingsTab(ctx, cfg, style, p.PlayerTypes(), m.changeTheme, m.enforceQuotas, m.setRelay, m.setDialKeys, m.setMetadataPoll, m.restoreFavorites, m.setAutoDuck, m.setCrossfade, m.setMaxVolume),
 	}
 
 	if len(cfg.Favorites) > 0 {
@@ -83,6 +202,15 @@ func newModel(ctx context.Context, cfg *config.Value, b *browser.Api, p *player.
 		m.toBrowseTab()
 	}
 
+	if p.Attached() {
+		m.restoreState()
+	}
+	m.enforceQuotas()
+	m.setDialKeys(cfg.DialKeys)
+	m.updateDialSlots()
+	if cfg.Relay {
+		m.setRelay(true)
+	}
 	go m.statusHandler(ctx)
 	return &m
 }
@@ -92,41 +220,43 @@ func getVolumeBar(secondColor string) progress.Model {
 		progress.WithWidth(10),
 		progress.WithSolidFill(secondColor),
 		progress.WithoutPercentage(),
+		progress.WithColorProfile(lipgloss.ColorProfile()),
 	}...)
 	b.EmptyColor = secondColor
 	return b
 }
 
-func updatePlayerMetadata(ctx context.Context, progr *tea.Program, m *Model) {
-	tick := time.NewTicker(playerPollInterval)
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-tick.C:
-			pollMetadata(m, progr)
-		}
-	}
-}
-
-func pollMetadata(m *Model, progr *tea.Program) {
-	log := slog.With("method", "pollMetadata")
+// pollMetadata must not hold the playing lock while waiting on the player,
+// otherwise a slow backend would block play/pause commands.
+func (m *Model) pollMetadata() tea.Msg {
+	log := slog.With("method", "ui.Model.pollMetadata")
 
 	m.delegate.playingMtx.RLock()
-	defer m.delegate.playingMtx.RUnlock()
-
 	if m.delegate.currPlaying == nil {
-		return
+		m.delegate.playingMtx.RUnlock()
+		return nil
 	}
+	station := *m.delegate.currPlaying
+	m.delegate.playingMtx.RUnlock()
+
 	metadata := m.player.Metadata()
 	if metadata == nil {
-		return
+		return nil
 	} else if metadata.Err != nil {
 		log.Error("", "metadata", metadata.Err)
-		return
+		if metadata.BufferSec != nil || metadata.Stream != nil {
+			// streams without song titles still report their buffer and parameters
+			return getStreamMsg(station, *metadata)
+		}
+		return nil
+	}
+
+	m.delegate.playingMtx.RLock()
+	defer m.delegate.playingMtx.RUnlock()
+	if m.delegate.currPlaying == nil || m.delegate.currPlaying.Stationuuid != station.Stationuuid {
+		return nil
 	}
-	msg := getMetadataMsg(*m.delegate.currPlaying, *metadata)
-	go progr.Send(msg)
+	return getMetadataMsg(station, *metadata)
 }
 
 type Model struct {
@@ -144,25 +274,187 @@ type Model struct {
 
 	// display currently performed action or encountered error
 	statusMsg    string
-	statusUpdate chan struct{}
+	statusLevel  statusLevel
+	statusUpdate chan time.Duration
+	// the state changes are printed as lines for the screen readers, nil unless in the screen reader mode
+	announcer *announcer
+	// the station starting to play, until it plays or fails
+	connecting *connectAttempt
+	// the stream of the highlighted station is resolved when the cursor rests on it
+	hoverUuid  string
+	hoverTimer *time.Timer
+	probed     map[string]probedStream
+	// the favorite to put on the quick dial slot of the next key pressed
+	dialAssign *browser.Station
+	// the playback stops after the configured hours without user interaction
+	lastInput   time.Time
+	idleWarning bool
+	// bytes streamed are counted on every tick, with a warning near the monthly cap
+	bandwidthTick    time.Time
+	bandwidthWarning string
+	// the periodic checks run on the tick of the UI
+	ticker ticker
+	// the song title is polled in the background, nil until the program runs
+	poller *metadataPoller
+	// the panics of the tabs show their error instead of quitting
+	boundary *tabBoundary
+	// the streams are reconnected when a gap of the clock between the ticks shows the system was suspended,
+	// or when the network state changes
+	resumeTick   time.Time
+	netState     string
+	reconnectSeq int
 
 	// display station metadata
 	playbackTime time.Duration
+	buffer       bufferHealth
+	live         *liveStream
 	spinner      *spinner.Model
 	songTitle    string
-	volumeBar    progress.Model
+	songChange   metadata.ChangeDetector
+	song         metadata.Song
+	recording    metadata.Recording
+	musicBrainz  *metadata.MusicBrainz
+	art          *artworkModel
+	lyrics       *metadata.Lyrics
+	lyricsPanel  *lyricsPanel
+	identifier   *metadata.Identifier
+	sessions     *sessionsView
+	outputs      *outputsView
+	guideView    *guideView
+	liveView     *liveView
+	mapView      *mapView
+	favoriteForm *favoriteForm
+	bulkAdd      *bulkAddView
+	trash        *trashView
+	palette      *paletteView
+	logView      *logView
+
+	// favoritesBackup is the last backup of the favorites, written again once they change
+	favoritesBackup config.FavoritesBackup
+
+	// macroRec is the macro being recorded, macroReplay the one being replayed, replaying while its key is handled
+	macroRec    *macroRecording
+	macroReplay *macroReplay
+	macroSeq    int
+	replaying   bool
+	guides      map[string]*stationGuide
+	// guideChecked is the time of the last check for followed programs starting, announced by guideNotice
+	guideChecked time.Time
+	guideNotice  string
+	mpris        *mpris.Server
+	lockWatcher  *mpris.LockWatcher
+	relay        *relay.Relay
+	// reminders tracks the reminders due, tuned into by the tune in key
+	reminders reminders
+	quiet     quietHours
+	// autoPaused is the reason of the last pause by a lock or an unplug, reset by the pause key
+	autoPaused autoPause
+	// ducked tells the volume is lowered for a notification or a call, followed until stopDuck
+	ducked   bool
+	stopDuck context.CancelFunc
+	// events streams the changes to `sonicradio events`, eventStation and eventVolume are the last ones sent
+	events       *events.Hub
+	eventStation string
+	eventVolume  int
+	newPlayer    func() (*player.Player, error)
+	// detach leaves the playback running in the background on quit
+	detach    bool
+	detached  bool
+	scheduler *recorder.Scheduler
+	volumeBar progress.Model
 
 	width        int
 	totHeight    int
 	headerHeight int
+
+	// perfOverlay shows the timings over the tab
+	perfOverlay bool
+	crash       *crashState
+	title       windowTitle
+	templates   *displayTemplates
+	osd         osdFile
+}
+
+// onSongChange is called once for every new song of the playing station.
+func (m *Model) onSongChange(stationUuid, stationName, title string) tea.Cmd {
+	m.song = metadata.Parse(title, m.cfg.GetSongRule(stationUuid))
+	m.recording = metadata.Recording{}
+	slog.With("method", "ui.Model.onSongChange").Info("", "artist", m.song.Artist, "title", m.song.Title)
+	m.publishSong(stationUuid, stationName, title)
+	go m.cfg.AddHistoryEntry(
+		time.Now(),
+		strings.TrimSpace(stationUuid),
+		strings.TrimSpace(stationName),
+		title,
+	)
+	cmds := []tea.Cmd{m.art.fetchCmd(m.art.favicon)}
+	if m.lyricsPanel.enabled && m.lyricsPanel.setSong(m.song) {
+		cmds = append(cmds, m.lyricsCmd(m.song))
+	}
+	if m.cfg.MusicBrainz && m.song.Artist != "" {
+		cmds = append(cmds, m.songInfoCmd(stationUuid, m.song))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) songInfoCmd(stationUuid string, song metadata.Song) tea.Cmd {
+	return func() tea.Msg {
+		r, err := m.musicBrainz.Lookup(context.Background(), song)
+		if err != nil {
+			slog.With("method", "ui.Model.songInfoCmd").Error("musicbrainz lookup", "error", err)
+			return nil
+		}
+		return songInfoMsg{stationUuid: stationUuid, song: song, recording: r}
+	}
+}
+
+// restoreState shows the playback of the daemon the app attached to.
+func (m *Model) restoreState() {
+	// the daemon caps the volume as set by its config at its start
+	if m.player.Attached() {
+		if err := m.player.SetMaxVolume(m.cfg.MaxVolume); err != nil {
+			slog.With("method", "ui.Model.restoreState").Error("daemon max volume", "error", err)
+		}
+	}
+	st := m.player.State()
+	if st == nil || !st.Playing {
+		return
+	}
+	m.cfg.SetVolume(st.Volume)
+	s := browser.Station{Stationuuid: st.StationUuid, Name: st.StationName, URL: st.URL}
+	if s.Name == "" {
+		s.Name = st.URL
+	}
+	if st.Paused {
+		m.delegate.prevPlaying = &s
+		m.delegate.keymap.pause.SetHelp("space", "resume")
+	} else {
+		m.delegate.currPlaying = &s
+		m.delegate.keymap.pause.SetHelp("space", "pause")
+	}
 }
 
 func (m *Model) Init() tea.Cmd {
-	return nil
+	if m.delegate.currPlaying != nil {
+		m.spinner = m.newSpinner()
+	}
+	return tea.Batch(m.retick(), m.checkGuides(time.Now()))
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	logTeaMsg(msg, "ui.model.Update")
+	defer perf.Since(perf.Update, time.Now())
+	defer m.updateMpris()
+	defer m.updateTitle()
+	defer m.updateOsd()
+	defer m.updateRelay()
+	defer m.publishEvents()
+	defer m.updateHover()
+	defer m.updateDialSlots()
+	if keyMsg, ok := parseMediaKey(msg); ok {
+		msg = keyMsg
+	}
+	m.observeInput(msg)
 	activeTab := m.tabs[m.activeTabIdx]
 
 	switch msg := msg.(type) {
@@ -178,14 +470,11 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if !m.ready {
 			m.ready = true
 			for i := range m.tabs {
-				tcmd := m.tabs[i].Init(m)
+				tcmd := m.initTab(uiTabIndex(i))
 				cmds = append(cmds, tcmd)
 			}
 		} else {
-			for i := range m.tabs {
-				_, tcmd := m.tabs[i].Update(m, msg)
-				cmds = append(cmds, tcmd)
-			}
+			cmds = append(cmds, m.resizeTabs())
 		}
 		return m, tea.Batch(cmds...)
 
@@ -195,60 +484,215 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case statusMsg:
 		m.updateStatus(string(msg))
 		return m, nil
+	case warnMsg:
+		m.updateStatusWarn(string(msg))
+		return m, nil
+	case errorMsg:
+		m.updateStatusError(string(msg))
+		return m, nil
 
 	case metadataMsg:
-		go m.cfg.AddHistoryEntry(
-			time.Now(),
-			strings.TrimSpace(msg.stationUuid),
-			strings.TrimSpace(msg.stationName),
-			strings.TrimSpace(msg.songTitle),
-		)
-		m.songTitle = msg.songTitle
+		m.updateBuffer(msg.stationUuid, msg.buffer)
+		m.live.update(msg.stationUuid, msg.stream)
 		if msg.playbackTime != nil {
 			m.playbackTime = *msg.playbackTime
 		}
+		title, changed := m.songChange.Observe(msg.stationUuid, msg.songTitle)
+		if !changed {
+			return m, nil
+		}
+		m.songTitle = title
+		m.announcer.announce(title)
+		return m, m.onSongChange(msg.stationUuid, msg.stationName, title)
+
+	case streamMsg:
+		m.updateBuffer(msg.stationUuid, msg.buffer)
+		m.live.update(msg.stationUuid, msg.stream)
 		return m, nil
 
-	case spinner.TickMsg:
-		if m.spinner == nil {
+	case songInfoMsg:
+		if msg.song != m.song || msg.stationUuid != m.songChange.Station() {
+			return m, nil
+		}
+		m.recording = msg.recording
+		if m.recording.ReleaseID != "" {
+			return m, m.art.fetchCmd(artwork.CoverArtUrl(m.recording.ReleaseID))
+		}
+		return m, nil
+
+	case artworkMsg:
+		m.art.update(msg)
+		return m, nil
+
+	case lyricsMsg:
+		m.lyricsPanel.update(msg)
+		return m, nil
+
+	case mediaKeyMsg:
+		return m, m.mediaKeyCmd(msg)
+
+	case sessionLockMsg:
+		return m, m.sessionLocked(msg)
+
+	case outputChangeMsg:
+		return m, m.outputChanged(msg)
+
+	case duckMsg:
+		return m, m.duck(msg.active)
+
+	case hoverMsg:
+		return m, m.preconnectCmd(string(msg))
+
+	case probeMsg:
+		return m, m.onProbe(msg)
+
+	case tickMsg:
+		return m, m.onTick(msg)
+
+	case mapCountriesMsg:
+		m.onMapCountries(msg)
+		return m, nil
+
+	case gitSyncMsg:
+		return m, m.onGitSync(msg)
+
+	case webdavReadMsg:
+		return m, m.onWebdavRead(msg)
+
+	case webdavPutMsg:
+		return m, m.onWebdavPut(msg)
+
+	case syncReadMsg:
+		return m, m.onSyncRead(msg)
+
+	case networkStateMsg:
+		return m, m.checkNetwork(msg)
+
+	case reconnectMsg:
+		return m, m.reconnectCmd(msg)
+
+	case macroStepMsg:
+		return m, m.macroStep(msg)
+
+	case roomStationMsg:
+		return m, m.roomStationCmd(relay.Station(msg))
+
+	case bulkAddMsg:
+		return m, m.onBulkAdd(msg)
+
+	case guideMsg:
+		m.onGuide(msg)
+		return m, nil
+
+	case tuneInMsg:
+		return m, m.tuneIn(msg.stationUuid)
+
+	case recordingsChangedMsg:
+		return m.updateTab(recordingsTabIx, msg)
+
+	case diskUsageMsg:
+		return m.updateTab(settingsTabIx, msg)
+
+	case outputsFoundMsg:
+		m.outputs.searching = false
+		m.outputs.sinks = msg.sinks
+		m.outputs.devices = msg.devices
+		m.outputs.idx = min(m.outputs.idx, len(msg.sinks)+len(msg.devices))
+		return m, nil
+
+	case sessionCreatedMsg:
+		m.sessions.sessions = append(m.sessions.sessions, msg.session)
+		m.switchSession(len(m.sessions.sessions) - 1)
+		m.sessions.idx = m.sessions.active
+		m.updateStatus(fmt.Sprintf(sessionStartedMsg, msg.session.name))
+		return m, nil
+
+	case identifyMsg:
+		if msg.stationUuid != m.delegate.playingUuid() {
+			// switched stations while identifying
+			slog.With("method", "ui.Model.Update").Info("identified song of a station no longer playing", "station", msg.stationUuid)
+			return m, nil
+		}
+		if msg.err != nil {
+			m.updateStatusError(msg.err.Error())
+			return m, nil
+		}
+		m.updateStatus(fmt.Sprintf("Identified %s", msg.song))
+		title, changed := m.songChange.Observe(msg.stationUuid, msg.song.String())
+		if !changed {
 			return m, nil
 		}
-		var cmd tea.Cmd
-		s, cmd := m.spinner.Update(msg)
-		m.spinner = &s
-		return m, cmd
+		m.songTitle = title
+		return m, m.onSongChange(msg.stationUuid, msg.stationName, title)
 
 	//
 	// messages that need to reach a particular tab
 	//
-	case topStationsRespMsg, searchRespMsg:
-		return m.tabs[browseTabIx].Update(m, msg)
+	case topStationsRespMsg, searchRespMsg, loadAllPageMsg:
+		return m.updateTab(browseTabIx, msg)
 
 	case favoritesStationRespMsg:
-		return m.tabs[favoriteTabIx].Update(m, msg)
+		return m.updateTab(favoriteTabIx, msg)
 
 	case toggleFavoriteMsg:
-		return m.tabs[favoriteTabIx].Update(m, msg)
+		return m.updateTab(favoriteTabIx, msg)
 
 	case pauseRespMsg:
 		if msg.err != "" {
-			m.updateStatus(msg.err)
+			m.updateStatusError(msg.err)
 		} else {
 			m.spinner = nil
 			m.delegate.keymap.pause.SetHelp("space", "resume")
+			m.announcer.announce(pausedAnnounce)
 		}
 		return m, nil
 	case playRespMsg:
+		if msg.attempt != nil && msg.attempt == m.connecting {
+			m.connecting.cancel()
+			m.connecting = nil
+		}
 		if msg.err != "" {
-			m.updateStatus(msg.err)
+			m.updateStatusError(msg.err)
 			m.spinner = nil
 		}
-		m.delegate.keymap.pause.SetHelp("space", "pause")
+		if msg.err == "" && !msg.cancelled {
+			m.delegate.keymap.pause.SetHelp("space", "pause")
+			if s := m.delegate.currPlaying; s != nil {
+				m.announcer.announce(fmt.Sprintf(playingAnnounce, m.stationName(*s)))
+			}
+		}
 		return m, nil
 
 	case tea.KeyMsg:
 		if msg.String() == "ctrl+c" {
 			return m, tea.Quit
+		} else if cmd, ok := m.updateMacro(msg); ok {
+			return m, cmd
+		} else if m.dialAssign != nil {
+			m.assignDial(msg)
+			return m, nil
+		} else if m.palette.enabled {
+			return m, m.updatePalette(msg)
+		} else if m.logView.enabled {
+			return m, m.updateLogView(msg)
+		} else if m.sessions.enabled {
+			return m, m.updateSessions(msg)
+		} else if m.outputs.enabled {
+			return m, m.updateOutputs(msg)
+		} else if m.guideView.enabled {
+			return m, m.updateGuide(msg)
+		} else if m.liveView.enabled {
+			return m, m.updateLive(msg)
+		} else if m.mapView.enabled {
+			return m, m.updateMap(msg)
+		} else if m.favoriteForm.enabled {
+			return m, m.updateFavoriteForm(msg)
+		} else if m.bulkAdd.enabled {
+			return m, m.updateBulkAdd(msg)
+		} else if m.trash.enabled {
+			return m, m.updateTrash(msg)
+		} else if cmd, ok := m.updateFault(msg); ok {
+			return m, cmd
 		} else if activeTab, ok := activeTab.(filteringTab); ok && activeTab.IsFiltering() {
 			break
 		} else if activeTab, ok := activeTab.(stationTab); ok && (activeTab.IsSearchEnabled() || activeTab.IsFiltering()) {
@@ -257,6 +701,33 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		d := m.delegate
 
+		if key.Matches(msg, d.keymap.palette) {
+			return m, m.togglePalette()
+		}
+		if key.Matches(msg, d.keymap.logView) {
+			return m, m.toggleLogView()
+		}
+		if key.Matches(msg, d.keymap.perfOverlay) {
+			m.perfOverlay = !m.perfOverlay
+			return m, nil
+		}
+		if key.Matches(msg, d.keymap.banner) {
+			return m, m.toggleBanner()
+		}
+
+		if m.activeTabIdx != settingsTabIx {
+			switch {
+			case key.Matches(msg, d.keymap.cancelConnect) && m.connecting != nil:
+				m.cancelConnect()
+				return m, nil
+			case key.Matches(msg, d.keymap.detach):
+				m.detach = true
+				return m, tea.Quit
+			case key.Matches(msg, d.keymap.quit):
+				return m, tea.Quit
+			}
+		}
+
 		if key.Matches(msg, d.keymap.volumeDown) {
 			return m, m.volumeCmd(false)
 		}
@@ -265,20 +736,20 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		if key.Matches(msg, d.keymap.seekBack) {
 			if m.activeTabIdx == settingsTabIx {
-				return m.tabs[settingsTabIx].Update(m, msg)
+				return m.updateTab(settingsTabIx, msg)
 			}
 			return m, m.seekCmd(-config.SeekStepSec)
 		}
 		if key.Matches(msg, d.keymap.seekFw) {
 			if m.activeTabIdx == settingsTabIx {
-				return m.tabs[settingsTabIx].Update(m, msg)
+				return m.updateTab(settingsTabIx, msg)
 			}
 			return m, m.seekCmd(config.SeekStepSec)
 		}
 
 		if key.Matches(msg, d.keymap.pause) {
 			if m.activeTabIdx == settingsTabIx {
-				return m.tabs[settingsTabIx].Update(m, msg)
+				return m.updateTab(settingsTabIx, msg)
 			}
 
 			if resM, resCmd := m.handlePauseKey(); resM != nil {
@@ -298,9 +769,69 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			break
 		}
 
+		if m.activeTabIdx != settingsTabIx {
+			switch {
+			case key.Matches(msg, d.keymap.lyrics):
+				return m, m.toggleLyrics()
+			case key.Matches(msg, d.keymap.identify):
+				return m, m.identifyCmd()
+			case key.Matches(msg, d.keymap.sessions):
+				return m, m.toggleSessions()
+			case key.Matches(msg, d.keymap.outputs):
+				return m, m.toggleOutputs()
+			case key.Matches(msg, d.keymap.guide):
+				return m, m.toggleGuide()
+			case key.Matches(msg, d.keymap.live):
+				return m, m.toggleLive()
+			case key.Matches(msg, d.keymap.radioMap):
+				return m, m.toggleMap()
+			case key.Matches(msg, d.keymap.nearby):
+				return m, m.nearbyCmd()
+			case key.Matches(msg, d.keymap.swap):
+				return m, m.swapStationCmd()
+			case key.Matches(msg, d.keymap.assignDial):
+				m.startDialAssign()
+				return m, nil
+			case key.Matches(msg, d.keymap.dial):
+				return m, m.dialCmd(dialSlot(msg))
+			case key.Matches(msg, d.keymap.toPlaying):
+				return m, m.toPlaying()
+			case key.Matches(msg, d.keymap.editFavorite):
+				return m, m.startFavoriteForm()
+			case key.Matches(msg, d.keymap.tuneIn):
+				return m, m.tuneIn(m.reminders.stationUuid)
+			case key.Matches(msg, d.keymap.bulkAdd):
+				return m, m.toggleBulkAdd()
+			case key.Matches(msg, d.keymap.trash):
+				return m, m.toggleTrash()
+			case key.Matches(msg, d.keymap.refreshMetadata):
+				return m, m.refreshMetadata()
+			case key.Matches(msg, d.keymap.label):
+				m.cycleLabel()
+				return m, nil
+			case key.Matches(msg, d.keymap.syncNow):
+				return m, m.syncAllCmd()
+			case key.Matches(msg, d.keymap.clearFilters):
+				m.clearFilters()
+				return m, nil
+			case key.Matches(msg, d.keymap.lyricsUp, d.keymap.lyricsDown) && m.lyricsPanel.enabled:
+				m.lyricsPanel.scroll(key.Matches(msg, d.keymap.lyricsDown))
+				return m, nil
+			case key.Matches(msg, d.keymap.scheduleRecording):
+				activeTab, ok := activeTab.(stationTab)
+				if !ok {
+					break
+				}
+				if selStation, ok := activeTab.Stations().list.SelectedItem().(browser.Station); ok {
+					m.toRecordingsTab()
+					return m, m.tabs[recordingsTabIx].(*recordingsTab).newSchedule(selStation)
+				}
+			}
+		}
+
 		if key.Matches(msg, d.keymap.playSelected) {
 			if m.activeTabIdx == settingsTabIx {
-				return m.tabs[settingsTabIx].Update(m, msg)
+				return m.updateTab(settingsTabIx, msg)
 			}
 
 			activeTab, ok := activeTab.(stationTab)
@@ -317,8 +848,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	//
 	// messages that need to reach active tab
 	//
-	model, cmd := activeTab.Update(m, msg)
-	return model, cmd
+	if _, ok := msg.(tea.KeyMsg); ok && m.boundary.faults[m.activeTabIdx] != nil {
+		return m, nil
+	}
+	return m.updateTab(m.activeTabIdx, msg)
 }
 
 func (m *Model) handlePauseKey() (*Model, tea.Cmd) {
@@ -326,6 +859,7 @@ func (m *Model) handlePauseKey() (*Model, tea.Cmd) {
 	log.Info("begin")
 	defer log.Info("end")
 
+	m.autoPaused = noAutoPause
 	m.delegate.playingMtx.RLock()
 	defer m.delegate.playingMtx.RUnlock()
 
@@ -348,40 +882,114 @@ func (m *Model) statusHandler(ctx context.Context) {
 			return
 		case <-t.C:
 			m.statusMsg = ""
-		case <-m.statusUpdate:
+		case d := <-m.statusUpdate:
 			t.Stop()
-			t.Reset(statusMsgTimeout)
+			t.Reset(d)
 		}
 	}
 }
 
+// setActiveTab shows the tab, announced to the screen readers.
+func (m *Model) setActiveTab(ix uiTabIndex) {
+	m.activeTabIdx = ix
+	m.announcer.announce(fmt.Sprintf(tabAnnounce, strings.TrimSpace(ix.String())))
+}
+
 func (m *Model) toFavoritesTab() {
+	m.delegate.favoritesTab = true
 	m.delegate.keymap.toggleFavorite.SetEnabled(false)
 	m.delegate.keymap.toggleAutoplay.SetEnabled(true)
-	m.activeTabIdx = favoriteTabIx
+	m.setActiveTab(favoriteTabIx)
 }
 
 func (m *Model) toBrowseTab() {
+	m.delegate.favoritesTab = false
 	m.delegate.keymap.toggleFavorite.SetEnabled(true)
 	m.delegate.keymap.toggleAutoplay.SetEnabled(false)
-	m.activeTabIdx = browseTabIx
+	m.setActiveTab(browseTabIx)
+}
+
+// selectedStation returns the station selected in the active tab, if it lists stations.
+func (m *Model) selectedStation() (browser.Station, bool) {
+	activeTab, ok := m.tabs[m.activeTabIdx].(stationTab)
+	if !ok {
+		return browser.Station{}, false
+	}
+	st, ok := activeTab.Stations().list.SelectedItem().(browser.Station)
+	return st, ok
+}
+
+// toPlaying moves to the list with the station playing, the active one first, and selects it.
+func (m *Model) toPlaying() tea.Cmd {
+	uuid := m.delegate.playingUuid()
+	if uuid == "" {
+		m.updateStatusWarn(noPlayingMsg)
+		return nil
+	}
+	order := []uiTabIndex{favoriteTabIx, browseTabIx, historyTabIx}
+	if i := slices.Index(order, m.activeTabIdx); i > 0 {
+		order[0], order[i] = order[i], order[0]
+	}
+	for _, ix := range order {
+		if ix == historyTabIx {
+			if m.tabs[historyTabIx].(*historyTab).selectStation(uuid) {
+				m.toHistoryTab()
+				return nil
+			}
+			continue
+		}
+		cmd, ok := m.tabs[ix].(stationTab).Stations().selectStation(uuid)
+		if !ok {
+			continue
+		}
+		if ix == favoriteTabIx {
+			m.toFavoritesTab()
+		} else {
+			m.toBrowseTab()
+		}
+		return cmd
+	}
+	m.updateStatusWarn(playingNotListed)
+	return nil
 }
 
 func (m *Model) toHistoryTab() {
-	m.activeTabIdx = historyTabIx
+	m.setActiveTab(historyTabIx)
+}
+
+func (m *Model) toRecordingsTab() {
+	m.setActiveTab(recordingsTabIx)
 }
 
 func (m *Model) toSettingsTab() tea.Cmd {
-	m.activeTabIdx = settingsTabIx
+	m.setActiveTab(settingsTabIx)
 	st := m.tabs[settingsTabIx].(*settingsTab)
 	return st.onEnter()
 }
 
 func (m *Model) updateStatus(msg string) {
-	slog.Info("updateStatus", "old", m.statusMsg, "new", msg)
+	m.setStatus(infoStatus, msg)
+}
+
+func (m *Model) updateStatusWarn(msg string) {
+	m.setStatus(warnStatus, msg)
+}
+
+func (m *Model) updateStatusError(msg string) {
+	m.setStatus(errorStatus, msg)
+}
+
+// setStatus shows the message in the header, with the style of its level, for the duration of the level.
+func (m *Model) setStatus(level statusLevel, msg string) {
+	slog.Info("updateStatus", "old", m.statusMsg, "new", msg, "level", level)
 	m.statusMsg = msg
+	m.statusLevel = level
+	m.announcer.announce(level.announcement(msg))
+	if level == errorStatus {
+		m.events.Publish(events.Event{Type: events.Error, Error: msg})
+	}
 	go func() {
-		m.statusUpdate <- struct{}{}
+		m.statusUpdate <- level.timeout()
 	}()
 }
 
@@ -389,16 +997,62 @@ func (m *Model) Quit() {
 	log := slog.With("method", "ui.model.quit")
 	log.Info("----------------------Quitting----------------------")
 
-	// stop player
-	err := m.player.Stop()
-	if err != nil {
-		log.Error("player stop", "error", err.Error())
+	// the next start plays locally, with the local volume
+	m.cfg.SwitchOutputVolume(m.outputKey(), localOutputKey)
+
+	if m.mpris != nil {
+		if err := m.mpris.Close(); err != nil {
+			log.Error("mpris close", "error", err)
+		}
+	}
+	if m.stopDuck != nil {
+		m.stopDuck()
+	}
+	if m.lockWatcher != nil {
+		if err := m.lockWatcher.Close(); err != nil {
+			log.Error("lock watcher close", "error", err)
+		}
+	}
+	if m.relay != nil {
+		if err := m.relay.Close(); err != nil {
+			log.Error("relay close", "error", err)
+		}
 	}
-	err = m.player.Close()
+	if err := m.events.Close(); err != nil {
+		log.Error("events close", "error", err)
+	}
+	m.clearTitle()
+	m.clearOsd()
+
+	// stop player, unless detaching
+	m.closeInactiveSessions()
+	switch {
+	case m.player.Output() != nil && m.detach:
+		// the renderer plays the stream on its own
+	case m.player.Attached() && m.detach:
+		m.detached = true
+	case m.player.Attached():
+		if err := m.player.Stop(); err != nil {
+			log.Error("player stop", "error", err.Error())
+		}
+		if err := m.player.Shutdown(); err != nil {
+			log.Error("daemon shutdown", "error", err.Error())
+		}
+	case m.detach && m.handOff():
+		m.detached = true
+	default:
+		if err := m.player.Stop(); err != nil {
+			log.Error("player stop", "error", err.Error())
+		}
+	}
+	err := m.player.Close()
 	if err != nil {
 		slog.Error(fmt.Sprintf("player close error: %v", err))
 	}
 
+	// wait for the running recordings to be saved
+	m.scheduler.Close()
+
 	// save config
 	autoplayFound := false
 	for _, v := range m.cfg.Favorites {
@@ -413,6 +1067,16 @@ func (m *Model) Quit() {
 	st := m.tabs[settingsTabIx].(*settingsTab)
 	st.updateConfig()
 
+	if err := m.browser.SaveCache(); err != nil {
+		log.Error("browser cache save", "error", err)
+	}
+	if err := m.musicBrainz.Save(); err != nil {
+		log.Error("musicbrainz cache save", "error", err)
+	}
+	enforceCacheQuota(m.cfg)
+	m.scheduler.EnforceQuota()
+	m.syncNow()
+
 	err = m.cfg.Save()
 	if err != nil {
 		log.Info(fmt.Sprintf("config save err: %v", err))
@@ -423,26 +1087,62 @@ func (m *Model) Quit() {
 func (m *Model) newSpinner() *spinner.Model {
 	s := spinner.New()
 	s.Spinner = spinner.Spinner{
-		Frames: []string{"⡷", "⣧", "⣏", "⡟", "⡷", "⣧", "⣏", "⡟"},
+		Frames: styles.SpinnerFrames,
 		FPS:    time.Second / 10,
 	}
 	s.Style = m.style.SongTitleStyle
 	return &s
 }
 
+// initSpinner shows a new spinner, its frames turning on the tick.
 func (m *Model) initSpinner() tea.Cmd {
 	m.spinner = m.newSpinner()
-	return m.spinner.Tick
+	return m.retick()
+}
+
+// spinInterval is the interval of the spinner frames while it's shown.
+func (m *Model) spinInterval() time.Duration {
+	if m.spinner == nil || m.cfg.NoMotion() {
+		return 0
+	}
+	return m.spinner.Spinner.FPS
+}
+
+func (m *Model) spin(now time.Time) tea.Cmd {
+	// the frame is turned by the tick of the ticker, not by the one returned by the spinner
+	s, _ := m.spinner.Update(spinner.TickMsg{ID: m.spinner.ID(), Time: now})
+	m.spinner = &s
+	return nil
 }
 
 func (m *Model) headerView(width int) string {
 	var res strings.Builder
 	status := ""
-	if len(m.statusMsg) > 0 {
-		status = m.style.StatusBarStyle.Render(strings.Repeat(" ", styles.HeaderPadDist) + m.statusMsg)
+	if m.idleWarning {
+		status = m.statusView(warnStatus, idleWarningMsg)
+	} else if len(m.statusMsg) > 0 {
+		status = m.statusView(m.statusLevel, m.statusMsg)
+	} else if m.connecting != nil {
+		status = m.statusView(infoStatus, fmt.Sprintf(connectingMsg, m.stationName(m.connecting.station)))
+	} else if m.guideNotice != "" {
+		status = m.statusView(infoStatus, m.guideNotice)
+	} else if m.reminders.notice != "" {
+		status = m.statusView(infoStatus, m.reminders.notice)
+	} else if m.bandwidthWarning != "" {
+		status = m.statusView(warnStatus, m.bandwidthWarning)
 	}
 	res.WriteString(status)
-	appNameVers := m.style.StatusBarStyle.Render(fmt.Sprintf("sonicradio v%v  ", m.cfg.Version))
+	appName := m.quietView() + m.bufferView() + m.clockView(time.Now()) + fmt.Sprintf("sonicradio v%v  ", m.cfg.Version)
+	if len(m.sessions.sessions) > 1 {
+		appName = m.sessions.sessions[m.sessions.active].name + " · " + appName
+	}
+	if out := m.player.Output(); out != nil {
+		appName = styles.CastChar + out.Name() + " · " + appName
+	}
+	if m.macroRec != nil {
+		appName = macroRecordingTag + " · " + appName
+	}
+	appNameVers := m.style.StatusBarStyle.Render(appName)
 	fill := max(0, width-lipgloss.Width(status)-lipgloss.Width(appNameVers)-2*styles.HeaderPadDist)
 	res.WriteString(m.style.StatusBarStyle.Render(strings.Repeat(" ", fill)))
 	res.WriteString(appNameVers)
@@ -450,6 +1150,9 @@ func (m *Model) headerView(width int) string {
 
 	metadata := m.metadataView(width)
 	res.WriteString(metadata)
+	if m.bannerVisible() {
+		res.WriteString("\n\n" + m.bannerView(width))
+	}
 
 	res.WriteString("\n\n")
 
@@ -508,6 +1211,10 @@ func (m *Model) metadataView(width int) string {
 		int(m.playbackTime.Seconds())%60,
 		gap,
 	)
+	if m.cfg.NoMotion() {
+		// the minutes, not to redraw the header every second
+		playTime = fmt.Sprintf("%s%03d:%02d%s", gap, int(m.playbackTime.Hours()), int(m.playbackTime.Minutes())%60, gap)
+	}
 	playTimeView := m.style.ItalicStyle.Render(playTime)
 	metadataParts[0] = playTimeView
 
@@ -525,15 +1232,36 @@ func (m *Model) metadataView(width int) string {
 	m.delegate.playingMtx.RLock()
 	defer m.delegate.playingMtx.RUnlock()
 
-	if m.delegate.currPlaying != nil {
+	if m.connecting != nil {
+		if m.spinner == nil {
+			m.spinner = m.newSpinner()
+		}
+		var line strings.Builder
+		line.WriteString(m.spinner.View())
+		line.WriteString(
+			m.style.PrimaryColorStyle.MaxWidth(maxW - 1).Render(
+				" " + m.stationName(m.connecting.station)))
+		fill := max(0, maxW-lipgloss.Width(line.String()))
+		line.WriteString(m.style.PrimaryColorStyle.Render(strings.Repeat(" ", fill)))
+		songView.WriteString(line.String())
+	} else if m.delegate.currPlaying != nil {
 		if m.spinner == nil {
 			m.spinner = m.newSpinner()
 		}
 		var line strings.Builder
 		line.WriteString(m.spinner.View())
+		name := m.stationName(*m.delegate.currPlaying)
+		if program := m.currentProgram(m.delegate.currPlaying.Stationuuid); program != "" {
+			name += " · " + program
+		}
+		if live := m.live.get(m.delegate.currPlaying.Stationuuid); live != nil {
+			if summary := streamSummary(*live); summary != "" {
+				name += " · " + summary
+			}
+		}
 		line.WriteString(
 			m.style.PrimaryColorStyle.MaxWidth(maxW - 1).Render(
-				" " + m.delegate.currPlaying.Name))
+				" " + name))
 		fill := max(0, maxW-lipgloss.Width(line.String()))
 		line.WriteString(m.style.PrimaryColorStyle.Render(strings.Repeat(" ", fill)))
 		songView.WriteString(line.String())
@@ -542,7 +1270,7 @@ func (m *Model) metadataView(width int) string {
 		line.WriteString(m.style.SongTitleStyle.Render(styles.PauseChar))
 		line.WriteString(
 			m.style.PrimaryColorStyle.MaxWidth(maxW - 1).Render(
-				" " + m.delegate.prevPlaying.Name))
+				" " + m.stationName(*m.delegate.prevPlaying)))
 		fill := max(0, maxW-lipgloss.Width(line.String()))
 		line.WriteString(m.style.PrimaryColorStyle.Render(strings.Repeat(" ", fill)))
 		songView.WriteString(line.String())
@@ -554,9 +1282,22 @@ func (m *Model) metadataView(width int) string {
 		songView.WriteString(line.String())
 	}
 	songView.WriteString("\n")
-	if m.songTitle != "" {
+	if text, ok := m.nowPlayingLine(); ok {
 		var line strings.Builder
-		line.WriteString(m.style.SongTitleStyle.MaxWidth(maxW).Render("  " + m.songTitle))
+		line.WriteString(m.style.SongTitleStyle.MaxWidth(maxW).Render("  " + text))
+		fill := max(0, maxW-lipgloss.Width(line.String()))
+		line.WriteString(m.style.PrimaryColorStyle.Render(strings.Repeat(" ", fill)))
+		songView.WriteString(line.String())
+	} else if m.songTitle != "" {
+		var line strings.Builder
+		songTitle := "  " + m.songTitle
+		if m.recording.Found() && m.recording.Album != "" {
+			songTitle += " · " + m.recording.Album
+			if m.recording.Year != "" {
+				songTitle += " (" + m.recording.Year + ")"
+			}
+		}
+		line.WriteString(m.style.SongTitleStyle.MaxWidth(maxW).Render(songTitle))
 		fill := max(0, maxW-lipgloss.Width(line.String()))
 		line.WriteString(m.style.PrimaryColorStyle.Render(strings.Repeat(" ", fill)))
 		songView.WriteString(line.String())
@@ -587,15 +1328,58 @@ func (m Model) View() string {
 	if !m.ready {
 		return loadingMsg
 	}
+	defer perf.Since(perf.Render, time.Now())
 
 	var doc strings.Builder
+	visible := m.artVisible()
+	if m.art.proto == artwork.Kitty && m.art.shown && !visible {
+		// kitty keeps images on screen until deleted
+		doc.WriteString(artwork.ClearKitty())
+	}
+	m.art.shown = visible
 	header := m.headerView(m.width)
 	doc.WriteString(header)
-	tabView := m.tabs[m.activeTabIdx].View()
+	tabView := m.viewTab(m.activeTabIdx)
+	if m.palette.enabled {
+		tabView = m.palette.View()
+	} else if m.logView.enabled {
+		tabView = m.logView.View()
+	} else if m.sessions.enabled {
+		tabView = m.sessions.View(m.cfg)
+	} else if m.outputs.enabled {
+		tabView = m.outputs.View(m.activeOutput())
+	} else if m.guideView.enabled {
+		tabView = m.guideView.View(m.cfg, m.guides[m.guideView.station.Stationuuid])
+	} else if m.liveView.enabled {
+		tabView = m.liveView.View(m.cfg)
+	} else if m.mapView.enabled {
+		tabView = m.mapView.View()
+	} else if m.favoriteForm.enabled {
+		tabView = m.favoriteForm.View()
+	} else if m.bulkAdd.enabled {
+		tabView = m.bulkAdd.View()
+	} else if m.trash.enabled {
+		tabView = m.trash.View(m.cfg.Trash)
+	}
+	if m.perfOverlay {
+		tabView = overlayTop(tabView, m.perfView(m.tabWidth()))
+	}
+	if m.lyricsPanel.enabled {
+		tabView = lipgloss.JoinHorizontal(lipgloss.Top, tabView, m.lyricsPanel.View())
+	}
 	doc.WriteString(tabView)
 	return m.style.DocStyle.Render(doc.String())
 }
 
+func (m Model) artVisible() bool {
+	st, ok := m.tabs[m.activeTabIdx].(stationTab)
+	if !ok || !st.IsInfoEnabled() {
+		return false
+	}
+	im := st.Stations().infoModel
+	return m.art.image(im.station.Stationuuid) != nil
+}
+
 func (m *Model) changeStationView() {
 	log := slog.With("method", "ui.Model.changeStationView")
 	m.cfg.StationView = (m.cfg.StationView + 1) % 3
@@ -608,6 +1392,21 @@ func (m *Model) changeStationView() {
 	}
 }
 
+// clearFilters clears the filters of all the lists, which are kept when switching tabs or going to the playing station.
+func (m *Model) clearFilters() {
+	for i := range m.tabs {
+		switch t := m.tabs[i].(type) {
+		case stationTab:
+			t.Stations().list.ResetFilter()
+		case *historyTab:
+			t.list.ResetFilter()
+		case *recordingsTab:
+			t.list.ResetFilter()
+		}
+	}
+	m.updateStatus(filtersClearedMsg)
+}
+
 func (m *Model) changeTheme(themeIdx int) {
 	m.style.SetThemeIdx(themeIdx)
 	m.cfg.Theme = themeIdx
@@ -671,7 +1470,7 @@ func logTeaMsg(msg tea.Msg, tag string) {
 	switch msg.(type) {
 	case favoritesStationRespMsg, topStationsRespMsg, searchRespMsg, toggleInfoMsg:
 		log.Info("tea.Msg", "type", fmt.Sprintf("%T", msg))
-	case cursor.BlinkMsg, spinner.TickMsg, list.FilterMatchesMsg:
+	case cursor.BlinkMsg, tickMsg, list.FilterMatchesMsg:
 		break
 	default:
 		log.Info("tea.Msg", "type", fmt.Sprintf("%T", msg), "value", msg, "#", fmt.Sprintf("%#v", msg))
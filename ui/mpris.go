@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/mpris"
+)
+
+// kittyMediaKeys are the key codes of the media keys in the kitty keyboard protocol,
+// sent as CSI code u by the terminals that forward them.
+var kittyMediaKeys = map[int]mpris.Action{
+	57428: mpris.Play,
+	57429: mpris.Pause,
+	57430: mpris.PlayPause,
+	57432: mpris.Stop,
+	57435: mpris.Next,
+	57436: mpris.Previous,
+}
+
+// startMpris registers the app on the session bus, so the media keys work when the terminal is not focused.
+func (m *Model) startMpris(progr *tea.Program) {
+	s, err := mpris.Start(func(c mpris.Command) {
+		progr.Send(mediaKeyMsg{action: c.Action, volume: c.Volume})
+	})
+	if err != nil {
+		slog.With("method", "ui.Model.startMpris").Info("media keys through MPRIS unavailable", "error", err)
+		return
+	}
+	m.mpris = s
+}
+
+// parseMediaKey returns the media key of an unknown CSI sequence reported by bubbletea,
+// whose type is not exported.
+func parseMediaKey(msg tea.Msg) (mediaKeyMsg, bool) {
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.Uint8 || v.Type().PkgPath() != reflect.TypeOf(tea.KeyMsg{}).PkgPath() {
+		return mediaKeyMsg{}, false
+	}
+	seq, ok := strings.CutPrefix(string(v.Bytes()), "\x1b[")
+	if !ok {
+		return mediaKeyMsg{}, false
+	}
+	seq, ok = strings.CutSuffix(seq, "u")
+	if !ok {
+		return mediaKeyMsg{}, false
+	}
+	// the code may be followed by the alternate keys, the modifiers and the event type
+	code, _, _ := strings.Cut(seq, ";")
+	code, _, _ = strings.Cut(code, ":")
+	n, err := strconv.Atoi(code)
+	if err != nil {
+		return mediaKeyMsg{}, false
+	}
+	if _, rest, _ := strings.Cut(seq, ";"); strings.HasSuffix(rest, ":3") {
+		// key release
+		return mediaKeyMsg{}, false
+	}
+	a, ok := kittyMediaKeys[n]
+	return mediaKeyMsg{action: a}, ok
+}
+
+func (m *Model) mediaKeyCmd(msg mediaKeyMsg) tea.Cmd {
+	log := slog.With("method", "ui.Model.mediaKeyCmd")
+	log.Info("media key", "action", msg.action)
+
+	m.delegate.playingMtx.RLock()
+	playing := m.delegate.currPlaying != nil
+	m.delegate.playingMtx.RUnlock()
+
+	switch msg.action {
+	case mpris.PlayPause:
+		_, cmd := m.handlePauseKey()
+		return cmd
+	case mpris.Play:
+		if playing {
+			return nil
+		}
+		_, cmd := m.handlePauseKey()
+		return cmd
+	case mpris.Pause, mpris.Stop:
+		if !playing {
+			return nil
+		}
+		_, cmd := m.handlePauseKey()
+		return cmd
+	case mpris.Next:
+		return m.playFavoriteCmd(1)
+	case mpris.Previous:
+		return m.playFavoriteCmd(-1)
+	case mpris.Volume:
+		return m.setVolumeCmd(msg.volume)
+	}
+	return nil
+}
+
+// playFavoriteCmd plays the favorite station at step from the playing one.
+func (m *Model) playFavoriteCmd(step int) tea.Cmd {
+	stations := m.favoriteStations()
+	if len(stations) == 0 {
+		return nil
+	}
+
+	m.delegate.playingMtx.RLock()
+	curr := m.delegate.currPlaying
+	if curr == nil {
+		curr = m.delegate.prevPlaying
+	}
+	idx := -1
+	for i := range stations {
+		if curr != nil && stations[i].Stationuuid == curr.Stationuuid {
+			idx = i
+			break
+		}
+	}
+	m.delegate.playingMtx.RUnlock()
+
+	if idx < 0 && step < 0 {
+		idx = 0
+	}
+	idx = (idx + step + len(stations)) % len(stations)
+	return m.playStationCmd(stations[idx])
+}
+
+func (m *Model) setVolumeCmd(vol int) tea.Cmd {
+	vol = min(vol, m.maxVolume())
+	return func() tea.Msg {
+		setVol, err := m.player.SetVolume(vol)
+		if err != nil {
+			return volumeMsg{err}
+		}
+		m.cfg.SetVolume(setVol)
+		return volumeMsg{}
+	}
+}
+
+// updateMpris shows the playback to the MPRIS clients. It runs after every update,
+// so it skips the update instead of waiting for a station to start playing.
+func (m *Model) updateMpris() {
+	if m.mpris == nil {
+		return
+	}
+	if !m.delegate.playingMtx.TryRLock() {
+		return
+	}
+	st := mpris.State{Status: mpris.Stopped, Volume: m.cfg.GetVolume()}
+	s := m.delegate.currPlaying
+	if s != nil {
+		st.Status = mpris.Playing
+	} else if s = m.delegate.prevPlaying; s != nil {
+		st.Status = mpris.Paused
+	}
+	m.delegate.playingMtx.RUnlock()
+
+	if s != nil {
+		st.Station = m.stationName(*s)
+		// the cached file, the clients fetching the remote url again otherwise
+		st.ArtURL = m.art.artUrl(s.Stationuuid)
+		if m.songTitle != "" {
+			st.Artist = m.song.Artist
+			st.Title = m.song.Title
+		}
+	}
+	m.mpris.Update(st)
+}
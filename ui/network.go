@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/netwatch"
+)
+
+const networkReconnectMsg = "Reconnecting after the network changed..."
+
+// readNetworkCmd reads the network state outside of the update.
+func (m *Model) readNetworkCmd(time.Time) tea.Cmd {
+	return func() tea.Msg {
+		state, err := netwatch.State()
+		return networkStateMsg{state: state, err: err}
+	}
+}
+
+// checkNetwork reconnects the streams when the network changed, instead of waiting for their
+// connections to time out. Losing the network is left to the player, as there's nothing to reconnect to.
+func (m *Model) checkNetwork(msg networkStateMsg) tea.Cmd {
+	log := slog.With("method", "ui.Model.checkNetwork")
+	if msg.err != nil {
+		log.Info("network state", "error", msg.err)
+		return nil
+	}
+	prev := m.netState
+	m.netState = msg.state
+	if prev == msg.state || msg.state == "" || !m.isPlaying() {
+		return nil
+	}
+	log.Info("network changed", "from", prev, "to", msg.state)
+	m.updateStatus(networkReconnectMsg)
+	return m.scheduleReconnect()
+}
+
+func networkState() string {
+	state, err := netwatch.State()
+	if err != nil {
+		slog.With("method", "ui.networkState").Info("network state", "error", err)
+	}
+	return state
+}
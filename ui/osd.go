@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"log/slog"
+	"os"
+)
+
+// osdFile is the text written to the OSD file of the config, read by the streaming software to show the
+// playing station and song over a video.
+type osdFile struct {
+	path string
+	last string
+}
+
+// updateOsd writes the playing station and song to the OSD file, when they changed. The file is empty
+// while nothing plays.
+func (m *Model) updateOsd() {
+	if m.cfg.OsdFile == "" {
+		return
+	}
+	d, ok := m.nowPlayingData()
+	if !ok {
+		return
+	}
+	text := ""
+	if d.State != "" {
+		if text, ok = m.templates.osd.execute(m.cfg.OsdFormat, d); !ok {
+			text = d.Station
+			if d.Song != "" {
+				text += " – " + d.Song
+			}
+		}
+	}
+	if m.cfg.OsdFile == m.osd.path && text == m.osd.last {
+		return
+	}
+	m.osd = osdFile{path: m.cfg.OsdFile, last: text}
+	if err := writeOsd(m.osd.path, text); err != nil {
+		slog.With("method", "ui.Model.updateOsd").Error("write osd file", "path", m.osd.path, "error", err)
+	}
+}
+
+// clearOsd empties the OSD file written.
+func (m *Model) clearOsd() {
+	if m.osd.last == "" {
+		return
+	}
+	m.osd.last = ""
+	if err := writeOsd(m.osd.path, ""); err != nil {
+		slog.With("method", "ui.Model.clearOsd").Error("write osd file", "path", m.osd.path, "error", err)
+	}
+}
+
+// writeOsd replaces the file at once, for it not to be read half written.
+func writeOsd(path, text string) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(text), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,353 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/audiosink"
+	"github.com/dancnb/sonicradio/cast"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/player"
+	"github.com/dancnb/sonicradio/ui/styles"
+)
+
+const (
+	localOutputKey    = "local"
+	localOutputName   = "Local player"
+	searchingOutputs  = "Searching for renderers..."
+	outputSelectedMsg = "Playing on %s"
+)
+
+// outputsView lists the local player, the Bluetooth audio sinks and the renderers found on the network,
+// to choose where the playback goes.
+type outputsView struct {
+	enabled   bool
+	searching bool
+	style     *styles.Style
+
+	sinks   []audiosink.Sink
+	devices []cast.Device
+	idx     int
+	// sink is the Bluetooth sink the local playback was moved to
+	sink string
+
+	keymap outputsKeymap
+	help   help.Model
+	width  int
+	height int
+}
+
+func newOutputsView(s *styles.Style) *outputsView {
+	h := help.New()
+	h.ShortSeparator = "   "
+	h.Styles = s.HelpStyles()
+	return &outputsView{
+		style:  s,
+		keymap: newOutputsKeymap(),
+		help:   h,
+	}
+}
+
+func (v *outputsView) setSize(width, height int) {
+	h, vf := v.style.DocStyle.GetFrameSize()
+	v.width = width - h
+	v.height = height - vf
+	v.help.Width = v.width
+}
+
+func (v *outputsView) View(active string) string {
+	var b strings.Builder
+	b.WriteString("\n")
+	names := []string{localOutputName}
+	kinds := []string{""}
+	for _, s := range v.sinks {
+		names = append(names, s.Description)
+		kinds = append(kinds, "Bluetooth")
+	}
+	for _, d := range v.devices {
+		names = append(names, d.Name)
+		kinds = append(kinds, d.Kind.String())
+	}
+	for i, name := range names {
+		prefix := "  "
+		if name == active {
+			prefix = styles.ActiveChar
+		}
+		itStyle := v.style.SecondaryColorStyle
+		if i == v.idx {
+			itStyle = v.style.HistorySelItemStyle
+		}
+		kind := v.style.ItalicStyle.Render(kinds[i])
+		line := prefix + name
+		fill := max(0, v.width-lipgloss.Width(line)-lipgloss.Width(kind))
+		b.WriteString(itStyle.Render(line+strings.Repeat(" ", fill)) + kind + "\n")
+	}
+	if v.searching {
+		b.WriteString("\n" + v.style.ItalicStyle.Render(searchingOutputs) + "\n")
+	}
+
+	help := v.style.HelpStyle.Render(v.help.View(&v.keymap))
+	for i := lipgloss.Height(b.String()); i < v.height-lipgloss.Height(help); i++ {
+		b.WriteString("\n")
+	}
+	return b.String() + help
+}
+
+// toggleOutputs shows or hides the outputs view, searching the network when shown.
+func (m *Model) toggleOutputs() tea.Cmd {
+	v := m.outputs
+	v.enabled = !v.enabled
+	if !v.enabled {
+		return nil
+	}
+	v.idx = 0
+	v.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	return m.discoverOutputsCmd()
+}
+
+func (m *Model) discoverOutputsCmd() tea.Cmd {
+	if m.outputs.searching {
+		return nil
+	}
+	m.outputs.searching = true
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), config.CastDiscoveryTimeout)
+		defer cancel()
+		log := slog.With("method", "ui.Model.discoverOutputsCmd")
+		var sinks []audiosink.Sink
+		if audiosink.Available() {
+			var err error
+			if sinks, err = audiosink.BluetoothSinks(); err != nil {
+				log.Error("bluetooth sinks", "error", err)
+			}
+		}
+		devices, err := cast.Discover(ctx)
+		if err != nil {
+			log.Error("", "error", err)
+		}
+		return outputsFoundMsg{sinks: sinks, devices: devices}
+	}
+}
+
+// activeOutput is the name of the renderer the playback is cast to, or of the Bluetooth sink it was moved to.
+func (m *Model) activeOutput() string {
+	if out := m.player.Output(); out != nil {
+		return out.Name()
+	}
+	for _, s := range m.outputs.sinks {
+		if s.Name == m.outputs.sink {
+			return s.Description
+		}
+	}
+	return localOutputName
+}
+
+// outputKey identifies the output for its remembered volume.
+func (m *Model) outputKey() string {
+	if out := m.player.Output(); out != nil {
+		return castOutputKey(out.Name())
+	}
+	if m.outputs.sink != "" {
+		return sinkOutputKey(m.outputs.sink)
+	}
+	return localOutputKey
+}
+
+func castOutputKey(name string) string {
+	return "cast:" + name
+}
+
+func sinkOutputKey(name string) string {
+	return "sink:" + name
+}
+
+// selectOutputCmd moves the playback to the local player if d is nil, otherwise to the renderer.
+func (m *Model) selectOutputCmd(d *cast.Device) tea.Cmd {
+	name, to := localOutputName, localOutputKey
+	if d != nil {
+		name, to = d.Name, castOutputKey(d.Name)
+	}
+	m.updateStatus(fmt.Sprintf("Connecting to %s...", name))
+	from := m.outputKey()
+	m.outputs.sink = ""
+	p := m.player
+	return func() tea.Msg {
+		log := slog.With("method", "ui.Model.selectOutputCmd")
+		var r cast.Renderer
+		if d != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), config.CastReqTimeout)
+			defer cancel()
+			var err error
+			r, err = cast.Connect(ctx, *d)
+			if err != nil {
+				log.Error("connect", "device", d.Name, "error", err)
+				return errorMsg(fmt.Sprintf("Could not connect to %s: %v", d.Name, err))
+			}
+		}
+
+		m.delegate.playingMtx.Lock()
+		defer m.delegate.playingMtx.Unlock()
+		if err := p.Stop(); err != nil {
+			log.Error("stop", "error", err)
+		}
+		p.SetOutput(r)
+		m.cfg.SwitchOutputVolume(from, to)
+		if _, err := p.SetVolume(m.cfg.GetVolume()); err != nil {
+			log.Error("volume", "error", err)
+		}
+		s, paused := m.delegate.currPlaying, false
+		if s == nil {
+			s, paused = m.delegate.prevPlaying, true
+		}
+		if s == nil {
+			return statusMsg(fmt.Sprintf(outputSelectedMsg, name))
+		}
+		if err := p.Play(s.URL); err != nil {
+			log.Error("play", "device", name, "error", err)
+			return errorMsg(fmt.Sprintf("Could not play %s on %s: %v", s.Name, name, err))
+		}
+		if paused {
+			if err := p.Pause(true); err != nil {
+				log.Error("pause", "device", name, "error", err)
+			}
+		}
+		return statusMsg(fmt.Sprintf(outputSelectedMsg, name))
+	}
+}
+
+// selectSinkCmd moves the local playback to the Bluetooth sink, playing locally first if casting.
+func (m *Model) selectSinkCmd(s audiosink.Sink) tea.Cmd {
+	var cmds []tea.Cmd
+	if m.player.Output() != nil {
+		cmds = append(cmds, m.selectOutputCmd(nil))
+	}
+	from := localOutputKey
+	if m.outputs.sink != "" {
+		from = sinkOutputKey(m.outputs.sink)
+	}
+	m.outputs.sink = s.Name
+	p := m.player
+	cmds = append(cmds, func() tea.Msg {
+		log := slog.With("method", "ui.Model.selectSinkCmd")
+		n, err := audiosink.MoveStreams(s, p.Pid())
+		if err != nil {
+			log.Error("", "sink", s.Name, "error", err)
+			return errorMsg(fmt.Sprintf("Could not move the playback to %s: %v", s.Description, err))
+		} else if n == 0 {
+			return warnMsg(fmt.Sprintf("Nothing playing to move to %s", s.Description))
+		}
+		m.setOutputVolume(p, from, sinkOutputKey(s.Name))
+		return statusMsg(fmt.Sprintf(outputSelectedMsg, s.Description))
+	})
+	return m.sequence(cmds...)
+}
+
+// defaultSinkCmd moves the local playback back from the Bluetooth sink to the default one.
+func (m *Model) defaultSinkCmd() tea.Cmd {
+	from := sinkOutputKey(m.outputs.sink)
+	m.outputs.sink = ""
+	p := m.player
+	return func() tea.Msg {
+		log := slog.With("method", "ui.Model.defaultSinkCmd")
+		s, err := audiosink.DefaultSink()
+		if err == nil {
+			_, err = audiosink.MoveStreams(s, p.Pid())
+		}
+		if err != nil {
+			log.Error("", "error", err)
+			return errorMsg(fmt.Sprintf("Could not move the playback to the default output: %v", err))
+		}
+		m.setOutputVolume(p, from, localOutputKey)
+		return statusMsg(fmt.Sprintf(outputSelectedMsg, localOutputName))
+	}
+}
+
+// setOutputVolume restores the volume remembered for the output to, if enabled.
+func (m *Model) setOutputVolume(p *player.Player, from, to string) {
+	m.delegate.playingMtx.Lock()
+	defer m.delegate.playingMtx.Unlock()
+	if !m.cfg.SwitchOutputVolume(from, to) {
+		return
+	}
+	if _, err := p.SetVolume(m.cfg.GetVolume()); err != nil {
+		slog.With("method", "ui.Model.setOutputVolume").Error("", "output", to, "error", err)
+	}
+}
+
+func (m *Model) updateOutputs(msg tea.KeyMsg) tea.Cmd {
+	v := m.outputs
+	n := len(v.sinks) + len(v.devices) + 1
+	switch {
+	case key.Matches(msg, v.keymap.up):
+		v.idx = (v.idx + n - 1) % n
+	case key.Matches(msg, v.keymap.down):
+		v.idx = (v.idx + 1) % n
+	case key.Matches(msg, v.keymap.selectOutput):
+		v.enabled = false
+		switch {
+		case v.idx == 0 && m.player.Output() == nil:
+			if v.sink == "" {
+				return nil
+			}
+			return m.defaultSinkCmd()
+		case v.idx == 0:
+			return m.selectOutputCmd(nil)
+		case v.idx <= len(v.sinks):
+			return m.selectSinkCmd(v.sinks[v.idx-1])
+		}
+		d := v.devices[v.idx-1-len(v.sinks)]
+		return m.selectOutputCmd(&d)
+	case key.Matches(msg, v.keymap.search):
+		return m.discoverOutputsCmd()
+	case key.Matches(msg, v.keymap.cancel, m.delegate.keymap.outputs):
+		v.enabled = false
+	}
+	return nil
+}
+
+type outputsKeymap struct {
+	up           key.Binding
+	down         key.Binding
+	selectOutput key.Binding
+	search       key.Binding
+	cancel       key.Binding
+}
+
+func newOutputsKeymap() outputsKeymap {
+	return outputsKeymap{
+		up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		selectOutput: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "play here"),
+		),
+		search: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "search again"),
+		),
+		cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back"),
+		),
+	}
+}
+
+func (k *outputsKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.up, k.down, k.selectOutput, k.search, k.cancel}
+}
+
+func (k *outputsKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
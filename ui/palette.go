@@ -0,0 +1,287 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/ui/styles"
+	"github.com/sahilm/fuzzy"
+)
+
+const (
+	palettePrompt      = "Action"
+	palettePlaceholder = "type to search the actions"
+	paletteNoMatch     = "No matching action"
+	paletteThemeFmt    = "theme %s"
+)
+
+// paletteCommand is an action of the palette, run by its key, or by run for the ones without a key.
+type paletteCommand struct {
+	name string
+	key  string
+	run  func(m *Model) tea.Cmd
+}
+
+// keyTypes maps the names of the keys to their type, to replay the key of a binding.
+var keyTypes = func() map[string]tea.KeyType {
+	res := make(map[string]tea.KeyType)
+	for t := tea.KeyType(-128); t < 128; t++ {
+		if name := t.String(); name != "" {
+			res[name] = t
+		}
+	}
+	return res
+}()
+
+// keyMsg is the key press matching the key of a binding, e.g. "ctrl+r", "alt+up" or "J".
+func keyMsg(k string) tea.KeyMsg {
+	var alt bool
+	if rest, ok := strings.CutPrefix(k, "alt+"); ok && rest != "" {
+		alt, k = true, rest
+	}
+	if t, ok := keyTypes[k]; ok {
+		msg := tea.KeyMsg{Type: t, Alt: alt}
+		if t == tea.KeySpace {
+			msg.Runes = []rune(k)
+		}
+		return msg
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k), Alt: alt}
+}
+
+// bindingCommands are the actions of the enabled key bindings, once per action.
+func bindingCommands(groups ...[][]key.Binding) []paletteCommand {
+	var res []paletteCommand
+	seen := make(map[string]bool)
+	for _, g := range groups {
+		for _, bindings := range g {
+			for _, b := range bindings {
+				h := b.Help()
+				if !b.Enabled() || len(b.Keys()) == 0 || h.Desc == "" || seen[h.Desc] {
+					continue
+				}
+				seen[h.Desc] = true
+				msg := keyMsg(b.Keys()[0])
+				res = append(res, paletteCommand{
+					name: h.Desc,
+					key:  h.Key,
+					run: func(*Model) tea.Cmd {
+						return func() tea.Msg { return msg }
+					},
+				})
+			}
+		}
+	}
+	return res
+}
+
+// paletteView lists every action of the active tab, with fuzzy search, so that they are found without knowing the keys.
+type paletteView struct {
+	enabled bool
+	style   *styles.Style
+
+	input    textinput.Model
+	commands []paletteCommand
+	// matches are the indexes of the commands matching the input, the best first
+	matches []int
+	idx     int
+
+	keymap paletteKeymap
+	help   help.Model
+	width  int
+	height int
+}
+
+func newPaletteView(s *styles.Style) *paletteView {
+	h := help.New()
+	h.ShortSeparator = "   "
+	h.Styles = s.HelpStyles()
+	return &paletteView{
+		style:  s,
+		input:  s.NewInputModel(palettePrompt, palettePlaceholder, nil, nil, nil, nil),
+		keymap: newPaletteKeymap(),
+		help:   h,
+	}
+}
+
+func (v *paletteView) setSize(width, height int) {
+	h, vf := v.style.DocStyle.GetFrameSize()
+	v.width = width - h
+	v.height = height - vf
+	v.help.Width = v.width
+	v.input.Width = max(0, v.width-lipgloss.Width(v.input.Prompt)-1)
+}
+
+// filter keeps the commands matching the input, in the order of the fuzzy match score.
+func (v *paletteView) filter() {
+	v.idx = 0
+	v.matches = v.matches[:0]
+	query := strings.TrimSpace(v.input.Value())
+	if query == "" {
+		for i := range v.commands {
+			v.matches = append(v.matches, i)
+		}
+		return
+	}
+	names := make([]string, len(v.commands))
+	for i := range v.commands {
+		names[i] = v.commands[i].name
+	}
+	for _, match := range fuzzy.Find(query, names) {
+		v.matches = append(v.matches, match.Index)
+	}
+}
+
+func (v *paletteView) View() string {
+	var b strings.Builder
+	b.WriteString("\n" + v.input.View() + "\n\n")
+
+	help := v.style.HelpStyle.Render(v.help.View(&v.keymap))
+	rows := max(1, v.height-lipgloss.Height(b.String())-lipgloss.Height(help))
+	if len(v.matches) == 0 {
+		b.WriteString(v.style.ItalicStyle.Render(paletteNoMatch) + "\n")
+	}
+	start := max(0, v.idx-rows+1)
+	for i := start; i < len(v.matches) && i < start+rows; i++ {
+		c := v.commands[v.matches[i]]
+		itStyle := v.style.SecondaryColorStyle
+		if i == v.idx {
+			itStyle = v.style.HistorySelItemStyle
+		}
+		k := v.style.ItalicStyle.Render(c.key)
+		fill := max(0, v.width-lipgloss.Width(c.name)-lipgloss.Width(k)-2)
+		b.WriteString(itStyle.Render("  "+c.name+strings.Repeat(" ", fill)) + k + "\n")
+	}
+
+	for i := lipgloss.Height(b.String()); i < v.height-lipgloss.Height(help); i++ {
+		b.WriteString("\n")
+	}
+	return b.String() + help
+}
+
+// togglePalette shows or hides the command palette, with the actions available on the active tab.
+func (m *Model) togglePalette() tea.Cmd {
+	v := m.palette
+	v.enabled = !v.enabled
+	if !v.enabled {
+		v.input.Blur()
+		return nil
+	}
+	v.commands = slices.Concat(m.paletteCommands(), m.macroCommands(), m.themeCommands())
+	v.input.SetValue("")
+	v.filter()
+	v.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	return v.input.Focus()
+}
+
+// paletteCommands are the actions of the keys of the active tab, then the global ones.
+func (m *Model) paletteCommands() []paletteCommand {
+	var tabHelp [][]key.Binding
+	switch t := m.tabs[m.activeTabIdx].(type) {
+	case stationTab:
+		tabHelp = t.Stations().list.FullHelp()
+	case *historyTab:
+		tabHelp = t.list.FullHelp()
+	case *recordingsTab:
+		tabHelp = t.list.FullHelp()
+	case *settingsTab:
+		tabHelp = t.keymap.FullHelp()
+	}
+	var global [][]key.Binding
+	if m.activeTabIdx != settingsTabIx {
+		global = m.delegate.FullHelp()
+	}
+	return bindingCommands(tabHelp, global)
+}
+
+// themeCommands switch to each theme, which has no key of its own.
+func (m *Model) themeCommands() []paletteCommand {
+	res := make([]paletteCommand, len(styles.Themes))
+	for i := range styles.Themes {
+		res[i] = paletteCommand{
+			name: fmt.Sprintf(paletteThemeFmt, styles.Themes[i].Name),
+			run: func(m *Model) tea.Cmd {
+				m.changeTheme(i)
+				m.tabs[settingsTabIx].(*settingsTab).inputs[themesIdx].SetValue(i)
+				return nil
+			},
+		}
+	}
+	return res
+}
+
+func (m *Model) updatePalette(msg tea.KeyMsg) tea.Cmd {
+	v := m.palette
+	n := len(v.matches)
+	switch {
+	case key.Matches(msg, v.keymap.up):
+		if n > 0 {
+			v.idx = (v.idx + n - 1) % n
+		}
+		return nil
+	case key.Matches(msg, v.keymap.down):
+		if n > 0 {
+			v.idx = (v.idx + 1) % n
+		}
+		return nil
+	case key.Matches(msg, v.keymap.run):
+		if n == 0 {
+			return nil
+		}
+		c := v.commands[v.matches[v.idx]]
+		m.togglePalette()
+		return c.run(m)
+	case key.Matches(msg, v.keymap.cancel, m.delegate.keymap.palette):
+		return m.togglePalette()
+	}
+
+	prev := v.input.Value()
+	var cmd tea.Cmd
+	v.input, cmd = v.input.Update(msg)
+	if v.input.Value() != prev {
+		v.filter()
+	}
+	return cmd
+}
+
+type paletteKeymap struct {
+	up     key.Binding
+	down   key.Binding
+	run    key.Binding
+	cancel key.Binding
+}
+
+func newPaletteKeymap() paletteKeymap {
+	return paletteKeymap{
+		up: key.NewBinding(
+			key.WithKeys("up"),
+			key.WithHelp("↑", "up"),
+		),
+		down: key.NewBinding(
+			key.WithKeys("down"),
+			key.WithHelp("↓", "down"),
+		),
+		run: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "run"),
+		),
+		cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back"),
+		),
+	}
+}
+
+func (k *paletteKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.up, k.down, k.run, k.cancel}
+}
+
+func (k *paletteKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
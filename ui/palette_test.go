@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+func TestKeyMsg(t *testing.T) {
+	for _, k := range []string{"enter", " ", "shift+tab", "ctrl+r", "alt+up", "alt+k", "J", "+", "#"} {
+		if got := keyMsg(k).String(); got != k {
+			t.Errorf("keyMsg(%q) = %q", k, got)
+		}
+	}
+}
+
+func TestBindingCommands(t *testing.T) {
+	disabled := key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "disabled"))
+	disabled.SetEnabled(false)
+	cmds := bindingCommands(
+		[][]key.Binding{{
+			key.NewBinding(key.WithKeys("ctrl+r", "R"), key.WithHelp("ctrl+r", "schedule recording")),
+			disabled,
+			key.NewBinding(key.WithHelp("alt+1..9", "quick dial")),
+		}},
+		[][]key.Binding{{key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "schedule recording"))}},
+	)
+	if len(cmds) != 1 || cmds[0].name != "schedule recording" || cmds[0].key != "ctrl+r" {
+		t.Fatalf("commands %+v", cmds)
+	}
+	if msg := cmds[0].run(nil)(); msg.(interface{ String() string }).String() != "ctrl+r" {
+		t.Errorf("run %v", msg)
+	}
+}
+
+func TestPaletteFilter(t *testing.T) {
+	v := &paletteView{input: textinput.New(), commands: []paletteCommand{
+		{name: "go to next tab"},
+		{name: "schedule recording"},
+		{name: "toggle lyrics"},
+	}}
+	v.filter()
+	if len(v.matches) != 3 {
+		t.Fatalf("matches %v", v.matches)
+	}
+	v.input.SetValue("rec")
+	v.filter()
+	if len(v.matches) != 1 || v.matches[0] != 1 {
+		t.Errorf("matches %v", v.matches)
+	}
+	v.input.SetValue("zzz")
+	v.filter()
+	if len(v.matches) != 0 {
+		t.Errorf("matches %v", v.matches)
+	}
+}
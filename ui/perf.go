@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/perf"
+)
+
+const (
+	perfTitle   = "Timings · ctrl+d to hide"
+	perfNoneMsg = "Nothing timed yet"
+	perfRowFmt  = "%-12s %7s %8s %8s %8s %8s"
+)
+
+// perfView returns the timings of the API requests, the renders, the updates and the player round-trips,
+// shown over the top of the tab to diagnose a sluggish app.
+func (m Model) perfView(width int) string {
+	line := func(style lipgloss.Style, text string) string {
+		fill := max(0, width-lipgloss.Width(text))
+		return style.MaxWidth(width).Render(text + strings.Repeat(" ", fill))
+	}
+	rows := []string{
+		line(m.style.PrimaryColorStyle, perfTitle),
+		line(m.style.SecondaryColorStyle, fmt.Sprintf(perfRowFmt, "", "count", "last", "p50", "p95", "max")),
+	}
+	stats := perf.Stats()
+	if len(stats) == 0 {
+		rows = append(rows, line(m.style.ItalicStyle, perfNoneMsg))
+	}
+	for _, s := range stats {
+		text := fmt.Sprintf(perfRowFmt, s.Name, fmt.Sprint(s.Count),
+			perfDuration(s.Last), perfDuration(s.P50), perfDuration(s.P95), perfDuration(s.Max))
+		rows = append(rows, line(m.style.SecondaryColorStyle, text))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// perfDuration keeps two decimals of its unit, e.g. 1.23ms.
+func perfDuration(d time.Duration) string {
+	for _, unit := range []time.Duration{time.Second, time.Millisecond, time.Microsecond} {
+		if d >= unit {
+			return d.Round(unit / 100).String()
+		}
+	}
+	return d.String()
+}
+
+// overlayTop replaces the first lines of view with the lines of overlay.
+func overlayTop(view, overlay string) string {
+	lines := strings.Split(view, "\n")
+	over := strings.Split(overlay, "\n")
+	n := min(len(over), len(lines))
+	return strings.Join(append(over[:n:n], lines[n:]...), "\n")
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_perfDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{850 * time.Nanosecond, "850ns"},
+		{12345 * time.Nanosecond, "12.35µs"},
+		{1234567 * time.Nanosecond, "1.23ms"},
+		{2345 * time.Millisecond, "2.35s"},
+	}
+	for _, tt := range tests {
+		if got := perfDuration(tt.d); got != tt.want {
+			t.Errorf("perfDuration(%v)=%s, want %s", tt.d, got, tt.want)
+		}
+	}
+}
+
+func Test_overlayTop(t *testing.T) {
+	if got := overlayTop("a\nb\nc", "x\ny"); got != "x\ny\nc" {
+		t.Errorf("got %q", got)
+	}
+	if got := overlayTop("a", "x\ny"); got != "x" {
+		t.Errorf("got %q, want the overlay cut to the view", got)
+	}
+}
+
+func Test_e2ePerfOverlay(t *testing.T) {
+	d := newUIDriver(t, e2eStations(3, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 3 })
+
+	d.keys("ctrl+d")
+	if !strings.Contains(d.view, perfTitle) || !strings.Contains(d.view, "render") || !strings.Contains(d.view, "api") {
+		t.Fatalf("expected the timings in the view:\n%s", d.view)
+	}
+	d.keys("ctrl+d")
+	if strings.Contains(d.view, perfTitle) {
+		t.Errorf("expected the timings hidden:\n%s", d.view)
+	}
+}
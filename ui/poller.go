@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// metadataPoller asks the active player for metadata on the tick of the UI, one poll at a time.
+// A poll is skipped if the previous one is still in flight, so a slow or hung backend
+// can never pile up requests.
+type metadataPoller struct {
+	// interval is the time between the polls, 0 for none
+	interval time.Duration
+	jitter   time.Duration
+	running  atomic.Bool
+
+	poll func() tea.Msg
+	send func(tea.Msg)
+}
+
+// newMetadataPoller returns a poller polling every interval, or never with a 0 interval, the polls
+// being left to the manual refresh.
+func newMetadataPoller(interval, jitter time.Duration, poll func() tea.Msg, send func(tea.Msg)) *metadataPoller {
+	return &metadataPoller{
+		interval: interval,
+		jitter:   jitter,
+		poll:     poll,
+		send:     send,
+	}
+}
+
+// tick starts a new poll unless the previous one has not finished yet.
+// It returns false if the poll was skipped.
+func (p *metadataPoller) tick() bool {
+	if !p.running.CompareAndSwap(false, true) {
+		slog.Debug("metadataPoller: previous poll still running, skipping")
+		return false
+	}
+	go func() {
+		defer p.running.Store(false)
+		if msg := p.poll(); msg != nil {
+			p.send(msg)
+		}
+	}()
+	return true
+}
+
+// nextDelay returns the interval to the next poll, with its jitter, 0 without polls.
+func (p *metadataPoller) nextDelay() time.Duration {
+	if p.interval <= 0 || p.jitter <= 0 {
+		return p.interval
+	}
+	return p.interval + rand.N(p.jitter)
+}
+
+// pollInterval is the interval of the song title polls on the tick, while a station plays.
+func (m *Model) pollInterval() time.Duration {
+	if m.poller == nil || m.delegate.playingUuid() == "" {
+		return 0
+	}
+	return m.poller.nextDelay()
+}
+
+func (m *Model) pollTick(time.Time) tea.Cmd {
+	m.poller.tick()
+	return nil
+}
+
+// setMetadataPoll changes the milliseconds between the song title polls, see config.Value.MetadataPoll.
+// The new interval is taken on the next tick.
+func (m *Model) setMetadataPoll(ms int) {
+	m.cfg.MetadataPollMs = ms
+	if m.poller != nil {
+		m.poller.interval, _ = m.cfg.MetadataPoll()
+	}
+}
+
+// refreshMetadata polls the song title now, as the manual refresh.
+func (m *Model) refreshMetadata() tea.Cmd {
+	if m.delegate.playingUuid() == "" {
+		m.updateStatusWarn(noPlayingMsg)
+		return nil
+	}
+	return m.pollMetadata
+}
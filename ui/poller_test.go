@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/config"
+)
+
+func Test_metadataPoller_skipsWhileRunning(t *testing.T) {
+	release := make(chan struct{})
+	sent := make(chan tea.Msg, 2)
+	p := newMetadataPoller(time.Millisecond, 0,
+		func() tea.Msg {
+			<-release
+			return statusMsg("polled")
+		},
+		func(msg tea.Msg) { sent <- msg },
+	)
+
+	if !p.tick() {
+		t.Fatal("first tick should start a poll")
+	}
+	if p.tick() {
+		t.Error("second tick should be skipped while the first poll is running")
+	}
+	close(release)
+
+	select {
+	case msg := <-sent:
+		if msg != statusMsg("polled") {
+			t.Errorf("got msg=%v, want %v", msg, statusMsg("polled"))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("poll result was not sent")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !p.tick() {
+		if time.Now().After(deadline) {
+			t.Fatal("poller did not become idle after the poll finished")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func Test_metadataPoller_nextDelay(t *testing.T) {
+	p := newMetadataPoller(100*time.Millisecond, 10*time.Millisecond, nil, nil)
+	for i := 0; i < 100; i++ {
+		d := p.nextDelay()
+		if d < 100*time.Millisecond || d >= 110*time.Millisecond {
+			t.Fatalf("delay %v out of range", d)
+		}
+	}
+}
+
+func Test_metadataPoller_noInterval(t *testing.T) {
+	p := newMetadataPoller(0, 10*time.Millisecond, nil, nil)
+	if d := p.nextDelay(); d != 0 {
+		t.Errorf("delay %v without polls, want 0 to pause them on the tick", d)
+	}
+}
+
+func Test_e2eRefreshMetadata(t *testing.T) {
+	d := newUIDriver(t, e2eStations(1, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 1 })
+	d.m.setMetadataPoll(config.MetadataPollManual)
+
+	d.keys("U")
+	if d.m.statusMsg != noPlayingMsg {
+		t.Errorf("status %q, want the warning of nothing playing", d.m.statusMsg)
+	}
+
+	d.keys("enter")
+	d.waitFor("the stream to play", func() bool { return d.m.connecting == nil && d.m.delegate.currPlaying != nil })
+	d.player.SetTitle("Artist - Song")
+	d.keys("U")
+	d.waitFor("the song title", func() bool { return d.m.playingData().Song == "Artist - Song" })
+}
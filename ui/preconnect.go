@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+)
+
+// probedStream is the stream url of a station resolved while it was highlighted.
+type probedStream struct {
+	url string
+	at  time.Time
+}
+
+// updateHover waits for the cursor to rest on a station to preconnect to it, also done for the
+// gapless switching. It runs after every update, the timer restarting when the highlighted station changes.
+func (m *Model) updateHover() {
+	var uuid string
+	if t, ok := m.tabs[m.activeTabIdx].(stationTab); ok && (m.cfg.Preconnect || m.cfg.Gapless) && !m.cfg.LowBandwidth {
+		if s, ok := t.Stations().list.SelectedItem().(browser.Station); ok {
+			uuid = s.Stationuuid
+		}
+	}
+	if uuid == m.hoverUuid {
+		return
+	}
+	m.hoverUuid = uuid
+	if m.hoverTimer != nil {
+		m.hoverTimer.Stop()
+	}
+	if uuid == "" || m.Progr == nil {
+		return
+	}
+	if p, ok := m.probed[uuid]; ok && time.Since(p.at) < config.PreconnectTTL && !m.cfg.Gapless {
+		return
+	}
+	progr := m.Progr
+	m.hoverTimer = time.AfterFunc(config.PreconnectDelay, func() {
+		progr.Send(hoverMsg(uuid))
+	})
+}
+
+// preconnectCmd resolves the stream of the station the cursor rested on.
+func (m *Model) preconnectCmd(uuid string) tea.Cmd {
+	if uuid != m.hoverUuid {
+		return nil
+	}
+	t, ok := m.tabs[m.activeTabIdx].(stationTab)
+	if !ok {
+		return nil
+	}
+	s, ok := t.Stations().list.SelectedItem().(browser.Station)
+	if !ok || s.Stationuuid != uuid {
+		return nil
+	}
+	if p, ok := m.probed[uuid]; ok && time.Since(p.at) < config.PreconnectTTL {
+		return m.preloadCmd(uuid, p.url)
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), config.ApiReqTimeout)
+		defer cancel()
+		streamURL := s.URLResolved
+		if streamURL == "" {
+			streamURL = s.URL
+		}
+		res, err := browser.ProbeStream(ctx, streamURL)
+		if err != nil {
+			slog.With("method", "ui.Model.preconnectCmd").Info("probe", "id", uuid, "url", streamURL, "error", err)
+		}
+		return probeMsg{uuid: uuid, url: res, err: err}
+	}
+}
+
+// onProbe keeps the stream url resolved, and with the gapless switching preloads it on the standby player
+// if the cursor still rests on the station.
+func (m *Model) onProbe(msg probeMsg) tea.Cmd {
+	if msg.err != nil {
+		delete(m.probed, msg.uuid)
+		return nil
+	}
+	m.probed[msg.uuid] = probedStream{url: msg.url, at: time.Now()}
+	return m.preloadCmd(msg.uuid, msg.url)
+}
+
+// preloadCmd plays the stream muted on the standby player, for the gapless switching, if the cursor
+// still rests on the station.
+func (m *Model) preloadCmd(uuid, url string) tea.Cmd {
+	if !m.cfg.Gapless || uuid != m.hoverUuid {
+		return nil
+	}
+	p := m.player
+	return func() tea.Msg {
+		if err := p.Preload(url); err != nil {
+			slog.With("method", "ui.Model.preloadCmd").Error("preload", "id", uuid, "error", err)
+		}
+		return nil
+	}
+}
+
+// probedStation returns the station with the stream url resolved by the preconnect, if it's recent.
+func (m *Model) probedStation(s browser.Station) browser.Station {
+	p, ok := m.probed[s.Stationuuid]
+	if !ok || time.Since(p.at) >= config.PreconnectTTL || m.cfg.LowBandwidth {
+		return s
+	}
+	s.URL = p.url
+	return s
+}
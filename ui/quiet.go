@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const quietVolumeMsg = "Quiet hours: volume capped at %d%%"
+
+// quietHours follows the quiet hours of the config, to cap the volume while they last.
+type quietHours struct {
+	active bool
+	// restore is the volume before it was capped to capped, restored when they end unless changed since
+	restore int
+	capped  int
+}
+
+// checkQuietHours caps the volume during the quiet hours, also when raised by a client or an output
+// switch, and restores it when they end.
+func (m *Model) checkQuietHours(now time.Time) tea.Cmd {
+	q := &m.quiet
+	quiet := m.cfg.Quiet(now)
+	if quiet != q.active {
+		slog.With("method", "ui.Model.checkQuietHours").Info("quiet hours", "active", quiet)
+	}
+	q.active = quiet
+	vol, maxVol := m.cfg.GetVolume(), m.cfg.QuietMaxVolume()
+	if !quiet {
+		restore, capped := q.restore, q.capped
+		q.restore, q.capped = 0, 0
+		if restore == 0 || vol != capped {
+			return nil
+		}
+		return m.setVolumeCmd(restore)
+	}
+	if vol <= maxVol {
+		return nil
+	}
+	if q.restore == 0 {
+		q.restore = vol
+	}
+	q.capped = maxVol
+	m.updateStatus(fmt.Sprintf(quietVolumeMsg, maxVol))
+	return m.setVolumeCmd(maxVol)
+}
+
+// maxVolume returns the highest volume allowed, by the cap of the settings and during the quiet hours.
+func (m *Model) maxVolume() int {
+	if m.quiet.active {
+		return min(m.cfg.QuietMaxVolume(), m.cfg.VolumeCap())
+	}
+	return m.cfg.VolumeCap()
+}
+
+// quietView tells in the status bar that the quiet hours are on.
+func (m *Model) quietView() string {
+	if !m.quiet.active {
+		return ""
+	}
+	return "quiet hours · "
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+func Test_e2eQuietHours(t *testing.T) {
+	d := newUIDriver(t, e2eStations(1, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 1 })
+	d.m.cfg.SetVolume(80)
+	// the quiet hours are checked on the tick
+	now := time.Now()
+	d.m.cfg.QuietHours = &config.QuietHours{From: now.Add(-time.Hour).Format("15:04"), To: now.Add(time.Hour).Format("15:04"), MaxVolume: 40}
+
+	d.waitFor("the volume capped", func() bool { return d.m.cfg.GetVolume() == 40 && d.player.Volume() == 40 })
+	d.keys("+")
+	d.waitFor("the volume key", func() bool { return d.player.Volume() == 40 })
+	if d.m.cfg.GetVolume() != 40 {
+		t.Fatalf("got volume %d, want raised up to the cap", d.m.cfg.GetVolume())
+	}
+
+	// a reminder due is held
+	r, _ := config.ParseReminder("daily " + now.Format("15:04"))
+	d.keys("f")
+	d.m.cfg.SetReminder("jazz-0", &r)
+	d.m.checkReminders(now.Add(-time.Minute))
+	d.m.checkReminders(now.Add(time.Minute))
+	if d.m.reminders.stationUuid != "" || d.m.delegate.keymap.tuneIn.Enabled() {
+		t.Errorf("got reminder %+v, want held during the quiet hours", d.m.reminders)
+	}
+
+	d.m.cfg.QuietHours = nil
+	d.waitFor("the volume restored", func() bool { return d.m.cfg.GetVolume() == 80 && d.player.Volume() == 80 })
+	d.keys("+")
+	d.waitFor("the volume key", func() bool { return d.player.Volume() == 85 })
+}
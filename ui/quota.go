@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/artwork"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/diskquota"
+	"github.com/dancnb/sonicradio/metadata"
+)
+
+// cacheEntries are the files and subdirs of the cache dir under its size limit, the others being the logs,
+// the crash reports and the sync data, which are not cached copies.
+var cacheEntries = []string{
+	artwork.CacheSubDir,
+	metadata.LyricsCacheSubDir,
+	metadata.MusicBrainzCacheFilename,
+	browser.HttpCacheFilename,
+	browser.IndexFilename,
+}
+
+// enforceCacheQuota removes the oldest cached files while the cacheEntries are over the cache size limit.
+func enforceCacheQuota(cfg *config.Value) {
+	if cfg.CacheLimitMB <= 0 {
+		return
+	}
+	log := slog.With("method", "ui.enforceCacheQuota")
+	dir, err := config.GetOrCreateCacheDir()
+	if err != nil {
+		log.Error("", "error", err)
+		return
+	}
+	removed, freed, err := diskquota.EnforceEntries(dir, cacheEntries, int64(cfg.CacheLimitMB)*diskquota.MB, time.Now())
+	if err != nil {
+		log.Error("", "error", err)
+	}
+	if len(removed) > 0 {
+		log.Info("removed cached files", "files", len(removed), "freed", diskquota.FormatSize(freed))
+	}
+}
+
+// enforceQuotas runs in the background, as walking the dirs can be slow on an SD card.
+func (m *Model) enforceQuotas() {
+	go func() {
+		enforceCacheQuota(m.cfg)
+		m.scheduler.EnforceQuota()
+	}()
+}
+
+func diskUsageCmd(cfg *config.Value) tea.Cmd {
+	return func() tea.Msg {
+		log := slog.With("method", "ui.diskUsageCmd")
+		var msg diskUsageMsg
+		if dir, err := config.GetOrCreateCacheDir(); err == nil {
+			if msg.cache, err = diskquota.UsageEntries(dir, cacheEntries); err != nil {
+				log.Error("cache usage", "error", err)
+			}
+		}
+		if dir, err := cfg.GetRecordingsDir(); err == nil {
+			if msg.recordings, err = diskquota.Usage(dir); err != nil {
+				log.Error("recordings usage", "error", err)
+			}
+		}
+		return msg
+	}
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dancnb/sonicradio/artwork"
+	"github.com/dancnb/sonicradio/config"
+)
+
+func Test_enforceCacheQuota(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir, err := config.GetOrCreateCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	cached := filepath.Join(dir, artwork.CacheSubDir, "cover.jpg")
+	kept := []string{
+		filepath.Join(dir, config.GitSyncSubDir, ".git", "HEAD"),
+		filepath.Join(dir, logsSubDir, "log.txt"),
+	}
+	for _, fp := range append(kept, cached) {
+		if err := os.MkdirAll(filepath.Dir(fp), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fp, make([]byte, 2<<20), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(fp, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	enforceCacheQuota(&config.Value{CacheLimitMB: 1})
+
+	if _, err := os.Stat(cached); !os.IsNotExist(err) {
+		t.Error("the cached artwork was not removed")
+	}
+	for _, fp := range kept {
+		if _, err := os.Stat(fp); err != nil {
+			t.Error(err)
+		}
+	}
+}
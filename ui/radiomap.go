@@ -0,0 +1,380 @@
+package ui
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/ui/styles"
+)
+
+const (
+	mapTitleFmt      = "Radio map · %d stations in %d countries"
+	mapLoadingMsg    = "Loading the countries..."
+	mapStationsLimit = 100
+	mapCols          = 3
+	mapBarWidth      = 20
+	otherRegion      = "Other"
+
+	noLocationMsg = "Set your location in the config file to find the stations near you"
+	nearbyMsg     = "Stations within %d km, the nearest first"
+)
+
+// mapRegions are the regions of the map in the order of the grid, roughly where they are on a world
+// map, with the ISO 3166-1 codes of their countries.
+var mapRegions = []struct {
+	name  string
+	codes string
+}{
+	{"North America", "US CA MX GT BZ SV HN NI CR PA CU JM HT DO PR BS BB TT AG DM GD KN LC VC AW CW BQ SX KY BM TC VG VI AI MS GP MQ BL MF PM GL"},
+	{"Europe", "AL AD AT BY BE BA BG HR CY CZ DK EE FO FI FR DE GI GR GG HU IS IE IM IT JE XK LV LI LT LU MT MD MC ME NL MK NO PL PT RO RU SM RS SK SI ES SJ SE CH UA GB VA AX"},
+	{"Asia", "AF AM AZ BH BD BT BN KH CN GE HK IN ID IR IQ IL JP JO KZ KW KG LA LB MO MY MV MN MM NP KP OM PK PS PH QA SA SG KR LK SY TW TJ TH TL TR TM AE UZ VN YE"},
+	{"South America", "AR BO BR CL CO EC GY PY PE SR UY VE GF FK"},
+	{"Africa", "DZ AO BJ BW BF BI CV CM CF TD KM CG CD CI DJ EG GQ ER SZ ET GA GM GH GN GW KE LS LR LY MG MW ML MR MU YT MA MZ NA NE NG RE RW SH ST SN SC SL SO ZA SS SD TZ TG TN UG EH ZM ZW"},
+	{"Oceania", "AS AU CK FJ PF GU KI MH FM NR NC NZ NU NF MP PW PG PN WS SB TK TO TV UM VU WF"},
+}
+
+type mapRegion struct {
+	name      string
+	countries []browser.Country // by station count
+	stations  int
+}
+
+// groupRegions groups the countries by region, the countries of no region in a last one.
+func groupRegions(countries []browser.Country) []mapRegion {
+	res := make([]mapRegion, len(mapRegions))
+	regionIx := make(map[string]int)
+	for i, r := range mapRegions {
+		res[i].name = r.name
+		for _, code := range strings.Fields(r.codes) {
+			regionIx[code] = i
+		}
+	}
+	other := mapRegion{name: otherRegion}
+	for _, c := range countries {
+		if c.Stationcount == 0 {
+			continue
+		}
+		r := &other
+		if ix, ok := regionIx[strings.ToUpper(c.ISO3166_1)]; ok {
+			r = &res[ix]
+		}
+		r.countries = append(r.countries, c)
+		r.stations += c.Stationcount
+	}
+	if len(other.countries) > 0 {
+		res = append(res, other)
+	}
+	for i := range res {
+		slices.SortStableFunc(res[i].countries, func(a, b browser.Country) int {
+			return cmp.Compare(b.Stationcount, a.Stationcount)
+		})
+	}
+	return res
+}
+
+// mapView shows the regions of the world as a grid with their station counts, and the countries of the
+// selected one, to browse the stations by geography.
+type mapView struct {
+	enabled bool
+	style   *styles.Style
+
+	regions   []mapRegion
+	loading   bool
+	regionIdx int
+	// the countries of the region are selected, not the regions
+	inCountries bool
+	countryIdx  int
+
+	keymap mapKeymap
+	help   help.Model
+	width  int
+	height int
+}
+
+func newMapView(s *styles.Style) *mapView {
+	h := help.New()
+	h.ShortSeparator = "   "
+	h.Styles = s.HelpStyles()
+	return &mapView{
+		style:  s,
+		keymap: newMapKeymap(),
+		help:   h,
+	}
+}
+
+func (v *mapView) setSize(width, height int) {
+	h, vf := v.style.DocStyle.GetFrameSize()
+	v.width = width - h
+	v.height = height - vf
+	v.help.Width = v.width
+}
+
+func (v *mapView) View() string {
+	var b strings.Builder
+	stations, countries := 0, 0
+	for _, r := range v.regions {
+		stations += r.stations
+		countries += len(r.countries)
+	}
+	b.WriteString("\n" + v.style.PrimaryColorStyle.Render(fmt.Sprintf(mapTitleFmt, stations, countries)) + "\n\n")
+	if len(v.regions) == 0 {
+		if v.loading {
+			b.WriteString(v.style.ItalicStyle.Render(mapLoadingMsg) + "\n")
+		}
+	} else {
+		b.WriteString(v.gridView() + "\n")
+		b.WriteString(v.countriesView(v.height - lipgloss.Height(b.String()) - lipgloss.Height(v.helpView())))
+	}
+
+	help := v.helpView()
+	for i := lipgloss.Height(b.String()); i < v.height-lipgloss.Height(help); i++ {
+		b.WriteString("\n")
+	}
+	return b.String() + help
+}
+
+func (v *mapView) helpView() string {
+	return v.style.HelpStyle.Render(v.help.View(&v.keymap))
+}
+
+func (v *mapView) gridView() string {
+	boxWidth := max(16, v.width/mapCols-2)
+	var rows []string
+	for first := 0; first < len(v.regions); first += mapCols {
+		var cells []string
+		for i := first; i < min(first+mapCols, len(v.regions)); i++ {
+			r := v.regions[i]
+			border := v.style.SecondaryColorStyle.Border(styles.BoxBorder()).
+				BorderForeground(v.style.SecondaryColorStyle.GetForeground())
+			name := v.style.SecondaryColorStyle.Bold(true).Render(r.name)
+			if i == v.regionIdx {
+				border = border.BorderForeground(v.style.PrimaryColorStyle.GetForeground())
+				name = v.style.PrimaryColorStyle.Bold(true).Render(styles.SelectedChar + r.name)
+			}
+			desc := fmt.Sprintf("%d stations · %d countries", r.stations, len(r.countries))
+			cells = append(cells, border.Width(boxWidth).Render(name+"\n"+desc))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func (v *mapView) countriesView(rows int) string {
+	r := v.regions[v.regionIdx]
+	if len(r.countries) == 0 || rows <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	first := 0
+	if v.inCountries {
+		first = max(0, v.countryIdx-rows+1)
+	}
+	top := r.countries[0].Stationcount
+	nameWidth := 0
+	for _, c := range r.countries {
+		nameWidth = max(nameWidth, lipgloss.Width(c.Name))
+	}
+	for i := first; i < len(r.countries) && i < first+rows; i++ {
+		c := r.countries[i]
+		bar := strings.Repeat(styles.BarChar, max(1, c.Stationcount*mapBarWidth/max(1, top)))
+		line := fmt.Sprintf(" %-*s  %5d  ", nameWidth, c.Name, c.Stationcount)
+		if v.inCountries && i == v.countryIdx {
+			line = v.style.HistorySelItemStyle.Render(line)
+		} else {
+			line = v.style.SecondaryColorStyle.Render(line)
+		}
+		b.WriteString(lipgloss.NewStyle().MaxWidth(v.width).Render(line+v.style.PrimaryColorStyle.Render(bar)) + "\n")
+	}
+	return b.String()
+}
+
+// move moves the selection on the grid of the regions by rows and cols.
+func (v *mapView) move(rows, cols int) {
+	ix := v.regionIdx + rows*mapCols + cols
+	if cols != 0 && ix/mapCols != v.regionIdx/mapCols {
+		return
+	}
+	if ix >= 0 && ix < len(v.regions) {
+		v.regionIdx = ix
+		v.countryIdx = 0
+	}
+}
+
+func (v *mapView) selectedCountry() (browser.Country, bool) {
+	if len(v.regions) == 0 || !v.inCountries {
+		return browser.Country{}, false
+	}
+	countries := v.regions[v.regionIdx].countries
+	if v.countryIdx >= len(countries) {
+		return browser.Country{}, false
+	}
+	return countries[v.countryIdx], true
+}
+
+// toggleMap shows or hides the map, loading the countries the first time.
+func (m *Model) toggleMap() tea.Cmd {
+	v := m.mapView
+	if v.enabled {
+		v.enabled = false
+		return nil
+	}
+	v.enabled = true
+	v.inCountries = false
+	v.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	if len(v.regions) > 0 || v.loading {
+		return nil
+	}
+	v.loading = true
+	return func() tea.Msg {
+		countries, err := m.browser.GetCountries()
+		return mapCountriesMsg{countries: countries, err: err}
+	}
+}
+
+func (m *Model) onMapCountries(msg mapCountriesMsg) {
+	v := m.mapView
+	v.loading = false
+	if msg.err != nil {
+		m.updateStatusError(msg.err.Error())
+		return
+	}
+	v.regions = groupRegions(msg.countries)
+	v.regionIdx = min(v.regionIdx, max(0, len(v.regions)-1))
+}
+
+// mapStationsCmd lists the stations of the country in the browse tab.
+func (m *Model) mapStationsCmd(c browser.Country) tea.Cmd {
+	m.mapView.enabled = false
+	m.toBrowseTab()
+	params := browser.DefaultSearchParams()
+	params.CountryCode = c.ISO3166_1
+	params.Limit = mapStationsLimit
+	return func() tea.Msg {
+		stations, err := m.browser.Search(params)
+		res := searchRespMsg{stations: stations, params: &params}
+		if err != nil {
+			res.errorMsg = errorMsg(err.Error())
+		} else if len(stations) == 0 {
+			res.viewMsg = noStationsFound
+		}
+		return res
+	}
+}
+
+// nearbyCmd lists the stations near the location of the user in the browse tab.
+func (m *Model) nearbyCmd() tea.Cmd {
+	loc := m.cfg.Location
+	if loc == nil {
+		m.updateStatusWarn(noLocationMsg)
+		return nil
+	}
+	m.mapView.enabled = false
+	m.toBrowseTab()
+	m.updateStatus(fmt.Sprintf(nearbyMsg, loc.Radius()))
+	return func() tea.Msg {
+		stations, err := m.browser.Nearby(context.Background(), loc.Lat, loc.Long, loc.Radius(), mapStationsLimit)
+		res := searchRespMsg{stations: stations}
+		if err != nil {
+			res.errorMsg = errorMsg(err.Error())
+		} else if len(stations) == 0 {
+			res.viewMsg = noStationsFound
+		}
+		return res
+	}
+}
+
+func (m *Model) updateMap(msg tea.Msg) tea.Cmd {
+	v := m.mapView
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || len(v.regions) == 0 && !key.Matches(keyMsg, v.keymap.cancel, m.delegate.keymap.radioMap) {
+		return nil
+	}
+	if v.inCountries {
+		countries := v.regions[v.regionIdx].countries
+		switch {
+		case key.Matches(keyMsg, v.keymap.up):
+			v.countryIdx = max(0, v.countryIdx-1)
+		case key.Matches(keyMsg, v.keymap.down):
+			v.countryIdx = min(len(countries)-1, v.countryIdx+1)
+		case key.Matches(keyMsg, v.keymap.enter):
+			if c, ok := v.selectedCountry(); ok {
+				return m.mapStationsCmd(c)
+			}
+		case key.Matches(keyMsg, v.keymap.cancel):
+			v.inCountries = false
+		case key.Matches(keyMsg, m.delegate.keymap.radioMap):
+			v.enabled = false
+		}
+		return nil
+	}
+	switch {
+	case key.Matches(keyMsg, v.keymap.up):
+		v.move(-1, 0)
+	case key.Matches(keyMsg, v.keymap.down):
+		v.move(1, 0)
+	case key.Matches(keyMsg, v.keymap.left):
+		v.move(0, -1)
+	case key.Matches(keyMsg, v.keymap.right):
+		v.move(0, 1)
+	case key.Matches(keyMsg, v.keymap.enter) && len(v.regions[v.regionIdx].countries) > 0:
+		v.inCountries = true
+		v.countryIdx = 0
+	case key.Matches(keyMsg, v.keymap.cancel, m.delegate.keymap.radioMap):
+		v.enabled = false
+	}
+	return nil
+}
+
+type mapKeymap struct {
+	up     key.Binding
+	down   key.Binding
+	left   key.Binding
+	right  key.Binding
+	enter  key.Binding
+	cancel key.Binding
+}
+
+func newMapKeymap() mapKeymap {
+	return mapKeymap{
+		up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		left: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "left"),
+		),
+		right: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "right"),
+		),
+		enter: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "countries/stations"),
+		),
+		cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back"),
+		),
+	}
+}
+
+func (k *mapKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.up, k.down, k.left, k.right, k.enter, k.cancel}
+}
+
+func (k *mapKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
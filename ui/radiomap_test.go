@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/dancnb/sonicradio/browser"
+)
+
+func TestGroupRegions(t *testing.T) {
+	regions := groupRegions([]browser.Country{
+		{Name: "France", ISO3166_1: "FR", Stationcount: 10},
+		{Name: "Germany", ISO3166_1: "DE", Stationcount: 20},
+		{Name: "Japan", ISO3166_1: "jp", Stationcount: 5},
+		{Name: "Nowhere", ISO3166_1: "", Stationcount: 1},
+		{Name: "Empty", ISO3166_1: "IT", Stationcount: 0},
+	})
+	if len(regions) != len(mapRegions)+1 || regions[len(regions)-1].name != otherRegion {
+		t.Fatalf("regions %v", regions)
+	}
+	europe := regions[1]
+	if europe.stations != 30 || len(europe.countries) != 2 || europe.countries[0].Name != "Germany" {
+		t.Errorf("europe %+v", europe)
+	}
+	if asia := regions[2]; asia.stations != 5 {
+		t.Errorf("asia %+v", asia)
+	}
+}
+
+func TestMapMove(t *testing.T) {
+	v := &mapView{regions: make([]mapRegion, 7)}
+	v.move(0, -1)
+	if v.regionIdx != 0 {
+		t.Errorf("moved left of the grid to %d", v.regionIdx)
+	}
+	v.move(0, 1)
+	v.move(0, 1)
+	v.move(0, 1)
+	if v.regionIdx != 2 {
+		t.Errorf("moved right of the grid to %d", v.regionIdx)
+	}
+	v.move(1, 0)
+	if v.regionIdx != 5 {
+		t.Errorf("moved down to %d", v.regionIdx)
+	}
+	v.move(1, 0)
+	if v.regionIdx != 5 {
+		t.Errorf("moved below the last region to %d", v.regionIdx)
+	}
+	v.regionIdx = 3
+	v.move(1, 0)
+	if v.regionIdx != 6 {
+		t.Errorf("moved down to the last row %d", v.regionIdx)
+	}
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/browser"
+
+	"github.com/dancnb/sonicradio/relay"
+)
+
+const (
+	relayStartedMsg = "Relaying the playback on %s"
+	relayErrMsg     = "Could not start the relay: %v"
+	roomPlayingMsg  = "Room host plays %s"
+	roomStoppedMsg  = "Room host stopped the playback"
+)
+
+// setRelay starts or stops serving the playing station to the other devices on the LAN.
+func (m *Model) setRelay(enabled bool) {
+	log := slog.With("method", "ui.Model.setRelay")
+	m.cfg.Relay = enabled
+	if !enabled {
+		if m.relay != nil {
+			if err := m.relay.Close(); err != nil {
+				log.Error("relay close", "error", err)
+			}
+			m.relay = nil
+		}
+		return
+	}
+	if m.relay != nil {
+		return
+	}
+	r, err := relay.Start(":" + strconv.Itoa(m.cfg.GetRelayPort()))
+	if err != nil {
+		log.Error("relay start", "error", err)
+		m.updateStatusError(fmt.Sprintf(relayErrMsg, err))
+		return
+	}
+	m.relay = r
+	log.Info("relay started", "url", r.URL())
+	m.updateStatus(fmt.Sprintf(relayStartedMsg, r.URL()))
+}
+
+// updateRelay relays the playing station. Like updateMpris, it runs after every update.
+func (m *Model) updateRelay() {
+	if m.relay == nil || !m.delegate.playingMtx.TryRLock() {
+		return
+	}
+	var st relay.Station
+	if s := m.delegate.currPlaying; s != nil {
+		st = relay.Station{Uuid: s.Stationuuid, Name: m.stationName(*s), URL: s.URLResolved}
+		if st.URL == "" {
+			st.URL = s.URL
+		}
+	}
+	m.delegate.playingMtx.RUnlock()
+	m.relay.SetStation(st)
+}
+
+// joinRoom follows the station changes of the relay at host, as a listening room guest.
+func (m *Model) joinRoom(ctx context.Context, host string, progr *tea.Program) {
+	slog.With("method", "ui.Model.joinRoom").Info("joining", "host", host)
+	go relay.Join(ctx, host, func(s relay.Station) {
+		progr.Send(roomStationMsg(s))
+	})
+}
+
+func (m *Model) roomStationCmd(s relay.Station) tea.Cmd {
+	if s.URL == "" {
+		return m.delegate.stopCmd(roomStoppedMsg)
+	}
+	m.delegate.playingMtx.RLock()
+	curr := m.delegate.currPlaying
+	m.delegate.playingMtx.RUnlock()
+	if curr != nil && curr.Stationuuid == s.Uuid && s.Uuid != "" {
+		return nil
+	}
+	station := browser.Station{Stationuuid: s.Uuid, Name: s.Name, URL: s.URL}
+	return m.sequence(m.playStationCmd(station), func() tea.Msg {
+		return statusMsg(fmt.Sprintf(roomPlayingMsg, s.Name))
+	})
+}
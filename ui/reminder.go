@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/notify"
+)
+
+const (
+	reminderMsg        = "Time to tune into %s"
+	reminderKeyMsg     = ", press t to tune in"
+	reminderAction     = "Tune in"
+	reminderMissingMsg = "The reminded station is no longer a favorite"
+)
+
+// reminders tracks the last reminder due, which the tune in key plays until it expires.
+type reminders struct {
+	// checked is the time of the last check for the reminders due
+	checked     time.Time
+	stationUuid string
+	until       time.Time
+	notice      string
+}
+
+// checkReminders notifies the reminders of the favorites due since the last check, outside the quiet hours.
+func (m *Model) checkReminders(now time.Time) tea.Cmd {
+	r := &m.reminders
+	from := r.checked
+	r.checked = now
+	if r.stationUuid != "" && now.After(r.until) {
+		m.clearReminder()
+	}
+	if from.IsZero() {
+		return nil
+	}
+	for _, uuid := range m.cfg.DueReminders(from, now) {
+		if m.cfg.Quiet(now) {
+			slog.With("method", "ui.Model.checkReminders").Info("held by the quiet hours", "station", uuid)
+			continue
+		}
+		m.remind(uuid, now)
+	}
+	return nil
+}
+
+// remind shows the notice of the reminder and the desktop notification, whose action tunes into the
+// favorite like the tune in key.
+func (m *Model) remind(uuid string, now time.Time) {
+	log := slog.With("method", "ui.Model.remind")
+	rem, _ := m.cfg.Reminder(uuid)
+	notice := fmt.Sprintf(reminderMsg, m.cfg.StationName(uuid, rem.StationName))
+	log.Info(notice)
+	m.reminders.stationUuid = uuid
+	m.reminders.until = now.Add(config.ReminderTuneInFor)
+	m.reminders.notice = notice + reminderKeyMsg
+	m.delegate.keymap.tuneIn.SetEnabled(true)
+	m.announcer.announce(notice)
+	if m.Progr == nil {
+		return
+	}
+	progr := m.Progr
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), config.ReminderTuneInFor)
+		defer cancel()
+		clicked, err := notify.SendAction(ctx, notice, reminderAction)
+		if err != nil {
+			log.Info("notification", "error", err)
+		}
+		if clicked {
+			progr.Send(tuneInMsg{stationUuid: uuid})
+		}
+	}()
+}
+
+// tuneIn plays the favorite reminded.
+func (m *Model) tuneIn(uuid string) tea.Cmd {
+	m.clearReminder()
+	if uuid == "" {
+		return nil
+	}
+	for _, s := range m.favoriteStations() {
+		if s.Stationuuid == uuid {
+			return m.playStationCmd(s)
+		}
+	}
+	m.updateStatusWarn(reminderMissingMsg)
+	return nil
+}
+
+func (m *Model) clearReminder() {
+	m.reminders = reminders{checked: m.reminders.checked}
+	m.delegate.keymap.tuneIn.SetEnabled(false)
+}
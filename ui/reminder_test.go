@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_e2eReminder(t *testing.T) {
+	d := newUIDriver(t, e2eStations(2, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 2 })
+
+	d.keys("j", "f", "r", "tab", "tab")
+	d.typeText("someday 18:00")
+	d.keys("enter")
+	if !d.m.favoriteForm.enabled || !strings.Contains(d.view, "Invalid reminder") {
+		t.Fatalf("expected the form kept open with the invalid reminder:\n%s", d.view)
+	}
+	d.m.favoriteForm.inputs[favoriteReminderIdx].SetValue("daily 18:00")
+	d.keys("enter")
+	r, ok := d.m.cfg.Reminder("jazz-1")
+	if d.m.favoriteForm.enabled || !ok || r.Spec() != "daily 18:00" || r.URL != "http://stream.example.com/jazz/1" {
+		t.Fatalf("got reminder %+v, want saved with the station", r)
+	}
+	d.waitFor("the favorites loaded", func() bool { return len(d.m.favoriteStations()) == 1 })
+
+	if d.m.delegate.keymap.tuneIn.Enabled() {
+		t.Fatal("expected the tune in key disabled without a reminder due")
+	}
+	from := time.Date(2026, 10, 16, 17, 59, 40, 0, time.Local)
+	d.m.checkReminders(from)
+	d.m.checkReminders(from.Add(30 * time.Second))
+	if !d.m.delegate.keymap.tuneIn.Enabled() || d.m.reminders.notice != "Time to tune into Jazz 1, press t to tune in" {
+		t.Fatalf("got notice %q, want the reminder", d.m.reminders.notice)
+	}
+
+	d.keys("k", "t")
+	d.waitFor("the reminded station", func() bool {
+		url, _ := d.player.Playing()
+		return url == "http://stream.example.com/jazz/1"
+	})
+	if d.m.delegate.keymap.tuneIn.Enabled() {
+		t.Error("expected the tune in key disabled once tuned in")
+	}
+
+	// the reminder expires
+	d.m.checkReminders(from.AddDate(0, 0, 1).Add(30 * time.Second))
+	d.m.checkReminders(from.AddDate(0, 0, 1).Add(time.Hour + time.Minute))
+	if d.m.delegate.keymap.tuneIn.Enabled() {
+		t.Error("expected the tune in key disabled after the reminder expired")
+	}
+}
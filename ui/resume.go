@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/config"
+)
+
+const resumeReconnectMsg = "Reconnecting after the system resumed..."
+
+// suspendedFor returns how long the system was suspended between two ticks. The monotonic clock
+// stops during the suspend while the wall clock keeps going, so the difference between the two is
+// the time spent asleep.
+func suspendedFor(prev, now time.Time) time.Duration {
+	if prev.IsZero() {
+		return 0
+	}
+	return now.Round(0).Sub(prev.Round(0)) - now.Sub(prev)
+}
+
+// suspendGap is suspendedFor, replaced by the tests as the monotonic clock can't be stopped.
+var suspendGap = suspendedFor
+
+// checkResume reconnects the streams, which break across a suspend, after the system resumed.
+func (m *Model) checkResume(now time.Time) tea.Cmd {
+	gap := suspendGap(m.resumeTick, now)
+	m.resumeTick = now
+	if gap < config.ResumeMinGap || !m.isPlaying() {
+		return nil
+	}
+	slog.With("method", "ui.Model.checkResume").Info("system resumed", "suspended", gap)
+	m.updateStatus(resumeReconnectMsg)
+	return m.scheduleReconnect()
+}
+
+// isPlaying returns if a session is playing a station.
+func (m *Model) isPlaying() bool {
+	m.saveSession()
+	for _, s := range m.sessions.sessions {
+		if s.currPlaying != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleReconnect reconnects the streams after a delay, so the network is back up. A reconnection
+// scheduled again before the delay replaces the previous one.
+func (m *Model) scheduleReconnect() tea.Cmd {
+	m.reconnectSeq++
+	seq := m.reconnectSeq
+	return tea.Tick(config.ReconnectDelay, func(time.Time) tea.Msg { return reconnectMsg{seq: seq} })
+}
+
+// reconnectCmd plays again the stations of the sessions, refreshing the metadata of the active one.
+func (m *Model) reconnectCmd(msg reconnectMsg) tea.Cmd {
+	if msg.seq != m.reconnectSeq {
+		return nil
+	}
+	log := slog.With("method", "ui.Model.reconnectCmd")
+	m.saveSession()
+	var cmds []tea.Cmd
+	for i, s := range m.sessions.sessions {
+		if s.currPlaying == nil {
+			continue
+		}
+		if i == m.sessions.active {
+			cmds = append(cmds, m.playStationCmd(*s.currPlaying))
+			continue
+		}
+		p, station := s.player, *s.currPlaying
+		cmds = append(cmds, func() tea.Msg {
+			if err := p.Play(station.URL); err != nil {
+				log.Error("reconnect", "session", s.name, "error", err)
+			}
+			return nil
+		})
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dancnb/sonicradio/config"
+)
+
+func Test_suspendedFor(t *testing.T) {
+	prev := time.Now()
+	if got := suspendedFor(time.Time{}, prev); got != 0 {
+		t.Errorf("got %s at the start, want no suspend", got)
+	}
+	if got := suspendedFor(prev, prev.Add(2*time.Second)); got != 0 {
+		t.Errorf("got %s between two ticks, want no suspend", got)
+	}
+}
+
+func Test_e2eResume(t *testing.T) {
+	stations := e2eStations(2, "Jazz", "jazz")
+	d := newUIDriver(t, stations...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 2 })
+	var gap time.Duration
+	prev := suspendGap
+	suspendGap = func(time.Time, time.Time) time.Duration { return gap }
+	t.Cleanup(func() { suspendGap = prev })
+	now := time.Now()
+	tick := func() {
+		now = now.Add(config.ResumeCheckInterval)
+		d.send(tickMsg{seq: d.m.ticker.seq, t: now})
+	}
+
+	// nothing to reconnect at the start, or with nothing playing
+	gap = time.Hour
+	tick()
+	if d.m.statusMsg == resumeReconnectMsg || d.m.reconnectSeq != 0 {
+		t.Fatal("reconnected without a station playing")
+	}
+
+	d.keys("enter")
+	d.waitFor("the station to play", func() bool { return d.m.connecting == nil && d.m.delegate.playingUuid() != "" })
+	gap = 0
+	tick()
+	if d.m.statusMsg == resumeReconnectMsg || d.m.reconnectSeq != 0 {
+		t.Fatal("reconnected on a tick without a suspend")
+	}
+
+	// the station is deleted from the directory while suspended, its stream is played again
+	d.srv.SetStations(stations[1])
+	gap = time.Hour
+	tick()
+	if d.m.statusMsg != resumeReconnectMsg || d.m.reconnectSeq != 1 {
+		t.Fatalf("got the status %q, want the reconnection after the resume", d.m.statusMsg)
+	}
+	d.send(reconnectMsg{seq: d.m.reconnectSeq})
+	d.waitFor("the reconnection", func() bool { return len(d.player.Played()) == 2 && d.m.connecting == nil })
+	if played := d.player.Played(); played[1] != stations[0].URL {
+		t.Errorf("played %v, want the stream of the station again", played)
+	}
+	if d.m.delegate.playingUuid() != stations[0].Stationuuid {
+		t.Errorf("playing %q, want %q", d.m.delegate.playingUuid(), stations[0].Stationuuid)
+	}
+}
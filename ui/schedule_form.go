@@ -0,0 +1,211 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/ui/styles"
+)
+
+const (
+	scheduleStartFormat = "2006-01-02 15:04"
+	scheduleEndFormat   = "15:04"
+)
+
+var errScheduleRepeat = errors.New("repeat must be once, daily or weekly")
+
+type scheduleInputIdx byte
+
+const (
+	scheduleStartIdx scheduleInputIdx = iota
+	scheduleEndIdx
+	scheduleRepeatIdx
+)
+
+// scheduleForm edits the time window of a new recording schedule.
+type scheduleForm struct {
+	style   *styles.Style
+	station browser.Station
+	inputs  []textinput.Model
+	idx     scheduleInputIdx
+	err     error
+
+	keymap scheduleFormKeymap
+	help   help.Model
+}
+
+func newScheduleForm(s *styles.Style, station browser.Station, now time.Time) *scheduleForm {
+	start := now.Truncate(time.Hour).Add(time.Hour)
+	startInput := s.NewInputModel("Start         ", scheduleStartFormat, nil, nil, nil, nil)
+	startInput.SetValue(start.Format(scheduleStartFormat))
+	endInput := s.NewInputModel("End           ", scheduleEndFormat, nil, nil, nil, nil)
+	endInput.SetValue(start.Add(time.Hour).Format(scheduleEndFormat))
+	repeatInput := s.NewInputModel("Repeat        ", "once, daily or weekly", nil, nil, nil, nil)
+	repeatInput.SetValue(strings.ToLower(config.RepeatOnce.String()))
+	repeatInput.ShowSuggestions = true
+	suggestions := make([]string, len(config.Repeats))
+	for i, r := range config.Repeats {
+		suggestions[i] = strings.ToLower(r.String())
+	}
+	repeatInput.SetSuggestions(suggestions)
+
+	h := help.New()
+	h.ShortSeparator = "   "
+	h.Styles = s.HelpStyles()
+
+	return &scheduleForm{
+		style:   s,
+		station: station,
+		inputs:  []textinput.Model{startInput, endInput, repeatInput},
+		keymap:  newScheduleFormKeymap(),
+		help:    h,
+	}
+}
+
+func (f *scheduleForm) focus() tea.Cmd {
+	var cmd tea.Cmd
+	for i := range f.inputs {
+		if i == int(f.idx) {
+			cmd = f.inputs[i].Focus()
+			continue
+		}
+		f.inputs[i].Blur()
+	}
+	return cmd
+}
+
+// schedule parses the inputs; the end time is on the day after the start if it's earlier than the start.
+func (f *scheduleForm) schedule(now time.Time) (config.RecordingSchedule, error) {
+	start, err := time.ParseInLocation(scheduleStartFormat, strings.TrimSpace(f.inputs[scheduleStartIdx].Value()), time.Local)
+	if err != nil {
+		return config.RecordingSchedule{}, fmt.Errorf("invalid start, expected %s", scheduleStartFormat)
+	}
+	endClock, err := time.Parse(scheduleEndFormat, strings.TrimSpace(f.inputs[scheduleEndIdx].Value()))
+	if err != nil {
+		return config.RecordingSchedule{}, fmt.Errorf("invalid end, expected %s", scheduleEndFormat)
+	}
+	end := time.Date(start.Year(), start.Month(), start.Day(), endClock.Hour(), endClock.Minute(), 0, 0, time.Local)
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	repeat := -1
+	for i, r := range config.Repeats {
+		if strings.EqualFold(strings.TrimSpace(f.inputs[scheduleRepeatIdx].Value()), r.String()) {
+			repeat = i
+		}
+	}
+	if repeat < 0 {
+		return config.RecordingSchedule{}, errScheduleRepeat
+	}
+	s := config.RecordingSchedule{
+		ID:          fmt.Sprintf("%s-%d", f.station.Stationuuid, now.UnixMilli()),
+		StationUuid: f.station.Stationuuid,
+		StationName: f.station.Name,
+		URL:         f.station.URL,
+		Start:       start,
+		Duration:    end.Sub(start),
+		Repeat:      config.Repeats[repeat],
+	}
+	if _, ok := s.Next(now); !ok {
+		return config.RecordingSchedule{}, errors.New("the recording window has already ended")
+	}
+	return s, nil
+}
+
+// Update returns the schedule when it is submitted, or done if the form was cancelled.
+func (f *scheduleForm) Update(msg tea.Msg) (sch *config.RecordingSchedule, done bool, cmd tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, f.keymap.cancel):
+			return nil, true, nil
+		case key.Matches(msg, f.keymap.submit):
+			s, err := f.schedule(time.Now())
+			if err != nil {
+				f.err = err
+				return nil, false, nil
+			}
+			return &s, true, nil
+		case key.Matches(msg, f.keymap.next):
+			if f.idx == scheduleRepeatIdx {
+				if sugg := f.inputs[f.idx].CurrentSuggestion(); sugg != "" {
+					f.inputs[f.idx].SetValue(sugg)
+				}
+			}
+			f.idx = (f.idx + 1) % scheduleInputIdx(len(f.inputs))
+			return nil, false, f.focus()
+		case key.Matches(msg, f.keymap.prev):
+			f.idx = (f.idx + scheduleInputIdx(len(f.inputs)) - 1) % scheduleInputIdx(len(f.inputs))
+			return nil, false, f.focus()
+		}
+	}
+	f.err = nil
+	f.inputs[f.idx], cmd = f.inputs[f.idx].Update(msg)
+	return nil, false, cmd
+}
+
+func (f *scheduleForm) View(height int) string {
+	var b strings.Builder
+	b.WriteString(f.style.PromptStyle.Render(styles.PadFieldName("Station       ", nil)))
+	b.WriteString(f.style.SecondaryColorStyle.Render(f.station.Name))
+	b.WriteString("\n\n")
+	for i := range f.inputs {
+		b.WriteString(f.inputs[i].View())
+		b.WriteString("\n\n")
+	}
+	if f.err != nil {
+		b.WriteString(f.style.PrimaryColorStyle.Render(f.err.Error()))
+		b.WriteString("\n")
+	}
+
+	help := f.style.HelpStyle.Render(f.help.View(&f.keymap))
+	availHeight := height - lipgloss.Height(help)
+	for i := lipgloss.Height(b.String()); i < availHeight; i++ {
+		b.WriteString("\n")
+	}
+	return b.String() + help
+}
+
+type scheduleFormKeymap struct {
+	next   key.Binding
+	prev   key.Binding
+	submit key.Binding
+	cancel key.Binding
+}
+
+func newScheduleFormKeymap() scheduleFormKeymap {
+	return scheduleFormKeymap{
+		next: key.NewBinding(
+			key.WithKeys("tab", "down"),
+			key.WithHelp("tab/↓", "next field"),
+		),
+		prev: key.NewBinding(
+			key.WithKeys("shift+tab", "up"),
+			key.WithHelp("shift+tab/↑", "prev field"),
+		),
+		submit: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "schedule"),
+		),
+		cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+	}
+}
+
+func (k *scheduleFormKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.submit, k.next, k.prev, k.cancel}
+}
+
+func (k *scheduleFormKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
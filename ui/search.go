@@ -2,9 +2,11 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dancnb/sonicradio/ui/components"
 	"github.com/dancnb/sonicradio/ui/styles"
@@ -15,14 +17,25 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+)
+
+const (
+	lookupFoundMsg       = "Press enter to play the station, f to add it to favorites"
+	transientStationMsg  = "Not listed in the directory: press enter to play the stream, it can't be added to favorites"
+	transientFavoriteMsg = "Only the stations listed in the directory can be added to favorites"
 )
 
 type searchModel struct {
 	enabled bool
+	// searching is set from the submit until the stations found are listed
+	searching bool
 
 	style *styles.Style
 
+	ctx       context.Context
 	browser   *browser.Api
+	cfg       *config.Value
 	countries []string
 	languages []string
 
@@ -34,6 +47,13 @@ type searchModel struct {
 
 	reverse bool
 
+	// as-you-type search
+	instant       bool
+	instantSeq    int
+	instantCancel context.CancelFunc
+	instantCount  *int
+	instantErr    error
+
 	keymap searchKeymap
 	help   help.Model
 	width  int
@@ -95,12 +115,12 @@ var orderView = []components.OptionValue{
 	{IdxView: 0, NameView: "Random           "},
 }
 
-func newSearchModel(ctx context.Context, browser *browser.Api, s *styles.Style) *searchModel {
+func newSearchModel(ctx context.Context, browser *browser.Api, cfg *config.Value, s *styles.Style) *searchModel {
 	k := newSearchKeymap()
 	inputs := []textinput.Model{
-		s.NewInputModel("Name          ", "leave empty for all", &k.prevSugg, &k.nextSugg, &k.acceptSugg, nil),
+		s.NewInputModel("Name          ", "leave empty for all, or paste a station link", &k.prevSugg, &k.nextSugg, &k.acceptSugg, nil),
 		s.NewInputModel("Tags          ", "comma separated list", &k.prevSugg, &k.nextSugg, &k.acceptSugg, nil),
-		s.NewInputModel("Country       ", "---", &k.prevSugg, &k.nextSugg, &k.acceptSugg, nil),
+		s.NewInputModel("Country       ", "name or code, e.g. DE", &k.prevSugg, &k.nextSugg, &k.acceptSugg, nil),
 		s.NewInputModel("Language      ", "---", &k.prevSugg, &k.nextSugg, &k.acceptSugg, nil),
 		s.NewInputModel("Limit         ", "---", &k.prevSugg, &k.nextSugg, &k.acceptSugg, styles.NrInputValidator),
 	}
@@ -116,7 +136,9 @@ func newSearchModel(ctx context.Context, browser *browser.Api, s *styles.Style)
 	orderOpts := components.NewOptionList("Order by", orderView, 0, s)
 	orderOpts.SetQuick(true)
 	sm := &searchModel{
+		ctx:          ctx,
 		browser:      browser,
+		cfg:          cfg,
 		keymap:       k,
 		help:         h,
 		inputs:       formElems,
@@ -137,6 +159,16 @@ func (s *searchModel) getSuggestions() {
 		s.inputs[country].TextInput().SetSuggestions(s.countries)
 	}
 
+	tagList, err := s.browser.GetTags()
+	if err == nil && len(tagList) > 0 {
+		tagNames := make([]string, len(tagList))
+		for i := range tagList {
+			tagNames[i] = tagList[i].Name
+		}
+		s.inputs[tags].TextInput().ShowSuggestions = true
+		s.inputs[tags].TextInput().SetSuggestions(tagNames)
+	}
+
 	langs, err := s.browser.GetLanguages()
 	if err == nil && len(langs) > 0 {
 		for i := range langs {
@@ -174,11 +206,17 @@ func (s *searchModel) setEnabled(v bool) {
 		s.inputs[i].TextInput().Reset()
 	}
 	s.inputs[limit].SetValue(fmt.Sprintf("%d", browser.DefLimit))
+	if v {
+		s.inputs[language].SetValue(s.cfg.LearnLanguage)
+	}
 	if !v {
 		s.orderOptions.SetIdx(0)
 	}
 	s.oIdx = orderVotes
 	s.reverse = true
+	s.cancelInstantSearch()
+	s.instantCount = nil
+	s.instantErr = nil
 	showAll := false
 	s.help.ShowAll = showAll
 	s.keymap.setEnable(v, showAll)
@@ -198,10 +236,30 @@ func (s *searchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		s.setSize(msg.Width, msg.Height)
 
+	case instantSearchTickMsg:
+		if msg.seq != s.instantSeq || !s.instant {
+			return s, nil
+		}
+		return s, s.instantSearchCmd(msg.seq)
+
+	case instantSearchRespMsg:
+		if msg.seq != s.instantSeq || !s.instant {
+			return s, nil
+		}
+		s.instantCancel = nil
+		if errors.Is(msg.err, context.Canceled) {
+			return s, nil
+		}
+		s.instantErr = msg.err
+		count := len(msg.stations)
+		s.instantCount = &count
+		return s, nil
+
 	case components.OptionMsg:
 		if msg.Done {
 			s.orderOptions.SetFocused(false)
 			s.oIdx = orderIx(msg.SelIdx)
+			cmds = append(cmds, s.scheduleInstantSearch())
 			s.keymap.setEnable(true, s.help.ShowAll)
 			cmds = s.updateInputs(cmds)
 			return s, tea.Batch(cmds...)
@@ -230,39 +288,55 @@ func (s *searchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, s.keymap.reverse):
 			s.reverse = !s.reverse
+			cmds = append(cmds, s.scheduleInstantSearch())
+
+		case key.Matches(msg, s.keymap.instant):
+			s.instant = !s.instant
+			if s.instant {
+				cmds = append(cmds, s.scheduleInstantSearch())
+			} else {
+				s.cancelInstantSearch()
+				s.instantCount = nil
+				s.instantErr = nil
+			}
+			return s, tea.Batch(cmds...)
 
 		case key.Matches(msg, s.keymap.cancel):
 			return s, func() tea.Msg {
-				s.setEnabled(false)
 				return searchRespMsg{cancelled: true}
 			}
 
 		case key.Matches(msg, s.keymap.submit):
+			s.cancelInstantSearch()
+			s.searching = true
+			if uuid, streamUrl := browser.ParseStationRef(s.inputs[name].Value()); uuid != "" || streamUrl != "" {
+				return s, s.lookupCmd(uuid, streamUrl)
+			}
+			params := s.searchParams()
 			return s, func() tea.Msg {
-				defer s.setEnabled(false)
-
-				params := browser.DefaultSearchParams()
-				params.Name = strings.TrimSpace(s.inputs[name].Value())
-				params.TagList = strings.TrimSpace(s.inputs[tags].Value())
-				params.Country = strings.Title(strings.TrimSpace(s.inputs[country].Value()))
-				params.Language = strings.TrimSpace(s.inputs[language].Value())
-				limit, err := strconv.Atoi(strings.TrimSpace(s.inputs[limit].Value()))
-				if err == nil {
-					params.Limit = limit
-				}
-				params.Order = s.oIdx.toSearchOrder()
-				params.Reverse = s.reverse
-
 				stations, err := s.browser.Search(params)
-				res := searchRespMsg{stations: stations}
+				res := searchRespMsg{stations: stations, params: &params}
 				if err != nil {
-					res.statusMsg = statusMsg(err.Error())
+					res.errorMsg = errorMsg(err.Error())
 				} else if len(stations) == 0 {
 					res.viewMsg = noStationsFound
 				}
 				return res
 			}
 
+		case key.Matches(msg, s.keymap.localSubmit):
+			s.cancelInstantSearch()
+			s.searching = true
+			params := s.searchParams()
+			return s, func() tea.Msg {
+				stations := s.browser.LocalSearch(params)
+				res := searchRespMsg{stations: stations}
+				if len(stations) == 0 {
+					res.viewMsg = noStationsFound
+				}
+				return res
+			}
+
 		case key.Matches(msg, s.keymap.nextInput):
 			if msg.String() == "tab" && strings.TrimSpace(s.inputs[s.idx].Value()) != "" && s.inputs[s.idx].TextInput().ShowSuggestions {
 				s.inputs[s.idx].SetValue(s.inputs[s.idx].TextInput().CurrentSuggestion())
@@ -280,16 +354,107 @@ func (s *searchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	prevParams := s.searchParams()
 	for i := range s.inputs {
 		var cmd tea.Cmd
 		fEl, cmd := s.inputs[i].Update(msg)
 		s.inputs[i] = *fEl
 		cmds = append(cmds, cmd)
 	}
+	if s.searchParams() != prevParams {
+		cmds = append(cmds, s.scheduleInstantSearch())
+	}
 
 	return s, tea.Batch(cmds...)
 }
 
+// prefill sets the inputs to the search parameters, e.g. translated from the list filter.
+func (s *searchModel) prefill(p browser.SearchParams) {
+	s.inputs[name].SetValue(p.Name)
+	s.inputs[tags].SetValue(p.TagList)
+	s.inputs[country].SetValue(p.Country + p.CountryCode)
+	if p.Language != "" {
+		s.inputs[language].SetValue(p.Language)
+	}
+}
+
+func (s *searchModel) searchParams() browser.SearchParams {
+	params := browser.DefaultSearchParams()
+	params.Name = strings.TrimSpace(s.inputs[name].Value())
+	params.TagList = strings.TrimSpace(s.inputs[tags].Value())
+	params.Country = strings.Title(strings.TrimSpace(s.inputs[country].Value()))
+	if len(params.Country) == 2 {
+		// a country code, like the ones of the list filter
+		params.CountryCode, params.Country = strings.ToUpper(params.Country), ""
+	}
+	params.Language = strings.TrimSpace(s.inputs[language].Value())
+	limit, err := strconv.Atoi(strings.TrimSpace(s.inputs[limit].Value()))
+	if err == nil {
+		params.Limit = limit
+	}
+	params.Order = s.oIdx.toSearchOrder()
+	params.Reverse = s.reverse
+	return params
+}
+
+// lookupCmd finds the station of the uuid or the stream url pasted as the name, offering to play or save it.
+func (s *searchModel) lookupCmd(uuid, streamUrl string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(s.ctx, config.ApiReqTimeout)
+		defer cancel()
+		stations, err := s.browser.Lookup(ctx, uuid, streamUrl)
+		res := searchRespMsg{stations: stations}
+		switch {
+		case err != nil:
+			res.errorMsg = errorMsg(err.Error())
+		case len(stations) == 0:
+			res.viewMsg = noStationsFound
+		case stations[0].Transient():
+			res.status = transientStationMsg
+		default:
+			res.status = lookupFoundMsg
+		}
+		return res
+	}
+}
+
+// scheduleInstantSearch starts the debounce timer for an as-you-type search.
+// Only the search scheduled last is performed, the previous ones become stale.
+func (s *searchModel) scheduleInstantSearch() tea.Cmd {
+	if !s.instant {
+		return nil
+	}
+	if uuid, streamUrl := browser.ParseStationRef(s.inputs[name].Value()); uuid != "" || streamUrl != "" {
+		// looked up on submit, not searched by name
+		s.cancelInstantSearch()
+		s.instantCount, s.instantErr = nil, nil
+		return nil
+	}
+	s.instantSeq++
+	seq := s.instantSeq
+	return tea.Tick(config.SearchDebounce, func(time.Time) tea.Msg {
+		return instantSearchTickMsg{seq: seq}
+	})
+}
+
+func (s *searchModel) instantSearchCmd(seq int) tea.Cmd {
+	s.cancelInstantSearch()
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.instantCancel = cancel
+	params := s.searchParams()
+	return func() tea.Msg {
+		stations, err := s.browser.SearchCtx(ctx, params)
+		return instantSearchRespMsg{seq: seq, stations: stations, err: err}
+	}
+}
+
+func (s *searchModel) cancelInstantSearch() {
+	if s.instantCancel != nil {
+		s.instantCancel()
+		s.instantCancel = nil
+	}
+}
+
 func (s *searchModel) updateInputs(cmds []tea.Cmd) []tea.Cmd {
 	for i := range s.inputs {
 		if !s.orderOptions.IsActive() && i == int(s.idx) {
@@ -320,6 +485,19 @@ func (s *searchModel) View() string {
 	}
 	b.WriteString(s.style.PrimaryColorStyle.Render(rev))
 
+	if s.instant {
+		b.WriteRune('\n')
+		b.WriteRune('\n')
+		b.WriteString(s.style.PromptStyle.Render(styles.PadFieldName("Results       ", nil)))
+		res := "..."
+		if s.instantErr != nil {
+			res = s.instantErr.Error()
+		} else if s.instantCount != nil && s.instantCancel == nil {
+			res = fmt.Sprintf("%d", *s.instantCount)
+		}
+		b.WriteString(s.style.PrimaryColorStyle.Render(res))
+	}
+
 	availHeight := s.height
 	var help string
 	if !s.orderOptions.IsActive() {
@@ -339,11 +517,13 @@ func (s *searchModel) View() string {
 
 type searchKeymap struct {
 	submit        key.Binding
+	localSubmit   key.Binding
 	cancel        key.Binding
 	nextInput     key.Binding
 	prevInput     key.Binding
 	order         key.Binding
 	reverse       key.Binding
+	instant       key.Binding
 	prevSugg      key.Binding
 	nextSugg      key.Binding
 	acceptSugg    key.Binding
@@ -357,6 +537,10 @@ func newSearchKeymap() searchKeymap {
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "submit"),
 		),
+		localSubmit: key.NewBinding(
+			key.WithKeys("alt+enter"),
+			key.WithHelp("alt+enter", "search offline"),
+		),
 		cancel: key.NewBinding(
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "cancel"),
@@ -377,6 +561,10 @@ func newSearchKeymap() searchKeymap {
 			key.WithKeys("ctrl+r"),
 			key.WithHelp("ctrl+r", "reverse"),
 		),
+		instant: key.NewBinding(
+			key.WithKeys("ctrl+t"),
+			key.WithHelp("ctrl+t", "search as you type"),
+		),
 		prevSugg: key.NewBinding(
 			key.WithKeys("ctrl+p", "ctrl+up"),
 			key.WithHelp("ctrl+↑/ctrl+p", "prev suggestion"),
@@ -409,18 +597,20 @@ func (k *searchKeymap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.prevInput, k.nextInput},
 		{k.prevSugg, k.nextSugg, k.acceptSugg},
-		{k.order, k.reverse},
-		{k.submit, k.cancel, k.closeFullHelp},
+		{k.order, k.reverse, k.instant},
+		{k.submit, k.localSubmit, k.cancel, k.closeFullHelp},
 	}
 }
 
 func (k *searchKeymap) setEnable(enabled bool, showAll bool) {
 	k.submit.SetEnabled(enabled)
+	k.localSubmit.SetEnabled(enabled)
 	k.cancel.SetEnabled(enabled)
 	k.prevInput.SetEnabled(enabled)
 	k.nextInput.SetEnabled(enabled)
 	k.order.SetEnabled(enabled)
 	k.reverse.SetEnabled(enabled)
+	k.instant.SetEnabled(enabled)
 	k.prevSugg.SetEnabled(enabled)
 	k.nextSugg.SetEnabled(enabled)
 	k.acceptSugg.SetEnabled(enabled)
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func Test_e2eInstantSearch(t *testing.T) {
+	d := newUIDriver(t, slices.Concat(e2eStations(3, "Jazz", "jazz"), e2eStations(2, "Rock", "rock"))...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 5 })
+	s := d.browse().searchModel
+
+	d.keys("s", "ctrl+t")
+	searches := d.srv.Searches()
+	d.typeText("rock")
+	d.waitFor("the instant search", func() bool { return s.instantCount != nil })
+	if *s.instantCount != 2 || s.instantErr != nil {
+		t.Errorf("got %d stations, err %v, want the 2 of rock", *s.instantCount, s.instantErr)
+	}
+	if n := d.srv.Searches() - searches; n != 1 {
+		t.Errorf("got %d searches, want only the one of the last keystroke", n)
+	}
+}
+
+func Test_instantSearchStale(t *testing.T) {
+	d := newUIDriver(t, e2eStations(3, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 3 })
+	s := d.browse().searchModel
+	d.keys("s", "ctrl+t")
+	d.typeText("ja")
+
+	// the response of the search of the first keystroke, arriving after the second one
+	s.Update(instantSearchRespMsg{seq: s.instantSeq - 1, stations: e2eStations(1, "Jazz", "jazz")})
+	if s.instantCount != nil {
+		t.Errorf("got the count %d of a stale search, want it dropped", *s.instantCount)
+	}
+	s.Update(instantSearchRespMsg{seq: s.instantSeq, stations: e2eStations(3, "Jazz", "jazz")})
+	if s.instantCount == nil || *s.instantCount != 3 {
+		t.Errorf("got the count %v, want the 3 of the last search", s.instantCount)
+	}
+}
+
+func Test_instantSearchToggleOff(t *testing.T) {
+	d := newUIDriver(t, e2eStations(3, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 3 })
+	s := d.browse().searchModel
+	d.keys("s", "ctrl+t")
+	d.typeText("jazz 1")
+
+	// the search in flight, not run yet
+	search := s.instantSearchCmd(s.instantSeq)
+	if s.instantCancel == nil {
+		t.Fatal("expected the search to be cancellable")
+	}
+	searches := d.srv.Searches()
+	d.keys("ctrl+t")
+	if s.instant || s.instantCancel != nil {
+		t.Fatalf("got instant %v, want it off with the search cancelled", s.instant)
+	}
+	msg := search()
+	if resp, ok := msg.(instantSearchRespMsg); !ok || !errors.Is(resp.err, context.Canceled) {
+		t.Fatalf("got %#v, want the search cancelled", msg)
+	}
+	if n := d.srv.Searches() - searches; n != 0 {
+		t.Errorf("got %d searches, want none after the cancel", n)
+	}
+	s.Update(msg)
+	if s.instantCount != nil || s.instantErr != nil {
+		t.Errorf("got the count %v, err %v, want the cancelled response dropped", s.instantCount, s.instantErr)
+	}
+}
@@ -0,0 +1,310 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/diskquota"
+	"github.com/dancnb/sonicradio/player"
+	"github.com/dancnb/sonicradio/ui/styles"
+)
+
+const (
+	defSessionName    = "Main"
+	sessionNameFmt    = "Session %d"
+	sessionStartedMsg = "Started session %s"
+)
+
+// session is a playback with its own player, so that several stations can play at once, e.g. in different rooms.
+type session struct {
+	name        string
+	player      *player.Player
+	currPlaying *browser.Station
+	prevPlaying *browser.Station
+	volume      int
+	// streamed is the estimated bytes played locally by the session
+	streamed int64
+	// uptimeSince is when the station uptimeUuid started streaming, without interruption since
+	uptimeUuid  string
+	uptimeSince time.Time
+}
+
+func (s *session) stationView(cfg *config.Value) string {
+	switch {
+	case s.currPlaying != nil:
+		return styles.PlayChar + " " + cfg.StationName(s.currPlaying.Stationuuid, s.currPlaying.Name)
+	case s.prevPlaying != nil:
+		return styles.PauseChar + " " + cfg.StationName(s.prevPlaying.Stationuuid, s.prevPlaying.Name)
+	}
+	return styles.LineChar + " " + noPlayingMsg
+}
+
+// sessionsView lists the sessions, with the station, volume and streamed data of each, to switch between them.
+type sessionsView struct {
+	enabled bool
+	style   *styles.Style
+
+	sessions []*session
+	active   int
+	idx      int
+
+	naming    bool
+	nameInput textinput.Model
+
+	keymap sessionsKeymap
+	help   help.Model
+	width  int
+	height int
+}
+
+func newSessionsView(s *styles.Style, main *session) *sessionsView {
+	h := help.New()
+	h.ShortSeparator = "   "
+	h.Styles = s.HelpStyles()
+	return &sessionsView{
+		style:     s,
+		sessions:  []*session{main},
+		nameInput: s.NewInputModel("Name", "session name", nil, nil, nil, nil),
+		keymap:    newSessionsKeymap(),
+		help:      h,
+	}
+}
+
+func (v *sessionsView) setSize(width, height int) {
+	h, vf := v.style.DocStyle.GetFrameSize()
+	v.width = width - h
+	v.height = height - vf
+	v.help.Width = v.width
+}
+
+func (v *sessionsView) View(cfg *config.Value) string {
+	var b strings.Builder
+	b.WriteString("\n")
+	for i, s := range v.sessions {
+		prefix := "  "
+		if i == v.active {
+			prefix = styles.ActiveChar
+		}
+		name := v.style.PrefixStyle.Render(styles.PadFieldName(prefix+s.name, nil))
+		vol := v.style.ItalicStyle.Render(fmt.Sprintf(" %3d%% · %s", s.volume, diskquota.FormatSize(s.streamed)))
+		itStyle := v.style.SecondaryColorStyle
+		if i == v.idx {
+			itStyle = v.style.HistorySelItemStyle
+		}
+		station := s.stationView(cfg)
+		for lipgloss.Width(name)+lipgloss.Width(station)+lipgloss.Width(vol) > v.width && len(station) > 0 {
+			station = station[:len(station)-1]
+		}
+		fill := max(0, v.width-lipgloss.Width(name)-lipgloss.Width(station)-lipgloss.Width(vol))
+		b.WriteString(name + itStyle.Render(station+strings.Repeat(" ", fill)) + vol + "\n")
+	}
+	if v.naming {
+		b.WriteString("\n" + v.nameInput.View() + "\n")
+	}
+
+	help := v.style.HelpStyle.Render(v.help.View(&v.keymap))
+	for i := lipgloss.Height(b.String()); i < v.height-lipgloss.Height(help); i++ {
+		b.WriteString("\n")
+	}
+	return b.String() + help
+}
+
+// toggleSessions shows or hides the sessions view.
+func (m *Model) toggleSessions() tea.Cmd {
+	v := m.sessions
+	v.enabled = !v.enabled
+	v.naming = false
+	if !v.enabled {
+		return nil
+	}
+	m.saveSession()
+	v.idx = v.active
+	v.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	return nil
+}
+
+// saveSession keeps the playback of the active session, before switching to another.
+func (m *Model) saveSession() {
+	s := m.sessions.sessions[m.sessions.active]
+	m.delegate.playingMtx.RLock()
+	s.currPlaying = m.delegate.currPlaying
+	s.prevPlaying = m.delegate.prevPlaying
+	m.delegate.playingMtx.RUnlock()
+	s.volume = m.cfg.GetVolume()
+}
+
+// switchSession makes the session the one controlled by the app, the others keep playing.
+func (m *Model) switchSession(idx int) {
+	if idx == m.sessions.active {
+		return
+	}
+	m.saveSession()
+	m.sessions.active = idx
+	s := m.sessions.sessions[idx]
+
+	m.delegate.playingMtx.Lock()
+	m.delegate.currPlaying = s.currPlaying
+	m.delegate.prevPlaying = s.prevPlaying
+	m.delegate.player = s.player
+	m.player = s.player
+	m.delegate.playingMtx.Unlock()
+	m.cfg.SetVolume(s.volume)
+
+	m.songTitle = ""
+	m.songChange.Reset()
+	m.playbackTime = 0
+	m.spinner = nil
+	if s.currPlaying != nil {
+		m.delegate.keymap.pause.SetHelp("space", "pause")
+	} else {
+		m.delegate.keymap.pause.SetHelp("space", "resume")
+	}
+}
+
+func (m *Model) newSessionCmd(name string) tea.Cmd {
+	return func() tea.Msg {
+		p, err := m.newPlayer()
+		if err != nil {
+			slog.With("method", "ui.Model.newSessionCmd").Error("", "error", err)
+			return errorMsg(fmt.Sprintf("Could not start session %s: %v", name, err))
+		}
+		return sessionCreatedMsg{&session{name: name, player: p, volume: m.cfg.GetVolume()}}
+	}
+}
+
+// closeSession stops the playback of a session other than the active one.
+func (m *Model) closeSession(idx int) {
+	v := m.sessions
+	if idx == v.active {
+		m.updateStatusWarn("Switch to another session to close this one")
+		return
+	}
+	s := v.sessions[idx]
+	closeSessionPlayer(s)
+	v.sessions = append(v.sessions[:idx], v.sessions[idx+1:]...)
+	if v.active > idx {
+		v.active--
+	}
+	v.idx = min(v.idx, len(v.sessions)-1)
+}
+
+func closeSessionPlayer(s *session) {
+	log := slog.With("method", "ui.closeSessionPlayer")
+	if err := s.player.Stop(); err != nil {
+		log.Error("player stop", "session", s.name, "error", err)
+	}
+	if err := s.player.Close(); err != nil {
+		log.Error("player close", "session", s.name, "error", err)
+	}
+}
+
+// closeInactiveSessions stops the players of the sessions other than the active one on quit, waiting for
+// each of them to stop.
+func (m *Model) closeInactiveSessions() {
+	for i, s := range m.sessions.sessions {
+		if i != m.sessions.active {
+			closeSessionPlayer(s)
+		}
+	}
+}
+
+func (m *Model) updateSessions(msg tea.Msg) tea.Cmd {
+	v := m.sessions
+	if v.naming {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch {
+			case key.Matches(msg, v.keymap.cancel):
+				v.naming = false
+				return nil
+			case key.Matches(msg, v.keymap.switchTo):
+				v.naming = false
+				name := strings.TrimSpace(v.nameInput.Value())
+				if name == "" {
+					name = fmt.Sprintf(sessionNameFmt, len(v.sessions)+1)
+				}
+				return m.newSessionCmd(name)
+			}
+		}
+		var cmd tea.Cmd
+		v.nameInput, cmd = v.nameInput.Update(msg)
+		return cmd
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	switch {
+	case key.Matches(keyMsg, v.keymap.up):
+		v.idx = (v.idx + len(v.sessions) - 1) % len(v.sessions)
+	case key.Matches(keyMsg, v.keymap.down):
+		v.idx = (v.idx + 1) % len(v.sessions)
+	case key.Matches(keyMsg, v.keymap.switchTo):
+		m.switchSession(v.idx)
+		v.enabled = false
+	case key.Matches(keyMsg, v.keymap.newSession):
+		v.naming = true
+		v.nameInput.SetValue("")
+		return v.nameInput.Focus()
+	case key.Matches(keyMsg, v.keymap.closeSession):
+		m.closeSession(v.idx)
+	case key.Matches(keyMsg, v.keymap.cancel, m.delegate.keymap.sessions):
+		v.enabled = false
+	}
+	return nil
+}
+
+type sessionsKeymap struct {
+	up           key.Binding
+	down         key.Binding
+	switchTo     key.Binding
+	newSession   key.Binding
+	closeSession key.Binding
+	cancel       key.Binding
+}
+
+func newSessionsKeymap() sessionsKeymap {
+	return sessionsKeymap{
+		up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		switchTo: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "switch"),
+		),
+		newSession: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "new session"),
+		),
+		closeSession: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "close session"),
+		),
+		cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back"),
+		),
+	}
+}
+
+func (k *sessionsKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.up, k.down, k.switchTo, k.newSession, k.closeSession, k.cancel}
+}
+
+func (k *sessionsKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
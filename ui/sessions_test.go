@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dancnb/sonicradio/player"
+	"github.com/dancnb/sonicradio/player/playertest"
+)
+
+func Test_e2eSessions(t *testing.T) {
+	stations := e2eStations(2, "Jazz", "jazz")
+	d := newUIDriver(t, stations...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 2 })
+	d.keys("enter")
+	d.waitFor("the station to play", func() bool { return d.m.connecting == nil && d.m.delegate.playingUuid() == stations[0].Stationuuid })
+	d.m.cfg.SetVolume(70)
+
+	// a new session has no station yet
+	kitchen := playertest.New()
+	d.send(sessionCreatedMsg{&session{name: "Kitchen", player: player.NewWithBackend(kitchen), volume: 40}})
+	if d.m.sessions.active != 1 || d.m.delegate.playingUuid() != "" || d.m.cfg.GetVolume() != 40 {
+		t.Fatalf("got session %d playing %q at %d, want the new session without a station at 40",
+			d.m.sessions.active, d.m.delegate.playingUuid(), d.m.cfg.GetVolume())
+	}
+	if got := d.m.sessions.sessions[1].stationView(d.m.cfg); !strings.Contains(got, noPlayingMsg) {
+		t.Errorf("got %q, want the session without a station", got)
+	}
+	if h := d.m.delegate.keymap.pause.Help().Desc; h != "resume" {
+		t.Errorf("got the pause help %q, want resume", h)
+	}
+
+	d.keys("down", "enter")
+	d.waitFor("the station of the new session", func() bool {
+		return d.m.connecting == nil && d.m.delegate.playingUuid() == stations[1].Stationuuid
+	})
+	if url, _ := kitchen.Playing(); url != stations[1].URL {
+		t.Errorf("new session playing %q, want %q", url, stations[1].URL)
+	}
+	if url, _ := d.player.Playing(); url != stations[0].URL {
+		t.Errorf("main session playing %q, want it still on %q", url, stations[0].URL)
+	}
+
+	d.m.switchSession(0)
+	if d.m.delegate.playingUuid() != stations[0].Stationuuid || d.m.cfg.GetVolume() != 70 || d.m.player != d.m.sessions.sessions[0].player {
+		t.Errorf("got %q at %d, want the station and volume of the main session restored", d.m.delegate.playingUuid(), d.m.cfg.GetVolume())
+	}
+	d.m.switchSession(1)
+	if d.m.delegate.playingUuid() != stations[1].Stationuuid || d.m.cfg.GetVolume() != 40 {
+		t.Errorf("got %q at %d, want the station and volume of the new session restored", d.m.delegate.playingUuid(), d.m.cfg.GetVolume())
+	}
+
+	d.m.closeSession(1)
+	if len(d.m.sessions.sessions) != 2 {
+		t.Errorf("closed the active session")
+	}
+	d.m.closeSession(0)
+	if len(d.m.sessions.sessions) != 1 || d.m.sessions.active != 0 || d.m.sessions.sessions[0].name != "Kitchen" {
+		t.Errorf("got the sessions %d active %d, want the new one left", len(d.m.sessions.sessions), d.m.sessions.active)
+	}
+}
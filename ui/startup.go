@@ -0,0 +1,20 @@
+package ui
+
+import (
+	"fmt"
+)
+
+var (
+	// startupVolumes are the options of the volume at the start, 0 being the last one.
+	startupVolumes = []int{0, 20, 30, 40, 50, 60, 70, 80}
+	// startupFades are the milliseconds of the fade in options of the first station played, 0 being off.
+	startupFades = []int{0, 2000, 5000, 10000}
+)
+
+// startupVolumeName returns the name of the startup volume option.
+func startupVolumeName(v int) string {
+	if v <= 0 {
+		return "Last"
+	}
+	return fmt.Sprintf("%d%%", v)
+}
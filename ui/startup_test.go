@@ -0,0 +1,22 @@
+package ui
+
+import "testing"
+
+func Test_e2eStartup(t *testing.T) {
+	d := newUIDriver(t)
+	d.keys("S", "j", "j", "j", "j", "j", "j", "j", "j", "j", "j", "enter", "4")
+	d.waitFor("the startup volume set", func() bool { return d.m.cfg.StartupVolume == 40 })
+	d.keys("j", "enter", "3")
+	d.waitFor("the startup fade set", func() bool { return d.m.cfg.StartupFadeMs == 5000 })
+	if d.m.cfg.StartupFade().Seconds() != 5 {
+		t.Errorf("got startup fade %v, want 5s", d.m.cfg.StartupFade())
+	}
+}
+
+func Test_startupVolumeName(t *testing.T) {
+	for v, want := range map[int]string{0: "Last", 40: "40%"} {
+		if got := startupVolumeName(v); got != want {
+			t.Errorf("startupVolumeName(%d) = %s, want %s", v, got, want)
+		}
+	}
+}
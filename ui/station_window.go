@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/browser"
+)
+
+const stationWindowSize = 200
+
+// stationWindow keeps the stations that were not yet handed to the list model.
+// Broad searches can return tens of thousands of stations, so list items are only
+// created in chunks, when the cursor gets close to the end of the loaded ones.
+type stationWindow struct {
+	pending []browser.Station
+}
+
+// reset returns the first chunk of items and keeps the rest as pending.
+func (w *stationWindow) reset(stations []browser.Station) []list.Item {
+	n := min(len(stations), stationWindowSize)
+	items := make([]list.Item, n, len(stations))
+	for i := 0; i < n; i++ {
+		items[i] = stations[i]
+	}
+	w.pending = stations[n:]
+	return items
+}
+
+func (w *stationWindow) hasPending() bool {
+	return len(w.pending) > 0
+}
+
+// extend appends up to n pending stations to the list items. A negative n loads all of them.
+func (w *stationWindow) extend(l *list.Model, n int) tea.Cmd {
+	if !w.hasPending() {
+		return nil
+	}
+	if n < 0 || n > len(w.pending) {
+		n = len(w.pending)
+	}
+	items := l.Items()
+	for i := 0; i < n; i++ {
+		items = append(items, w.pending[i])
+	}
+	w.pending = w.pending[n:]
+	idx := l.Index()
+	cmd := l.SetItems(items)
+	l.Select(idx)
+	return cmd
+}
+
+// add appends the stations to the pending ones, or to the list items while the list is filtered,
+// so that the filter covers them too.
+func (w *stationWindow) add(l *list.Model, stations []browser.Station) tea.Cmd {
+	w.pending = append(w.pending, stations...)
+	if l.FilterState() == list.Unfiltered {
+		return nil
+	}
+	return w.extend(l, -1)
+}
+
+// onKey loads more items before the list model handles a navigation key,
+// so that moving past the loaded items does not wrap around.
+func (w *stationWindow) onKey(l *list.Model, msg tea.KeyMsg) tea.Cmd {
+	if !w.hasPending() {
+		return nil
+	}
+	switch {
+	case key.Matches(msg, l.KeyMap.GoToEnd, l.KeyMap.Filter),
+		key.Matches(msg, l.KeyMap.CursorUp) && l.Index() == 0:
+		return w.extend(l, -1)
+	case key.Matches(msg, l.KeyMap.CursorDown, l.KeyMap.NextPage):
+		if l.Index() >= len(l.Items())-l.Paginator.PerPage {
+			return w.extend(l, stationWindowSize)
+		}
+	}
+	return nil
+}
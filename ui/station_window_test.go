@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+)
+
+func testStations(n int) []browser.Station {
+	res := make([]browser.Station, n)
+	for i := range res {
+		res[i] = browser.Station{Stationuuid: fmt.Sprintf("uuid-%d", i), Name: fmt.Sprintf("station %d", i)}
+	}
+	return res
+}
+
+func Test_stationWindow(t *testing.T) {
+	total := stationWindowSize*2 + 10
+	var w stationWindow
+	l := list.New(nil, list.NewDefaultDelegate(), 80, 20)
+	l.SetItems(w.reset(testStations(total)))
+
+	if got := len(l.Items()); got != stationWindowSize {
+		t.Fatalf("initial items=%d, want %d", got, stationWindowSize)
+	}
+
+	l.Select(len(l.Items()) - 1)
+	w.onKey(&l, tea.KeyMsg{Type: tea.KeyDown})
+	if got := len(l.Items()); got != stationWindowSize*2 {
+		t.Errorf("items after cursor down=%d, want %d", got, stationWindowSize*2)
+	}
+	if got := l.Index(); got != stationWindowSize-1 {
+		t.Errorf("selection changed to %d, want %d", got, stationWindowSize-1)
+	}
+
+	w.onKey(&l, tea.KeyMsg{Type: tea.KeyEnd})
+	if got := len(l.Items()); got != total {
+		t.Errorf("items after go to end=%d, want %d", got, total)
+	}
+	if w.hasPending() {
+		t.Error("expected no pending stations")
+	}
+	last := l.Items()[total-1].(browser.Station)
+	if last.Stationuuid != fmt.Sprintf("uuid-%d", total-1) {
+		t.Errorf("last item=%s, want uuid-%d", last.Stationuuid, total-1)
+	}
+}
+
+func Test_stationWindow_add(t *testing.T) {
+	var w stationWindow
+	l := list.New(nil, list.NewDefaultDelegate(), 80, 20)
+	l.SetItems(w.reset(testStations(10)))
+
+	w.add(&l, testStations(5))
+	if got := len(l.Items()); got != 10 {
+		t.Errorf("unfiltered items=%d, want 10", got)
+	}
+	if got := len(w.pending); got != 5 {
+		t.Errorf("pending=%d, want 5", got)
+	}
+
+	l, _ = l.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	w.add(&l, testStations(3))
+	if got := len(l.Items()); got != 18 {
+		t.Errorf("filtered items=%d, want 18", got)
+	}
+	if w.hasPending() {
+		t.Error("expected the filtered list to take the pending stations")
+	}
+}
+
+func Test_nextPage(t *testing.T) {
+	params := browser.DefaultSearchParams()
+	if p := nextPage(&params, testStations(browser.DefLimit-1)); p != nil {
+		t.Errorf("next page of a short page=%+v, want nil", p)
+	}
+	if p := nextPage(nil, testStations(browser.DefLimit)); p != nil {
+		t.Errorf("next page without search=%+v, want nil", p)
+	}
+	p := nextPage(&params, testStations(browser.DefLimit))
+	if p == nil {
+		t.Fatal("expected a next page")
+	}
+	if p.Offset != browser.DefLimit || p.Limit != config.LoadAllPageSize {
+		t.Errorf("next page offset=%d limit=%d, want %d and %d", p.Offset, p.Limit, browser.DefLimit, config.LoadAllPageSize)
+	}
+	if params.Offset != 0 {
+		t.Errorf("search params changed to offset %d", params.Offset)
+	}
+}
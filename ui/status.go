@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dancnb/sonicradio/ui/styles"
+)
+
+// statusLevel is the severity of a header status message, which sets its style and how long it's shown.
+type statusLevel uint8
+
+const (
+	infoStatus statusLevel = iota
+	warnStatus
+	errorStatus
+)
+
+func (l statusLevel) String() string {
+	switch l {
+	case warnStatus:
+		return "warn"
+	case errorStatus:
+		return "error"
+	}
+	return "info"
+}
+
+func (l statusLevel) timeout() time.Duration {
+	switch l {
+	case warnStatus:
+		return statusWarnTimeout
+	case errorStatus:
+		return statusErrTimeout
+	}
+	return statusMsgTimeout
+}
+
+// announcement is the message as announced to the screen readers, or shown when the symbols are used,
+// without the colors of its level.
+func (l statusLevel) announcement(msg string) string {
+	switch l {
+	case warnStatus:
+		return "Warning: " + msg
+	case errorStatus:
+		return "Error: " + msg
+	}
+	return msg
+}
+
+func (m *Model) statusView(level statusLevel, msg string) string {
+	style := m.style.StatusBarStyle
+	switch level {
+	case warnStatus:
+		style = m.style.StatusWarnStyle
+	case errorStatus:
+		style = m.style.StatusErrorStyle
+	}
+	if styles.Symbols() {
+		msg = level.announcement(msg)
+	}
+	pad := strings.Repeat(" ", styles.HeaderPadDist)
+	return m.style.StatusBarStyle.Render(pad) + style.Render(msg)
+}
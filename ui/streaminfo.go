@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dancnb/sonicradio/player/model"
+)
+
+// liveStream are the audio parameters reported by the player for the playing station, shared with the
+// station info view. The ones listed by radio-browser are often stale.
+type liveStream struct {
+	stationUuid string
+	info        *model.StreamInfo
+}
+
+// update records the parameters of the station, the last ones being kept while the player doesn't
+// report them.
+func (l *liveStream) update(uuid string, info *model.StreamInfo) {
+	if uuid != l.stationUuid {
+		l.stationUuid, l.info = uuid, nil
+	}
+	if info != nil {
+		l.info = info
+	}
+}
+
+// get returns the parameters of the station, nil if it's not the one playing or they're unknown.
+func (l *liveStream) get(uuid string) *model.StreamInfo {
+	if l == nil || uuid == "" || uuid != l.stationUuid {
+		return nil
+	}
+	return l.info
+}
+
+// streamSummary returns the codec and the bitrate, e.g. "MP3 128 kbps", for the playing station line.
+func streamSummary(s model.StreamInfo) string {
+	var parts []string
+	if s.Codec != "" {
+		parts = append(parts, s.Codec)
+	}
+	if s.BitrateKbps > 0 {
+		parts = append(parts, fmt.Sprintf("%d kbps", s.BitrateKbps))
+	}
+	return strings.Join(parts, " ")
+}
+
+// streamParams returns the sample rate and the channels, e.g. "44.1 kHz stereo".
+func streamParams(s model.StreamInfo) string {
+	var parts []string
+	if s.SampleRate > 0 {
+		parts = append(parts, strconv.FormatFloat(float64(s.SampleRate)/1000, 'f', -1, 64)+" kHz")
+	}
+	switch s.Channels {
+	case 0:
+	case 1:
+		parts = append(parts, "mono")
+	case 2:
+		parts = append(parts, "stereo")
+	default:
+		parts = append(parts, fmt.Sprintf("%d channels", s.Channels))
+	}
+	return strings.Join(parts, " ")
+}
+
+// liveCodecView returns the codec of the station info: the one playing with its parameters, and the
+// one listed if it differs, AAC+ being listed for the HE-AAC streams decoded as AAC.
+func liveCodecView(live *model.StreamInfo, listed string) string {
+	if live == nil || live.Codec == "" {
+		return listed
+	}
+	res := live.Codec
+	if params := streamParams(*live); params != "" {
+		res += " · " + params
+	}
+	if listed != "" && !strings.HasPrefix(strings.ToUpper(listed), live.Codec) {
+		return res + fmt.Sprintf(" (live, listed %s)", listed)
+	}
+	return res + " (live)"
+}
+
+// liveBitrateView returns the bitrate of the station info: the one measured while playing, and the one
+// listed if it differs by more than 5%, as the measures of the variable bitrates vary.
+func liveBitrateView(live *model.StreamInfo, listed int64) string {
+	if live == nil || live.BitrateKbps == 0 {
+		if listed == 0 {
+			return ""
+		}
+		return strconv.FormatInt(listed, 10)
+	}
+	diff := listed - int64(live.BitrateKbps)
+	if listed != 0 && max(diff, -diff)*20 > listed {
+		return fmt.Sprintf("%d (live, listed %d)", live.BitrateKbps, listed)
+	}
+	return fmt.Sprintf("%d (live)", live.BitrateKbps)
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/player/model"
+)
+
+func Test_liveViews(t *testing.T) {
+	live := &model.StreamInfo{Codec: "AAC", BitrateKbps: 63, SampleRate: 44100, Channels: 2}
+	if got, want := liveCodecView(live, "AAC+"), "AAC · 44.1 kHz stereo (live)"; got != want {
+		t.Errorf("got codec %q, want %q", got, want)
+	}
+	if got, want := liveCodecView(live, "MP3"), "AAC · 44.1 kHz stereo (live, listed MP3)"; got != want {
+		t.Errorf("got codec %q, want %q", got, want)
+	}
+	if got, want := liveCodecView(nil, "MP3"), "MP3"; got != want {
+		t.Errorf("got codec %q, want %q", got, want)
+	}
+	for listed, want := range map[int64]string{0: "63 (live)", 64: "63 (live)", 128: "63 (live, listed 128)"} {
+		if got := liveBitrateView(live, listed); got != want {
+			t.Errorf("got bitrate %q listed %d, want %q", got, listed, want)
+		}
+	}
+	if got := liveBitrateView(nil, 128); got != "128" {
+		t.Errorf("got bitrate %q, want the listed one", got)
+	}
+}
+
+func Test_e2eLiveStream(t *testing.T) {
+	stations := e2eStations(1, "Jazz", "jazz")
+	stations[0].Codec, stations[0].Bitrate = "AAC", 192
+	d := newUIDriver(t, stations...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 1 })
+	d.m.setMetadataPoll(config.MetadataPollManual)
+	d.keys("enter")
+	d.waitFor("the stream to play", func() bool { return d.m.connecting == nil && d.m.delegate.currPlaying != nil })
+
+	d.player.SetStream(model.StreamInfo{Codec: "MP3", BitrateKbps: 128, SampleRate: 44100, Channels: 2})
+	d.keys("U")
+	d.waitFor("the stream parameters", func() bool { return strings.Contains(d.view, "Jazz 0 · MP3 128 kbps") })
+
+	d.keys("i")
+	d.waitFor("the live codec", func() bool { return strings.Contains(d.view, "MP3 · 44.1 kHz stereo (live, listed AAC)") })
+	if !strings.Contains(d.view, "128 (live, listed 192)") {
+		t.Errorf("station info without the live bitrate:\n%s", d.view)
+	}
+}
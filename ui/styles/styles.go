@@ -10,26 +10,193 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/banner"
+	"github.com/muesli/termenv"
 )
 
 const (
 	TabGapDistance = 2
 	HeaderPadDist  = 2
 
-	FavChar      = "  ★"
 	AutoplayChar = " Auto"
-	PlayChar     = "\u2877"
-	PauseChar    = "\u28FF"
-	LineChar     = "\u2847"
+	DialFmt      = " #%d"
 )
 
+// the glyphs, replaced by words and ASCII in the plain text mode
+var (
+	FavChar   = "  ★"
+	LabelChar = " ●"
+	PlayChar  = "\u2877"
+	PauseChar = "\u28FF"
+	LineChar  = "\u2847"
+	// ActiveChar marks the active session or output
+	ActiveChar    = "● "
+	RecordingChar = "● "
+	ScheduleChar  = "◷ "
+	CastChar      = "⇢ "
+	BarChar       = "▇"
+	BannerBlocks  = banner.HalfBlocks
+	// SelectedChar marks the station under the cursor and the selected region of the radio map, which are
+	// told by colors and boxes unless the symbols are used
+	SelectedChar  = ""
+	SpinnerFrames = []string{"⡷", "⣧", "⣏", "⡟", "⡷", "⣧", "⣏", "⡟"}
+
+	// CursorMode is the mode of the cursor of the text inputs, static with the reduced motion
+	CursorMode = cursor.CursorBlink
+
+	plain bool
+	// symbols tell what the colors do: the line under the cursor, the labels, the active tab and the status level
+	symbols bool
+)
+
+// UseSymbols tells by symbols and words too what is told by colors, for the colorblind users.
+// It's called before the styles are created.
+func UseSymbols() {
+	symbols = true
+	SelectedChar = "> "
+}
+
+// UseReducedMotion stops the animations: the spinner shows its first frame and the cursors don't blink.
+// It's called before the styles are created.
+func UseReducedMotion() {
+	SpinnerFrames = SpinnerFrames[:1]
+	CursorMode = cursor.CursorStatic
+}
+
+// Symbols reports whether what is told by colors is also told by symbols and words.
+func Symbols() bool {
+	return symbols
+}
+
+// UseNerdFont replaces the indicators by the icons of the Nerd Fonts, for the terminals with one.
+// It's called before the styles are created.
+func UseNerdFont() {
+	FavChar = "  \uf005"
+	LabelChar = " \uf111"
+	PlayChar = "\uf04b"
+	PauseChar = "\uf04c"
+	ActiveChar = "\uf111 "
+	RecordingChar = "\uf111 "
+	ScheduleChar = "\uf017 "
+	CastChar = "\uf1eb "
+}
+
+// UseASCII replaces the glyphs by ASCII, for the terminals whose fonts lack the Unicode symbols, like the
+// Linux console. It's called before the styles are created.
+func UseASCII() {
+	FavChar = "  *"
+	LabelChar = " o"
+	PlayChar = ">"
+	PauseChar = "="
+	LineChar = "-"
+	ActiveChar = "* "
+	RecordingChar = "* "
+	ScheduleChar = "@ "
+	CastChar = "-> "
+	BarChar = "#"
+	BannerBlocks = banner.ASCIIBlocks
+	SpinnerFrames = []string{"|", "/", "-", "\\"}
+}
+
+// UsePlainText replaces the decorative glyphs and the box drawing borders by words and ASCII, for the
+// screen readers. It's called before the styles are created.
+func UsePlainText() {
+	UseSymbols()
+	plain = true
+	FavChar = " (favorite)"
+	PlayChar = "(playing)"
+	PauseChar = "(paused)"
+	LineChar = "-"
+	ActiveChar = "* "
+	RecordingChar = "Recording: "
+	ScheduleChar = "Scheduled: "
+	CastChar = "Casting to "
+	BarChar = "#"
+	SpinnerFrames = []string{"..."}
+}
+
+// border is b, or a blank one in the plain text mode.
+func border(b lipgloss.Border) lipgloss.Border {
+	if plain {
+		return lipgloss.HiddenBorder()
+	}
+	return b
+}
+
+// BoxBorder is the border of the boxes, blank in the plain text mode.
+func BoxBorder() lipgloss.Border {
+	return border(lipgloss.RoundedBorder())
+}
+
+// currentBorder marks the current line on its left, like the station playing, with * in the plain text mode.
+func currentBorder() lipgloss.Border {
+	if plain {
+		return lipgloss.Border{Left: "*"}
+	}
+	return lipgloss.BlockBorder()
+}
+
+// cursorBorder marks the line under the cursor on its left, where it's only told by colors otherwise.
+func cursorBorder() lipgloss.Border {
+	return lipgloss.Border{Left: ">"}
+}
+
+// tabBorder is blank, with the active tab in brackets when the symbols are used.
+func tabBorder(active bool) lipgloss.Border {
+	b := lipgloss.HiddenBorder()
+	if active && symbols {
+		b.Left, b.Right = "[", "]"
+	}
+	return b
+}
+
+// LabelColors are the colors of the favorites labels, from the first label on.
+var LabelColors = []lipgloss.CompleteAdaptiveColor{
+	fixedColor("#D13438", "#F0565A", "1", "9"),
+	fixedColor("#D9620F", "#F7893B", "9", "3"),
+	fixedColor("#B58900", "#F2C94C", "3", "11"),
+	fixedColor("#2E8B3D", "#5FC16F", "2", "10"),
+	fixedColor("#1F6FD1", "#4C9AFF", "4", "12"),
+	fixedColor("#7B3FC4", "#A874E8", "5", "13"),
+}
+
+// LabelDot renders the dot of the favorites label, the first label being 1, followed by its name when the
+// symbols are used, or only the name in the plain text mode.
+func LabelDot(label int, name string) string {
+	if label < 1 || label > len(LabelColors) {
+		return ""
+	}
+	if plain {
+		return " (" + name + ")"
+	}
+	dot := LabelChar
+	if symbols {
+		dot += " " + name
+	}
+	return lipgloss.NewStyle().Foreground(LabelColors[label-1]).Render(dot)
+}
+
+var (
+	warnColor     = fixedColor("#C28A00", "#E5B73B", "3", "11")
+	errorColor    = fixedColor("#B3261E", "#E2504A", "1", "9")
+	statusFgColor = fixedColor("#FFFFFF", "#1C1C1C", "15", "0")
+)
+
+// fixedColor is a color of the light and dark terminals, with its ANSI color for the 16 colors terminals.
+func fixedColor(light, dark, light16, dark16 string) lipgloss.CompleteAdaptiveColor {
+	return lipgloss.CompleteAdaptiveColor{
+		Light: lipgloss.CompleteColor{TrueColor: light, ANSI256: light, ANSI: light16},
+		Dark:  lipgloss.CompleteColor{TrueColor: dark, ANSI256: dark, ANSI: dark16},
+	}
+}
+
 type Style struct {
 	theme string
 
-	basePrimaryColor       lipgloss.AdaptiveColor
-	baseSecondaryColor     lipgloss.AdaptiveColor
-	invertedPrimaryColor   lipgloss.AdaptiveColor
-	invertedSecondaryColor lipgloss.AdaptiveColor
+	basePrimaryColor       lipgloss.CompleteAdaptiveColor
+	baseSecondaryColor     lipgloss.CompleteAdaptiveColor
+	invertedPrimaryColor   lipgloss.CompleteAdaptiveColor
+	invertedSecondaryColor lipgloss.CompleteAdaptiveColor
 
 	PrimaryColorStyle   lipgloss.Style
 	SecondaryColorStyle lipgloss.Style
@@ -38,8 +205,11 @@ type Style struct {
 	BaseBold       lipgloss.Style
 	DocStyle       lipgloss.Style
 	StatusBarStyle lipgloss.Style
-	ViewStyle      lipgloss.Style
-	NoItemsStyle   lipgloss.Style
+	// status messages of warnings and errors, in the same colors for every theme
+	StatusWarnStyle  lipgloss.Style
+	StatusErrorStyle lipgloss.Style
+	ViewStyle        lipgloss.Style
+	NoItemsStyle     lipgloss.Style
 
 	// station delegate
 	PrefixStyle                 lipgloss.Style
@@ -92,10 +262,10 @@ func NewStyle(themeIdx int) *Style {
 		DocStyle: lipgloss.NewStyle().
 			Padding(1, HeaderPadDist, 0, HeaderPadDist),
 		InactiveTabBorder: lipgloss.NewStyle().
-			Border(lipgloss.HiddenBorder(), true).
+			Border(tabBorder(false), true).
 			Padding(0, 0).Margin(0),
 		ActiveTabBorder: lipgloss.NewStyle().
-			Border(lipgloss.HiddenBorder(), true).
+			Border(tabBorder(true), true).
 			Padding(0, 0).Margin(0),
 	}
 	u.setTheme(t)
@@ -112,16 +282,18 @@ func (s *Style) SetThemeIdx(themeIdx int) {
 
 func (s *Style) setTheme(t Theme) {
 	s.theme = t.Name
-	s.basePrimaryColor = lipgloss.AdaptiveColor{Light: t.Light.primaryColor, Dark: t.Dark.primaryColor}
-	s.baseSecondaryColor = lipgloss.AdaptiveColor{Light: t.Light.secondaryColor, Dark: t.Dark.secondaryColor}
-	s.invertedPrimaryColor = lipgloss.AdaptiveColor{Light: t.Light.invertedPrimaryColor, Dark: t.Dark.invertedPrimaryColor}
-	s.invertedSecondaryColor = lipgloss.AdaptiveColor{Light: t.Light.invertedSecondaryColor, Dark: t.Dark.invertedSecondaryColor}
+	s.basePrimaryColor = fixedColor(t.Light.primaryColor, t.Dark.primaryColor, t.Light16.primaryColor, t.Dark16.primaryColor)
+	s.baseSecondaryColor = fixedColor(t.Light.secondaryColor, t.Dark.secondaryColor, t.Light16.secondaryColor, t.Dark16.secondaryColor)
+	s.invertedPrimaryColor = fixedColor(t.Light.invertedPrimaryColor, t.Dark.invertedPrimaryColor, t.Light16.invertedPrimaryColor, t.Dark16.invertedPrimaryColor)
+	s.invertedSecondaryColor = fixedColor(t.Light.invertedSecondaryColor, t.Dark.invertedSecondaryColor, t.Light16.invertedSecondaryColor, t.Dark16.invertedSecondaryColor)
 
 	s.PrimaryColorStyle = lipgloss.NewStyle().Foreground(s.basePrimaryColor)
 	s.SecondaryColorStyle = lipgloss.NewStyle().Foreground(s.baseSecondaryColor)
 	//
 	// general
 	s.StatusBarStyle = lipgloss.NewStyle().Background(s.baseSecondaryColor).Foreground(s.invertedPrimaryColor)
+	s.StatusWarnStyle = lipgloss.NewStyle().Bold(true).Background(warnColor).Foreground(statusFgColor)
+	s.StatusErrorStyle = lipgloss.NewStyle().Bold(true).Background(errorColor).Foreground(statusFgColor)
 	s.ViewStyle = s.SecondaryColorStyle.PaddingLeft(HeaderPadDist)
 	s.NoItemsStyle = s.SecondaryColorStyle.PaddingLeft(HeaderPadDist * 2)
 
@@ -133,7 +305,7 @@ func (s *Style) setTheme(t Theme) {
 	s.SelItemStyle = lipgloss.NewStyle().Background(s.basePrimaryColor).Foreground(s.invertedPrimaryColor)
 	s.SelDescStyle = lipgloss.NewStyle().Background(s.basePrimaryColor).Foreground(s.invertedSecondaryColor)
 	s.SelectedBorderStyle = lipgloss.NewStyle().
-		Border(lipgloss.BlockBorder(), false, false, false, true).
+		Border(currentBorder(), false, false, false, true).
 		BorderForeground(s.basePrimaryColor)
 	s.SelectedBorderStyleInactive = lipgloss.NewStyle().Inherit(s.SelectedBorderStyle).BorderForeground(s.baseSecondaryColor)
 
@@ -170,7 +342,7 @@ func (s *Style) setTheme(t Theme) {
 	// help
 	s.HelpStyle = lipgloss.NewStyle().
 		Padding(0, 0).Margin(0).
-		Border(lipgloss.NormalBorder()).
+		Border(border(lipgloss.NormalBorder())).
 		BorderForeground(s.basePrimaryColor)
 
 	prompyStyleBase := s.PrimaryColorStyle.Bold(true)
@@ -190,20 +362,26 @@ func (s *Style) setTheme(t Theme) {
 	s.HistoryDescStyle = s.PrimaryColorStyle.Bold(true)
 	s.HistorySelItemStyle = s.SelDescStyle
 	s.HistorySelDescStyle = s.SelItemStyle.Bold(true)
+	if symbols {
+		// the selection is otherwise only told by the colors
+		s.HistorySelItemStyle = s.HistorySelItemStyle.Border(cursorBorder(), false, false, false, true)
+	}
 
 	// settings
 	s.SettingDescription = s.SecondaryColorStyle.
 		Padding(0, 1, 1, 1).
-		Border(lipgloss.NormalBorder(), true, false, false).BorderForeground(s.basePrimaryColor)
+		Border(border(lipgloss.NormalBorder()), true, false, false).BorderForeground(s.basePrimaryColor)
 }
 
 func (s *Style) GetSecondColor() string {
-	hasDark := lipgloss.DefaultRenderer().HasDarkBackground()
-	if hasDark {
-		return s.baseSecondaryColor.Dark
-	} else {
-		return s.baseSecondaryColor.Light
+	c := s.baseSecondaryColor.Light
+	if lipgloss.DefaultRenderer().HasDarkBackground() {
+		c = s.baseSecondaryColor.Dark
+	}
+	if lipgloss.ColorProfile() == termenv.ANSI {
+		return c.ANSI
 	}
+	return c.TrueColor
 }
 
 func (s *Style) HelpStyles() help.Styles {
@@ -226,7 +404,7 @@ func (s *Style) NewInputModel(
 	validator textinput.ValidateFunc,
 ) textinput.Model {
 	input := textinput.New()
-	input.Cursor.SetMode(cursor.CursorBlink)
+	input.Cursor.SetMode(CursorMode)
 	prompt = PadFieldName(prompt, nil)
 	s.TextInputSyle(&input, prompt, placeholder)
 	input.PromptStyle = s.PromptStyle
@@ -289,3 +467,13 @@ func IndexString(index int) string {
 	}
 	return prefix
 }
+
+// MarkSelected puts the SelectedChar in front of the index prefix of the selected line, in its padding.
+func MarkSelected(prefix string) string {
+	if SelectedChar == "" {
+		return prefix
+	}
+	index := strings.TrimLeft(prefix, " ")
+	pad := max(0, len(prefix)-len(index)-len(SelectedChar))
+	return SelectedChar + strings.Repeat(" ", pad) + index
+}
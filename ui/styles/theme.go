@@ -11,47 +11,82 @@ type Theme struct {
 	Name  string
 	Dark  ColorProfile
 	Light ColorProfile
+	// the ANSI colors of the 16 colors terminals, whose nearest colors to the hex ones can't be told apart
+	Dark16  ColorProfile
+	Light16 ColorProfile
 }
 
 var Themes = []Theme{
 	{
-		Name:  "Duo Yellow",
-		Dark:  ColorProfile{primaryColor: "#D4DAF7", secondaryColor: "#D58610", invertedPrimaryColor: "#2D2D0B", invertedSecondaryColor: "#827545"},
-		Light: ColorProfile{primaryColor: "#2D2D0B", secondaryColor: "#827545", invertedPrimaryColor: "#D4DAF7", invertedSecondaryColor: "#D58610"},
+		Name:    "Duo Yellow",
+		Dark:    ColorProfile{primaryColor: "#D4DAF7", secondaryColor: "#D58610", invertedPrimaryColor: "#2D2D0B", invertedSecondaryColor: "#827545"},
+		Light:   ColorProfile{primaryColor: "#2D2D0B", secondaryColor: "#827545", invertedPrimaryColor: "#D4DAF7", invertedSecondaryColor: "#D58610"},
+		Dark16:  ColorProfile{primaryColor: "15", secondaryColor: "3", invertedPrimaryColor: "0", invertedSecondaryColor: "8"},
+		Light16: ColorProfile{primaryColor: "0", secondaryColor: "3", invertedPrimaryColor: "15", invertedSecondaryColor: "11"},
 	},
 	{
-		Name:  "Duo Green",
-		Dark:  ColorProfile{primaryColor: "#F7D4D6", secondaryColor: "#6b9e47", invertedPrimaryColor: "#243518", invertedSecondaryColor: "#3c5828"},
-		Light: ColorProfile{primaryColor: "#243518", secondaryColor: "#3c5828", invertedPrimaryColor: "#F7D4D6", invertedSecondaryColor: "#6b9e47"},
+		Name:    "Duo Green",
+		Dark:    ColorProfile{primaryColor: "#F7D4D6", secondaryColor: "#6b9e47", invertedPrimaryColor: "#243518", invertedSecondaryColor: "#3c5828"},
+		Light:   ColorProfile{primaryColor: "#243518", secondaryColor: "#3c5828", invertedPrimaryColor: "#F7D4D6", invertedSecondaryColor: "#6b9e47"},
+		Dark16:  ColorProfile{primaryColor: "15", secondaryColor: "2", invertedPrimaryColor: "0", invertedSecondaryColor: "2"},
+		Light16: ColorProfile{primaryColor: "0", secondaryColor: "2", invertedPrimaryColor: "15", invertedSecondaryColor: "10"},
 	},
 	{
-		Name:  "Duo Blue",
-		Dark:  ColorProfile{primaryColor: "#F7EDD4", secondaryColor: "#6d9edf", invertedPrimaryColor: "#1c467d", invertedSecondaryColor: "#2969bc"},
-		Light: ColorProfile{primaryColor: "#1c467d", secondaryColor: "#2969bc", invertedPrimaryColor: "#F7EDD4", invertedSecondaryColor: "#6d9edf"},
+		Name:    "Duo Blue",
+		Dark:    ColorProfile{primaryColor: "#F7EDD4", secondaryColor: "#6d9edf", invertedPrimaryColor: "#1c467d", invertedSecondaryColor: "#2969bc"},
+		Light:   ColorProfile{primaryColor: "#1c467d", secondaryColor: "#2969bc", invertedPrimaryColor: "#F7EDD4", invertedSecondaryColor: "#6d9edf"},
+		Dark16:  ColorProfile{primaryColor: "15", secondaryColor: "12", invertedPrimaryColor: "0", invertedSecondaryColor: "4"},
+		Light16: ColorProfile{primaryColor: "4", secondaryColor: "12", invertedPrimaryColor: "15", invertedSecondaryColor: "7"},
 	},
 	{
-		Name:  "Duo Red",
-		Dark:  ColorProfile{primaryColor: "#E3F7D4", secondaryColor: "#DE5145", invertedPrimaryColor: "#351D10", invertedSecondaryColor: "#8C4D2B"},
-		Light: ColorProfile{primaryColor: "#351D10", secondaryColor: "#8C4D2B", invertedPrimaryColor: "#E3F7D4", invertedSecondaryColor: "#DE5145"},
+		Name:    "Duo Red",
+		Dark:    ColorProfile{primaryColor: "#E3F7D4", secondaryColor: "#DE5145", invertedPrimaryColor: "#351D10", invertedSecondaryColor: "#8C4D2B"},
+		Light:   ColorProfile{primaryColor: "#351D10", secondaryColor: "#8C4D2B", invertedPrimaryColor: "#E3F7D4", invertedSecondaryColor: "#DE5145"},
+		Dark16:  ColorProfile{primaryColor: "15", secondaryColor: "9", invertedPrimaryColor: "0", invertedSecondaryColor: "1"},
+		Light16: ColorProfile{primaryColor: "0", secondaryColor: "1", invertedPrimaryColor: "15", invertedSecondaryColor: "9"},
 	},
 	{
-		Name:  "Mono Yellow",
-		Dark:  ColorProfile{primaryColor: "#ffb641", secondaryColor: "#bd862d", invertedPrimaryColor: "#12100d", invertedSecondaryColor: "#4a4133"},
-		Light: ColorProfile{primaryColor: "#12100d", secondaryColor: "#4a4133", invertedPrimaryColor: "#ffb641", invertedSecondaryColor: "#bd862d"},
+		Name:    "Mono Yellow",
+		Dark:    ColorProfile{primaryColor: "#ffb641", secondaryColor: "#bd862d", invertedPrimaryColor: "#12100d", invertedSecondaryColor: "#4a4133"},
+		Light:   ColorProfile{primaryColor: "#12100d", secondaryColor: "#4a4133", invertedPrimaryColor: "#ffb641", invertedSecondaryColor: "#bd862d"},
+		Dark16:  ColorProfile{primaryColor: "11", secondaryColor: "3", invertedPrimaryColor: "0", invertedSecondaryColor: "8"},
+		Light16: ColorProfile{primaryColor: "0", secondaryColor: "8", invertedPrimaryColor: "11", invertedSecondaryColor: "3"},
 	},
 	{
-		Name:  "Mono Green",
-		Dark:  ColorProfile{primaryColor: "#98c379", secondaryColor: "#6b9e47", invertedPrimaryColor: "#243518", invertedSecondaryColor: "#3c5828"},
-		Light: ColorProfile{primaryColor: "#243518", secondaryColor: "#3c5828", invertedPrimaryColor: "#98c379", invertedSecondaryColor: "#6b9e47"},
+		Name:    "Mono Green",
+		Dark:    ColorProfile{primaryColor: "#98c379", secondaryColor: "#6b9e47", invertedPrimaryColor: "#243518", invertedSecondaryColor: "#3c5828"},
+		Light:   ColorProfile{primaryColor: "#243518", secondaryColor: "#3c5828", invertedPrimaryColor: "#98c379", invertedSecondaryColor: "#6b9e47"},
+		Dark16:  ColorProfile{primaryColor: "10", secondaryColor: "2", invertedPrimaryColor: "0", invertedSecondaryColor: "8"},
+		Light16: ColorProfile{primaryColor: "0", secondaryColor: "2", invertedPrimaryColor: "10", invertedSecondaryColor: "2"},
 	},
 	{
-		Name:  "Mono Blue",
-		Dark:  ColorProfile{primaryColor: "#abc8ed", secondaryColor: "#6d9edf", invertedPrimaryColor: "#1c467d", invertedSecondaryColor: "#2969bc"},
-		Light: ColorProfile{primaryColor: "#1c467d", secondaryColor: "#2969bc", invertedPrimaryColor: "#abc8ed", invertedSecondaryColor: "#6d9edf"},
+		Name:    "Mono Blue",
+		Dark:    ColorProfile{primaryColor: "#abc8ed", secondaryColor: "#6d9edf", invertedPrimaryColor: "#1c467d", invertedSecondaryColor: "#2969bc"},
+		Light:   ColorProfile{primaryColor: "#1c467d", secondaryColor: "#2969bc", invertedPrimaryColor: "#abc8ed", invertedSecondaryColor: "#6d9edf"},
+		Dark16:  ColorProfile{primaryColor: "14", secondaryColor: "12", invertedPrimaryColor: "0", invertedSecondaryColor: "4"},
+		Light16: ColorProfile{primaryColor: "4", secondaryColor: "12", invertedPrimaryColor: "15", invertedSecondaryColor: "14"},
 	},
 	{
-		Name:  "Mono Red",
-		Dark:  ColorProfile{primaryColor: "#e48189", secondaryColor: "#d7424e", invertedPrimaryColor: "#69161d", invertedSecondaryColor: "#931f29"},
-		Light: ColorProfile{primaryColor: "#69161d", secondaryColor: "#931f29", invertedPrimaryColor: "#e48189", invertedSecondaryColor: "#d7424e"},
+		Name:    "Mono Red",
+		Dark:    ColorProfile{primaryColor: "#e48189", secondaryColor: "#d7424e", invertedPrimaryColor: "#69161d", invertedSecondaryColor: "#931f29"},
+		Light:   ColorProfile{primaryColor: "#69161d", secondaryColor: "#931f29", invertedPrimaryColor: "#e48189", invertedSecondaryColor: "#d7424e"},
+		Dark16:  ColorProfile{primaryColor: "9", secondaryColor: "1", invertedPrimaryColor: "0", invertedSecondaryColor: "15"},
+		Light16: ColorProfile{primaryColor: "1", secondaryColor: "9", invertedPrimaryColor: "15", invertedSecondaryColor: "7"},
+	},
+	// the accessible themes, with a WCAG contrast of 7 for the high contrast one and 4.5 for the
+	// colorblind safe one, from the Okabe-Ito palette darkened on the light terminals, checked by the tests
+	{
+		Name:    "High Contrast",
+		Dark:    ColorProfile{primaryColor: "#FFFFFF", secondaryColor: "#FFD700", invertedPrimaryColor: "#000000", invertedSecondaryColor: "#1A1A1A"},
+		Light:   ColorProfile{primaryColor: "#000000", secondaryColor: "#003A8C", invertedPrimaryColor: "#FFFFFF", invertedSecondaryColor: "#F0F0F0"},
+		Dark16:  ColorProfile{primaryColor: "15", secondaryColor: "11", invertedPrimaryColor: "0", invertedSecondaryColor: "0"},
+		Light16: ColorProfile{primaryColor: "0", secondaryColor: "4", invertedPrimaryColor: "15", invertedSecondaryColor: "15"},
+	},
+	{
+		Name:    "Colorblind Safe",
+		Dark:    ColorProfile{primaryColor: "#56B4E9", secondaryColor: "#E69F00", invertedPrimaryColor: "#000000", invertedSecondaryColor: "#202020"},
+		Light:   ColorProfile{primaryColor: "#005F99", secondaryColor: "#8F4000", invertedPrimaryColor: "#FFFFFF", invertedSecondaryColor: "#F5F5F5"},
+		Dark16:  ColorProfile{primaryColor: "14", secondaryColor: "3", invertedPrimaryColor: "0", invertedSecondaryColor: "0"},
+		Light16: ColorProfile{primaryColor: "4", secondaryColor: "3", invertedPrimaryColor: "15", invertedSecondaryColor: "15"},
 	},
 }
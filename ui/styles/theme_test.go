@@ -0,0 +1,133 @@
+package styles
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// the backgrounds assumed for the dark and light terminals
+const (
+	darkBackground  = "#000000"
+	lightBackground = "#FFFFFF"
+)
+
+// cvdMatrices simulate the color vision deficiencies on the linear RGB, from Machado et al. 2009 at full severity.
+var cvdMatrices = map[string][3][3]float64{
+	"none": {{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+	"protanopia": {
+		{0.152286, 1.052583, -0.204868}, {0.114503, 0.786281, 0.099216}, {-0.003882, -0.048116, 1.051998},
+	},
+	"deuteranopia": {
+		{0.367322, 0.860646, -0.227968}, {0.280085, 0.672501, 0.047413}, {-0.011820, 0.042940, 0.968881},
+	},
+	"tritanopia": {
+		{1.255528, -0.076749, -0.178779}, {-0.078411, 0.930809, 0.147602}, {0.004733, 0.691367, 0.303900},
+	},
+}
+
+func linearRGB(t *testing.T, hex string) [3]float64 {
+	t.Helper()
+	v, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil || len(hex) != 7 {
+		t.Fatalf("invalid color %q", hex)
+	}
+	var res [3]float64
+	for i := range res {
+		c := float64(v>>(16-8*i)&0xff) / 255
+		if c <= 0.04045 {
+			res[i] = c / 12.92
+		} else {
+			res[i] = math.Pow((c+0.055)/1.055, 2.4)
+		}
+	}
+	return res
+}
+
+func luminance(rgb [3]float64, cvd [3][3]float64) float64 {
+	var sim [3]float64
+	for i := range sim {
+		sim[i] = math.Min(1, math.Max(0, cvd[i][0]*rgb[0]+cvd[i][1]*rgb[1]+cvd[i][2]*rgb[2]))
+	}
+	return 0.2126*sim[0] + 0.7152*sim[1] + 0.0722*sim[2]
+}
+
+// contrast is the WCAG contrast ratio of the colors, as seen with the color vision deficiency.
+func contrast(t *testing.T, a, b string, cvd [3][3]float64) float64 {
+	la, lb := luminance(linearRGB(t, a), cvd), luminance(linearRGB(t, b), cvd)
+	return (math.Max(la, lb) + 0.05) / (math.Min(la, lb) + 0.05)
+}
+
+func TestAccessibleThemes(t *testing.T) {
+	minContrast := map[string]float64{
+		"High Contrast":   7,
+		"Colorblind Safe": 4.5,
+	}
+	for _, theme := range Themes {
+		want, ok := minContrast[theme.Name]
+		if !ok {
+			continue
+		}
+		delete(minContrast, theme.Name)
+		for profileName, p := range map[string]struct {
+			ColorProfile
+			background string
+		}{
+			"dark":  {theme.Dark, darkBackground},
+			"light": {theme.Light, lightBackground},
+		} {
+			// the foreground and background of the texts: in the lists, the selected items, the status bar and the active tab
+			pairs := [][2]string{
+				{p.primaryColor, p.background},
+				{p.secondaryColor, p.background},
+				{p.invertedPrimaryColor, p.primaryColor},
+				{p.invertedSecondaryColor, p.primaryColor},
+				{p.invertedPrimaryColor, p.secondaryColor},
+			}
+			for cvdName, cvd := range cvdMatrices {
+				for _, pair := range pairs {
+					if got := contrast(t, pair[0], pair[1], cvd); got < want {
+						t.Errorf("%s %s with %s: contrast of %s on %s is %.2f, want %.1f",
+							theme.Name, profileName, cvdName, pair[0], pair[1], got, want)
+					}
+				}
+			}
+		}
+	}
+	for name := range minContrast {
+		t.Errorf("no theme %q", name)
+	}
+}
+
+func TestThemes16Colors(t *testing.T) {
+	// the ANSI colors unreadable on the dark and light terminals
+	unreadable := map[string][]string{
+		"dark":  {"0", "8"},
+		"light": {"15", "7"},
+	}
+	for _, theme := range Themes {
+		for profileName, p := range map[string]ColorProfile{"dark": theme.Dark16, "light": theme.Light16} {
+			for _, c := range []string{p.primaryColor, p.secondaryColor, p.invertedPrimaryColor, p.invertedSecondaryColor} {
+				if n, err := strconv.Atoi(c); err != nil || n < 0 || n > 15 {
+					t.Errorf("%s %s: %q is not an ANSI color", theme.Name, profileName, c)
+				}
+			}
+			if slices.Contains(unreadable[profileName], p.primaryColor) || slices.Contains(unreadable[profileName], p.secondaryColor) {
+				t.Errorf("%s %s: texts in %s and %s", theme.Name, profileName, p.primaryColor, p.secondaryColor)
+			}
+			// the texts of the lists, the selected items, the status bar and the active tab
+			pairs := [][2]string{
+				{p.primaryColor, p.secondaryColor},
+				{p.invertedPrimaryColor, p.primaryColor},
+				{p.invertedSecondaryColor, p.primaryColor},
+				{p.invertedPrimaryColor, p.secondaryColor},
+			}
+			for _, pair := range pairs {
+				if pair[0] == pair[1] {
+					t.Errorf("%s %s: same color %s for the text and its background", theme.Name, profileName, pair[0])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package ui
+
+import "testing"
+
+func Test_e2eSwapStation(t *testing.T) {
+	tests := []struct {
+		name   string
+		played []int
+		pause  bool
+		// swaps are the stations expected playing after each swap key, -1 for the warning
+		swaps []int
+	}{
+		{name: "nothing played", swaps: []int{-1}},
+		{name: "one station", played: []int{0}, swaps: []int{-1}},
+		{name: "first and last", played: []int{0, 2}, swaps: []int{0, 2, 0}},
+		{name: "same station replayed", played: []int{0, 1, 1}, swaps: []int{0, 1}},
+		{name: "paused", played: []int{0, 1}, pause: true, swaps: []int{0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stations := e2eStations(3, "Jazz", "jazz")
+			d := newUIDriver(t, stations...)
+			d.waitFor("the top stations", func() bool { return d.listed() == 3 })
+			play := func(desc string, i int) {
+				t.Helper()
+				d.waitFor(desc, func() bool {
+					return d.m.connecting == nil && d.m.delegate.playingUuid() == stations[i].Stationuuid
+				})
+			}
+			for _, i := range tt.played {
+				d.run(d.m.playStationCmd(stations[i]))
+				play("the station to play", i)
+			}
+			if tt.pause {
+				d.keys(" ")
+				d.waitFor("the pause", func() bool { _, paused := d.m.delegate.nowPlaying(); return paused })
+			}
+
+			for _, want := range tt.swaps {
+				d.m.statusMsg = ""
+				d.keys("b")
+				if want < 0 {
+					if d.m.statusMsg != noLastStationMsg {
+						t.Errorf("got status %q, want %q", d.m.statusMsg, noLastStationMsg)
+					}
+					continue
+				}
+				play("the swapped station", want)
+			}
+		})
+	}
+}
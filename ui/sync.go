@@ -0,0 +1,287 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/gitsync"
+	"github.com/dancnb/sonicradio/webdav"
+)
+
+const (
+	syncErrMsg = "Could not sync the favorites: %v"
+	syncedMsg  = "Favorites synced"
+	noSyncMsg  = "No sync set up in the config file"
+)
+
+// gitSyncMtx keeps a manual sync from running in the same clone as the periodic one.
+var gitSyncMtx sync.Mutex
+
+// syncAllCmd syncs now with all the sync backends set up, besides the periodic syncs.
+func (m *Model) syncAllCmd() tea.Cmd {
+	if m.cfg.SyncFile == "" && m.cfg.SyncGit == "" && m.cfg.SyncWebdav == nil {
+		m.updateStatusWarn(noSyncMsg)
+		return nil
+	}
+	var cmds []tea.Cmd
+	if m.cfg.SyncFile != "" {
+		path := m.cfg.SyncFile
+		cmds = append(cmds, func() tea.Msg {
+			d, err := config.ReadSync(path)
+			return syncReadMsg{data: d, err: err, manual: true}
+		})
+	}
+	if m.cfg.SyncGit != "" {
+		cmds = append(cmds, m.onGitSyncTick(true))
+	}
+	if m.cfg.SyncWebdav != nil {
+		cmds = append(cmds, m.webdavReadCmd(true))
+	}
+	return tea.Batch(cmds...)
+}
+
+// syncInterval is the interval of the syncs with the sync file, on the tick while one is set.
+func (m *Model) syncInterval() time.Duration {
+	if m.cfg.SyncFile == "" {
+		return 0
+	}
+	return config.SyncInterval
+}
+
+// syncCmd reads the sync file outside of the update. The file is merged with the favorites and the
+// history in the update, then written back if it changed.
+func (m *Model) syncCmd(time.Time) tea.Cmd {
+	path := m.cfg.SyncFile
+	return func() tea.Msg {
+		d, err := config.ReadSync(path)
+		return syncReadMsg{data: d, err: err}
+	}
+}
+
+func (m *Model) onSyncRead(msg syncReadMsg) tea.Cmd {
+	log := slog.With("method", "ui.Model.onSyncRead")
+	if msg.err != nil {
+		log.Error("read sync file", "error", msg.err)
+		m.updateStatusWarn(fmt.Sprintf(syncErrMsg, msg.err))
+		return nil
+	}
+	merged, changed := m.cfg.MergeSync(msg.data)
+	return tea.Batch(m.writeSyncCmd(merged), m.onSynced(changed, msg.manual))
+}
+
+// onSynced reloads the favorites and the history after they changed by a sync.
+func (m *Model) onSynced(changed, manual bool) tea.Cmd {
+	if manual {
+		m.updateStatus(syncedMsg)
+	}
+	if !changed {
+		return nil
+	}
+	slog.With("method", "ui.Model.onSynced").Info("favorites or history changed by the sync")
+	return tea.Batch(m.tabs[historyTabIx].(*historyTab).setEntries(m.cfg.History), m.reloadSyncedFavorites)
+}
+
+func (m *Model) reloadSyncedFavorites() tea.Msg {
+	msg := m.favoritesReqCmd().(favoritesStationRespMsg)
+	msg.synced = true
+	return msg
+}
+
+// gitSyncInterval is the interval of the push and pull of the favorites through the git remote, on the
+// tick while one is set.
+func (m *Model) gitSyncInterval() time.Duration {
+	if m.cfg.SyncGit == "" {
+		return 0
+	}
+	return config.GitSyncInterval
+}
+
+func (m *Model) gitSyncCmd(time.Time) tea.Cmd {
+	return m.onGitSyncTick(false)
+}
+
+// onGitSyncTick runs the git sync outside of the update, with the favorites at the time of the
+// tick: they can change while it runs.
+func (m *Model) onGitSyncTick(manual bool) tea.Cmd {
+	remote := m.cfg.SyncGit
+	snapshot := slices.Clone(m.cfg.Favorites)
+	return func() tea.Msg {
+		favorites, err := gitSync(remote, snapshot)
+		return gitSyncMsg{snapshot: snapshot, favorites: favorites, err: err, manual: manual}
+	}
+}
+
+func gitSync(remote string, favorites []string) ([]string, error) {
+	gitSyncMtx.Lock()
+	defer gitSyncMtx.Unlock()
+	dir, err := config.GitSyncDir()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), config.GitSyncTimeout)
+	defer cancel()
+	return gitsync.Sync(ctx, dir, remote, favorites)
+}
+
+func (m *Model) onGitSync(msg gitSyncMsg) tea.Cmd {
+	log := slog.With("method", "ui.Model.onGitSync")
+	if msg.err != nil {
+		log.Error("git sync", "error", msg.err)
+		m.updateStatusWarn(fmt.Sprintf(syncErrMsg, msg.err))
+		return nil
+	}
+	favorites := msg.favorites
+	if !slices.Equal(m.cfg.Favorites, msg.snapshot) {
+		favorites = gitsync.Merge(msg.snapshot, m.cfg.Favorites, msg.favorites)
+	}
+	changed := !slices.Equal(favorites, m.cfg.Favorites)
+	if changed {
+		m.cfg.SetFavorites(favorites)
+	}
+	return m.onSynced(changed, msg.manual)
+}
+
+// webdavSyncInterval is the interval of the syncs with the WebDAV file, on the tick while one is set.
+func (m *Model) webdavSyncInterval() time.Duration {
+	if m.cfg.SyncWebdav == nil {
+		return 0
+	}
+	return config.WebdavSyncInterval
+}
+
+func (m *Model) webdavSyncCmd(time.Time) tea.Cmd {
+	return m.webdavReadCmd(false)
+}
+
+func newWebdavClient(s *config.WebdavSync) *webdav.Client {
+	return webdav.New(s.URL, s.User, s.Password)
+}
+
+// webdavReadCmd reads the WebDAV file, which is merged like the sync file in the update.
+func (m *Model) webdavReadCmd(manual bool) tea.Cmd {
+	c := newWebdavClient(m.cfg.SyncWebdav)
+	return func() tea.Msg {
+		msg := webdavReadMsg{manual: manual}
+		msg.file, msg.err = c.Get(context.Background())
+		if msg.err == nil {
+			msg.data, msg.err = decodeWebdavFile(msg.file)
+		}
+		return msg
+	}
+}
+
+func decodeWebdavFile(f *webdav.File) (*config.SyncData, error) {
+	if f == nil {
+		return &config.SyncData{}, nil
+	}
+	return config.DecodeSync(f.Data)
+}
+
+func (m *Model) onWebdavRead(msg webdavReadMsg) tea.Cmd {
+	log := slog.With("method", "ui.Model.onWebdavRead")
+	if msg.err != nil {
+		log.Error("read webdav file", "error", msg.err)
+		m.updateStatusWarn(fmt.Sprintf(syncErrMsg, msg.err))
+		return nil
+	}
+	merged, changed := m.cfg.MergeSync(msg.data)
+	b, write, err := config.EncodeSync(merged)
+	if err != nil {
+		log.Error("encode webdav file", "error", err)
+		return nil
+	}
+	var put tea.Cmd
+	if write {
+		c := newWebdavClient(m.cfg.SyncWebdav)
+		put = func() tea.Msg {
+			err := c.Put(context.Background(), b, msg.file)
+			return webdavPutMsg{err: err, manual: msg.manual}
+		}
+	}
+	return tea.Batch(put, m.onSynced(changed, msg.manual))
+}
+
+func (m *Model) onWebdavPut(msg webdavPutMsg) tea.Cmd {
+	log := slog.With("method", "ui.Model.onWebdavPut")
+	if errors.Is(msg.err, webdav.ErrConflict) {
+		// changed by another machine since it was read, merged again
+		log.Info("webdav file changed, syncing again")
+		return m.webdavReadCmd(msg.manual)
+	} else if msg.err != nil {
+		log.Error("write webdav file", "error", msg.err)
+		m.updateStatusWarn(fmt.Sprintf(syncErrMsg, msg.err))
+	}
+	return nil
+}
+
+// webdavSync merges the last changes into the WebDAV file, while quitting.
+func (m *Model) webdavSync() error {
+	ctx, cancel := context.WithTimeout(context.Background(), config.WebdavSyncTimeout)
+	defer cancel()
+	c := newWebdavClient(m.cfg.SyncWebdav)
+	for {
+		f, err := c.Get(ctx)
+		if err != nil {
+			return err
+		}
+		d, err := decodeWebdavFile(f)
+		if err != nil {
+			return err
+		}
+		merged, _ := m.cfg.MergeSync(d)
+		b, write, err := config.EncodeSync(merged)
+		if err != nil || !write {
+			return err
+		}
+		if err := c.Put(ctx, b, f); !errors.Is(err, webdav.ErrConflict) {
+			return err
+		}
+	}
+}
+
+func (m *Model) writeSyncCmd(d *config.SyncData) tea.Cmd {
+	path := m.cfg.SyncFile
+	return func() tea.Msg {
+		if err := config.WriteSync(path, d); err != nil {
+			slog.With("method", "ui.Model.writeSyncCmd").Error("write sync file", "error", err)
+			return warnMsg(fmt.Sprintf(syncErrMsg, err))
+		}
+		return nil
+	}
+}
+
+// syncNow merges the last changes into the sync file, the git remote and the WebDAV file on quit.
+func (m *Model) syncNow() {
+	log := slog.With("method", "ui.Model.syncNow")
+	if m.cfg.SyncGit != "" {
+		if favorites, err := gitSync(m.cfg.SyncGit, m.cfg.Favorites); err != nil {
+			log.Error("git sync", "error", err)
+		} else {
+			m.cfg.SetFavorites(favorites)
+		}
+	}
+	if m.cfg.SyncWebdav != nil {
+		if err := m.webdavSync(); err != nil {
+			log.Error("webdav sync", "error", err)
+		}
+	}
+	if m.cfg.SyncFile == "" {
+		return
+	}
+	d, err := config.ReadSync(m.cfg.SyncFile)
+	if err != nil {
+		log.Error("read sync file", "error", err)
+		return
+	}
+	merged, _ := m.cfg.MergeSync(d)
+	if err := config.WriteSync(m.cfg.SyncFile, merged); err != nil {
+		log.Error("write sync file", "error", err)
+	}
+}
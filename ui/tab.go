@@ -2,6 +2,7 @@ package ui
 
 import (
 	"log/slog"
+	"slices"
 	"strconv"
 
 	"github.com/dancnb/sonicradio/ui/components"
@@ -15,7 +16,7 @@ import (
 
 const (
 	stationsFilterPrompt      = "Filter:       "
-	stationsFilterPlaceholder = "station name"
+	stationsFilterPlaceholder = "station name, tag:jazz country:de, /regex/"
 )
 
 type uiTabIndex uint8
@@ -28,6 +29,8 @@ func (t uiTabIndex) String() string {
 		return "  Browse  "
 	case historyTabIx:
 		return "  History  "
+	case recordingsTabIx:
+		return " Recordings "
 	case settingsTabIx:
 		return " Settings "
 	}
@@ -38,6 +41,7 @@ const (
 	favoriteTabIx uiTabIndex = iota
 	browseTabIx
 	historyTabIx
+	recordingsTabIx
 	settingsTabIx
 )
 
@@ -68,6 +72,9 @@ type stationsTabBase struct {
 	listKeymap listKeymap
 	jump       components.JumpInfo
 	infoModel  *infoModel
+	window     stationWindow
+	// filterItems are the stations of the list when the filter started, for the field scoped terms
+	filterItems []browser.Station
 }
 
 func newStationsTab(k listKeymap, infoModel *infoModel, s *styles.Style) stationsTabBase {
@@ -82,11 +89,76 @@ func newStationsTab(k listKeymap, infoModel *infoModel, s *styles.Style) station
 func (t *stationsTabBase) doJump(msg tea.KeyMsg) {
 	digit, _ := strconv.Atoi(msg.String())
 	jumpIdx := t.jump.NewPosition(digit)
+	if missing := jumpIdx - len(t.list.Items()); missing > 0 {
+		t.window.extend(&t.list, missing)
+	}
 	if jumpIdx > 0 && jumpIdx <= len(t.list.Items()) {
 		t.list.Select(jumpIdx - 1)
 	}
 }
 
+func (t *stationsTabBase) setStations(stations []browser.Station) tea.Cmd {
+	items := t.window.reset(stations)
+	cmd := t.list.SetItems(items)
+	// the applied filter runs again on the new stations
+	t.setFilterItems()
+	t.list.Select(0)
+	return cmd
+}
+
+// addStations appends the stations to the list, e.g. the next pages of a search.
+func (t *stationsTabBase) addStations(stations []browser.Station) tea.Cmd {
+	cmd := t.window.add(&t.list, stations)
+	t.setFilterItems()
+	return cmd
+}
+
+func (t *stationsTabBase) setFilterItems() {
+	items := t.list.Items()
+	t.filterItems = make([]browser.Station, len(items))
+	for i := range items {
+		t.filterItems[i], _ = items[i].(browser.Station)
+	}
+}
+
+// filter matches the stations by the fields of the term too, like `tag:jazz country:de`, and by regular expressions.
+// It runs outside of the update loop, so it only reads the stations set when the filter started.
+func (t *stationsTabBase) filter(term string, targets []string) []list.Rank {
+	if len(t.filterItems) != len(targets) {
+		return list.DefaultFilter(term, targets)
+	}
+	return rankStations(term, t.filterItems, targets)
+}
+
+// rankStations filters the stations by the field terms and regular expressions of the term, ranking them
+// by the fuzzy match of the rest of the term with their texts. Without field terms, only the texts are matched.
+func rankStations(term string, stations []browser.Station, texts []string) []list.Rank {
+	f, err := browser.ParseFilter(term)
+	if err != nil || !f.Scoped() {
+		return list.DefaultFilter(term, texts)
+	}
+	var idx []int
+	var matched []string
+	for i := range stations {
+		if f.Match(stations[i]) {
+			idx = append(idx, i)
+			matched = append(matched, texts[i])
+		}
+	}
+	var ranks []list.Rank
+	if f.Text == "" {
+		for _, i := range idx {
+			ranks = append(ranks, list.Rank{Index: i})
+		}
+		return ranks
+	}
+	ranks = list.DefaultFilter(f.Text, matched)
+	for i := range ranks {
+		ranks[i].Index = idx[ranks[i].Index]
+	}
+	return ranks
+}
+
 func (t *stationsTabBase) View() string {
 	if t.viewMsg != "" {
 		var sections []string
@@ -107,34 +179,43 @@ func (t *stationsTabBase) IsFiltering() bool {
 	return t.list.FilterState() == list.Filtering
 }
 
-func (t *stationsTabBase) toNowPlaying(m *Model) {
+// toNowPlaying moves the cursor to the playing station, keeping the filter unless it hides the station.
+func (t *stationsTabBase) toNowPlaying(m *Model) tea.Cmd {
 	log := slog.With("method", "ui.stationsTabBase.toNowPlaying")
-	log.Info("begin")
-	defer log.Info("end")
-
-	m.delegate.playingMtx.RLock()
-	defer m.delegate.playingMtx.RUnlock()
-
-	uuid := ""
-	if m.delegate.currPlaying != nil {
-		uuid = m.delegate.currPlaying.Stationuuid
-	} else if m.delegate.prevPlaying != nil {
-		uuid = m.delegate.prevPlaying.Stationuuid
-	} else {
-		return
-	}
-	selIndex := -1
-	items := t.list.VisibleItems()
-	for ix := range items {
-		if items[ix].(browser.Station).Stationuuid == uuid {
-			selIndex = ix
+	uuid := m.delegate.playingUuid()
+	if uuid == "" {
+		return nil
+	}
+	cmd, ok := t.selectStation(uuid)
+	log.Info("", "uuid", uuid, "found", ok)
+	return cmd
+}
+
+// selectStation moves the cursor to the station, loading the pending stations
+// and clearing the filter if they hide it. It reports whether the list has the station.
+func (t *stationsTabBase) selectStation(uuid string) (tea.Cmd, bool) {
+	var cmd tea.Cmd
+	for i := range t.window.pending {
+		if t.window.pending[i].Stationuuid == uuid {
+			cmd = t.window.extend(&t.list, i+1)
 			break
 		}
 	}
-	slog.Info("method", "ui.baseTab.toNowPlaying", "selIndex", selIndex)
-	if selIndex > -1 {
-		t.list.Select(selIndex)
+	if _, idx := t.getListStationByUuid(uuid); idx == nil {
+		return cmd, false
+	}
+	if t.list.FilterState() != list.Unfiltered && !slices.ContainsFunc(t.list.VisibleItems(), func(it list.Item) bool {
+		return it.(browser.Station).Stationuuid == uuid
+	}) {
+		t.list.ResetFilter()
+	}
+	for ix, it := range t.list.VisibleItems() {
+		if it.(browser.Station).Stationuuid == uuid {
+			t.list.Select(ix)
+			break
+		}
 	}
+	return cmd, true
 }
 
 func (t *stationsTabBase) IsSearchEnabled() bool {
@@ -153,7 +234,7 @@ func (*stationsTabBase) newSizeMsg(sizeMsg tea.WindowSizeMsg, m *Model) tea.Wind
 
 func (t *stationsTabBase) initInfoModel(m *Model, msg toggleInfoMsg) tea.Cmd {
 	t.listKeymap.setEnabled(false)
-	t.infoModel.setSize(m.width, m.totHeight-m.headerHeight)
+	t.infoModel.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
 	return t.infoModel.Init(msg.station)
 }
 
@@ -173,6 +254,7 @@ func (t *stationsTabBase) getListStationByUuid(uuid string) (*browser.Station, *
 
 func createList(delegate *stationDelegate, width int, height int) list.Model {
 	l := list.New([]list.Item{}, delegate, 0, 0)
+	l.FilterInput.Cursor.SetMode(styles.CursorMode)
 	l.InfiniteScrolling = true
 	l.SetShowTitle(false)
 	l.SetShowStatusBar(false)
@@ -182,10 +264,14 @@ func createList(delegate *stationDelegate, width int, height int) list.Model {
 	l.Styles.NoItems = delegate.style.NoItemsStyle
 	l.FilterInput.ShowSuggestions = true
 	l.KeyMap.Quit.SetKeys("q")
+	l.KeyMap.Quit.SetHelp("q", "detach")
 	l.KeyMap.PrevPage.SetKeys("pgup", "ctrl+b")
 	l.KeyMap.PrevPage.SetHelp("ctrl+b/pgup", "prev page")
 	l.KeyMap.NextPage.SetKeys("pgdown", "ctrl+f")
 	l.KeyMap.NextPage.SetHelp("ctrl+f/pgdn", "next page")
+	// esc goes to the playing station, the filters are kept until cleared
+	l.KeyMap.ClearFilter.SetKeys("X")
+	l.KeyMap.ClearFilter.SetHelp("X", "clear filters")
 	h, v := delegate.style.DocStyle.GetFrameSize()
 	l.SetSize(width-h, height-v)
 
@@ -9,38 +9,47 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
 )
 
 type browseTab struct {
 	stationsTabBase
 	defTopStations []browser.Station
 	searchModel    *searchModel
+
+	// nextPage is the page of the listed search after its stations, nil when they are all listed
+	nextPage   *browser.SearchParams
+	loadAll    *loadAll
+	loadAllSeq int
 }
 
-func newBrowseTab(ctx context.Context, browser *browser.Api, infoModel *infoModel, s *styles.Style) *browseTab {
+func newBrowseTab(ctx context.Context, browser *browser.Api, cfg *config.Value, infoModel *infoModel, s *styles.Style) *browseTab {
 	k := newListKeymap()
 
 	m := &browseTab{
 		stationsTabBase: newStationsTab(k, infoModel, s),
-		searchModel:     newSearchModel(ctx, browser, s),
+		searchModel:     newSearchModel(ctx, browser, cfg, s),
 	}
 	return m
 }
 
 func (t *browseTab) createList(delegate *stationDelegate, width int, height int) list.Model {
 	l := createList(delegate, width, height)
+	l.Filter = t.filter
 	l.AdditionalShortHelpKeys = func() []key.Binding {
 		return []key.Binding{t.listKeymap.search}
 	}
 	l.AdditionalFullHelpKeys = func() []key.Binding {
 		return []key.Binding{
 			t.listKeymap.search,
+			t.listKeymap.loadAll,
 			t.listKeymap.digitHelp,
 			t.listKeymap.toNowPlaying,
 			t.listKeymap.prevTab,
 			t.listKeymap.nextTab,
 			t.listKeymap.favoritesTab,
 			t.listKeymap.historyTab,
+			t.listKeymap.recordingsTab,
 			t.listKeymap.settingsTab,
 			t.listKeymap.stationView,
 		}
@@ -51,7 +60,7 @@ func (t *browseTab) createList(delegate *stationDelegate, width int, height int)
 
 func (t *browseTab) Init(m *Model) tea.Cmd {
 	t.viewMsg = loadingMsg
-	t.list = t.createList(m.delegate, m.width, m.totHeight-m.headerHeight)
+	t.list = t.createList(m.delegate, m.tabWidth(), m.totHeight-m.headerHeight)
 	return m.topStationsCmd
 }
 
@@ -84,10 +93,11 @@ func (t *browseTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		t.list.SetSize(msg.Width-h, msg.Height-m.headerHeight-v)
 
 	case topStationsRespMsg:
-		m.updateStatus(string(msg.statusMsg))
+		m.updateStatusError(string(msg.errorMsg))
 		t.viewMsg = string(msg.viewMsg)
 		copy(t.defTopStations, msg.stations)
-		cmd := t.setStations(msg.stations)
+		t.listSearch(nil, msg.stations)
+		cmd := t.setStations(m.preferredOrder(msg.stations))
 		cmds = append(cmds, cmd)
 
 	case playHistoryEntryMsg:
@@ -99,26 +109,44 @@ func (t *browseTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.playUuidCmd(msg.uuid)
 		}
 	case playUuidRespMsg:
-		m.updateStatus(string(msg.statusMsg))
+		m.updateStatusError(string(msg.errorMsg))
 		t.viewMsg = string(msg.viewMsg)
 		if len(msg.stations) > 0 {
-			return m, tea.Sequence(
+			t.listSearch(nil, msg.stations)
+			return m, m.sequence(
 				t.setStations(msg.stations),
 				m.playStationCmd(msg.stations[0]),
 			)
 		}
 
 	case searchRespMsg:
+		t.searchModel.searching = false
+		if t.searchModel.isEnabled() {
+			// the search page is left when its stations arrive
+			t.searchModel.setEnabled(false)
+		}
 		t.listKeymap.setEnabled(true)
 		if msg.cancelled {
 			// do nothing, list already has top stations
 		} else {
-			m.updateStatus(string(msg.statusMsg))
+			m.updateStatusError(string(msg.errorMsg))
+			if msg.status != "" {
+				m.updateStatus(msg.status)
+			}
 			t.viewMsg = string(msg.viewMsg)
-			cmd := t.setStations(msg.stations)
+			params := msg.params
+			if msg.errorMsg != "" {
+				// the locally indexed stations have no next page
+				params = nil
+			}
+			t.listSearch(params, msg.stations)
+			cmd := t.setStations(m.preferredOrder(msg.stations))
 			cmds = append(cmds, cmd)
 		}
 
+	case loadAllPageMsg:
+		cmds = append(cmds, t.onLoadAllPage(m, msg))
+
 	case toggleInfoMsg:
 		if msg.enable {
 			cmds = append(cmds, t.initInfoModel(m, msg))
@@ -132,25 +160,33 @@ func (t *browseTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(cmds...)
 		}
 
-		if key.Matches(msg, t.listKeymap.toNowPlaying) {
-			newListModel, cmd := t.list.Update(msg)
-			t.list = newListModel
-			cmds = append(cmds, cmd)
-			t.toNowPlaying(m)
+		if key.Matches(msg, t.listKeymap.toNowPlaying) && !t.IsFiltering() {
+			cmds = append(cmds, t.toNowPlaying(m))
+			return m, tea.Batch(cmds...)
 		}
 
 		if t.IsFiltering() {
 			break
 		}
 
+		cmds = append(cmds, t.window.onKey(&t.list, msg))
+
 		switch {
 		case key.Matches(msg, t.list.KeyMap.Quit, t.list.KeyMap.ForceQuit):
 			return m, tea.Quit
 
 		case key.Matches(msg, t.listKeymap.search):
 			t.listKeymap.setEnabled(false)
-			t.searchModel.setSize(m.width, m.totHeight-m.headerHeight)
+			t.searchModel.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
 			cmds = append(cmds, t.searchModel.Init())
+			// the field terms of the filter are searched on the server
+			if f, err := browser.ParseFilter(t.list.FilterValue()); err == nil && len(f.Fields) > 0 {
+				t.searchModel.prefill(f.SearchParams())
+			}
+			return m, tea.Batch(cmds...)
+
+		case key.Matches(msg, t.listKeymap.loadAll):
+			cmds = append(cmds, t.toggleLoadAll(m))
 			return m, tea.Batch(cmds...)
 
 		case key.Matches(msg, t.listKeymap.nextTab, t.listKeymap.historyTab):
@@ -159,6 +195,9 @@ func (t *browseTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, t.listKeymap.prevTab, t.listKeymap.favoritesTab):
 			m.toFavoritesTab()
 
+		case key.Matches(msg, t.listKeymap.recordingsTab):
+			m.toRecordingsTab()
+
 		case key.Matches(msg, t.listKeymap.settingsTab):
 			return m, m.toSettingsTab()
 
@@ -167,6 +206,9 @@ func (t *browseTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, t.listKeymap.digits...):
 			t.doJump(msg)
+
+		case key.Matches(msg, t.list.KeyMap.Filter):
+			t.setFilterItems()
 		}
 	}
 
@@ -177,16 +219,6 @@ func (t *browseTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
-func (t *browseTab) setStations(stations []browser.Station) tea.Cmd {
-	items := make([]list.Item, len(stations))
-	for i := 0; i < len(stations); i++ {
-		items[i] = stations[i]
-	}
-	cmd := t.list.SetItems(items)
-	t.list.Select(0)
-	return cmd
-}
-
 func (t *browseTab) View() string {
 	if t.IsSearchEnabled() {
 		return t.searchModel.View()
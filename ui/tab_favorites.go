@@ -1,15 +1,23 @@
 package ui
 
 import (
+	"strings"
+	"time"
+
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
 	"github.com/dancnb/sonicradio/ui/styles"
 )
 
 type favoritesTab struct {
 	stationsTabBase
+	// filterTexts are the names, aliases and notes the favorites are filtered by, and filterLabels
+	// their color labels, set when the filter starts
+	filterTexts  []string
+	filterLabels []config.Label
 }
 
 func newFavoritesTab(infoModel *infoModel, s *styles.Style) *favoritesTab {
@@ -23,6 +31,7 @@ func newFavoritesTab(infoModel *infoModel, s *styles.Style) *favoritesTab {
 
 func (t *favoritesTab) createList(delegate *stationDelegate, width int, height int) list.Model {
 	l := createList(delegate, width, height)
+	l.Filter = t.filter
 	l.AdditionalShortHelpKeys = func() []key.Binding {
 		return []key.Binding{t.listKeymap.search}
 	}
@@ -35,6 +44,7 @@ func (t *favoritesTab) createList(delegate *stationDelegate, width int, height i
 			t.listKeymap.nextTab,
 			t.listKeymap.browseTab,
 			t.listKeymap.historyTab,
+			t.listKeymap.recordingsTab,
 			t.listKeymap.settingsTab,
 			t.listKeymap.stationView,
 		}
@@ -43,9 +53,55 @@ func (t *favoritesTab) createList(delegate *stationDelegate, width int, height i
 	return l
 }
 
+// filter matches the favorites by their alias and note too, and by their color label with @label.
+// It runs outside of the update loop, so it only reads the texts set when the filter started.
+func (t *favoritesTab) filter(term string, targets []string) []list.Rank {
+	if len(t.filterTexts) != len(targets) || len(t.filterItems) != len(targets) {
+		return list.DefaultFilter(term, targets)
+	}
+	label, term := labelFilter(term)
+	if label == config.NoLabel {
+		return rankStations(term, t.filterItems, t.filterTexts)
+	}
+	var idx []int
+	var stations []browser.Station
+	var texts []string
+	for i := range t.filterTexts {
+		if t.filterLabels[i] == label {
+			idx = append(idx, i)
+			stations = append(stations, t.filterItems[i])
+			texts = append(texts, t.filterTexts[i])
+		}
+	}
+	var ranks []list.Rank
+	if term == "" {
+		for _, i := range idx {
+			ranks = append(ranks, list.Rank{Index: i})
+		}
+		return ranks
+	}
+	ranks = rankStations(term, stations, texts)
+	for i := range ranks {
+		ranks[i].Index = idx[ranks[i].Index]
+	}
+	return ranks
+}
+
+func (t *favoritesTab) setFilterTexts(cfg *config.Value) {
+	t.setFilterItems()
+	items := t.list.Items()
+	t.filterTexts = make([]string, len(items))
+	t.filterLabels = make([]config.Label, len(items))
+	for i := range items {
+		s, _ := items[i].(browser.Station)
+		t.filterTexts[i] = strings.Join([]string{s.Name, cfg.Alias(s.Stationuuid), cfg.Note(s.Stationuuid)}, " ")
+		t.filterLabels[i] = cfg.Label(s.Stationuuid)
+	}
+}
+
 func (t *favoritesTab) Init(m *Model) tea.Cmd {
 	t.viewMsg = loadingMsg
-	t.list = t.createList(m.delegate, m.width, m.totHeight-m.headerHeight)
+	t.list = t.createList(m.delegate, m.tabWidth(), m.totHeight-m.headerHeight)
 	return m.favoritesReqCmd
 }
 
@@ -94,14 +150,16 @@ func (t *favoritesTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 				notFound = append(notFound, m.cfg.Favorites[j])
 			}
 		}
-		sm := msg.statusMsg
-		if sm == "" && len(notFound) > 0 {
-			sm = statusMsg(missingFavorites)
+		if msg.errorMsg == "" && len(notFound) > 0 {
+			m.updateStatusWarn(missingFavorites)
+		} else {
+			m.updateStatusError(string(msg.errorMsg))
 		}
-		m.updateStatus(string(sm))
 		cmd := t.list.SetItems(items)
 		cmds = append(cmds, cmd)
-		if autoplayUuid != nil {
+		if msg.synced {
+			t.list.Select(min(t.list.Index(), max(0, len(items)-1)))
+		} else if autoplayUuid != nil {
 			t.list.Select(autoplayIdx)
 			cmds = append(cmds, m.playStationCmd(*autoplayUuid))
 		}
@@ -145,11 +203,9 @@ func (t *favoritesTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(cmds...)
 		}
 
-		if key.Matches(msg, t.listKeymap.toNowPlaying) {
-			newListModel, cmd := t.list.Update(msg)
-			t.list = newListModel
-			cmds = append(cmds, cmd)
-			t.toNowPlaying(m)
+		if key.Matches(msg, t.listKeymap.toNowPlaying) && !t.IsFiltering() {
+			cmds = append(cmds, t.toNowPlaying(m))
+			return m, tea.Batch(cmds...)
 		}
 
 		if t.IsFiltering() {
@@ -165,7 +221,10 @@ func (t *favoritesTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 			if !ok {
 				break
 			}
-			m.cfg.DeleteFavorite(selStation.Stationuuid)
+			name := m.cfg.StationName(selStation.Stationuuid, selStation.Name)
+			if m.cfg.DeleteFavorite(selStation.Stationuuid) {
+				m.cfg.TrashFavorite(selStation.Stationuuid, name, time.Now())
+			}
 			t.viewMsg = ""
 			if len(m.cfg.Favorites) == 0 {
 				t.viewMsg = noFavoritesAddedMsg
@@ -196,7 +255,7 @@ func (t *favoritesTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, t.listKeymap.search):
 			m.toBrowseTab()
-			return m.tabs[browseTabIx].Update(m, msg)
+			return m.updateTab(browseTabIx, msg)
 
 		case key.Matches(msg, t.listKeymap.nextTab, t.listKeymap.browseTab):
 			m.toBrowseTab()
@@ -204,6 +263,9 @@ func (t *favoritesTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, t.listKeymap.historyTab):
 			m.toHistoryTab()
 
+		case key.Matches(msg, t.listKeymap.recordingsTab):
+			m.toRecordingsTab()
+
 		case key.Matches(msg, t.listKeymap.prevTab, t.listKeymap.settingsTab):
 			return m, m.toSettingsTab()
 
@@ -212,6 +274,9 @@ func (t *favoritesTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, t.listKeymap.digits...):
 			t.doJump(msg)
+
+		case key.Matches(msg, t.list.KeyMap.Filter):
+			t.setFilterTexts(m.cfg)
 		}
 	}
 
@@ -228,3 +293,15 @@ func (t *favoritesTab) View() string {
 	}
 	return t.stationsTabBase.View()
 }
+
+// favoriteStations returns the stations of the favorites tab, including the ones not loaded in the list yet.
+func (m *Model) favoriteStations() []browser.Station {
+	t := m.tabs[favoriteTabIx].(*favoritesTab)
+	var stations []browser.Station
+	for _, it := range t.list.Items() {
+		if s, ok := it.(browser.Station); ok {
+			stations = append(stations, s)
+		}
+	}
+	return append(stations, t.window.pending...)
+}
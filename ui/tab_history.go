@@ -57,6 +57,10 @@ func newHistoryTab(ctx context.Context, cfg *config.Value, s *styles.Style) *his
 				key.WithKeys("shift+tab"),
 				key.WithHelp("shift+tab", "go to prev tab"),
 			),
+			recordingsTab: key.NewBinding(
+				key.WithKeys("R"),
+				key.WithHelp("R", "go to recordings tab"),
+			),
 			settingsTab: key.NewBinding(
 				key.WithKeys("S"),
 				key.WithHelp("S", "go to settings tab"),
@@ -110,7 +114,7 @@ func (t *historyTab) handleHistoryUpdates(ctx context.Context) {
 
 func (t *historyTab) Init(m *Model) tea.Cmd {
 	t.viewMsg = emptyHistoryMsg
-	t.createList(m.width, m.totHeight-m.headerHeight)
+	t.createList(m.tabWidth(), m.totHeight-m.headerHeight, m.delegate.nowPlaying)
 	return t.setEntries(t.cfg.History)
 }
 
@@ -161,13 +165,15 @@ func (t *historyTab) deleteAllCmd() tea.Cmd {
 	}
 }
 
-func (t *historyTab) createList(width int, height int) {
+func (t *historyTab) createList(width int, height int, nowPlaying func() (string, bool)) {
 	delegate := historyEntryDelegate{
 		defaultDelegate: list.NewDefaultDelegate(),
 		keymap:          &t.keymap,
 		style:           t.style,
+		nowPlaying:      nowPlaying,
 	}
 	l := list.New([]list.Item{}, &delegate, 0, 0)
+	l.FilterInput.Cursor.SetMode(styles.CursorMode)
 	l.InfiniteScrolling = true
 	l.SetShowTitle(false)
 	l.SetShowStatusBar(false)
@@ -178,10 +184,14 @@ func (t *historyTab) createList(width int, height int) {
 	l.Styles.NoItems = t.style.NoItemsStyle
 	l.FilterInput.ShowSuggestions = true
 	l.KeyMap.Quit.SetKeys("q")
+	l.KeyMap.Quit.SetHelp("q", "detach")
 	l.KeyMap.PrevPage.SetKeys("pgup", "ctrl+b")
 	l.KeyMap.PrevPage.SetHelp("ctrl+b/pgup", "prev page")
 	l.KeyMap.NextPage.SetKeys("pgdown", "ctrl+f")
 	l.KeyMap.NextPage.SetHelp("ctrl+f/pgdn", "next page")
+	// esc goes to the playing station, the filters are kept until cleared
+	l.KeyMap.ClearFilter.SetKeys("X")
+	l.KeyMap.ClearFilter.SetHelp("X", "clear filters")
 	h, v := t.style.DocStyle.GetFrameSize()
 	l.SetSize(width-h, height-v)
 
@@ -199,6 +209,7 @@ func (t *historyTab) createList(width int, height int) {
 			t.keymap.nextTab,
 			t.keymap.favoritesTab,
 			t.keymap.browseTab,
+			t.keymap.recordingsTab,
 			t.keymap.settingsTab,
 		}
 	}
@@ -231,10 +242,10 @@ func (t *historyTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 			e, _ := t.list.SelectedItem().(config.HistoryEntry)
 			if slices.Contains(m.cfg.Favorites, e.Uuid) {
 				m.toFavoritesTab()
-				return m.tabs[favoriteTabIx].Update(m, playHistoryEntryMsg{e.Uuid})
+				return m.updateTab(favoriteTabIx, playHistoryEntryMsg{e.Uuid})
 			}
 			m.toBrowseTab()
-			return m.tabs[browseTabIx].Update(m, playHistoryEntryMsg{e.Uuid})
+			return m.updateTab(browseTabIx, playHistoryEntryMsg{e.Uuid})
 
 		case key.Matches(msg, t.keymap.deleteOne):
 			return m, t.deleteOneCmd()
@@ -243,11 +254,14 @@ func (t *historyTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, t.keymap.search):
 			m.toBrowseTab()
-			return m.tabs[browseTabIx].Update(m, msg)
+			return m.updateTab(browseTabIx, msg)
 		case key.Matches(msg, t.keymap.digits...):
 			t.doJump(msg)
 
-		case key.Matches(msg, t.keymap.nextTab, t.keymap.settingsTab):
+		case key.Matches(msg, t.keymap.nextTab, t.keymap.recordingsTab):
+			m.toRecordingsTab()
+
+		case key.Matches(msg, t.keymap.settingsTab):
 			return m, m.toSettingsTab()
 
 		case key.Matches(msg, t.keymap.favoritesTab):
@@ -273,6 +287,20 @@ func (t *historyTab) doJump(msg tea.KeyMsg) {
 	}
 }
 
+// selectStation moves the cursor to the latest entry of the station, clearing the filter if it hides it.
+// It reports whether the history has the station.
+func (t *historyTab) selectStation(uuid string) bool {
+	isStation := func(it list.Item) bool { return it.(config.HistoryEntry).Uuid == uuid }
+	if !slices.ContainsFunc(t.list.Items(), isStation) {
+		return false
+	}
+	if !slices.ContainsFunc(t.list.VisibleItems(), isStation) {
+		t.list.ResetFilter()
+	}
+	t.list.Select(slices.IndexFunc(t.list.VisibleItems(), isStation))
+	return true
+}
+
 func (t *historyTab) IsFiltering() bool {
 	return t.list.FilterState() == list.Filtering
 }
@@ -295,6 +323,8 @@ type historyEntryDelegate struct {
 	defaultDelegate list.DefaultDelegate
 	keymap          *historyKeymap
 	style           *styles.Style
+	// nowPlaying returns the station playing, whose entries are marked
+	nowPlaying func() (string, bool)
 }
 
 func (d *historyEntryDelegate) ShortHelp() []key.Binding {
@@ -320,6 +350,19 @@ func (d *historyEntryDelegate) Render(w io.Writer, m list.Model, index int, item
 		return
 	}
 	isSel := index == m.Index()
+	var playingUuid string
+	var paused bool
+	if d.nowPlaying != nil {
+		playingUuid, paused = d.nowPlaying()
+	}
+	// the latest entry of the station playing is highlighted, the others only have the icon
+	isPlaying := entry.Uuid == playingUuid
+	isLatest := isPlaying &&
+		slices.IndexFunc(m.VisibleItems(), func(it list.Item) bool { return it.(config.HistoryEntry).Uuid == entry.Uuid }) == index
+	prefixStyle := d.style.PrefixStyle
+	if isLatest {
+		prefixStyle = d.style.NowPlayingPrefixStyle
+	}
 	var res strings.Builder
 
 	prefix := fmt.Sprintf("%d. ", index+1)
@@ -332,8 +375,11 @@ func (d *historyEntryDelegate) Render(w io.Writer, m list.Model, index int, item
 	}
 	listWidth := m.Width()
 	station := entry.Title()
+	if isPlaying {
+		station += nowPlayingIcon(paused)
+	}
 
-	prefixRender := d.style.PrefixStyle.Render(prefix)
+	prefixRender := prefixStyle.Render(prefix)
 	res.WriteString(prefixRender)
 	maxWidth := max(listWidth-lipgloss.Width(prefixRender)-styles.HeaderPadDist, 0)
 
@@ -353,7 +399,7 @@ func (d *historyEntryDelegate) Render(w io.Writer, m list.Model, index int, item
 	res.WriteString(itStyle.Render(strings.Repeat(" ", hFill)))
 	res.WriteString("\n")
 
-	res.WriteString(d.style.PrefixStyle.Render(strings.Repeat(" ", utf8.RuneCountInString(prefix))))
+	res.WriteString(prefixStyle.Render(strings.Repeat(" ", utf8.RuneCountInString(prefix))))
 	desc := entry.Description()
 	for lipgloss.Width(descStyle.Render(desc)) > maxWidth && len(desc) > 0 {
 		desc = desc[:len(desc)-1]
@@ -364,19 +410,23 @@ func (d *historyEntryDelegate) Render(w io.Writer, m list.Model, index int, item
 	res.WriteString(descStyle.Render(strings.Repeat(" ", hFill)))
 
 	str := res.String()
+	if isLatest {
+		str = d.style.SelectedBorderStyle.Render(str)
+	}
 	fmt.Fprint(w, str)
 }
 
 type historyKeymap struct {
-	play         key.Binding
-	deleteOne    key.Binding
-	deleteAll    key.Binding
-	nextTab      key.Binding
-	prevTab      key.Binding
-	favoritesTab key.Binding
-	settingsTab  key.Binding
-	browseTab    key.Binding
-	search       key.Binding
-	digits       []key.Binding
-	digitHelp    key.Binding
+	play          key.Binding
+	deleteOne     key.Binding
+	deleteAll     key.Binding
+	nextTab       key.Binding
+	prevTab       key.Binding
+	favoritesTab  key.Binding
+	recordingsTab key.Binding
+	settingsTab   key.Binding
+	browseTab     key.Binding
+	search        key.Binding
+	digits        []key.Binding
+	digitHelp     key.Binding
 }
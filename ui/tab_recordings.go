@@ -0,0 +1,360 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/browser"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/diskquota"
+	"github.com/dancnb/sonicradio/recorder"
+	"github.com/dancnb/sonicradio/ui/styles"
+)
+
+const (
+	emptyRecordingsMsg          = "\n  No recordings scheduled. Press ctrl+r on a station to schedule one. \n"
+	recordingsFilterPlaceholder = "station name or file"
+	recordingScheduled          = "Recording scheduled"
+)
+
+// scheduleItem is an upcoming or running recording.
+type scheduleItem struct {
+	config.RecordingSchedule
+	running bool
+}
+
+type recordingsTab struct {
+	ctx       context.Context
+	cfg       *config.Value
+	style     *styles.Style
+	scheduler *recorder.Scheduler
+	viewMsg   string
+	list      list.Model
+	keymap    recordingsKeymap
+	form      *scheduleForm
+	height    int
+}
+
+func newRecordingsTab(ctx context.Context, cfg *config.Value, s *styles.Style, scheduler *recorder.Scheduler) *recordingsTab {
+	return &recordingsTab{
+		ctx:       ctx,
+		cfg:       cfg,
+		style:     s,
+		scheduler: scheduler,
+		keymap: recordingsKeymap{
+			deleteOne: key.NewBinding(
+				key.WithKeys("d"),
+				key.WithHelp("d", "stop/delete"),
+			),
+			nextTab: key.NewBinding(
+				key.WithKeys("tab"),
+				key.WithHelp("tab", "go to next tab"),
+			),
+			prevTab: key.NewBinding(
+				key.WithKeys("shift+tab"),
+				key.WithHelp("shift+tab", "go to prev tab"),
+			),
+			favoritesTab: key.NewBinding(
+				key.WithKeys("F"),
+				key.WithHelp("F", "go to favorites tab"),
+			),
+			browseTab: key.NewBinding(
+				key.WithKeys("B"),
+				key.WithHelp("B", "go to browse tab"),
+			),
+			historyTab: key.NewBinding(
+				key.WithKeys("H"),
+				key.WithHelp("H", "go to history tab"),
+			),
+			settingsTab: key.NewBinding(
+				key.WithKeys("S"),
+				key.WithHelp("S", "go to settings tab"),
+			),
+		},
+	}
+}
+
+func (t *recordingsTab) Init(m *Model) tea.Cmd {
+	t.createList(m.tabWidth(), m.totHeight-m.headerHeight)
+	return t.setItems()
+}
+
+// setItems lists the running and upcoming recordings by start time, followed by the finished ones, newest first.
+func (t *recordingsTab) setItems() tea.Cmd {
+	now := time.Now()
+	schedules := t.cfg.GetSchedules()
+	slices.SortFunc(schedules, func(a, b config.RecordingSchedule) int {
+		na, _ := a.Next(now)
+		nb, _ := b.Next(now)
+		return na.Compare(nb)
+	})
+	recordings := t.cfg.GetRecordings()
+
+	items := make([]list.Item, 0, len(schedules)+len(recordings))
+	for _, s := range schedules {
+		if _, ok := s.Next(now); !ok {
+			continue
+		}
+		items = append(items, scheduleItem{RecordingSchedule: s, running: t.scheduler.IsRunning(s.ID)})
+	}
+	for i := len(recordings) - 1; i >= 0; i-- {
+		items = append(items, recordings[i])
+	}
+	t.viewMsg = ""
+	if len(items) == 0 {
+		t.viewMsg = emptyRecordingsMsg
+	}
+	idx := t.list.Index()
+	cmd := t.list.SetItems(items)
+	t.list.Select(min(idx, max(0, len(items)-1)))
+	return cmd
+}
+
+// newSchedule opens the form to schedule a recording of the station.
+func (t *recordingsTab) newSchedule(s browser.Station) tea.Cmd {
+	t.form = newScheduleForm(t.style, s, time.Now())
+	return t.form.focus()
+}
+
+func (t *recordingsTab) deleteOne() {
+	switch it := t.list.SelectedItem().(type) {
+	case scheduleItem:
+		if it.running {
+			t.scheduler.Stop(it.ID)
+			if it.Repeat != config.RepeatOnce {
+				return
+			}
+		}
+		t.cfg.DeleteSchedule(it.ID)
+	case config.RecordingEntry:
+		t.cfg.DeleteRecording(it)
+	}
+}
+
+func (t *recordingsTab) createList(width int, height int) {
+	delegate := recordingDelegate{
+		defaultDelegate: list.NewDefaultDelegate(),
+		keymap:          &t.keymap,
+		style:           t.style,
+	}
+	l := list.New([]list.Item{}, &delegate, 0, 0)
+	l.FilterInput.Cursor.SetMode(styles.CursorMode)
+	l.InfiniteScrolling = true
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetShowPagination(false)
+	l.SetShowFilter(true)
+	l.Filter = list.UnsortedFilter
+	l.SetStatusBarItemName("recording", "recordings")
+	l.Styles.NoItems = t.style.NoItemsStyle
+	l.KeyMap.Quit.SetKeys("q")
+	l.KeyMap.Quit.SetHelp("q", "detach")
+	l.KeyMap.PrevPage.SetKeys("pgup", "ctrl+b")
+	l.KeyMap.PrevPage.SetHelp("ctrl+b/pgup", "prev page")
+	l.KeyMap.NextPage.SetKeys("pgdown", "ctrl+f")
+	l.KeyMap.NextPage.SetHelp("ctrl+f/pgdn", "next page")
+	// esc goes to the playing station, the filters are kept until cleared
+	l.KeyMap.ClearFilter.SetKeys("X")
+	l.KeyMap.ClearFilter.SetHelp("X", "clear filters")
+	h, v := t.style.DocStyle.GetFrameSize()
+	l.SetSize(width-h, height-v)
+	t.height = height - v
+
+	l.Help.ShortSeparator = "   "
+	l.Help.Styles = t.style.HelpStyles()
+	l.Styles.HelpStyle = t.style.HelpStyle
+	l.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			t.keymap.prevTab,
+			t.keymap.nextTab,
+			t.keymap.favoritesTab,
+			t.keymap.browseTab,
+			t.keymap.historyTab,
+			t.keymap.settingsTab,
+		}
+	}
+
+	t.style.TextInputSyle(&l.FilterInput, stationsFilterPrompt, recordingsFilterPlaceholder)
+
+	t.list = l
+}
+
+func (t *recordingsTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	logTeaMsg(msg, "ui.recordingsTab.Update")
+
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := t.style.DocStyle.GetFrameSize()
+		t.list.SetSize(msg.Width-h, msg.Height-m.headerHeight-v)
+		t.height = msg.Height - m.headerHeight - v
+
+	case recordingsChangedMsg:
+		return m, t.setItems()
+
+	case tea.KeyMsg:
+		if t.form != nil || t.list.FilterState() == list.Filtering {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, t.list.KeyMap.Quit, t.list.KeyMap.ForceQuit):
+			return m, tea.Quit
+
+		case key.Matches(msg, t.keymap.deleteOne):
+			t.deleteOne()
+			return m, t.setItems()
+
+		case key.Matches(msg, t.keymap.nextTab, t.keymap.settingsTab):
+			return m, m.toSettingsTab()
+		case key.Matches(msg, t.keymap.favoritesTab):
+			m.toFavoritesTab()
+		case key.Matches(msg, t.keymap.browseTab):
+			m.toBrowseTab()
+		case key.Matches(msg, t.keymap.prevTab, t.keymap.historyTab):
+			m.toHistoryTab()
+		}
+	}
+
+	if t.form != nil {
+		sch, done, cmd := t.form.Update(msg)
+		if done {
+			t.form = nil
+		}
+		if sch != nil {
+			t.cfg.AddSchedule(*sch)
+			m.updateStatus(recordingScheduled)
+			t.scheduler.Check(t.ctx)
+			return m, t.setItems()
+		}
+		return m, cmd
+	}
+	newListModel, cmd := t.list.Update(msg)
+	t.list = newListModel
+	cmds = append(cmds, cmd)
+
+	return m, tea.Batch(cmds...)
+}
+
+// IsFiltering is also true while the schedule form is open, so that its input is not handled as key bindings.
+func (t *recordingsTab) IsFiltering() bool {
+	return t.form != nil || t.list.FilterState() == list.Filtering
+}
+
+func (t *recordingsTab) View() string {
+	if t.form != nil {
+		return t.form.View(t.height)
+	}
+	if t.viewMsg != "" {
+		var sections []string
+		availHeight := t.list.Height()
+		help := t.list.Styles.HelpStyle.Render(t.list.Help.View(t.list))
+		availHeight -= lipgloss.Height(help)
+		viewSection := t.style.ViewStyle.Height(availHeight).Render(t.viewMsg)
+		sections = append(sections, viewSection)
+		sections = append(sections, help)
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+	return t.list.View()
+}
+
+type recordingDelegate struct {
+	defaultDelegate list.DefaultDelegate
+	keymap          *recordingsKeymap
+	style           *styles.Style
+}
+
+func (d *recordingDelegate) ShortHelp() []key.Binding {
+	return []key.Binding{d.keymap.deleteOne}
+}
+
+func (d *recordingDelegate) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{d.keymap.deleteOne}}
+}
+
+func (d *recordingDelegate) Height() int { return d.defaultDelegate.Height() }
+
+func (d *recordingDelegate) Spacing() int { return d.defaultDelegate.Spacing() }
+
+func (d *recordingDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
+	logTeaMsg(msg, "ui.recordingDelegate.Update")
+	return nil
+}
+
+func (d *recordingDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	var title, desc string
+	switch it := item.(type) {
+	case scheduleItem:
+		title, desc = styles.ScheduleChar+it.Title(), it.Description()
+		if it.running {
+			title = styles.RecordingChar + it.Title()
+		}
+	case config.RecordingEntry:
+		title, desc = "  "+it.Title(), fmt.Sprintf("%s · %s", it.Description(), diskquota.FormatSize(it.Size))
+	default:
+		return
+	}
+	isSel := index == m.Index()
+	var res strings.Builder
+
+	prefix := fmt.Sprintf("%d. ", index+1)
+	if index+1 < 10 {
+		prefix = fmt.Sprintf("   %s", prefix)
+	} else if index+1 < 100 {
+		prefix = fmt.Sprintf("  %s", prefix)
+	} else if index+1 < 1000 {
+		prefix = fmt.Sprintf(" %s", prefix)
+	}
+	listWidth := m.Width()
+
+	prefixRender := d.style.PrefixStyle.Render(prefix)
+	res.WriteString(prefixRender)
+	maxWidth := max(listWidth-lipgloss.Width(prefixRender)-styles.HeaderPadDist, 0)
+
+	itStyle := d.style.SecondaryColorStyle
+	descStyle := d.style.HistoryDescStyle
+	if isSel {
+		itStyle = d.style.HistorySelItemStyle
+		descStyle = d.style.HistorySelDescStyle
+	}
+
+	for lipgloss.Width(itStyle.Render(title)) > maxWidth && len(title) > 0 {
+		title = title[:len(title)-1]
+	}
+	titleRender := itStyle.Render(title)
+	res.WriteString(titleRender)
+	hFill := max(listWidth-lipgloss.Width(prefixRender)-lipgloss.Width(titleRender)-styles.HeaderPadDist, 0)
+	res.WriteString(itStyle.Render(strings.Repeat(" ", hFill)))
+	res.WriteString("\n")
+
+	res.WriteString(d.style.PrefixStyle.Render(strings.Repeat(" ", utf8.RuneCountInString(prefix))))
+	for lipgloss.Width(descStyle.Render(desc)) > maxWidth && len(desc) > 0 {
+		desc = desc[:len(desc)-1]
+	}
+	descRender := descStyle.Render(desc)
+	res.WriteString(descRender)
+	hFill = max(listWidth-lipgloss.Width(prefixRender)-lipgloss.Width(descRender)-styles.HeaderPadDist, 0)
+	res.WriteString(descStyle.Render(strings.Repeat(" ", hFill)))
+
+	fmt.Fprint(w, res.String())
+}
+
+type recordingsKeymap struct {
+	deleteOne    key.Binding
+	nextTab      key.Binding
+	prevTab      key.Binding
+	favoritesTab key.Binding
+	browseTab    key.Binding
+	historyTab   key.Binding
+	settingsTab  key.Binding
+}
@@ -7,6 +7,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dancnb/sonicradio/ui/components"
 	"github.com/dancnb/sonicradio/ui/styles"
@@ -16,11 +17,26 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/diskquota"
 )
 
 type settingsTab struct {
-	cfg           *config.Value
-	changeThemeFn func(int)
+	cfg             *config.Value
+	changeThemeFn   func(int)
+	enforceQuotasFn func()
+	relayFn         func(bool)
+	dialFn          func(config.DialKeys)
+	metadataPollFn  func(int)
+	restoreFn       func(config.FavoritesBackupFile) tea.Cmd
+	autoDuckFn      func(bool) tea.Cmd
+	crossfadeFn     func(int)
+	maxVolumeFn     func(int) tea.Cmd
+	// callbackCmd is the command of the last option callback, run after it
+	callbackCmd tea.Cmd
+
+	// backups are the favorites backups listed by backupList, after the current favorites
+	backups    []config.FavoritesBackupFile
+	backupList *components.OptionList
 
 	style  *styles.Style
 	keymap settingsKeymap
@@ -37,17 +53,86 @@ type settingsInputIdx byte
 const (
 	historySaveMaxIdx settingsInputIdx = iota
 	themesIdx
+	playerIdx
+	musicBrainzIdx
+	artworkIdx
+	splitRecordingsIdx
+	cacheLimitIdx
+	recordingsLimitIdx
+	outputVolumeIdx
+	maxVolumeIdx
+	startupVolumeIdx
+	startupFadeIdx
+	idleStopIdx
+	bandwidthCapIdx
+	bandwidthStopIdx
+	lowBandwidthIdx
+	relayIdx
+	clockIdx
+	uptimeIdx
+	bufferHealthIdx
+	connectTimeoutIdx
+	preconnectIdx
+	dialKeysIdx
+	learnLanguageIdx
+	preferTalkIdx
+	screenReaderIdx
+	symbolSignalsIdx
+	reducedMotionIdx
+	renderProfileIdx
+	titleIdx
+	iconsIdx
+	metadataPollIdx
+	favoritesBackupIdx
+	autoDuckIdx
+	crossfadeIdx
 )
 
 var (
 	descriptions = []string{
 		`Maximum number of entries displayed in "History" tab.`,
 		`Preview and select a theme.`,
-		`Choose one of the available backend players (only those found in PATH are displayed): Mpv, FFplay, VLC, MPlayer. The choice will take effect after a restart.`,
+		`Choose one of the available backend players (only those found in PATH, or at the path set in the config file, are displayed): Mpv, FFplay, VLC, MPlayer. The choice will take effect after a restart.`,
+		`Look up the playing song on MusicBrainz to display its album and release year.`,
+		`Display the cover of the current song, or the station logo, in the station info view. Images are drawn with the kitty, iTerm2 or sixel graphics protocols when the terminal supports them, otherwise as ASCII art. The cover requires the MusicBrainz lookup.`,
+		`Save every song of a scheduled recording to its own file, named after the artist and title of the stream metadata and tagged with them. Only streams with ICY metadata can be split.`,
+		`Size limit in MB of the cached station lists, artwork and lyrics, 0 for no limit. The least recently saved files are removed when it's exceeded.`,
+		`Size limit in MB of the recordings folder, 0 for no limit. The oldest recordings are removed when it's exceeded.`,
+		`Remember the volume of each output, the local player, a Bluetooth device or a cast target, and restore it when switching to that output.`,
+		`Hard cap of the volume, protecting the ears and the speakers: the volume is never set above it, whatever the player, the output or the client of the daemon asking. Other caps can be set as maxVolume in the config file.`,
+		`The volume the app and the daemon start with, instead of the last one, from the next start. Other volumes can be set as startupVolume in the config file.`,
+		`Fade in the first station played after the start, from silence to the volume, from the next start. Not available with FFplay, which can't change its volume while playing. Other durations, in milliseconds, can be set as startupFadeMs in the config file.`,
+		`Stop the playback after this many hours without a key press, 0 to never stop. A warning is displayed a minute before.`,
+		`Monthly data cap in MB, 0 for no cap. The data is estimated from the bitrate of the stations played locally, cast targets stream on their own. A warning is displayed at 90% of the cap.`,
+		`Stop the playback when the monthly data cap is reached, instead of only warning.`,
+		`For tethered connections: play the lowest bitrate variant of a station when radio-browser lists several, and don't download station logos or cover art.`,
+		`Serve the playing station over HTTP on the local network, so other devices can tune into it. The listeners share one connection of the app to the station, with its song titles, counted in the data cap.`,
+		`Show the local time in the status bar. Other layouts, as in the Go time package, can be set as clockFormat in the config file.`,
+		`Show in the status bar how long the playing station has been streaming without interruption. Unlike the playback time, it's kept when the station reconnects or the session is switched.`,
+		`Show in the status bar the seconds of the stream buffered ahead by the player, mpv only. Whether shown or not, a warning tells when the buffer keeps running low, under 2 seconds for half a minute, as the stream may stall; the low bandwidth mode may help then.`,
+		`Seconds for a station to start playing before giving up on it, 0 for the default of 15 seconds. Connecting can also be cancelled with esc.`,
+		`Resolve the playlists and redirects of the station the cursor rests on, so it starts faster when played. Disabled in low bandwidth mode.`,
+		`Keys playing the favorites on the quick dial slots from any tab. The slots are the first nine favorites until a favorite is put on a slot with m. With 1..9, going to a station number in the lists is not available.`,
+		`For language immersion: the language the top stations of the Browse tab are filtered by, from the next start, and the default language of the searches. Empty for all languages.`,
+		`List the talk and news stations first in the Browse tab, where more is said than sung.`,
+		`Plain text output for the screen readers: words and ASCII instead of the icons and box borders, the selected line marked with >, and the state changes, like the station playing, the song titles and the messages of the status bar, printed as lines above the view. Can also be enabled for a session with the -screen-reader arg.`,
+		`Don't tell things by colors alone: the line under the cursor is marked with >, the active tab is in brackets, the color labels are followed by their names and the warnings and errors start with a word. The "High Contrast" and "Colorblind Safe" themes are checked for contrast, also with color vision deficiencies.`,
+		`No animations, for the users sensitive to motion and the slow SSH links: the spinner and the cursors stand still, and the playback time and the stream uptime count the minutes, so the view only changes with the state. A status bar clock with seconds still ticks.`,
+		`For the high latency links: the low refresh draws the view 4 times per second instead of 60, with the 16 ANSI colors, without the animations and with the seconds of the clock standing still. Auto uses it in the SSH sessions.`,
+		`Show the playing station and song in the title of the terminal window or tab, and of the pane in tmux. Other templates, as in the Go text/template package with .State, .Station, .Song, .Artist and .Title, can be set as titleFormat in the config file.`,
+		`The glyphs of the play, pause, favorite and recording indicators: the icons of the Nerd Fonts, for a terminal using one, the Unicode symbols, or ASCII, for the terminals and fonts without them like the Linux console. Auto uses the Nerd Font icons in kitty, WezTerm and Ghostty, which bundle them, and ASCII on the Linux console and with a locale which isn't UTF-8.`,
+		`How often the song title and the playback time are asked to the player. Auto is every 500ms, or the minimum of the player: 1s for FFplay, whose title is read from its whole output, 500ms for VLC and 250ms for mpv and MPlayer. Shorter intervals are raised to the minimum. With Manual, the title is only refreshed with U. Other intervals, in milliseconds, can be set as metadataPollMs in the config file.`,
+		`Restore the favorites, with their names, notes and labels, as they were at a backup. A backup is written when the favorites change, the last 10 being kept; the number can be set as favoriteBackups in the config file, 0 for none. The favorites replaced are backed up first.`,
+		`On Linux, lower the volume while another app plays a notification or a call, as told by its PulseAudio or PipeWire media role, and restore it after. The volume kept, 30% by default, can be set as duckVolume in the config file.`,
+		`When switching stations, fade the playing one out while the next one fades in, two players running for that time. Not available with FFplay, which can't change its volume while playing, nor for the cast targets; the daemon fades with the duration of the config file at its start. Other durations, in milliseconds, can be set as crossfadeMs in the config file.`,
 	}
-	ffplayDesc  = "\nFFplay does not allow changing the volume during playback or seeking backward/forward."
-	vlcDesc     = "\nFor VLC, pausing or seeking backward/forward may result in an invalid song title being displayed."
-	mplayerDesc = "\nFor MPlayer, seeking backward/forward is not available."
+	diskUsageDesc = "\nCurrently used: %s"
+	bandwidthDesc = "\nStreamed today: %s, this month: %s"
+	relayPortDesc = "\nListening on port %d, the port can be changed in the config file."
+	ffplayDesc    = "\nFFplay does not allow changing the volume during playback or seeking backward/forward."
+	vlcDesc       = "\nFor VLC, pausing or seeking backward/forward may result in an invalid song title being displayed."
+	mplayerDesc   = "\nFor MPlayer, seeking backward/forward is not available."
+	listeningDesc = "\nListened: %s"
 )
 
 func newSettingsTab(
@@ -56,6 +141,14 @@ func newSettingsTab(
 	s *styles.Style,
 	playerTypes []config.PlayerType,
 	changeThemeFn func(int),
+	enforceQuotasFn func(),
+	relayFn func(bool),
+	dialFn func(config.DialKeys),
+	metadataPollFn func(int),
+	restoreFn func(config.FavoritesBackupFile) tea.Cmd,
+	autoDuckFn func(bool) tea.Cmd,
+	crossfadeFn func(int),
+	maxVolumeFn func(int) tea.Cmd,
 ) *settingsTab {
 	h := help.New()
 	h.ShowAll = false
@@ -71,8 +164,8 @@ func newSettingsTab(
 		themeOpts[i] = components.OptionValue{IdxView: i + 1, NameView: styles.Themes[i].Name}
 	}
 	themeList := components.NewOptionList("Theme", themeOpts, cfg.Theme, s)
-	// TODO: false if more than 10 themes
-	themeList.SetQuick(true)
+	// with 10 themes or more, the digits of the 2 digit positions are awaited
+	themeList.SetQuick(len(themeOpts) < 10)
 	themeList.PartialCallbackFn = changeThemeFn
 	themeList.DoneCallbackFn = changeThemeFn
 
@@ -92,6 +185,228 @@ func newSettingsTab(
 		slog.Info("change player type", "i", i, "new type", cfg.Player.String())
 	}
 
+	// musicbrainz
+	musicBrainzList := newToggle("MusicBrainz lookup", cfg.MusicBrainz, s, func(v bool) {
+		cfg.MusicBrainz = v
+	})
+
+	// artwork
+	artworkList := newToggle("Album art", cfg.Artwork, s, func(v bool) {
+		cfg.Artwork = v
+	})
+
+	// split recordings
+	splitRecordingsList := newToggle("Split recordings by song", cfg.SplitRecordings, s, func(v bool) {
+		cfg.SplitRecordings = v
+	})
+
+	// disk quotas
+	cacheLimit := s.NewInputModel("Cache limit (MB)", "0", nil, nil, nil, styles.NrInputValidator)
+	recordingsLimit := s.NewInputModel("Recordings limit (MB)", "0", nil, nil, nil, styles.NrInputValidator)
+
+	// volume per output
+	outputVolumeList := newToggle("Volume per output", cfg.RememberOutputVolume, s, func(v bool) {
+		cfg.RememberOutputVolume = v
+	})
+
+	// volume cap, its callback set with the tab
+	caps := maxVolumes
+	if !slices.Contains(caps, cfg.MaxVolume) {
+		caps = append(slices.Clone(caps), cfg.MaxVolume)
+	}
+	var capOpts []components.OptionValue
+	for i, v := range caps {
+		capOpts = append(capOpts, components.OptionValue{IdxView: i + 1, NameView: maxVolumeName(v)})
+	}
+	maxVolumeList := components.NewOptionList("Max volume", capOpts, slices.Index(caps, cfg.MaxVolume), s)
+	maxVolumeList.SetQuick(true)
+
+	// startup volume and fade in
+	startVols := startupVolumes
+	if !slices.Contains(startVols, cfg.StartupVolume) {
+		startVols = append(slices.Clone(startVols), cfg.StartupVolume)
+	}
+	var startVolOpts []components.OptionValue
+	for i, v := range startVols {
+		startVolOpts = append(startVolOpts, components.OptionValue{IdxView: i + 1, NameView: startupVolumeName(v)})
+	}
+	startupVolumeList := components.NewOptionList("Startup volume", startVolOpts, slices.Index(startVols, cfg.StartupVolume), s)
+	startupVolumeList.SetQuick(true)
+	startupVolumeList.DoneCallbackFn = func(i int) {
+		cfg.StartupVolume = startVols[i]
+	}
+	startFades := startupFades
+	if !slices.Contains(startFades, cfg.StartupFadeMs) {
+		startFades = append(slices.Clone(startFades), cfg.StartupFadeMs)
+	}
+	var startFadeOpts []components.OptionValue
+	for i, ms := range startFades {
+		startFadeOpts = append(startFadeOpts, components.OptionValue{IdxView: i + 1, NameView: fadeName(ms)})
+	}
+	startupFadeList := components.NewOptionList("Startup fade", startFadeOpts, slices.Index(startFades, cfg.StartupFadeMs), s)
+	startupFadeList.SetQuick(true)
+	startupFadeList.DoneCallbackFn = func(i int) {
+		cfg.StartupFadeMs = startFades[i]
+	}
+
+	// idle stop
+	idleStop := s.NewInputModel("Idle stop (hours)", "0", nil, nil, nil, styles.NrInputValidator)
+
+	// data cap
+	bandwidthCap := s.NewInputModel("Monthly data cap (MB)", "0", nil, nil, nil, styles.NrInputValidator)
+	bandwidthStopList := newToggle("Stop at data cap", cfg.BandwidthStop, s, func(v bool) {
+		cfg.BandwidthStop = v
+	})
+
+	// low bandwidth
+	lowBandwidthList := newToggle("Low bandwidth mode", cfg.LowBandwidth, s, func(v bool) {
+		cfg.LowBandwidth = v
+	})
+
+	// relay
+	relayList := newToggle("Stream relay", cfg.Relay, s, relayFn)
+
+	// status bar clock and uptime
+	clockOpts := []components.OptionValue{{IdxView: 1, NameView: "Off"}}
+	formats := append([]string{""}, clockFormats...)
+	if cfg.ClockFormat != "" && !slices.Contains(formats, cfg.ClockFormat) {
+		formats = append(formats, cfg.ClockFormat)
+	}
+	for i, f := range formats[1:] {
+		clockOpts = append(clockOpts, components.OptionValue{IdxView: i + 2, NameView: f})
+	}
+	clockList := components.NewOptionList("Status bar clock", clockOpts, slices.Index(formats, cfg.ClockFormat), s)
+	clockList.SetQuick(true)
+	clockList.DoneCallbackFn = func(i int) {
+		cfg.ClockFormat = formats[i]
+	}
+	uptimeList := newToggle("Stream uptime", cfg.StreamUptime, s, func(v bool) {
+		cfg.StreamUptime = v
+	})
+	bufferHealthList := newToggle("Buffer health", cfg.BufferHealth, s, func(v bool) {
+		cfg.BufferHealth = v
+	})
+
+	// connect timeout
+	connectTimeout := s.NewInputModel("Connect timeout (s)", "0", nil, nil, nil, styles.NrInputValidator)
+
+	// quick dial
+	dialOpts := []components.OptionValue{
+		{IdxView: 1, NameView: config.DialDigits.String()},
+		{IdxView: 2, NameView: config.DialAlt.String()},
+		{IdxView: 3, NameView: config.DialOff.String()},
+	}
+	dialList := components.NewOptionList("Quick dial keys", dialOpts, int(cfg.DialKeys), s)
+	dialList.SetQuick(true)
+	dialList.DoneCallbackFn = func(i int) {
+		dialFn(config.DialKeys(i))
+	}
+
+	// preconnect
+	preconnectList := newToggle("Preconnect on hover", cfg.Preconnect, s, func(v bool) {
+		cfg.Preconnect = v
+	})
+
+	// language learning
+	learnLanguage := s.NewInputModel("Learning language", "---", nil, nil, nil, nil)
+	preferTalkList := newToggle("Prefer talk and news", cfg.PreferTalk, s, func(v bool) {
+		cfg.PreferTalk = v
+	})
+
+	// accessibility
+	screenReaderList := newToggle("Screen reader mode (requires restart)", cfg.ScreenReader, s, func(v bool) {
+		cfg.ScreenReader = v
+	})
+	symbolSignalsList := newToggle("Symbols with colors (requires restart)", cfg.SymbolSignals, s, func(v bool) {
+		cfg.SymbolSignals = v
+	})
+	reducedMotionList := newToggle("Reduced motion (requires restart)", cfg.ReducedMotion, s, func(v bool) {
+		cfg.ReducedMotion = v
+	})
+
+	// rendering
+	renderProfiles := []config.RenderProfile{config.RenderAuto, config.RenderFull, config.RenderLow}
+	renderOpts := []components.OptionValue{
+		{IdxView: 1, NameView: "Auto"},
+		{IdxView: 2, NameView: "Full"},
+		{IdxView: 3, NameView: "Low refresh"},
+	}
+	renderList := components.NewOptionList("Rendering (requires restart)", renderOpts, max(0, slices.Index(renderProfiles, cfg.RenderProfile)), s)
+	renderList.SetQuick(true)
+	renderList.DoneCallbackFn = func(i int) {
+		cfg.RenderProfile = renderProfiles[i]
+	}
+
+	// terminal title
+	titleOpts := []components.OptionValue{{IdxView: 1, NameView: "Off"}}
+	titles := append([]string{""}, titleFormats...)
+	if cfg.TitleFormat != "" && !slices.Contains(titles, cfg.TitleFormat) {
+		titles = append(titles, cfg.TitleFormat)
+	}
+	for i, f := range titles[1:] {
+		var t windowTitle
+		example, ok := t.render(f, titleExample)
+		if !ok {
+			example = f
+		}
+		titleOpts = append(titleOpts, components.OptionValue{IdxView: i + 2, NameView: example})
+	}
+	titleList := components.NewOptionList("Terminal title", titleOpts, slices.Index(titles, cfg.TitleFormat), s)
+	titleList.SetQuick(true)
+	titleList.DoneCallbackFn = func(i int) {
+		cfg.TitleFormat = titles[i]
+	}
+
+	// icons
+	iconSets := []config.IconSet{config.IconsAuto, config.IconsNerd, config.IconsUnicode, config.IconsASCII}
+	iconOpts := []components.OptionValue{
+		{IdxView: 1, NameView: "Auto"},
+		{IdxView: 2, NameView: "Nerd Font"},
+		{IdxView: 3, NameView: "Unicode"},
+		{IdxView: 4, NameView: "ASCII"},
+	}
+	iconList := components.NewOptionList("Icons (requires restart)", iconOpts, max(0, slices.Index(iconSets, cfg.IconSet)), s)
+	iconList.SetQuick(true)
+	iconList.DoneCallbackFn = func(i int) {
+		cfg.IconSet = iconSets[i]
+	}
+
+	// song title refresh
+	polls := []int{0, 250, 500, 1000, 2000, 5000, config.MetadataPollManual}
+	if !slices.Contains(polls, cfg.MetadataPollMs) {
+		polls = append(polls, cfg.MetadataPollMs)
+	}
+	var pollOpts []components.OptionValue
+	for i, ms := range polls {
+		pollOpts = append(pollOpts, components.OptionValue{IdxView: i + 1, NameView: metadataPollName(ms)})
+	}
+	pollList := components.NewOptionList("Song title refresh", pollOpts, slices.Index(polls, cfg.MetadataPollMs), s)
+	pollList.SetQuick(true)
+	pollList.DoneCallbackFn = func(i int) {
+		metadataPollFn(polls[i])
+	}
+
+	// favorites backups, listed on enter
+	backupList := components.NewOptionList("Favorites backup", nil, 0, s)
+
+	// auto duck, its callback set with the tab
+	autoDuckList := newToggle("Auto duck", cfg.AutoDuck, s, nil)
+
+	// crossfade
+	fades := crossfades
+	if !slices.Contains(fades, cfg.CrossfadeMs) {
+		fades = append(slices.Clone(fades), cfg.CrossfadeMs)
+	}
+	var fadeOpts []components.OptionValue
+	for i, ms := range fades {
+		fadeOpts = append(fadeOpts, components.OptionValue{IdxView: i + 1, NameView: fadeName(ms)})
+	}
+	crossfadeList := components.NewOptionList("Crossfade", fadeOpts, slices.Index(fades, cfg.CrossfadeMs), s)
+	crossfadeList.SetQuick(true)
+	crossfadeList.DoneCallbackFn = func(i int) {
+		crossfadeFn(fades[i])
+	}
+
 	playerDesc := descriptions[2]
 	if slices.Contains(playerTypes, config.FFPlay) {
 		playerDesc += ffplayDesc
@@ -103,9 +418,18 @@ func newSettingsTab(
 		playerDesc += mplayerDesc
 	}
 	st := &settingsTab{
-		cfg:           cfg,
-		changeThemeFn: changeThemeFn,
-		style:         s,
+		cfg:             cfg,
+		changeThemeFn:   changeThemeFn,
+		enforceQuotasFn: enforceQuotasFn,
+		relayFn:         relayFn,
+		dialFn:          dialFn,
+		metadataPollFn:  metadataPollFn,
+		restoreFn:       restoreFn,
+		autoDuckFn:      autoDuckFn,
+		crossfadeFn:     crossfadeFn,
+		maxVolumeFn:     maxVolumeFn,
+		backupList:      &backupList,
+		style:           s,
 		inputs: []*components.FormElement{
 			components.NewFormElement(
 				components.WithTextInput(&historySaveMax),
@@ -116,21 +440,193 @@ func newSettingsTab(
 			components.NewFormElement(
 				components.WithOptionList(&playerList),
 				components.WithDescription(playerDesc)),
+			components.NewFormElement(
+				components.WithOptionList(&musicBrainzList),
+				components.WithDescription(descriptions[3])),
+			components.NewFormElement(
+				components.WithOptionList(&artworkList),
+				components.WithDescription(descriptions[4])),
+			components.NewFormElement(
+				components.WithOptionList(&splitRecordingsList),
+				components.WithDescription(descriptions[5])),
+			components.NewFormElement(
+				components.WithTextInput(&cacheLimit),
+				components.WithDescription(descriptions[6])),
+			components.NewFormElement(
+				components.WithTextInput(&recordingsLimit),
+				components.WithDescription(descriptions[7])),
+			components.NewFormElement(
+				components.WithOptionList(&outputVolumeList),
+				components.WithDescription(descriptions[8])),
+			components.NewFormElement(
+				components.WithOptionList(&maxVolumeList),
+				components.WithDescription(descriptions[9])),
+			components.NewFormElement(
+				components.WithOptionList(&startupVolumeList),
+				components.WithDescription(descriptions[10])),
+			components.NewFormElement(
+				components.WithOptionList(&startupFadeList),
+				components.WithDescription(descriptions[11])),
+			components.NewFormElement(
+				components.WithTextInput(&idleStop),
+				components.WithDescription(descriptions[12])),
+			components.NewFormElement(
+				components.WithTextInput(&bandwidthCap),
+				components.WithDescription(descriptions[13])),
+			components.NewFormElement(
+				components.WithOptionList(&bandwidthStopList),
+				components.WithDescription(descriptions[14])),
+			components.NewFormElement(
+				components.WithOptionList(&lowBandwidthList),
+				components.WithDescription(descriptions[15])),
+			components.NewFormElement(
+				components.WithOptionList(&relayList),
+				components.WithDescription(relayDesc(cfg))),
+			components.NewFormElement(
+				components.WithOptionList(&clockList),
+				components.WithDescription(descriptions[17])),
+			components.NewFormElement(
+				components.WithOptionList(&uptimeList),
+				components.WithDescription(descriptions[18])),
+			components.NewFormElement(
+				components.WithOptionList(&bufferHealthList),
+				components.WithDescription(descriptions[19])),
+			components.NewFormElement(
+				components.WithTextInput(&connectTimeout),
+				components.WithDescription(descriptions[20])),
+			components.NewFormElement(
+				components.WithOptionList(&preconnectList),
+				components.WithDescription(descriptions[21])),
+			components.NewFormElement(
+				components.WithOptionList(&dialList),
+				components.WithDescription(descriptions[22])),
+			components.NewFormElement(
+				components.WithTextInput(&learnLanguage),
+				components.WithDescription(descriptions[23])),
+			components.NewFormElement(
+				components.WithOptionList(&preferTalkList),
+				components.WithDescription(descriptions[24])),
+			components.NewFormElement(
+				components.WithOptionList(&screenReaderList),
+				components.WithDescription(descriptions[25])),
+			components.NewFormElement(
+				components.WithOptionList(&symbolSignalsList),
+				components.WithDescription(descriptions[26])),
+			components.NewFormElement(
+				components.WithOptionList(&reducedMotionList),
+				components.WithDescription(descriptions[27])),
+			components.NewFormElement(
+				components.WithOptionList(&renderList),
+				components.WithDescription(descriptions[28])),
+			components.NewFormElement(
+				components.WithOptionList(&titleList),
+				components.WithDescription(descriptions[29])),
+			components.NewFormElement(
+				components.WithOptionList(&iconList),
+				components.WithDescription(descriptions[30])),
+			components.NewFormElement(
+				components.WithOptionList(&pollList),
+				components.WithDescription(descriptions[31])),
+			components.NewFormElement(
+				components.WithOptionList(&backupList),
+				components.WithDescription(descriptions[32])),
+			components.NewFormElement(
+				components.WithOptionList(&autoDuckList),
+				components.WithDescription(descriptions[33])),
+			components.NewFormElement(
+				components.WithOptionList(&crossfadeList),
+				components.WithDescription(descriptions[34])),
 		},
 		keymap: newSettingsKeymap(),
 		help:   h,
 	}
+	backupList.DoneCallbackFn = st.restoreBackup
+	autoDuckList.DoneCallbackFn = func(i int) {
+		st.callbackCmd = st.autoDuckFn(i == 1)
+	}
+	maxVolumeList.DoneCallbackFn = func(i int) {
+		st.callbackCmd = st.maxVolumeFn(caps[i])
+	}
 
 	st.loadConfig()
 	return st
 }
 
+// metadataPollName returns the name of the song title refresh option of the milliseconds.
+func metadataPollName(ms int) string {
+	switch {
+	case ms == 0:
+		return "Auto"
+	case ms < 0:
+		return "Manual"
+	}
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+func relayDesc(cfg *config.Value) string {
+	return descriptions[16] + fmt.Sprintf(relayPortDesc, cfg.GetRelayPort())
+}
+
+var toggleOpts = []components.OptionValue{
+	{IdxView: 1, NameView: "Off"},
+	{IdxView: 2, NameView: "On"},
+}
+
+func newToggle(label string, val bool, s *styles.Style, fn func(bool)) components.OptionList {
+	startIdx := 0
+	if val {
+		startIdx = 1
+	}
+	l := components.NewOptionList(label, toggleOpts, startIdx, s)
+	l.SetQuick(true)
+	l.DoneCallbackFn = func(i int) { fn(i == 1) }
+	return l
+}
+
 func (s *settingsTab) loadConfig() {
 	s.inputs[historySaveMaxIdx].SetValue(fmt.Sprintf("%d", *s.cfg.HistorySaveMax))
+	s.inputs[cacheLimitIdx].SetValue(strconv.Itoa(s.cfg.CacheLimitMB))
+	s.inputs[recordingsLimitIdx].SetValue(strconv.Itoa(s.cfg.RecordingsLimitMB))
+	s.inputs[idleStopIdx].SetValue(strconv.Itoa(s.cfg.IdleStopHours))
+	s.inputs[connectTimeoutIdx].SetValue(strconv.Itoa(s.cfg.ConnectTimeoutSec))
+	s.inputs[bandwidthCapIdx].SetValue(strconv.Itoa(s.cfg.BandwidthCapMB))
+	now := time.Now()
+	s.inputs[bandwidthCapIdx].SetDescription(descriptions[13] + fmt.Sprintf(bandwidthDesc,
+		diskquota.FormatSize(s.cfg.DayBandwidth(now)), diskquota.FormatSize(s.cfg.MonthBandwidth(now))))
+	s.inputs[learnLanguageIdx].SetValue(s.cfg.LearnLanguage)
+	s.inputs[learnLanguageIdx].SetDescription(descriptions[23] + fmt.Sprintf(listeningDesc, listeningView(s.cfg.ListeningTimes())))
+	s.loadBackups()
+}
+
+// loadBackups lists the favorites backups, the current favorites first.
+func (s *settingsTab) loadBackups() {
+	backups, err := config.FavoritesBackups()
+	if err != nil {
+		slog.With("method", "ui.settingsTab.loadBackups").Error("list favorites backups", "error", err)
+	}
+	s.backups = backups
+	opts := []components.OptionValue{{IdxView: 1, NameView: "Current"}}
+	for i, b := range backups {
+		name := fmt.Sprintf("%s · %d favorites", b.Time.Format(favoritesBackupFmt), b.Count)
+		opts = append(opts, components.OptionValue{IdxView: i + 2, NameView: name})
+	}
+	s.backupList.SetOptions(opts)
+	// with 10 options or more, the digits of the 2 digit positions are awaited
+	s.backupList.SetQuick(len(opts) < 10)
+}
+
+// restoreBackup restores the favorites backup of the option, the option going back to the current
+// favorites, for closing the list again not to restore it twice.
+func (s *settingsTab) restoreBackup(i int) {
+	if i == 0 || i > len(s.backups) {
+		return
+	}
+	s.callbackCmd = s.restoreFn(s.backups[i-1])
+	s.backupList.SetIdx(0)
 }
 
 func (s *settingsTab) Init(m *Model) tea.Cmd {
-	s.setSize(m.width, m.totHeight-m.headerHeight)
+	s.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
 
 	showAll := false
 	s.help.ShowAll = showAll
@@ -146,7 +642,7 @@ func (s *settingsTab) onEnter() tea.Cmd {
 
 	s.loadConfig()
 
-	return s.inputs[historySaveMaxIdx].Focus()
+	return tea.Batch(s.inputs[historySaveMaxIdx].Focus(), diskUsageCmd(s.cfg))
 }
 
 func (s *settingsTab) onExit() {
@@ -165,6 +661,35 @@ func (s *settingsTab) updateConfig() {
 	} else {
 		s.cfg.HistorySaveMax = &intVal
 	}
+
+	if idleStop, err := strconv.Atoi(s.inputs[idleStopIdx].Value()); err != nil {
+		log.Info("invalid idle stop input value", "error", err)
+	} else {
+		s.cfg.IdleStopHours = max(idleStop, 0)
+	}
+	if connectTimeout, err := strconv.Atoi(s.inputs[connectTimeoutIdx].Value()); err != nil {
+		log.Info("invalid connect timeout input value", "error", err)
+	} else {
+		s.cfg.ConnectTimeoutSec = max(connectTimeout, 0)
+	}
+	if bandwidthCap, err := strconv.Atoi(s.inputs[bandwidthCapIdx].Value()); err != nil {
+		log.Info("invalid data cap input value", "error", err)
+	} else {
+		s.cfg.BandwidthCapMB = max(bandwidthCap, 0)
+	}
+	s.cfg.LearnLanguage = strings.TrimSpace(s.inputs[learnLanguageIdx].Value())
+
+	cacheLimit, cacheErr := strconv.Atoi(s.inputs[cacheLimitIdx].Value())
+	recordingsLimit, recordingsErr := strconv.Atoi(s.inputs[recordingsLimitIdx].Value())
+	if cacheErr != nil || recordingsErr != nil {
+		log.Info("invalid disk limit input value", "cache", cacheErr, "recordings", recordingsErr)
+		return
+	}
+	if cacheLimit != s.cfg.CacheLimitMB || recordingsLimit != s.cfg.RecordingsLimitMB {
+		s.cfg.CacheLimitMB = max(cacheLimit, 0)
+		s.cfg.RecordingsLimitMB = max(recordingsLimit, 0)
+		s.enforceQuotasFn()
+	}
 }
 
 func (s *settingsTab) setSize(width, height int) {
@@ -184,6 +709,10 @@ func (s *settingsTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		availableHeight := msg.Height - m.headerHeight
 		s.setSize(msg.Width, availableHeight)
 
+	case diskUsageMsg:
+		s.inputs[cacheLimitIdx].SetDescription(descriptions[6] + fmt.Sprintf(diskUsageDesc, diskquota.FormatSize(msg.cache)))
+		s.inputs[recordingsLimitIdx].SetDescription(descriptions[7] + fmt.Sprintf(diskUsageDesc, diskquota.FormatSize(msg.recordings)))
+
 	case components.OptionMsg:
 		var idx int
 		if msg.Done {
@@ -198,11 +727,16 @@ func (s *settingsTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.CallbackFn != nil {
 			msg.CallbackFn(idx)
 		}
+		cmds = append(cmds, s.callbackCmd)
+		s.callbackCmd = nil
 		return m, tea.Batch(cmds...)
 
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, s.keymap.quit):
+			m.detach = true
+			return m, tea.Quit
+		case key.Matches(msg, s.keymap.quitAll):
 			return m, tea.Quit
 
 		case key.Matches(msg, s.keymap.showFullHelp):
@@ -223,9 +757,12 @@ func (s *settingsTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, s.keymap.browseTab):
 			s.onExit()
 			m.toBrowseTab()
-		case key.Matches(msg, s.keymap.prevTab, s.keymap.historyTab):
+		case key.Matches(msg, s.keymap.historyTab):
 			s.onExit()
 			m.toHistoryTab()
+		case key.Matches(msg, s.keymap.prevTab, s.keymap.recordingsTab):
+			s.onExit()
+			m.toRecordingsTab()
 
 		case key.Matches(msg, s.keymap.nextInput):
 			s.idx++
@@ -244,7 +781,7 @@ func (s *settingsTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 			s.inputs[s.idx].SetActive()
 			return m, tea.Batch(cmds...)
 		case key.Matches(msg, s.keymap.reset):
-			s.resetSettings()
+			cmds = append(cmds, s.resetSettings())
 			return m, tea.Batch(cmds...)
 		}
 	}
@@ -256,7 +793,8 @@ func (s *settingsTab) Update(m *Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
-func (s *settingsTab) resetSettings() {
+// resetSettings sets the defaults, returning the command restoring the volume lowered by the auto duck.
+func (s *settingsTab) resetSettings() tea.Cmd {
 	defHistorySaveMax := config.DefHistorySaveMax
 	s.cfg.HistorySaveMax = &defHistorySaveMax
 	val := strconv.Itoa(defHistorySaveMax)
@@ -264,6 +802,68 @@ func (s *settingsTab) resetSettings() {
 
 	s.changeThemeFn(0)
 	s.inputs[themesIdx].SetValue(0)
+
+	s.cfg.MusicBrainz = false
+	s.inputs[musicBrainzIdx].SetValue(0)
+	s.cfg.Artwork = false
+	s.inputs[artworkIdx].SetValue(0)
+	s.cfg.SplitRecordings = false
+	s.inputs[splitRecordingsIdx].SetValue(0)
+	s.cfg.CacheLimitMB = 0
+	s.inputs[cacheLimitIdx].SetValue("0")
+	s.cfg.RecordingsLimitMB = 0
+	s.inputs[recordingsLimitIdx].SetValue("0")
+	s.cfg.RememberOutputVolume = false
+	s.inputs[outputVolumeIdx].SetValue(0)
+	s.inputs[maxVolumeIdx].SetValue(0)
+	s.cfg.StartupVolume = 0
+	s.inputs[startupVolumeIdx].SetValue(0)
+	s.cfg.StartupFadeMs = 0
+	s.inputs[startupFadeIdx].SetValue(0)
+	s.cfg.IdleStopHours = 0
+	s.inputs[idleStopIdx].SetValue("0")
+	s.cfg.BandwidthCapMB = 0
+	s.inputs[bandwidthCapIdx].SetValue("0")
+	s.cfg.BandwidthStop = false
+	s.inputs[bandwidthStopIdx].SetValue(0)
+	s.cfg.LowBandwidth = false
+	s.inputs[lowBandwidthIdx].SetValue(0)
+	s.relayFn(false)
+	s.inputs[relayIdx].SetValue(0)
+	s.cfg.ClockFormat = ""
+	s.inputs[clockIdx].SetValue(0)
+	s.cfg.StreamUptime = false
+	s.inputs[uptimeIdx].SetValue(0)
+	s.cfg.BufferHealth = false
+	s.inputs[bufferHealthIdx].SetValue(0)
+	s.cfg.ConnectTimeoutSec = 0
+	s.inputs[connectTimeoutIdx].SetValue("0")
+	s.cfg.Preconnect = false
+	s.inputs[preconnectIdx].SetValue(0)
+	s.dialFn(config.DialDigits)
+	s.inputs[dialKeysIdx].SetValue(0)
+	s.cfg.LearnLanguage = ""
+	s.inputs[learnLanguageIdx].SetValue("")
+	s.cfg.PreferTalk = false
+	s.inputs[preferTalkIdx].SetValue(0)
+	s.cfg.ScreenReader = false
+	s.inputs[screenReaderIdx].SetValue(0)
+	s.cfg.SymbolSignals = false
+	s.inputs[symbolSignalsIdx].SetValue(0)
+	s.cfg.ReducedMotion = false
+	s.inputs[reducedMotionIdx].SetValue(0)
+	s.cfg.RenderProfile = config.RenderAuto
+	s.inputs[renderProfileIdx].SetValue(0)
+	s.cfg.TitleFormat = ""
+	s.inputs[titleIdx].SetValue(0)
+	s.cfg.IconSet = config.IconsAuto
+	s.inputs[iconsIdx].SetValue(0)
+	s.metadataPollFn(0)
+	s.inputs[metadataPollIdx].SetValue(0)
+	s.inputs[autoDuckIdx].SetValue(0)
+	s.crossfadeFn(0)
+	s.inputs[crossfadeIdx].SetValue(0)
+	return tea.Batch(s.maxVolumeFn(0), s.autoDuckFn(false))
 }
 
 func (s *settingsTab) changeInput(cmds []tea.Cmd) []tea.Cmd {
@@ -322,9 +922,11 @@ type settingsKeymap struct {
 	favoritesTab  key.Binding
 	browseTab     key.Binding
 	historyTab    key.Binding
+	recordingsTab key.Binding
 	showFullHelp  key.Binding
 	closeFullHelp key.Binding
 	quit          key.Binding
+	quitAll       key.Binding
 }
 
 func newSettingsKeymap() settingsKeymap {
@@ -357,6 +959,10 @@ func newSettingsKeymap() settingsKeymap {
 			key.WithKeys("H"),
 			key.WithHelp("H", "go to history tab"),
 		),
+		recordingsTab: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "go to recordings tab"),
+		),
 		favoritesTab: key.NewBinding(
 			key.WithKeys("F"),
 			key.WithHelp("F", "go to favorites tab"),
@@ -375,7 +981,11 @@ func newSettingsKeymap() settingsKeymap {
 		),
 		quit: key.NewBinding(
 			key.WithKeys("q"),
-			key.WithHelp("q", "quit"),
+			key.WithHelp("q", "detach"),
+		),
+		quitAll: key.NewBinding(
+			key.WithKeys("Q"),
+			key.WithHelp("Q", "quit"),
 		),
 	}
 }
@@ -390,6 +1000,7 @@ func (k *settingsKeymap) setEnable(v bool, showAll bool) {
 	k.favoritesTab.SetEnabled(v)
 	k.browseTab.SetEnabled(v)
 	k.historyTab.SetEnabled(v)
+	k.recordingsTab.SetEnabled(v)
 	if v {
 		k.showFullHelp.SetEnabled(!showAll)
 		k.closeFullHelp.SetEnabled(showAll)
@@ -398,6 +1009,7 @@ func (k *settingsKeymap) setEnable(v bool, showAll bool) {
 		k.closeFullHelp.SetEnabled(false)
 	}
 	k.quit.SetEnabled(v)
+	k.quitAll.SetEnabled(v)
 }
 
 func (k *settingsKeymap) ShortHelp() []key.Binding {
@@ -407,7 +1019,7 @@ func (k *settingsKeymap) ShortHelp() []key.Binding {
 func (k *settingsKeymap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.prevInput, k.nextInput, k.enterInput, k.reset},
-		{k.prevTab, k.nextTab, k.favoritesTab, k.browseTab, k.historyTab},
-		{k.quit, k.closeFullHelp},
+		{k.prevTab, k.nextTab, k.favoritesTab, k.browseTab, k.historyTab, k.recordingsTab},
+		{k.quit, k.quitAll, k.closeFullHelp},
 	}
 }
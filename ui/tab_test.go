@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/dancnb/sonicradio/browser"
+)
+
+func TestRankStations(t *testing.T) {
+	stations := []browser.Station{
+		{Name: "Jazz FM", Tags: "jazz", Countrycode: "DE"},
+		{Name: "Smooth Jazz", Tags: "jazz,smooth", Countrycode: "US"},
+		{Name: "Jazz Radio", Tags: "jazz", Countrycode: "DE"},
+		{Name: "Pop Hits", Tags: "pop", Countrycode: "DE"},
+	}
+	texts := make([]string, len(stations))
+	for i := range stations {
+		texts[i] = stations[i].Name
+	}
+	indexes := func(term string) []int {
+		var res []int
+		for _, r := range rankStations(term, stations, texts) {
+			res = append(res, r.Index)
+		}
+		return res
+	}
+
+	if got := indexes("tag:jazz country:de"); len(got) != 2 || got[0] != 0 || got[1] != 2 {
+		t.Errorf("fields %v", got)
+	}
+	if got := indexes("country:de radio"); len(got) != 1 || got[0] != 2 {
+		t.Errorf("fields and text %v", got)
+	}
+	if got := indexes("/^jazz/"); len(got) != 2 {
+		t.Errorf("regex %v", got)
+	}
+	if got := indexes("smooth"); len(got) != 1 || got[0] != 1 {
+		t.Errorf("text %v", got)
+	}
+	if got := indexes("tag:/[/"); len(got) != 0 {
+		t.Errorf("invalid regex %v", got)
+	}
+}
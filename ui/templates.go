@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"log/slog"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// textTemplate is a template of the config file, as in the text/template package, parsed again when it changes.
+type textTemplate struct {
+	format string
+	tmpl   *template.Template
+}
+
+// execute returns the text of the template, or false if it's empty or invalid, for the default text to be
+// used instead.
+func (t *textTemplate) execute(format string, data any) (string, bool) {
+	if format == "" {
+		return "", false
+	}
+	if format != t.format {
+		t.format = format
+		tmpl, err := template.New("").Parse(format)
+		if err != nil {
+			slog.With("method", "ui.textTemplate.execute").Error("invalid template", "format", format, "error", err)
+		}
+		t.tmpl = tmpl
+	}
+	if t.tmpl == nil {
+		return "", false
+	}
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		slog.With("method", "ui.textTemplate.execute").Debug("template", "format", format, "error", err)
+		return "", false
+	}
+	return b.String(), true
+}
+
+// line returns the text of the template on one line, as execute.
+func (t *textTemplate) line(format string, data any) (string, bool) {
+	res, ok := t.execute(format, data)
+	// the line breaks would break the rows, and end the escape sequence of the terminal title
+	return strings.Join(strings.Fields(res), " "), ok
+}
+
+// displayTemplates are the templates of the model, kept by pointer for the views to reuse them parsed.
+type displayTemplates struct {
+	nowPlaying   textTemplate
+	notification textTemplate
+	osd          textTemplate
+}
+
+// nowPlayingData are the values of the now playing templates.
+type nowPlayingData struct {
+	State    string // ▶ when playing, ⏸ when paused, empty when stopped
+	Station  string
+	Program  string // the program on air, if the station has a guide
+	Homepage string
+	Song     string // the stream title, as sent by the station
+	Artist   string
+	Title    string
+	Album    string // the album and the year found on MusicBrainz
+	Year     string
+}
+
+// nowPlayingData returns false if the playing station is being changed.
+func (m *Model) nowPlayingData() (nowPlayingData, bool) {
+	if !m.delegate.playingMtx.TryRLock() {
+		return nowPlayingData{}, false
+	}
+	defer m.delegate.playingMtx.RUnlock()
+	return m.playingData(), true
+}
+
+// playingData returns the values of the playing or paused station and of its song, the playing mutex
+// being held.
+func (m *Model) playingData() nowPlayingData {
+	var d nowPlayingData
+	s := m.delegate.currPlaying
+	if s != nil {
+		d.State = titlePlaying
+		d.Program = m.currentProgram(s.Stationuuid)
+	} else if s = m.delegate.prevPlaying; s != nil {
+		d.State = titlePaused
+	} else {
+		return d
+	}
+	d.Station = m.stationName(*s)
+	d.Homepage = s.Homepage
+	if m.songTitle != "" {
+		d.Song = m.songTitle
+		d.Artist = m.song.Artist
+		d.Title = m.song.Title
+		if m.recording.Found() {
+			d.Album = m.recording.Album
+			d.Year = m.recording.Year
+		}
+	}
+	return d
+}
+
+// nowPlayingLine returns the line of the template under the playing or paused station, the playing mutex
+// being held, or false for the default line.
+func (m *Model) nowPlayingLine() (string, bool) {
+	d := m.playingData()
+	if d.State == "" {
+		return "", false
+	}
+	return m.templates.nowPlaying.line(m.cfg.NowPlayingFormat, d)
+}
+
+// notificationData are the values of the notification template.
+type notificationData struct {
+	Program string
+	Station string
+	Start   time.Time
+	End     time.Time
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_textTemplate(t *testing.T) {
+	var tmpl textTemplate
+	if _, ok := tmpl.execute("", titleExample); ok {
+		t.Error("expected no text without a template")
+	}
+	if got, ok := tmpl.execute("{{.Station}}\n{{.Song}}", titleExample); !ok || got != "Station\nArtist - Song" {
+		t.Errorf("execute=%q %v", got, ok)
+	}
+	if got, ok := tmpl.line("{{.Station}}\n{{.Song}}", titleExample); !ok || got != "Station Artist - Song" {
+		t.Errorf("line=%q %v", got, ok)
+	}
+	if _, ok := tmpl.execute("{{.Station", titleExample); ok {
+		t.Error("expected the invalid template to fail")
+	}
+	if _, ok := tmpl.execute("{{.Unknown}}", titleExample); ok {
+		t.Error("expected the unknown field to fail")
+	}
+}
+
+func Test_e2eTemplates(t *testing.T) {
+	d := newUIDriver(t, e2eStations(3, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 3 })
+	osd := filepath.Join(t.TempDir(), "osd.txt")
+	d.m.cfg.RowFormat = "{{.Countrycode}} · {{.Tags}} · {{.Votes}} votes"
+	d.m.cfg.NowPlayingFormat = "on {{.Station}}{{with .Program}} · {{.}}{{end}}"
+	d.m.cfg.OsdFile = osd
+	d.m.cfg.OsdFormat = "{{.State}} {{.Station}}"
+
+	d.keys("enter")
+	d.waitFor("the station to play", func() bool { return d.m.connecting == nil && d.m.delegate.currPlaying != nil })
+	if data, ok := d.m.nowPlayingData(); !ok || data.State != titlePlaying || data.Station != "Jazz 0" {
+		t.Errorf("got %+v %v, want Jazz 0 playing", data, ok)
+	}
+	if !strings.Contains(d.view, "DE · jazz · 3 votes") {
+		t.Errorf("expected the rows of the template:\n%s", d.view)
+	}
+	if !strings.Contains(d.view, "on Jazz 0") {
+		t.Errorf("expected the now playing line of the template:\n%s", d.view)
+	}
+	d.waitFor("the osd file of the playing station", func() bool { return readOsd(osd) == "▶ Jazz 0" })
+
+	d.keys(" ")
+	d.waitFor("the pause", func() bool { data, _ := d.m.nowPlayingData(); return data.State == titlePaused })
+	if data, _ := d.m.nowPlayingData(); data.Station != "Jazz 0" {
+		t.Errorf("got %+v, want Jazz 0 paused", data)
+	}
+	d.waitFor("the osd file of the paused station", func() bool { return readOsd(osd) == "⏸ Jazz 0" })
+
+	d.m.clearOsd()
+	if b := readOsd(osd); b != "" {
+		t.Errorf("osd file %q, want it cleared", b)
+	}
+}
+
+func readOsd(path string) string {
+	b, _ := os.ReadFile(path)
+	return string(b)
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dancnb/sonicradio/config"
+)
+
+// ticker is the one tick of the UI that the periodic work subscribes to with its interval, instead of each
+// one running its own timer: the spinner frames, the song title and playback time polls, the syncs and the
+// background checks. It ticks every second, or at the shortest interval of the active subscribers.
+type ticker struct {
+	subs []tickSub
+	// seq is the number of the tick in flight, due at due, the ticks of another number being dropped
+	seq int
+	due time.Time
+}
+
+// tickSub is called on the first tick after next, then every interval, rounded up to the ticks.
+// An interval of 0 pauses it; it's called on the first tick once active again.
+type tickSub struct {
+	interval func(m *Model) time.Duration
+	// every is the interval at the last tick
+	every time.Duration
+	next  time.Time
+	fn    func(m *Model, now time.Time) tea.Cmd
+}
+
+// subscribe calls fn an interval from now, then every interval. The commands returned are batched.
+func (t *ticker) subscribe(every time.Duration, fn func(m *Model, now time.Time) tea.Cmd) {
+	interval := func(*Model) time.Duration { return every }
+	t.subs = append(t.subs, tickSub{interval: interval, every: every, next: time.Now().Add(every), fn: fn})
+}
+
+// subscribeWhile calls fn every interval returned by the interval func at each tick, while it's not 0.
+// The model must call retick when the interval becomes shorter than the tick.
+func (t *ticker) subscribeWhile(interval func(m *Model) time.Duration, fn func(m *Model, now time.Time) tea.Cmd) {
+	t.subs = append(t.subs, tickSub{interval: interval, fn: fn})
+}
+
+// period returns the interval of the next tick, the shortest of the active subscribers.
+func (t *ticker) period() time.Duration {
+	period := config.TickInterval
+	for _, s := range t.subs {
+		if s.every > 0 {
+			period = min(period, s.every)
+		}
+	}
+	return period
+}
+
+func tickCmd(seq int, period time.Duration) tea.Cmd {
+	if period == config.TickInterval {
+		// on the second, for the clock
+		return tea.Every(period, func(t time.Time) tea.Msg { return tickMsg{seq: seq, t: t} })
+	}
+	return tea.Tick(period, func(t time.Time) tea.Msg { return tickMsg{seq: seq, t: t} })
+}
+
+// retick schedules the tick, replacing the one in flight when a subscriber is due sooner than it, after a
+// change of the model shortened its interval.
+func (m *Model) retick() tea.Cmd {
+	t := &m.ticker
+	for i := range t.subs {
+		s := &t.subs[i]
+		if s.every = s.interval(m); s.every <= 0 {
+			s.next = time.Time{}
+		}
+	}
+	period := t.period()
+	due := time.Now().Add(period)
+	if !t.due.IsZero() && !due.Before(t.due) {
+		return nil
+	}
+	t.seq++
+	t.due = due
+	return tickCmd(t.seq, period)
+}
+
+// onTick calls the subscribers due and waits for the next tick. A subscriber late by more than its
+// interval, after a suspend of the system, is called once.
+func (m *Model) onTick(msg tickMsg) tea.Cmd {
+	if msg.seq != m.ticker.seq {
+		return nil
+	}
+	now := msg.t
+	var cmds []tea.Cmd
+	for i := range m.ticker.subs {
+		s := &m.ticker.subs[i]
+		if s.every = s.interval(m); s.every <= 0 {
+			s.next = time.Time{}
+			continue
+		}
+		if s.next.IsZero() {
+			s.next = now
+		}
+		if now.Before(s.next) {
+			continue
+		}
+		if s.next = s.next.Add(s.every); !now.Before(s.next) {
+			s.next = now.Add(s.every)
+		}
+		cmds = append(cmds, s.fn(m, now))
+	}
+	// the subscribers may have changed the intervals
+	m.ticker.due = time.Time{}
+	return tea.Batch(append(cmds, m.retick())...)
+}
+
+// subscribeTicks subscribes the periodic work of the model to the tick.
+func (m *Model) subscribeTicks() {
+	m.ticker.subscribeWhile((*Model).spinInterval, (*Model).spin)
+	m.ticker.subscribeWhile((*Model).pollInterval, (*Model).pollTick)
+	m.ticker.subscribeWhile((*Model).syncInterval, (*Model).syncCmd)
+	m.ticker.subscribeWhile((*Model).gitSyncInterval, (*Model).gitSyncCmd)
+	m.ticker.subscribeWhile((*Model).webdavSyncInterval, (*Model).webdavSyncCmd)
+	m.ticker.subscribe(config.TickInterval, (*Model).updateUptime)
+	m.ticker.subscribe(config.TickInterval, (*Model).checkQuietHours)
+	m.ticker.subscribe(config.IdleCheckInterval, (*Model).checkIdle)
+	m.ticker.subscribe(config.ResumeCheckInterval, (*Model).checkResume)
+	m.ticker.subscribe(config.NetworkCheckInterval, (*Model).readNetworkCmd)
+	m.ticker.subscribe(config.BandwidthTickInterval, (*Model).trackBandwidth)
+	m.ticker.subscribe(config.FavoritesBackupInterval, (*Model).backupFavorites)
+	m.ticker.subscribe(config.GuideCheckInterval, (*Model).checkGuides)
+	m.ticker.subscribe(config.ReminderCheckInterval, (*Model).checkReminders)
+}
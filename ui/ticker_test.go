@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func Test_ticker(t *testing.T) {
+	m := &Model{}
+	var every1, every3 []time.Time
+	m.ticker.subscribe(time.Second, func(_ *Model, now time.Time) tea.Cmd {
+		every1 = append(every1, now)
+		return nil
+	})
+	m.ticker.subscribe(3*time.Second, func(_ *Model, now time.Time) tea.Cmd {
+		every3 = append(every3, now)
+		return nil
+	})
+
+	tick := func(now time.Time) tea.Cmd { return m.onTick(tickMsg{seq: m.ticker.seq, t: now}) }
+	start := time.Now().Add(time.Second)
+	for i := range 6 {
+		tick(start.Add(time.Duration(i) * time.Second))
+	}
+	if len(every1) != 6 {
+		t.Errorf("got %d calls of the 1s subscriber, want every tick", len(every1))
+	}
+	if len(every3) != 2 {
+		t.Errorf("got %d calls of the 3s subscriber in 6s, want 2", len(every3))
+	}
+
+	// a suspend of the system: the late subscribers are called once
+	every1, every3 = nil, nil
+	resumed := start.Add(time.Hour)
+	tick(resumed)
+	tick(resumed.Add(time.Second))
+	if len(every1) != 2 || len(every3) != 1 {
+		t.Errorf("got %d and %d calls after the suspend, want 2 and 1", len(every1), len(every3))
+	}
+	tick(resumed.Add(3 * time.Second))
+	if len(every3) != 2 {
+		t.Errorf("got %d calls of the 3s subscriber, want its interval counted from the resume", len(every3))
+	}
+}
+
+func Test_tickerWhile(t *testing.T) {
+	m := &Model{}
+	var fast time.Duration
+	var calls []time.Time
+	m.ticker.subscribeWhile(func(*Model) time.Duration { return fast }, func(_ *Model, now time.Time) tea.Cmd {
+		calls = append(calls, now)
+		return nil
+	})
+	if m.retick() == nil {
+		t.Fatal("no first tick")
+	}
+	if m.retick() != nil {
+		t.Error("ticked again without a shorter interval")
+	}
+
+	stale := m.ticker.seq
+	fast = 100 * time.Millisecond
+	if m.retick() == nil {
+		t.Fatal("the tick was not replaced by a shorter one")
+	}
+	if m.onTick(tickMsg{seq: stale, t: time.Now()}) != nil || len(calls) != 0 {
+		t.Error("the replaced tick was not dropped")
+	}
+
+	start := time.Now()
+	for i := range 5 {
+		m.onTick(tickMsg{seq: m.ticker.seq, t: start.Add(time.Duration(i) * fast)})
+	}
+	if len(calls) != 5 {
+		t.Errorf("got %d calls, want one on each tick once active", len(calls))
+	}
+
+	// paused, then called on the first tick once active again
+	fast = 0
+	m.onTick(tickMsg{seq: m.ticker.seq, t: start.Add(time.Second)})
+	fast = 100 * time.Millisecond
+	m.onTick(tickMsg{seq: m.ticker.seq, t: start.Add(time.Hour)})
+	if len(calls) != 6 || !calls[5].Equal(start.Add(time.Hour)) {
+		t.Errorf("got %d calls, want a call on the tick after the pause", len(calls))
+	}
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/muesli/termenv"
+)
+
+const (
+	titlePlaying = "▶"
+	titlePaused  = "⏸"
+	titleIdle    = "sonicradio"
+)
+
+// titleFormats are the templates of the terminal title offered in the settings,
+// any other template can be set in the config file.
+var titleFormats = []string{
+	`{{.State}} {{.Station}}{{with .Song}} – {{.}}{{end}}`,
+	`{{.State}} {{.Station}}`,
+	`{{with .Song}}{{.}}{{else}}{{.Station}}{{end}}`,
+}
+
+// titleExample is shown in the settings for each template.
+var titleExample = nowPlayingData{State: titlePlaying, Station: "Station", Song: "Artist - Song", Artist: "Artist", Title: "Song"}
+
+// windowTitle is the terminal title set from the config template, the window and the tab title,
+// and the pane title in tmux.
+type windowTitle struct {
+	tmpl textTemplate
+	// last is the title set, empty if none
+	last string
+}
+
+// render returns the title of the template, or false if the template is invalid.
+func (t *windowTitle) render(format string, data nowPlayingData) (string, bool) {
+	res, ok := t.tmpl.line(format, data)
+	if !ok {
+		return "", false
+	}
+	if res == "" {
+		res = titleIdle
+	}
+	return res, true
+}
+
+// updateTitle sets the terminal title of the playing station and song, when it changed.
+func (m *Model) updateTitle() {
+	if m.Progr == nil || m.cfg.TitleFormat == "" && m.title.last == "" {
+		return
+	}
+	title := ""
+	if m.cfg.TitleFormat != "" {
+		data, ok := m.nowPlayingData()
+		if !ok {
+			return
+		}
+		if title, ok = m.title.render(m.cfg.TitleFormat, data); !ok {
+			return
+		}
+	}
+	if title == m.title.last {
+		return
+	}
+	m.title.last = title
+	m.Progr.SetWindowTitle(title)
+}
+
+// clearTitle empties the terminal title set, once the program stopped.
+func (m *Model) clearTitle() {
+	if m.title.last == "" {
+		return
+	}
+	m.title.last = ""
+	termenv.NewOutput(os.Stdout).SetWindowTitle("")
+}
@@ -0,0 +1,32 @@
+package ui
+
+import "testing"
+
+func Test_windowTitle_render(t *testing.T) {
+	tests := []struct {
+		format string
+		data   nowPlayingData
+		want   string
+	}{
+		{titleFormats[0], titleExample, "▶ Station – Artist - Song"},
+		{titleFormats[0], nowPlayingData{State: titlePaused, Station: "Station"}, "⏸ Station"},
+		{titleFormats[0], nowPlayingData{}, titleIdle},
+		{titleFormats[2], nowPlayingData{State: titlePlaying, Station: "Station"}, "Station"},
+		{`{{.Title}} by {{.Artist}}`, titleExample, "Song by Artist"},
+		{titleFormats[0], nowPlayingData{State: titlePlaying, Station: "Station", Song: "two\nlines"}, "▶ Station – two lines"},
+	}
+	for _, tt := range tests {
+		var w windowTitle
+		if got, ok := w.render(tt.format, tt.data); !ok || got != tt.want {
+			t.Errorf("render(%q)=%q %v, want %q", tt.format, got, ok, tt.want)
+		}
+	}
+
+	var w windowTitle
+	if _, ok := w.render(`{{.Station`, titleExample); ok {
+		t.Error("expected the invalid template to fail")
+	}
+	if got, ok := w.render(titleFormats[1], titleExample); !ok || got != "▶ Station" {
+		t.Errorf("got %q %v after the template changed", got, ok)
+	}
+}
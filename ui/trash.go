@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dancnb/sonicradio/config"
+	"github.com/dancnb/sonicradio/ui/styles"
+)
+
+const (
+	trashTitle      = "Removed favorites"
+	trashEmptyMsg   = "No favorites removed in the last %d days"
+	trashRemovedFmt = "Jan 2 15:04"
+	trashRestored   = "Restored %s to the favorites"
+)
+
+// trashView lists the favorites removed recently, the latest first, to restore them.
+type trashView struct {
+	enabled bool
+	style   *styles.Style
+
+	idx int
+
+	keymap trashKeymap
+	help   help.Model
+	width  int
+	height int
+}
+
+func newTrashView(s *styles.Style) *trashView {
+	h := help.New()
+	h.ShortSeparator = "   "
+	h.Styles = s.HelpStyles()
+	return &trashView{
+		style:  s,
+		keymap: newTrashKeymap(),
+		help:   h,
+	}
+}
+
+func (v *trashView) setSize(width, height int) {
+	h, vf := v.style.DocStyle.GetFrameSize()
+	v.width = width - h
+	v.height = height - vf
+	v.help.Width = v.width
+}
+
+func (v *trashView) View(trash []config.TrashedFavorite) string {
+	var b strings.Builder
+	b.WriteString("\n" + v.style.PrimaryColorStyle.Render(trashTitle) + "\n\n")
+	if len(trash) == 0 {
+		b.WriteString(v.style.ItalicStyle.Render(fmt.Sprintf(trashEmptyMsg, trashDays(config.TrashRetention))) + "\n")
+	}
+
+	help := v.style.HelpStyle.Render(v.help.View(&v.keymap))
+	now := time.Now()
+	rows := max(1, v.height-lipgloss.Height(b.String())-lipgloss.Height(help))
+	first := max(0, v.idx-rows+1)
+	for i := first; i < len(trash) && i < first+rows; i++ {
+		t := trash[i]
+		itStyle := v.style.SecondaryColorStyle
+		if i == v.idx {
+			itStyle = v.style.HistorySelItemStyle
+		}
+		left := trashDays(t.Expires().Sub(now))
+		desc := fmt.Sprintf("removed %s · %d days left", t.Removed.Local().Format(trashRemovedFmt), left)
+		fill := max(0, v.width-lipgloss.Width(t.Name)-lipgloss.Width(desc)-styles.HeaderPadDist)
+		b.WriteString(itStyle.Render(t.Name+strings.Repeat(" ", fill+styles.HeaderPadDist)+desc) + "\n")
+	}
+
+	for i := lipgloss.Height(b.String()); i < v.height-lipgloss.Height(help); i++ {
+		b.WriteString("\n")
+	}
+	return b.String() + help
+}
+
+// trashDays returns the days of the duration, a day started counting as one.
+func trashDays(d time.Duration) int {
+	day := 24 * time.Hour
+	return int((d + day - 1) / day)
+}
+
+// toggleTrash shows or hides the removed favorites, purging the ones past the retention.
+func (m *Model) toggleTrash() tea.Cmd {
+	v := m.trash
+	if v.enabled {
+		v.enabled = false
+		return nil
+	}
+	m.cfg.PurgeTrash(time.Now())
+	v.enabled = true
+	v.idx = 0
+	v.setSize(m.tabWidth(), m.totHeight-m.headerHeight)
+	return nil
+}
+
+func (m *Model) updateTrash(msg tea.Msg) tea.Cmd {
+	v := m.trash
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	trash := m.cfg.Trash
+	switch {
+	case key.Matches(keyMsg, v.keymap.up) && len(trash) > 0:
+		v.idx = (v.idx + len(trash) - 1) % len(trash)
+	case key.Matches(keyMsg, v.keymap.down) && len(trash) > 0:
+		v.idx = (v.idx + 1) % len(trash)
+	case key.Matches(keyMsg, v.keymap.restore) && len(trash) > 0:
+		t := trash[v.idx]
+		m.cfg.RestoreFavorite(t.Uuid)
+		v.idx = min(v.idx, max(0, len(m.cfg.Trash)-1))
+		m.updateStatus(fmt.Sprintf(trashRestored, t.Name))
+		return m.favoritesReqCmd
+	case key.Matches(keyMsg, v.keymap.cancel, m.delegate.keymap.trash):
+		v.enabled = false
+	}
+	return nil
+}
+
+type trashKeymap struct {
+	up      key.Binding
+	down    key.Binding
+	restore key.Binding
+	cancel  key.Binding
+}
+
+func newTrashKeymap() trashKeymap {
+	return trashKeymap{
+		up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		restore: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "restore"),
+		),
+		cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back"),
+		),
+	}
+}
+
+func (k *trashKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.up, k.down, k.restore, k.cancel}
+}
+
+func (k *trashKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
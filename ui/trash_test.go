@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_trashDays(t *testing.T) {
+	for d, want := range map[time.Duration]int{0: 0, time.Minute: 1, 24 * time.Hour: 1, 25 * time.Hour: 2} {
+		if got := trashDays(d); got != want {
+			t.Errorf("trashDays(%v)=%d, want %d", d, got, want)
+		}
+	}
+}
+
+func Test_e2eTrash(t *testing.T) {
+	d := newUIDriver(t, e2eStations(3, "Jazz", "jazz")...)
+	d.waitFor("the top stations", func() bool { return d.listed() == 3 })
+
+	d.keys("f", "j", "f")
+	d.keys("k", "f", "j", "f")
+	if len(d.m.cfg.Favorites) != 0 {
+		t.Fatalf("favorites %v, want all removed", d.m.cfg.Favorites)
+	}
+
+	d.keys("u")
+	if !d.m.trash.enabled || !strings.Contains(d.view, "Jazz 0") || !strings.Contains(d.view, "30 days left") {
+		t.Fatalf("expected the removed favorites:\n%s", d.view)
+	}
+	if strings.Index(d.view, "Jazz 1") > strings.Index(d.view, "Jazz 0") {
+		t.Errorf("expected the latest removed first:\n%s", d.view)
+	}
+
+	d.keys("j", "enter")
+	if !slices.Equal(d.m.cfg.Favorites, []string{"jazz-0"}) || len(d.m.cfg.Trash) != 1 {
+		t.Errorf("favorites %v, trash %+v, want the selected favorite restored", d.m.cfg.Favorites, d.m.cfg.Trash)
+	}
+	d.waitFor("the favorites reloaded", func() bool {
+		return len(d.m.tabs[favoriteTabIx].(stationTab).Stations().list.Items()) == 1
+	})
+
+	d.keys("esc")
+	if d.m.trash.enabled {
+		t.Error("expected the view closed")
+	}
+}
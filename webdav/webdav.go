@@ -0,0 +1,139 @@
+// Package webdav reads and writes a file on a WebDAV server, like Nextcloud or ownCloud.
+// The writes are conditional on the ETag of the last read, so that a file changed in between by
+// another machine is never overwritten.
+package webdav
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+)
+
+// ErrConflict is returned by Put when the file changed since it was read.
+var ErrConflict = errors.New("file changed on the server")
+
+const reqTimeout = 30 * time.Second
+
+// File is the content of the file on the server, with its version.
+type File struct {
+	Data []byte
+	ETag string
+}
+
+type Client struct {
+	url      string
+	user     string
+	password string
+	client   *http.Client
+}
+
+// New returns a client of the file at rawUrl, e.g. https://cloud.example.com/remote.php/dav/files/me/sonicradio.json.
+func New(rawUrl, user, password string) *Client {
+	return &Client{
+		url:      rawUrl,
+		user:     user,
+		password: password,
+		client:   &http.Client{Timeout: reqTimeout},
+	}
+}
+
+// Get reads the file, nil if it doesn't exist yet.
+func (c *Client) Get(ctx context.Context) (*File, error) {
+	res, err := c.do(ctx, http.MethodGet, c.url, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	} else if res.StatusCode != http.StatusOK {
+		return nil, statusErr(http.MethodGet, res)
+	}
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &File{Data: b, ETag: res.Header.Get("ETag")}, nil
+}
+
+// Put writes the file if it has not changed since prev was read, or if it doesn't exist when prev is
+// nil. It returns ErrConflict otherwise. The folder of the file is created if needed.
+func (c *Client) Put(ctx context.Context, data []byte, prev *File) error {
+	header := make(http.Header)
+	if prev == nil {
+		header.Set("If-None-Match", "*")
+	} else if prev.ETag != "" {
+		header.Set("If-Match", prev.ETag)
+	}
+	res, err := c.put(ctx, data, header)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode == http.StatusConflict {
+		// the parent collection is missing
+		if err := c.mkcol(ctx); err != nil {
+			return err
+		}
+		if res, err = c.put(ctx, data, header); err != nil {
+			return err
+		}
+	}
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+	case http.StatusPreconditionFailed:
+		return ErrConflict
+	default:
+		return statusErr(http.MethodPut, res)
+	}
+}
+
+func (c *Client) put(ctx context.Context, data []byte, header http.Header) (*http.Response, error) {
+	res, err := c.do(ctx, http.MethodPut, c.url, bytes.NewReader(data), header)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	return res, nil
+}
+
+func (c *Client) mkcol(ctx context.Context) error {
+	u, err := url.Parse(c.url)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Dir(u.Path) + "/"
+	res, err := c.do(ctx, "MKCOL", u.String(), nil, nil)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusMethodNotAllowed {
+		return statusErr("MKCOL", res)
+	}
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, u string, body io.Reader, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if c.user != "" || c.password != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+	return c.client.Do(req)
+}
+
+func statusErr(method string, res *http.Response) error {
+	return fmt.Errorf("webdav %s: %s", method, res.Status)
+}
@@ -0,0 +1,98 @@
+package webdav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+)
+
+// davServer is a minimal WebDAV server keeping the files in memory.
+type davServer struct {
+	mtx     sync.Mutex
+	files   map[string][]byte
+	dirs    map[string]bool
+	version int
+}
+
+func (s *davServer) etag(p string) string {
+	return fmt.Sprintf(`"%s-%d"`, p, len(s.files[p])+s.version)
+}
+
+func (s *davServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if user, pass, _ := r.BasicAuth(); user != "me" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	p := r.URL.Path
+	switch r.Method {
+	case http.MethodGet:
+		b, ok := s.files[p]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", s.etag(p))
+		w.Write(b)
+	case http.MethodPut:
+		if !s.dirs[path.Dir(p)+"/"] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		_, exists := s.files[p]
+		if m := r.Header.Get("If-Match"); m != "" && (!exists || m != s.etag(p)) ||
+			r.Header.Get("If-None-Match") == "*" && exists {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		s.files[p] = b
+		s.version++
+		w.WriteHeader(http.StatusCreated)
+	case "MKCOL":
+		s.dirs[p] = true
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func TestClient(t *testing.T) {
+	srv := httptest.NewServer(&davServer{files: make(map[string][]byte), dirs: make(map[string]bool)})
+	defer srv.Close()
+	ctx := context.Background()
+	a := New(srv.URL+"/files/me/sonicradio.json", "me", "secret")
+	b := New(srv.URL+"/files/me/sonicradio.json", "me", "secret")
+
+	f, err := a.Get(ctx)
+	if err != nil || f != nil {
+		t.Fatalf("missing file %v, %v", f, err)
+	}
+	if err := a.Put(ctx, []byte("1"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Put(ctx, []byte("2"), nil); !errors.Is(err, ErrConflict) {
+		t.Errorf("created twice: %v", err)
+	}
+
+	fa, err := a.Get(ctx)
+	if err != nil || string(fa.Data) != "1" {
+		t.Fatalf("read %v, %v", fa, err)
+	}
+	fb, _ := b.Get(ctx)
+	if err := b.Put(ctx, []byte("2"), fb); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Put(ctx, []byte("3"), fa); !errors.Is(err, ErrConflict) {
+		t.Errorf("overwritten a changed file: %v", err)
+	}
+
+	if _, err := New(srv.URL+"/files/me/sonicradio.json", "me", "wrong").Get(ctx); err == nil {
+		t.Error("no error with the wrong password")
+	}
+}